@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/trader"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminMiddleware restricts access to users with the admin role (must be used after authMiddleware).
+// The role is synced with ADMIN_EMAILS at startup and checked on every request, so a revoked role
+// takes effect without new tokens.
+func (s *Server) adminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		isAdmin, err := s.store.User().IsAdmin(c.GetString("user_id"))
+		if err != nil || !isAdmin {
+			respondError(c, http.StatusForbidden, ErrCodeAdminRequired, "Admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// handleAdminOverview System-wide overview across all users (admin only)
+// Query params: top (number of top/bottom performers, default 5, max 50)
+func (s *Server) handleAdminOverview(c *gin.Context) {
+	topN := 5
+	if v := c.Query("top"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			topN = n
+		}
+	}
+	if topN > 50 {
+		topN = 50
+	}
+
+	overview, err := s.store.GetSystemOverview(topN)
+	if err != nil {
+		SafeInternalError(c, "Get admin overview", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// handleAdminPauseAll Emergency brake: all traders stop opening/closing positions (admin only).
+// Traders keep running and monitoring; the pause is persisted and survives restarts.
+func (s *Server) handleAdminPauseAll(c *gin.Context) {
+	s.setTradingPaused(c, true)
+}
+
+// handleAdminResumeAll Lifts the global pause set by pause-all (admin only)
+func (s *Server) handleAdminResumeAll(c *gin.Context) {
+	s.setTradingPaused(c, false)
+}
+
+// setTradingPaused persists and applies the global trading pause
+func (s *Server) setTradingPaused(c *gin.Context, paused bool) {
+	if err := s.store.SetSystemConfig(trader.TradingPausedConfigKey, strconv.FormatBool(paused)); err != nil {
+		SafeInternalError(c, "Save trading pause", err)
+		return
+	}
+	trader.SetTradingPaused(paused)
+
+	traders := len(s.traderManager.GetAllTraders())
+	if paused {
+		logger.Warnf("⏸ Trading globally paused by admin (%d traders loaded)", traders)
+	} else {
+		logger.Infof("▶️ Trading globally resumed by admin (%d traders loaded)", traders)
+	}
+	c.JSON(http.StatusOK, gin.H{"paused": paused, "traders": traders})
+}
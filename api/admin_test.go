@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"nofx/auth"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth.SetJWTSecret("admin-test-secret")
+
+	st, err := store.New(filepath.Join(t.TempDir(), "admin.db"))
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	defer st.Close()
+
+	for _, u := range []*store.User{
+		{ID: "u-admin", Email: "Ops@example.com", OTPVerified: true},
+		{ID: "u-user", Email: "user@example.com", OTPVerified: true},
+		{ID: "admin", Email: "admin@localhost", OTPVerified: true, IsAdmin: true},
+	} {
+		if err := st.User().Create(u); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	if promoted, demoted, err := st.User().SyncAdmins([]string{"ops@example.com"}); err != nil || promoted != 1 || demoted != 0 {
+		t.Fatalf("SyncAdmins() = %d, %d, %v, want 1 promoted", promoted, demoted, err)
+	}
+
+	s := &Server{store: st}
+	r := gin.New()
+	admin := r.Group("/api/admin", s.authMiddleware(), s.adminMiddleware())
+	admin.GET("/overview", s.handleAdminOverview)
+
+	request := func(userID, email string) int {
+		token, err := auth.GenerateJWT(userID, email)
+		if err != nil {
+			t.Fatalf("GenerateJWT() error = %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/overview", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request("u-admin", "Ops@example.com"); code != http.StatusOK {
+		t.Errorf("admin user: status = %d, want 200", code)
+	}
+	if code := request("u-user", "user@example.com"); code != http.StatusForbidden {
+		t.Errorf("regular user: status = %d, want 403", code)
+	}
+
+	if _, demoted, err := st.User().SyncAdmins(nil); err != nil || demoted != 1 {
+		t.Fatalf("SyncAdmins(nil) = %d demoted, %v, want 1", demoted, err)
+	}
+	if code := request("u-admin", "Ops@example.com"); code != http.StatusForbidden {
+		t.Errorf("revoked admin: status = %d, want 403", code)
+	}
+	if isAdmin, _ := st.User().IsAdmin("admin"); !isAdmin {
+		t.Error("built-in admin user demoted")
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditQueueSize     = 1024
+	auditBatchSize     = 100
+	auditFlushInterval = 2 * time.Second
+)
+
+// auditSkipPaths endpoints not worth auditing (polled constantly)
+var auditSkipPaths = map[string]bool{
+	"/api/health": true,
+}
+
+// apiAuditor writes API audit log entries in batches on a background goroutine,
+// so auditing never blocks a request. Entries are dropped when the queue is full.
+type apiAuditor struct {
+	store   *store.APIAuditStore
+	entries chan *store.APIAuditLog
+}
+
+func newAPIAuditor(st *store.APIAuditStore) *apiAuditor {
+	a := &apiAuditor{
+		store:   st,
+		entries: make(chan *store.APIAuditLog, auditQueueSize),
+	}
+	go a.run()
+	return a
+}
+
+// record queues an entry without blocking
+func (a *apiAuditor) record(entry *store.APIAuditLog) {
+	select {
+	case a.entries <- entry:
+	default:
+		logger.Warnf("[Audit] queue full, dropping entry for %s %s", entry.Method, entry.Path)
+	}
+}
+
+func (a *apiAuditor) run() {
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*store.APIAuditLog, 0, auditBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.store.SaveBatch(batch); err != nil {
+			logger.Warnf("[Audit] failed to save %d entries: %v", len(batch), err)
+		}
+		batch = make([]*store.APIAuditLog, 0, auditBatchSize)
+	}
+
+	for {
+		select {
+		case entry := <-a.entries:
+			batch = append(batch, entry)
+			if len(batch) >= auditBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// auditMiddleware records user, endpoint, status and latency of each request
+// (user is known after authMiddleware ran further down the chain)
+func (s *Server) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.auditor == nil || c.Request.Method == http.MethodOptions || auditSkipPaths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		s.auditor.record(&store.APIAuditLog{
+			UserID:    c.GetString("user_id"),
+			Method:    c.Request.Method,
+			Path:      path,
+			Status:    c.Writer.Status(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			ClientIP:  c.ClientIP(),
+			CreatedAt: start.UTC(),
+		})
+	}
+}
+
+// handleAdminAuditLogs Recent API audit log entries (admin only)
+// Query params: user_id, path (route path, e.g. /api/decisions), limit (default 100, max 1000)
+func (s *Server) handleAdminAuditLogs(c *gin.Context) {
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	entries, err := s.store.APIAudit().List(c.Query("user_id"), c.Query("path"), limit)
+	if err != nil {
+		SafeInternalError(c, "Get audit logs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "count": len(entries)})
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"nofx/backtest"
+	"nofx/kernel"
 	"nofx/logger"
 	"nofx/market"
 	"nofx/provider/nofxos"
@@ -23,6 +24,7 @@ import (
 
 func (s *Server) registerBacktestRoutes(router *gin.RouterGroup) {
 	router.POST("/start", s.handleBacktestStart)
+	router.POST("/sweep", s.handleBacktestSweep)
 	router.POST("/pause", s.handleBacktestPause)
 	router.POST("/resume", s.handleBacktestResume)
 	router.POST("/stop", s.handleBacktestStop)
@@ -54,7 +56,7 @@ type labelRequest struct {
 
 func (s *Server) handleBacktestStart(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 
@@ -70,11 +72,16 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	}
 	cfg.CustomPrompt = strings.TrimSpace(cfg.CustomPrompt)
 	cfg.UserID = normalizeUserID(c.GetString("user_id"))
+	// ?mode=walkforward|sweep overrides the mode in the body
+	if mode := c.Query("mode"); mode != "" {
+		cfg.Mode = mode
+	}
 
 	logger.Infof("📊 Backtest request - symbols from request: %v (count=%d), strategyID: %s",
 		cfg.Symbols, len(cfg.Symbols), cfg.StrategyID)
 
 	// Load strategy config if strategy_id is provided
+	cfg.StrategyConfigHash = ""
 	if cfg.StrategyID != "" {
 		strategy, err := s.store.Strategy().Get(cfg.UserID, cfg.StrategyID)
 		if err != nil {
@@ -91,6 +98,7 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 			return
 		}
 		cfg.SetLoadedStrategy(&strategyConfig)
+		cfg.StrategyConfigHash = store.HashStrategyConfig(strategy.Config)
 		logger.Infof("📊 Backtest using saved strategy: %s (%s)", strategy.Name, strategy.ID)
 		logger.Infof("📊 Strategy coin source: type=%s, use_ai500=%v, use_oi_top=%v, static_coins=%v",
 			strategyConfig.CoinSource.SourceType,
@@ -118,6 +126,26 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	logger.Infof("📊 Starting backtest with final config: runID=%s, symbols=%v (count=%d), strategyID=%s",
 		cfg.RunID, cfg.Symbols, len(cfg.Symbols), cfg.StrategyID)
 
+	if strings.EqualFold(strings.TrimSpace(cfg.Mode), backtest.ModeWalkForward) {
+		meta, err := s.backtestManager.StartWalkForward(context.Background(), cfg)
+		if err != nil {
+			SafeError(c, http.StatusBadRequest, "Failed to start walk-forward backtest", err)
+			return
+		}
+		c.JSON(http.StatusOK, meta)
+		return
+	}
+
+	if strings.EqualFold(strings.TrimSpace(cfg.Mode), backtest.ModeSweep) {
+		meta, err := s.backtestManager.StartSweep(context.Background(), cfg)
+		if err != nil {
+			SafeError(c, http.StatusBadRequest, "Failed to start sweep backtest", err)
+			return
+		}
+		c.JSON(http.StatusOK, meta)
+		return
+	}
+
 	runner, err := s.backtestManager.Start(context.Background(), cfg)
 	if err != nil {
 		SafeError(c, http.StatusBadRequest, "Failed to start backtest", err)
@@ -128,6 +156,15 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	c.JSON(http.StatusOK, meta)
 }
 
+// handleBacktestSweep starts a parameter sweep (same body as /start with config.sweep set).
+// The comparison table is in the sweep run's metrics (GET /metrics, "sweep").
+func (s *Server) handleBacktestSweep(c *gin.Context) {
+	query := c.Request.URL.Query()
+	query.Set("mode", backtest.ModeSweep)
+	c.Request.URL.RawQuery = query.Encode()
+	s.handleBacktestStart(c)
+}
+
 func (s *Server) handleBacktestPause(c *gin.Context) {
 	s.handleBacktestControl(c, s.backtestManager.Pause)
 }
@@ -142,7 +179,7 @@ func (s *Server) handleBacktestStop(c *gin.Context) {
 
 func (s *Server) handleBacktestControl(c *gin.Context, fn func(string) error) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	userID := normalizeUserID(c.GetString("user_id"))
@@ -176,7 +213,7 @@ func (s *Server) handleBacktestControl(c *gin.Context, fn func(string) error) {
 
 func (s *Server) handleBacktestLabel(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	var req labelRequest
@@ -202,7 +239,7 @@ func (s *Server) handleBacktestLabel(c *gin.Context) {
 
 func (s *Server) handleBacktestDelete(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	var req runIDRequest
@@ -227,7 +264,7 @@ func (s *Server) handleBacktestDelete(c *gin.Context) {
 
 func (s *Server) handleBacktestStatus(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 
@@ -235,7 +272,7 @@ func (s *Server) handleBacktestStatus(c *gin.Context) {
 
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 
@@ -268,7 +305,7 @@ func (s *Server) handleBacktestStatus(c *gin.Context) {
 
 func (s *Server) handleBacktestRuns(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	rawUserID := strings.TrimSpace(c.GetString("user_id"))
@@ -330,7 +367,7 @@ func (s *Server) handleBacktestRuns(c *gin.Context) {
 
 func (s *Server) handleBacktestEquity(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 
@@ -338,7 +375,7 @@ func (s *Server) handleBacktestEquity(c *gin.Context) {
 
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -357,7 +394,7 @@ func (s *Server) handleBacktestEquity(c *gin.Context) {
 
 func (s *Server) handleBacktestTrades(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 
@@ -365,7 +402,7 @@ func (s *Server) handleBacktestTrades(c *gin.Context) {
 
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -383,7 +420,7 @@ func (s *Server) handleBacktestTrades(c *gin.Context) {
 
 func (s *Server) handleBacktestMetrics(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 
@@ -391,7 +428,7 @@ func (s *Server) handleBacktestMetrics(c *gin.Context) {
 
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -401,7 +438,7 @@ func (s *Server) handleBacktestMetrics(c *gin.Context) {
 	metrics, err := s.backtestManager.GetMetrics(runID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, os.ErrNotExist) {
-			c.JSON(http.StatusAccepted, gin.H{"error": "metrics not ready yet"})
+			respondError(c, http.StatusAccepted, ErrCodeNotReady, "metrics not ready yet")
 			return
 		}
 		SafeError(c, http.StatusBadRequest, "Failed to load metrics", err)
@@ -412,13 +449,13 @@ func (s *Server) handleBacktestMetrics(c *gin.Context) {
 
 func (s *Server) handleBacktestTrace(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	userID := normalizeUserID(c.GetString("user_id"))
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -435,13 +472,13 @@ func (s *Server) handleBacktestTrace(c *gin.Context) {
 
 func (s *Server) handleBacktestDecisions(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	userID := normalizeUserID(c.GetString("user_id"))
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -469,13 +506,13 @@ func (s *Server) handleBacktestDecisions(c *gin.Context) {
 
 func (s *Server) handleBacktestExport(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	userID := normalizeUserID(c.GetString("user_id"))
 	runID := c.Query("run_id")
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if _, err := s.ensureBacktestRunOwnership(runID, userID); writeBacktestAccessError(c, err) {
@@ -493,7 +530,7 @@ func (s *Server) handleBacktestExport(c *gin.Context) {
 
 func (s *Server) handleBacktestKlines(c *gin.Context) {
 	if s.backtestManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "backtest manager unavailable")
 		return
 	}
 	userID := normalizeUserID(c.GetString("user_id"))
@@ -502,11 +539,11 @@ func (s *Server) handleBacktestKlines(c *gin.Context) {
 	timeframe := c.Query("timeframe")
 
 	if runID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "run_id is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "run_id is required")
 		return
 	}
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol is required")
 		return
 	}
 
@@ -518,7 +555,7 @@ func (s *Server) handleBacktestKlines(c *gin.Context) {
 	// Load config to get time range
 	cfg, err := backtest.LoadConfig(runID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "failed to load backtest config"})
+		respondError(c, http.StatusNotFound, ErrCodeBacktestNotFound, "failed to load backtest config")
 		return
 	}
 
@@ -757,6 +794,32 @@ func (s *Server) resolveStrategyCoins(strategyConfig *store.StrategyConfig) ([]s
 			}
 		}
 
+		// Add pluggable sources
+		if len(coinSource.Sources) > 0 {
+			coins, err := kernel.NewStrategyEngine(strategyConfig).GetSourceCoins()
+			if err != nil {
+				logger.Warnf("Failed to get coin source coins: %v", err)
+			}
+			for _, sym := range coins {
+				if !symbolSet[sym] {
+					symbols = append(symbols, sym)
+					symbolSet[sym] = true
+				}
+			}
+		}
+
+	case "custom":
+		coins, err := kernel.NewStrategyEngine(strategyConfig).GetSourceCoins()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get coin source coins: %w", err)
+		}
+		for _, sym := range coins {
+			if !symbolSet[sym] {
+				symbols = append(symbols, sym)
+				symbolSet[sym] = true
+			}
+		}
+
 	default:
 		return nil, fmt.Errorf("unknown coin source type: %s", sourceType)
 	}
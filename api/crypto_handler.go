@@ -59,7 +59,7 @@ func (h *CryptoHandler) HandleGetPublicKey(c *gin.Context) {
 func (h *CryptoHandler) HandleDecryptSensitiveData(c *gin.Context) {
 	var payload crypto.EncryptedPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request")
 		return
 	}
 
@@ -67,7 +67,7 @@ func (h *CryptoHandler) HandleDecryptSensitiveData(c *gin.Context) {
 	decrypted, err := h.cryptoService.DecryptSensitiveData(&payload)
 	if err != nil {
 		log.Printf("❌ Decryption failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Decryption failed"})
+		respondError(c, http.StatusInternalServerError, ErrCodeDecryptionFailed, "Decryption failed")
 		return
 	}
 
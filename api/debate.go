@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"nofx/debate"
+	"nofx/kernel"
 	"nofx/logger"
 	"nofx/provider/nofxos"
 	"nofx/store"
@@ -82,14 +83,14 @@ type ParticipantConfig struct {
 func (h *DebateHandler) HandleListDebates(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized")
 		return
 	}
 
 	sessions, err := h.debateStore.GetSessionsByUser(userID)
 	if err != nil {
 		logger.Errorf("Failed to get debates for user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get debates"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to get debates")
 		return
 	}
 
@@ -108,13 +109,13 @@ func (h *DebateHandler) HandleGetDebate(c *gin.Context) {
 
 	session, err := h.debateStore.GetSessionWithDetails(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	// Check ownership
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
@@ -125,7 +126,7 @@ func (h *DebateHandler) HandleGetDebate(c *gin.Context) {
 func (h *DebateHandler) HandleCreateDebate(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized")
 		return
 	}
 
@@ -138,13 +139,13 @@ func (h *DebateHandler) HandleCreateDebate(c *gin.Context) {
 	// Validate strategy exists
 	strategy, err := h.strategyStore.Get(userID, req.StrategyID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "strategy not found"})
+		respondError(c, http.StatusBadRequest, ErrCodeStrategyNotFound, "strategy not found")
 		return
 	}
 
 	// Validate strategy belongs to user or is default
 	if strategy.UserID != userID && !strategy.IsDefault {
-		c.JSON(http.StatusForbidden, gin.H{"error": "strategy access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "strategy access denied")
 		return
 	}
 
@@ -170,6 +171,11 @@ func (h *DebateHandler) HandleCreateDebate(c *gin.Context) {
 					req.Symbol = coins[0]
 					logger.Infof("Fetched coin from OI Top API: %s", req.Symbol)
 				}
+			case "custom":
+				if coins, err := kernel.NewStrategyEngine(strategyConfig).GetSourceCoins(); err == nil && len(coins) > 0 {
+					req.Symbol = coins[0]
+					logger.Infof("Fetched coin from coin sources: %s", req.Symbol)
+				}
 			case "mixed":
 				// Try AI500 first, then OI top
 				if coinSource.UseAI500 {
@@ -217,7 +223,7 @@ func (h *DebateHandler) HandleCreateDebate(c *gin.Context) {
 	}
 
 	if err := h.debateStore.CreateSession(session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create debate"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to create debate")
 		return
 	}
 
@@ -268,17 +274,17 @@ func (h *DebateHandler) HandleStartDebate(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
 	if session.Status != store.DebateStatusPending {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "debate is not in pending status"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "debate is not in pending status")
 		return
 	}
 
@@ -298,12 +304,12 @@ func (h *DebateHandler) HandleCancelDebate(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
@@ -322,23 +328,23 @@ func (h *DebateHandler) HandleDeleteDebate(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
 	// Don't allow deleting running debates
 	if session.Status == store.DebateStatusRunning || session.Status == store.DebateStatusVoting {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete running debate"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "cannot delete running debate")
 		return
 	}
 
 	if err := h.debateStore.DeleteSession(debateID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete debate"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to delete debate")
 		return
 	}
 
@@ -352,18 +358,18 @@ func (h *DebateHandler) HandleGetMessages(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
 	messages, err := h.debateStore.GetMessages(debateID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get messages"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to get messages")
 		return
 	}
 
@@ -377,18 +383,18 @@ func (h *DebateHandler) HandleGetVotes(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
 	votes, err := h.debateStore.GetVotes(debateID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get votes"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to get votes")
 		return
 	}
 
@@ -402,12 +408,12 @@ func (h *DebateHandler) HandleDebateStream(c *gin.Context) {
 
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
@@ -461,26 +467,26 @@ func (h *DebateHandler) HandleExecuteDebate(c *gin.Context) {
 
 	// Check trader manager is available
 	if h.traderManager == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "trading service not available"})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeServiceUnavailable, "trading service not available")
 		return
 	}
 
 	// Get debate session
 	session, err := h.debateStore.GetSession(debateID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "debate not found"})
+		respondError(c, http.StatusNotFound, ErrCodeDebateNotFound, "debate not found")
 		return
 	}
 
 	// Check ownership
 	if session.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "access denied")
 		return
 	}
 
 	// Check status
 	if session.Status != store.DebateStatusCompleted {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "debate is not completed"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "debate is not completed")
 		return
 	}
 
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nofx/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxDecisionNotesLength = 4000
+	maxDecisionTags        = 20
+	maxDecisionTagLength   = 50
+)
+
+// normalizeDecisionTags trims tags, drops empty ones and duplicates (case-insensitive), keeps input order
+func normalizeDecisionTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// handleAnnotateDecision stores user notes and tags on a decision record
+func (s *Server) handleAnnotateDecision(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	decisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || decisionID <= 0 {
+		SafeBadRequest(c, "Invalid decision ID")
+		return
+	}
+
+	var req struct {
+		Notes string   `json:"notes"`
+		Tags  []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	req.Notes = strings.TrimSpace(req.Notes)
+	if len(req.Notes) > maxDecisionNotesLength {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Notes too long (max 4000 characters)")
+		return
+	}
+	tags := normalizeDecisionTags(req.Tags)
+	if len(tags) > maxDecisionTags {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Too many tags (max 20)")
+		return
+	}
+	for _, tag := range tags {
+		if len(tag) > maxDecisionTagLength {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Tag too long (max 50 characters)")
+			return
+		}
+	}
+
+	record, err := s.store.Decision().GetRecordByID(decisionID)
+	if err != nil {
+		SafeNotFound(c, "Decision")
+		return
+	}
+
+	// Only the owner of the trader may annotate its decisions
+	traderRecord, err := s.store.Trader().GetByID(record.TraderID)
+	if err != nil || traderRecord.UserID != userID {
+		SafeNotFound(c, "Decision")
+		return
+	}
+
+	if err := s.store.Decision().Annotate(decisionID, req.Notes, tags); err != nil {
+		SafeInternalError(c, "Annotate decision", err)
+		return
+	}
+
+	logger.Infof("📝 User %s annotated decision %d (trader %s, tags: %v)", userID, decisionID, record.TraderID, tags)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":    decisionID,
+		"notes": req.Notes,
+		"tags":  tags,
+	})
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeDecisionTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{name: "nil", in: nil, want: []string{}},
+		{name: "trim and drop empty", in: []string{" good call ", "", "  "}, want: []string{"good call"}},
+		{name: "dedupe case-insensitive", in: []string{"Good Call", "good call", "late entry"}, want: []string{"Good Call", "late entry"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeDecisionTags(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeDecisionTags(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
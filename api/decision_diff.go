@@ -0,0 +1,201 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDecisionDiffLimit = 20
+	maxDecisionDiffLimit     = 100
+)
+
+// decisionChange one symbol's action in a cycle, compared with the same symbol in the previous cycle
+type decisionChange struct {
+	Symbol           string  `json:"symbol"`
+	Action           string  `json:"action"`
+	Reasoning        string  `json:"reasoning,omitempty"`
+	Confidence       int     `json:"confidence,omitempty"`
+	Quantity         float64 `json:"quantity,omitempty"`
+	Price            float64 `json:"price,omitempty"`
+	Error            string  `json:"error,omitempty"`
+	PrevAction       string  `json:"prev_action,omitempty"` // Empty if the symbol wasn't addressed in the previous cycle
+	PrevReasoning    string  `json:"prev_reasoning,omitempty"`
+	PrevConfidence   int     `json:"prev_confidence,omitempty"`
+	ReasoningChanged bool    `json:"reasoning_changed"`
+}
+
+// decisionCycleDiff what the AI changed in one cycle versus the previous one
+type decisionCycleDiff struct {
+	RecordID        int64            `json:"record_id"`
+	CycleNumber     int              `json:"cycle_number"`
+	Timestamp       time.Time        `json:"timestamp"`
+	Success         bool             `json:"success"`
+	ErrorMessage    string           `json:"error_message,omitempty"`
+	PrevRecordID    int64            `json:"prev_record_id,omitempty"` // 0 if no earlier cycle is in the window
+	PrevCycleNumber int              `json:"prev_cycle_number,omitempty"`
+	Opened          []decisionChange `json:"opened"`
+	Closed          []decisionChange `json:"closed"`
+	Reduced         []decisionChange `json:"reduced"`
+	Held            []decisionChange `json:"held"`
+	Failed          []decisionChange `json:"failed"`  // Open/close/reduce actions that failed to execute
+	Dropped         []decisionChange `json:"dropped"` // Opened or held last cycle, not addressed in this one
+}
+
+// normalizeReasoning collapses whitespace so formatting-only differences don't count as a reasoning change
+func normalizeReasoning(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// lastActionBySymbol returns the last action of each symbol in a cycle (an auto-flip records two legs)
+func lastActionBySymbol(actions []store.DecisionAction) map[string]store.DecisionAction {
+	bySymbol := make(map[string]store.DecisionAction, len(actions))
+	for _, a := range actions {
+		bySymbol[a.Symbol] = a
+	}
+	return bySymbol
+}
+
+// diffDecisionCycles correlates consecutive decision records (oldest first) and returns one diff per record.
+// Each record is compared with the closest earlier record that has decisions, so a failed AI call in between
+// doesn't make every position look new.
+func diffDecisionCycles(records []*store.DecisionRecord) []decisionCycleDiff {
+	diffs := make([]decisionCycleDiff, 0, len(records))
+	var prev *store.DecisionRecord
+
+	for _, record := range records {
+		diff := decisionCycleDiff{
+			RecordID:     record.ID,
+			CycleNumber:  record.CycleNumber,
+			Timestamp:    record.Timestamp,
+			Success:      record.Success,
+			ErrorMessage: record.ErrorMessage,
+			Opened:       []decisionChange{},
+			Closed:       []decisionChange{},
+			Reduced:      []decisionChange{},
+			Held:         []decisionChange{},
+			Failed:       []decisionChange{},
+			Dropped:      []decisionChange{},
+		}
+
+		prevBySymbol := map[string]store.DecisionAction{}
+		if prev != nil {
+			diff.PrevRecordID = prev.ID
+			diff.PrevCycleNumber = prev.CycleNumber
+			prevBySymbol = lastActionBySymbol(prev.Decisions)
+		}
+
+		addressed := make(map[string]bool, len(record.Decisions))
+		for _, a := range record.Decisions {
+			addressed[a.Symbol] = true
+
+			change := decisionChange{
+				Symbol:           a.Symbol,
+				Action:           a.Action,
+				Reasoning:        a.Reasoning,
+				Confidence:       a.Confidence,
+				Quantity:         a.Quantity,
+				Price:            a.Price,
+				Error:            a.Error,
+				ReasoningChanged: true,
+			}
+			if p, ok := prevBySymbol[a.Symbol]; ok {
+				change.PrevAction = p.Action
+				change.PrevReasoning = p.Reasoning
+				change.PrevConfidence = p.Confidence
+				change.ReasoningChanged = normalizeReasoning(p.Reasoning) != normalizeReasoning(a.Reasoning)
+			}
+
+			switch {
+			case a.Action == "hold":
+				diff.Held = append(diff.Held, change)
+			case a.Action == "wait":
+				// Waiting on a symbol without a position isn't a change
+			case !a.Success:
+				diff.Failed = append(diff.Failed, change)
+			case strings.HasPrefix(a.Action, "open_"):
+				diff.Opened = append(diff.Opened, change)
+			case strings.HasPrefix(a.Action, "close_"):
+				diff.Closed = append(diff.Closed, change)
+			case strings.HasPrefix(a.Action, "reduce_"):
+				diff.Reduced = append(diff.Reduced, change)
+			}
+		}
+
+		if prev != nil {
+			for _, p := range prev.Decisions {
+				if addressed[p.Symbol] {
+					continue
+				}
+				if p.Action == "hold" || (strings.HasPrefix(p.Action, "open_") && p.Success) {
+					addressed[p.Symbol] = true // Report each symbol once
+					diff.Dropped = append(diff.Dropped, decisionChange{
+						Symbol:         p.Symbol,
+						PrevAction:     p.Action,
+						PrevReasoning:  p.Reasoning,
+						PrevConfidence: p.Confidence,
+					})
+				}
+			}
+		}
+
+		diffs = append(diffs, diff)
+		if len(record.Decisions) > 0 {
+			prev = record
+		}
+	}
+	return diffs
+}
+
+// handleDecisionDiff Per-cycle audit trail: positions opened/closed/held versus the previous cycle and why
+// Query params: trader_id, limit (number of cycles, default 20, max 100). Newest cycle first.
+func (s *Server) handleDecisionDiff(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, "Invalid trader ID")
+		return
+	}
+
+	trader, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	limit := defaultDecisionDiffLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, maxDecisionDiffLimit)
+	}
+
+	// One extra record so the oldest returned cycle still has a predecessor to compare with
+	records, err := trader.GetStore().Decision().GetLatestRecords(trader.GetID(), limit+1)
+	if err != nil {
+		SafeInternalError(c, "Get decision log", err)
+		return
+	}
+
+	diffs := diffDecisionCycles(records)
+	if len(records) > limit {
+		diffs = diffs[1:]
+	}
+	// Newest first, like /decisions/latest
+	for i, j := 0, len(diffs)-1; i < j; i, j = i+1, j-1 {
+		diffs[i], diffs[j] = diffs[j], diffs[i]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": trader.GetID(),
+		"cycles":    diffs,
+	})
+}
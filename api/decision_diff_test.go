@@ -0,0 +1,64 @@
+package api
+
+import (
+	"testing"
+
+	"nofx/store"
+)
+
+func TestDiffDecisionCycles(t *testing.T) {
+	records := []*store.DecisionRecord{
+		{ID: 1, CycleNumber: 1, Success: true, Decisions: []store.DecisionAction{
+			{Action: "open_long", Symbol: "BTCUSDT", Reasoning: "breakout", Success: true},
+			{Action: "open_short", Symbol: "ETHUSDT", Reasoning: "weak", Success: true},
+			{Action: "wait", Symbol: "SOLUSDT", Reasoning: "no setup", Success: true},
+		}},
+		// Failed AI call: skipped as the comparison base
+		{ID: 2, CycleNumber: 2, Success: false, ErrorMessage: "timeout"},
+		{ID: 3, CycleNumber: 3, Success: true, Decisions: []store.DecisionAction{
+			{Action: "hold", Symbol: "BTCUSDT", Reasoning: "trend  intact", Success: true},
+			{Action: "open_long", Symbol: "SOLUSDT", Reasoning: "reclaim", Error: "insufficient margin"},
+		}},
+		{ID: 4, CycleNumber: 4, Success: true, Decisions: []store.DecisionAction{
+			{Action: "close_long", Symbol: "BTCUSDT", Reasoning: "target hit", Success: true},
+		}},
+	}
+
+	diffs := diffDecisionCycles(records)
+	if len(diffs) != 4 {
+		t.Fatalf("got %d diffs, want 4", len(diffs))
+	}
+
+	first := diffs[0]
+	if first.PrevRecordID != 0 || len(first.Opened) != 2 || len(first.Held) != 0 {
+		t.Errorf("first cycle: prev=%d opened=%d held=%d, want 0/2/0", first.PrevRecordID, len(first.Opened), len(first.Held))
+	}
+
+	third := diffs[2]
+	if third.PrevRecordID != 1 {
+		t.Errorf("third cycle compared with record %d, want 1 (failed cycle skipped)", third.PrevRecordID)
+	}
+	if len(third.Held) != 1 || third.Held[0].PrevAction != "open_long" || !third.Held[0].ReasoningChanged {
+		t.Errorf("third cycle held = %+v, want BTCUSDT held after open_long with changed reasoning", third.Held)
+	}
+	if len(third.Failed) != 1 || third.Failed[0].Symbol != "SOLUSDT" || third.Failed[0].PrevAction != "wait" {
+		t.Errorf("third cycle failed = %+v, want SOLUSDT open after wait", third.Failed)
+	}
+	if len(third.Dropped) != 1 || third.Dropped[0].Symbol != "ETHUSDT" {
+		t.Errorf("third cycle dropped = %+v, want ETHUSDT", third.Dropped)
+	}
+
+	fourth := diffs[3]
+	if len(fourth.Closed) != 1 || fourth.Closed[0].PrevAction != "hold" || fourth.Closed[0].PrevReasoning != "trend  intact" {
+		t.Errorf("fourth cycle closed = %+v, want BTCUSDT closed after hold", fourth.Closed)
+	}
+	if len(fourth.Dropped) != 0 {
+		t.Errorf("fourth cycle dropped = %+v, want none (failed open is not a position)", fourth.Dropped)
+	}
+}
+
+func TestNormalizeReasoning(t *testing.T) {
+	if normalizeReasoning(" trend\n intact ") != normalizeReasoning("trend intact") {
+		t.Error("whitespace-only differences should not count as a reasoning change")
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// strategyConfigDiff returns the JSON paths (e.g. "risk_control.max_leverage") whose values differ
+// between two strategy configs; empty when they are equivalent
+func strategyConfigDiff(a, b *store.StrategyConfig) ([]string, error) {
+	var av, bv interface{}
+	for _, pair := range []struct {
+		cfg *store.StrategyConfig
+		out *interface{}
+	}{{a, &av}, {b, &bv}} {
+		data, err := json.Marshal(pair.cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, pair.out); err != nil {
+			return nil, err
+		}
+	}
+
+	var diffs []string
+	diffJSON("", av, bv, &diffs)
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+// diffJSON collects the paths at which two decoded JSON values differ, descending into objects
+func diffJSON(path string, a, b interface{}, diffs *[]string) {
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if !aIsMap || !bIsMap {
+		if !reflect.DeepEqual(a, b) {
+			*diffs = append(*diffs, path)
+		}
+		return
+	}
+
+	keys := make(map[string]struct{}, len(am)+len(bm))
+	for k := range am {
+		keys[k] = struct{}{}
+	}
+	for k := range bm {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		child := k
+		if path != "" {
+			child = path + "." + k
+		}
+		diffJSON(child, am[k], bm[k], diffs)
+	}
+}
+
+// handleTraderEffectiveConfig Strategy configuration the in-memory trader is running with, and whether
+// it still matches the stored strategy (it may not if a reload failed after the strategy was edited)
+func (s *Server) handleTraderEffectiveConfig(c *gin.Context) {
+	traderID := c.Param("id")
+	fullCfg, err := s.store.Trader().GetFullConfig(c.GetString("user_id"), traderID)
+	if err != nil || fullCfg.Trader == nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotLoaded, "Trader is not loaded in memory")
+		return
+	}
+	effective := at.GetStrategyConfig()
+	if effective == nil {
+		respondError(c, http.StatusNotFound, ErrCodeStrategyRequired, "Trader has no strategy engine")
+		return
+	}
+
+	result := gin.H{
+		"trader_id":        traderID,
+		"strategy_id":      fullCfg.Trader.StrategyID,
+		"is_running":       at.GetStatus()["is_running"],
+		"effective_config": effective,
+		"matches_stored":   false,
+	}
+
+	if fullCfg.Strategy == nil {
+		result["stored_error"] = "Stored strategy not found"
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	result["strategy_name"] = fullCfg.Strategy.Name
+	stored, err := fullCfg.Strategy.ParseConfig()
+	if err != nil {
+		result["stored_error"] = err.Error()
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	diffs, err := strategyConfigDiff(effective, stored)
+	if err != nil {
+		SafeInternalError(c, "Compare strategy configs", err)
+		return
+	}
+	result["matches_stored"] = len(diffs) == 0
+	result["differences"] = diffs
+	c.JSON(http.StatusOK, result)
+}
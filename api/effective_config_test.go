@@ -0,0 +1,32 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"nofx/store"
+)
+
+func TestStrategyConfigDiff(t *testing.T) {
+	a := store.GetDefaultStrategyConfig("en")
+	b := store.GetDefaultStrategyConfig("en")
+
+	diffs, err := strategyConfigDiff(&a, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("identical configs differ at %v", diffs)
+	}
+
+	b.RiskControl.BTCETHMaxLeverage = a.RiskControl.BTCETHMaxLeverage + 1
+	b.CustomPrompt = "changed"
+	diffs, err = strategyConfigDiff(&a, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"custom_prompt", "risk_control.btc_eth_max_leverage"}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("diffs = %v, want %v", diffs, want)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxEquityAlertsPerTrader = 20
+	maxEquityAlertNoteLength = 200
+	maxEquityAlertHysteresis = 50.0
+)
+
+// equityAlertRequest create/update body; nil fields keep their value on update
+type equityAlertRequest struct {
+	Direction     *string  `json:"direction"`
+	Threshold     *float64 `json:"threshold"`
+	HysteresisPct *float64 `json:"hysteresis_pct"`
+	Note          *string  `json:"note"`
+	Enabled       *bool    `json:"enabled"`
+}
+
+// apply copies the set fields of the request onto alert
+func (r *equityAlertRequest) apply(alert *store.EquityAlert) {
+	if r.Direction != nil {
+		alert.Direction = strings.ToLower(strings.TrimSpace(*r.Direction))
+	}
+	if r.Threshold != nil {
+		alert.Threshold = *r.Threshold
+	}
+	if r.HysteresisPct != nil {
+		alert.HysteresisPct = *r.HysteresisPct
+	}
+	if r.Note != nil {
+		alert.Note = strings.TrimSpace(*r.Note)
+	}
+	if r.Enabled != nil {
+		alert.Enabled = *r.Enabled
+	}
+}
+
+// validateEquityAlert checks an alert rule before saving
+func validateEquityAlert(alert *store.EquityAlert) error {
+	if alert.Direction != store.EquityAlertBelow && alert.Direction != store.EquityAlertAbove {
+		return fmt.Errorf("direction must be %q or %q", store.EquityAlertBelow, store.EquityAlertAbove)
+	}
+	if alert.Threshold <= 0 {
+		return fmt.Errorf("threshold must be greater than 0")
+	}
+	if alert.HysteresisPct < 0 || alert.HysteresisPct > maxEquityAlertHysteresis {
+		return fmt.Errorf("hysteresis_pct must be between 0 and %.0f", maxEquityAlertHysteresis)
+	}
+	if len(alert.Note) > maxEquityAlertNoteLength {
+		return fmt.Errorf("note too long (max %d characters)", maxEquityAlertNoteLength)
+	}
+	return nil
+}
+
+// checkTraderAccess verifies the trader exists and belongs to the user, writing a 404 otherwise
+func (s *Server) checkTraderAccess(c *gin.Context, traderID string) bool {
+	fullCfg, err := s.store.Trader().GetFullConfig(c.GetString("user_id"), traderID)
+	if err != nil || fullCfg.Trader == nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return false
+	}
+	return true
+}
+
+// parseEquityAlertID parses the :alertId path parameter
+func parseEquityAlertID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("alertId"), 10, 64)
+	if err != nil || id <= 0 {
+		SafeBadRequest(c, "Invalid alert ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// handleListEquityAlerts lists a trader's equity alert rules
+func (s *Server) handleListEquityAlerts(c *gin.Context) {
+	traderID := c.Param("id")
+	if !s.checkTraderAccess(c, traderID) {
+		return
+	}
+
+	alerts, err := s.store.EquityAlert().List(traderID)
+	if err != nil {
+		SafeInternalError(c, "List equity alerts", err)
+		return
+	}
+	if alerts == nil {
+		alerts = []*store.EquityAlert{}
+	}
+	c.JSON(http.StatusOK, alerts)
+}
+
+// handleCreateEquityAlert adds an equity alert rule to a trader
+func (s *Server) handleCreateEquityAlert(c *gin.Context) {
+	traderID := c.Param("id")
+	if !s.checkTraderAccess(c, traderID) {
+		return
+	}
+
+	var req equityAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	alert := &store.EquityAlert{TraderID: traderID, Enabled: true}
+	req.apply(alert)
+	if err := validateEquityAlert(alert); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	existing, err := s.store.EquityAlert().List(traderID)
+	if err != nil {
+		SafeInternalError(c, "List equity alerts", err)
+		return
+	}
+	if len(existing) >= maxEquityAlertsPerTrader {
+		SafeBadRequest(c, fmt.Sprintf("Too many alerts (max %d per trader)", maxEquityAlertsPerTrader))
+		return
+	}
+
+	if err := s.store.EquityAlert().Create(alert); err != nil {
+		SafeInternalError(c, "Create equity alert", err)
+		return
+	}
+	c.JSON(http.StatusCreated, alert)
+}
+
+// handleUpdateEquityAlert edits an equity alert rule (re-arms it)
+func (s *Server) handleUpdateEquityAlert(c *gin.Context) {
+	traderID := c.Param("id")
+	if !s.checkTraderAccess(c, traderID) {
+		return
+	}
+	alertID, ok := parseEquityAlertID(c)
+	if !ok {
+		return
+	}
+
+	var req equityAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	alert, err := s.store.EquityAlert().Get(traderID, alertID)
+	if err != nil {
+		SafeNotFound(c, "Alert")
+		return
+	}
+	req.apply(alert)
+	if err := validateEquityAlert(alert); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	if err := s.store.EquityAlert().Update(alert); err != nil {
+		SafeInternalError(c, "Update equity alert", err)
+		return
+	}
+	alert.Triggered = false
+	c.JSON(http.StatusOK, alert)
+}
+
+// handleDeleteEquityAlert deletes an equity alert rule
+func (s *Server) handleDeleteEquityAlert(c *gin.Context) {
+	traderID := c.Param("id")
+	if !s.checkTraderAccess(c, traderID) {
+		return
+	}
+	alertID, ok := parseEquityAlertID(c)
+	if !ok {
+		return
+	}
+
+	if err := s.store.EquityAlert().Delete(traderID, alertID); err != nil {
+		SafeNotFound(c, "Alert")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Alert deleted"})
+}
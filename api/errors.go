@@ -1,13 +1,148 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"nofx/logger"
+	"nofx/trader"
 )
 
+// ErrorCode stable machine-readable error code, returned next to the human-readable message as
+// {"error": msg, "code": "TRADER_NOT_FOUND"}. Clients should branch (and translate) on the code;
+// messages may change, codes may not.
+type ErrorCode string
+
+const (
+	// Generic codes, one per HTTP status (used when nothing more specific applies)
+	ErrCodeInvalidRequest     ErrorCode = "INVALID_REQUEST"
+	ErrCodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrCodeConflict           ErrorCode = "CONFLICT"
+	ErrCodeRateLimited        ErrorCode = "RATE_LIMITED"
+	ErrCodeInternal           ErrorCode = "INTERNAL_ERROR"
+	ErrCodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrCodeNotReady           ErrorCode = "NOT_READY"
+
+	// Authentication
+	ErrCodeTokenExpired        ErrorCode = "TOKEN_EXPIRED"
+	ErrCodeInvalidCredentials  ErrorCode = "INVALID_CREDENTIALS"
+	ErrCodeInvalidOTP          ErrorCode = "INVALID_OTP"
+	ErrCodeOTPSetupRequired    ErrorCode = "OTP_SETUP_REQUIRED"
+	ErrCodeAdminRequired       ErrorCode = "ADMIN_REQUIRED"
+	ErrCodeReadOnlyToken       ErrorCode = "READ_ONLY_TOKEN"
+	ErrCodeRegistrationClosed  ErrorCode = "REGISTRATION_CLOSED"
+	ErrCodeEmailTaken          ErrorCode = "EMAIL_TAKEN"
+	ErrCodeEncryptionRequired  ErrorCode = "ENCRYPTION_REQUIRED"
+	ErrCodeDecryptionFailed    ErrorCode = "DECRYPTION_FAILED"
+	ErrCodeBalanceConfirmation ErrorCode = "BALANCE_CONFIRMATION_REQUIRED"
+
+	// Resources
+	ErrCodeTraderNotFound   ErrorCode = "TRADER_NOT_FOUND"
+	ErrCodeStrategyNotFound ErrorCode = "STRATEGY_NOT_FOUND"
+	ErrCodeExchangeNotFound ErrorCode = "EXCHANGE_NOT_FOUND"
+	ErrCodeModelNotFound    ErrorCode = "MODEL_NOT_FOUND"
+	ErrCodeUserNotFound     ErrorCode = "USER_NOT_FOUND"
+	ErrCodePositionNotFound ErrorCode = "POSITION_NOT_FOUND"
+	ErrCodeDecisionNotFound ErrorCode = "DECISION_NOT_FOUND"
+	ErrCodeBacktestNotFound ErrorCode = "BACKTEST_NOT_FOUND"
+	ErrCodeDebateNotFound   ErrorCode = "DEBATE_NOT_FOUND"
+
+	// Trader / exchange state
+	ErrCodeTraderRunning       ErrorCode = "TRADER_RUNNING"
+	ErrCodeTraderNotRunning    ErrorCode = "TRADER_NOT_RUNNING"
+	ErrCodeTraderDisabled      ErrorCode = "TRADER_DISABLED"
+	ErrCodeTraderNotLoaded     ErrorCode = "TRADER_NOT_LOADED"
+	ErrCodeStrategyRequired    ErrorCode = "STRATEGY_REQUIRED"
+	ErrCodeSystemStrategy      ErrorCode = "SYSTEM_STRATEGY_READONLY"
+	ErrCodeExchangeDisabled    ErrorCode = "EXCHANGE_DISABLED"
+	ErrCodeExchangeInUse       ErrorCode = "EXCHANGE_IN_USE"
+	ErrCodeModelDisabled       ErrorCode = "MODEL_DISABLED"
+	ErrCodeUnsupportedExchange ErrorCode = "UNSUPPORTED_EXCHANGE"
+	ErrCodeInsufficientMargin  ErrorCode = "INSUFFICIENT_MARGIN"
+	ErrCodeOrderTooSmall       ErrorCode = "ORDER_TOO_SMALL"
+	ErrCodeOrderRejected       ErrorCode = "ORDER_REJECTED"
+	ErrCodeTradingPaused       ErrorCode = "TRADING_PAUSED"
+)
+
+// respondError writes the error envelope {"error": msg, "code": code};
+// an empty code falls back to the generic code of the status
+func respondError(c *gin.Context, statusCode int, code ErrorCode, msg string) {
+	if code == "" {
+		code = statusErrorCode(statusCode)
+	}
+	c.JSON(statusCode, gin.H{"error": msg, "code": code})
+}
+
+// statusErrorCode generic code of an HTTP status
+func statusErrorCode(statusCode int) ErrorCode {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return ErrCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrCodeServiceUnavailable
+	case http.StatusAccepted:
+		return ErrCodeNotReady
+	}
+	return ErrCodeInternal
+}
+
+// ErrorCodeFor maps an internal error to a stable code ("" if it has no specific one,
+// so respondError falls back to the status code)
+func ErrorCodeFor(err error) ErrorCode {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, sql.ErrNoRows), errors.Is(err, os.ErrNotExist):
+		return ErrCodeNotFound
+	case errors.Is(err, trader.ErrUnsupportedExchange):
+		return ErrCodeUnsupportedExchange
+	case errors.Is(err, trader.ErrInsufficientMargin):
+		return ErrCodeInsufficientMargin
+	case errors.Is(err, trader.ErrMinNotional):
+		return ErrCodeOrderTooSmall
+	case errors.Is(err, trader.ErrReduceOnlyReject):
+		return ErrCodeOrderRejected
+	case errors.Is(err, trader.ErrTradingPaused), errors.Is(err, trader.ErrRiskControlPaused):
+		return ErrCodeTradingPaused
+	case errors.Is(err, trader.ErrSignalTraderNotRunning):
+		return ErrCodeTraderNotRunning
+	case errors.Is(err, trader.ErrSignalRateLimited), errors.Is(err, trader.ErrSignalQueueFull):
+		return ErrCodeRateLimited
+	case errors.Is(err, errBacktestForbidden):
+		return ErrCodeForbidden
+	}
+	return ""
+}
+
+// notFoundErrorCodes specific codes of the resources passed to SafeNotFound
+var notFoundErrorCodes = map[string]ErrorCode{
+	"Trader":           ErrCodeTraderNotFound,
+	"Trader config":    ErrCodeTraderNotFound,
+	"Strategy":         ErrCodeStrategyNotFound,
+	"Exchange":         ErrCodeExchangeNotFound,
+	"User":             ErrCodeUserNotFound,
+	"Decision":         ErrCodeDecisionNotFound,
+	"Backtest task":    ErrCodeBacktestNotFound,
+	"Backtest summary": ErrCodeBacktestNotFound,
+}
+
 // SafeError returns a safe error message without exposing internal details
 // It logs the actual error for debugging but returns a generic message to the client
 func SafeError(c *gin.Context, statusCode int, publicMsg string, internalErr error) {
@@ -16,34 +151,34 @@ func SafeError(c *gin.Context, statusCode int, publicMsg string, internalErr err
 		logger.Errorf("[API Error] %s: %v", publicMsg, internalErr)
 	}
 
-	c.JSON(statusCode, gin.H{"error": publicMsg})
+	respondError(c, statusCode, ErrorCodeFor(internalErr), publicMsg)
 }
 
 // SafeInternalError logs internal error and returns a generic message
 func SafeInternalError(c *gin.Context, operation string, err error) {
 	logger.Errorf("[Internal Error] %s: %v", operation, err)
-	c.JSON(http.StatusInternalServerError, gin.H{"error": operation + " failed"})
+	respondError(c, http.StatusInternalServerError, ErrCodeInternal, operation+" failed")
 }
 
 // SafeBadRequest returns a safe bad request error
 // For validation errors, we can be more specific since they're about user input
 func SafeBadRequest(c *gin.Context, msg string) {
-	c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, msg)
 }
 
 // SafeNotFound returns a generic not found error
 func SafeNotFound(c *gin.Context, resource string) {
-	c.JSON(http.StatusNotFound, gin.H{"error": resource + " not found"})
+	respondError(c, http.StatusNotFound, notFoundErrorCodes[resource], resource+" not found")
 }
 
 // SafeUnauthorized returns unauthorized error
 func SafeUnauthorized(c *gin.Context) {
-	c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 }
 
 // SafeForbidden returns forbidden error
 func SafeForbidden(c *gin.Context, msg string) {
-	c.JSON(http.StatusForbidden, gin.H{"error": msg})
+	respondError(c, http.StatusForbidden, ErrCodeForbidden, msg)
 }
 
 // IsSensitiveError checks if an error message contains sensitive information
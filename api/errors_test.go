@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"nofx/trader"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		respond    func(c *gin.Context)
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{"explicit code", func(c *gin.Context) {
+			respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
+		}, http.StatusBadRequest, ErrCodeExchangeDisabled},
+		{"status fallback", func(c *gin.Context) {
+			respondError(c, http.StatusServiceUnavailable, "", "backtest manager unavailable")
+		}, http.StatusServiceUnavailable, ErrCodeServiceUnavailable},
+		{"known resource", func(c *gin.Context) { SafeNotFound(c, "Trader") }, http.StatusNotFound, ErrCodeTraderNotFound},
+		{"other resource", func(c *gin.Context) { SafeNotFound(c, "Alert") }, http.StatusNotFound, ErrCodeNotFound},
+		{"internal", func(c *gin.Context) { SafeInternalError(c, "Get positions", errors.New("boom")) }, http.StatusInternalServerError, ErrCodeInternal},
+		{"mapped internal error", func(c *gin.Context) {
+			SafeError(c, http.StatusBadRequest, "Failed to start backtest", trader.ErrUnsupportedExchange)
+		}, http.StatusBadRequest, ErrCodeUnsupportedExchange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			tt.respond(c)
+
+			var body struct {
+				Error string    `json:"error"`
+				Code  ErrorCode `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON body %q: %v", w.Body.String(), err)
+			}
+			if w.Code != tt.wantStatus || body.Code != tt.wantCode || body.Error == "" {
+				t.Errorf("got %d %+v, want %d with code %s and a message", w.Code, body, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestErrorCodeFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorCode
+	}{
+		{nil, ""},
+		{errors.New("something odd"), ""},
+		{gorm.ErrRecordNotFound, ErrCodeNotFound},
+		{fmt.Errorf("open order: %w", trader.ErrInsufficientMargin), ErrCodeInsufficientMargin},
+		{trader.ErrSignalQueueFull, ErrCodeRateLimited},
+		{fmt.Errorf("%w: cool-off", trader.ErrRiskControlPaused), ErrCodeTradingPaused},
+		{errBacktestForbidden, ErrCodeForbidden},
+	}
+	for _, tt := range tests {
+		if got := ErrorCodeFor(tt.err); got != tt.want {
+			t.Errorf("ErrorCodeFor(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exchangeErrorRule maps exchange error fragments (codes or messages) to a safe, user-facing reason
+type exchangeErrorRule struct {
+	code      string
+	message   string
+	fragments []string
+}
+
+// exchangeErrorRules ordered most specific first, matched case-insensitively against the raw error
+var exchangeErrorRules = []exchangeErrorRule{
+	{
+		code:      "ip_not_whitelisted",
+		message:   "Request IP is not whitelisted for this API key",
+		fragments: []string{"whitelist", "ip not allowed", "unmatched ip", "10010", "50110", "40018"},
+	},
+	{
+		code:      "invalid_passphrase",
+		message:   "API passphrase is incorrect",
+		fragments: []string{"passphrase", "50105", "40012"},
+	},
+	{
+		code:      "invalid_signature",
+		message:   "Signature check failed, the API secret is probably wrong",
+		fragments: []string{"signature", "invalid sign", "error sign", "-1022", "10004", "50113", "40009"},
+	},
+	{
+		code:      "timestamp_out_of_range",
+		message:   "Request timestamp rejected, check the server clock",
+		fragments: []string{"timestamp", "recv_window", "recvwindow", "-1021", "10002", "50102"},
+	},
+	{
+		code:      "permission_denied",
+		message:   "API key lacks the required permissions (futures trading / read)",
+		fragments: []string{"permission", "not authorized", "-2015", "10005", "50120"},
+	},
+	{
+		code:      "invalid_api_key",
+		message:   "API key is invalid or has been deleted",
+		fragments: []string{"api-key", "api key", "apikey", "-2014", "-2008", "10003", "50111", "40006", "40037", "unauthorized", "401"},
+	},
+	{
+		code:      "network_error",
+		message:   "Could not reach the exchange (network error or timeout)",
+		fragments: []string{"timeout", "deadline exceeded", "connection refused", "connection reset", "no such host", "dial tcp", "eof"},
+	},
+}
+
+// classifyExchangeError turns a raw exchange error into a stable code and a message safe to show users.
+// Raw errors may contain request details, so they are never returned to the client.
+func classifyExchangeError(err error) (string, string) {
+	if err == nil {
+		return "", ""
+	}
+	raw := strings.ToLower(err.Error())
+	for _, rule := range exchangeErrorRules {
+		for _, fragment := range rule.fragments {
+			if strings.Contains(raw, fragment) {
+				return rule.code, rule.message
+			}
+		}
+	}
+	return "exchange_error", "Exchange rejected the request, check the API credentials and account type"
+}
+
+// extractTotalEquity returns total account value from a GetBalance result
+// Priority: total_equity > totalWalletBalance > wallet_balance > totalEq > balance
+func extractTotalEquity(balanceInfo map[string]interface{}) float64 {
+	for _, key := range []string{"total_equity", "totalWalletBalance", "wallet_balance", "totalEq", "balance"} {
+		if balance, ok := balanceInfo[key].(float64); ok && balance > 0 {
+			return balance
+		}
+	}
+	return 0
+}
+
+// handleTestExchange checks stored exchange credentials by querying the account balance
+func (s *Server) handleTestExchange(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+
+	exchangeCfg, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil || exchangeCfg == nil {
+		SafeNotFound(c, "Exchange")
+		return
+	}
+
+	s.respondExchangeTest(c, exchangeID, exchangeCfg)
+}
+
+// respondExchangeTest queries the account balance with exchangeCfg and writes the test result
+// (exchangeID is empty for credentials that haven't been saved yet)
+func (s *Server) respondExchangeTest(c *gin.Context, exchangeID string, exchangeCfg *store.Exchange) {
+	label := exchangeID
+	if label == "" {
+		label = "(unsaved)"
+	}
+
+	tempTrader, err := trader.NewTraderFromExchangeConfig(exchangeCfg, c.GetString("user_id"))
+	if err != nil {
+		logger.Warnf("⚠️ Exchange test %s (%s): failed to create client: %v", label, exchangeCfg.ExchangeType, err)
+		code, message := classifyExchangeError(err)
+		c.JSON(http.StatusOK, gin.H{
+			"success":       false,
+			"exchange_id":   exchangeID,
+			"exchange_type": exchangeCfg.ExchangeType,
+			"error_code":    code,
+			"error":         message,
+		})
+		return
+	}
+
+	start := time.Now()
+	balanceInfo, err := tempTrader.GetBalance()
+	latencyMs := time.Since(start).Milliseconds()
+	if err != nil {
+		code, message := classifyExchangeError(err)
+		logger.Warnf("⚠️ Exchange test %s (%s) failed [%s]: %v", label, exchangeCfg.ExchangeType, code, err)
+		c.JSON(http.StatusOK, gin.H{
+			"success":       false,
+			"exchange_id":   exchangeID,
+			"exchange_type": exchangeCfg.ExchangeType,
+			"latency_ms":    latencyMs,
+			"error_code":    code,
+			"error":         message,
+		})
+		return
+	}
+
+	available, _ := balanceInfo["availableBalance"].(float64)
+	logger.Infof("✓ Exchange test %s (%s) succeeded in %dms", label, exchangeCfg.ExchangeType, latencyMs)
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"exchange_id":       exchangeID,
+		"exchange_type":     exchangeCfg.ExchangeType,
+		"testnet":           exchangeCfg.Testnet,
+		"latency_ms":        latencyMs,
+		"total_equity":      extractTotalEquity(balanceInfo),
+		"available_balance": available,
+	})
+}
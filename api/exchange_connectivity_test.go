@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyExchangeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "binance invalid key", err: errors.New("<APIError> code=-2014, msg=API-key format invalid."), wantCode: "invalid_api_key"},
+		{name: "binance ip or permissions", err: errors.New("<APIError> code=-2015, msg=Invalid API-key, IP, or permissions for action."), wantCode: "permission_denied"},
+		{name: "binance timestamp", err: errors.New("<APIError> code=-1021, msg=Timestamp for this request is outside of the recvWindow."), wantCode: "timestamp_out_of_range"},
+		{name: "bybit unmatched ip", err: errors.New("retCode=10010 retMsg=Unmatched IP, please check your API key's bound IP addresses."), wantCode: "ip_not_whitelisted"},
+		{name: "okx passphrase", err: errors.New("OKX API error: code=50105, msg=Passphrase incorrect"), wantCode: "invalid_passphrase"},
+		{name: "signature", err: errors.New("code=-1022, msg=Signature for this request is not valid."), wantCode: "invalid_signature"},
+		{name: "network", err: errors.New("Get \"https://fapi.binance.com\": dial tcp: lookup fapi.binance.com: no such host"), wantCode: "network_error"},
+		{name: "unknown", err: errors.New("something odd happened"), wantCode: "exchange_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, message := classifyExchangeError(tt.err)
+			if code != tt.wantCode {
+				t.Errorf("classifyExchangeError() code = %q, want %q", code, tt.wantCode)
+			}
+			if message == "" || message == tt.err.Error() {
+				t.Errorf("classifyExchangeError() should return a sanitized message, got %q", message)
+			}
+		})
+	}
+}
+
+func TestExtractTotalEquity(t *testing.T) {
+	if got := extractTotalEquity(map[string]interface{}{"totalWalletBalance": 120.5, "balance": 99.0}); got != 120.5 {
+		t.Errorf("extractTotalEquity() = %v, want 120.5", got)
+	}
+	if got := extractTotalEquity(map[string]interface{}{"total_equity": 0.0, "totalEq": 42.0}); got != 42 {
+		t.Errorf("extractTotalEquity() = %v, want 42", got)
+	}
+	if got := extractTotalEquity(map[string]interface{}{"availableBalance": 10.0}); got != 0 {
+		t.Errorf("extractTotalEquity() = %v, want 0", got)
+	}
+}
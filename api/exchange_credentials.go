@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"nofx/config"
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindSensitiveJSON decodes a request body carrying credentials: plain JSON, or an encrypted
+// payload when transport encryption is enabled. Writes a 400 and returns false on failure.
+func (s *Server) bindSensitiveJSON(c *gin.Context, req interface{}) bool {
+	userID := c.GetString("user_id")
+
+	bodyBytes, err := c.GetRawData()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Failed to read request body")
+		return false
+	}
+
+	if !config.Get().TransportEncryption {
+		if err := json.Unmarshal(bodyBytes, req); err != nil {
+			logger.Infof("❌ Failed to parse plain JSON request: %v", err)
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request format")
+			return false
+		}
+		return true
+	}
+
+	var encryptedPayload crypto.EncryptedPayload
+	if err := json.Unmarshal(bodyBytes, &encryptedPayload); err != nil {
+		logger.Infof("❌ Failed to parse encrypted payload: %v", err)
+		respondError(c, http.StatusBadRequest, ErrCodeEncryptionRequired, "Invalid request format, encrypted transmission required")
+		return false
+	}
+	if encryptedPayload.WrappedKey == "" {
+		logger.Infof("❌ Detected unencrypted request (UserID: %s)", userID)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "This endpoint only supports encrypted transmission, please use encrypted client",
+			"code":    ErrCodeEncryptionRequired,
+			"message": "Encrypted transmission is required for security reasons",
+		})
+		return false
+	}
+
+	decrypted, err := s.cryptoHandler.cryptoService.DecryptSensitiveData(&encryptedPayload)
+	if err != nil {
+		logger.Infof("❌ Failed to decrypt request (UserID: %s): %v", userID, err)
+		respondError(c, http.StatusBadRequest, ErrCodeDecryptionFailed, "Failed to decrypt data")
+		return false
+	}
+	if err := json.Unmarshal([]byte(decrypted), req); err != nil {
+		logger.Infof("❌ Failed to parse decrypted data: %v", err)
+		respondError(c, http.StatusBadRequest, ErrCodeDecryptionFailed, "Failed to parse decrypted data")
+		return false
+	}
+	return true
+}
+
+// exchangeFromRequest builds an unsaved exchange config from create-style request credentials
+func exchangeFromRequest(req *CreateExchangeRequest) *store.Exchange {
+	return &store.Exchange{
+		ExchangeType:            req.ExchangeType,
+		AccountName:             req.AccountName,
+		Enabled:                 true,
+		APIKey:                  crypto.EncryptedString(req.APIKey),
+		SecretKey:               crypto.EncryptedString(req.SecretKey),
+		Passphrase:              crypto.EncryptedString(req.Passphrase),
+		Testnet:                 req.Testnet,
+		HyperliquidWalletAddr:   req.HyperliquidWalletAddr,
+		AsterUser:               req.AsterUser,
+		AsterSigner:             req.AsterSigner,
+		AsterPrivateKey:         crypto.EncryptedString(req.AsterPrivateKey),
+		LighterWalletAddr:       req.LighterWalletAddr,
+		LighterPrivateKey:       crypto.EncryptedString(req.LighterPrivateKey),
+		LighterAPIKeyPrivateKey: crypto.EncryptedString(req.LighterAPIKeyPrivateKey),
+		LighterAPIKeyIndex:      req.LighterAPIKeyIndex,
+	}
+}
+
+// handleTestExchangeCredentials checks exchange credentials before they are saved: builds a temporary
+// client, reads the account balance and reports the result. Nothing is persisted.
+func (s *Server) handleTestExchangeCredentials(c *gin.Context) {
+	var req CreateExchangeRequest
+	if !s.bindSensitiveJSON(c, &req) {
+		return
+	}
+	if !trader.IsSupportedExchange(req.ExchangeType) {
+		SafeBadRequest(c, "Invalid exchange type: "+req.ExchangeType)
+		return
+	}
+
+	s.respondExchangeTest(c, "", exchangeFromRequest(&req))
+}
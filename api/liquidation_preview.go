@@ -0,0 +1,97 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nofx/logger"
+	"nofx/market"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleLiquidationPreview Estimated liquidation price of a hypothetical trade at the current mark price
+// GET /api/liquidation-preview?exchange_id=xxx&symbol=BTCUSDT&side=LONG&size_usd=100&leverage=10
+func (s *Server) handleLiquidationPreview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Query("exchange_id")
+	symbol := market.Normalize(c.Query("symbol"))
+	side := strings.ToUpper(c.Query("side"))
+	sizeUSD, sizeErr := strconv.ParseFloat(c.Query("size_usd"), 64)
+	leverage, levErr := strconv.Atoi(c.Query("leverage"))
+
+	switch {
+	case exchangeID == "" || c.Query("symbol") == "":
+		SafeBadRequest(c, "exchange_id and symbol are required")
+		return
+	case side != "LONG" && side != "SHORT":
+		SafeBadRequest(c, "side must be LONG or SHORT")
+		return
+	case sizeErr != nil || sizeUSD <= 0:
+		SafeBadRequest(c, "size_usd must be a positive number")
+		return
+	case levErr != nil || leverage < 1:
+		SafeBadRequest(c, "leverage must be a positive integer")
+		return
+	}
+
+	exchangeCfg, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil || exchangeCfg == nil {
+		SafeNotFound(c, "Exchange")
+		return
+	}
+
+	tempTrader, err := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if err != nil {
+		SafeInternalError(c, "Failed to connect to exchange", err)
+		return
+	}
+
+	markPrice, err := tempTrader.GetMarketPrice(symbol)
+	if err != nil || markPrice <= 0 {
+		SafeInternalError(c, "Failed to get market price", err)
+		return
+	}
+
+	var info *trader.SymbolInfo
+	if provider, ok := tempTrader.(trader.SymbolInfoProvider); ok {
+		if info, err = provider.GetSymbolInfo(symbol); err != nil {
+			logger.Warnf("⚠️ Liquidation preview: no symbol info for %s on %s, using default maintenance margin: %v",
+				symbol, exchangeCfg.ExchangeType, err)
+			info = nil
+		}
+	}
+
+	quantity := sizeUSD / markPrice
+	tier := trader.MaintenanceTierFor(info, sizeUSD)
+	liqPrice := trader.EstimateLiquidationPrice(side, markPrice, quantity, leverage, tier)
+
+	result := gin.H{
+		"exchange_id":         exchangeID,
+		"exchange_type":       exchangeCfg.ExchangeType,
+		"symbol":              symbol,
+		"side":                side,
+		"size_usd":            sizeUSD,
+		"leverage":            leverage,
+		"mark_price":          markPrice,
+		"quantity":            quantity,
+		"initial_margin":      sizeUSD / float64(leverage),
+		"maint_margin_ratio":  tier.MaintMarginRatio,
+		"maint_amount":        tier.MaintAmount,
+		"tiers_from_exchange": info != nil && len(info.MaintenanceTiers) > 0,
+		"liquidation_price":   liqPrice,
+	}
+	if liqPrice > 0 {
+		result["distance_pct"] = math.Abs(markPrice-liqPrice) / markPrice * 100
+	}
+	if info != nil && info.MaxLeverage > 0 {
+		result["max_leverage"] = info.MaxLeverage
+		if leverage > info.MaxLeverage {
+			result["warning"] = "Leverage exceeds the exchange maximum for this symbol"
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
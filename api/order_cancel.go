@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleCancelTraderOrder Cancel a single pending order on the trader's exchange
+// DELETE /api/traders/:id/orders/:exchangeOrderId?symbol=BTCUSDT
+func (s *Server) handleCancelTraderOrder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+	orderID := c.Param("exchangeOrderId")
+	if c.Query("symbol") == "" {
+		SafeBadRequest(c, "symbol parameter is required")
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+	exchangeCfg := fullConfig.Exchange
+	if exchangeCfg == nil || !exchangeCfg.Enabled {
+		respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
+		return
+	}
+	symbol := market.NormalizeForExchange(c.Query("symbol"), exchangeCfg.ExchangeType, "")
+
+	tempTrader, err := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if errors.Is(err, trader.ErrUnsupportedExchange) {
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, "Unsupported exchange type")
+		return
+	}
+	if err != nil {
+		SafeInternalError(c, "Failed to connect to exchange", err)
+		return
+	}
+
+	// Look the order up before cancelling so it can be recorded with its details
+	// (not all exchanges list every order type, so a miss is not an error)
+	var pending *trader.OpenOrder
+	if openOrders, err := tempTrader.GetOpenOrders(symbol); err != nil {
+		logger.Warnf("⚠️ Cancel order: failed to get open orders for %s: %v", symbol, err)
+	} else {
+		for i := range openOrders {
+			if openOrders[i].OrderID == orderID {
+				pending = &openOrders[i]
+				break
+			}
+		}
+	}
+
+	logger.Infof("🗑️ User %s requested order cancel: trader=%s, symbol=%s, order=%s", userID, traderID, symbol, orderID)
+	if err := tempTrader.CancelOrder(symbol, orderID); err != nil {
+		logger.Infof("❌ Cancel order failed: symbol=%s, order=%s, error=%v", symbol, orderID, err)
+		SafeInternalError(c, "Failed to cancel order", err)
+		return
+	}
+	logger.Infof("✅ Order canceled: symbol=%s, order=%s", symbol, orderID)
+
+	recorded := s.recordCanceledOrder(traderID, exchangeCfg, symbol, orderID, pending)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Order canceled successfully",
+		"trader_id":         traderID,
+		"symbol":            symbol,
+		"exchange_order_id": orderID,
+		"order":             pending,
+		"recorded":          recorded,
+	})
+}
+
+// recordCanceledOrder marks a cancelled order as CANCELED in the order history, creating the
+// record from the exchange order details when it was never stored. Returns whether it was recorded.
+func (s *Server) recordCanceledOrder(traderID string, exchangeCfg *store.Exchange, symbol, orderID string, pending *trader.OpenOrder) bool {
+	existing, err := s.store.Order().GetOrderByExchangeID(exchangeCfg.ID, orderID)
+	if err != nil {
+		logger.Infof("  ⚠️ Failed to look up canceled order %s: %v", orderID, err)
+		return false
+	}
+	if existing != nil {
+		if err := s.store.Order().UpdateOrderStatus(existing.ID, "CANCELED", existing.FilledQuantity, existing.AvgFillPrice, existing.Commission); err != nil {
+			logger.Infof("  ⚠️ Failed to mark order %s as canceled: %v", orderID, err)
+			return false
+		}
+		return true
+	}
+
+	if pending == nil {
+		logger.Infof("  ⚠️ Canceled order %s not found in history or open orders, not recorded", orderID)
+		return false
+	}
+
+	now := time.Now().UTC().UnixMilli()
+	order := &store.TraderOrder{
+		TraderID:        traderID,
+		ExchangeID:      exchangeCfg.ID,
+		ExchangeType:    exchangeCfg.ExchangeType,
+		ExchangeOrderID: orderID,
+		Symbol:          symbol,
+		Side:            pending.Side,
+		PositionSide:    pending.PositionSide,
+		Type:            pending.Type,
+		Quantity:        pending.Quantity,
+		Price:           pending.Price,
+		StopPrice:       pending.StopPrice,
+		Status:          "CANCELED",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.store.Order().CreateOrder(order); err != nil {
+		logger.Infof("  ⚠️ Failed to record canceled order %s: %v", orderID, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPortfolioHours = 7 * 24
+	maxPortfolioHours     = 90 * 24
+	// portfolioCurvePoints target number of points of the combined curve when the bucket is automatic
+	portfolioCurvePoints = 500
+	minPortfolioBucket   = 5 * time.Minute
+)
+
+// portfolioCurveBucket returns the curve bucket for a window: the requested size, or one that
+// yields about portfolioCurvePoints points (rounded to whole minutes, at least 5 minutes)
+func portfolioCurveBucket(window time.Duration, bucketMinutes int) time.Duration {
+	if bucketMinutes > 0 {
+		return time.Duration(bucketMinutes) * time.Minute
+	}
+	bucket := (window / portfolioCurvePoints).Round(time.Minute)
+	if bucket < minPortfolioBucket {
+		bucket = minPortfolioBucket
+	}
+	return bucket
+}
+
+// handlePortfolio Aggregated view across all of the user's traders
+// Query params: hours (equity curve window, default 168, max 2160), bucket_minutes (curve resolution, default auto)
+func (s *Server) handlePortfolio(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	hours := defaultPortfolioHours
+	if v := c.Query("hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "hours must be a positive integer")
+			return
+		}
+		hours = min(n, maxPortfolioHours)
+	}
+	bucketMinutes := 0
+	if v := c.Query("bucket_minutes"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "bucket_minutes must be a positive integer")
+			return
+		}
+		bucketMinutes = n
+	}
+
+	window := time.Duration(hours) * time.Hour
+	portfolio, err := s.store.GetUserPortfolio(userID, time.Now().Add(-window), portfolioCurveBucket(window, bucketMinutes))
+	if err != nil {
+		SafeInternalError(c, "Get portfolio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// portfolioPosition an open position of one of the user's traders
+type portfolioPosition struct {
+	TraderID      string  `json:"trader_id"`
+	TraderName    string  `json:"trader_name"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"` // long / short
+	Quantity      float64 `json:"quantity"`
+	EntryPrice    float64 `json:"entry_price"`
+	MarkPrice     float64 `json:"mark_price"` // Entry price for positions loaded from the DB
+	Leverage      int     `json:"leverage"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	Notional      float64 `json:"notional"`
+	Source        string  `json:"source"` // "live" (running trader) or "db" (recorded open position)
+}
+
+// symbolExposure combined exposure to one symbol across traders
+type symbolExposure struct {
+	Symbol        string   `json:"symbol"`
+	LongNotional  float64  `json:"long_notional"`
+	ShortNotional float64  `json:"short_notional"`
+	NetNotional   float64  `json:"net_notional"` // Long - short
+	NetSide       string   `json:"net_side"`     // long / short / flat
+	GrossPct      float64  `json:"gross_pct"`    // Share of the gross exposure of all symbols (%)
+	Positions     int      `json:"positions"`
+	TraderIDs     []string `json:"trader_ids"`
+}
+
+// aggregateSymbolExposure combines positions per symbol, largest gross exposure first
+func aggregateSymbolExposure(positions []portfolioPosition) []symbolExposure {
+	bySymbol := make(map[string]*symbolExposure)
+	traders := make(map[string]map[string]bool)
+	totalGross := 0.0
+	for _, pos := range positions {
+		symbol := market.Normalize(pos.Symbol)
+		exp, ok := bySymbol[symbol]
+		if !ok {
+			exp = &symbolExposure{Symbol: symbol}
+			bySymbol[symbol] = exp
+			traders[symbol] = make(map[string]bool)
+		}
+		if pos.Side == "short" {
+			exp.ShortNotional += pos.Notional
+		} else {
+			exp.LongNotional += pos.Notional
+		}
+		exp.Positions++
+		if !traders[symbol][pos.TraderID] {
+			traders[symbol][pos.TraderID] = true
+			exp.TraderIDs = append(exp.TraderIDs, pos.TraderID)
+		}
+		totalGross += pos.Notional
+	}
+
+	result := make([]symbolExposure, 0, len(bySymbol))
+	for _, exp := range bySymbol {
+		exp.NetNotional = exp.LongNotional - exp.ShortNotional
+		switch {
+		case math.Abs(exp.NetNotional) < 1e-9:
+			exp.NetSide = "flat"
+		case exp.NetNotional > 0:
+			exp.NetSide = "long"
+		default:
+			exp.NetSide = "short"
+		}
+		if totalGross > 0 {
+			exp.GrossPct = (exp.LongNotional + exp.ShortNotional) / totalGross * 100
+		}
+		sort.Strings(exp.TraderIDs)
+		result = append(result, *exp)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		gi := result[i].LongNotional + result[i].ShortNotional
+		gj := result[j].LongNotional + result[j].ShortNotional
+		if gi != gj {
+			return gi > gj
+		}
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result
+}
+
+// livePortfolioPositions converts a running trader's positions (AutoTrader.GetPositions format)
+func livePortfolioPositions(trader *store.Trader, positions []map[string]interface{}) []portfolioPosition {
+	result := make([]portfolioPosition, 0, len(positions))
+	for _, p := range positions {
+		pos := portfolioPosition{TraderID: trader.ID, TraderName: trader.Name, Source: "live"}
+		pos.Symbol, _ = p["symbol"].(string)
+		pos.Side, _ = p["side"].(string)
+		pos.Side = strings.ToLower(pos.Side)
+		pos.Quantity, _ = p["quantity"].(float64)
+		pos.EntryPrice, _ = p["entry_price"].(float64)
+		pos.MarkPrice, _ = p["mark_price"].(float64)
+		pos.Leverage, _ = p["leverage"].(int)
+		pos.UnrealizedPnL, _ = p["unrealized_pnl"].(float64)
+		pos.Notional = pos.Quantity * pos.MarkPrice
+		result = append(result, pos)
+	}
+	return result
+}
+
+// storedPortfolioPositions converts a trader's recorded open positions (no live mark price)
+func storedPortfolioPositions(trader *store.Trader, positions []*store.TraderPosition) []portfolioPosition {
+	result := make([]portfolioPosition, 0, len(positions))
+	for _, p := range positions {
+		result = append(result, portfolioPosition{
+			TraderID:   trader.ID,
+			TraderName: trader.Name,
+			Symbol:     p.Symbol,
+			Side:       strings.ToLower(p.Side),
+			Quantity:   p.Quantity,
+			EntryPrice: p.EntryPrice,
+			MarkPrice:  p.EntryPrice,
+			Leverage:   p.Leverage,
+			Notional:   p.Quantity * p.EntryPrice,
+			Source:     "db",
+		})
+	}
+	return result
+}
+
+// handlePortfolioPositions Open positions across all of the user's traders with combined per-symbol exposure.
+// Traders loaded in memory are queried live, the others fall back to their recorded open positions.
+func (s *Server) handlePortfolioPositions(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	traders, err := s.store.Trader().List(userID)
+	if err != nil {
+		SafeInternalError(c, "Failed to get trader list", err)
+		return
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		positions = make([]portfolioPosition, 0)
+		errs      = make(map[string]string)
+	)
+	for _, t := range traders {
+		wg.Add(1)
+		go func(t *store.Trader) {
+			defer wg.Done()
+
+			var found []portfolioPosition
+			var loadErr error
+			if at, err := s.traderManager.GetTrader(t.ID); err == nil {
+				var live []map[string]interface{}
+				if live, loadErr = at.GetPositions(); loadErr == nil {
+					found = livePortfolioPositions(t, live)
+				}
+			}
+			if found == nil {
+				var stored []*store.TraderPosition
+				if stored, err = s.store.Position().GetOpenPositions(t.ID); err == nil {
+					found = storedPortfolioPositions(t, stored)
+					if loadErr != nil {
+						loadErr = fmt.Errorf("live positions unavailable, using recorded positions: %w", loadErr)
+					}
+				} else if loadErr == nil {
+					loadErr = err
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if loadErr != nil {
+				errs[t.ID] = loadErr.Error()
+			}
+			positions = append(positions, found...)
+		}(t)
+	}
+	wg.Wait()
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Notional != positions[j].Notional {
+			return positions[i].Notional > positions[j].Notional
+		}
+		return positions[i].TraderID < positions[j].TraderID
+	})
+
+	var totalLong, totalShort float64
+	for _, pos := range positions {
+		if pos.Side == "short" {
+			totalShort += pos.Notional
+		} else {
+			totalLong += pos.Notional
+		}
+	}
+
+	response := gin.H{
+		"positions":      positions,
+		"exposure":       aggregateSymbolExposure(positions),
+		"total_long":     totalLong,
+		"total_short":    totalShort,
+		"net_exposure":   totalLong - totalShort,
+		"gross_exposure": totalLong + totalShort,
+	}
+	if len(errs) > 0 {
+		response["errors"] = errs
+	}
+	c.JSON(http.StatusOK, response)
+}
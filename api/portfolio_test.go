@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPortfolioCurveBucket(t *testing.T) {
+	tests := []struct {
+		name          string
+		window        time.Duration
+		bucketMinutes int
+		want          time.Duration
+	}{
+		{"explicit bucket", 7 * 24 * time.Hour, 60, time.Hour},
+		{"auto for one week", 7 * 24 * time.Hour, 0, 20 * time.Minute},
+		{"auto never below minimum", 24 * time.Hour, 0, 5 * time.Minute},
+		{"auto for 90 days", 90 * 24 * time.Hour, 0, 259 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := portfolioCurveBucket(tt.window, tt.bucketMinutes); got != tt.want {
+				t.Errorf("portfolioCurveBucket(%v, %d) = %v, want %v", tt.window, tt.bucketMinutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateSymbolExposure(t *testing.T) {
+	positions := []portfolioPosition{
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "long", Notional: 3000},
+		{TraderID: "t2", Symbol: "btc", Side: "short", Notional: 1000},
+		{TraderID: "t2", Symbol: "ETHUSDT", Side: "short", Notional: 500},
+		{TraderID: "t3", Symbol: "SOLUSDT", Side: "long", Notional: 250},
+		{TraderID: "t1", Symbol: "SOLUSDT", Side: "short", Notional: 250},
+	}
+
+	exposure := aggregateSymbolExposure(positions)
+	if len(exposure) != 3 {
+		t.Fatalf("got %d symbols, want 3: %+v", len(exposure), exposure)
+	}
+
+	btc := exposure[0]
+	if btc.Symbol != "BTCUSDT" || btc.NetNotional != 2000 || btc.NetSide != "long" || btc.Positions != 2 {
+		t.Errorf("BTC exposure = %+v", btc)
+	}
+	if len(btc.TraderIDs) != 2 || btc.TraderIDs[0] != "t1" || btc.TraderIDs[1] != "t2" {
+		t.Errorf("BTC traders = %v, want [t1 t2]", btc.TraderIDs)
+	}
+	if btc.GrossPct != 80 {
+		t.Errorf("BTC gross share = %.2f%%, want 80%%", btc.GrossPct)
+	}
+	if eth := exposure[1]; eth.Symbol != "ETHUSDT" || eth.NetSide != "short" {
+		t.Errorf("ETH exposure = %+v", eth)
+	}
+	if sol := exposure[2]; sol.NetSide != "flat" {
+		t.Errorf("SOL long and short should net flat, got %+v", sol)
+	}
+}
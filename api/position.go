@@ -0,0 +1,367 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validateSLTPLevels checks stop-loss/take-profit are on the correct side of mark price
+// LONG: stopLoss < mark < takeProfit, SHORT: takeProfit < mark < stopLoss (0 = not set)
+func validateSLTPLevels(side string, markPrice, stopLoss, takeProfit float64) error {
+	if stopLoss < 0 || takeProfit < 0 {
+		return fmt.Errorf("stop loss and take profit must be positive")
+	}
+	if stopLoss == 0 && takeProfit == 0 {
+		return fmt.Errorf("at least one of stopLoss or takeProfit is required")
+	}
+	if markPrice <= 0 {
+		return fmt.Errorf("invalid mark price: %.4f", markPrice)
+	}
+
+	switch side {
+	case "LONG":
+		if stopLoss > 0 && stopLoss >= markPrice {
+			return fmt.Errorf("long stop loss (%.4f) must be below mark price (%.4f)", stopLoss, markPrice)
+		}
+		if takeProfit > 0 && takeProfit <= markPrice {
+			return fmt.Errorf("long take profit (%.4f) must be above mark price (%.4f)", takeProfit, markPrice)
+		}
+	case "SHORT":
+		if stopLoss > 0 && stopLoss <= markPrice {
+			return fmt.Errorf("short stop loss (%.4f) must be above mark price (%.4f)", stopLoss, markPrice)
+		}
+		if takeProfit > 0 && takeProfit >= markPrice {
+			return fmt.Errorf("short take profit (%.4f) must be below mark price (%.4f)", takeProfit, markPrice)
+		}
+	default:
+		return fmt.Errorf("side must be LONG or SHORT")
+	}
+	return nil
+}
+
+// handleSetSLTP Manually adjust stop-loss/take-profit of an open position
+func (s *Server) handleSetSLTP(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		Symbol     string  `json:"symbol" binding:"required"`
+		Side       string  `json:"side" binding:"required"` // "LONG" or "SHORT"
+		StopLoss   float64 `json:"stopLoss"`
+		TakeProfit float64 `json:"takeProfit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Parameter error: symbol and side are required")
+		return
+	}
+	req.Side = strings.ToUpper(req.Side)
+	if req.Side != "LONG" && req.Side != "SHORT" {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "side must be LONG or SHORT")
+		return
+	}
+	if req.StopLoss <= 0 && req.TakeProfit <= 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "At least one of stopLoss or takeProfit is required")
+		return
+	}
+
+	logger.Infof("🎯 User %s requested SL/TP update: trader=%s, symbol=%s, side=%s, SL=%.4f, TP=%.4f",
+		userID, traderID, req.Symbol, req.Side, req.StopLoss, req.TakeProfit)
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+
+	exchangeCfg := fullConfig.Exchange
+	if exchangeCfg == nil || !exchangeCfg.Enabled {
+		respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
+		return
+	}
+
+	tempTrader, err := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if err != nil {
+		logger.Infof("⚠️ Failed to create temporary trader: %v", err)
+		SafeInternalError(c, "Failed to connect to exchange", err)
+		return
+	}
+
+	// Find the position to protect
+	positions, err := tempTrader.GetPositions()
+	if err != nil {
+		SafeInternalError(c, "Failed to get positions", err)
+		return
+	}
+
+	var posQty, markPrice float64
+	for _, pos := range positions {
+		if pos["symbol"] == req.Symbol && pos["side"] == strings.ToLower(req.Side) {
+			if amt, ok := pos["positionAmt"].(float64); ok {
+				posQty = amt
+				if posQty < 0 {
+					posQty = -posQty
+				}
+			}
+			if price, ok := pos["markPrice"].(float64); ok {
+				markPrice = price
+			}
+			break
+		}
+	}
+	if posQty == 0 {
+		respondError(c, http.StatusNotFound, ErrCodePositionNotFound, fmt.Sprintf("No %s position found for %s", req.Side, req.Symbol))
+		return
+	}
+
+	if markPrice <= 0 {
+		markPrice, err = tempTrader.GetMarketPrice(req.Symbol)
+		if err != nil {
+			SafeInternalError(c, "Failed to get market price", err)
+			return
+		}
+	}
+
+	if err := validateSLTPLevels(req.Side, markPrice, req.StopLoss, req.TakeProfit); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	// Replace only the protection being changed (keep the other side untouched)
+	if req.StopLoss > 0 {
+		if err := tempTrader.CancelStopLossOrders(req.Symbol); err != nil {
+			logger.Infof("  ⚠️ Failed to cancel existing stop loss orders: %v", err)
+		}
+		if err := tempTrader.SetStopLoss(req.Symbol, req.Side, posQty, req.StopLoss); err != nil {
+			logger.Infof("❌ Set stop loss failed: symbol=%s, side=%s, error=%v", req.Symbol, req.Side, err)
+			SafeInternalError(c, "Failed to set stop loss", err)
+			return
+		}
+	}
+	if req.TakeProfit > 0 {
+		if err := tempTrader.CancelTakeProfitOrders(req.Symbol); err != nil {
+			logger.Infof("  ⚠️ Failed to cancel existing take profit orders: %v", err)
+		}
+		if err := tempTrader.SetTakeProfit(req.Symbol, req.Side, posQty, req.TakeProfit); err != nil {
+			logger.Infof("❌ Set take profit failed: symbol=%s, side=%s, error=%v", req.Symbol, req.Side, err)
+			SafeInternalError(c, "Failed to set take profit", err)
+			return
+		}
+	}
+
+	logger.Infof("✅ SL/TP updated: symbol=%s, side=%s, qty=%.6f, mark=%.4f", req.Symbol, req.Side, posQty, markPrice)
+
+	// Read back resulting protection orders and record them
+	orders, err := tempTrader.GetOpenOrders(req.Symbol)
+	if err != nil {
+		logger.Infof("  ⚠️ Failed to get open orders after SL/TP update: %v", err)
+		orders = []trader.OpenOrder{}
+	}
+	s.recordProtectionOrders(traderID, exchangeCfg.ID, exchangeCfg.ExchangeType, req.Side, orders)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Stop loss / take profit updated",
+		"symbol":      req.Symbol,
+		"side":        req.Side,
+		"quantity":    posQty,
+		"mark_price":  markPrice,
+		"stop_loss":   req.StopLoss,
+		"take_profit": req.TakeProfit,
+		"orders":      orders,
+	})
+}
+
+// handleRebalancePosition Change leverage and/or isolated margin of an open position without closing it
+// POST /api/traders/:id/rebalance {symbol, side, leverage?, margin_delta?}
+func (s *Server) handleRebalancePosition(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		Symbol      string  `json:"symbol" binding:"required"`
+		Side        string  `json:"side" binding:"required"` // "LONG" or "SHORT"
+		Leverage    int     `json:"leverage"`                // New leverage, 0 = unchanged
+		MarginDelta float64 `json:"margin_delta"`            // Isolated margin to add (> 0) or remove (< 0)
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Parameter error: symbol and side are required")
+		return
+	}
+	rebalance := trader.RebalanceRequest{
+		Symbol:      req.Symbol,
+		Side:        strings.ToUpper(req.Side),
+		Leverage:    req.Leverage,
+		MarginDelta: req.MarginDelta,
+	}
+	if err := rebalance.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	logger.Infof("⚖️ User %s requested rebalance: trader=%s, symbol=%s, side=%s, leverage=%d, margin_delta=%.4f",
+		userID, traderID, rebalance.Symbol, rebalance.Side, rebalance.Leverage, rebalance.MarginDelta)
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+
+	exchangeCfg := fullConfig.Exchange
+	if exchangeCfg == nil || !exchangeCfg.Enabled {
+		respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
+		return
+	}
+
+	tempTrader, err := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if err != nil {
+		logger.Infof("⚠️ Failed to create temporary trader: %v", err)
+		SafeInternalError(c, "Failed to connect to exchange", err)
+		return
+	}
+	if _, ok := tempTrader.(trader.PositionMarginAdjuster); rebalance.MarginDelta != 0 && !ok {
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, fmt.Sprintf("%s does not support position margin adjustment", exchangeCfg.ExchangeType))
+		return
+	}
+
+	result, err := trader.RebalancePosition(tempTrader, rebalance)
+	if err != nil {
+		logger.Infof("❌ Rebalance failed: symbol=%s, side=%s, error=%v", rebalance.Symbol, rebalance.Side, err)
+		SafeInternalError(c, "Failed to rebalance position", err)
+		return
+	}
+
+	// Persist the new leverage/liquidation price on the local position record
+	if pos, err := s.store.Position().GetOpenPositionBySymbol(traderID, rebalance.Symbol, rebalance.Side); err == nil && pos != nil {
+		if err := s.store.Position().UpdateLeverage(pos.ID, result.Leverage, result.LiquidationPrice); err != nil {
+			logger.Infof("  ⚠️ Failed to record rebalanced position: %v", err)
+		}
+	}
+
+	logger.Infof("✅ Position rebalanced: symbol=%s, side=%s, leverage %dx -> %dx, liquidation %.4f -> %.4f",
+		result.Symbol, result.Side, result.PreviousLeverage, result.Leverage, result.PreviousLiquidationPrice, result.LiquidationPrice)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleOpenPosition Manually open a position through a running trader (same risk control and
+// bookkeeping as the AI's entries)
+func (s *Server) handleOpenPosition(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		Symbol     string  `json:"symbol" binding:"required"`
+		Side       string  `json:"side" binding:"required"` // "long" or "short"
+		SizeUSD    float64 `json:"size_usd" binding:"required"`
+		Leverage   int     `json:"leverage" binding:"required"`
+		StopLoss   float64 `json:"stop_loss"`   // 0 = none
+		TakeProfit float64 `json:"take_profit"` // 0 = none
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Parameter error: symbol, side, size_usd and leverage are required")
+		return
+	}
+	open := trader.ManualOpenRequest{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		SizeUSD:    req.SizeUSD,
+		Leverage:   req.Leverage,
+		StopLoss:   req.StopLoss,
+		TakeProfit: req.TakeProfit,
+	}
+	if _, err := open.Decision(); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		respondError(c, http.StatusConflict, ErrCodeTraderNotRunning, "Trader is not running")
+		return
+	}
+
+	logger.Infof("🖐 User %s requested manual open: trader=%s, symbol=%s, side=%s, size=%.2f, leverage=%d",
+		userID, traderID, req.Symbol, req.Side, req.SizeUSD, req.Leverage)
+
+	action, err := at.OpenManualPosition(open)
+	if err != nil {
+		switch {
+		case errors.Is(err, trader.ErrSignalTraderNotRunning):
+			respondError(c, http.StatusConflict, ErrCodeTraderNotRunning, "Trader is not running")
+		case errors.Is(err, trader.ErrTradingPaused), errors.Is(err, trader.ErrRiskControlPaused):
+			respondError(c, http.StatusConflict, ErrCodeTradingPaused, err.Error())
+		default:
+			// Rejected by a code-enforced limit or the exchange; the action is recorded either way
+			respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Position opened successfully",
+		"action":  action,
+	})
+}
+
+// recordProtectionOrders Record pending stop-loss/take-profit orders to database (status NEW)
+func (s *Server) recordProtectionOrders(traderID, exchangeID, exchangeType, positionSide string, orders []trader.OpenOrder) {
+	orderStore := s.store.Order()
+	now := time.Now().UTC().UnixMilli()
+
+	for _, o := range orders {
+		var orderAction string
+		switch {
+		case strings.Contains(o.Type, "TAKE_PROFIT"):
+			orderAction = "take_profit"
+		case strings.Contains(o.Type, "STOP"):
+			orderAction = "stop_loss"
+		default:
+			continue // Not a protection order
+		}
+		if o.PositionSide != "" && o.PositionSide != "BOTH" && !strings.EqualFold(o.PositionSide, positionSide) {
+			continue
+		}
+		if o.OrderID == "" {
+			continue
+		}
+		if existing, err := orderStore.GetOrderByExchangeID(exchangeID, o.OrderID); err == nil && existing != nil {
+			continue
+		}
+
+		record := &store.TraderOrder{
+			TraderID:        traderID,
+			ExchangeID:      exchangeID,
+			ExchangeType:    exchangeType,
+			ExchangeOrderID: o.OrderID,
+			Symbol:          o.Symbol,
+			Side:            o.Side,
+			PositionSide:    positionSide,
+			Type:            o.Type,
+			Quantity:        o.Quantity,
+			Price:           o.Price,
+			StopPrice:       o.StopPrice,
+			Status:          "NEW",
+			ReduceOnly:      true,
+			OrderAction:     orderAction,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := orderStore.CreateOrder(record); err != nil {
+			logger.Infof("  ⚠️ Failed to record %s order %s: %v", orderAction, o.OrderID, err)
+			continue
+		}
+		logger.Infof("  📝 Recorded %s order: id=%s, trigger=%.4f, qty=%.6f", orderAction, o.OrderID, o.StopPrice, o.Quantity)
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestValidateSLTPLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       string
+		mark       float64
+		stopLoss   float64
+		takeProfit float64
+		wantErr    bool
+	}{
+		{name: "long valid", side: "LONG", mark: 100, stopLoss: 95, takeProfit: 110},
+		{name: "long SL only", side: "LONG", mark: 100, stopLoss: 95},
+		{name: "long TP only", side: "LONG", mark: 100, takeProfit: 110},
+		{name: "long SL above mark", side: "LONG", mark: 100, stopLoss: 101, wantErr: true},
+		{name: "long TP below mark", side: "LONG", mark: 100, takeProfit: 99, wantErr: true},
+		{name: "short valid", side: "SHORT", mark: 100, stopLoss: 105, takeProfit: 90},
+		{name: "short SL below mark", side: "SHORT", mark: 100, stopLoss: 99, wantErr: true},
+		{name: "short TP above mark", side: "SHORT", mark: 100, takeProfit: 101, wantErr: true},
+		{name: "nothing set", side: "LONG", mark: 100, wantErr: true},
+		{name: "negative level", side: "LONG", mark: 100, stopLoss: -1, wantErr: true},
+		{name: "no mark price", side: "LONG", mark: 0, stopLoss: 95, wantErr: true},
+		{name: "invalid side", side: "BOTH", mark: 100, stopLoss: 95, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSLTPLevels(tt.side, tt.mark, tt.stopLoss, tt.takeProfit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSLTPLevels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/auth"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiRateLimitWindow = time.Minute
+	// apiRateLimitSweepEvery how often expired buckets are dropped from memory
+	apiRateLimitSweepEvery = 5 * time.Minute
+)
+
+// apiRateLimiter in-memory fixed-window limiter keyed by identity (user or client IP) and endpoint
+type apiRateLimiter struct {
+	mu        sync.Mutex
+	limit     int            // Default requests per window per endpoint (0 = unlimited)
+	overrides map[string]int // Per-endpoint limits by route path (0 = unlimited)
+	window    time.Duration
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+type rateBucket struct {
+	start time.Time
+	count int
+}
+
+// newAPIRateLimiter creates a limiter; returns nil when no limit is configured
+func newAPIRateLimiter(limit int, overrides map[string]int, window time.Duration) *apiRateLimiter {
+	if limit <= 0 && len(overrides) == 0 {
+		return nil
+	}
+	return &apiRateLimiter{
+		limit:     limit,
+		overrides: overrides,
+		window:    window,
+		buckets:   make(map[string]*rateBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// limitFor returns the limit of an endpoint (0 = unlimited)
+func (l *apiRateLimiter) limitFor(path string) int {
+	if n, ok := l.overrides[path]; ok {
+		return n
+	}
+	return l.limit
+}
+
+// Allow records a request at now and reports whether it's within the limit,
+// and if not, how long until the window resets
+func (l *apiRateLimiter) Allow(identity, path string, now time.Time) (bool, time.Duration) {
+	limit := l.limitFor(path)
+	if limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= apiRateLimitSweepEvery {
+		for key, b := range l.buckets {
+			if now.Sub(b.start) >= l.window {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	key := identity + " " + path
+	b, ok := l.buckets[key]
+	if !ok || now.Sub(b.start) >= l.window {
+		b = &rateBucket{start: now}
+		l.buckets[key] = b
+	}
+	if b.count >= limit {
+		return false, b.start.Add(l.window).Sub(now)
+	}
+	b.count++
+	return true, 0
+}
+
+// ParseAPIRateLimitOverrides parses per-endpoint limits "/api/path=limit,...".
+// Valid entries are returned even if others fail to parse.
+func ParseAPIRateLimitOverrides(spec string) (map[string]int, error) {
+	overrides := make(map[string]int)
+	var errs []error
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, value, ok := strings.Cut(entry, "=")
+		path = strings.TrimSpace(path)
+		if !ok || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("%q: expected /path=limit", entry))
+			continue
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			errs = append(errs, fmt.Errorf("%q: invalid limit", entry))
+			continue
+		}
+		overrides[path] = limit
+	}
+	return overrides, errors.Join(errs...)
+}
+
+// requestIdentity returns the rate limit identity of a request: the user for
+// authenticated requests (also on public routes), otherwise the client IP
+func requestIdentity(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		if claims, err := auth.ValidateJWT(token); err == nil {
+			return "user:" + claims.UserID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitMiddleware rejects requests over the per-user, per-endpoint limit with 429
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.rateLimiter == nil {
+			c.Next()
+			return
+		}
+		path := c.FullPath()
+		identity := requestIdentity(c)
+		ok, retryAfter := s.rateLimiter.Allow(identity, path, time.Now())
+		if !ok {
+			logger.Warnf("[RateLimit] %s exceeded limit on %s %s", identity, c.Request.Method, path)
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			respondError(c, http.StatusTooManyRequests, ErrCodeRateLimited, "Too many requests, please retry later")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIRateLimiterAllow(t *testing.T) {
+	l := newAPIRateLimiter(2, map[string]int{"/api/decisions": 1, "/api/health": 0}, time.Minute)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("user:a", "/api/status", now); !ok {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	ok, retryAfter := l.Allow("user:a", "/api/status", now.Add(10*time.Second))
+	if ok {
+		t.Fatal("third request within the window should be rejected")
+	}
+	if retryAfter != 50*time.Second {
+		t.Errorf("retryAfter = %v, want 50s", retryAfter)
+	}
+
+	// Limits are per identity and per endpoint
+	if ok, _ := l.Allow("user:b", "/api/status", now); !ok {
+		t.Error("other user should have its own budget")
+	}
+	if ok, _ := l.Allow("user:a", "/api/decisions", now); !ok {
+		t.Error("other endpoint should have its own budget")
+	}
+	if ok, _ := l.Allow("user:a", "/api/decisions", now); ok {
+		t.Error("override limit of 1 should reject the second request")
+	}
+	for i := 0; i < 5; i++ {
+		if ok, _ := l.Allow("user:a", "/api/health", now); !ok {
+			t.Fatal("override of 0 should mean unlimited")
+		}
+	}
+
+	// Window resets
+	if ok, _ := l.Allow("user:a", "/api/status", now.Add(time.Minute)); !ok {
+		t.Error("request in the next window should be allowed")
+	}
+}
+
+func TestNewAPIRateLimiterDisabled(t *testing.T) {
+	if l := newAPIRateLimiter(0, nil, time.Minute); l != nil {
+		t.Error("limiter without limits should be nil")
+	}
+}
+
+func TestParseAPIRateLimitOverrides(t *testing.T) {
+	got, err := ParseAPIRateLimitOverrides("/api/equity-history-batch=10, /api/decisions = 30,bad,decisions=5,/api/x=-1")
+	if err == nil {
+		t.Error("expected error for malformed entries")
+	}
+	want := map[string]int{"/api/equity-history-batch": 10, "/api/decisions": 30}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for path, limit := range want {
+		if got[path] != limit {
+			t.Errorf("%s = %d, want %d", path, got[path], limit)
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{rateLimiter: newAPIRateLimiter(1, nil, time.Minute)}
+
+	r := gin.New()
+	api := r.Group("/api", s.rateLimitMiddleware())
+	api.GET("/decisions", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	codes := make([]int, 2)
+	for i := range codes {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/decisions", nil))
+		codes[i] = w.Code
+		if i == 1 && w.Header().Get("Retry-After") == "" {
+			t.Error("429 response should set Retry-After")
+		}
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("codes = %v, want [200 429]", codes)
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"nofx/auth"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// tokenScopeKey context key of the authenticated token scope (set by authMiddleware)
+	tokenScopeKey = "token_scope"
+
+	readOnlyForbiddenMsg = "Read-only token cannot modify data or place orders"
+
+	defaultReadOnlyTokenTTL = 7 * 24 * time.Hour
+	maxReadOnlyTokenTTL     = 30 * 24 * time.Hour
+)
+
+// readOnlyAllowedWrites mutating routes a read-only token may still call
+var readOnlyAllowedWrites = map[string]bool{
+	"/api/logout": true,
+}
+
+// isReadOnlyRequest reports whether the request was authenticated with a read-only token
+func isReadOnlyRequest(c *gin.Context) bool {
+	return c.GetString(tokenScopeKey) == auth.ScopeReadOnly
+}
+
+// readOnlyGuard rejects every request that could change state (create/start/stop/close trader,
+// set SL/TP, update configs...) when authenticated with a read-only token.
+// Only safe methods pass, so new mutating endpoints are covered without extra checks.
+func (s *Server) readOnlyGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isReadOnlyRequest(c) {
+			c.Next()
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if readOnlyAllowedWrites[c.FullPath()] {
+			c.Next()
+			return
+		}
+		respondError(c, http.StatusForbidden, ErrCodeReadOnlyToken, readOnlyForbiddenMsg)
+		c.Abort()
+	}
+}
+
+// handleCreateReadOnlyToken Mint a view-only token for the current user (e.g. to share a dashboard)
+// Body (optional): {"ttl_hours": 168} - default 7 days, max 30 days
+func (s *Server) handleCreateReadOnlyToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	email := c.GetString("email")
+
+	var req struct {
+		TTLHours int `json:"ttl_hours"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			SafeBadRequest(c, "Invalid request parameters")
+			return
+		}
+	}
+
+	ttl := defaultReadOnlyTokenTTL
+	if req.TTLHours < 0 {
+		SafeBadRequest(c, "ttl_hours must be positive")
+		return
+	}
+	if req.TTLHours > 0 {
+		ttl = time.Duration(req.TTLHours) * time.Hour
+	}
+	if ttl > maxReadOnlyTokenTTL {
+		ttl = maxReadOnlyTokenTTL
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	token, err := auth.GenerateReadOnlyJWT(userID, email, expiresAt)
+	if err != nil {
+		SafeInternalError(c, "Generate read-only token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      token,
+		"scope":      auth.ScopeReadOnly,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
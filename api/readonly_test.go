@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nofx/auth"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadOnlyGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{}
+
+	newRouter := func(scope string) *gin.Engine {
+		r := gin.New()
+		api := r.Group("/api", func(c *gin.Context) {
+			c.Set(tokenScopeKey, scope)
+		}, s.readOnlyGuard())
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		api.GET("/traders", ok)
+		api.POST("/traders", ok)
+		api.POST("/traders/:id/stop", ok)
+		api.PUT("/traders/:id", ok)
+		api.POST("/logout", ok)
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		scope  string
+		method string
+		path   string
+		want   int
+	}{
+		{"full scope create", auth.ScopeFull, http.MethodPost, "/api/traders", http.StatusOK},
+		{"read-only list", auth.ScopeReadOnly, http.MethodGet, "/api/traders", http.StatusOK},
+		{"read-only create", auth.ScopeReadOnly, http.MethodPost, "/api/traders", http.StatusForbidden},
+		{"read-only stop", auth.ScopeReadOnly, http.MethodPost, "/api/traders/abc/stop", http.StatusForbidden},
+		{"read-only update", auth.ScopeReadOnly, http.MethodPut, "/api/traders/abc", http.StatusForbidden},
+		{"read-only logout", auth.ScopeReadOnly, http.MethodPost, "/api/logout", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newRouter(tt.scope).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlyJWTScope(t *testing.T) {
+	auth.SetJWTSecret("test-secret")
+
+	token, err := auth.GenerateReadOnlyJWT("user-1", "a@b.c", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GenerateReadOnlyJWT() error = %v", err)
+	}
+	claims, err := auth.ValidateJWT(token)
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if !claims.IsReadOnly() || claims.UserID != "user-1" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	token, err = auth.GenerateJWT("user-1", "a@b.c")
+	if err != nil {
+		t.Fatalf("GenerateJWT() error = %v", err)
+	}
+	if claims, err = auth.ValidateJWT(token); err != nil || claims.IsReadOnly() {
+		t.Errorf("regular token should have full scope, got %+v, %v", claims, err)
+	}
+}
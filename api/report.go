@@ -0,0 +1,292 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Report limits
+const (
+	reportMaxDays        = 365
+	reportTopTrades      = 5
+	reportMaxCurvePoints = 300
+	reportChartWidth     = 720
+	reportChartHeight    = 200
+)
+
+// parseReportPeriod parses a report period such as "30d" (1 to 365 days)
+func parseReportPeriod(period string) (time.Duration, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+	if err != nil || !strings.HasSuffix(period, "d") || days < 1 || days > reportMaxDays {
+		return 0, fmt.Errorf("period must be between 1d and %dd", reportMaxDays)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// equityCurve equity snapshots scaled to an SVG polyline
+type equityCurve struct {
+	Points     string // SVG polyline points
+	Start, End float64
+	Min, Max   float64
+	ChangePct  float64
+}
+
+// buildEquityCurve scales the equity snapshots to the chart, keeping at most reportMaxCurvePoints points
+func buildEquityCurve(snapshots []*store.EquitySnapshot) *equityCurve {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	step := 1
+	if len(snapshots) > reportMaxCurvePoints {
+		step = (len(snapshots) + reportMaxCurvePoints - 1) / reportMaxCurvePoints
+	}
+	var sampled []*store.EquitySnapshot
+	for i := 0; i < len(snapshots); i += step {
+		sampled = append(sampled, snapshots[i])
+	}
+	if last := snapshots[len(snapshots)-1]; sampled[len(sampled)-1] != last {
+		sampled = append(sampled, last)
+	}
+
+	curve := &equityCurve{
+		Start: sampled[0].TotalEquity,
+		End:   sampled[len(sampled)-1].TotalEquity,
+		Min:   sampled[0].TotalEquity,
+		Max:   sampled[0].TotalEquity,
+	}
+	for _, s := range sampled {
+		curve.Min = min(curve.Min, s.TotalEquity)
+		curve.Max = max(curve.Max, s.TotalEquity)
+	}
+	if curve.Start > 0 {
+		curve.ChangePct = (curve.End - curve.Start) / curve.Start * 100
+	}
+
+	spread := curve.Max - curve.Min
+	points := make([]string, 0, len(sampled))
+	for i, s := range sampled {
+		x := 0.0
+		if len(sampled) > 1 {
+			x = float64(i) / float64(len(sampled)-1) * reportChartWidth
+		}
+		y := reportChartHeight / 2.0
+		if spread > 0 {
+			y = (curve.Max - s.TotalEquity) / spread * reportChartHeight
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	curve.Points = strings.Join(points, " ")
+	return curve
+}
+
+// traderReport data of the trade-journal report template
+type traderReport struct {
+	TraderName  string
+	Exchange    string
+	Period      string
+	Start, End  time.Time
+	GeneratedAt time.Time
+	Equity      *equityCurve
+	Trades      *store.PeriodStats
+	AI          *store.AIUsage
+	ChartWidth  int
+	ChartHeight int
+}
+
+// EstimatedTokens rough token volume of the AI calls (~4 characters per token)
+func (r *traderReport) EstimatedTokens() int64 {
+	return (r.AI.PromptChars + r.AI.ResponseChars) / 4
+}
+
+// AvgAISeconds average AI request duration in seconds
+func (r *traderReport) AvgAISeconds() float64 {
+	if r.AI.Calls == 0 {
+		return 0
+	}
+	return float64(r.AI.TotalDurationMs) / float64(r.AI.Calls) / 1000
+}
+
+var reportFuncs = template.FuncMap{
+	"usd": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"pct": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"signed": func(v float64) string {
+		if v > 0 {
+			return fmt.Sprintf("+%.2f", v)
+		}
+		return fmt.Sprintf("%.2f", v)
+	},
+	"unix": func(sec int64) string { return time.Unix(sec, 0).UTC().Format("2006-01-02 15:04") },
+	"pnlClass": func(v float64) string {
+		if v < 0 {
+			return "neg"
+		}
+		return "pos"
+	},
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.TraderName}} · Trading report {{.Period}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; color: #1e2329; max-width: 800px; margin: 32px auto; padding: 0 16px; }
+h1 { margin-bottom: 4px; } h2 { margin-top: 32px; border-bottom: 1px solid #eaecef; padding-bottom: 4px; }
+.meta { color: #707a8a; font-size: 14px; }
+table { width: 100%; border-collapse: collapse; font-size: 14px; }
+th, td { text-align: right; padding: 6px 8px; border-bottom: 1px solid #f0f1f2; }
+th:first-child, td:first-child { text-align: left; }
+.grid { display: grid; grid-template-columns: repeat(4, 1fr); gap: 12px; }
+.card { background: #f5f5f5; border-radius: 8px; padding: 12px; }
+.card .label { color: #707a8a; font-size: 12px; } .card .value { font-size: 20px; font-weight: 600; }
+.pos { color: #0ecb81; } .neg { color: #f6465d; }
+svg { background: #fafafa; border-radius: 8px; }
+</style>
+</head>
+<body>
+<h1>{{.TraderName}}</h1>
+<div class="meta">{{.Exchange}} · {{.Start.Format "2006-01-02"}} – {{.End.Format "2006-01-02"}} (UTC) · generated {{.GeneratedAt.Format "2006-01-02 15:04"}} UTC</div>
+
+<h2>Summary</h2>
+<div class="grid">
+<div class="card"><div class="label">Realized PnL</div><div class="value {{pnlClass .Trades.Stats.TotalPnL}}">{{signed .Trades.Stats.TotalPnL}}</div></div>
+<div class="card"><div class="label">Win rate</div><div class="value">{{pct .Trades.Stats.WinRate}}</div></div>
+<div class="card"><div class="label">Trades</div><div class="value">{{.Trades.Stats.TotalTrades}}</div></div>
+<div class="card"><div class="label">Profit factor</div><div class="value">{{printf "%.2f" .Trades.Stats.ProfitFactor}}</div></div>
+<div class="card"><div class="label">Fees</div><div class="value">{{usd .Trades.Stats.TotalFee}}</div></div>
+<div class="card"><div class="label">Avg win / loss</div><div class="value">{{usd .Trades.Stats.AvgWin}} / {{usd .Trades.Stats.AvgLoss}}</div></div>
+<div class="card"><div class="label">Max drawdown</div><div class="value">{{pct .Trades.Stats.MaxDrawdownPct}}</div></div>
+<div class="card"><div class="label">Sharpe</div><div class="value">{{printf "%.2f" .Trades.Stats.SharpeRatio}}</div></div>
+</div>
+
+<h2>Equity curve</h2>
+{{if .Equity}}
+<svg width="{{.ChartWidth}}" height="{{.ChartHeight}}" viewBox="0 0 {{.ChartWidth}} {{.ChartHeight}}" preserveAspectRatio="none">
+<polyline fill="none" stroke="#f0b90b" stroke-width="2" points="{{.Equity.Points}}"/>
+</svg>
+<p class="meta">{{usd .Equity.Start}} → {{usd .Equity.End}} USDT (<span class="{{pnlClass .Equity.ChangePct}}">{{signed .Equity.ChangePct}}%</span>) · low {{usd .Equity.Min}} · high {{usd .Equity.Max}}</p>
+{{else}}
+<p class="meta">No equity snapshots in this period.</p>
+{{end}}
+
+<h2>Best trades</h2>
+{{template "trades" .Trades.BestTrades}}
+<h2>Worst trades</h2>
+{{template "trades" .Trades.WorstTrades}}
+
+<h2>By symbol</h2>
+{{if .Trades.Symbols}}
+<table>
+<tr><th>Symbol</th><th>Trades</th><th>Win rate</th><th>Total PnL</th><th>Avg PnL</th><th>Avg hold (min)</th></tr>
+{{range .Trades.Symbols}}<tr><td>{{.Symbol}}</td><td>{{.TotalTrades}}</td><td>{{pct .WinRate}}</td><td class="{{pnlClass .TotalPnL}}">{{signed .TotalPnL}}</td><td>{{signed .AvgPnL}}</td><td>{{printf "%.0f" .AvgHoldMins}}</td></tr>
+{{end}}
+</table>
+{{else}}
+<p class="meta">No closed trades in this period.</p>
+{{end}}
+
+<h2>AI usage</h2>
+<table>
+<tr><td>Decision cycles</td><td>{{.AI.Calls}} ({{.AI.FailedCalls}} failed)</td></tr>
+<tr><td>Average AI response time</td><td>{{printf "%.1f" .AvgAISeconds}} s</td></tr>
+<tr><td>Estimated tokens (prompt + response)</td><td>~{{.EstimatedTokens}}</td></tr>
+</table>
+</body>
+</html>
+{{define "trades"}}{{if .}}
+<table>
+<tr><th>Symbol</th><th>Side</th><th>Entry</th><th>Exit</th><th>PnL</th><th>PnL %</th><th>Held</th><th>Closed (UTC)</th></tr>
+{{range .}}<tr><td>{{.Symbol}}</td><td>{{.Side}}</td><td>{{.EntryPrice}}</td><td>{{.ExitPrice}}</td><td class="{{pnlClass .RealizedPnL}}">{{signed .RealizedPnL}}</td><td>{{pct .PnLPct}}</td><td>{{.HoldDuration}}</td><td>{{unix .ExitTime}}</td></tr>
+{{end}}
+</table>
+{{else}}<p class="meta">None.</p>{{end}}{{end}}
+`))
+
+// reportFilenameUnsafe characters replaced in the report filename
+var reportFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// reportFilename attachment filename of a report, e.g. "my-trader-report-30d-20260101.html"
+func reportFilename(traderName, period string, generatedAt time.Time) string {
+	name := strings.Trim(reportFilenameUnsafe.ReplaceAllString(traderName, "-"), "-")
+	if name == "" {
+		name = "trader"
+	}
+	return fmt.Sprintf("%s-report-%s-%s.html", name, period, generatedAt.Format("20060102"))
+}
+
+// handleTraderReport Download a trade-journal HTML report of a trader for a period
+// (equity curve, win rate, best/worst trades, symbol breakdown and AI usage)
+func (s *Server) handleTraderReport(c *gin.Context) {
+	traderID := c.Param("id")
+	fullCfg, err := s.store.Trader().GetFullConfig(c.GetString("user_id"), traderID)
+	if err != nil || fullCfg.Trader == nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	if format := c.DefaultQuery("format", "html"); format != "html" {
+		SafeBadRequest(c, "Unsupported report format, only html is available")
+		return
+	}
+	period := c.DefaultQuery("period", "30d")
+	duration, err := parseReportPeriod(period)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-duration)
+
+	trades, err := s.store.Position().GetPeriodStats(traderID, start.UnixMilli(), end.UnixMilli(), reportTopTrades)
+	if err != nil {
+		SafeInternalError(c, "Get report trade statistics", err)
+		return
+	}
+	snapshots, err := s.store.Equity().GetByTimeRange(traderID, start, end)
+	if err != nil {
+		SafeInternalError(c, "Get report equity curve", err)
+		return
+	}
+	aiUsage, err := s.store.Decision().GetAIUsage(traderID, start, end)
+	if err != nil {
+		SafeInternalError(c, "Get report AI usage", err)
+		return
+	}
+
+	report := &traderReport{
+		TraderName:  fullCfg.Trader.Name,
+		Period:      period,
+		Start:       start,
+		End:         end,
+		GeneratedAt: end,
+		Equity:      buildEquityCurve(snapshots),
+		Trades:      trades,
+		AI:          aiUsage,
+		ChartWidth:  reportChartWidth,
+		ChartHeight: reportChartHeight,
+	}
+	if fullCfg.Exchange != nil {
+		report.Exchange = fullCfg.Exchange.ExchangeType
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, report); err != nil {
+		SafeInternalError(c, "Render trader report", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, reportFilename(report.TraderName, period, end)))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+func TestParseReportPeriod(t *testing.T) {
+	if d, err := parseReportPeriod("30d"); err != nil || d != 30*24*time.Hour {
+		t.Errorf("parseReportPeriod(30d) = %v, %v", d, err)
+	}
+	for _, bad := range []string{"", "30", "0d", "366d", "2w", "-1d"} {
+		if _, err := parseReportPeriod(bad); err == nil {
+			t.Errorf("parseReportPeriod(%q) accepted", bad)
+		}
+	}
+}
+
+func TestBuildEquityCurve(t *testing.T) {
+	if buildEquityCurve(nil) != nil {
+		t.Fatal("expected no curve without snapshots")
+	}
+
+	var snapshots []*store.EquitySnapshot
+	for i := 0; i < 1000; i++ {
+		snapshots = append(snapshots, &store.EquitySnapshot{TotalEquity: 1000 + float64(i)})
+	}
+	curve := buildEquityCurve(snapshots)
+	if n := len(strings.Fields(curve.Points)); n > reportMaxCurvePoints+1 {
+		t.Errorf("curve has %d points, want at most %d", n, reportMaxCurvePoints+1)
+	}
+	if curve.Start != 1000 || curve.End != 1999 || curve.Min != 1000 || curve.Max != 1999 {
+		t.Errorf("curve range = %+v", curve)
+	}
+	if !strings.HasSuffix(curve.Points, "720.0,0.0") {
+		t.Errorf("last point should be the top right corner, got %q", curve.Points[len(curve.Points)-20:])
+	}
+}
+
+func TestRenderTraderReport(t *testing.T) {
+	report := &traderReport{
+		TraderName: "<script>x</script>",
+		Period:     "7d",
+		Equity:     buildEquityCurve([]*store.EquitySnapshot{{TotalEquity: 100}, {TotalEquity: 110}}),
+		Trades: &store.PeriodStats{
+			Stats:       &store.TraderStats{TotalTrades: 2, WinRate: 50, TotalPnL: 12.5},
+			Symbols:     []store.SymbolStats{{Symbol: "BTCUSDT", TotalTrades: 2, TotalPnL: 12.5}},
+			BestTrades:  []store.RecentTrade{{Symbol: "BTCUSDT", Side: "long", RealizedPnL: 20}},
+			WorstTrades: []store.RecentTrade{},
+		},
+		AI: &store.AIUsage{Calls: 4, TotalDurationMs: 8000, PromptChars: 4000},
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, report); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"12.50", "50.00%", "BTCUSDT", "<polyline", "~1000", "2.0 s"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>x</script>") {
+		t.Error("trader name not escaped")
+	}
+}
+
+func TestReportFilename(t *testing.T) {
+	got := reportFilename("BTC Trend / v2", "30d", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	if got != "BTC-Trend-v2-report-30d-20260102.html" {
+		t.Errorf("reportFilename() = %q", got)
+	}
+	if got := reportFilename("交易员", "7d", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)); got != "trader-report-7d-20260102.html" {
+		t.Errorf("reportFilename() = %q", got)
+	}
+}
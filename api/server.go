@@ -3,25 +3,33 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"nofx/auth"
 	"nofx/backtest"
 	"nofx/config"
 	"nofx/crypto"
+	"nofx/kernel"
 	"nofx/logger"
 	"nofx/manager"
 	"nofx/market"
 	"nofx/provider/alpaca"
+	"nofx/provider/aster"
+	"nofx/provider/backpack"
 	"nofx/provider/coinank/coinank_api"
 	"nofx/provider/coinank/coinank_enum"
+	"nofx/provider/coinbase"
+	"nofx/provider/gateio"
 	"nofx/provider/hyperliquid"
 	"nofx/provider/twelvedata"
 	"nofx/store"
 	"nofx/trader"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +46,10 @@ type Server struct {
 	debateHandler   *DebateHandler
 	httpServer      *http.Server
 	port            int
+	rateLimiter     *apiRateLimiter // nil = rate limiting disabled
+	auditor         *apiAuditor     // nil = audit logging disabled
+	signalReplays   *signalReplayCache
+	signalTargetFn  func(traderID string) (signalSubmitter, error) // nil = traders loaded in traderManager
 }
 
 // NewServer Creates API server
@@ -53,11 +65,8 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 	// Create crypto handler
 	cryptoHandler := NewCryptoHandler(cryptoService)
 
-	// Create debate store and handler
+	// Create debate store and handler (tables are created by store migrations)
 	debateStore := store.NewDebateStore(st.GormDB())
-	if err := debateStore.InitSchema(); err != nil {
-		logger.Errorf("Failed to initialize debate schema: %v", err)
-	}
 	debateHandler := NewDebateHandler(debateStore, st.Strategy(), st.AIModel())
 	debateHandler.SetTraderManager(traderManager)
 
@@ -69,6 +78,23 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 		backtestManager: backtestManager,
 		debateHandler:   debateHandler,
 		port:            port,
+		signalReplays:   newSignalReplayCache(),
+	}
+
+	// Per-user, per-endpoint rate limiting and request audit log
+	cfg := config.Get()
+	overrides, err := ParseAPIRateLimitOverrides(cfg.APIRateLimitOverrides)
+	if err != nil {
+		logger.Warnf("⚠️ Invalid API rate limit overrides %q: %v", cfg.APIRateLimitOverrides, err)
+	}
+	s.rateLimiter = newAPIRateLimiter(cfg.APIRateLimitPerMinute, overrides, apiRateLimitWindow)
+	if cfg.APIAuditLog {
+		s.auditor = newAPIAuditor(st.APIAudit())
+	}
+
+	// Cache finished strategy backtests for the strategy dashboard
+	if backtestManager != nil {
+		backtestManager.SetCompletionHandler(s.recordStrategyBacktestSummary)
 	}
 
 	// Setup routes
@@ -96,7 +122,7 @@ func corsMiddleware() gin.HandlerFunc {
 // setupRoutes Setup routes
 func (s *Server) setupRoutes() {
 	// API route group
-	api := s.router.Group("/api")
+	api := s.router.Group("/api", s.auditMiddleware(), s.rateLimitMiddleware())
 	{
 		// Health check
 		api.Any("/health", s.handleHealth)
@@ -123,9 +149,13 @@ func (s *Server) setupRoutes() {
 		api.POST("/equity-history-batch", s.handleEquityHistoryBatch)
 		api.GET("/traders/:id/public-config", s.handleGetPublicTraderConfig)
 
+		// External signals (Bearer JWT or HMAC-signed webhook, verified in handler)
+		api.POST("/traders/:id/signal", s.handleTraderSignal)
+
 		// Market data (no authentication required)
 		api.GET("/klines", s.handleKlines)
 		api.GET("/symbols", s.handleSymbols)
+		api.GET("/symbols/search", s.handleSymbolSearch)
 
 		// Public strategy market (no authentication required)
 		api.GET("/strategies/public", s.handlePublicStrategies)
@@ -137,25 +167,47 @@ func (s *Server) setupRoutes() {
 		api.POST("/complete-registration", s.handleCompleteRegistration)
 
 		// Routes requiring authentication
-		protected := api.Group("/", s.authMiddleware())
+		protected := api.Group("/", s.authMiddleware(), s.readOnlyGuard())
 		{
 			// Logout (add to blacklist)
 			protected.POST("/logout", s.handleLogout)
 
+			// Mint a view-only token for sharing dashboards
+			protected.POST("/tokens/read-only", s.handleCreateReadOnlyToken)
+
 			// Server IP query (requires authentication, for whitelist configuration)
 			protected.GET("/server-ip", s.handleGetServerIP)
 
 			// AI trader management
 			protected.GET("/my-traders", s.handleTraderList)
 			protected.GET("/traders/:id/config", s.handleGetTraderConfig)
+			protected.GET("/traders/:id/effective-config", s.handleTraderEffectiveConfig)
 			protected.POST("/traders", s.handleCreateTrader)
 			protected.PUT("/traders/:id", s.handleUpdateTrader)
 			protected.DELETE("/traders/:id", s.handleDeleteTrader)
 			protected.POST("/traders/:id/start", s.handleStartTrader)
 			protected.POST("/traders/:id/stop", s.handleStopTrader)
+			protected.POST("/traders/:id/disable", s.handleDisableTrader)
+			protected.POST("/traders/:id/enable", s.handleEnableTrader)
+			protected.POST("/traders/:id/reset-history", s.handleResetTraderHistory)
+			protected.POST("/traders/:id/webhook-secret", s.handleRotateWebhookSecret)
+			protected.DELETE("/traders/:id/webhook-secret", s.handleDeleteWebhookSecret)
 			protected.PUT("/traders/:id/prompt", s.handleUpdateTraderPrompt)
+			protected.GET("/traders/:id/effective-prompt", s.handleGetEffectivePrompt)
+			protected.GET("/traders/:id/open-orders/all", s.handleTraderOpenOrdersAll)
+			protected.DELETE("/traders/:id/orders/:exchangeOrderId", s.handleCancelTraderOrder)
+			protected.GET("/traders/:id/alerts", s.handleListEquityAlerts)
+			protected.POST("/traders/:id/alerts", s.handleCreateEquityAlert)
+			protected.PUT("/traders/:id/alerts/:alertId", s.handleUpdateEquityAlert)
+			protected.DELETE("/traders/:id/alerts/:alertId", s.handleDeleteEquityAlert)
+			protected.GET("/traders/:id/report", s.handleTraderReport)
+			protected.GET("/traders/:id/logs", s.handleTraderLogs)
 			protected.POST("/traders/:id/sync-balance", s.handleSyncBalance)
+			protected.GET("/traders/:id/balance-adjustments", s.handleListBalanceAdjustments)
+			protected.POST("/traders/:id/open", s.handleOpenPosition)
 			protected.POST("/traders/:id/close-position", s.handleClosePosition)
+			protected.POST("/traders/:id/set-sltp", s.handleSetSLTP)
+			protected.POST("/traders/:id/rebalance", s.handleRebalancePosition)
 			protected.PUT("/traders/:id/competition", s.handleToggleCompetition)
 
 			// AI model configuration
@@ -165,8 +217,10 @@ func (s *Server) setupRoutes() {
 			// Exchange configuration
 			protected.GET("/exchanges", s.handleGetExchangeConfigs)
 			protected.POST("/exchanges", s.handleCreateExchange)
+			protected.POST("/exchanges/test", s.handleTestExchangeCredentials)
 			protected.PUT("/exchanges", s.handleUpdateExchangeConfigs)
 			protected.DELETE("/exchanges/:id", s.handleDeleteExchange)
+			protected.POST("/exchanges/:id/test", s.handleTestExchange)
 
 			// Strategy management
 			protected.GET("/strategies", s.handleGetStrategies)
@@ -180,6 +234,7 @@ func (s *Server) setupRoutes() {
 			protected.DELETE("/strategies/:id", s.handleDeleteStrategy)
 			protected.POST("/strategies/:id/activate", s.handleActivateStrategy)
 			protected.POST("/strategies/:id/duplicate", s.handleDuplicateStrategy)
+			protected.GET("/strategies/:id/backtest-summary", s.handleStrategyBacktestSummary)
 
 			// Debate Arena
 			protected.GET("/debates", s.debateHandler.HandleListDebates)
@@ -199,17 +254,33 @@ func (s *Server) setupRoutes() {
 			protected.GET("/account", s.handleAccount)
 			protected.GET("/positions", s.handlePositions)
 			protected.GET("/positions/history", s.handlePositionHistory)
+			protected.GET("/liquidation-preview", s.handleLiquidationPreview)
 			protected.GET("/trades", s.handleTrades)
 			protected.GET("/orders", s.handleOrders)               // Order list (all orders)
 			protected.GET("/orders/:id/fills", s.handleOrderFills) // Order fill details
 			protected.GET("/open-orders", s.handleOpenOrders)      // Open orders from exchange (pending SL/TP)
 			protected.GET("/decisions", s.handleDecisions)
 			protected.GET("/decisions/latest", s.handleLatestDecisions)
+			protected.GET("/decisions/diff", s.handleDecisionDiff)
+			protected.GET("/decisions/:id", s.handleGetDecision)
+			protected.PUT("/decisions/:id/annotate", s.handleAnnotateDecision)
 			protected.GET("/statistics", s.handleStatistics)
+			protected.GET("/realized-pnl-history", s.handleRealizedPnLHistory)
+
+			// Aggregated view across all of the user's traders
+			protected.GET("/portfolio", s.handlePortfolio)
+			protected.GET("/portfolio/positions", s.handlePortfolioPositions)
 
 			// Backtest routes
 			backtest := protected.Group("/backtest")
 			s.registerBacktestRoutes(backtest)
+
+			// Admin routes (admin user only)
+			admin := protected.Group("/admin", s.adminMiddleware())
+			admin.GET("/overview", s.handleAdminOverview)
+			admin.GET("/audit-logs", s.handleAdminAuditLogs)
+			admin.POST("/pause-all", s.handleAdminPauseAll)
+			admin.POST("/resume-all", s.handleAdminResumeAll)
 		}
 	}
 }
@@ -228,6 +299,7 @@ func (s *Server) handleGetSystemConfig(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"registration_enabled": cfg.RegistrationEnabled,
+		"trading_paused":       trader.IsTradingPaused(),
 		"btc_eth_leverage":     10, // Default value
 		"altcoin_leverage":     5,  // Default value
 	})
@@ -245,7 +317,7 @@ func (s *Server) handleGetServerIP(c *gin.Context) {
 
 	// If still cannot get it, return error
 	if publicIP == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to get public IP address"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Unable to get public IP address")
 		return
 	}
 
@@ -398,8 +470,17 @@ type CreateTraderRequest struct {
 	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
 	InitialBalance      float64 `json:"initial_balance"`
 	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
-	IsCrossMargin       *bool   `json:"is_cross_margin"`     // Pointer type, nil means use default value true
-	ShowInCompetition   *bool   `json:"show_in_competition"` // Pointer type, nil means use default value true
+	IsCrossMargin       *bool   `json:"is_cross_margin"`        // Pointer type, nil means use default value true
+	ShowInCompetition   *bool   `json:"show_in_competition"`    // Pointer type, nil means use default value true
+	QuoteAsset          string  `json:"quote_asset"`            // Stablecoin quote asset: USDT (default) or USDC
+	FlattenOnStop       bool    `json:"flatten_on_stop"`        // Close all positions and cancel orders when stopped
+	PromptLanguage      string  `json:"prompt_language"`        // AI reasoning language (empty = use strategy)
+	ConsensusModels     string  `json:"consensus_models"`       // Extra AI model IDs voting each cycle ("id:weight,id")
+	ConsensusThreshold  float64 `json:"consensus_threshold"`    // Share of vote weight needed (0 = more than half)
+	AIRequestTimeoutSec int     `json:"ai_request_timeout_sec"` // Max seconds to wait for the AI decision (0 = default 120s)
+	DisplayDecimals     int     `json:"display_decimals"`       // Decimal places of equity/PnL amounts (0 = chosen from account size)
+	MirrorExchangeIDs   string  `json:"mirror_exchange_ids"`    // Extra exchange account IDs every order is replicated on ("id,id")
+	LogLevel            string  `json:"log_level"`              // Log level of the trader's lines (empty = global level)
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	BTCETHLeverage       int    `json:"btc_eth_leverage"`
 	AltcoinLeverage      int    `json:"altcoin_leverage"`
@@ -453,6 +534,9 @@ type SafeExchangeConfig struct {
 	AsterUser             string `json:"asterUser"`             // Aster username (not sensitive)
 	AsterSigner           string `json:"asterSigner"`           // Aster signer (not sensitive)
 	LighterWalletAddr     string `json:"lighterWalletAddr"`     // LIGHTER wallet address (not sensitive)
+	ExtraAPIKeyCount      int    `json:"extraApiKeyCount"`      // Additional API keys rotated with the primary key
+	// IOC limit offset of DEX market orders (Hyperliquid, Lighter), omitted when the exchange default is used
+	Slippage *store.SlippageSettings `json:"slippage,omitempty"`
 }
 
 type UpdateModelConfigRequest struct {
@@ -479,9 +563,16 @@ type UpdateExchangeConfigRequest struct {
 		LighterPrivateKey       string `json:"lighter_private_key"`
 		LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
 		LighterAPIKeyIndex      int    `json:"lighter_api_key_index"`
+		// Additional API keys of the same account (nil = unchanged, empty = remove all)
+		ExtraAPIKeys *[]store.APIKeyCredential `json:"extra_api_keys,omitempty"`
+		// IOC slippage of Hyperliquid/Lighter market orders in bps (nil = unchanged, empty = exchange default)
+		Slippage *store.SlippageSettings `json:"slippage,omitempty"`
 	} `json:"exchanges"`
 }
 
+// maxExtraAPIKeys additional API keys allowed per exchange account
+const maxExtraAPIKeys = 10
+
 // handleCreateTrader Create new AI trader
 func (s *Server) handleCreateTrader(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -493,24 +584,52 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 
 	// Validate leverage values
 	if req.BTCETHLeverage < 0 || req.BTCETHLeverage > 50 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "BTC/ETH leverage must be between 1-50x"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "BTC/ETH leverage must be between 1-50x")
 		return
 	}
 	if req.AltcoinLeverage < 0 || req.AltcoinLeverage > 20 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Altcoin leverage must be between 1-20x"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Altcoin leverage must be between 1-20x")
 		return
 	}
 
-	// Validate trading symbol format
-	if req.TradingSymbols != "" {
-		symbols := strings.Split(req.TradingSymbols, ",")
-		for _, symbol := range symbols {
-			symbol = strings.TrimSpace(symbol)
-			if symbol != "" && !strings.HasSuffix(strings.ToUpper(symbol), "USDT") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid symbol format: %s, must end with USDT", symbol)})
-				return
-			}
-		}
+	// Validate trading symbol format and quote asset
+	quoteAsset, err := resolveQuoteAsset(req.QuoteAsset, req.TradingSymbols)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	promptLanguage, err := kernel.NormalizePromptLanguage(req.PromptLanguage)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	if err := validateConsensusConfig(req.ConsensusModels, req.ConsensusThreshold); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	if err := validateAIRequestTimeout(req.AIRequestTimeoutSec); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	if err := validateDisplayDecimals(req.DisplayDecimals); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	mirrorExchangeIDs, err := s.validateMirrorExchanges(userID, req.ExchangeID, req.MirrorExchangeIDs)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	logLevel := strings.ToLower(strings.TrimSpace(req.LogLevel))
+	if err := logger.ParseLevel(logLevel); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
 	}
 
 	// Generate trader ID (use short UUID prefix for readability)
@@ -575,58 +694,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		logger.Infof("⚠️ Exchange %s not enabled, using user input for initial balance", req.ExchangeID)
 	} else {
 		// Create temporary trader based on exchange type to query balance
-		var tempTrader trader.Trader
-		var createErr error
-
-		// Use ExchangeType (e.g., "binance") instead of ID (UUID)
-		// Convert EncryptedString fields to string
-		switch exchangeCfg.ExchangeType {
-		case "binance":
-			tempTrader = trader.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
-		case "hyperliquid":
-			tempTrader, createErr = trader.NewHyperliquidTrader(
-				string(exchangeCfg.APIKey), // private key
-				exchangeCfg.HyperliquidWalletAddr,
-				exchangeCfg.Testnet,
-			)
-		case "aster":
-			tempTrader, createErr = trader.NewAsterTrader(
-				exchangeCfg.AsterUser,
-				exchangeCfg.AsterSigner,
-				string(exchangeCfg.AsterPrivateKey),
-			)
-		case "bybit":
-			tempTrader = trader.NewBybitTrader(
-				string(exchangeCfg.APIKey),
-				string(exchangeCfg.SecretKey),
-			)
-		case "okx":
-			tempTrader = trader.NewOKXTrader(
-				string(exchangeCfg.APIKey),
-				string(exchangeCfg.SecretKey),
-				string(exchangeCfg.Passphrase),
-			)
-		case "bitget":
-			tempTrader = trader.NewBitgetTrader(
-				string(exchangeCfg.APIKey),
-				string(exchangeCfg.SecretKey),
-				string(exchangeCfg.Passphrase),
-			)
-		case "lighter":
-			if exchangeCfg.LighterWalletAddr != "" && string(exchangeCfg.LighterAPIKeyPrivateKey) != "" {
-				// Lighter only supports mainnet
-				tempTrader, createErr = trader.NewLighterTraderV2(
-					exchangeCfg.LighterWalletAddr,
-					string(exchangeCfg.LighterAPIKeyPrivateKey),
-					exchangeCfg.LighterAPIKeyIndex,
-					false, // Always use mainnet for Lighter
-				)
-			} else {
-				createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
-			}
-		default:
-			logger.Infof("⚠️ Unsupported exchange type: %s, using user input for initial balance", exchangeCfg.ExchangeType)
-		}
+		tempTrader, createErr := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
 
 		if createErr != nil {
 			logger.Infof("⚠️ Failed to create temporary trader, using user input for initial balance: %v", createErr)
@@ -674,6 +742,15 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		SystemPromptTemplate: systemPromptTemplate,
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
+		QuoteAsset:           quoteAsset,
+		FlattenOnStop:        req.FlattenOnStop,
+		PromptLanguage:       promptLanguage,
+		ConsensusModels:      strings.TrimSpace(req.ConsensusModels),
+		ConsensusThreshold:   req.ConsensusThreshold,
+		AIRequestTimeoutSec:  req.AIRequestTimeoutSec,
+		DisplayDecimals:      req.DisplayDecimals,
+		MirrorExchangeIDs:    mirrorExchangeIDs,
+		LogLevel:             logLevel,
 		ScanIntervalMinutes:  scanIntervalMinutes,
 		IsRunning:            false,
 	}
@@ -707,16 +784,121 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 	})
 }
 
+// resolveQuoteAsset validates trading symbols and returns the trader's quote asset
+// Symbols must end with a supported quote (USDT/USDC). If quote is empty it is inferred
+// from the symbols (USDC only when all symbols are USDC-quoted), defaulting to USDT.
+func resolveQuoteAsset(quote, tradingSymbols string) (string, error) {
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if quote != "" && !market.IsSupportedQuoteAsset(quote) {
+		return "", fmt.Errorf("Unsupported quote asset: %s, must be one of %s", quote, strings.Join(market.SupportedQuoteAssets, "/"))
+	}
+
+	inferred := ""
+	for _, symbol := range strings.Split(tradingSymbols, ",") {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			continue
+		}
+		symbolQuote := market.QuoteAsset(symbol)
+		if symbolQuote == "" {
+			return "", fmt.Errorf("Invalid symbol format: %s, must end with %s", symbol, strings.Join(market.SupportedQuoteAssets, " or "))
+		}
+		if inferred == "" || symbolQuote == market.QuoteUSDT {
+			inferred = symbolQuote
+		}
+	}
+
+	if quote != "" {
+		return quote, nil
+	}
+	if inferred != "" {
+		return inferred, nil
+	}
+	return market.QuoteUSDT, nil
+}
+
+// validateConsensusConfig checks the multi-model consensus settings of a trader
+func validateConsensusConfig(models string, threshold float64) error {
+	if _, err := trader.ParseConsensusModels(models); err != nil {
+		return err
+	}
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("consensus_threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// maxAIRequestTimeoutSec upper bound of a trader's AI decision timeout
+const maxAIRequestTimeoutSec = 600
+
+// validateAIRequestTimeout checks a trader's AI decision timeout (0 = default)
+func validateAIRequestTimeout(seconds int) error {
+	if seconds < 0 || seconds > maxAIRequestTimeoutSec {
+		return fmt.Errorf("ai_request_timeout_sec must be between 0 and %d", maxAIRequestTimeoutSec)
+	}
+	return nil
+}
+
+// validateMirrorExchanges checks a trader's mirror exchange accounts (they must be the user's, of a
+// supported type and differ from the primary) and returns the normalized list
+func (s *Server) validateMirrorExchanges(userID, primaryID, spec string) (string, error) {
+	ids, err := trader.ParseMirrorExchangeIDs(spec)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range ids {
+		if id == primaryID {
+			return "", fmt.Errorf("mirror exchange %s is the trader's primary exchange", id)
+		}
+		exchange, err := s.store.Exchange().GetByID(userID, id)
+		if err != nil {
+			return "", fmt.Errorf("mirror exchange %s: %w", id, err)
+		}
+		if !trader.IsSupportedExchange(exchange.ExchangeType) {
+			return "", fmt.Errorf("mirror exchange %s: %w: %s", id, trader.ErrUnsupportedExchange, exchange.ExchangeType)
+		}
+	}
+	return strings.Join(ids, ","), nil
+}
+
+// mirrorsExchange reports whether a trader replicates its orders on the exchange account
+func mirrorsExchange(t *store.Trader, exchangeID string) bool {
+	ids, _ := trader.ParseMirrorExchangeIDs(t.MirrorExchangeIDs)
+	for _, id := range ids {
+		if id == exchangeID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDisplayDecimals checks a trader's equity/PnL display precision (0 = auto)
+func validateDisplayDecimals(decimals int) error {
+	if decimals < 0 || decimals > trader.MaxDisplayDecimals {
+		return fmt.Errorf("display_decimals must be between 0 and %d", trader.MaxDisplayDecimals)
+	}
+	return nil
+}
+
 // UpdateTraderRequest Update trader request
 type UpdateTraderRequest struct {
-	Name                string  `json:"name" binding:"required"`
-	AIModelID           string  `json:"ai_model_id" binding:"required"`
-	ExchangeID          string  `json:"exchange_id" binding:"required"`
-	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
-	InitialBalance      float64 `json:"initial_balance"`
-	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
-	IsCrossMargin       *bool   `json:"is_cross_margin"`
-	ShowInCompetition   *bool   `json:"show_in_competition"`
+	Name                string   `json:"name" binding:"required"`
+	AIModelID           string   `json:"ai_model_id" binding:"required"`
+	ExchangeID          string   `json:"exchange_id" binding:"required"`
+	StrategyID          string   `json:"strategy_id"` // Strategy ID (new version)
+	InitialBalance      float64  `json:"initial_balance"`
+	ScanIntervalMinutes int      `json:"scan_interval_minutes"`
+	IsCrossMargin       *bool    `json:"is_cross_margin"`
+	ShowInCompetition   *bool    `json:"show_in_competition"`
+	QuoteAsset          string   `json:"quote_asset"`            // Stablecoin quote asset: USDT or USDC (empty keeps original)
+	FlattenOnStop       *bool    `json:"flatten_on_stop"`        // nil keeps original
+	PromptLanguage      *string  `json:"prompt_language"`        // nil keeps original, "" uses the strategy's
+	ConsensusModels     *string  `json:"consensus_models"`       // nil keeps original, "" disables consensus
+	ConsensusThreshold  *float64 `json:"consensus_threshold"`    // nil keeps original
+	AIRequestTimeoutSec *int     `json:"ai_request_timeout_sec"` // nil keeps original, 0 uses the default
+	DisplayDecimals     *int     `json:"display_decimals"`       // nil keeps original, 0 chooses from account size
+	MirrorExchangeIDs   *string  `json:"mirror_exchange_ids"`    // nil keeps original, "" trades on the primary exchange only
+	LogLevel            *string  `json:"log_level"`              // nil keeps original, "" uses the global level
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	BTCETHLeverage       int    `json:"btc_eth_leverage"`
 	AltcoinLeverage      int    `json:"altcoin_leverage"`
@@ -740,7 +922,7 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 	// Check if trader exists and belongs to current user
 	traders, err := s.store.Trader().List(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trader list"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to get trader list")
 		return
 	}
 
@@ -753,7 +935,7 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 	}
 
 	if existingTrader == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
 		return
 	}
 
@@ -768,6 +950,70 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		showInCompetition = *req.ShowInCompetition
 	}
 
+	flattenOnStop := existingTrader.FlattenOnStop // Keep original value
+	if req.FlattenOnStop != nil {
+		flattenOnStop = *req.FlattenOnStop
+	}
+
+	promptLanguage := existingTrader.PromptLanguage // Keep original value
+	if req.PromptLanguage != nil {
+		promptLanguage, err = kernel.NormalizePromptLanguage(*req.PromptLanguage)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+			return
+		}
+	}
+
+	consensusModels := existingTrader.ConsensusModels // Keep original values
+	if req.ConsensusModels != nil {
+		consensusModels = strings.TrimSpace(*req.ConsensusModels)
+	}
+	consensusThreshold := existingTrader.ConsensusThreshold
+	if req.ConsensusThreshold != nil {
+		consensusThreshold = *req.ConsensusThreshold
+	}
+	if err := validateConsensusConfig(consensusModels, consensusThreshold); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	aiRequestTimeoutSec := existingTrader.AIRequestTimeoutSec
+	if req.AIRequestTimeoutSec != nil {
+		aiRequestTimeoutSec = *req.AIRequestTimeoutSec
+	}
+	if err := validateAIRequestTimeout(aiRequestTimeoutSec); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	displayDecimals := existingTrader.DisplayDecimals
+	if req.DisplayDecimals != nil {
+		displayDecimals = *req.DisplayDecimals
+	}
+	if err := validateDisplayDecimals(displayDecimals); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	mirrorExchangeIDs := existingTrader.MirrorExchangeIDs
+	if req.MirrorExchangeIDs != nil {
+		mirrorExchangeIDs = *req.MirrorExchangeIDs
+	}
+	mirrorExchangeIDs, err = s.validateMirrorExchanges(userID, req.ExchangeID, mirrorExchangeIDs)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
+	logLevel := existingTrader.LogLevel
+	if req.LogLevel != nil {
+		logLevel = strings.ToLower(strings.TrimSpace(*req.LogLevel))
+	}
+	if err := logger.ParseLevel(logLevel); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		return
+	}
+
 	// Set leverage default values
 	btcEthLeverage := req.BTCETHLeverage
 	altcoinLeverage := req.AltcoinLeverage
@@ -794,6 +1040,16 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		systemPromptTemplate = existingTrader.SystemPromptTemplate // Keep original value
 	}
 
+	// Validate quote asset (empty keeps original value)
+	quoteAsset := ""
+	if req.QuoteAsset != "" || req.TradingSymbols != "" {
+		quoteAsset, err = resolveQuoteAsset(req.QuoteAsset, req.TradingSymbols)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+			return
+		}
+	}
+
 	// Handle strategy ID (if not provided, keep original value)
 	strategyID := req.StrategyID
 	if strategyID == "" {
@@ -817,10 +1073,22 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		SystemPromptTemplate: systemPromptTemplate,
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
+		QuoteAsset:           quoteAsset,
+		FlattenOnStop:        flattenOnStop,
+		PromptLanguage:       promptLanguage,
+		ConsensusModels:      consensusModels,
+		ConsensusThreshold:   consensusThreshold,
+		AIRequestTimeoutSec:  aiRequestTimeoutSec,
+		DisplayDecimals:      displayDecimals,
+		MirrorExchangeIDs:    mirrorExchangeIDs,
+		LogLevel:             logLevel,
 		ScanIntervalMinutes:  scanIntervalMinutes,
 		IsRunning:            existingTrader.IsRunning, // Keep original value
 	}
 
+	// Not restarted while its history is reset (see handleResetTraderHistory)
+	defer s.traderManager.LockTrader(traderID)()
+
 	// Check if trader was running before update (we'll restart it after)
 	wasRunning := false
 	if existingMemTrader, memErr := s.traderManager.GetTrader(traderID); memErr == nil {
@@ -905,18 +1173,25 @@ func (s *Server) handleStartTrader(c *gin.Context) {
 	traderID := c.Param("id")
 
 	// Verify trader belongs to current user
-	_, err := s.store.Trader().GetFullConfig(userID, traderID)
+	fullCfg, err := s.store.Trader().GetFullConfig(userID, traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+	if fullCfg.Trader != nil && fullCfg.Trader.Disabled {
+		respondError(c, http.StatusConflict, ErrCodeTraderDisabled, "Trader is disabled, enable it first")
 		return
 	}
 
+	// Not started while its history is reset (see handleResetTraderHistory)
+	defer s.traderManager.LockTrader(traderID)()
+
 	// Check if trader exists in memory and if it's running
 	existingTrader, _ := s.traderManager.GetTrader(traderID)
 	if existingTrader != nil {
 		status := existingTrader.GetStatus()
 		if isRunning, ok := status["is_running"].(bool); ok && isRunning {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Trader is already running"})
+			respondError(c, http.StatusBadRequest, ErrCodeTraderRunning, "Trader is already running")
 			return
 		}
 		// Trader exists but is stopped - remove from memory to reload fresh config
@@ -928,7 +1203,7 @@ func (s *Server) handleStartTrader(c *gin.Context) {
 	logger.Infof("🔄 Loading trader %s from database...", traderID)
 	if loadErr := s.traderManager.LoadUserTradersFromStore(s.store, userID); loadErr != nil {
 		logger.Infof("❌ Failed to load user traders: %v", loadErr)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load trader: " + loadErr.Error()})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load trader: "+loadErr.Error())
 		return
 	}
 
@@ -939,34 +1214,34 @@ func (s *Server) handleStartTrader(c *gin.Context) {
 		if fullCfg != nil && fullCfg.Trader != nil {
 			// Check strategy
 			if fullCfg.Strategy == nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Trader has no strategy configured, please create a strategy in Strategy Studio and associate it with the trader"})
+				respondError(c, http.StatusBadRequest, ErrCodeStrategyRequired, "Trader has no strategy configured, please create a strategy in Strategy Studio and associate it with the trader")
 				return
 			}
 			// Check AI model
 			if fullCfg.AIModel == nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Trader's AI model does not exist, please check AI model configuration"})
+				respondError(c, http.StatusBadRequest, ErrCodeModelNotFound, "Trader's AI model does not exist, please check AI model configuration")
 				return
 			}
 			if !fullCfg.AIModel.Enabled {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Trader's AI model is not enabled, please enable the AI model first"})
+				respondError(c, http.StatusBadRequest, ErrCodeModelDisabled, "Trader's AI model is not enabled, please enable the AI model first")
 				return
 			}
 			// Check exchange
 			if fullCfg.Exchange == nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Trader's exchange does not exist, please check exchange configuration"})
+				respondError(c, http.StatusBadRequest, ErrCodeExchangeNotFound, "Trader's exchange does not exist, please check exchange configuration")
 				return
 			}
 			if !fullCfg.Exchange.Enabled {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Trader's exchange is not enabled, please enable the exchange first"})
+				respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Trader's exchange is not enabled, please enable the exchange first")
 				return
 			}
 		}
 		// Check if there's a specific load error
 		if loadErr := s.traderManager.GetLoadError(traderID); loadErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load trader: " + loadErr.Error()})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to load trader: "+loadErr.Error())
 			return
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "Failed to load trader, please check AI model, exchange and strategy configuration"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotLoaded, "Failed to load trader, please check AI model, exchange and strategy configuration")
 		return
 	}
 
@@ -989,32 +1264,52 @@ func (s *Server) handleStartTrader(c *gin.Context) {
 }
 
 // handleStopTrader Stop trader
+// Optional body {"flatten": true|false} overrides the trader's flatten_on_stop setting for this stop.
 func (s *Server) handleStopTrader(c *gin.Context) {
 	userID := c.GetString("user_id")
 	traderID := c.Param("id")
 
+	var req struct {
+		Flatten *bool `json:"flatten"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			SafeBadRequest(c, "Invalid request parameters")
+			return
+		}
+	}
+
 	// Verify trader belongs to current user
-	_, err := s.store.Trader().GetFullConfig(userID, traderID)
+	fullCfg, err := s.store.Trader().GetFullConfig(userID, traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
 		return
 	}
+	flatten := fullCfg.Trader != nil && fullCfg.Trader.FlattenOnStop
+	if req.Flatten != nil {
+		flatten = *req.Flatten
+	}
 
 	trader, err := s.traderManager.GetTrader(traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
 		return
 	}
 
 	// Check if trader is running
 	status := trader.GetStatus()
 	if isRunning, ok := status["is_running"].(bool); ok && !isRunning {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Trader is already stopped"})
+		respondError(c, http.StatusBadRequest, ErrCodeTraderNotRunning, "Trader is already stopped")
 		return
 	}
 
-	// Stop trader
-	trader.Stop()
+	// Stop trader (and close everything it left open when flattening)
+	var flattenErr error
+	if flatten {
+		flattenErr = trader.StopAndFlatten()
+	} else {
+		trader.Stop()
+	}
 
 	// Update running status in database
 	err = s.store.Trader().UpdateStatus(userID, traderID, false)
@@ -1022,8 +1317,68 @@ func (s *Server) handleStopTrader(c *gin.Context) {
 		logger.Infof("⚠️  Failed to update trader status: %v", err)
 	}
 
+	if flattenErr != nil {
+		logger.Warnf("⚠️  Trader %s stopped, but flattening failed: %v", trader.GetName(), flattenErr)
+		c.JSON(http.StatusOK, gin.H{"message": "Trader stopped", "flattened": false, "flatten_error": flattenErr.Error()})
+		return
+	}
+
 	logger.Infof("⏹  Trader %s stopped", trader.GetName())
-	c.JSON(http.StatusOK, gin.H{"message": "Trader stopped"})
+	c.JSON(http.StatusOK, gin.H{"message": "Trader stopped", "flattened": flatten})
+}
+
+// handleDisableTrader Kill-switch: stop the trader and keep it stopped (also across restarts) until re-enabled
+func (s *Server) handleDisableTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	fullCfg, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	// Persist first so a crash right after stopping can't bring the trader back
+	if err := s.store.Trader().SetDisabled(userID, traderID, true); err != nil {
+		SafeInternalError(c, "Disable trader", err)
+		return
+	}
+
+	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
+		if isRunning, ok := trader.GetStatus()["is_running"].(bool); ok && isRunning {
+			if fullCfg.Trader != nil && fullCfg.Trader.FlattenOnStop {
+				if err := trader.StopAndFlatten(); err != nil {
+					logger.Warnf("⚠️  Trader %s disabled, but flattening failed: %v", traderID, err)
+				}
+			} else {
+				trader.Stop()
+			}
+		}
+	}
+
+	logger.Infof("🛑 Trader %s disabled (kill-switch)", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Trader disabled"})
+}
+
+// handleEnableTrader Clear the kill-switch; the trader stays stopped until started explicitly
+func (s *Server) handleEnableTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Verify trader belongs to current user
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	if err := s.store.Trader().SetDisabled(userID, traderID, false); err != nil {
+		SafeInternalError(c, "Enable trader", err)
+		return
+	}
+
+	logger.Infof("✓ Trader %s enabled", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Trader enabled"})
 }
 
 // handleUpdateTraderPrompt Update trader custom prompt
@@ -1097,16 +1452,36 @@ func (s *Server) handleToggleCompetition(c *gin.Context) {
 }
 
 // handleSyncBalance Sync exchange balance to initial_balance (Option B: Manual Sync + Option C: Smart Detection)
+// mode=reset (default, BALANCE_SYNC_MODE) moves initial_balance to the current equity; mode=adjust keeps it and records
+// the equity change not explained by trading as a deposit/withdrawal (optional body: {"amount": 500, "note": "..."})
+// Query params: force=true to apply a change beyond BALANCE_SYNC_MAX_CHANGE_PCT (otherwise 409 with the detected change)
 func (s *Server) handleSyncBalance(c *gin.Context) {
 	userID := c.GetString("user_id")
 	traderID := c.Param("id")
+	force := c.Query("force") == "true"
+	mode := c.DefaultQuery("mode", config.Get().BalanceSyncMode)
+	if mode != "reset" && mode != "adjust" {
+		SafeBadRequest(c, "mode must be reset or adjust")
+		return
+	}
+
+	var adjustReq struct {
+		Amount *float64 `json:"amount"` // + deposit, - withdrawal (estimated when omitted)
+		Note   string   `json:"note"`
+	}
+	if mode == "adjust" && c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&adjustReq); err != nil {
+			SafeBadRequest(c, "Invalid request body")
+			return
+		}
+	}
 
-	logger.Infof("🔄 User %s requested balance sync for trader %s", userID, traderID)
+	logger.Infof("🔄 User %s requested balance sync for trader %s (mode: %s)", userID, traderID, mode)
 
 	// Get trader configuration from database (including exchange info)
 	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
 		return
 	}
 
@@ -1114,67 +1489,16 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	exchangeCfg := fullConfig.Exchange
 
 	if exchangeCfg == nil || !exchangeCfg.Enabled {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Exchange not configured or not enabled"})
+		respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
 		return
 	}
 
 	// Create temporary trader to query balance
-	var tempTrader trader.Trader
-	var createErr error
-
-	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
-	// Convert EncryptedString fields to string
-	switch exchangeCfg.ExchangeType {
-	case "binance":
-		tempTrader = trader.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
-	case "hyperliquid":
-		tempTrader, createErr = trader.NewHyperliquidTrader(
-			string(exchangeCfg.APIKey),
-			exchangeCfg.HyperliquidWalletAddr,
-			exchangeCfg.Testnet,
-		)
-	case "aster":
-		tempTrader, createErr = trader.NewAsterTrader(
-			exchangeCfg.AsterUser,
-			exchangeCfg.AsterSigner,
-			string(exchangeCfg.AsterPrivateKey),
-		)
-	case "bybit":
-		tempTrader = trader.NewBybitTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-		)
-	case "okx":
-		tempTrader = trader.NewOKXTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-			string(exchangeCfg.Passphrase),
-		)
-	case "bitget":
-		tempTrader = trader.NewBitgetTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-			string(exchangeCfg.Passphrase),
-		)
-	case "lighter":
-		if exchangeCfg.LighterWalletAddr != "" && string(exchangeCfg.LighterAPIKeyPrivateKey) != "" {
-			// Lighter only supports mainnet
-			tempTrader, createErr = trader.NewLighterTraderV2(
-				exchangeCfg.LighterWalletAddr,
-				string(exchangeCfg.LighterAPIKeyPrivateKey),
-				exchangeCfg.LighterAPIKeyIndex,
-				false, // Always use mainnet for Lighter
-			)
-		} else {
-			createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
-		}
-	case "gateio":
-		tempTrader = trader.NewGateTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey))
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange type"})
+	tempTrader, createErr := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if errors.Is(createErr, trader.ErrUnsupportedExchange) {
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, "Unsupported exchange type")
 		return
 	}
-
 	if createErr != nil {
 		logger.Infof("⚠️ Failed to create temporary trader: %v", createErr)
 		SafeInternalError(c, "Failed to connect to exchange", createErr)
@@ -1190,24 +1514,19 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	}
 
 	// Extract total equity (for P&L calculation, we need total account value, not available balance)
-	var actualBalance float64
-	// Priority: total_equity > totalWalletBalance > wallet_balance > totalEq > balance
-	balanceKeys := []string{"total_equity", "totalWalletBalance", "wallet_balance", "totalEq", "balance"}
-	for _, key := range balanceKeys {
-		if balance, ok := balanceInfo[key].(float64); ok && balance > 0 {
-			actualBalance = balance
-			break
-		}
-	}
+	actualBalance := extractTotalEquity(balanceInfo)
 	if actualBalance <= 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to get total equity"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Unable to get total equity")
 		return
 	}
 
 	oldBalance := traderConfig.InitialBalance
 
 	// ✅ Option C: Smart balance change detection
-	changePercent := ((actualBalance - oldBalance) / oldBalance) * 100
+	var changePercent float64
+	if oldBalance > 0 {
+		changePercent = ((actualBalance - oldBalance) / oldBalance) * 100
+	}
 	changeType := "increase"
 	if changePercent < 0 {
 		changeType = "decrease"
@@ -1216,14 +1535,48 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	logger.Infof("✓ Queried actual exchange balance: %.2f USDT (current config: %.2f USDT, change: %.2f%%)",
 		actualBalance, oldBalance, changePercent)
 
+	// Implausible jumps (API returning a transient value) need explicit confirmation, initial_balance drives all PnL math
+	if anomaly := trader.CheckBalanceChange(oldBalance, actualBalance, config.Get().BalanceSyncMaxChangePct); anomaly != nil && !force {
+		logger.Warnf("⚠️ Balance sync for trader %s rejected: %v", traderID, anomaly)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":                 "Balance change exceeds the allowed threshold, retry with force=true to confirm",
+			"code":                  ErrCodeBalanceConfirmation,
+			"requires_confirmation": true,
+			"old_balance":           anomaly.OldBalance,
+			"new_balance":           anomaly.NewBalance,
+			"change_percent":        anomaly.ChangePercent,
+			"max_change_percent":    anomaly.MaxChangePct,
+			"change_type":           changeType,
+		})
+		return
+	}
+
+	if mode == "adjust" {
+		s.recordBalanceAdjustment(c, traderID, balanceInfo, actualBalance, oldBalance, adjustReq.Amount, adjustReq.Note)
+		return
+	}
+
 	// Update initial_balance in database
 	err = s.store.Trader().UpdateInitialBalance(userID, traderID, actualBalance)
 	if err != nil {
 		logger.Infof("❌ Failed to update initial_balance: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update balance"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to update balance")
 		return
 	}
 
+	// The new initial_balance already contains earlier deposits/withdrawals: offset them, and mark the
+	// point before which exchange transfers must not be imported again
+	if contributions, err := s.store.BalanceAdjustment().NetContributions(traderID); err != nil {
+		logger.Warnf("⚠️ Failed to get balance adjustments of trader %s: %v", traderID, err)
+	} else if err := s.store.BalanceAdjustment().Create(&store.BalanceAdjustment{
+		TraderID: traderID,
+		Amount:   -contributions,
+		Equity:   actualBalance,
+		Source:   store.BalanceAdjustmentReset,
+	}); err != nil {
+		logger.Warnf("⚠️ Failed to record balance reset of trader %s: %v", traderID, err)
+	}
+
 	// Reload traders into memory
 	err = s.traderManager.LoadUserTradersFromStore(s.store, userID)
 	if err != nil {
@@ -1241,6 +1594,111 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	})
 }
 
+// recordBalanceAdjustment records a deposit/withdrawal for handleSyncBalance in adjust mode, keeping initial_balance.
+// Without an explicit amount the adjustment is the equity change not explained by realized + unrealized PnL.
+func (s *Server) recordBalanceAdjustment(c *gin.Context, traderID string, balanceInfo map[string]interface{}, equity, initialBalance float64, amount *float64, note string) {
+	contributions, err := s.store.BalanceAdjustment().NetContributions(traderID)
+	if err != nil {
+		SafeInternalError(c, "Failed to get balance adjustments", err)
+		return
+	}
+
+	source := store.BalanceAdjustmentManual
+	if amount == nil {
+		realizedPnL, err := s.realizedSinceBalanceSet(traderID)
+		if err != nil {
+			SafeInternalError(c, "Failed to get realized PnL", err)
+			return
+		}
+		unrealizedPnL, _ := balanceInfo["totalUnrealizedProfit"].(float64)
+		estimated := trader.EstimateBalanceAdjustment(equity, initialBalance, contributions, realizedPnL, unrealizedPnL)
+		amount = &estimated
+		source = store.BalanceAdjustmentSync
+	}
+
+	// Sub-cent differences are rounding noise, not funding
+	if math.Abs(*amount) < 0.01 {
+		c.JSON(http.StatusOK, gin.H{
+			"message":           "No deposit or withdrawal detected",
+			"mode":              "adjust",
+			"initial_balance":   initialBalance,
+			"net_contributions": contributions,
+			"total_equity":      equity,
+		})
+		return
+	}
+
+	adj := &store.BalanceAdjustment{
+		TraderID: traderID,
+		Amount:   *amount,
+		Equity:   equity,
+		Source:   source,
+		Note:     note,
+	}
+	if err := s.store.BalanceAdjustment().Create(adj); err != nil {
+		SafeInternalError(c, "Failed to record balance adjustment", err)
+		return
+	}
+
+	logger.Infof("✅ Recorded balance adjustment for trader %s: %+.2f USDT (%s, net contributions %.2f → %.2f)",
+		traderID, adj.Amount, adj.Source, contributions, contributions+adj.Amount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Balance adjustment recorded",
+		"mode":              "adjust",
+		"adjustment":        adj,
+		"initial_balance":   initialBalance,
+		"net_contributions": contributions + adj.Amount,
+		"total_equity":      equity,
+	})
+}
+
+// realizedSinceBalanceSet realized PnL net of fees, plus funding (which moved equity too and must not
+// be mistaken for a deposit/withdrawal), since initial_balance was last set: PnL from before is
+// already part of initial_balance
+func (s *Server) realizedSinceBalanceSet(traderID string) (float64, error) {
+	since, err := s.store.BalanceAdjustment().LastResetTime(traderID)
+	if err != nil {
+		return 0, err
+	}
+	pnl, fee, err := s.store.Position().RealizedSince(traderID, since)
+	if err != nil {
+		return 0, err
+	}
+	funding, err := s.store.FundingPayment().NetFundingSince(traderID, since)
+	if err != nil {
+		return 0, err
+	}
+	return pnl - fee + funding, nil
+}
+
+// handleListBalanceAdjustments List a trader's recorded deposits/withdrawals (oldest first)
+// GET /api/traders/:id/balance-adjustments
+func (s *Server) handleListBalanceAdjustments(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+
+	adjustments, err := s.store.BalanceAdjustment().List(traderID)
+	if err != nil {
+		SafeInternalError(c, "Failed to get balance adjustments", err)
+		return
+	}
+
+	var net float64
+	for _, adj := range adjustments {
+		net += adj.Amount
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"adjustments":       adjustments,
+		"net_contributions": net,
+	})
+}
+
 // handleClosePosition One-click close position
 func (s *Server) handleClosePosition(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -1252,7 +1710,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter error: symbol and side are required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Parameter error: symbol and side are required")
 		return
 	}
 
@@ -1261,74 +1719,23 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	// Get trader configuration from database (including exchange info)
 	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
 		return
 	}
 
 	exchangeCfg := fullConfig.Exchange
 
 	if exchangeCfg == nil || !exchangeCfg.Enabled {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Exchange not configured or not enabled"})
+		respondError(c, http.StatusBadRequest, ErrCodeExchangeDisabled, "Exchange not configured or not enabled")
 		return
 	}
 
 	// Create temporary trader to execute close position
-	var tempTrader trader.Trader
-	var createErr error
-
-	// Use ExchangeType (e.g., "binance") instead of ExchangeID (which is now UUID)
-	// Convert EncryptedString fields to string
-	switch exchangeCfg.ExchangeType {
-	case "binance":
-		tempTrader = trader.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
-	case "hyperliquid":
-		tempTrader, createErr = trader.NewHyperliquidTrader(
-			string(exchangeCfg.APIKey),
-			exchangeCfg.HyperliquidWalletAddr,
-			exchangeCfg.Testnet,
-		)
-	case "aster":
-		tempTrader, createErr = trader.NewAsterTrader(
-			exchangeCfg.AsterUser,
-			exchangeCfg.AsterSigner,
-			string(exchangeCfg.AsterPrivateKey),
-		)
-	case "bybit":
-		tempTrader = trader.NewBybitTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-		)
-	case "okx":
-		tempTrader = trader.NewOKXTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-			string(exchangeCfg.Passphrase),
-		)
-	case "bitget":
-		tempTrader = trader.NewBitgetTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-			string(exchangeCfg.Passphrase),
-		)
-	case "lighter":
-		if exchangeCfg.LighterWalletAddr != "" && string(exchangeCfg.LighterAPIKeyPrivateKey) != "" {
-			// Lighter only supports mainnet
-			tempTrader, createErr = trader.NewLighterTraderV2(
-				exchangeCfg.LighterWalletAddr,
-				string(exchangeCfg.LighterAPIKeyPrivateKey),
-				exchangeCfg.LighterAPIKeyIndex,
-				false, // Always use mainnet for Lighter
-			)
-		} else {
-			createErr = fmt.Errorf("Lighter requires wallet address and API Key private key")
-		}
-	case "gateio":
-		tempTrader = trader.NewGateTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey))
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange type"})
+	tempTrader, createErr := trader.NewTraderFromExchangeConfig(exchangeCfg, userID)
+	if errors.Is(createErr, trader.ErrUnsupportedExchange) {
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, "Unsupported exchange type")
 		return
 	}
-
 	if createErr != nil {
 		logger.Infof("⚠️ Failed to create temporary trader: %v", createErr)
 		SafeInternalError(c, "Failed to connect to exchange", createErr)
@@ -1367,7 +1774,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	} else if req.Side == "SHORT" {
 		result, closeErr = tempTrader.CloseShort(req.Symbol, 0) // 0 means close all
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "side must be LONG or SHORT"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "side must be LONG or SHORT")
 		return
 	}
 
@@ -1394,7 +1801,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 func (s *Server) recordClosePositionOrder(traderID, exchangeID, exchangeType, symbol, side string, quantity, exitPrice float64, result map[string]interface{}) {
 	// Skip for exchanges with OrderSync - let the background sync handle it to avoid duplicates
 	switch exchangeType {
-	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "gateio":
+	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "gateio", "coinbase", "backpack":
 		logger.Infof("  📝 Close order will be synced by OrderSync, skipping immediate record")
 		return
 	}
@@ -1437,8 +1844,12 @@ func (s *Server) recordClosePositionOrder(traderID, exchangeID, exchangeType, sy
 		exitPrice = quantity * 100 // Rough estimate if we don't have price
 	}
 
-	// Estimate fee (0.04% for Lighter taker)
+	// Estimate fee (0.04% taker), charged in the symbol's quote currency
 	fee := exitPrice * quantity * 0.0004
+	feeAsset := market.QuoteAsset(market.Normalize(symbol))
+	if feeAsset == "" {
+		feeAsset = market.QuoteUSDT
+	}
 
 	// Create order record - DIRECTLY as FILLED (Lighter market orders fill immediately)
 	orderRecord := &store.TraderOrder{
@@ -1484,7 +1895,8 @@ func (s *Server) recordClosePositionOrder(traderID, exchangeID, exchangeType, sy
 		Quantity:        quantity,
 		QuoteQuantity:   exitPrice * quantity,
 		Commission:      fee,
-		CommissionAsset: "USDT",
+		CommissionAsset: feeAsset,
+		CommissionUSDT:  fee,
 		RealizedPnL:     0,
 		IsMaker:         false,
 		CreatedAt:       time.Now().UTC().UnixMilli(),
@@ -1501,7 +1913,8 @@ func (s *Server) recordClosePositionOrder(traderID, exchangeID, exchangeType, sy
 func (s *Server) pollAndUpdateOrderStatus(orderRecordID int64, traderID, exchangeID, exchangeType, orderID, symbol, orderAction string, tempTrader trader.Trader) {
 	var actualPrice float64
 	var actualQty float64
-	var fee float64
+	var fee, feeQuote float64
+	var feeAsset string
 
 	// Wait a bit for order to be filled
 	time.Sleep(500 * time.Millisecond)
@@ -1531,15 +1944,13 @@ func (s *Server) pollAndUpdateOrderStatus(orderRecordID int64, traderID, exchang
 				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
 					actualQty = execQty
 				}
-				// Get commission/fee
-				if commission, ok := status["commission"].(float64); ok {
-					fee = commission
-				}
+				// Get commission/fee, valued in the quote currency when charged in another asset
+				fee, feeAsset, feeQuote = trader.OrderStatusCommission(tempTrader, symbol, status, actualPrice)
 
-				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f %s", actualPrice, actualQty, fee, feeAsset)
 
 				// Update order status to FILLED
-				if err := s.store.Order().UpdateOrderStatus(orderRecordID, "FILLED", actualQty, actualPrice, fee); err != nil {
+				if err := s.store.Order().UpdateOrderStatus(orderRecordID, "FILLED", actualQty, actualPrice, feeQuote); err != nil {
 					logger.Infof("  ⚠️ Failed to update order status: %v", err)
 					return
 				}
@@ -1559,7 +1970,8 @@ func (s *Server) pollAndUpdateOrderStatus(orderRecordID int64, traderID, exchang
 					Quantity:        actualQty,
 					QuoteQuantity:   actualPrice * actualQty,
 					Commission:      fee,
-					CommissionAsset: "USDT",
+					CommissionAsset: feeAsset,
+					CommissionUSDT:  feeQuote,
 					RealizedPnL:     0,
 					IsMaker:         false,
 					CreatedAt:       time.Now().UTC().UnixMilli(),
@@ -1657,7 +2069,7 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 	// Read raw request body
 	bodyBytes, err := c.GetRawData()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Failed to read request body")
 		return
 	}
 
@@ -1668,7 +2080,7 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 		// Transport encryption disabled, accept plain JSON
 		if err := json.Unmarshal(bodyBytes, &req); err != nil {
 			logger.Infof("❌ Failed to parse plain JSON request: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request format")
 			return
 		}
 		logger.Infof("📝 Received plain text model config (UserID: %s)", userID)
@@ -1677,7 +2089,7 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 		var encryptedPayload crypto.EncryptedPayload
 		if err := json.Unmarshal(bodyBytes, &encryptedPayload); err != nil {
 			logger.Infof("❌ Failed to parse encrypted payload: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format, encrypted transmission required"})
+			respondError(c, http.StatusBadRequest, ErrCodeEncryptionRequired, "Invalid request format, encrypted transmission required")
 			return
 		}
 
@@ -1686,7 +2098,7 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 			logger.Infof("❌ Detected unencrypted request (UserID: %s)", userID)
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   "This endpoint only supports encrypted transmission, please use encrypted client",
-				"code":    "ENCRYPTION_REQUIRED",
+				"code":    ErrCodeEncryptionRequired,
 				"message": "Encrypted transmission is required for security reasons",
 			})
 			return
@@ -1696,14 +2108,14 @@ func (s *Server) handleUpdateModelConfigs(c *gin.Context) {
 		decrypted, err := s.cryptoHandler.cryptoService.DecryptSensitiveData(&encryptedPayload)
 		if err != nil {
 			logger.Infof("❌ Failed to decrypt model config (UserID: %s): %v", userID, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to decrypt data"})
+			respondError(c, http.StatusBadRequest, ErrCodeDecryptionFailed, "Failed to decrypt data")
 			return
 		}
 
 		// Parse decrypted data
 		if err := json.Unmarshal([]byte(decrypted), &req); err != nil {
 			logger.Infof("❌ Failed to parse decrypted data: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse decrypted data"})
+			respondError(c, http.StatusBadRequest, ErrCodeDecryptionFailed, "Failed to parse decrypted data")
 			return
 		}
 		logger.Infof("🔓 Decrypted model config data (UserID: %s)", userID)
@@ -1763,6 +2175,10 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 			AsterUser:             exchange.AsterUser,
 			AsterSigner:           exchange.AsterSigner,
 			LighterWalletAddr:     exchange.LighterWalletAddr,
+			ExtraAPIKeyCount:      len(exchange.AdditionalAPIKeys()),
+		}
+		if slippage := exchange.SlippageSettings(); !slippage.IsZero() {
+			safeExchanges[i].Slippage = &slippage
 		}
 	}
 
@@ -1772,61 +2188,32 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 // handleUpdateExchangeConfigs Update exchange configurations (supports both encrypted and plain text based on config)
 func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 	userID := c.GetString("user_id")
-	cfg := config.Get()
 
-	// Read raw request body
-	bodyBytes, err := c.GetRawData()
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+	var req UpdateExchangeConfigRequest
+	if !s.bindSensitiveJSON(c, &req) {
 		return
 	}
 
-	var req UpdateExchangeConfigRequest
-
-	// Check if transport encryption is enabled
-	if !cfg.TransportEncryption {
-		// Transport encryption disabled, accept plain JSON
-		if err := json.Unmarshal(bodyBytes, &req); err != nil {
-			logger.Infof("❌ Failed to parse plain JSON request: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
-			return
-		}
-		logger.Infof("📝 Received plain text exchange config (UserID: %s)", userID)
-	} else {
-		// Transport encryption enabled, require encrypted payload
-		var encryptedPayload crypto.EncryptedPayload
-		if err := json.Unmarshal(bodyBytes, &encryptedPayload); err != nil {
-			logger.Infof("❌ Failed to parse encrypted payload: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format, encrypted transmission required"})
-			return
+	for _, exchangeData := range req.Exchanges {
+		if exchangeData.Slippage != nil {
+			if err := trader.ValidateSlippage(*exchangeData.Slippage); err != nil {
+				SafeBadRequest(c, err.Error())
+				return
+			}
 		}
-
-		// Verify encrypted data
-		if encryptedPayload.WrappedKey == "" {
-			logger.Infof("❌ Detected unencrypted request (UserID: %s)", userID)
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "This endpoint only supports encrypted transmission, please use encrypted client",
-				"code":    "ENCRYPTION_REQUIRED",
-				"message": "Encrypted transmission is required for security reasons",
-			})
-			return
+		if exchangeData.ExtraAPIKeys == nil {
+			continue
 		}
-
-		// Decrypt data
-		decrypted, err := s.cryptoHandler.cryptoService.DecryptSensitiveData(&encryptedPayload)
-		if err != nil {
-			logger.Infof("❌ Failed to decrypt exchange config (UserID: %s): %v", userID, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to decrypt data"})
+		if len(*exchangeData.ExtraAPIKeys) > maxExtraAPIKeys {
+			SafeBadRequest(c, fmt.Sprintf("At most %d additional API keys per exchange account", maxExtraAPIKeys))
 			return
 		}
-
-		// Parse decrypted data
-		if err := json.Unmarshal([]byte(decrypted), &req); err != nil {
-			logger.Infof("❌ Failed to parse decrypted data: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse decrypted data"})
-			return
+		for _, key := range *exchangeData.ExtraAPIKeys {
+			if key.APIKey == "" || key.SecretKey == "" {
+				SafeBadRequest(c, "Additional API keys need both api_key and secret_key")
+				return
+			}
 		}
-		logger.Infof("🔓 Decrypted exchange config data (UserID: %s)", userID)
 	}
 
 	// Update each exchange's configuration
@@ -1836,10 +2223,22 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 			SafeInternalError(c, fmt.Sprintf("Update exchange %s", exchangeID), err)
 			return
 		}
+		if exchangeData.ExtraAPIKeys != nil {
+			if err := s.store.Exchange().SetAdditionalAPIKeys(userID, exchangeID, *exchangeData.ExtraAPIKeys); err != nil {
+				SafeInternalError(c, fmt.Sprintf("Update additional API keys of exchange %s", exchangeID), err)
+				return
+			}
+		}
+		if exchangeData.Slippage != nil {
+			if err := s.store.Exchange().SetSlippage(userID, exchangeID, *exchangeData.Slippage); err != nil {
+				SafeInternalError(c, fmt.Sprintf("Update slippage of exchange %s", exchangeID), err)
+				return
+			}
+		}
 	}
 
 	// Reload all traders for this user to make new config take effect immediately
-	err = s.traderManager.LoadUserTradersFromStore(s.store, userID)
+	err := s.traderManager.LoadUserTradersFromStore(s.store, userID)
 	if err != nil {
 		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
 		// Don't return error here since exchange config was successfully updated to database
@@ -1871,61 +2270,15 @@ type CreateExchangeRequest struct {
 // handleCreateExchange Create a new exchange account
 func (s *Server) handleCreateExchange(c *gin.Context) {
 	userID := c.GetString("user_id")
-	cfg := config.Get()
-
-	// Read raw request body
-	bodyBytes, err := c.GetRawData()
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
-		return
-	}
 
 	var req CreateExchangeRequest
-
-	// Check if transport encryption is enabled
-	if !cfg.TransportEncryption {
-		// Transport encryption disabled, accept plain JSON
-		if err := json.Unmarshal(bodyBytes, &req); err != nil {
-			logger.Infof("❌ Failed to parse plain JSON request: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
-			return
-		}
-	} else {
-		// Transport encryption enabled, require encrypted payload
-		var encryptedPayload crypto.EncryptedPayload
-		if err := json.Unmarshal(bodyBytes, &encryptedPayload); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format, encrypted transmission required"})
-			return
-		}
-
-		if encryptedPayload.WrappedKey == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "This endpoint only supports encrypted transmission",
-				"code":    "ENCRYPTION_REQUIRED",
-				"message": "Encrypted transmission is required for security reasons",
-			})
-			return
-		}
-
-		decrypted, err := s.cryptoHandler.cryptoService.DecryptSensitiveData(&encryptedPayload)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to decrypt data"})
-			return
-		}
-
-		if err := json.Unmarshal([]byte(decrypted), &req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse decrypted data"})
-			return
-		}
+	if !s.bindSensitiveJSON(c, &req) {
+		return
 	}
 
 	// Validate exchange type
-	validTypes := map[string]bool{
-		"binance": true, "bybit": true, "okx": true, "bitget": true,
-		"hyperliquid": true, "aster": true, "lighter": true, "gateio": true,
-	}
-	if !validTypes[req.ExchangeType] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid exchange type: %s", req.ExchangeType)})
+	if !trader.IsSupportedExchange(req.ExchangeType) {
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, fmt.Sprintf("Invalid exchange type: %s", req.ExchangeType))
 		return
 	}
 
@@ -1958,21 +2311,22 @@ func (s *Server) handleDeleteExchange(c *gin.Context) {
 	exchangeID := c.Param("id")
 
 	if exchangeID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Exchange ID is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Exchange ID is required")
 		return
 	}
 
 	// Check if any traders are using this exchange
 	traders, err := s.store.Trader().List(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check traders"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to check traders")
 		return
 	}
 
 	for _, trader := range traders {
-		if trader.ExchangeID == exchangeID {
+		if trader.ExchangeID == exchangeID || mirrorsExchange(trader, exchangeID) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":       "Cannot delete exchange account that is in use by traders",
+				"code":        ErrCodeExchangeInUse,
 				"trader_id":   trader.ID,
 				"trader_name": trader.Name,
 			})
@@ -2028,6 +2382,8 @@ func (s *Server) handleTraderList(c *gin.Context) {
 			"ai_model":            trader.AIModelID, // Use complete ID
 			"exchange_id":         trader.ExchangeID,
 			"is_running":          isRunning,
+			"disabled":            trader.Disabled,
+			"flatten_on_stop":     trader.FlattenOnStop,
 			"show_in_competition": trader.ShowInCompetition,
 			"initial_balance":     trader.InitialBalance,
 			"strategy_id":         trader.StrategyID,
@@ -2044,7 +2400,7 @@ func (s *Server) handleGetTraderConfig(c *gin.Context) {
 	traderID := c.Param("id")
 
 	if traderID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Trader ID cannot be empty"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Trader ID cannot be empty")
 		return
 	}
 
@@ -2068,22 +2424,32 @@ func (s *Server) handleGetTraderConfig(c *gin.Context) {
 	aiModelID := traderConfig.AIModelID
 
 	result := map[string]interface{}{
-		"trader_id":             traderConfig.ID,
-		"trader_name":           traderConfig.Name,
-		"ai_model":              aiModelID,
-		"exchange_id":           traderConfig.ExchangeID,
-		"strategy_id":           traderConfig.StrategyID,
-		"initial_balance":       traderConfig.InitialBalance,
-		"scan_interval_minutes": traderConfig.ScanIntervalMinutes,
-		"btc_eth_leverage":      traderConfig.BTCETHLeverage,
-		"altcoin_leverage":      traderConfig.AltcoinLeverage,
-		"trading_symbols":       traderConfig.TradingSymbols,
-		"custom_prompt":         traderConfig.CustomPrompt,
-		"override_base_prompt":  traderConfig.OverrideBasePrompt,
-		"is_cross_margin":       traderConfig.IsCrossMargin,
-		"use_ai500":             traderConfig.UseAI500,
-		"use_oi_top":            traderConfig.UseOITop,
-		"is_running":            isRunning,
+		"trader_id":              traderConfig.ID,
+		"trader_name":            traderConfig.Name,
+		"ai_model":               aiModelID,
+		"exchange_id":            traderConfig.ExchangeID,
+		"strategy_id":            traderConfig.StrategyID,
+		"initial_balance":        traderConfig.InitialBalance,
+		"scan_interval_minutes":  traderConfig.ScanIntervalMinutes,
+		"btc_eth_leverage":       traderConfig.BTCETHLeverage,
+		"altcoin_leverage":       traderConfig.AltcoinLeverage,
+		"trading_symbols":        traderConfig.TradingSymbols,
+		"custom_prompt":          traderConfig.CustomPrompt,
+		"override_base_prompt":   traderConfig.OverrideBasePrompt,
+		"is_cross_margin":        traderConfig.IsCrossMargin,
+		"quote_asset":            traderConfig.QuoteAsset,
+		"use_ai500":              traderConfig.UseAI500,
+		"use_oi_top":             traderConfig.UseOITop,
+		"is_running":             isRunning,
+		"disabled":               traderConfig.Disabled,
+		"flatten_on_stop":        traderConfig.FlattenOnStop,
+		"prompt_language":        traderConfig.PromptLanguage,
+		"consensus_models":       traderConfig.ConsensusModels,
+		"consensus_threshold":    traderConfig.ConsensusThreshold,
+		"ai_request_timeout_sec": traderConfig.AIRequestTimeoutSec,
+		"display_decimals":       traderConfig.DisplayDecimals,
+		"mirror_exchange_ids":    traderConfig.MirrorExchangeIDs,
+		"log_level":              traderConfig.LogLevel,
 	}
 
 	c.JSON(http.StatusOK, result)
@@ -2184,7 +2550,7 @@ func (s *Server) handlePositionHistory(c *gin.Context) {
 	// Get store
 	store := trader.GetStore()
 	if store == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Store not available")
 		return
 	}
 
@@ -2234,15 +2600,15 @@ func (s *Server) handleTrades(c *gin.Context) {
 		limit = l
 	}
 
-	// Normalize symbol (add USDT suffix if not present)
+	// Normalize symbol for the trader's asset class (crypto gets a USDT suffix if not present)
 	if symbol != "" {
-		symbol = market.Normalize(symbol)
+		symbol = market.NormalizeForExchange(symbol, trader.GetExchange(), "")
 	}
 
 	// Get trades from store
 	store := trader.GetStore()
 	if store == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Store not available")
 		return
 	}
 
@@ -2290,15 +2656,15 @@ func (s *Server) handleOrders(c *gin.Context) {
 		limit = l
 	}
 
-	// Normalize symbol (add USDT suffix if not present)
+	// Normalize symbol for the trader's asset class (crypto gets a USDT suffix if not present)
 	if symbol != "" {
-		symbol = market.Normalize(symbol)
+		symbol = market.NormalizeForExchange(symbol, trader.GetExchange(), "")
 	}
 
 	// Get orders from store
 	store := trader.GetStore()
 	if store == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Store not available")
 		return
 	}
 
@@ -2317,7 +2683,7 @@ func (s *Server) handleOrderFills(c *gin.Context) {
 	orderIDStr := c.Param("id")
 	orderID, err := strconv.ParseInt(orderIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid order ID")
 		return
 	}
 
@@ -2335,7 +2701,7 @@ func (s *Server) handleOrderFills(c *gin.Context) {
 
 	store := trader.GetStore()
 	if store == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Store not available"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Store not available")
 		return
 	}
 
@@ -2366,12 +2732,12 @@ func (s *Server) handleOpenOrders(c *gin.Context) {
 	// Get symbol parameter (required for exchange query)
 	symbol := c.Query("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol parameter is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol parameter is required")
 		return
 	}
 
-	// Normalize symbol
-	symbol = market.Normalize(symbol)
+	// Normalize symbol for the trader's asset class
+	symbol = market.NormalizeForExchange(symbol, trader.GetExchange(), "")
 
 	// Get open orders from exchange
 	openOrders, err := trader.GetOpenOrders(symbol)
@@ -2383,12 +2749,43 @@ func (s *Server) handleOpenOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, openOrders)
 }
 
+// handleTraderOpenOrdersAll open orders of a trader across all symbols
+func (s *Server) handleTraderOpenOrdersAll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().GetFullConfig(userID, traderID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+
+	trader, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	result, err := trader.GetAllOpenOrders()
+	if err != nil {
+		SafeInternalError(c, "Get open orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"orders":    result.Orders,
+		"count":     len(result.Orders),
+		"symbols":   result.Symbols,
+		"errors":    result.Errors,
+	})
+}
+
 // handleKlines K-line data (supports multiple exchanges via coinank)
 func (s *Server) handleKlines(c *gin.Context) {
 	// Get query parameters
 	symbol := c.Query("symbol")
 	if symbol == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "symbol parameter is required"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "symbol parameter is required")
 		return
 	}
 
@@ -2412,6 +2809,7 @@ func (s *Server) handleKlines(c *gin.Context) {
 	switch exchangeLower {
 	case "alpaca":
 		// US Stocks via Alpaca
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, "")
 		klines, err = s.getKlinesFromAlpaca(symbol, interval, limit)
 		if err != nil {
 			SafeInternalError(c, "Get klines from Alpaca", err)
@@ -2419,6 +2817,7 @@ func (s *Server) handleKlines(c *gin.Context) {
 		}
 	case "forex", "metals":
 		// Forex and Metals via Twelve Data
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, "")
 		klines, err = s.getKlinesFromTwelveData(symbol, interval, limit)
 		if err != nil {
 			SafeInternalError(c, "Get klines from TwelveData", err)
@@ -2431,9 +2830,61 @@ func (s *Server) handleKlines(c *gin.Context) {
 			SafeInternalError(c, "Get klines from Hyperliquid", err)
 			return
 		}
+	case "gateio", "gate":
+		// Gate.io native futures API (CoinAnk doesn't cover Gate.io)
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, c.Query("quote"))
+		if _, err := gateio.MapTimeframe(interval); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		klines, err = s.getKlinesFromGate(symbol, interval, limit)
+		if err != nil {
+			logger.Warnf("⚠️ Gate.io klines failed for %s: %v", symbol, err)
+			klines, err = s.getKlinesBinanceFallback(symbol, interval, exchange, limit)
+		}
+		if err != nil {
+			SafeInternalError(c, "Get klines from Gate.io", err)
+			return
+		}
+	case "coinbase":
+		// Coinbase International native perpetuals API (CoinAnk doesn't cover Coinbase)
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, c.Query("quote"))
+		klines, err = s.getKlinesFromCoinbase(symbol, interval, limit)
+		if err != nil {
+			logger.Warnf("⚠️ Coinbase klines failed for %s: %v", symbol, err)
+			klines, err = s.getKlinesBinanceFallback(symbol, interval, exchange, limit)
+		}
+		if err != nil {
+			SafeInternalError(c, "Get klines from Coinbase", err)
+			return
+		}
+	case "backpack":
+		// Backpack native perpetuals API (CoinAnk doesn't cover Backpack)
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, c.Query("quote"))
+		klines, err = s.getKlinesFromBackpack(symbol, interval, limit)
+		if err != nil {
+			logger.Warnf("⚠️ Backpack klines failed for %s: %v", symbol, err)
+			klines, err = s.getKlinesBinanceFallback(symbol, interval, exchange, limit)
+		}
+		if err != nil {
+			SafeInternalError(c, "Get klines from Backpack", err)
+			return
+		}
+	case "aster":
+		// Aster native futures API
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, c.Query("quote"))
+		klines, err = s.getKlinesFromAster(symbol, interval, limit)
+		if err != nil {
+			logger.Warnf("⚠️ Aster klines failed for %s: %v", symbol, err)
+			klines, err = s.getKlinesBinanceFallback(symbol, interval, exchange, limit)
+		}
+		if err != nil {
+			SafeInternalError(c, "Get klines from Aster", err)
+			return
+		}
 	default:
-		// Crypto exchanges via CoinAnk
-		symbol = market.Normalize(symbol)
+		// Crypto exchanges via CoinAnk (optional quote=USDC for USDC-quoted perps)
+		symbol = market.NormalizeForExchange(symbol, exchangeLower, c.Query("quote"))
 		klines, err = s.getKlinesFromCoinank(symbol, interval, exchange, limit)
 		if err != nil {
 			SafeInternalError(c, "Get klines from CoinAnk", err)
@@ -2461,6 +2912,7 @@ func (s *Server) getKlinesFromCoinank(symbol, interval, exchange string, limit i
 		coinankExchange = coinank_enum.Aster
 	case "lighter":
 		// Lighter doesn't have direct CoinAnk support, use Binance data as fallback
+		logger.Warnf("⚠️ No native klines for %s, using Binance data (not native to the exchange)", exchange)
 		coinankExchange = coinank_enum.Binance
 	default:
 		// For any unknown exchange, default to Binance
@@ -2516,13 +2968,13 @@ func (s *Server) getKlinesFromCoinank(symbol, interval, exchange string, limit i
 	}
 
 	// Convert symbol format for different exchanges
-	// OKX uses "BTC-USDT-SWAP" format instead of "BTCUSDT"
+	// OKX uses "BTC-USDT-SWAP" / "BTC-USDC-SWAP" format instead of "BTCUSDT"
 	apiSymbol := symbol
 	if coinankExchange == coinank_enum.Okex {
-		// Convert BTCUSDT -> BTC-USDT-SWAP
-		if strings.HasSuffix(symbol, "USDT") {
-			base := strings.TrimSuffix(symbol, "USDT")
-			apiSymbol = fmt.Sprintf("%s-USDT-SWAP", base)
+		// Convert BTCUSDT -> BTC-USDT-SWAP, BTCUSDC -> BTC-USDC-SWAP
+		if quote := market.QuoteAsset(symbol); quote != "" {
+			base := strings.TrimSuffix(symbol, quote)
+			apiSymbol = fmt.Sprintf("%s-%s-SWAP", base, quote)
 		}
 	}
 
@@ -2535,7 +2987,7 @@ func (s *Server) getKlinesFromCoinank(symbol, interval, exchange string, limit i
 		// Free API doesn't support all exchanges (e.g., OKX, Bitget)
 		// Fallback to Binance data as reference
 		if coinankExchange != coinank_enum.Binance {
-			logger.Warnf("⚠️ CoinAnk free API doesn't support %s, falling back to Binance data", coinankExchange)
+			logger.Warnf("⚠️ CoinAnk free API doesn't support %s, falling back to Binance data (not native to the exchange)", coinankExchange)
 			coinankKlines, err = coinank_api.Kline(ctx, symbol, coinank_enum.Binance, ts, coinank_enum.To, limit, coinankInterval)
 			if err != nil {
 				return nil, fmt.Errorf("coinank API error (fallback): %w", err)
@@ -2564,6 +3016,128 @@ func (s *Server) getKlinesFromCoinank(symbol, interval, exchange string, limit i
 	return klines, nil
 }
 
+// getKlinesBinanceFallback last-resort Binance klines (via CoinAnk) when an exchange's native source fails
+func (s *Server) getKlinesBinanceFallback(symbol, interval, exchange string, limit int) ([]market.Kline, error) {
+	logger.Warnf("⚠️ Falling back to Binance klines for %s on %s (data is not native to the exchange)", symbol, exchange)
+	return s.getKlinesFromCoinank(symbol, interval, "binance", limit)
+}
+
+// Shared public market data clients (reuse connections and the contract multiplier cache)
+var (
+	gateKlineClient  = sync.OnceValue(gateio.NewClient)
+	asterKlineClient = sync.OnceValue(aster.NewClient)
+)
+
+// getKlinesFromGate fetches kline data from Gate.io futures public API
+func (s *Server) getKlinesFromGate(symbol, interval string, limit int) ([]market.Kline, error) {
+	timeframe, err := gateio.MapTimeframe(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	candles, err := gateKlineClient().GetCandles(ctx, symbol, timeframe, limit)
+	if err != nil {
+		return nil, fmt.Errorf("gate.io API error: %w", err)
+	}
+
+	klines := make([]market.Kline, len(candles))
+	for i, candle := range candles {
+		klines[i] = market.Kline{
+			OpenTime:    candle.OpenTime,
+			Open:        candle.Open,
+			High:        candle.High,
+			Low:         candle.Low,
+			Close:       candle.Close,
+			Volume:      candle.Volume,      // 币数量
+			QuoteVolume: candle.QuoteVolume, // USDT 成交额
+			CloseTime:   candle.CloseTime,
+		}
+	}
+
+	return klines, nil
+}
+
+// getKlinesFromCoinbase fetches kline data from Coinbase International public API
+func (s *Server) getKlinesFromCoinbase(symbol, interval string, limit int) ([]market.Kline, error) {
+	client := coinbase.NewClient()
+
+	ctx := context.Background()
+	candles, err := client.GetCandles(ctx, symbol, coinbase.MapTimeframe(interval), limit)
+	if err != nil {
+		return nil, fmt.Errorf("coinbase API error: %w", err)
+	}
+
+	klines := make([]market.Kline, len(candles))
+	for i, candle := range candles {
+		klines[i] = market.Kline{
+			OpenTime:    candle.OpenTime,
+			Open:        candle.Open,
+			High:        candle.High,
+			Low:         candle.Low,
+			Close:       candle.Close,
+			Volume:      candle.Volume,
+			QuoteVolume: candle.QuoteVolume,
+			CloseTime:   candle.CloseTime,
+		}
+	}
+
+	return klines, nil
+}
+
+// getKlinesFromBackpack fetches kline data from Backpack public API
+func (s *Server) getKlinesFromBackpack(symbol, interval string, limit int) ([]market.Kline, error) {
+	client := backpack.NewClient()
+
+	ctx := context.Background()
+	candles, err := client.GetCandles(ctx, symbol, backpack.MapTimeframe(interval), limit)
+	if err != nil {
+		return nil, fmt.Errorf("backpack API error: %w", err)
+	}
+
+	klines := make([]market.Kline, len(candles))
+	for i, candle := range candles {
+		klines[i] = market.Kline{
+			OpenTime:    candle.OpenTime,
+			Open:        candle.Open,
+			High:        candle.High,
+			Low:         candle.Low,
+			Close:       candle.Close,
+			Volume:      candle.Volume,
+			QuoteVolume: candle.QuoteVolume,
+			CloseTime:   candle.CloseTime,
+		}
+	}
+
+	return klines, nil
+}
+
+// getKlinesFromAster fetches kline data from Aster futures public API
+func (s *Server) getKlinesFromAster(symbol, interval string, limit int) ([]market.Kline, error) {
+	ctx := context.Background()
+	candles, err := asterKlineClient().GetCandles(ctx, symbol, interval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("aster API error: %w", err)
+	}
+
+	klines := make([]market.Kline, len(candles))
+	for i, candle := range candles {
+		klines[i] = market.Kline{
+			OpenTime:    candle.OpenTime,
+			Open:        candle.Open,
+			High:        candle.High,
+			Low:         candle.Low,
+			Close:       candle.Close,
+			Volume:      candle.Volume,      // 币数量
+			QuoteVolume: candle.QuoteVolume, // USDT 成交额
+			CloseTime:   candle.CloseTime,
+			Trades:      candle.Trades,
+		}
+	}
+
+	return klines, nil
+}
+
 // getKlinesFromAlpaca fetches kline data from Alpaca API for US stocks
 func (s *Server) getKlinesFromAlpaca(symbol, interval string, limit int) ([]market.Kline, error) {
 	// Create Alpaca client
@@ -2722,24 +3296,16 @@ func (s *Server) handleSymbols(c *gin.Context) {
 			for symbol := range xyzMids {
 				// Remove xyz: prefix for display
 				displaySymbol := strings.TrimPrefix(symbol, "xyz:")
-				category := "stock"
-				if displaySymbol == "GOLD" || displaySymbol == "SILVER" {
-					category = "commodity"
-				} else if displaySymbol == "EUR" || displaySymbol == "JPY" {
-					category = "forex"
-				} else if displaySymbol == "XYZ100" {
-					category = "index"
-				}
 				symbols = append(symbols, SymbolInfo{
 					Symbol:   displaySymbol,
 					Name:     displaySymbol,
-					Category: category,
+					Category: xyzCategory(displaySymbol),
 				})
 			}
 		}
 
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported exchange for symbol listing"})
+		respondError(c, http.StatusBadRequest, ErrCodeUnsupportedExchange, "Unsupported exchange for symbol listing")
 		return
 	}
 
@@ -2750,7 +3316,7 @@ func (s *Server) handleSymbols(c *gin.Context) {
 	})
 }
 
-// handleDecisions Decision log list
+// handleDecisions Decision log list (with ?symbol=, a page of the decisions that acted on that symbol)
 func (s *Server) handleDecisions(c *gin.Context) {
 	_, traderID, err := s.getTraderFromQuery(c)
 	if err != nil {
@@ -2764,6 +3330,24 @@ func (s *Server) handleDecisions(c *gin.Context) {
 		return
 	}
 
+	if symbol := c.Query("symbol"); symbol != "" {
+		limit := queryInt(c, "limit", 50)
+		if limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		offset := queryInt(c, "offset", 0)
+		if offset < 0 {
+			offset = 0
+		}
+		records, total, err := trader.GetStore().Decision().GetRecordsBySymbol(trader.GetID(), market.Normalize(symbol), limit, offset)
+		if err != nil {
+			SafeInternalError(c, "Get decision log", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": total, "items": records})
+		return
+	}
+
 	// Get all historical decision records (unlimited)
 	records, err := trader.GetStore().Decision().GetLatestRecords(trader.GetID(), 10000)
 	if err != nil {
@@ -2774,6 +3358,33 @@ func (s *Server) handleDecisions(c *gin.Context) {
 	c.JSON(http.StatusOK, records)
 }
 
+// handleGetDecision A single decision record in full, including the cycle's trace ID
+// (grep the logs for it to follow the whole cycle)
+func (s *Server) handleGetDecision(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	decisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || decisionID <= 0 {
+		SafeBadRequest(c, "Invalid decision ID")
+		return
+	}
+
+	record, err := s.store.Decision().GetRecordByID(decisionID)
+	if err != nil {
+		SafeNotFound(c, "Decision")
+		return
+	}
+
+	// Only the owner of the trader may view its decisions
+	traderRecord, err := s.store.Trader().GetByID(record.TraderID)
+	if err != nil || traderRecord.UserID != userID {
+		SafeNotFound(c, "Decision")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
 // handleLatestDecisions Latest decision logs (newest first, supports limit parameter)
 func (s *Server) handleLatestDecisions(c *gin.Context) {
 	_, traderID, err := s.getTraderFromQuery(c)
@@ -2917,12 +3528,56 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, history)
 }
 
+// handleRealizedPnLHistory Cumulative realized PnL of closed positions, bucketed by hour or day (UTC)
+// Query: trader_id, interval=day|hour (default day), days=N to limit the window (default all history)
+func (s *Server) handleRealizedPnLHistory(c *gin.Context) {
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, "Invalid trader ID")
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	var bucket time.Duration
+	switch interval {
+	case "day":
+		bucket = 24 * time.Hour
+	case "hour":
+		bucket = time.Hour
+	default:
+		SafeBadRequest(c, "interval must be day or hour")
+		return
+	}
+
+	var since int64
+	if daysStr := c.Query("days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days <= 0 || days > 3650 {
+			SafeBadRequest(c, "days must be between 1 and 3650")
+			return
+		}
+		since = time.Now().UTC().Add(-time.Duration(days) * 24 * time.Hour).Truncate(bucket).UnixMilli()
+	}
+
+	points, err := s.store.Position().GetRealizedPnLSeries(traderID, bucket.Milliseconds(), since)
+	if err != nil {
+		SafeInternalError(c, "Get realized PnL history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"interval":  interval,
+		"points":    points,
+	})
+}
+
 // authMiddleware JWT authentication middleware
 func (s *Server) authMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
+			respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Missing Authorization header")
 			c.Abort()
 			return
 		}
@@ -2930,7 +3585,7 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 		// Check Bearer token format
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization format"})
+			respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid Authorization format")
 			c.Abort()
 			return
 		}
@@ -2939,7 +3594,7 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 
 		// Blacklist check
 		if auth.IsTokenBlacklisted(tokenString) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired, please login again"})
+			respondError(c, http.StatusUnauthorized, ErrCodeTokenExpired, "Token expired, please login again")
 			c.Abort()
 			return
 		}
@@ -2948,7 +3603,7 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 		claims, err := auth.ValidateJWT(tokenString)
 		if err != nil {
 			logger.Errorf("[Auth] Invalid token: %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired token")
 			c.Abort()
 			return
 		}
@@ -2956,6 +3611,7 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 		// Store user information in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set(tokenScopeKey, claims.Scope)
 		c.Next()
 	}
 }
@@ -2964,18 +3620,18 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 func (s *Server) handleLogout(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Missing Authorization header")
 		return
 	}
 	parts := strings.Split(authHeader, " ")
 	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization format"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid Authorization format")
 		return
 	}
 	tokenString := parts[1]
 	claims, err := auth.ValidateJWT(tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid token")
 		return
 	}
 	var exp time.Time
@@ -2992,7 +3648,7 @@ func (s *Server) handleLogout(c *gin.Context) {
 func (s *Server) handleRegister(c *gin.Context) {
 	// Check if registration is allowed
 	if !config.Get().RegistrationEnabled {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Registration is disabled"})
+		respondError(c, http.StatusForbidden, ErrCodeRegistrationClosed, "Registration is disabled")
 		return
 	}
 
@@ -3001,11 +3657,11 @@ func (s *Server) handleRegister(c *gin.Context) {
 	if maxUsers > 0 {
 		userCount, err := s.store.User().Count()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check user count"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to check user count")
 			return
 		}
 		if userCount >= maxUsers {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Not on whitelist"})
+			respondError(c, http.StatusForbidden, ErrCodeRegistrationClosed, "Not on whitelist")
 			return
 		}
 	}
@@ -3023,21 +3679,21 @@ func (s *Server) handleRegister(c *gin.Context) {
 	// Check if email already exists
 	_, err := s.store.User().GetByEmail(req.Email)
 	if err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		respondError(c, http.StatusConflict, ErrCodeEmailTaken, "Email already registered")
 		return
 	}
 
 	// Generate password hash
 	passwordHash, err := auth.HashPassword(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password processing failed"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Password processing failed")
 		return
 	}
 
 	// Generate OTP secret
 	otpSecret, err := auth.GenerateOTPSecret()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "OTP secret generation failed"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "OTP secret generation failed")
 		return
 	}
 
@@ -3089,21 +3745,21 @@ func (s *Server) handleCompleteRegistration(c *gin.Context) {
 
 	// Verify OTP
 	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP code error"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidOTP, "OTP code error")
 		return
 	}
 
 	// Update user OTP verified status
 	err = s.store.User().UpdateOTPVerified(req.UserID, true)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to update user status")
 		return
 	}
 
 	// Generate JWT token
 	token, err := auth.GenerateJWT(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to generate token")
 		return
 	}
 
@@ -3136,13 +3792,13 @@ func (s *Server) handleLogin(c *gin.Context) {
 	// Get user information
 	user, err := s.store.User().GetByEmail(req.Email)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Email or password incorrect"})
+		respondError(c, http.StatusUnauthorized, ErrCodeInvalidCredentials, "Email or password incorrect")
 		return
 	}
 
 	// Verify password
 	if !auth.CheckPassword(req.Password, user.PasswordHash) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Email or password incorrect"})
+		respondError(c, http.StatusUnauthorized, ErrCodeInvalidCredentials, "Email or password incorrect")
 		return
 	}
 
@@ -3150,6 +3806,7 @@ func (s *Server) handleLogin(c *gin.Context) {
 	if !user.OTPVerified {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error":              "Account has not completed OTP setup",
+			"code":               ErrCodeOTPSetupRequired,
 			"user_id":            user.ID,
 			"requires_otp_setup": true,
 		})
@@ -3186,14 +3843,14 @@ func (s *Server) handleVerifyOTP(c *gin.Context) {
 
 	// Verify OTP
 	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification code error"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidOTP, "Verification code error")
 		return
 	}
 
 	// Generate JWT token
 	token, err := auth.GenerateJWT(user.ID, user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Failed to generate token")
 		return
 	}
 
@@ -3221,27 +3878,27 @@ func (s *Server) handleResetPassword(c *gin.Context) {
 	// Query user
 	user, err := s.store.User().GetByEmail(req.Email)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Email does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeUserNotFound, "Email does not exist")
 		return
 	}
 
 	// Verify OTP
 	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Google Authenticator code error"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidOTP, "Google Authenticator code error")
 		return
 	}
 
 	// Generate new password hash
 	newPasswordHash, err := auth.HashPassword(req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password processing failed"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Password processing failed")
 		return
 	}
 
 	// Update password
 	err = s.store.User().UpdatePassword(user.ID, newPasswordHash)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password update failed"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Password update failed")
 		return
 	}
 
@@ -3284,6 +3941,7 @@ func (s *Server) handleGetSupportedExchanges(c *gin.Context) {
 		{ExchangeType: "hyperliquid", Name: "Hyperliquid", Type: "dex"},
 		{ExchangeType: "aster", Name: "Aster DEX", Type: "dex"},
 		{ExchangeType: "lighter", Name: "LIGHTER DEX", Type: "dex"},
+		{ExchangeType: "backpack", Name: "Backpack", Type: "cex"},
 		{ExchangeType: "alpaca", Name: "Alpaca (US Stocks)", Type: "stock"},
 		{ExchangeType: "forex", Name: "Forex (TwelveData)", Type: "forex"},
 		{ExchangeType: "metals", Name: "Metals (TwelveData)", Type: "metals"},
@@ -3302,12 +3960,16 @@ func (s *Server) Start() error {
 	logger.Infof("  • GET  /api/competition      - Public competition data (no auth required)")
 	logger.Infof("  • GET  /api/top-traders      - Top 5 trader data (no auth required, for performance comparison)")
 	logger.Infof("  • GET  /api/equity-history?trader_id=xxx - Public return rate historical data (no auth required, for competition)")
+	logger.Infof("  • GET  /api/realized-pnl-history?trader_id=xxx - Cumulative realized PnL by day/hour")
 	logger.Infof("  • GET  /api/equity-history-batch?trader_ids=a,b,c - Batch get historical data (no auth required, performance comparison optimization)")
 	logger.Infof("  • GET  /api/traders/:id/public-config - Public trader config (no auth required, no sensitive info)")
 	logger.Infof("  • POST /api/traders          - Create new AI trader")
 	logger.Infof("  • DELETE /api/traders/:id    - Delete AI trader")
 	logger.Infof("  • POST /api/traders/:id/start - Start AI trader")
 	logger.Infof("  • POST /api/traders/:id/stop  - Stop AI trader")
+	logger.Infof("  • POST /api/traders/:id/disable - Kill-switch: stop and keep trader disabled")
+	logger.Infof("  • POST /api/traders/:id/enable  - Re-enable a disabled trader")
+	logger.Infof("  • POST /api/traders/:id/reset-history?confirm=true - Delete a stopped trader's recorded history")
 	logger.Infof("  • GET  /api/models           - Get AI model config")
 	logger.Infof("  • PUT  /api/models           - Update AI model config")
 	logger.Infof("  • GET  /api/exchanges        - Get exchange config")
@@ -3316,7 +3978,17 @@ func (s *Server) Start() error {
 	logger.Infof("  • GET  /api/account?trader_id=xxx    - Specified trader's account info")
 	logger.Infof("  • GET  /api/positions?trader_id=xxx  - Specified trader's position list")
 	logger.Infof("  • GET  /api/decisions?trader_id=xxx  - Specified trader's decision log")
+	logger.Infof("  • GET  /api/decisions?trader_id=xxx&symbol=BTC - Decisions that acted on a symbol (paginated)")
 	logger.Infof("  • GET  /api/decisions/latest?trader_id=xxx - Specified trader's latest decisions")
+	logger.Infof("  • GET  /api/decisions/diff?trader_id=xxx - What the AI changed between cycles")
+	logger.Infof("  • GET  /api/decisions/:id - A decision in full, with its cycle trace ID")
+	logger.Infof("  • PUT  /api/decisions/:id/annotate - Add notes/tags to a decision")
+	logger.Infof("  • POST /api/traders/:id/signal - External signal (JWT or HMAC-signed webhook)")
+	logger.Infof("  • POST /api/traders/:id/webhook-secret - Rotate the trader's signal webhook secret")
+	logger.Infof("  • POST /api/traders/:id/open - Manually open a position through a running trader")
+	logger.Infof("  • GET  /api/traders/:id/logs?tail=500 - Recent lines of the trader's log file")
+	logger.Infof("  • POST /api/traders/:id/rebalance - Change leverage/isolated margin of an open position")
+	logger.Infof("  • POST /api/admin/pause-all | resume-all - Globally pause/resume trading (admin)")
 	logger.Infof("  • GET  /api/statistics?trader_id=xxx - Specified trader's statistics")
 	logger.Infof("  • GET  /api/performance?trader_id=xxx - Specified trader's AI learning performance analysis")
 	logger.Info()
@@ -3356,9 +4028,7 @@ func (s *Server) handlePublicTraderList(c *gin.Context) {
 
 	traders, ok := tradersData.([]map[string]interface{})
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Trader data format error",
-		})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Trader data format error")
 		return
 	}
 
@@ -3426,7 +4096,7 @@ func (s *Server) handleEquityHistoryBatch(c *gin.Context) {
 
 			traders, ok := topTraders["traders"].([]map[string]interface{})
 			if !ok {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Trader data format error"})
+				respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Trader data format error")
 				return
 			}
 
@@ -3527,15 +4197,18 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 			initialBalance = snapshots[0].TotalEquity
 		}
 
+		// Deposits/withdrawals recorded up to each point are added to the capital base, not counted as PnL
+		adjustments, err := s.store.BalanceAdjustment().List(traderID)
+		if err != nil {
+			logger.Warnf("[API] Failed to get balance adjustments for %s: %v", traderID, err)
+		}
+
 		// Build return rate historical data with PnL percentage
 		history := make([]map[string]interface{}, 0, len(snapshots)+1)
 		var lastSnapshotTime time.Time
 		for _, snap := range snapshots {
-			// Calculate PnL percentage: (current_equity - initial_balance) / initial_balance * 100
-			pnlPct := 0.0
-			if initialBalance > 0 {
-				pnlPct = (snap.TotalEquity - initialBalance) / initialBalance * 100
-			}
+			// Calculate PnL percentage: (current_equity - capital) / capital * 100, capital = initial_balance + net contributions
+			_, pnlPct := trader.TradingPnL(snap.TotalEquity, initialBalance, store.ContributionsUntil(adjustments, snap.Timestamp))
 
 			history = append(history, map[string]interface{}{
 				"timestamp":     snap.Timestamp,
@@ -3551,8 +4224,8 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 
 		// Append current real-time data point to ensure chart matches leaderboard
 		// This ensures the latest point is always current, not from a potentially stale snapshot
-		if trader, err := s.traderManager.GetTrader(traderID); err == nil {
-			if accountInfo, err := trader.GetAccountInfo(); err == nil {
+		if liveTrader, err := s.traderManager.GetTrader(traderID); err == nil {
+			if accountInfo, err := liveTrader.GetAccountInfo(); err == nil {
 				// Only append if it's been more than 30 seconds since last snapshot
 				if now.Sub(lastSnapshotTime) > 30*time.Second {
 					totalEquity := 0.0
@@ -3567,10 +4240,7 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 					if v, ok := accountInfo["wallet_balance"].(float64); ok {
 						walletBalance = v
 					}
-					pnlPct := 0.0
-					if initialBalance > 0 {
-						pnlPct = (totalEquity - initialBalance) / initialBalance * 100
-					}
+					_, pnlPct := trader.TradingPnL(totalEquity, initialBalance, store.ContributionsUntil(adjustments, now))
 
 					history = append(history, map[string]interface{}{
 						"timestamp":     now,
@@ -3599,13 +4269,13 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 func (s *Server) handleGetPublicTraderConfig(c *gin.Context) {
 	traderID := c.Param("id")
 	if traderID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Trader ID cannot be empty"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Trader ID cannot be empty")
 		return
 	}
 
 	trader, err := s.traderManager.GetTrader(traderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
 		return
 	}
 
@@ -303,3 +303,34 @@ func TestPublicTraderListResponse_SystemPromptTemplate(t *testing.T) {
 		t.Errorf("Expected system_prompt_template='default', got %v", response["system_prompt_template"])
 	}
 }
+
+// TestResolveQuoteAsset Test trading symbol validation and quote asset inference
+func TestResolveQuoteAsset(t *testing.T) {
+	tests := []struct {
+		name      string
+		quote     string
+		symbols   string
+		want      string
+		wantError bool
+	}{
+		{name: "default USDT", want: "USDT"},
+		{name: "USDT symbols", symbols: "BTCUSDT,ETHUSDT", want: "USDT"},
+		{name: "USDC symbols inferred", symbols: "BTCUSDC, ETHUSDC", want: "USDC"},
+		{name: "mixed symbols default to USDT", symbols: "BTCUSDC,ETHUSDT", want: "USDT"},
+		{name: "explicit quote", quote: "usdc", symbols: "BTCUSDT", want: "USDC"},
+		{name: "invalid symbol", symbols: "BTC", wantError: true},
+		{name: "unsupported quote", quote: "BUSD", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveQuoteAsset(tt.quote, tt.symbols)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("resolveQuoteAsset() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && got != tt.want {
+				t.Errorf("resolveQuoteAsset() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
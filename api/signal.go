@@ -0,0 +1,289 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/auth"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxSignalBodyBytes limits webhook payload size
+	maxSignalBodyBytes = 64 * 1024
+	// signalTimestampTolerance max distance of a webhook's X-Timestamp from the server clock
+	signalTimestampTolerance = 5 * time.Minute
+)
+
+// SignalRequest external signal payload (TradingView alert message, scripts, etc.)
+type SignalRequest struct {
+	Action          string  `json:"action"` // open_long / open_short / close_long / close_short / reduce_long / reduce_short (empty = no direct decision)
+	Symbol          string  `json:"symbol"`
+	Leverage        int     `json:"leverage"`
+	PositionSizeUSD float64 `json:"position_size_usd"`
+	StopLoss        float64 `json:"stop_loss"`
+	TakeProfit      float64 `json:"take_profit"`
+	ClosePercentage float64 `json:"close_percentage"` // Percent to close for reduce_long / reduce_short
+	Confidence      int     `json:"confidence"`
+	Reasoning       string  `json:"reasoning"`
+	RunCycle        bool    `json:"run_cycle"` // Run an AI cycle now
+}
+
+// signSignal returns hex(HMAC-SHA256(secret, "<traderID>.<timestamp>.<body>"))
+func signSignal(secret, traderID, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(traderID + "." + timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignalSignature checks the webhook signature of traderID, timestamp and body in constant time
+func verifySignalSignature(secret, traderID, timestamp string, body []byte, signature string) bool {
+	if secret == "" || signature == "" || timestamp == "" {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(signSignal(secret, traderID, timestamp, body))
+	return hmac.Equal(expected, got)
+}
+
+// signalTimestampFresh reports whether a unix-seconds webhook timestamp is within the tolerance of now
+func signalTimestampFresh(timestamp string, now time.Time) bool {
+	sec, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return false
+	}
+	diff := now.Sub(time.Unix(sec, 0))
+	return diff <= signalTimestampTolerance && diff >= -signalTimestampTolerance
+}
+
+// signalReplayCache remembers the signatures of accepted webhooks while their timestamp is still
+// fresh, so a captured request can't be replayed
+type signalReplayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time // signature -> expiry
+}
+
+func newSignalReplayCache() *signalReplayCache {
+	return &signalReplayCache{seen: make(map[string]time.Time)}
+}
+
+// Remember records signature at now; returns false if it was already seen (a replay)
+func (c *signalReplayCache) Remember(signature string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, expiry := range c.seen {
+		if now.After(expiry) {
+			delete(c.seen, key)
+		}
+	}
+	key := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	// A timestamp stays fresh for up to twice the tolerance (from -tolerance to +tolerance)
+	c.seen[key] = now.Add(2 * signalTimestampTolerance)
+	return true
+}
+
+// newWebhookSecret generates a random signal webhook secret
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// authenticateSignal accepts either a logged-in owner (Bearer JWT) or an HMAC-signed webhook.
+// Returns the signal source, or writes an error response and returns "".
+func (s *Server) authenticateSignal(c *gin.Context, traderID string, body []byte) string {
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader || auth.IsTokenBlacklisted(tokenString) {
+			SafeUnauthorized(c)
+			return ""
+		}
+		claims, err := auth.ValidateJWT(tokenString)
+		if err != nil {
+			SafeUnauthorized(c)
+			return ""
+		}
+		if claims.IsReadOnly() {
+			respondError(c, http.StatusForbidden, ErrCodeReadOnlyToken, readOnlyForbiddenMsg)
+			return ""
+		}
+		traderRecord, err := s.store.Trader().GetByID(traderID)
+		if err != nil || traderRecord.UserID != claims.UserID {
+			SafeNotFound(c, "Trader")
+			return ""
+		}
+		return trader.SignalSourceAPI
+	}
+
+	signature := c.GetHeader("X-Signature")
+	if signature == "" {
+		signature = c.Query("signature")
+	}
+	timestamp := c.GetHeader("X-Timestamp")
+	if timestamp == "" {
+		timestamp = c.Query("timestamp")
+	}
+	now := time.Now()
+	if !signalTimestampFresh(timestamp, now) {
+		logger.Warnf("⚠️ Rejected signal webhook for trader %s from %s: missing or stale timestamp", traderID, c.ClientIP())
+		SafeUnauthorized(c)
+		return ""
+	}
+	var secret string
+	if traderRecord, err := s.store.Trader().GetByID(traderID); err == nil {
+		secret = string(traderRecord.WebhookSecret)
+	}
+	if !verifySignalSignature(secret, traderID, timestamp, body, signature) {
+		logger.Warnf("⚠️ Rejected unsigned or invalid signal webhook for trader %s from %s", traderID, c.ClientIP())
+		SafeUnauthorized(c)
+		return ""
+	}
+	if !s.signalReplays.Remember(signature, now) {
+		logger.Warnf("⚠️ Rejected replayed signal webhook for trader %s from %s", traderID, c.ClientIP())
+		SafeUnauthorized(c)
+		return ""
+	}
+	return trader.SignalSourceWebhook
+}
+
+// signalSubmitter queues a signal on a loaded trader (*trader.AutoTrader)
+type signalSubmitter interface {
+	SubmitSignal(sig trader.Signal) error
+}
+
+// signalTarget returns the loaded trader signals for traderID are queued on
+func (s *Server) signalTarget(traderID string) (signalSubmitter, error) {
+	if s.signalTargetFn != nil {
+		return s.signalTargetFn(traderID)
+	}
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil {
+		return nil, err
+	}
+	return at, nil
+}
+
+// handleTraderSignal receives an external signal and queues it for the trader's main loop
+func (s *Server) handleTraderSignal(c *gin.Context) {
+	traderID := c.Param("id")
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignalBodyBytes+1))
+	if err != nil || len(body) > maxSignalBodyBytes {
+		SafeBadRequest(c, "Invalid signal payload")
+		return
+	}
+
+	source := s.authenticateSignal(c, traderID, body)
+	if source == "" {
+		return
+	}
+
+	var req SignalRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		SafeBadRequest(c, "Invalid signal payload")
+		return
+	}
+
+	sig := trader.Signal{
+		RunCycle:   req.RunCycle,
+		Source:     source,
+		ReceivedAt: time.Now(),
+	}
+	if req.Action != "" {
+		sig.Decision = &kernel.Decision{
+			Symbol:          req.Symbol,
+			Action:          strings.ToLower(req.Action),
+			Leverage:        req.Leverage,
+			PositionSizeUSD: req.PositionSizeUSD,
+			StopLoss:        req.StopLoss,
+			TakeProfit:      req.TakeProfit,
+			ClosePercentage: req.ClosePercentage,
+			Confidence:      req.Confidence,
+			Reasoning:       req.Reasoning,
+		}
+	}
+
+	at, err := s.signalTarget(traderID)
+	if err != nil {
+		respondError(c, http.StatusConflict, ErrCodeTraderNotRunning, "Trader is not running")
+		return
+	}
+
+	if err := at.SubmitSignal(sig); err != nil {
+		switch {
+		case errors.Is(err, trader.ErrSignalTraderNotRunning):
+			respondError(c, http.StatusConflict, ErrCodeTraderNotRunning, "Trader is not running")
+		case errors.Is(err, trader.ErrSignalRateLimited), errors.Is(err, trader.ErrSignalQueueFull):
+			respondError(c, http.StatusTooManyRequests, ErrorCodeFor(err), err.Error())
+		default:
+			respondError(c, http.StatusBadRequest, ErrorCodeFor(err), err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Signal queued",
+		"trader_id": traderID,
+		"source":    source,
+		"run_cycle": sig.RunCycle,
+		"decision":  sig.Decision,
+	})
+}
+
+// handleRotateWebhookSecret generates a new signal webhook secret for the trader and returns it
+// (shown once; the previous secret stops working)
+func (s *Server) handleRotateWebhookSecret(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		SafeInternalError(c, "Generate webhook secret", err)
+		return
+	}
+	if err := s.store.Trader().SetWebhookSecret(userID, traderID, secret); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	logger.Infof("🔑 Signal webhook secret rotated for trader %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"trader_id": traderID, "webhook_secret": secret})
+}
+
+// handleDeleteWebhookSecret disables signed webhooks for the trader
+func (s *Server) handleDeleteWebhookSecret(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if err := s.store.Trader().SetWebhookSecret(userID, traderID, ""); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	logger.Infof("🔑 Signal webhooks disabled for trader %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook secret removed"})
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSignalTrader records the signals queued by the handler
+type fakeSignalTrader struct {
+	signals []trader.Signal
+}
+
+func (f *fakeSignalTrader) SubmitSignal(sig trader.Signal) error {
+	f.signals = append(f.signals, sig)
+	return nil
+}
+
+func TestHandleTraderSignalReduce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st, err := store.New(filepath.Join(t.TempDir(), "signal.db"))
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	defer st.Close()
+	if err := st.Trader().Create(&store.Trader{ID: "trader-1", UserID: "u1", Name: "t", AIModelID: "m", ExchangeID: "e"}); err != nil {
+		t.Fatalf("create trader: %v", err)
+	}
+	if err := st.Trader().SetWebhookSecret("u1", "trader-1", "webhook-secret"); err != nil {
+		t.Fatalf("SetWebhookSecret() error = %v", err)
+	}
+
+	target := &fakeSignalTrader{}
+	s := &Server{
+		store:          st,
+		signalReplays:  newSignalReplayCache(),
+		signalTargetFn: func(string) (signalSubmitter, error) { return target, nil },
+	}
+	r := gin.New()
+	r.POST("/api/traders/:id/signal", s.handleTraderSignal)
+
+	body := []byte(`{"action":"reduce_long","symbol":"BTCUSDT","close_percentage":40}`)
+	send := func() int {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/api/traders/trader-1/signal", bytes.NewReader(body))
+		req.Header.Set("X-Timestamp", ts)
+		req.Header.Set("X-Signature", signSignal("webhook-secret", "trader-1", ts, body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(); code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", code)
+	}
+	if len(target.signals) != 1 || target.signals[0].Decision == nil {
+		t.Fatalf("queued signals = %+v, want one decision", target.signals)
+	}
+	d := target.signals[0].Decision
+	if d.Action != "reduce_long" || d.Symbol != "BTCUSDT" || d.ClosePercentage != 40 {
+		t.Errorf("decision = %s %s %.0f%%, want reduce_long BTCUSDT 40%%", d.Action, d.Symbol, d.ClosePercentage)
+	}
+	if target.signals[0].Source != trader.SignalSourceWebhook {
+		t.Errorf("source = %q, want %q", target.signals[0].Source, trader.SignalSourceWebhook)
+	}
+}
+
+func TestVerifySignalSignature(t *testing.T) {
+	secret := "webhook-secret"
+	body := []byte(`{"action":"open_long","symbol":"BTCUSDT"}`)
+	ts := "1700000000"
+	valid := signSignal(secret, "trader-1", ts, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		traderID  string
+		timestamp string
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: secret, traderID: "trader-1", timestamp: ts, signature: valid, want: true},
+		{name: "valid with prefix", secret: secret, traderID: "trader-1", timestamp: ts, signature: "sha256=" + valid, want: true},
+		{name: "wrong secret", secret: "other", traderID: "trader-1", timestamp: ts, signature: valid, want: false},
+		{name: "other trader", secret: secret, traderID: "trader-2", timestamp: ts, signature: valid, want: false},
+		{name: "other timestamp", secret: secret, traderID: "trader-1", timestamp: "1700000001", signature: valid, want: false},
+		{name: "missing timestamp", secret: secret, traderID: "trader-1", timestamp: "", signature: valid, want: false},
+		{name: "missing signature", secret: secret, traderID: "trader-1", timestamp: ts, signature: "", want: false},
+		{name: "webhooks disabled", secret: "", traderID: "trader-1", timestamp: ts, signature: valid, want: false},
+		{name: "not hex", secret: secret, traderID: "trader-1", timestamp: ts, signature: "zz", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifySignalSignature(tt.secret, tt.traderID, tt.timestamp, body, tt.signature); got != tt.want {
+				t.Errorf("verifySignalSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignalTimestampFresh(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := map[string]bool{
+		strconv.FormatInt(now.Unix(), 10):                                            true,
+		strconv.FormatInt(now.Add(-4*time.Minute).Unix(), 10):                        true,
+		strconv.FormatInt(now.Add(4*time.Minute).Unix(), 10):                         true,
+		strconv.FormatInt(now.Add(-signalTimestampTolerance-time.Second).Unix(), 10): false,
+		strconv.FormatInt(now.Add(signalTimestampTolerance+time.Second).Unix(), 10):  false,
+		"":    false,
+		"abc": false,
+	}
+	for ts, want := range tests {
+		if got := signalTimestampFresh(ts, now); got != want {
+			t.Errorf("signalTimestampFresh(%q) = %v, want %v", ts, got, want)
+		}
+	}
+}
+
+func TestSignalReplayCache(t *testing.T) {
+	cache := newSignalReplayCache()
+	now := time.Now()
+
+	if !cache.Remember("abcd", now) {
+		t.Fatal("first use rejected")
+	}
+	if cache.Remember("ABCD", now.Add(time.Minute)) {
+		t.Error("replay accepted")
+	}
+	if cache.Remember("sha256=abcd", now.Add(time.Minute)) {
+		t.Error("replay with prefix accepted")
+	}
+	if !cache.Remember("ef01", now) {
+		t.Error("other signature rejected")
+	}
+	// Expired entries are forgotten once their timestamp can no longer be fresh
+	if !cache.Remember("abcd", now.Add(2*signalTimestampTolerance+time.Second)) {
+		t.Error("entry kept after expiry")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"nofx/market"
 	"nofx/mcp"
 	"nofx/store"
+	"nofx/trader"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +30,28 @@ func validateStrategyConfig(config *store.StrategyConfig) []string {
 	return warnings
 }
 
+// validateStrategyIntervals rejects kline intervals that the market data source can't serve,
+// malformed trading windows and invalid coin source parameters
+func validateStrategyIntervals(config *store.StrategyConfig) error {
+	if interval := config.Indicators.AnalysisInterval; interval != "" {
+		if err := market.ValidateKlineInterval(interval); err != nil {
+			return fmt.Errorf("analysis_interval: %w", err)
+		}
+	}
+	if interval := config.RiskControl.VolatilityTimeframe; interval != "" {
+		if err := market.ValidateKlineInterval(interval); err != nil {
+			return fmt.Errorf("volatility_timeframe: %w", err)
+		}
+	}
+	if err := trader.ValidateTradingWindows(config.RiskControl.TradingWindows); err != nil {
+		return err
+	}
+	if err := kernel.ValidateCoinSources(config.CoinSource); err != nil {
+		return err
+	}
+	return nil
+}
+
 // handlePublicStrategies Get public strategies for strategy market (no auth required)
 func (s *Server) handlePublicStrategies(c *gin.Context) {
 	strategies, err := s.store.Strategy().ListPublic()
@@ -70,7 +93,7 @@ func (s *Server) handlePublicStrategies(c *gin.Context) {
 func (s *Server) handleGetStrategies(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -111,13 +134,13 @@ func (s *Server) handleGetStrategy(c *gin.Context) {
 	strategyID := c.Param("id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	strategy, err := s.store.Strategy().Get(userID, strategyID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Strategy not found"})
+		respondError(c, http.StatusNotFound, ErrCodeStrategyNotFound, "Strategy not found")
 		return
 	}
 
@@ -140,7 +163,7 @@ func (s *Server) handleGetStrategy(c *gin.Context) {
 func (s *Server) handleCreateStrategy(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -155,6 +178,11 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 		return
 	}
 
+	if err := validateStrategyIntervals(&req.Config); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
 	if err != nil {
@@ -197,18 +225,18 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 	strategyID := c.Param("id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Check if it's a system default strategy
 	existing, err := s.store.Strategy().Get(userID, strategyID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Strategy not found"})
+		respondError(c, http.StatusNotFound, ErrCodeStrategyNotFound, "Strategy not found")
 		return
 	}
 	if existing.IsDefault {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot modify system default strategy"})
+		respondError(c, http.StatusForbidden, ErrCodeSystemStrategy, "Cannot modify system default strategy")
 		return
 	}
 
@@ -225,6 +253,11 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		return
 	}
 
+	if err := validateStrategyIntervals(&req.Config); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
 	if err != nil {
@@ -264,7 +297,7 @@ func (s *Server) handleDeleteStrategy(c *gin.Context) {
 	strategyID := c.Param("id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -282,7 +315,7 @@ func (s *Server) handleActivateStrategy(c *gin.Context) {
 	strategyID := c.Param("id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -300,7 +333,7 @@ func (s *Server) handleDuplicateStrategy(c *gin.Context) {
 	sourceID := c.Param("id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -330,13 +363,13 @@ func (s *Server) handleGetActiveStrategy(c *gin.Context) {
 	userID := c.GetString("user_id")
 
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	strategy, err := s.store.Strategy().GetActive(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No active strategy"})
+		respondError(c, http.StatusNotFound, ErrCodeStrategyNotFound, "No active strategy")
 		return
 	}
 
@@ -372,7 +405,7 @@ func (s *Server) handleGetDefaultStrategyConfig(c *gin.Context) {
 func (s *Server) handlePreviewPrompt(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -417,11 +450,73 @@ func (s *Server) handlePreviewPrompt(c *gin.Context) {
 	})
 }
 
+// handleGetEffectivePrompt Get the effective prompt of a trader (strategy + trader custom prompt merged)
+// Runs the same prompt assembly as a trading cycle without calling the AI
+func (s *Server) handleGetEffectivePrompt(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+	if fullConfig.Strategy == nil {
+		respondError(c, http.StatusBadRequest, ErrCodeStrategyRequired, "Trader has no strategy configured")
+		return
+	}
+
+	strategyConfig, err := fullConfig.Strategy.ParseConfig()
+	if err != nil {
+		SafeInternalError(c, "Failed to parse strategy config", err)
+		return
+	}
+
+	traderCfg := fullConfig.Trader
+	engine := kernel.NewStrategyEngine(strategyConfig)
+	engine.SetTraderPrompt(traderCfg.CustomPrompt, traderCfg.OverrideBasePrompt)
+	engine.SetPromptLanguage(traderCfg.PromptLanguage)
+
+	// Use latest equity if available, otherwise initial balance
+	accountEquity := traderCfg.InitialBalance
+	if snapshots, err := s.store.Equity().GetLatest(traderID, 1); err == nil && len(snapshots) > 0 {
+		accountEquity = snapshots[len(snapshots)-1].TotalEquity
+	}
+	if accountEquity <= 0 {
+		accountEquity = 1000.0
+	}
+
+	promptVariant := kernel.PromptVariantFor(traderCfg.SystemPromptTemplate) // Variant used by trading cycles
+	systemPrompt := engine.BuildSystemPrompt(accountEquity, promptVariant)
+
+	// User prompt template: account section only, market data is filled in each cycle
+	userPrompt := engine.BuildUserPrompt(&kernel.Context{
+		CurrentTime: time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
+		Account: kernel.AccountInfo{
+			TotalEquity:      accountEquity,
+			AvailableBalance: accountEquity,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id":            traderID,
+		"strategy_id":          fullConfig.Strategy.ID,
+		"strategy_name":        fullConfig.Strategy.Name,
+		"prompt_variant":       promptVariant,
+		"account_equity":       accountEquity,
+		"has_custom_prompt":    traderCfg.CustomPrompt != "",
+		"override_base_prompt": traderCfg.OverrideBasePrompt,
+		"prompt_language":      engine.PromptLanguage(),
+		"system_prompt":        systemPrompt,
+		"user_prompt_template": userPrompt,
+	})
+}
+
 // handleStrategyTestRun AI test run (does not execute trades, only returns AI analysis results)
 func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -450,6 +545,7 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 		logger.Errorf("[API Error] Failed to get candidate coins: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":       "Failed to get candidate coins",
+			"code":        ErrCodeInternal,
 			"ai_response": "",
 		})
 		return
@@ -501,13 +597,13 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	quantDataMap := engine.FetchQuantDataBatch(symbols)
 
 	// Fetch OI ranking data (market-wide position changes)
-	oiRankingData := engine.FetchOIRankingData()
+	oiRankingData, _ := engine.FetchOIRankingData() // failures are logged; preview proceeds without it
 
 	// Fetch NetFlow ranking data (market-wide fund flow)
-	netFlowRankingData := engine.FetchNetFlowRankingData()
+	netFlowRankingData, _ := engine.FetchNetFlowRankingData() // failures are logged; preview proceeds without it
 
 	// Fetch Price ranking data (market-wide gainers/losers)
-	priceRankingData := engine.FetchPriceRankingData()
+	priceRankingData, _ := engine.FetchPriceRankingData() // failures are logged; preview proceeds without it
 
 	// Build real context (for generating User Prompt)
 	testContext := &kernel.Context{
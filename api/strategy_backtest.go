@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"nofx/backtest"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordStrategyBacktestSummary caches the result of a finished strategy backtest (backtest completion handler)
+func (s *Server) recordStrategyBacktestSummary(cfg backtest.BacktestConfig, meta *backtest.RunMetadata, metrics *backtest.Metrics) {
+	if cfg.StrategyID == "" || meta == nil {
+		return
+	}
+
+	symbols, _ := json.Marshal(cfg.Symbols)
+	summary := &store.StrategyBacktestSummary{
+		StrategyID:     cfg.StrategyID,
+		ParamsHash:     cfg.ParamsHash(),
+		UserID:         cfg.UserID,
+		RunID:          cfg.RunID,
+		ConfigHash:     cfg.StrategyConfigHash,
+		State:          string(meta.State),
+		Symbols:        string(symbols),
+		StartTS:        cfg.StartTS,
+		EndTS:          cfg.EndTS,
+		InitialBalance: cfg.InitialBalance,
+		EquityLast:     meta.Summary.EquityLast,
+		MaxDrawdownPct: meta.Summary.MaxDrawdownPct,
+		CompletedAt:    time.Now().UTC(),
+	}
+	if metrics != nil {
+		if data, err := json.Marshal(metrics); err == nil {
+			summary.Metrics = string(data)
+		}
+	}
+
+	if err := s.store.Strategy().SaveBacktestSummary(summary); err != nil {
+		logger.Warnf("⚠️ Failed to cache backtest summary for strategy %s (run %s): %v", cfg.StrategyID, cfg.RunID, err)
+		return
+	}
+	logger.Infof("📊 Cached backtest summary for strategy %s (run %s)", cfg.StrategyID, cfg.RunID)
+}
+
+// handleStrategyBacktestSummary returns the cached result of the strategy's last finished backtest.
+// Optional ?params_hash= selects a specific parameter set; stale=true means the strategy changed since.
+func (s *Server) handleStrategyBacktestSummary(c *gin.Context) {
+	userID := normalizeUserID(c.GetString("user_id"))
+	strategyID := c.Param("id")
+
+	strategy, err := s.store.Strategy().Get(userID, strategyID)
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+
+	summary, err := s.store.Strategy().GetBacktestSummary(userID, strategyID, c.Query("params_hash"))
+	if err != nil {
+		SafeInternalError(c, "Load backtest summary", err)
+		return
+	}
+	if summary == nil {
+		SafeNotFound(c, "Backtest summary")
+		return
+	}
+
+	var symbols []string
+	json.Unmarshal([]byte(summary.Symbols), &symbols)
+	var metrics *backtest.Metrics
+	if summary.Metrics != "" {
+		metrics = &backtest.Metrics{}
+		if err := json.Unmarshal([]byte(summary.Metrics), metrics); err != nil {
+			metrics = nil
+		}
+	}
+
+	currentHash := store.HashStrategyConfig(strategy.Config)
+	c.JSON(http.StatusOK, gin.H{
+		"strategy_id":         summary.StrategyID,
+		"run_id":              summary.RunID,
+		"state":               summary.State,
+		"params_hash":         summary.ParamsHash,
+		"config_hash":         summary.ConfigHash,
+		"current_config_hash": currentHash,
+		"stale":               summary.ConfigHash != currentHash,
+		"symbols":             symbols,
+		"start_ts":            summary.StartTS,
+		"end_ts":              summary.EndTS,
+		"initial_balance":     summary.InitialBalance,
+		"equity_last":         summary.EquityLast,
+		"max_drawdown_pct":    summary.MaxDrawdownPct,
+		"metrics":             metrics,
+		"completed_at":        summary.CompletedAt,
+	})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+
+	"nofx/backtest"
+	"nofx/store"
+)
+
+func TestStrategyConfigHashStaleness(t *testing.T) {
+	original := `{"language":"en","risk_control":{"max_positions":3,"min_confidence":70}}`
+	reordered := "{\n  \"risk_control\": {\"min_confidence\": 70, \"max_positions\": 3},\n  \"language\": \"en\"\n}"
+	changed := `{"language":"en","risk_control":{"max_positions":4,"min_confidence":70}}`
+
+	if store.HashStrategyConfig(original) != store.HashStrategyConfig(reordered) {
+		t.Error("key order and whitespace should not mark a summary stale")
+	}
+	if store.HashStrategyConfig(original) == store.HashStrategyConfig(changed) {
+		t.Error("config change should mark a summary stale")
+	}
+}
+
+func TestBacktestParamsHash(t *testing.T) {
+	base := backtest.BacktestConfig{
+		RunID:          "bt_1",
+		Symbols:        []string{"BTCUSDT"},
+		StartTS:        1700000000,
+		EndTS:          1700086400,
+		InitialBalance: 1000,
+	}
+	rerun := base
+	rerun.RunID = "bt_2"
+	rerun.CacheAI = true
+	longer := base
+	longer.EndTS = 1700172800
+
+	if base.ParamsHash() != rerun.ParamsHash() {
+		t.Error("run identity and cache options should not change the params hash")
+	}
+	if base.ParamsHash() == longer.ParamsHash() {
+		t.Error("different time range should change the params hash")
+	}
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/provider/alpaca"
+	"nofx/provider/coinank/coinank_api"
+	"nofx/provider/coinank/coinank_enum"
+	"nofx/provider/hyperliquid"
+	"nofx/provider/twelvedata"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/singleflight"
+)
+
+// Symbol search limits
+const (
+	symbolUniverseTTL        = time.Hour
+	symbolFetchRetryAfter    = time.Minute // A source that failed isn't retried before this
+	symbolFetchTimeout       = 20 * time.Second
+	symbolSearchDefaultLimit = 20
+	symbolSearchMaxLimit     = 100
+)
+
+// SymbolSearchResult a symbol of the unified search universe
+type SymbolSearchResult struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Category string `json:"category"` // crypto, stock, forex, commodity, index
+	Source   string `json:"source"`   // coinank, alpaca, twelvedata, hyperliquid
+}
+
+// symbolSource fetches the symbol universe of one data source
+type symbolSource struct {
+	name  string
+	fetch func(ctx context.Context) ([]SymbolSearchResult, error)
+}
+
+// symbolUniverse caches the symbol universe per source for ttl. A source that fails to refresh
+// keeps serving its previous symbols, so one provider outage doesn't empty the search, and isn't
+// retried for symbolFetchRetryAfter. Fetches run outside the lock, one at a time per source.
+type symbolUniverse struct {
+	sources []symbolSource
+	ttl     time.Duration
+	fetches singleflight.Group
+
+	mu        sync.Mutex
+	symbols   map[string][]SymbolSearchResult
+	fetchedAt map[string]time.Time
+	failedAt  map[string]time.Time
+}
+
+func newSymbolUniverse(sources []symbolSource, ttl time.Duration) *symbolUniverse {
+	return &symbolUniverse{
+		sources:   sources,
+		ttl:       ttl,
+		symbols:   make(map[string][]SymbolSearchResult),
+		fetchedAt: make(map[string]time.Time),
+		failedAt:  make(map[string]time.Time),
+	}
+}
+
+// defaultSymbolUniverse universe of all data sources shared by search requests
+var defaultSymbolUniverse = newSymbolUniverse([]symbolSource{
+	{name: "coinank", fetch: fetchCoinankSymbols},
+	{name: "alpaca", fetch: fetchAlpacaSymbols},
+	{name: "twelvedata", fetch: fetchTwelveDataSymbols},
+	{name: "hyperliquid", fetch: fetchHyperliquidSymbols},
+}, symbolUniverseTTL)
+
+// all returns the symbols of all sources, refreshing expired sources concurrently. Refreshes
+// aren't tied to ctx: a canceled request stops waiting and gets the cached symbols, while the
+// fetch completes for the next request.
+func (u *symbolUniverse) all(ctx context.Context) []SymbolSearchResult {
+	var pending []<-chan singleflight.Result
+	for _, src := range u.sources {
+		if !u.expired(src.name, time.Now()) {
+			continue
+		}
+		pending = append(pending, u.fetches.DoChan(src.name, func() (interface{}, error) {
+			u.refresh(src)
+			return nil, nil
+		}))
+	}
+	for _, ch := range pending {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var all []SymbolSearchResult
+	for _, src := range u.sources {
+		all = append(all, u.symbols[src.name]...)
+	}
+	return all
+}
+
+// expired reports whether a source is due for a refresh: past its ttl and not failed recently
+func (u *symbolUniverse) expired(name string, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return now.Sub(u.fetchedAt[name]) >= u.ttl && now.Sub(u.failedAt[name]) >= symbolFetchRetryAfter
+}
+
+// refresh fetches the symbols of a source and stores them (or the failure time)
+func (u *symbolUniverse) refresh(src symbolSource) {
+	fetchCtx, cancel := context.WithTimeout(context.Background(), symbolFetchTimeout)
+	defer cancel()
+	symbols, err := src.fetch(fetchCtx)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err != nil {
+		logger.Warnf("⚠️ Symbol search: failed to load %s symbols: %v", src.name, err)
+		u.failedAt[src.name] = time.Now()
+		return
+	}
+	u.symbols[src.name] = symbols
+	u.fetchedAt[src.name] = time.Now()
+}
+
+// searchSymbols filters symbols by query (matched against symbol and name, case-insensitive) and
+// category. Exact symbol matches rank first, then symbol prefix matches, then other matches.
+func searchSymbols(symbols []SymbolSearchResult, query, category string, limit int) []SymbolSearchResult {
+	q := strings.ToUpper(strings.TrimSpace(query))
+	type scored struct {
+		SymbolSearchResult
+		rank int
+	}
+	var matches []scored
+	for _, s := range symbols {
+		if category != "" && !strings.EqualFold(s.Category, category) {
+			continue
+		}
+		symbol := strings.ToUpper(s.Symbol)
+		rank := -1
+		switch {
+		case q == "":
+			rank = 3
+		case symbol == q:
+			rank = 0
+		case strings.HasPrefix(symbol, q):
+			rank = 1
+		case strings.Contains(symbol, q) || strings.Contains(strings.ToUpper(s.Name), q):
+			rank = 2
+		}
+		if rank >= 0 {
+			matches = append(matches, scored{s, rank})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		if len(matches[i].Symbol) != len(matches[j].Symbol) {
+			return len(matches[i].Symbol) < len(matches[j].Symbol)
+		}
+		return matches[i].Symbol < matches[j].Symbol
+	})
+
+	results := make([]SymbolSearchResult, 0, min(limit, len(matches)))
+	for _, m := range matches {
+		if len(results) >= limit {
+			break
+		}
+		results = append(results, m.SymbolSearchResult)
+	}
+	return results
+}
+
+// handleSymbolSearch Search symbols across CoinAnk crypto, Alpaca stocks, TwelveData forex/metals and Hyperliquid
+// GET /api/symbols/search?q=BTC&category=crypto&limit=20
+func (s *Server) handleSymbolSearch(c *gin.Context) {
+	limit := symbolSearchDefaultLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			SafeBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = min(n, symbolSearchMaxLimit)
+	}
+
+	results := searchSymbols(defaultSymbolUniverse.all(c.Request.Context()), c.Query("q"), c.Query("category"), limit)
+	c.JSON(http.StatusOK, gin.H{"symbols": results, "count": len(results)})
+}
+
+// fetchCoinankSymbols crypto perpetuals listed on Binance (CoinAnk free API, no key needed)
+func fetchCoinankSymbols(ctx context.Context) ([]SymbolSearchResult, error) {
+	coins, err := coinank_api.BaseCoinSymbols(ctx, coinank_enum.Binance, "", "")
+	if err != nil {
+		return nil, err
+	}
+	symbols := make([]SymbolSearchResult, 0, len(coins))
+	for _, coin := range coins {
+		if coin.ProductType != "" && coin.ProductType != string(coinank_enum.SWAP) {
+			continue
+		}
+		symbols = append(symbols, SymbolSearchResult{Symbol: coin.Symbol, Name: coin.BaseCoin, Category: "crypto", Source: "coinank"})
+	}
+	return symbols, nil
+}
+
+// fetchAlpacaSymbols tradable US stocks (requires Alpaca keys)
+func fetchAlpacaSymbols(ctx context.Context) ([]SymbolSearchResult, error) {
+	assets, err := alpaca.NewClient().ListAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	symbols := make([]SymbolSearchResult, 0, len(assets))
+	for _, a := range assets {
+		if !a.Tradable {
+			continue
+		}
+		symbols = append(symbols, SymbolSearchResult{Symbol: a.Symbol, Name: a.Name, Category: "stock", Source: "alpaca"})
+	}
+	return symbols, nil
+}
+
+// fetchTwelveDataSymbols forex pairs and commodities (requires a Twelve Data key)
+func fetchTwelveDataSymbols(ctx context.Context) ([]SymbolSearchResult, error) {
+	client := twelvedata.NewClient()
+	pairs, err := client.ListForexPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commodities, err := client.ListCommodities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]SymbolSearchResult, 0, len(pairs)+len(commodities))
+	for _, p := range pairs {
+		symbols = append(symbols, SymbolSearchResult{
+			Symbol: p.Symbol, Name: p.CurrencyBase + " / " + p.CurrencyQuote, Category: "forex", Source: "twelvedata",
+		})
+	}
+	for _, cm := range commodities {
+		symbols = append(symbols, SymbolSearchResult{Symbol: cm.Symbol, Name: cm.Name, Category: "commodity", Source: "twelvedata"})
+	}
+	return symbols, nil
+}
+
+// fetchHyperliquidSymbols Hyperliquid crypto perps and xyz dex assets (stocks, forex, commodities)
+func fetchHyperliquidSymbols(ctx context.Context) ([]SymbolSearchResult, error) {
+	client := hyperliquid.NewClient()
+	mids, err := client.GetAllMids(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []SymbolSearchResult
+	for symbol := range mids {
+		if strings.HasPrefix(symbol, "@") { // spot tokens
+			continue
+		}
+		symbols = append(symbols, SymbolSearchResult{Symbol: symbol, Name: symbol, Category: "crypto", Source: "hyperliquid"})
+	}
+	if xyzMids, err := client.GetAllMidsXYZ(ctx); err == nil {
+		for symbol := range xyzMids {
+			display := strings.TrimPrefix(symbol, "xyz:")
+			symbols = append(symbols, SymbolSearchResult{Symbol: display, Name: display, Category: xyzCategory(display), Source: "hyperliquid"})
+		}
+	}
+	return symbols, nil
+}
+
+// xyzCategory category of a Hyperliquid xyz dex asset
+func xyzCategory(symbol string) string {
+	switch symbol {
+	case "GOLD", "SILVER":
+		return "commodity"
+	case "EUR", "JPY":
+		return "forex"
+	case "XYZ100":
+		return "index"
+	}
+	return "stock"
+}
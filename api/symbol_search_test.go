@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	universe := []SymbolSearchResult{
+		{Symbol: "WBTCUSDT", Name: "WBTC", Category: "crypto", Source: "coinank"},
+		{Symbol: "BTCUSDT", Name: "BTC", Category: "crypto", Source: "coinank"},
+		{Symbol: "BTC", Name: "BTC", Category: "crypto", Source: "hyperliquid"},
+		{Symbol: "TSLA", Name: "Tesla, Inc. Common Stock", Category: "stock", Source: "alpaca"},
+		{Symbol: "XAU/USD", Name: "Gold Spot", Category: "commodity", Source: "twelvedata"},
+	}
+
+	got := searchSymbols(universe, "btc", "", 10)
+	want := []string{"BTC", "BTCUSDT", "WBTCUSDT"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d results %v, want %v", len(got), got, want)
+	}
+	for i, w := range want {
+		if got[i].Symbol != w {
+			t.Errorf("result %d = %s, want %s", i, got[i].Symbol, w)
+		}
+	}
+
+	if got := searchSymbols(universe, "gold", "", 10); len(got) != 1 || got[0].Symbol != "XAU/USD" {
+		t.Errorf("name search = %v, want XAU/USD", got)
+	}
+	if got := searchSymbols(universe, "btc", "stock", 10); len(got) != 0 {
+		t.Errorf("category filter returned %v", got)
+	}
+	if got := searchSymbols(universe, "", "crypto", 2); len(got) != 2 {
+		t.Errorf("limit not applied: %d results", len(got))
+	}
+}
+
+func TestSymbolUniverseCache(t *testing.T) {
+	calls := 0
+	fail := false
+	u := newSymbolUniverse([]symbolSource{{
+		name: "fake",
+		fetch: func(ctx context.Context) ([]SymbolSearchResult, error) {
+			calls++
+			if fail {
+				return nil, errors.New("source down")
+			}
+			return []SymbolSearchResult{{Symbol: "ETHUSDT", Category: "crypto", Source: "fake"}}, nil
+		},
+	}}, time.Hour)
+
+	u.all(context.Background())
+	u.all(context.Background())
+	if calls != 1 {
+		t.Errorf("fetched %d times within TTL, want 1", calls)
+	}
+
+	// Expired and failing: previous symbols are still served
+	u.fetchedAt["fake"] = time.Now().Add(-2 * time.Hour)
+	fail = true
+	if got := u.all(context.Background()); len(got) != 1 || calls != 2 {
+		t.Errorf("stale fallback: got %v after %d fetches", got, calls)
+	}
+	// A failed source isn't retried on every request
+	u.all(context.Background())
+	if calls != 2 {
+		t.Errorf("failed source fetched %d times, want 2 (retry backed off)", calls)
+	}
+}
+
+func TestSymbolUniverseConcurrentRefresh(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	u := newSymbolUniverse([]symbolSource{{
+		name: "slow",
+		fetch: func(ctx context.Context) ([]SymbolSearchResult, error) {
+			calls.Add(1)
+			<-release
+			return []SymbolSearchResult{{Symbol: "SOLUSDT", Category: "crypto", Source: "slow"}}, nil
+		},
+	}}, time.Hour)
+
+	// A canceled request stops waiting for the fetch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := u.all(ctx); len(got) != 0 {
+		t.Errorf("canceled request got %v, want nothing cached yet", got)
+	}
+
+	// Concurrent requests share the fetch that is already running
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.all(context.Background())
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+	if got := u.all(context.Background()); len(got) != 1 {
+		t.Errorf("got %v after the shared fetch", got)
+	}
+}
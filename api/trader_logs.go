@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"nofx/logger"
+)
+
+// handleTraderLogs Recent lines of a trader's own log file
+// GET /api/traders/:id/logs?tail=500
+func (s *Server) handleTraderLogs(c *gin.Context) {
+	traderID := c.Param("id")
+	if _, err := s.store.Trader().GetFullConfig(c.GetString("user_id"), traderID); err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist")
+		return
+	}
+
+	tail := logger.DefaultTraderLogTail
+	if v := c.Query("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "tail must be a positive integer")
+			return
+		}
+		tail = n
+	}
+
+	lines, err := logger.ReadTraderLog(traderID, tail)
+	if errors.Is(err, logger.ErrTraderLogDisabled) {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Per-trader log files are disabled (set TRADER_LOG_ENABLED=true)")
+		return
+	}
+	if err != nil {
+		SafeInternalError(c, "Read trader log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"lines":     lines,
+		"count":     len(lines),
+	})
+}
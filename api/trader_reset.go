@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleResetTraderHistory Delete a trader's recorded history (decisions, orders, fills, positions, equity,
+// balance adjustments, funding and equity alerts), keeping its config
+// Query params: confirm=true (required). The trader must be stopped.
+func (s *Server) handleResetTraderHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	// Held until the trader is reloaded, so it can't be started between the running check and the
+	// delete (and the running flag read below includes any start that finished before)
+	defer s.traderManager.LockTrader(traderID)()
+
+	fullCfg, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil || fullCfg.Trader == nil {
+		respondError(c, http.StatusNotFound, ErrCodeTraderNotFound, "Trader does not exist or no access permission")
+		return
+	}
+
+	if c.Query("confirm") != "true" {
+		SafeBadRequest(c, "This permanently deletes the trader's history, pass confirm=true to proceed")
+		return
+	}
+
+	running := fullCfg.Trader.IsRunning
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if isRunning, ok := at.GetStatus()["is_running"].(bool); ok && isRunning {
+			running = true
+		}
+	}
+	if running {
+		respondError(c, http.StatusConflict, ErrCodeTraderRunning, "Trader is running, stop it before resetting its history")
+		return
+	}
+
+	deleted, err := s.store.ResetTraderHistory(traderID)
+	if err != nil {
+		SafeInternalError(c, "Reset trader history", err)
+		return
+	}
+
+	// Reload the trader so in-memory state (equity high-water mark, cycle counters) starts fresh
+	s.traderManager.RemoveTrader(traderID)
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Warnf("⚠️ Failed to reload traders after history reset: %v", err)
+	}
+
+	logger.Infof("🧹 Trader %s history reset: %v", traderID, deleted)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trader history reset",
+		"deleted": deleted,
+	})
+}
@@ -72,9 +72,21 @@ func IsTokenBlacklisted(token string) bool {
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Scope  string `json:"scope,omitempty"` // Token scope, empty = full access
 	jwt.RegisteredClaims
 }
 
+// Token scopes
+const (
+	ScopeFull     = ""          // Full access (regular login)
+	ScopeReadOnly = "read_only" // View-only: requests that modify data or place orders are rejected
+)
+
+// IsReadOnly returns whether the token only grants view access
+func (c *Claims) IsReadOnly() bool {
+	return c.Scope == ScopeReadOnly
+}
+
 // HashPassword hashes the password
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -113,11 +125,21 @@ func VerifyOTP(secret, code string) bool {
 
 // GenerateJWT generates JWT token
 func GenerateJWT(userID, email string) (string, error) {
+	return generateJWT(userID, email, ScopeFull, time.Now().Add(24*time.Hour)) // Expires in 24 hours
+}
+
+// GenerateReadOnlyJWT generates a view-only token (e.g. for sharing a dashboard) valid until expiresAt
+func GenerateReadOnlyJWT(userID, email string, expiresAt time.Time) (string, error) {
+	return generateJWT(userID, email, ScopeReadOnly, expiresAt)
+}
+
+func generateJWT(userID, email, scope string, expiresAt time.Time) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
+		Scope:  scope,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Expires in 24 hours
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "nofxAI",
@@ -4,27 +4,32 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
-	"nofx/kernel"
-	"nofx/market"
+	"nofx/logger"
+	"nofx/mcp"
 )
 
-type cachedDecision struct {
-	Key           string                 `json:"key"`
-	PromptVariant string                 `json:"prompt_variant"`
-	Timestamp     int64                  `json:"ts"`
-	Decision      *kernel.FullDecision `json:"decision"`
+// errReplayCacheMiss returned in replay-only mode when a prompt has no recorded response
+var errReplayCacheMiss = errors.New("replay_only enabled but no recorded AI response for this prompt")
+
+type cachedResponse struct {
+	Key        string `json:"key"`
+	Response   string `json:"response"`
+	RecordedAt int64  `json:"recorded_at"` // Unix milliseconds
 }
 
-// AICache persists AI decisions for repeated backtesting or replay.
+// AICache persists raw AI responses keyed by a hash of the prompts, so re-running a backtest
+// replays identical decisions while any strategy or prompt change misses the cache.
 type AICache struct {
 	mu      sync.RWMutex
 	path    string
-	Entries map[string]cachedDecision `json:"entries"`
+	Entries map[string]cachedResponse `json:"entries"`
 }
 
 func LoadAICache(path string) (*AICache, error) {
@@ -38,7 +43,7 @@ func LoadAICache(path string) (*AICache, error) {
 
 	cache := &AICache{
 		path:    path,
-		Entries: make(map[string]cachedDecision),
+		Entries: make(map[string]cachedResponse),
 	}
 
 	data, err := os.ReadFile(path)
@@ -55,7 +60,7 @@ func LoadAICache(path string) (*AICache, error) {
 		return nil, err
 	}
 	if cache.Entries == nil {
-		cache.Entries = make(map[string]cachedDecision)
+		cache.Entries = make(map[string]cachedResponse)
 	}
 	return cache, nil
 }
@@ -67,28 +72,28 @@ func (c *AICache) Path() string {
 	return c.path
 }
 
-func (c *AICache) Get(key string) (*kernel.FullDecision, bool) {
+func (c *AICache) Get(key string) (string, bool) {
 	if c == nil || key == "" {
-		return nil, false
+		return "", false
 	}
 	c.mu.RLock()
 	entry, ok := c.Entries[key]
 	c.mu.RUnlock()
-	if !ok || entry.Decision == nil {
-		return nil, false
+	// Entries of the old context-keyed format have no response and never match
+	if !ok || entry.Response == "" {
+		return "", false
 	}
-	return cloneDecision(entry.Decision), true
+	return entry.Response, true
 }
 
-func (c *AICache) Put(key string, variant string, ts int64, decision *kernel.FullDecision) error {
-	if c == nil || key == "" || decision == nil {
+func (c *AICache) Put(key string, response string) error {
+	if c == nil || key == "" || response == "" {
 		return nil
 	}
-	entry := cachedDecision{
-		Key:           key,
-		PromptVariant: variant,
-		Timestamp:     ts,
-		Decision:      cloneDecision(decision),
+	entry := cachedResponse{
+		Key:        key,
+		Response:   response,
+		RecordedAt: time.Now().UnixMilli(),
 	}
 	c.mu.Lock()
 	c.Entries[key] = entry
@@ -109,60 +114,57 @@ func (c *AICache) save() error {
 	return writeFileAtomic(c.path, data, 0o644)
 }
 
-func cloneDecision(src *kernel.FullDecision) *kernel.FullDecision {
-	if src == nil {
-		return nil
-	}
-	data, err := json.Marshal(src)
-	if err != nil {
-		return nil
-	}
-	var dst kernel.FullDecision
-	if err := json.Unmarshal(data, &dst); err != nil {
-		return nil
+// computeCacheKey hashes the model identity and both prompts of an AI call
+func computeCacheKey(model, systemPrompt, userPrompt string) string {
+	h := sha256.New()
+	for _, part := range []string{model, systemPrompt, userPrompt} {
+		// Length-prefix each part so different splits never collide
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachingAIClient serves AI calls from the cache, recording new responses when record is set.
+// Parsing a replayed response reproduces the original decision, including re-prompt repairs.
+type cachingAIClient struct {
+	mcp.AIClient
+	cache      *AICache
+	model      string
+	record     bool
+	replayOnly bool
+	hits       int
+}
+
+func newCachingAIClient(inner mcp.AIClient, cache *AICache, model string, record, replayOnly bool) *cachingAIClient {
+	return &cachingAIClient{AIClient: inner, cache: cache, model: model, record: record, replayOnly: replayOnly}
+}
+
+// hitCount returns the number of AI calls served from the cache (0 for a nil client)
+func (c *cachingAIClient) hitCount() int {
+	if c == nil {
+		return 0
 	}
-	return &dst
+	return c.hits
 }
 
-func computeCacheKey(ctx *kernel.Context, variant string, ts int64) (string, error) {
-	if ctx == nil {
-		return "", fmt.Errorf("context is nil")
-	}
-	payload := struct {
-		Variant        string                   `json:"variant"`
-		Timestamp      int64                    `json:"ts"`
-		CurrentTime    string                   `json:"current_time"`
-		Account        kernel.AccountInfo     `json:"account"`
-		Positions      []kernel.PositionInfo  `json:"positions"`
-		CandidateCoins []kernel.CandidateCoin `json:"candidate_coins"`
-		MarketData     map[string]market.Data   `json:"market"`
-		MarginUsedPct  float64                  `json:"margin_used_pct"`
-		Runtime        int                      `json:"runtime_minutes"`
-		CallCount      int                      `json:"call_count"`
-	}{
-		Variant:        variant,
-		Timestamp:      ts,
-		CurrentTime:    ctx.CurrentTime,
-		Account:        ctx.Account,
-		Positions:      ctx.Positions,
-		CandidateCoins: ctx.CandidateCoins,
-		MarginUsedPct:  ctx.Account.MarginUsedPct,
-		Runtime:        ctx.RuntimeMinutes,
-		CallCount:      ctx.CallCount,
-		MarketData:     make(map[string]market.Data, len(ctx.MarketDataMap)),
-	}
-
-	for symbol, data := range ctx.MarketDataMap {
-		if data == nil {
-			continue
-		}
-		payload.MarketData[symbol] = *data
+func (c *cachingAIClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	key := computeCacheKey(c.model, systemPrompt, userPrompt)
+	if response, ok := c.cache.Get(key); ok {
+		c.hits++
+		return response, nil
+	}
+	if c.replayOnly {
+		return "", errReplayCacheMiss
 	}
 
-	bytes, err := json.Marshal(payload)
+	response, err := c.AIClient.CallWithMessages(systemPrompt, userPrompt)
 	if err != nil {
 		return "", err
 	}
-	sum := sha256.Sum256(bytes)
-	return hex.EncodeToString(sum[:]), nil
+	if c.record {
+		if err := c.cache.Put(key, response); err != nil {
+			logger.Infof("failed to persist ai cache %s: %v", c.cache.Path(), err)
+		}
+	}
+	return response, nil
 }
@@ -1,6 +1,9 @@
 package backtest
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -24,12 +27,42 @@ type LeverageConfig struct {
 	AltcoinLeverage int `json:"altcoin_leverage"`
 }
 
+// RiskOverrides replaces risk control parameters of the strategy (zero values keep the strategy's)
+type RiskOverrides struct {
+	MaxPositions       int     `json:"max_positions,omitempty"`
+	MinConfidence      int     `json:"min_confidence,omitempty"`
+	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio,omitempty"`
+	MaxMarginUsage     float64 `json:"max_margin_usage,omitempty"`
+}
+
+// isZero reports whether no override is set
+func (r RiskOverrides) isZero() bool {
+	return r == RiskOverrides{}
+}
+
+// apply sets the overridden parameters on rc
+func (r RiskOverrides) apply(rc *store.RiskControlConfig) {
+	if r.MaxPositions > 0 {
+		rc.MaxPositions = r.MaxPositions
+	}
+	if r.MinConfidence > 0 {
+		rc.MinConfidence = r.MinConfidence
+	}
+	if r.MinRiskRewardRatio > 0 {
+		rc.MinRiskRewardRatio = r.MinRiskRewardRatio
+	}
+	if r.MaxMarginUsage > 0 {
+		rc.MaxMarginUsage = r.MaxMarginUsage
+	}
+}
+
 // BacktestConfig describes the input configuration for a backtest run.
 type BacktestConfig struct {
 	RunID                string   `json:"run_id"`
 	UserID               string   `json:"user_id,omitempty"`
 	AIModelID            string   `json:"ai_model_id,omitempty"`
-	StrategyID           string   `json:"strategy_id,omitempty"` // Optional: use saved strategy from Strategy Studio
+	StrategyID           string   `json:"strategy_id,omitempty"`          // Optional: use saved strategy from Strategy Studio
+	StrategyConfigHash   string   `json:"strategy_config_hash,omitempty"` // Hash of the strategy config at start (set by server)
 	Symbols              []string `json:"symbols"`
 	Timeframes           []string `json:"timeframes"`
 	DecisionTimeframe    string   `json:"decision_timeframe"`
@@ -49,14 +82,27 @@ type BacktestConfig struct {
 
 	AICfg    AIConfig       `json:"ai"`
 	Leverage LeverageConfig `json:"leverage"`
+	Risk     RiskOverrides  `json:"risk,omitempty"`
 
 	SharedAICachePath         string `json:"ai_cache_path,omitempty"`
 	CheckpointIntervalBars    int    `json:"checkpoint_interval_bars,omitempty"`
 	CheckpointIntervalSeconds int    `json:"checkpoint_interval_seconds,omitempty"`
 	ReplayDecisionDir         string `json:"replay_decision_dir,omitempty"`
 
+	// Run mode: "" (single run over the whole range), "walkforward" (rolling train/test windows)
+	// or "sweep" (one run per combination of a parameter grid)
+	Mode        string            `json:"mode,omitempty"`
+	WalkForward WalkForwardConfig `json:"walk_forward,omitempty"`
+	Sweep       SweepConfig       `json:"sweep,omitempty"`
+	// Set on the segment runs of a walk-forward run
+	WalkForwardParent string `json:"walk_forward_parent,omitempty"`
+	// Set on the combination runs of a sweep run
+	SweepParent string `json:"sweep_parent,omitempty"`
+
 	// Internal: loaded strategy config (set by Manager when StrategyID is provided)
 	loadedStrategy *store.StrategyConfig `json:"-"`
+	// Internal: klines shared between the runs of a sweep
+	klines *klineCache `json:"-"`
 }
 
 // Validate performs validity checks on the configuration and fills in default values.
@@ -153,6 +199,22 @@ func (cfg *BacktestConfig) Validate() error {
 		cfg.Leverage.AltcoinLeverage = 5
 	}
 
+	cfg.Mode = strings.ToLower(strings.TrimSpace(cfg.Mode))
+	switch cfg.Mode {
+	case "", "single":
+		cfg.Mode = ""
+	case ModeWalkForward:
+		if err := cfg.WalkForward.validate(); err != nil {
+			return err
+		}
+	case ModeSweep:
+		if err := cfg.Sweep.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported mode '%s'", cfg.Mode)
+	}
+
 	return nil
 }
 
@@ -182,6 +244,55 @@ func validateFillPolicy(policy string) error {
 	}
 }
 
+// ParamsHash returns a stable hash of the run parameters that affect results
+// (market range, costs, leverage, AI model and prompt), excluding run identity and storage options.
+func (cfg *BacktestConfig) ParamsHash() string {
+	params := struct {
+		Symbols              []string       `json:"symbols"`
+		Timeframes           []string       `json:"timeframes"`
+		DecisionTimeframe    string         `json:"decision_timeframe"`
+		DecisionCadenceNBars int            `json:"decision_cadence_nbars"`
+		StartTS              int64          `json:"start_ts"`
+		EndTS                int64          `json:"end_ts"`
+		InitialBalance       float64        `json:"initial_balance"`
+		FeeBps               float64        `json:"fee_bps"`
+		SlippageBps          float64        `json:"slippage_bps"`
+		FillPolicy           string         `json:"fill_policy"`
+		PromptVariant        string         `json:"prompt_variant"`
+		PromptTemplate       string         `json:"prompt_template"`
+		CustomPrompt         string         `json:"custom_prompt"`
+		OverrideBasePrompt   bool           `json:"override_prompt"`
+		AIProvider           string         `json:"ai_provider"`
+		AIModel              string         `json:"ai_model"`
+		Leverage             LeverageConfig `json:"leverage"`
+		Risk                 *RiskOverrides `json:"risk,omitempty"`
+	}{
+		Symbols:              cfg.Symbols,
+		Timeframes:           cfg.Timeframes,
+		DecisionTimeframe:    cfg.DecisionTimeframe,
+		DecisionCadenceNBars: cfg.DecisionCadenceNBars,
+		StartTS:              cfg.StartTS,
+		EndTS:                cfg.EndTS,
+		InitialBalance:       cfg.InitialBalance,
+		FeeBps:               cfg.FeeBps,
+		SlippageBps:          cfg.SlippageBps,
+		FillPolicy:           cfg.FillPolicy,
+		PromptVariant:        cfg.PromptVariant,
+		PromptTemplate:       cfg.PromptTemplate,
+		CustomPrompt:         cfg.CustomPrompt,
+		OverrideBasePrompt:   cfg.OverrideBasePrompt,
+		AIProvider:           cfg.AICfg.Provider,
+		AIModel:              cfg.AICfg.Model,
+		Leverage:             cfg.Leverage,
+	}
+	if !cfg.Risk.isZero() {
+		params.Risk = &cfg.Risk
+	}
+	data, _ := json.Marshal(params)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // SetLoadedStrategy sets the loaded strategy config from database.
 func (cfg *BacktestConfig) SetLoadedStrategy(strategy *store.StrategyConfig) {
 	cfg.loadedStrategy = strategy
@@ -221,6 +332,8 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			result.RiskControl.AltcoinMaxLeverage = cfg.Leverage.AltcoinLeverage
 		}
 
+		cfg.Risk.apply(&result.RiskControl)
+
 		// Override custom prompt if provided in backtest config
 		if cfg.CustomPrompt != "" {
 			result.CustomPrompt = cfg.CustomPrompt
@@ -239,7 +352,7 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 		longerTF = cfg.Timeframes[len(cfg.Timeframes)-1]
 	}
 
-	return &store.StrategyConfig{
+	strategy := &store.StrategyConfig{
 		CoinSource: store.CoinSourceConfig{
 			SourceType: "static",
 			StaticCoins: cfg.Symbols,
@@ -282,4 +395,6 @@ func (cfg *BacktestConfig) ToStrategyConfig() *store.StrategyConfig {
 			MinConfidence:                75,
 		},
 	}
+	cfg.Risk.apply(&strategy.RiskControl)
+	return strategy
 }
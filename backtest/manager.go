@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"nofx/mcp"
 	"nofx/store"
@@ -21,16 +22,24 @@ type Manager struct {
 	cancels    map[string]context.CancelFunc
 	mcpClient  mcp.AIClient
 	aiResolver AIConfigResolver
+	onComplete RunCompletionHandler
+	// Active walk-forward and sweep runs (parent run ID -> cancel)
+	compositeRuns map[string]context.CancelFunc
 }
 
 type AIConfigResolver func(*BacktestConfig) error
 
+// RunCompletionHandler is called once a run finished (completed or liquidated) with its final metrics
+type RunCompletionHandler func(cfg BacktestConfig, meta *RunMetadata, metrics *Metrics)
+
 func NewManager(defaultClient mcp.AIClient) *Manager {
 	return &Manager{
 		runners:   make(map[string]*Runner),
 		metadata:  make(map[string]*RunMetadata),
 		cancels:   make(map[string]context.CancelFunc),
 		mcpClient: defaultClient,
+
+		compositeRuns: make(map[string]context.CancelFunc),
 	}
 }
 
@@ -40,10 +49,23 @@ func (m *Manager) SetAIResolver(resolver AIConfigResolver) {
 	m.aiResolver = resolver
 }
 
+// SetCompletionHandler registers a handler for finished runs (e.g. caching results per strategy)
+func (m *Manager) SetCompletionHandler(handler RunCompletionHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onComplete = handler
+}
+
 func (m *Manager) Start(ctx context.Context, cfg BacktestConfig) (*Runner, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	if cfg.Mode == ModeWalkForward {
+		return nil, fmt.Errorf("walk-forward runs must be started with StartWalkForward")
+	}
+	if cfg.Mode == ModeSweep {
+		return nil, fmt.Errorf("sweep runs must be started with StartSweep")
+	}
 	if err := m.resolveAIConfig(&cfg); err != nil {
 		return nil, err
 	}
@@ -205,6 +227,9 @@ func (m *Manager) Resume(runID string) error {
 	if err := cfgCopy.Validate(); err != nil {
 		return err
 	}
+	if cfgCopy.Mode == ModeWalkForward || cfgCopy.Mode == ModeSweep {
+		return fmt.Errorf("%s run %s cannot be resumed, start a new one", cfgCopy.Mode, runID)
+	}
 	if err := m.resolveAIConfig(&cfgCopy); err != nil {
 		return err
 	}
@@ -247,6 +272,9 @@ func (m *Manager) Resume(runID string) error {
 }
 
 func (m *Manager) Stop(runID string) error {
+	if m.cancelCompositeRun(runID) {
+		return nil
+	}
 	runner, ok := m.GetRunner(runID)
 	if ok {
 		runner.Stop()
@@ -289,6 +317,15 @@ func (m *Manager) UpdateLabel(runID, label string) (*RunMetadata, error) {
 }
 
 func (m *Manager) Delete(runID string) error {
+	if m.cancelCompositeRun(runID) {
+		// Child runs are removed below; give the active ones a moment to stop
+		time.Sleep(100 * time.Millisecond)
+	}
+	for _, childID := range append(walkForwardChildRunIDs(runID), sweepChildRunIDs(runID)...) {
+		if err := m.Delete(childID); err != nil {
+			logger.Infof("failed to delete child run %s of %s: %v", childID, runID, err)
+		}
+	}
 	runner, ok := m.GetRunner(runID)
 	if ok {
 		runner.Stop()
@@ -311,6 +348,39 @@ func (m *Manager) Delete(runID string) error {
 	return nil
 }
 
+// registerCompositeRun marks a walk-forward or sweep parent run active, returns the context its child runs use
+func (m *Manager) registerCompositeRun(ctx context.Context, runID string) (context.Context, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, active := m.compositeRuns[runID]; active {
+		return nil, fmt.Errorf("run %s is already active", runID)
+	}
+	if _, active := m.runners[runID]; active {
+		return nil, fmt.Errorf("run %s is already active", runID)
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	m.compositeRuns[runID] = cancel
+	return runCtx, nil
+}
+
+// unregisterCompositeRun removes a finished walk-forward or sweep parent run
+func (m *Manager) unregisterCompositeRun(runID string) {
+	m.mu.Lock()
+	delete(m.compositeRuns, runID)
+	m.mu.Unlock()
+}
+
+// cancelCompositeRun stops an active walk-forward or sweep run, reports whether runID was one
+func (m *Manager) cancelCompositeRun(runID string) bool {
+	m.mu.RLock()
+	cancel, ok := m.compositeRuns[runID]
+	m.mu.RUnlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
 func (m *Manager) LoadMetadata(runID string) (*RunMetadata, error) {
 	runner, ok := m.GetRunner(runID)
 	if ok {
@@ -389,7 +459,19 @@ func (m *Manager) launchWatcher(runID string, runner *Runner) {
 			delete(m.cancels, runID)
 		}
 		delete(m.runners, runID)
+		handler := m.onComplete
 		m.mu.Unlock()
+
+		// Walk-forward segments cover a sub-range only and sweep runs are parameter variants,
+		// don't report them as strategy results
+		if handler != nil && meta != nil && runner.cfg.WalkForwardParent == "" && runner.cfg.SweepParent == "" &&
+			(meta.State == RunStateCompleted || meta.State == RunStateLiquidated) {
+			metrics, err := LoadMetrics(runID)
+			if err != nil {
+				logger.Infof("backtest run %s: metrics unavailable for completion handler: %v", runID, err)
+			}
+			handler(runner.cfg, meta, metrics)
+		}
 	}()
 }
 
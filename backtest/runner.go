@@ -58,6 +58,7 @@ type Runner struct {
 	lastMetricsWrite time.Time
 
 	aiCache   *AICache
+	aiReplay  *cachingAIClient // Set when the AI cache is enabled, wraps mcpClient
 	cachePath string
 
 	lockInfo *RunLockInfo
@@ -102,11 +103,17 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 
 	var (
 		aiCache   *AICache
+		aiReplay  *cachingAIClient
 		cachePath string
 	)
-	if cfg.CacheAI || cfg.ReplayOnly || cfg.SharedAICachePath != "" {
-		cachePath = cfg.SharedAICachePath
-		if cachePath == "" {
+	if cfg.CacheAI || cfg.ReplayOnly || cfg.SharedAICachePath != "" || cfg.ReplayDecisionDir != "" {
+		switch {
+		case cfg.SharedAICachePath != "":
+			cachePath = cfg.SharedAICachePath
+		case cfg.ReplayDecisionDir != "":
+			// Replay the responses recorded by a previous run
+			cachePath = filepath.Join(cfg.ReplayDecisionDir, "ai_cache.json")
+		default:
 			cachePath = filepath.Join(runDir(cfg.RunID), "ai_cache.json")
 		}
 		cache, err := LoadAICache(cachePath)
@@ -114,6 +121,8 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 			return nil, fmt.Errorf("load ai cache: %w", err)
 		}
 		aiCache = cache
+		aiReplay = newCachingAIClient(client, cache, cfg.AICfg.Provider+"/"+cfg.AICfg.Model, cfg.CacheAI, cfg.ReplayOnly)
+		client = aiReplay
 	}
 
 	// Create strategy engine from backtest config for unified prompt generation
@@ -135,6 +144,7 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 		doneCh:         make(chan struct{}),
 		createdAt:      createdAt,
 		aiCache:        aiCache,
+		aiReplay:       aiReplay,
 		cachePath:      cachePath,
 	}
 
@@ -304,45 +314,26 @@ func (r *Runner) stepOnce() error {
 		}
 		record = rec
 
-		var (
-			fullDecision *kernel.FullDecision
-			fromCache    bool
-			cacheKey     string
-		)
-		if r.aiCache != nil {
-			if key, err := computeCacheKey(ctx, r.cfg.PromptVariant, ts); err == nil {
-				cacheKey = key
-				if cached, ok := r.aiCache.Get(cacheKey); ok {
-					fullDecision = cached
-					fromCache = true
-				} else if r.cfg.ReplayOnly {
-					decisionErr := fmt.Errorf("replay_only enabled but cache miss at %d", ts)
-					record.Success = false
-					record.ErrorMessage = fmt.Sprintf("cached decision not found for ts=%d", ts)
-					_ = r.logDecision(record)
-					return decisionErr
-				}
-			} else {
-				logger.Infof("failed to compute ai cache key: %v", err)
-			}
+		var fullDecision *kernel.FullDecision
+		hitsBefore := r.aiReplay.hitCount()
+		fd, err := r.invokeAIWithRetry(ctx)
+		if errors.Is(err, errReplayCacheMiss) {
+			record.Success = false
+			record.ErrorMessage = fmt.Sprintf("recorded AI response not found for ts=%d", ts)
+			_ = r.logDecision(record)
+			return fmt.Errorf("%w (ts=%d)", errReplayCacheMiss, ts)
 		}
-
-		if !fromCache {
-			fd, err := r.invokeAIWithRetry(ctx)
-			if err != nil {
-				decisionAttempted = true
-				hadError = true
-				record.Success = false
-				record.ErrorMessage = fmt.Sprintf("AI decision failed: %v", err)
-				execLog = append(execLog, fmt.Sprintf("⚠️ AI decision failed: %v", err))
-				r.setLastError(err)
-			} else {
-				fullDecision = fd
-				if r.cfg.CacheAI && r.aiCache != nil && cacheKey != "" {
-					if err := r.aiCache.Put(cacheKey, r.cfg.PromptVariant, ts, fullDecision); err != nil {
-						logger.Infof("failed to persist ai cache for %s: %v", r.cfg.RunID, err)
-					}
-				}
+		if err != nil {
+			decisionAttempted = true
+			hadError = true
+			record.Success = false
+			record.ErrorMessage = fmt.Sprintf("AI decision failed: %v", err)
+			execLog = append(execLog, fmt.Sprintf("⚠️ AI decision failed: %v", err))
+			r.setLastError(err)
+		} else {
+			fullDecision = fd
+			if r.aiReplay.hitCount() > hitsBefore {
+				execLog = append(execLog, "♻️ AI response replayed from cache")
 			}
 		}
 
@@ -540,7 +531,11 @@ func (r *Runner) buildDecisionContext(ts int64, marketData map[string]*market.Da
 
 	// Fetch OI ranking data if enabled in strategy (uses current data as approximation)
 	if strategyConfig.Indicators.EnableOIRanking {
-		ctx.OIRankingData = r.strategyEngine.FetchOIRankingData()
+		data, err := r.strategyEngine.FetchOIRankingData()
+		if err != nil {
+			ctx.UnavailableSources = append(ctx.UnavailableSources, kernel.SourceOIRanking)
+		}
+		ctx.OIRankingData = data
 		if ctx.OIRankingData != nil {
 			logger.Infof("📊 Backtest: OI ranking data ready: %d top, %d low positions",
 				len(ctx.OIRankingData.TopPositions), len(ctx.OIRankingData.LowPositions))
@@ -549,7 +544,11 @@ func (r *Runner) buildDecisionContext(ts int64, marketData map[string]*market.Da
 
 	// Fetch NetFlow ranking data if enabled in strategy
 	if strategyConfig.Indicators.EnableNetFlowRanking {
-		ctx.NetFlowRankingData = r.strategyEngine.FetchNetFlowRankingData()
+		data, err := r.strategyEngine.FetchNetFlowRankingData()
+		if err != nil {
+			ctx.UnavailableSources = append(ctx.UnavailableSources, kernel.SourceNetFlowRanking)
+		}
+		ctx.NetFlowRankingData = data
 		if ctx.NetFlowRankingData != nil {
 			logger.Infof("💰 Backtest: NetFlow ranking data ready: inst_in=%d, inst_out=%d",
 				len(ctx.NetFlowRankingData.InstitutionFutureTop), len(ctx.NetFlowRankingData.InstitutionFutureLow))
@@ -558,7 +557,11 @@ func (r *Runner) buildDecisionContext(ts int64, marketData map[string]*market.Da
 
 	// Fetch Price ranking data if enabled in strategy
 	if strategyConfig.Indicators.EnablePriceRanking {
-		ctx.PriceRankingData = r.strategyEngine.FetchPriceRankingData()
+		data, err := r.strategyEngine.FetchPriceRankingData()
+		if err != nil {
+			ctx.UnavailableSources = append(ctx.UnavailableSources, kernel.SourcePriceRanking)
+		}
+		ctx.PriceRankingData = data
 		if ctx.PriceRankingData != nil {
 			logger.Infof("📈 Backtest: Price ranking data ready for %d durations",
 				len(ctx.PriceRankingData.Durations))
@@ -573,8 +576,9 @@ func (r *Runner) buildDecisionContext(ts int64, marketData map[string]*market.Da
 			PositionCount:         accountInfo.PositionCount,
 			MarginUsedPct:         accountInfo.MarginUsedPct,
 		},
-		CandidateCoins: make([]string, 0, len(candidateCoins)),
-		Positions:      r.snapshotPositions(priceMap),
+		CandidateCoins:     make([]string, 0, len(candidateCoins)),
+		Positions:          r.snapshotPositions(priceMap),
+		UnavailableSources: ctx.UnavailableSources,
 	}
 	for _, coin := range candidateCoins {
 		record.CandidateCoins = append(record.CandidateCoins, coin.Symbol)
@@ -608,6 +612,9 @@ func (r *Runner) invokeAIWithRetry(ctx *kernel.Context) (*kernel.FullDecision, e
 		if err == nil {
 			return fd, nil
 		}
+		if errors.Is(err, errReplayCacheMiss) {
+			return nil, err // Retrying can't produce a recorded response
+		}
 		lastErr = err
 		delay := time.Duration(attempt+1) * 500 * time.Millisecond
 		time.Sleep(delay)
@@ -631,15 +638,29 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 	}
 	fillPrice := r.executionPrice(symbol, basePrice, ts)
 
+	// Auto-flip: close the opposite position before opening the new side
+	var flipTrades []TradeEvent
+	var flipLog string
+	if side := flipSide(dec.Action); side != "" && r.strategyEngine.GetConfig().RiskControl.AutoFlip && r.remainingPosition(symbol, side) > 0 {
+		closeDec := dec
+		closeDec.Action = "close_" + side
+		_, trades, _, err := r.executeDecision(closeDec, priceMap, ts, cycle)
+		if err != nil {
+			return actionRecord, nil, "", fmt.Errorf("auto-flip close %s failed: %w", side, err)
+		}
+		flipTrades = trades
+		flipLog = fmt.Sprintf("🔁 %s auto-flip: closed %s before %s", symbol, side, dec.Action)
+	}
+
 	switch dec.Action {
 	case "open_long":
 		qty := r.determineQuantity(dec, basePrice)
 		if qty <= 0 {
-			return actionRecord, nil, "", fmt.Errorf("invalid qty")
+			return actionRecord, flipTrades, flipLog, fmt.Errorf("invalid qty")
 		}
 		pos, fee, execPrice, err := r.account.Open(symbol, "long", qty, usedLeverage, fillPrice, ts)
 		if err != nil {
-			return actionRecord, nil, "", err
+			return actionRecord, flipTrades, flipLog, err
 		}
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
@@ -659,16 +680,16 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 			Cycle:         cycle,
 			PositionAfter: pos.Quantity,
 		}
-		return actionRecord, []TradeEvent{trade}, "", nil
+		return actionRecord, append(flipTrades, trade), flipLog, nil
 
 	case "open_short":
 		qty := r.determineQuantity(dec, basePrice)
 		if qty <= 0 {
-			return actionRecord, nil, "", fmt.Errorf("invalid qty")
+			return actionRecord, flipTrades, flipLog, fmt.Errorf("invalid qty")
 		}
 		pos, fee, execPrice, err := r.account.Open(symbol, "short", qty, usedLeverage, fillPrice, ts)
 		if err != nil {
-			return actionRecord, nil, "", err
+			return actionRecord, flipTrades, flipLog, err
 		}
 		actionRecord.Quantity = qty
 		actionRecord.Price = execPrice
@@ -688,9 +709,9 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 			Cycle:         cycle,
 			PositionAfter: pos.Quantity,
 		}
-		return actionRecord, []TradeEvent{trade}, "", nil
+		return actionRecord, append(flipTrades, trade), flipLog, nil
 
-	case "close_long":
+	case "close_long", "reduce_long":
 		qty := r.determineCloseQuantity(symbol, "long", dec)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
@@ -720,7 +741,7 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 		}
 		return actionRecord, []TradeEvent{trade}, "", nil
 
-	case "close_short":
+	case "close_short", "reduce_short":
 		qty := r.determineCloseQuantity(symbol, "short", dec)
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
@@ -798,6 +819,10 @@ func (r *Runner) determineQuantity(dec kernel.Decision, price float64) float64 {
 func (r *Runner) determineCloseQuantity(symbol, side string, dec kernel.Decision) float64 {
 	for _, pos := range r.account.Positions() {
 		if pos.Symbol == strings.ToUpper(symbol) && pos.Side == side {
+			// reduce_* closes only close_percentage of the position
+			if strings.HasPrefix(dec.Action, "reduce_") && dec.ClosePercentage > 0 && dec.ClosePercentage < 100 {
+				return pos.Quantity * dec.ClosePercentage / 100
+			}
 			return pos.Quantity
 		}
 	}
@@ -821,6 +846,17 @@ func (r *Runner) resolveLeverage(requested int, symbol string) int {
 	return 5
 }
 
+// flipSide returns the position side an open action flips with auto-flip ("long"/"short"), or ""
+func flipSide(action string) string {
+	switch action {
+	case "open_long":
+		return "short"
+	case "open_short":
+		return "long"
+	}
+	return ""
+}
+
 func (r *Runner) remainingPosition(symbol, side string) float64 {
 	for _, pos := range r.account.Positions() {
 		if pos.Symbol == strings.ToUpper(symbol) && pos.Side == side {
@@ -1441,7 +1477,7 @@ func sortDecisionsByPriority(decisions []kernel.Decision) []kernel.Decision {
 
 	priority := func(action string) int {
 		switch action {
-		case "close_long", "close_short":
+		case "close_long", "close_short", "reduce_long", "reduce_short":
 			return 1
 		case "open_long", "open_short":
 			return 2
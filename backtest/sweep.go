@@ -0,0 +1,423 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/market"
+)
+
+// ModeSweep runs the same strategy once per combination of a parameter grid
+const ModeSweep = "sweep"
+
+const (
+	maxSweepCombinations = 64
+	maxSweepConcurrency  = 8
+)
+
+// Parameters a sweep can vary
+const (
+	SweepLeverage           = "leverage" // Both BTC/ETH and altcoin leverage
+	SweepBTCETHLeverage     = "btc_eth_leverage"
+	SweepAltcoinLeverage    = "altcoin_leverage"
+	SweepMinConfidence      = "min_confidence" // 0-100, fractions (0.7) are read as percentages
+	SweepMaxPositions       = "max_positions"
+	SweepMinRiskRewardRatio = "min_risk_reward_ratio"
+	SweepMaxMarginUsage     = "max_margin_usage" // 0-1, percentages (80) are read as fractions
+)
+
+// SweepParam one swept parameter and the values it takes
+type SweepParam struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+}
+
+// SweepConfig configures a parameter sweep: every combination of the params' values runs as a
+// regular backtest over the full range, at most Concurrency at a time.
+type SweepConfig struct {
+	Params      []SweepParam `json:"params"`
+	Concurrency int          `json:"concurrency,omitempty"` // Runs executed in parallel (default 2)
+}
+
+// validate fills in defaults and checks the grid
+func (sc *SweepConfig) validate() error {
+	if len(sc.Params) == 0 {
+		return fmt.Errorf("sweep.params must list at least one parameter")
+	}
+	seen := make(map[string]bool, len(sc.Params))
+	combinations := 1
+	for i := range sc.Params {
+		p := &sc.Params[i]
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if seen[p.Name] {
+			return fmt.Errorf("sweep parameter '%s' is listed twice", p.Name)
+		}
+		seen[p.Name] = true
+		if len(p.Values) == 0 {
+			return fmt.Errorf("sweep parameter '%s' has no values", p.Name)
+		}
+		for j, v := range p.Values {
+			normalized, err := normalizeSweepValue(p.Name, v)
+			if err != nil {
+				return err
+			}
+			p.Values[j] = normalized
+		}
+		combinations *= len(p.Values)
+		if combinations > maxSweepCombinations {
+			return fmt.Errorf("sweep grid exceeds %d combinations", maxSweepCombinations)
+		}
+	}
+	if sc.Concurrency <= 0 {
+		sc.Concurrency = 2
+	}
+	if sc.Concurrency > maxSweepConcurrency {
+		return fmt.Errorf("sweep.concurrency must be between 1 and %d", maxSweepConcurrency)
+	}
+	return nil
+}
+
+// normalizeSweepValue checks a swept value's range and converts it to the strategy's unit
+func normalizeSweepValue(name string, v float64) (float64, error) {
+	switch name {
+	case SweepLeverage, SweepBTCETHLeverage, SweepAltcoinLeverage:
+		if v < 1 || v > 125 || v != math.Trunc(v) {
+			return 0, fmt.Errorf("%s values must be whole numbers between 1 and 125", name)
+		}
+	case SweepMinConfidence:
+		if v > 0 && v <= 1 {
+			v *= 100
+		}
+		if v < 0 || v > 100 {
+			return 0, fmt.Errorf("%s values must be between 0 and 100", name)
+		}
+		v = math.Round(v)
+	case SweepMaxPositions:
+		if v < 1 || v > 20 || v != math.Trunc(v) {
+			return 0, fmt.Errorf("%s values must be whole numbers between 1 and 20", name)
+		}
+	case SweepMinRiskRewardRatio:
+		if v <= 0 || v > 20 {
+			return 0, fmt.Errorf("%s values must be between 0 and 20", name)
+		}
+	case SweepMaxMarginUsage:
+		if v > 1 {
+			v /= 100
+		}
+		if v <= 0 || v > 1 {
+			return 0, fmt.Errorf("%s values must be between 0 and 1", name)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported sweep parameter '%s'", name)
+	}
+	return v, nil
+}
+
+// ExpandSweep returns every combination of the params' values, the last parameter varying fastest
+func ExpandSweep(params []SweepParam) []map[string]float64 {
+	combos := []map[string]float64{{}}
+	for _, p := range params {
+		next := make([]map[string]float64, 0, len(combos)*len(p.Values))
+		for _, combo := range combos {
+			for _, v := range p.Values {
+				c := make(map[string]float64, len(combo)+1)
+				for k, existing := range combo {
+					c[k] = existing
+				}
+				c[p.Name] = v
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// applySweepValues sets a combination's values on a run config
+func applySweepValues(cfg *BacktestConfig, values map[string]float64) {
+	for name, v := range values {
+		switch name {
+		case SweepLeverage:
+			cfg.Leverage.BTCETHLeverage = int(v)
+			cfg.Leverage.AltcoinLeverage = int(v)
+		case SweepBTCETHLeverage:
+			cfg.Leverage.BTCETHLeverage = int(v)
+		case SweepAltcoinLeverage:
+			cfg.Leverage.AltcoinLeverage = int(v)
+		case SweepMinConfidence:
+			cfg.Risk.MinConfidence = int(v)
+		case SweepMaxPositions:
+			cfg.Risk.MaxPositions = int(v)
+		case SweepMinRiskRewardRatio:
+			cfg.Risk.MinRiskRewardRatio = v
+		case SweepMaxMarginUsage:
+			cfg.Risk.MaxMarginUsage = v
+		}
+	}
+}
+
+// SweepResult one combination of a sweep and its metrics
+type SweepResult struct {
+	Index          int                `json:"index"`
+	Params         map[string]float64 `json:"params"`
+	RunID          string             `json:"run_id"`
+	Rank           int                `json:"rank,omitempty"` // 1 = highest total return among completed runs
+	TotalReturnPct float64            `json:"total_return_pct"`
+	MaxDrawdownPct float64            `json:"max_drawdown_pct"`
+	SharpeRatio    float64            `json:"sharpe_ratio"`
+	ProfitFactor   float64            `json:"profit_factor"`
+	WinRate        float64            `json:"win_rate"`
+	Trades         int                `json:"trades"`
+	Liquidated     bool               `json:"liquidated"`
+	Error          string             `json:"error,omitempty"`
+}
+
+// SweepReport comparison table of a sweep run.
+// The Metrics the report is attached to are those of the best (rank 1) combination.
+type SweepReport struct {
+	Params    []SweepParam  `json:"params"`
+	Results   []SweepResult `json:"results"`
+	Completed int           `json:"completed"`
+	BestRunID string        `json:"best_run_id,omitempty"`
+}
+
+// rank orders completed results by total return (ties by Sharpe ratio) and returns the best one's index
+func (r *SweepReport) rank() int {
+	var completed []int
+	for i, res := range r.Results {
+		if res.Error == "" && res.RunID != "" {
+			completed = append(completed, i)
+		}
+	}
+	sort.SliceStable(completed, func(a, b int) bool {
+		ra, rb := r.Results[completed[a]], r.Results[completed[b]]
+		if ra.TotalReturnPct != rb.TotalReturnPct {
+			return ra.TotalReturnPct > rb.TotalReturnPct
+		}
+		return ra.SharpeRatio > rb.SharpeRatio
+	})
+	for pos, idx := range completed {
+		r.Results[idx].Rank = pos + 1
+	}
+	r.Completed = len(completed)
+	if len(completed) == 0 {
+		return -1
+	}
+	r.BestRunID = r.Results[completed[0]].RunID
+	return completed[0]
+}
+
+// klineCache shares fetched klines between the runs of a sweep, which all cover the same range
+type klineCache struct {
+	mu      sync.Mutex
+	entries map[string][]market.Kline
+}
+
+func newKlineCache() *klineCache {
+	return &klineCache{entries: make(map[string][]market.Kline)}
+}
+
+// getRange returns klines of symbol/tf in [start, end], fetching them once. A nil cache always fetches.
+// The returned slice is shared and must not be modified.
+func (c *klineCache) getRange(symbol, tf string, start, end time.Time) ([]market.Kline, error) {
+	if c == nil {
+		return market.GetKlinesRange(symbol, tf, start, end)
+	}
+	key := fmt.Sprintf("%s|%s|%d|%d", symbol, tf, start.Unix(), end.Unix())
+	// Held during the fetch so concurrent runs wait for the first one instead of fetching again
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if klines, ok := c.entries[key]; ok {
+		return klines, nil
+	}
+	klines, err := market.GetKlinesRange(symbol, tf, start, end)
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = klines
+	return klines, nil
+}
+
+// StartSweep starts a parameter sweep: each grid combination runs as a regular backtest
+// (Sweep.Concurrency at a time, sharing fetched market data), and the parent run collects the
+// comparison table in Metrics.Sweep.
+func (m *Manager) StartSweep(ctx context.Context, cfg BacktestConfig) (*RunMetadata, error) {
+	cfg.Mode = ModeSweep
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.resolveAIConfig(&cfg); err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	runCtx, err := m.registerCompositeRun(ctx, cfg.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	persistCfg := cfg
+	persistCfg.AICfg.APIKey = ""
+	if err := SaveConfig(cfg.RunID, &persistCfg); err != nil {
+		m.cancelCompositeRun(cfg.RunID)
+		m.unregisterCompositeRun(cfg.RunID)
+		return nil, err
+	}
+
+	meta := &RunMetadata{
+		RunID:  cfg.RunID,
+		UserID: cfg.UserID,
+		State:  RunStateRunning,
+		Summary: RunSummary{
+			SymbolCount: len(cfg.Symbols),
+			DecisionTF:  cfg.DecisionTimeframe,
+			EquityLast:  cfg.InitialBalance,
+		},
+	}
+	m.storeMetadata(cfg.RunID, meta)
+
+	go m.runSweep(runCtx, cfg, *meta)
+
+	metaCopy := *meta
+	return &metaCopy, nil
+}
+
+// runSweep runs the grid combinations and records the comparison table on the parent run
+func (m *Manager) runSweep(ctx context.Context, cfg BacktestConfig, meta RunMetadata) {
+	defer func() {
+		m.cancelCompositeRun(cfg.RunID)
+		m.unregisterCompositeRun(cfg.RunID)
+	}()
+
+	combos := ExpandSweep(cfg.Sweep.Params)
+	report := &SweepReport{Params: cfg.Sweep.Params, Results: make([]SweepResult, len(combos))}
+	metrics := make([]*Metrics, len(combos))
+	cache := newKlineCache()
+
+	var (
+		mu   sync.Mutex
+		done int
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, cfg.Sweep.Concurrency)
+	for i, combo := range combos {
+		report.Results[i] = SweepResult{Index: i + 1, Params: combo}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, combo map[string]float64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			runID := fmt.Sprintf("%s_s%02d", cfg.RunID, i+1)
+			result, err := m.runSweepCombination(ctx, cfg, runID, combo, cache)
+
+			mu.Lock()
+			defer mu.Unlock()
+			res := &report.Results[i]
+			res.RunID = runID
+			if err != nil {
+				res.Error = err.Error()
+				logger.Infof("sweep %s combination %d %v failed: %v", cfg.RunID, i+1, combo, err)
+			} else {
+				metrics[i] = result
+				res.TotalReturnPct = result.TotalReturnPct
+				res.MaxDrawdownPct = result.MaxDrawdownPct
+				res.SharpeRatio = result.SharpeRatio
+				res.ProfitFactor = result.ProfitFactor
+				res.WinRate = result.WinRate
+				res.Trades = result.Trades
+				res.Liquidated = result.Liquidated
+			}
+			done++
+			meta.Summary.ProgressPct = float64(done) / float64(len(combos)) * 100
+			metaCopy := meta
+			m.storeMetadata(cfg.RunID, &metaCopy)
+		}(i, combo)
+	}
+	wg.Wait()
+
+	aggregate := &Metrics{SymbolStats: make(map[string]SymbolMetrics)}
+	if best := report.rank(); best >= 0 {
+		aggregate = metrics[best]
+		meta.Summary.EquityLast = cfg.InitialBalance * (1 + aggregate.TotalReturnPct/100)
+		meta.Summary.MaxDrawdownPct = aggregate.MaxDrawdownPct
+		meta.Summary.Liquidated = aggregate.Liquidated
+	}
+	aggregate.Sweep = report
+	if err := saveMetrics(cfg.RunID, aggregate); err != nil {
+		logger.Infof("sweep %s: failed to save metrics: %v", cfg.RunID, err)
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		meta.State = RunStateStopped
+	case report.Completed == 0:
+		meta.State = RunStateFailed
+		meta.LastError = "no sweep combination completed"
+	default:
+		meta.State = RunStateCompleted
+		meta.Summary.ProgressPct = 100
+	}
+	m.storeMetadata(cfg.RunID, &meta)
+	logger.Infof("sweep %s finished: %d/%d combinations, best %s", cfg.RunID, report.Completed, len(combos), report.BestRunID)
+}
+
+// runSweepCombination runs one combination of a sweep as a regular backtest and waits for it
+func (m *Manager) runSweepCombination(ctx context.Context, parent BacktestConfig, runID string, values map[string]float64, cache *klineCache) (*Metrics, error) {
+	run := parent
+	run.RunID = runID
+	run.Mode = ""
+	run.Sweep = SweepConfig{}
+	run.SweepParent = parent.RunID
+	run.Symbols = append([]string(nil), parent.Symbols...)
+	run.klines = cache
+	applySweepValues(&run, values)
+
+	runner, err := m.Start(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if err := runner.Wait(); err != nil {
+		return nil, err
+	}
+	if state := runner.Status(); state != RunStateCompleted && state != RunStateLiquidated {
+		return nil, fmt.Errorf("run %s ended in state %s", runID, state)
+	}
+	metrics, err := LoadMetrics(runID)
+	if err != nil {
+		return nil, fmt.Errorf("load metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// sweepChildRunIDs returns the combination runs of a sweep run
+func sweepChildRunIDs(parentID string) []string {
+	runIDs, err := LoadRunIDs()
+	if err != nil {
+		return nil
+	}
+	prefix := parentID + "_s"
+	var children []string
+	for _, id := range runIDs {
+		if rest, ok := strings.CutPrefix(id, prefix); ok && len(rest) >= 2 && strings.Trim(rest, "0123456789") == "" {
+			children = append(children, id)
+		}
+	}
+	return children
+}
@@ -91,6 +91,10 @@ type Metrics struct {
 	WorstSymbol    string                   `json:"worst_symbol"`
 	SymbolStats    map[string]SymbolMetrics `json:"symbol_stats"`
 	Liquidated     bool                     `json:"liquidated"`
+	// Per-window results of a walk-forward run (the fields above are the aggregated out-of-sample metrics)
+	WalkForward *WalkForwardReport `json:"walk_forward,omitempty"`
+	// Comparison table of a sweep run (the fields above are the best combination's metrics)
+	Sweep *SweepReport `json:"sweep,omitempty"`
 }
 
 // SymbolMetrics records performance for a single symbol.
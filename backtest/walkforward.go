@@ -0,0 +1,334 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/market"
+)
+
+// ModeWalkForward runs the strategy over sequential train/test windows instead of one range
+const ModeWalkForward = "walkforward"
+
+// WalkForwardConfig configures a walk-forward run.
+// The date range is split into rolling windows; each window's test segment follows its
+// train segment and the next window starts one test length later, so test segments
+// tile the end of the range without overlapping.
+type WalkForwardConfig struct {
+	Windows  int     `json:"windows"`             // Number of train/test windows (default 4)
+	TrainPct float64 `json:"train_pct"`           // Share of each window used for training, % (default 70)
+	InSample bool    `json:"in_sample,omitempty"` // Also backtest the train segments to compare in- vs out-of-sample results
+}
+
+// validate fills in defaults and checks bounds
+func (wf *WalkForwardConfig) validate() error {
+	if wf.Windows <= 0 {
+		wf.Windows = 4
+	}
+	if wf.Windows < 2 || wf.Windows > 20 {
+		return fmt.Errorf("walk_forward.windows must be between 2 and 20")
+	}
+	if wf.TrainPct == 0 {
+		wf.TrainPct = 70
+	}
+	if wf.TrainPct < 10 || wf.TrainPct > 90 {
+		return fmt.Errorf("walk_forward.train_pct must be between 10 and 90")
+	}
+	return nil
+}
+
+// WalkForwardWindow one train/test window and its results
+type WalkForwardWindow struct {
+	Index        int   `json:"index"`
+	TrainStartTS int64 `json:"train_start_ts"`
+	TrainEndTS   int64 `json:"train_end_ts"`
+	TestStartTS  int64 `json:"test_start_ts"`
+	TestEndTS    int64 `json:"test_end_ts"`
+
+	RunID         string   `json:"run_id,omitempty"`           // Out-of-sample (test segment) run
+	InSampleRunID string   `json:"in_sample_run_id,omitempty"` // Train segment run (in_sample only)
+	OutOfSample   *Metrics `json:"out_of_sample,omitempty"`
+	InSample      *Metrics `json:"in_sample,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// WalkForwardReport per-window results of a walk-forward run.
+// The aggregated out-of-sample metrics are the Metrics the report is attached to.
+type WalkForwardReport struct {
+	Windows          []WalkForwardWindow `json:"windows"`
+	CompletedWindows int                 `json:"completed_windows"`
+	PositiveWindows  int                 `json:"positive_windows"` // Windows with a positive out-of-sample return
+	AvgReturnPct     float64             `json:"avg_return_pct"`   // Mean out-of-sample return per window
+	// Mean out-of-sample return / mean in-sample return (in_sample only, 0 when in-sample return <= 0)
+	Efficiency float64 `json:"efficiency,omitempty"`
+}
+
+// SplitWalkForward splits [startTS, endTS] into rolling train/test windows (unix seconds)
+func SplitWalkForward(startTS, endTS int64, windows int, trainPct float64) []WalkForwardWindow {
+	if windows <= 0 || endTS <= startTS {
+		return nil
+	}
+	testFrac := 1 - trainPct/100
+	// windowLen + (windows-1) × testLen = total, testLen = windowLen × testFrac
+	windowLen := float64(endTS-startTS) / (1 + float64(windows-1)*testFrac)
+	testLen := int64(windowLen * testFrac)
+	trainLen := int64(windowLen) - testLen
+
+	result := make([]WalkForwardWindow, 0, windows)
+	for i := 0; i < windows; i++ {
+		trainStart := startTS + int64(i)*testLen
+		testStart := trainStart + trainLen
+		testEnd := testStart + testLen
+		if i == windows-1 {
+			testEnd = endTS
+		}
+		result = append(result, WalkForwardWindow{
+			Index:        i + 1,
+			TrainStartTS: trainStart,
+			TrainEndTS:   testStart,
+			TestStartTS:  testStart,
+			TestEndTS:    testEnd,
+		})
+	}
+	return result
+}
+
+// StartWalkForward starts a walk-forward run: each window's segments run as regular backtests
+// one after another, and the parent run collects the stitched out-of-sample equity curve,
+// trades and aggregated metrics (with the per-window report in Metrics.WalkForward).
+func (m *Manager) StartWalkForward(ctx context.Context, cfg BacktestConfig) (*RunMetadata, error) {
+	cfg.Mode = ModeWalkForward
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.resolveAIConfig(&cfg); err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	windows := SplitWalkForward(cfg.StartTS, cfg.EndTS, cfg.WalkForward.Windows, cfg.WalkForward.TrainPct)
+	if tfDur, err := market.TFDuration(cfg.DecisionTimeframe); err == nil {
+		minTest := tfDur * time.Duration(cfg.DecisionCadenceNBars)
+		if test := time.Duration(windows[0].TestEndTS-windows[0].TestStartTS) * time.Second; test < minTest {
+			return nil, fmt.Errorf("test windows (%v) are shorter than one decision interval (%v), use fewer windows or a longer range", test, minTest)
+		}
+	}
+
+	runCtx, err := m.registerCompositeRun(ctx, cfg.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SaveConfig(cfg.RunID, &cfg); err != nil {
+		m.cancelCompositeRun(cfg.RunID)
+		m.unregisterCompositeRun(cfg.RunID)
+		return nil, err
+	}
+
+	meta := &RunMetadata{
+		RunID:  cfg.RunID,
+		UserID: cfg.UserID,
+		State:  RunStateRunning,
+		Summary: RunSummary{
+			SymbolCount: len(cfg.Symbols),
+			DecisionTF:  cfg.DecisionTimeframe,
+			EquityLast:  cfg.InitialBalance,
+		},
+	}
+	m.storeMetadata(cfg.RunID, meta)
+
+	go m.runWalkForward(runCtx, cfg, windows, *meta)
+
+	metaCopy := *meta
+	return &metaCopy, nil
+}
+
+// runWalkForward runs the windows sequentially and records the aggregated results on the parent run
+func (m *Manager) runWalkForward(ctx context.Context, cfg BacktestConfig, windows []WalkForwardWindow, meta RunMetadata) {
+	defer func() {
+		m.cancelCompositeRun(cfg.RunID)
+		m.unregisterCompositeRun(cfg.RunID)
+	}()
+
+	initial := cfg.InitialBalance
+	equity := initial
+	peak := initial
+	var (
+		stitched  []EquityPoint
+		oosEvents []TradeEvent
+		lastErr   error
+		liquidate bool
+	)
+
+	report := &WalkForwardReport{Windows: windows}
+	var sumOOS, sumIS float64
+	var countIS int
+
+	for i := range windows {
+		if ctx.Err() != nil {
+			break
+		}
+		w := &windows[i]
+
+		if cfg.WalkForward.InSample {
+			w.InSampleRunID = fmt.Sprintf("%s_w%02d_is", cfg.RunID, w.Index)
+			metrics, _, _, err := m.runWalkForwardSegment(ctx, cfg, w.InSampleRunID, w.TrainStartTS, w.TrainEndTS)
+			if err != nil {
+				logger.Infof("walk-forward %s window %d in-sample failed: %v", cfg.RunID, w.Index, err)
+			} else {
+				w.InSample = metrics
+				sumIS += metrics.TotalReturnPct
+				countIS++
+			}
+		}
+
+		w.RunID = fmt.Sprintf("%s_w%02d_oos", cfg.RunID, w.Index)
+		metrics, points, events, err := m.runWalkForwardSegment(ctx, cfg, w.RunID, w.TestStartTS, w.TestEndTS)
+		if err != nil {
+			w.Error = err.Error()
+			lastErr = err
+			logger.Infof("walk-forward %s window %d failed: %v", cfg.RunID, w.Index, err)
+			continue
+		}
+		w.OutOfSample = metrics
+		report.CompletedWindows++
+		sumOOS += metrics.TotalReturnPct
+		if metrics.TotalReturnPct > 0 {
+			report.PositiveWindows++
+		}
+		liquidate = liquidate || metrics.Liquidated
+
+		// Chain the window onto the curve so far: every segment starts from the initial balance,
+		// so scale it by the equity reached at the end of the previous segment
+		scale := equity / initial
+		for _, pt := range points {
+			pt.Equity *= scale
+			pt.Available *= scale
+			pt.PnL = pt.Equity - initial
+			pt.PnLPct = pt.PnL / initial * 100
+			if pt.Equity > peak {
+				peak = pt.Equity
+			}
+			pt.DrawdownPct = 0
+			if peak > 0 {
+				pt.DrawdownPct = (peak - pt.Equity) / peak * 100
+			}
+			stitched = append(stitched, pt)
+			if err := appendEquityPoint(cfg.RunID, pt); err != nil {
+				logger.Infof("walk-forward %s: failed to save equity point: %v", cfg.RunID, err)
+			}
+		}
+		if len(points) > 0 {
+			equity = stitched[len(stitched)-1].Equity
+		}
+		for _, ev := range events {
+			oosEvents = append(oosEvents, ev)
+			if err := appendTradeEvent(cfg.RunID, ev); err != nil {
+				logger.Infof("walk-forward %s: failed to save trade event: %v", cfg.RunID, err)
+			}
+		}
+
+		meta.Summary.ProgressPct = float64(w.Index) / float64(len(windows)) * 100
+		meta.Summary.EquityLast = equity
+		meta.Summary.MaxDrawdownPct = maxDrawdown(stitched, nil)
+		metaCopy := meta
+		m.storeMetadata(cfg.RunID, &metaCopy)
+	}
+
+	if report.CompletedWindows > 0 {
+		report.AvgReturnPct = sumOOS / float64(report.CompletedWindows)
+	}
+	if countIS > 0 && sumIS > 0 {
+		report.Efficiency = report.AvgReturnPct / (sumIS / float64(countIS))
+	}
+
+	aggregate := &Metrics{
+		SymbolStats:    make(map[string]SymbolMetrics),
+		TotalReturnPct: (equity - initial) / initial * 100,
+		MaxDrawdownPct: maxDrawdown(stitched, nil),
+		SharpeRatio:    sharpeRatio(stitched),
+		Liquidated:     liquidate,
+		WalkForward:    report,
+	}
+	fillTradeMetrics(aggregate, oosEvents)
+	if err := saveMetrics(cfg.RunID, aggregate); err != nil {
+		logger.Infof("walk-forward %s: failed to save metrics: %v", cfg.RunID, err)
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		meta.State = RunStateStopped
+	case report.CompletedWindows == 0:
+		meta.State = RunStateFailed
+		if lastErr != nil {
+			meta.LastError = lastErr.Error()
+		}
+	default:
+		meta.State = RunStateCompleted
+		meta.Summary.ProgressPct = 100
+	}
+	meta.Summary.EquityLast = equity
+	meta.Summary.MaxDrawdownPct = aggregate.MaxDrawdownPct
+	meta.Summary.Liquidated = liquidate
+	m.storeMetadata(cfg.RunID, &meta)
+	logger.Infof("walk-forward %s finished: %d/%d windows, out-of-sample return %.2f%%",
+		cfg.RunID, report.CompletedWindows, len(windows), aggregate.TotalReturnPct)
+}
+
+// runWalkForwardSegment runs one segment of a walk-forward run as a regular backtest and waits for it
+func (m *Manager) runWalkForwardSegment(ctx context.Context, parent BacktestConfig, runID string, startTS, endTS int64) (*Metrics, []EquityPoint, []TradeEvent, error) {
+	seg := parent
+	seg.RunID = runID
+	seg.StartTS = startTS
+	seg.EndTS = endTS
+	seg.Mode = ""
+	seg.WalkForward = WalkForwardConfig{}
+	seg.WalkForwardParent = parent.RunID
+	seg.Symbols = append([]string(nil), parent.Symbols...)
+
+	runner, err := m.Start(ctx, seg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := runner.Wait(); err != nil {
+		return nil, nil, nil, err
+	}
+	if state := runner.Status(); state != RunStateCompleted && state != RunStateLiquidated {
+		return nil, nil, nil, fmt.Errorf("run %s ended in state %s", runID, state)
+	}
+
+	metrics, err := LoadMetrics(runID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load metrics: %w", err)
+	}
+	points, err := LoadEquityPoints(runID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load equity: %w", err)
+	}
+	events, err := LoadTradeEvents(runID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load trades: %w", err)
+	}
+	return metrics, points, events, nil
+}
+
+// walkForwardChildRunIDs returns the segment runs of a walk-forward run
+func walkForwardChildRunIDs(parentID string) []string {
+	runIDs, err := LoadRunIDs()
+	if err != nil {
+		return nil
+	}
+	prefix := parentID + "_w"
+	var children []string
+	for _, id := range runIDs {
+		if strings.HasPrefix(id, prefix) && (strings.HasSuffix(id, "_oos") || strings.HasSuffix(id, "_is")) {
+			children = append(children, id)
+		}
+	}
+	return children
+}
@@ -18,7 +18,8 @@ type Config struct {
 	APIServerPort       int
 	JWTSecret           string
 	RegistrationEnabled bool
-	MaxUsers            int // Maximum number of users allowed (0 = unlimited, default = 10)
+	MaxUsers            int      // Maximum number of users allowed (0 = unlimited, default = 10)
+	AdminEmails         []string // Registered users with these emails get the admin role at startup
 
 	// Database configuration
 	DBType     string // sqlite or postgres
@@ -30,6 +31,10 @@ type Config struct {
 	DBName     string // PostgreSQL database name
 	DBSSLMode  string // PostgreSQL SSL mode
 
+	// SQLite tuning (ignored for PostgreSQL)
+	DBJournalMode   string // SQLite journal mode: WAL (default) or DELETE for filesystems without WAL support
+	DBBusyTimeoutMs int    // How long SQLite waits for a lock before "database is locked" (default 5000)
+
 	// Security configuration
 	// TransportEncryption enables browser-side encryption for API keys
 	// Requires HTTPS or localhost. Set to false for HTTP access via IP.
@@ -40,6 +45,67 @@ type Config struct {
 	// Set EXPERIENCE_IMPROVEMENT=false to disable
 	ExperienceImprovement bool
 
+	// Data retention
+	EquityRetentionDays     int // Equity snapshots older than this are downsampled (0 = disabled, default 7)
+	EquityDownsampleMinutes int // Target resolution for downsampled equity snapshots (default 60)
+	RecordRetentionDays     int // Decision records, equity snapshots and fills older than this are deleted (0 = keep all)
+	RecordRetentionMax      int // Max decision records, equity snapshots and fills kept per trader (0 = unlimited)
+	RecordPruneBatchSize    int // Rows deleted per batch by the pruner (default 500)
+	DecisionArchiveDays     int // Prompts/responses of decision records older than this are compressed (0 = disabled)
+
+	// Order sync
+	OrderSyncMaxRetries       int  // Retries per sync cycle before the cycle counts as failed (default 2)
+	OrderSyncUnhealthyAfter   int  // Consecutive failed cycles before sync is marked unhealthy (default 5)
+	OrderSyncPauseOnUnhealthy bool // Pause trading while order sync is unhealthy (default false)
+
+	// Order fill confirmation
+	OrderFillPollAttempts   int    // Order status polls after submitting an order (default 5)
+	OrderFillPollIntervalMs int    // Milliseconds between order status polls (default 500)
+	OrderFillOverrides      string // Per-exchange poll settings, e.g. "gate=10x300ms,okx=8x1s"
+	OrderFillUserStream     bool   // Confirm fills via exchange user data streams where supported (default true)
+
+	// Signed request validity window
+	ExchangeRecvWindowMs int // recvWindow of signed Binance/Bybit/Aster requests (0 = exchange default)
+
+	// Strategy data cache
+	StrategyDataCacheTTLSeconds int // TTL for cached indicator data shared across traders (0 = disabled, default 60)
+
+	// AI call concurrency
+	MaxConcurrentAICalls int // Max AI decision calls in flight across all traders, others queue (0 = unlimited)
+
+	// AI prompt log files (per trader, for debugging strategies)
+	PromptLogEnabled    bool   // Write each cycle's prompts and AI response to per-trader files (default false)
+	PromptLogDir        string // Directory of the prompt log files (default data/prompt_logs)
+	PromptLogMaxSizeMB  int    // Rotate a trader's prompt log beyond this size (default 10)
+	PromptLogMaxBackups int    // Rotated prompt log files kept per trader (default 5)
+
+	// Logging
+	LogLevel            string // Global log level: debug, info, warn, error (default info)
+	TraderLogEnabled    bool   // Also write each trader's log lines to its own file (default false)
+	TraderLogDir        string // Directory of the per-trader log files (default data/trader_logs)
+	TraderLogMaxSizeMB  int    // Rotate a trader's log file beyond this size (default 20)
+	TraderLogMaxBackups int    // Rotated log files kept per trader (default 3)
+
+	// External signals
+	SignalRateLimitPerMinute int // Max signals accepted per trader per minute (default 6)
+
+	// Balance sync guard
+	BalanceSyncMaxChangePct float64 // Max balance change (%) accepted by balance sync without confirmation (0 = no check, default 50)
+	BalanceSyncMode         string  // "reset": balance sync moves initial_balance to equity (default); "adjust": records a deposit/withdrawal instead
+	TransferReconcileMin    int     // Minutes between imports of exchange deposit/withdrawal history into the PnL baseline (0 = disabled)
+	FundingReconcileMin     int     // Minutes between imports of exchange funding payment history into realized PnL (0 = disabled, default 60)
+
+	// API rate limiting and audit
+	APIRateLimitPerMinute int    // Requests per user (or client IP) per endpoint per minute (0 = disabled, default 120)
+	APIRateLimitOverrides string // Per-endpoint limits, e.g. "/api/equity-history-batch=10,/api/decisions=30"
+	APIAuditLog           bool   // Record user, endpoint, status and latency of API requests (default true)
+	APIAuditRetentionDays int    // Audit log entries older than this are deleted (0 = keep all, default 30)
+
+	// Discord bot (optional, disabled without a token)
+	DiscordBotToken string // Bot token for the /nofx slash commands
+	DiscordGuildID  string // Register commands in this guild only (empty = global)
+	DiscordUserMap  string // Discord user ID -> NOFX user ID or email, e.g. "1234=alice@example.com,5678=bob@example.com"
+
 	// Market data provider API keys
 	AlpacaAPIKey    string // Alpaca API key for US stocks
 	AlpacaSecretKey string // Alpaca secret key
@@ -53,6 +119,39 @@ func Init() {
 		RegistrationEnabled:   true,
 		MaxUsers:              10,   // Default: 10 users allowed
 		ExperienceImprovement: true, // Default: enabled to help improve the product
+		// Data retention defaults
+		EquityRetentionDays:     7,
+		EquityDownsampleMinutes: 60,
+		RecordPruneBatchSize:    500,
+		// Order sync defaults
+		OrderSyncMaxRetries:     2,
+		OrderSyncUnhealthyAfter: 5,
+		// Order fill defaults
+		OrderFillPollAttempts:   5,
+		OrderFillPollIntervalMs: 500,
+		OrderFillUserStream:     true,
+		// Strategy data cache defaults
+		StrategyDataCacheTTLSeconds: 60,
+		// AI prompt log defaults
+		PromptLogDir:        "data/prompt_logs",
+		PromptLogMaxSizeMB:  10,
+		PromptLogMaxBackups: 5,
+		// Logging defaults
+		LogLevel:            "info",
+		TraderLogDir:        "data/trader_logs",
+		TraderLogMaxSizeMB:  20,
+		TraderLogMaxBackups: 3,
+		// External signal defaults
+		SignalRateLimitPerMinute: 6,
+		// Balance sync guard defaults
+		BalanceSyncMaxChangePct: 50,
+		BalanceSyncMode:         "reset",
+		FundingReconcileMin:     60,
+		// API rate limiting and audit defaults
+		APIRateLimitPerMinute: 120,
+		APIRateLimitOverrides: "/api/equity-history-batch=20,/api/decisions=30",
+		APIAuditLog:           true,
+		APIAuditRetentionDays: 30,
 		// Database defaults
 		DBType:    "sqlite",
 		DBPath:    "data/data.db",
@@ -61,6 +160,9 @@ func Init() {
 		DBUser:    "postgres",
 		DBName:    "nofx",
 		DBSSLMode: "disable",
+		// SQLite tuning defaults
+		DBJournalMode:   "WAL",
+		DBBusyTimeoutMs: 5000,
 	}
 
 	// Load from environment variables
@@ -81,6 +183,14 @@ func Init() {
 		}
 	}
 
+	if v := os.Getenv("ADMIN_EMAILS"); v != "" {
+		for _, email := range strings.Split(v, ",") {
+			if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+				cfg.AdminEmails = append(cfg.AdminEmails, email)
+			}
+		}
+	}
+
 	if v := os.Getenv("API_SERVER_PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil && port > 0 {
 			cfg.APIServerPort = port
@@ -99,6 +209,179 @@ func Init() {
 		cfg.ExperienceImprovement = strings.ToLower(v) != "false"
 	}
 
+	// Data retention
+	if v := os.Getenv("EQUITY_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.EquityRetentionDays = days
+		}
+	}
+	if v := os.Getenv("EQUITY_DOWNSAMPLE_MINUTES"); v != "" {
+		if minutes, err := strconv.Atoi(v); err == nil && minutes > 0 {
+			cfg.EquityDownsampleMinutes = minutes
+		}
+	}
+	if v := os.Getenv("RECORD_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.RecordRetentionDays = days
+		}
+	}
+	if v := os.Getenv("RECORD_RETENTION_MAX_PER_TRADER"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil && limit >= 0 {
+			cfg.RecordRetentionMax = limit
+		}
+	}
+	if v := os.Getenv("DECISION_ARCHIVE_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.DecisionArchiveDays = days
+		}
+	}
+	if v := os.Getenv("RECORD_PRUNE_BATCH_SIZE"); v != "" {
+		if size, err := strconv.Atoi(v); err == nil && size > 0 {
+			cfg.RecordPruneBatchSize = size
+		}
+	}
+
+	// Order sync
+	if v := os.Getenv("ORDER_SYNC_MAX_RETRIES"); v != "" {
+		if retries, err := strconv.Atoi(v); err == nil && retries >= 0 {
+			cfg.OrderSyncMaxRetries = retries
+		}
+	}
+	if v := os.Getenv("ORDER_SYNC_UNHEALTHY_AFTER"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.OrderSyncUnhealthyAfter = n
+		}
+	}
+	if v := os.Getenv("ORDER_SYNC_PAUSE_ON_UNHEALTHY"); v != "" {
+		cfg.OrderSyncPauseOnUnhealthy = strings.ToLower(v) == "true"
+	}
+
+	// Order fill confirmation
+	if v := os.Getenv("ORDER_FILL_POLL_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.OrderFillPollAttempts = n
+		}
+	}
+	if v := os.Getenv("ORDER_FILL_POLL_INTERVAL_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			cfg.OrderFillPollIntervalMs = ms
+		}
+	}
+	cfg.OrderFillOverrides = strings.TrimSpace(os.Getenv("ORDER_FILL_POLL_OVERRIDES"))
+	if v := os.Getenv("ORDER_FILL_USER_STREAM"); v != "" {
+		cfg.OrderFillUserStream = strings.ToLower(v) != "false"
+	}
+
+	// Signed request validity window
+	if v := os.Getenv("EXCHANGE_RECV_WINDOW_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms >= 0 {
+			cfg.ExchangeRecvWindowMs = ms
+		}
+	}
+
+	// Strategy data cache
+	if v := os.Getenv("STRATEGY_DATA_CACHE_TTL"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			cfg.StrategyDataCacheTTLSeconds = secs
+		}
+	}
+
+	// AI call concurrency
+	if v := os.Getenv("MAX_CONCURRENT_AI_CALLS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MaxConcurrentAICalls = n
+		}
+	}
+
+	// AI prompt log files
+	if v := os.Getenv("PROMPT_LOG_ENABLED"); v != "" {
+		cfg.PromptLogEnabled = strings.ToLower(v) == "true"
+	}
+	if v := os.Getenv("PROMPT_LOG_DIR"); v != "" {
+		cfg.PromptLogDir = v
+	}
+	if v := os.Getenv("PROMPT_LOG_MAX_SIZE_MB"); v != "" {
+		if mb, err := strconv.Atoi(v); err == nil && mb > 0 {
+			cfg.PromptLogMaxSizeMB = mb
+		}
+	}
+	if v := os.Getenv("PROMPT_LOG_MAX_BACKUPS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PromptLogMaxBackups = n
+		}
+	}
+
+	// Logging
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.LogLevel = strings.ToLower(v)
+	}
+	if v := os.Getenv("TRADER_LOG_ENABLED"); v != "" {
+		cfg.TraderLogEnabled = strings.ToLower(v) == "true"
+	}
+	if v := os.Getenv("TRADER_LOG_DIR"); v != "" {
+		cfg.TraderLogDir = v
+	}
+	if v := os.Getenv("TRADER_LOG_MAX_SIZE_MB"); v != "" {
+		if mb, err := strconv.Atoi(v); err == nil && mb > 0 {
+			cfg.TraderLogMaxSizeMB = mb
+		}
+	}
+	if v := os.Getenv("TRADER_LOG_MAX_BACKUPS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.TraderLogMaxBackups = n
+		}
+	}
+
+	// External signals
+	if v := os.Getenv("SIGNAL_RATE_LIMIT_PER_MINUTE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.SignalRateLimitPerMinute = n
+		}
+	}
+
+	// Balance sync guard
+	if v := os.Getenv("BALANCE_SYNC_MAX_CHANGE_PCT"); v != "" {
+		if pct, err := strconv.ParseFloat(v, 64); err == nil && pct >= 0 {
+			cfg.BalanceSyncMaxChangePct = pct
+		}
+	}
+	if v := strings.ToLower(strings.TrimSpace(os.Getenv("BALANCE_SYNC_MODE"))); v == "reset" || v == "adjust" {
+		cfg.BalanceSyncMode = v
+	}
+	if v := os.Getenv("TRANSFER_RECONCILE_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.TransferReconcileMin = n
+		}
+	}
+	if v := os.Getenv("FUNDING_RECONCILE_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.FundingReconcileMin = n
+		}
+	}
+
+	// API rate limiting and audit
+	if v := os.Getenv("API_RATE_LIMIT_PER_MINUTE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.APIRateLimitPerMinute = n
+		}
+	}
+	if v, ok := os.LookupEnv("API_RATE_LIMIT_OVERRIDES"); ok {
+		cfg.APIRateLimitOverrides = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("API_AUDIT_LOG"); v != "" {
+		cfg.APIAuditLog = strings.ToLower(v) != "false"
+	}
+	if v := os.Getenv("API_AUDIT_RETENTION_DAYS"); v != "" {
+		if days, err := strconv.Atoi(v); err == nil && days >= 0 {
+			cfg.APIAuditRetentionDays = days
+		}
+	}
+
+	// Discord bot
+	cfg.DiscordBotToken = strings.TrimSpace(os.Getenv("DISCORD_BOT_TOKEN"))
+	cfg.DiscordGuildID = strings.TrimSpace(os.Getenv("DISCORD_GUILD_ID"))
+	cfg.DiscordUserMap = os.Getenv("DISCORD_USER_MAP")
+
 	// Market data provider API keys
 	cfg.AlpacaAPIKey = os.Getenv("ALPACA_API_KEY")
 	cfg.AlpacaSecretKey = os.Getenv("ALPACA_SECRET_KEY")
@@ -131,6 +414,14 @@ func Init() {
 	if v := os.Getenv("DB_SSLMODE"); v != "" {
 		cfg.DBSSLMode = v
 	}
+	if v := os.Getenv("DB_JOURNAL_MODE"); v != "" {
+		cfg.DBJournalMode = strings.ToUpper(v)
+	}
+	if v := os.Getenv("DB_BUSY_TIMEOUT_MS"); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			cfg.DBBusyTimeoutMs = ms
+		}
+	}
 
 	global = cfg
 
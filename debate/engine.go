@@ -333,13 +333,13 @@ func (e *DebateEngine) buildMarketContext(session *store.DebateSessionWithDetail
 	quantDataMap := strategyEngine.FetchQuantDataBatch(symbols)
 
 	// Fetch OI ranking data (market-wide position changes)
-	oiRankingData := strategyEngine.FetchOIRankingData()
+	oiRankingData, _ := strategyEngine.FetchOIRankingData() // failures are logged; preview proceeds without it
 
 	// Fetch NetFlow ranking data (market-wide fund flow)
-	netFlowRankingData := strategyEngine.FetchNetFlowRankingData()
+	netFlowRankingData, _ := strategyEngine.FetchNetFlowRankingData() // failures are logged; preview proceeds without it
 
 	// Fetch Price ranking data (market-wide gainers/losers)
-	priceRankingData := strategyEngine.FetchPriceRankingData()
+	priceRankingData, _ := strategyEngine.FetchPriceRankingData() // failures are logged; preview proceeds without it
 
 	// Build context
 	ctx := &kernel.Context{
@@ -0,0 +1,294 @@
+// Package discord provides an optional Discord bot for querying and controlling traders
+// through slash commands (/nofx list|status|positions|start|stop).
+//
+// The bot connects to the Discord gateway, so no public URL is needed. Discord users are
+// mapped to NOFX users through DISCORD_USER_MAP; unmapped users can't run any command.
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/manager"
+	"nofx/store"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	gatewayURL = "wss://gateway.discord.gg/?v=10&encoding=json"
+
+	// Gateway opcodes
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatAck   = 11
+
+	minReconnectDelay = 2 * time.Second
+	maxReconnectDelay = 2 * time.Minute
+)
+
+// Config Discord bot configuration
+type Config struct {
+	BotToken string            // Bot token (empty = bot disabled)
+	GuildID  string            // Register commands in this guild only (instant update); empty = global commands
+	UserMap  map[string]string // Discord user ID -> NOFX user ID or email
+}
+
+// Bot Discord slash command bot
+type Bot struct {
+	cfg           Config
+	traderManager *manager.TraderManager
+	store         *store.Store
+	rest          *restClient
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// gatewayPayload Discord gateway message
+type gatewayPayload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d,omitempty"`
+	S  *int64          `json:"s,omitempty"`
+	T  string          `json:"t,omitempty"`
+}
+
+// ParseUserMap parses "discordID=user,discordID=user" (user = NOFX user ID or email)
+func ParseUserMap(s string) map[string]string {
+	userMap := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		discordID, user, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		discordID, user = strings.TrimSpace(discordID), strings.TrimSpace(user)
+		if !ok || discordID == "" || user == "" {
+			continue
+		}
+		userMap[discordID] = user
+	}
+	return userMap
+}
+
+// New creates the bot, or returns nil when no bot token is configured
+func New(cfg Config, tm *manager.TraderManager, st *store.Store) *Bot {
+	if cfg.BotToken == "" {
+		return nil
+	}
+	return &Bot{
+		cfg:           cfg,
+		traderManager: tm,
+		store:         st,
+		rest:          newRESTClient(cfg.BotToken),
+	}
+}
+
+// Start connects to the gateway in the background and keeps reconnecting until Stop
+func (b *Bot) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+
+	if len(b.cfg.UserMap) == 0 {
+		logger.Warnf("⚠️ [Discord] DISCORD_USER_MAP is empty, nobody will be able to use the bot commands")
+	}
+
+	go func() {
+		defer close(b.done)
+		delay := minReconnectDelay
+		for {
+			start := time.Now()
+			err := b.runSession(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			// A session that lived a while was healthy: reconnect quickly
+			if time.Since(start) > maxReconnectDelay {
+				delay = minReconnectDelay
+			}
+			logger.Warnf("⚠️ [Discord] Gateway disconnected: %v, reconnecting in %v", err, delay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}()
+}
+
+// Stop disconnects from the gateway and waits for the connection loop to exit
+func (b *Bot) Stop() {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel = nil
+	b.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	logger.Infof("🔌 [Discord] Bot stopped")
+}
+
+// runSession runs one gateway session: identify, heartbeat and dispatch events until the connection drops
+func (b *Bot) runSession(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, gatewayURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial gateway: %w", err)
+	}
+	defer conn.Close()
+
+	// Close the connection on shutdown so the blocking read returns
+	sessionDone := make(chan struct{})
+	defer close(sessionDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-sessionDone:
+		}
+	}()
+
+	var writeMu sync.Mutex
+	send := func(op int, d interface{}) error {
+		data, err := json.Marshal(map[string]interface{}{"op": op, "d": d})
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, data)
+	}
+
+	var hello gatewayPayload
+	if err := conn.ReadJSON(&hello); err != nil {
+		return fmt.Errorf("read hello: %w", err)
+	}
+	if hello.Op != opHello {
+		return fmt.Errorf("unexpected first opcode %d", hello.Op)
+	}
+	var helloData struct {
+		HeartbeatInterval int64 `json:"heartbeat_interval"`
+	}
+	if err := json.Unmarshal(hello.D, &helloData); err != nil || helloData.HeartbeatInterval <= 0 {
+		return fmt.Errorf("invalid hello payload")
+	}
+
+	// Slash commands arrive as INTERACTION_CREATE without any intents
+	err = send(opIdentify, map[string]interface{}{
+		"token":   b.cfg.BotToken,
+		"intents": 0,
+		"properties": map[string]string{
+			"os":      "linux",
+			"browser": "nofx",
+			"device":  "nofx",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("identify: %w", err)
+	}
+
+	var seqMu sync.Mutex
+	var lastSeq *int64
+	heartbeatErr := make(chan error, 1)
+	go func() {
+		ticker := time.NewTicker(time.Duration(helloData.HeartbeatInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sessionDone:
+				return
+			case <-ticker.C:
+				seqMu.Lock()
+				seq := lastSeq
+				seqMu.Unlock()
+				if err := send(opHeartbeat, seq); err != nil {
+					heartbeatErr <- err
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var msg gatewayPayload
+		if err := conn.ReadJSON(&msg); err != nil {
+			select {
+			case hbErr := <-heartbeatErr:
+				return fmt.Errorf("heartbeat: %w", hbErr)
+			default:
+			}
+			return fmt.Errorf("read: %w", err)
+		}
+		if msg.S != nil {
+			seqMu.Lock()
+			lastSeq = msg.S
+			seqMu.Unlock()
+		}
+
+		switch msg.Op {
+		case opDispatch:
+			b.handleDispatch(msg.T, msg.D)
+		case opHeartbeat:
+			seqMu.Lock()
+			seq := lastSeq
+			seqMu.Unlock()
+			if err := send(opHeartbeat, seq); err != nil {
+				return fmt.Errorf("heartbeat: %w", err)
+			}
+		case opReconnect:
+			return fmt.Errorf("gateway requested reconnect")
+		case opInvalidSession:
+			return fmt.Errorf("invalid session")
+		case opHeartbeatAck:
+		}
+	}
+}
+
+// handleDispatch handles gateway events
+func (b *Bot) handleDispatch(event string, data json.RawMessage) {
+	switch event {
+	case "READY":
+		var ready struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+			Application struct {
+				ID string `json:"id"`
+			} `json:"application"`
+		}
+		if err := json.Unmarshal(data, &ready); err != nil {
+			logger.Warnf("⚠️ [Discord] Invalid READY payload: %v", err)
+			return
+		}
+		b.rest.setApplicationID(ready.Application.ID)
+		logger.Infof("🤖 [Discord] Connected as %s", ready.User.Username)
+		go func() {
+			if err := b.rest.registerCommands(b.cfg.GuildID, slashCommands()); err != nil {
+				logger.Warnf("⚠️ [Discord] Failed to register slash commands: %v", err)
+			}
+		}()
+
+	case "INTERACTION_CREATE":
+		var in interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			logger.Warnf("⚠️ [Discord] Invalid interaction payload: %v", err)
+			return
+		}
+		go b.handleInteraction(&in)
+	}
+}
@@ -0,0 +1,365 @@
+package discord
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"nofx/logger"
+	"nofx/store"
+)
+
+// maxMessageLength Discord message content limit
+const maxMessageLength = 2000
+
+// Application command option types
+const (
+	optionSubcommand = 1
+	optionString     = 3
+)
+
+// applicationCommand slash command definition
+type applicationCommand struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Options     []applicationOption `json:"options,omitempty"`
+}
+
+type applicationOption struct {
+	Type        int                 `json:"type"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Required    bool                `json:"required,omitempty"`
+	Options     []applicationOption `json:"options,omitempty"`
+}
+
+// interaction INTERACTION_CREATE payload (fields used by the bot)
+type interaction struct {
+	ID            string `json:"id"`
+	ApplicationID string `json:"application_id"`
+	Token         string `json:"token"`
+	Type          int    `json:"type"`
+	Data          struct {
+		Name    string              `json:"name"`
+		Options []interactionOption `json:"options"`
+	} `json:"data"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"` // Set instead of member in DMs
+}
+
+type interactionOption struct {
+	Name    string              `json:"name"`
+	Value   interface{}         `json:"value"`
+	Options []interactionOption `json:"options"`
+}
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// slashCommands the /nofx command and its subcommands
+func slashCommands() []applicationCommand {
+	traderOption := []applicationOption{{
+		Type:        optionString,
+		Name:        "trader",
+		Description: "Trader name or ID",
+		Required:    true,
+	}}
+	return []applicationCommand{{
+		Name:        "nofx",
+		Description: "Query and control your NOFX traders",
+		Options: []applicationOption{
+			{Type: optionSubcommand, Name: "list", Description: "List your traders"},
+			{Type: optionSubcommand, Name: "status", Description: "Show a trader's status and account", Options: traderOption},
+			{Type: optionSubcommand, Name: "positions", Description: "Show a trader's open positions", Options: traderOption},
+			{Type: optionSubcommand, Name: "start", Description: "Start a trader", Options: traderOption},
+			{Type: optionSubcommand, Name: "stop", Description: "Stop a trader", Options: traderOption},
+		},
+	}}
+}
+
+// commandArgs extracts the subcommand and its trader argument
+func (in *interaction) commandArgs() (subcommand, traderArg string) {
+	if len(in.Data.Options) == 0 {
+		return "", ""
+	}
+	sub := in.Data.Options[0]
+	for _, opt := range sub.Options {
+		if opt.Name == "trader" {
+			traderArg, _ = opt.Value.(string)
+		}
+	}
+	return sub.Name, strings.TrimSpace(traderArg)
+}
+
+// discordUserID returns the invoking user's ID (guild member or DM user)
+func (in *interaction) discordUserID() string {
+	if in.Member != nil {
+		return in.Member.User.ID
+	}
+	if in.User != nil {
+		return in.User.ID
+	}
+	return ""
+}
+
+// handleInteraction runs a slash command and edits the deferred (ephemeral) response with the result
+func (b *Bot) handleInteraction(in *interaction) {
+	const applicationCommandType = 2
+	if in.Type != applicationCommandType || in.Data.Name != "nofx" {
+		return
+	}
+	if err := b.rest.deferResponse(in); err != nil {
+		logger.Warnf("⚠️ [Discord] Failed to acknowledge interaction: %v", err)
+		return
+	}
+
+	subcommand, traderArg := in.commandArgs()
+	reply := b.runCommand(in.discordUserID(), subcommand, traderArg)
+	reply = truncateMessage(reply, maxMessageLength)
+	if err := b.rest.editResponse(in, reply); err != nil {
+		logger.Warnf("⚠️ [Discord] Failed to send command response: %v", err)
+	}
+}
+
+// runCommand executes a subcommand for a Discord user and returns the reply text
+func (b *Bot) runCommand(discordUserID, subcommand, traderArg string) string {
+	userID, err := b.resolveUser(discordUserID)
+	if err != nil {
+		return "⛔ " + err.Error()
+	}
+
+	if subcommand == "list" {
+		return b.listTraders(userID)
+	}
+
+	t, err := b.findTrader(userID, traderArg)
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+
+	logger.Infof("🤖 [Discord] User %s ran /nofx %s %s", discordUserID, subcommand, t.Name)
+	switch subcommand {
+	case "status":
+		return b.traderStatus(t)
+	case "positions":
+		return b.traderPositions(t)
+	case "start":
+		return b.startTrader(userID, t)
+	case "stop":
+		return b.stopTrader(userID, t)
+	default:
+		return fmt.Sprintf("❌ Unknown command: %s", subcommand)
+	}
+}
+
+// resolveUser maps a Discord user to a NOFX user ID via the configured user map
+func (b *Bot) resolveUser(discordUserID string) (string, error) {
+	mapped, ok := b.cfg.UserMap[discordUserID]
+	if !ok || discordUserID == "" {
+		return "", fmt.Errorf("your Discord account (%s) is not linked to a NOFX user", discordUserID)
+	}
+
+	var user *store.User
+	var err error
+	if strings.Contains(mapped, "@") {
+		user, err = b.store.User().GetByEmail(mapped)
+	} else {
+		user, err = b.store.User().GetByID(mapped)
+	}
+	if err != nil || user == nil {
+		return "", fmt.Errorf("linked NOFX user %q not found", mapped)
+	}
+	return user.ID, nil
+}
+
+// findTrader finds one of the user's traders by exact ID, ID prefix or name (case-insensitive)
+func (b *Bot) findTrader(userID, arg string) (*store.Trader, error) {
+	if arg == "" {
+		return nil, fmt.Errorf("trader name or ID is required")
+	}
+	traders, err := b.store.Trader().List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load traders: %v", err)
+	}
+
+	var matches []*store.Trader
+	for _, t := range traders {
+		if t.ID == arg {
+			return t, nil
+		}
+		if strings.EqualFold(t.Name, arg) || strings.HasPrefix(t.ID, arg) {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no trader named %q", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%q matches %d traders, use the trader ID", arg, len(matches))
+	}
+}
+
+func (b *Bot) listTraders(userID string) string {
+	traders, err := b.store.Trader().List(userID)
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to load traders: %v", err)
+	}
+	if len(traders) == 0 {
+		return "No traders yet."
+	}
+	sort.Slice(traders, func(i, j int) bool { return traders[i].Name < traders[j].Name })
+
+	var sb strings.Builder
+	sb.WriteString("**Your traders**\n")
+	for _, t := range traders {
+		status := "⏹ stopped"
+		if at, err := b.traderManager.GetTrader(t.ID); err == nil {
+			if running, _ := at.GetStatus()["is_running"].(bool); running {
+				status = "▶️ running"
+			}
+		}
+		fmt.Fprintf(&sb, "• **%s** `%s` — %s\n", t.Name, shortID(t.ID), status)
+	}
+	return sb.String()
+}
+
+func (b *Bot) traderStatus(t *store.Trader) string {
+	at, err := b.traderManager.GetTrader(t.ID)
+	if err != nil {
+		return fmt.Sprintf("**%s** `%s` — not loaded (stopped)", t.Name, shortID(t.ID))
+	}
+	status := at.GetStatus()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s** `%s`\n", t.Name, shortID(t.ID))
+	state := "⏹ stopped"
+	if running, _ := status["is_running"].(bool); running {
+		state = "▶️ running"
+	}
+	fmt.Fprintf(&sb, "Status: %s | Exchange: %v | AI: %v | Cycles: %v\n",
+		state, status["exchange"], status["ai_model"], status["call_count"])
+
+	account, err := at.GetAccountInfo()
+	if err != nil {
+		fmt.Fprintf(&sb, "Account: unavailable (%v)\n", err)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "Equity: %.2f USDT | P&L: %+.2f (%+.2f%%) | Available: %.2f\n",
+		account["total_equity"], account["total_pnl"], account["total_pnl_pct"], account["available_balance"])
+	fmt.Fprintf(&sb, "Positions: %v | Margin used: %.1f%%\n", account["position_count"], account["margin_used_pct"])
+	return sb.String()
+}
+
+func (b *Bot) traderPositions(t *store.Trader) string {
+	at, err := b.traderManager.GetTrader(t.ID)
+	if err != nil {
+		return fmt.Sprintf("**%s** is not loaded (stopped)", t.Name)
+	}
+	positions, err := at.GetPositions()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to get positions: %v", err)
+	}
+	if len(positions) == 0 {
+		return fmt.Sprintf("**%s** has no open positions.", t.Name)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s** positions\n", t.Name)
+	for _, p := range positions {
+		fmt.Fprintf(&sb, "• %v %v ×%v — qty %.4f, entry %.4f, mark %.4f, P&L %+.2f (%+.2f%%)\n",
+			p["symbol"], strings.ToUpper(fmt.Sprint(p["side"])), p["leverage"],
+			p["quantity"], p["entry_price"], p["mark_price"], p["unrealized_pnl"], p["unrealized_pnl_pct"])
+	}
+	return sb.String()
+}
+
+// startTrader (re)loads the trader from the store and starts it, like POST /api/traders/:id/start
+func (b *Bot) startTrader(userID string, t *store.Trader) string {
+	if t.Disabled {
+		return fmt.Sprintf("🛑 **%s** is disabled (kill-switch), enable it in the web UI first.", t.Name)
+	}
+	if at, err := b.traderManager.GetTrader(t.ID); err == nil {
+		if running, _ := at.GetStatus()["is_running"].(bool); running {
+			return fmt.Sprintf("**%s** is already running.", t.Name)
+		}
+		// Stopped trader: reload it so it picks up the latest config
+		b.traderManager.RemoveTrader(t.ID)
+	}
+
+	if err := b.traderManager.LoadUserTradersFromStore(b.store, userID); err != nil {
+		return fmt.Sprintf("❌ Failed to load trader: %v", err)
+	}
+	at, err := b.traderManager.GetTrader(t.ID)
+	if err != nil {
+		if loadErr := b.traderManager.GetLoadError(t.ID); loadErr != nil {
+			return fmt.Sprintf("❌ Failed to load trader: %v", loadErr)
+		}
+		return "❌ Failed to load trader, please check its AI model, exchange and strategy configuration"
+	}
+
+	go func() {
+		logger.Infof("▶️  Starting trader %s (%s) from Discord", t.ID, at.GetName())
+		if err := at.Run(); err != nil {
+			logger.Infof("❌ Trader %s runtime error: %v", at.GetName(), err)
+		}
+	}()
+	if err := b.store.Trader().UpdateStatus(userID, t.ID, true); err != nil {
+		logger.Infof("⚠️  Failed to update trader status: %v", err)
+	}
+	return fmt.Sprintf("▶️ **%s** started.", t.Name)
+}
+
+// stopTrader stops a running trader, like POST /api/traders/:id/stop
+func (b *Bot) stopTrader(userID string, t *store.Trader) string {
+	at, err := b.traderManager.GetTrader(t.ID)
+	if err != nil {
+		return fmt.Sprintf("**%s** is not running.", t.Name)
+	}
+	if running, _ := at.GetStatus()["is_running"].(bool); !running {
+		return fmt.Sprintf("**%s** is already stopped.", t.Name)
+	}
+
+	var flattenErr error
+	if t.FlattenOnStop {
+		flattenErr = at.StopAndFlatten()
+	} else {
+		at.Stop()
+	}
+	if err := b.store.Trader().UpdateStatus(userID, t.ID, false); err != nil {
+		logger.Infof("⚠️  Failed to update trader status: %v", err)
+	}
+	logger.Infof("⏹  Trader %s stopped from Discord", at.GetName())
+	if flattenErr != nil {
+		return fmt.Sprintf("⏹ **%s** stopped, but closing positions failed: %v", t.Name, flattenErr)
+	}
+	if t.FlattenOnStop {
+		return fmt.Sprintf("⏹ **%s** stopped, positions closed and orders cancelled.", t.Name)
+	}
+	return fmt.Sprintf("⏹ **%s** stopped.", t.Name)
+}
+
+// truncateMessage cuts a message to the Discord length limit without splitting a UTF-8 character
+func truncateMessage(msg string, limit int) string {
+	if len(msg) <= limit {
+		return msg
+	}
+	cut := limit - len("\n...")
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "\n..."
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,53 @@
+package discord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseUserMap(t *testing.T) {
+	got := ParseUserMap(" 111=alice@example.com, 222 = user-uuid ,bad,=x,333=")
+	if len(got) != 2 || got["111"] != "alice@example.com" || got["222"] != "user-uuid" {
+		t.Errorf("ParseUserMap() = %v", got)
+	}
+}
+
+func TestInteractionCommandArgs(t *testing.T) {
+	payload := `{
+		"id": "1", "token": "tok", "type": 2,
+		"data": {"name": "nofx", "options": [{"name": "status", "type": 1, "options": [{"name": "trader", "type": 3, "value": " BTC Bot "}]}]},
+		"member": {"user": {"id": "42", "username": "alice"}}
+	}`
+	var in interaction
+	if err := json.Unmarshal([]byte(payload), &in); err != nil {
+		t.Fatal(err)
+	}
+	sub, trader := in.commandArgs()
+	if sub != "status" || trader != "BTC Bot" {
+		t.Errorf("commandArgs() = %q, %q", sub, trader)
+	}
+	if in.discordUserID() != "42" {
+		t.Errorf("discordUserID() = %q, want 42", in.discordUserID())
+	}
+}
+
+func TestRunCommandRejectsUnlinkedUser(t *testing.T) {
+	b := &Bot{cfg: Config{UserMap: map[string]string{"111": "alice@example.com"}}}
+	reply := b.runCommand("999", "stop", "BTC Bot")
+	if !strings.Contains(reply, "not linked") {
+		t.Errorf("unlinked user reply = %q", reply)
+	}
+}
+
+func TestTruncateMessage(t *testing.T) {
+	msg := strings.Repeat("é", 20) // 2 bytes per rune
+	got := truncateMessage(msg, 15)
+	if len(got) > 15 || !utf8.ValidString(got) || !strings.HasSuffix(got, "...") {
+		t.Errorf("truncateMessage() = %q (%d bytes)", got, len(got))
+	}
+	if truncateMessage("short", 15) != "short" {
+		t.Error("short messages must be kept as is")
+	}
+}
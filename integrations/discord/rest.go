@@ -0,0 +1,108 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const apiBaseURL = "https://discord.com/api/v10"
+
+// Interaction callback types
+const (
+	callbackDeferredMessage = 5 // ACK now, the response is edited in later
+	messageFlagEphemeral    = 64
+)
+
+// restClient minimal Discord REST client (command registration and interaction responses)
+type restClient struct {
+	token      string
+	httpClient *http.Client
+
+	mu            sync.RWMutex
+	applicationID string
+}
+
+func newRESTClient(token string) *restClient {
+	return &restClient{
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *restClient) setApplicationID(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.applicationID = id
+}
+
+func (c *restClient) getApplicationID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.applicationID
+}
+
+// do sends a JSON request; auth=false for interaction webhooks, which are authorized by their token
+func (c *restClient) do(method, path string, body interface{}, auth bool) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, apiBaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/NoFxAiOS/nofx, 1.0)")
+	if auth {
+		req.Header.Set("Authorization", "Bot "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// registerCommands overwrites the application's slash commands (guild commands when guildID is set)
+func (c *restClient) registerCommands(guildID string, commands []applicationCommand) error {
+	appID := c.getApplicationID()
+	if appID == "" {
+		return fmt.Errorf("application ID unknown")
+	}
+	path := fmt.Sprintf("/applications/%s/commands", appID)
+	if guildID != "" {
+		path = fmt.Sprintf("/applications/%s/guilds/%s/commands", appID, guildID)
+	}
+	return c.do(http.MethodPut, path, commands, true)
+}
+
+// deferResponse acknowledges an interaction with an ephemeral "thinking" state
+func (c *restClient) deferResponse(in *interaction) error {
+	path := fmt.Sprintf("/interactions/%s/%s/callback", in.ID, in.Token)
+	return c.do(http.MethodPost, path, map[string]interface{}{
+		"type": callbackDeferredMessage,
+		"data": map[string]interface{}{"flags": messageFlagEphemeral},
+	}, false)
+}
+
+// editResponse replaces the deferred response with the final message
+func (c *restClient) editResponse(in *interaction, content string) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", in.ApplicationID, in.Token)
+	return c.do(http.MethodPatch, path, map[string]interface{}{"content": content}, false)
+}
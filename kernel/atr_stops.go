@@ -0,0 +1,87 @@
+package kernel
+
+import (
+	"nofx/logger"
+	"nofx/market"
+)
+
+// ============================================================================
+// ATR-based Stop Loss / Take Profit
+// ============================================================================
+
+// atrStopsEnabled returns whether ATR-based SL/TP is configured
+func (e *StrategyEngine) atrStopsEnabled() bool {
+	rc := e.config.RiskControl
+	return rc.ATRStopLossMultiplier > 0 || rc.ATRTakeProfitMultiplier > 0
+}
+
+// atrForSymbol returns the ATR used for SL/TP sizing
+// Priority: configured ATR timeframe → primary timeframe → 4h context → intraday series
+func (e *StrategyEngine) atrForSymbol(data *market.Data) float64 {
+	if data == nil {
+		return 0
+	}
+
+	timeframe := e.config.RiskControl.ATRTimeframe
+	if timeframe == "" {
+		timeframe = e.AnalysisInterval()
+	}
+	if tf, ok := data.TimeframeData[timeframe]; ok && tf != nil && tf.ATR14 > 0 {
+		return tf.ATR14
+	}
+	if data.LongerTermContext != nil && data.LongerTermContext.ATR14 > 0 {
+		return data.LongerTermContext.ATR14
+	}
+	if data.IntradaySeries != nil && data.IntradaySeries.ATR14 > 0 {
+		return data.IntradaySeries.ATR14
+	}
+	return 0
+}
+
+// calculateATRStops calculates SL/TP levels from entry price and ATR
+// Long: SL = entry - k_sl×ATR, TP = entry + k_tp×ATR; Short is mirrored
+func calculateATRStops(action string, entryPrice, atr, slMultiplier, tpMultiplier float64) (stopLoss, takeProfit float64) {
+	if action == "open_short" {
+		return entryPrice + slMultiplier*atr, entryPrice - tpMultiplier*atr
+	}
+	return entryPrice - slMultiplier*atr, entryPrice + tpMultiplier*atr
+}
+
+// applyATRStops fills stop_loss/take_profit of opening decisions that the AI left unset (or "auto")
+// with ATR-based levels. Decisions are modified in place; levels the AI set explicitly are kept.
+func (e *StrategyEngine) applyATRStops(decisions []Decision, marketData map[string]*market.Data) {
+	if !e.atrStopsEnabled() {
+		return
+	}
+	rc := e.config.RiskControl
+
+	for i := range decisions {
+		d := &decisions[i]
+		if d.Action != "open_long" && d.Action != "open_short" {
+			continue
+		}
+		needSL := d.StopLoss <= 0 && rc.ATRStopLossMultiplier > 0
+		needTP := d.TakeProfit <= 0 && rc.ATRTakeProfitMultiplier > 0
+		if !needSL && !needTP {
+			continue
+		}
+
+		data := marketData[d.Symbol]
+		atr := e.atrForSymbol(data)
+		if data == nil || data.CurrentPrice <= 0 || atr <= 0 {
+			logger.Infof("⚠️  [ATR Stops] %s has no price/ATR data, cannot compute stop levels", d.Symbol)
+			continue
+		}
+
+		stopLoss, takeProfit := calculateATRStops(d.Action, data.CurrentPrice, atr,
+			rc.ATRStopLossMultiplier, rc.ATRTakeProfitMultiplier)
+		if needSL && stopLoss > 0 {
+			d.StopLoss = stopLoss
+		}
+		if needTP && takeProfit > 0 {
+			d.TakeProfit = takeProfit
+		}
+		logger.Infof("📐 [ATR Stops] %s %s entry=%.4f ATR=%.4f → SL=%.4f TP=%.4f",
+			d.Symbol, d.Action, data.CurrentPrice, atr, d.StopLoss, d.TakeProfit)
+	}
+}
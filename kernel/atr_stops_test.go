@@ -0,0 +1,77 @@
+package kernel
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"testing"
+)
+
+func newATRTestEngine(slMult, tpMult float64) *StrategyEngine {
+	cfg := store.GetDefaultStrategyConfig("en")
+	cfg.Indicators.Klines.PrimaryTimeframe = "5m"
+	cfg.RiskControl.ATRStopLossMultiplier = slMult
+	cfg.RiskControl.ATRTakeProfitMultiplier = tpMult
+	return NewStrategyEngine(&cfg)
+}
+
+func TestApplyATRStops(t *testing.T) {
+	engine := newATRTestEngine(1.5, 4.5)
+	marketData := map[string]*market.Data{
+		"SOLUSDT": {
+			Symbol:       "SOLUSDT",
+			CurrentPrice: 100,
+			TimeframeData: map[string]*market.TimeframeSeriesData{
+				"5m": {Timeframe: "5m", ATR14: 2},
+			},
+		},
+	}
+
+	decisions := []Decision{
+		{Symbol: "SOLUSDT", Action: "open_long"},
+		{Symbol: "SOLUSDT", Action: "open_short"},
+		{Symbol: "SOLUSDT", Action: "open_long", StopLoss: 95, TakeProfit: 120},
+		{Symbol: "SOLUSDT", Action: "close_long"},
+	}
+	engine.applyATRStops(decisions, marketData)
+
+	if decisions[0].StopLoss != 97 || decisions[0].TakeProfit != 109 {
+		t.Errorf("long: expected SL=97 TP=109, got SL=%v TP=%v", decisions[0].StopLoss, decisions[0].TakeProfit)
+	}
+	if decisions[1].StopLoss != 103 || decisions[1].TakeProfit != 91 {
+		t.Errorf("short: expected SL=103 TP=91, got SL=%v TP=%v", decisions[1].StopLoss, decisions[1].TakeProfit)
+	}
+	if decisions[2].StopLoss != 95 || decisions[2].TakeProfit != 120 {
+		t.Errorf("explicit levels should be kept, got SL=%v TP=%v", decisions[2].StopLoss, decisions[2].TakeProfit)
+	}
+	if decisions[3].StopLoss != 0 || decisions[3].TakeProfit != 0 {
+		t.Errorf("close decision should not get stop levels")
+	}
+}
+
+func TestApplyATRStops_Disabled(t *testing.T) {
+	engine := newATRTestEngine(0, 0)
+	marketData := map[string]*market.Data{
+		"SOLUSDT": {CurrentPrice: 100, LongerTermContext: &market.LongerTermData{ATR14: 2}},
+	}
+	decisions := []Decision{{Symbol: "SOLUSDT", Action: "open_long"}}
+	engine.applyATRStops(decisions, marketData)
+	if decisions[0].StopLoss != 0 || decisions[0].TakeProfit != 0 {
+		t.Errorf("ATR stops disabled, levels should stay unset")
+	}
+}
+
+func TestExtractDecisions_AutoStopLevels(t *testing.T) {
+	response := `<decision>
+` + "```json" + `
+[{"symbol": "SOLUSDT", "action": "open_long", "leverage": 5, "position_size_usd": 100, "stop_loss": "auto", "take_profit": "AUTO", "confidence": 80}]
+` + "```" + `
+</decision>`
+
+	decisions, err := extractDecisions(response)
+	if err != nil {
+		t.Fatalf("extractDecisions() error = %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].StopLoss != 0 || decisions[0].TakeProfit != 0 {
+		t.Errorf("expected auto levels parsed as unset, got %+v", decisions)
+	}
+}
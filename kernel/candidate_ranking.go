@@ -0,0 +1,114 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/provider/nofxos"
+	"sort"
+	"strconv"
+)
+
+// Candidate ranking criteria (coin_source.candidate_ranking)
+const (
+	RankBySources    = "sources"    // Coins picked by more sources first, then source order
+	RankByVolume     = "volume"     // 24h quote volume
+	RankByOIChange   = "oi_change"  // Absolute open interest change (OI Top data)
+	RankByVolatility = "volatility" // 24h high-low range in % of the low
+)
+
+// rankCandidates sorts candidates by score (highest first). Candidates without a score keep their
+// relative order after the scored ones, so a partial data source never promotes unknown coins.
+func rankCandidates(candidates []CandidateCoin, scores map[string]float64) []CandidateCoin {
+	ranked := make([]CandidateCoin, len(candidates))
+	copy(ranked, candidates)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, okI := scores[ranked[i].Symbol]
+		sj, okJ := scores[ranked[j].Symbol]
+		if okI != okJ {
+			return okI
+		}
+		return si > sj
+	})
+	return ranked
+}
+
+// candidateScores returns the ranking score of each symbol for a criterion
+func (e *StrategyEngine) candidateScores(criterion string, candidates []CandidateCoin) (map[string]float64, error) {
+	scores := make(map[string]float64, len(candidates))
+
+	switch criterion {
+	case "", RankBySources:
+		for _, c := range candidates {
+			scores[c.Symbol] = float64(len(c.Sources))
+		}
+
+	case RankByVolume, RankByVolatility:
+		tickers, err := getTickers24hr()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tickers {
+			if criterion == RankByVolume {
+				if v, err := strconv.ParseFloat(t.QuoteVolume, 64); err == nil {
+					scores[t.Symbol] = v
+				}
+				continue
+			}
+			high, errH := strconv.ParseFloat(t.HighPrice, 64)
+			low, errL := strconv.ParseFloat(t.LowPrice, 64)
+			if errH == nil && errL == nil && low > 0 {
+				scores[t.Symbol] = (high - low) / low * 100
+			}
+		}
+
+	case RankByOIChange:
+		if e.nofxosClient == nil {
+			return nil, fmt.Errorf("OI data unavailable (no NofxOS client)")
+		}
+		cached, _, err := strategyDataCache.getOrFetch("oi_top", func() (interface{}, error) {
+			return e.nofxosClient.GetOITopPositions()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OI data: %w", err)
+		}
+		positions, _ := cached.([]nofxos.OIPosition)
+		for _, pos := range positions {
+			scores[market.Normalize(pos.Symbol)] = math.Abs(pos.OIDeltaPercent)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown candidate ranking: %s", criterion)
+	}
+	return scores, nil
+}
+
+// limitCandidates ranks candidates by coin_source.candidate_ranking and truncates them to
+// coin_source.max_candidates. If ranking data can't be fetched, the source order is kept.
+func (e *StrategyEngine) limitCandidates(candidates []CandidateCoin) []CandidateCoin {
+	maxCandidates := e.config.CoinSource.MaxCandidates
+	if maxCandidates <= 0 || len(candidates) <= maxCandidates {
+		return candidates
+	}
+
+	criterion := e.config.CoinSource.CandidateRanking
+	if criterion == "" {
+		criterion = RankBySources
+	}
+	ranked := candidates
+	if scores, err := e.candidateScores(criterion, candidates); err != nil {
+		logger.Infof("⚠️  Candidate ranking by %s failed, keeping source order: %v", criterion, err)
+	} else {
+		ranked = rankCandidates(candidates, scores)
+	}
+
+	dropped := make([]string, 0, len(ranked)-maxCandidates)
+	for _, c := range ranked[maxCandidates:] {
+		dropped = append(dropped, c.Symbol)
+	}
+	logger.Infof("✂️  Candidate coins limited to %d of %d (ranked by %s), dropped %d: %v",
+		maxCandidates, len(candidates), criterion, len(dropped), dropped)
+
+	return ranked[:maxCandidates]
+}
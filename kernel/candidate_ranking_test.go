@@ -0,0 +1,53 @@
+package kernel
+
+import (
+	"testing"
+
+	"nofx/store"
+)
+
+func candidateSymbols(candidates []CandidateCoin) []string {
+	symbols := make([]string, len(candidates))
+	for i, c := range candidates {
+		symbols[i] = c.Symbol
+	}
+	return symbols
+}
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []CandidateCoin{{Symbol: "AUSDT"}, {Symbol: "BUSDT"}, {Symbol: "CUSDT"}, {Symbol: "DUSDT"}}
+	// DUSDT has no data: it must stay behind every scored coin
+	scores := map[string]float64{"AUSDT": 1, "BUSDT": 5, "CUSDT": 3}
+
+	got := candidateSymbols(rankCandidates(candidates, scores))
+	want := []string{"BUSDT", "CUSDT", "AUSDT", "DUSDT"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rankCandidates() = %v, want %v", got, want)
+		}
+	}
+	if candidates[0].Symbol != "AUSDT" {
+		t.Error("rankCandidates must not reorder its input")
+	}
+}
+
+func TestLimitCandidatesBySources(t *testing.T) {
+	engine := NewStrategyEngine(&store.StrategyConfig{
+		CoinSource: store.CoinSourceConfig{MaxCandidates: 2},
+	})
+	candidates := []CandidateCoin{
+		{Symbol: "AUSDT", Sources: []string{"ai500"}},
+		{Symbol: "BUSDT", Sources: []string{"ai500", "oi_top"}},
+		{Symbol: "CUSDT", Sources: []string{"oi_top"}},
+	}
+
+	got := candidateSymbols(engine.limitCandidates(candidates))
+	if len(got) != 2 || got[0] != "BUSDT" || got[1] != "AUSDT" {
+		t.Errorf("limitCandidates() = %v, want [BUSDT AUSDT]", got)
+	}
+
+	engine.config.CoinSource.MaxCandidates = 0
+	if got := engine.limitCandidates(candidates); len(got) != 3 {
+		t.Errorf("max_candidates 0 should keep all candidates, got %d", len(got))
+	}
+}
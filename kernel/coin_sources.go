@@ -0,0 +1,317 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"nofx/market"
+	"nofx/provider/coinank"
+	"nofx/provider/coinank/coinank_enum"
+	"nofx/store"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Pluggable Coin Sources
+// ============================================================================
+// coin_source.sources lists extra candidate sources with their own parameters: an explicit symbol
+// list, top-N by 24h volume, top gainers/losers over a window and a custom CoinAnk ranking query.
+// They are the only sources when source_type = "custom" and are merged with AI500/OI Top when "mixed".
+
+// defaultCoinSourceLimit coins taken from a ranked source when limit isn't set
+const defaultCoinSourceLimit = 10
+
+// coinSourceWindow is the default gainers/losers window, served by the free Binance 24h ticker
+const coinSourceWindow = "24h"
+
+// coinAnkPriceChangeSortBy maps gainers/losers windows to CoinAnk price ranking fields
+var coinAnkPriceChangeSortBy = map[string]coinank_enum.InstrumentAggSortBy{
+	"5m":  coinank_enum.PriceChangeM5,
+	"15m": coinank_enum.PriceChangeM15,
+	"30m": coinank_enum.PriceChangeM30,
+	"1h":  coinank_enum.PriceChangeH1,
+	"4h":  coinank_enum.PriceChangeH4,
+	"6h":  coinank_enum.PriceChangeH6,
+	"12h": coinank_enum.PriceChangeH12,
+	"24h": coinank_enum.PriceChangeH24,
+}
+
+// coinAnkRankings CoinAnk ranking lists usable by coinank_rank
+var coinAnkRankings = map[string]bool{
+	"oi":          true,
+	"price":       true,
+	"volume":      true,
+	"liquidation": true,
+	"long_short":  true,
+}
+
+// coinAnkRequestTimeout timeout of a single CoinAnk ranking request
+const coinAnkRequestTimeout = 15 * time.Second
+
+// ValidateCoinSources checks coin_source.sources parameters
+func ValidateCoinSources(cfg store.CoinSourceConfig) error {
+	for i, spec := range cfg.Sources {
+		if spec.Limit < 0 {
+			return fmt.Errorf("coin_source.sources[%d]: limit must not be negative", i)
+		}
+		switch spec.Type {
+		case store.CoinSourceSymbols:
+			if len(spec.Symbols) == 0 {
+				return fmt.Errorf("coin_source.sources[%d]: symbols source needs at least one symbol", i)
+			}
+		case store.CoinSourceVolumeTop:
+		case store.CoinSourceGainers, store.CoinSourceLosers:
+			window := coinSourceWindowOrDefault(spec.Window)
+			if _, ok := coinAnkPriceChangeSortBy[window]; !ok {
+				return fmt.Errorf("coin_source.sources[%d]: unsupported window %q", i, spec.Window)
+			}
+			if window != coinSourceWindow && cfg.CoinAnkAPIKey == "" {
+				return fmt.Errorf("coin_source.sources[%d]: %s window needs coinank_api_key", i, window)
+			}
+		case store.CoinSourceCoinAnkRank:
+			if !coinAnkRankings[spec.Ranking] {
+				return fmt.Errorf("coin_source.sources[%d]: unknown CoinAnk ranking %q", i, spec.Ranking)
+			}
+			if spec.SortType != "" && spec.SortType != string(coinank_enum.Asc) && spec.SortType != string(coinank_enum.Desc) {
+				return fmt.Errorf("coin_source.sources[%d]: sort_type must be asc or desc", i)
+			}
+			if cfg.CoinAnkAPIKey == "" {
+				return fmt.Errorf("coin_source.sources[%d]: coinank_rank needs coinank_api_key", i)
+			}
+		default:
+			return fmt.Errorf("coin_source.sources[%d]: unknown source type %q", i, spec.Type)
+		}
+	}
+	if cfg.SourceType == "custom" && len(cfg.Sources) == 0 {
+		return fmt.Errorf("coin_source: source_type custom needs at least one entry in sources")
+	}
+	return nil
+}
+
+func coinSourceWindowOrDefault(window string) string {
+	window = strings.ToLower(strings.TrimSpace(window))
+	if window == "" {
+		return coinSourceWindow
+	}
+	return window
+}
+
+// GetSourceCoins returns the deduplicated symbols of coin_source.sources in config order
+// (used where candidates are resolved outside a trading cycle, e.g. backtests)
+func (e *StrategyEngine) GetSourceCoins() ([]string, error) {
+	var symbols []string
+	seen := make(map[string]bool)
+	specs := e.config.CoinSource.Sources
+	failed := e.mergeSourceCoins(specs, func(symbol, _ string) {
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	})
+	if failed > 0 && failed == len(specs) {
+		return nil, fmt.Errorf("all %d coin sources failed", failed)
+	}
+	return symbols, nil
+}
+
+// fetchSourceCoins fetches all pluggable sources concurrently; results and errors are indexed like specs
+func (e *StrategyEngine) fetchSourceCoins(specs []store.CoinSourceSpec) ([][]string, []error) {
+	coins := make([][]string, len(specs))
+	errs := make([]error, len(specs))
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		wg.Add(1)
+		go func(i int, spec store.CoinSourceSpec) {
+			defer wg.Done()
+			coins[i], errs[i] = e.getSourceCoins(spec)
+		}(i, spec)
+	}
+	wg.Wait()
+	return coins, errs
+}
+
+// getSourceCoins fetches the symbols of one pluggable source, in source order
+func (e *StrategyEngine) getSourceCoins(spec store.CoinSourceSpec) ([]string, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultCoinSourceLimit
+	}
+
+	switch spec.Type {
+	case store.CoinSourceSymbols:
+		symbols := make([]string, 0, len(spec.Symbols))
+		for _, symbol := range spec.Symbols {
+			symbols = append(symbols, market.Normalize(symbol))
+		}
+		return symbols, nil
+
+	case store.CoinSourceVolumeTop:
+		tickers, err := getTickers24hr()
+		if err != nil {
+			return nil, err
+		}
+		return topTickerSymbols(tickers, store.CoinSourceVolumeTop, limit), nil
+
+	case store.CoinSourceGainers, store.CoinSourceLosers:
+		window := coinSourceWindowOrDefault(spec.Window)
+		if window == coinSourceWindow {
+			tickers, err := getTickers24hr()
+			if err != nil {
+				return nil, err
+			}
+			return topTickerSymbols(tickers, spec.Type, limit), nil
+		}
+		sortBy, ok := coinAnkPriceChangeSortBy[window]
+		if !ok {
+			return nil, fmt.Errorf("unsupported %s window: %s", spec.Type, spec.Window)
+		}
+		sortType := coinank_enum.Desc
+		if spec.Type == store.CoinSourceLosers {
+			sortType = coinank_enum.Asc
+		}
+		return e.getCoinAnkRankCoins("price", sortBy, sortType, limit)
+
+	case store.CoinSourceCoinAnkRank:
+		sortType := coinank_enum.SortType(spec.SortType)
+		return e.getCoinAnkRankCoins(spec.Ranking, coinank_enum.InstrumentAggSortBy(spec.SortBy), sortType, limit)
+
+	default:
+		return nil, fmt.Errorf("unknown coin source: %s", spec.Type)
+	}
+}
+
+// getTickers24hr returns Binance futures 24h tickers (shared with candidate ranking through the data cache)
+func getTickers24hr() ([]market.Ticker24hr, error) {
+	cached, _, err := strategyDataCache.getOrFetch("ticker24hr", func() (interface{}, error) {
+		return market.NewAPIClient().GetTickers24hr()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h tickers: %w", err)
+	}
+	tickers, _ := cached.([]market.Ticker24hr)
+	return tickers, nil
+}
+
+// topTickerSymbols picks the top limit USDT symbols by 24h quote volume (volume_top),
+// price change (gainers) or negative price change (losers). Symbols without volume are skipped.
+func topTickerSymbols(tickers []market.Ticker24hr, source store.CoinSource, limit int) []string {
+	type scored struct {
+		symbol string
+		score  float64
+	}
+	var ranked []scored
+	for _, t := range tickers {
+		if market.QuoteAsset(t.Symbol) != market.QuoteUSDT {
+			continue
+		}
+		volume, err := strconv.ParseFloat(t.QuoteVolume, 64)
+		if err != nil || volume <= 0 {
+			continue
+		}
+		score := volume
+		if source != store.CoinSourceVolumeTop {
+			change, err := strconv.ParseFloat(t.PriceChangePercent, 64)
+			if err != nil {
+				continue
+			}
+			score = change
+			if source == store.CoinSourceLosers {
+				score = -change
+			}
+		}
+		ranked = append(ranked, scored{symbol: t.Symbol, score: score})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+
+	if limit > len(ranked) {
+		limit = len(ranked)
+	}
+	symbols := make([]string, 0, limit)
+	for _, r := range ranked[:limit] {
+		symbols = append(symbols, r.symbol)
+	}
+	return symbols
+}
+
+// getCoinAnkRankCoins runs a CoinAnk instrument ranking query and returns the ranked coins as USDT symbols
+func (e *StrategyEngine) getCoinAnkRankCoins(ranking string, sortBy coinank_enum.InstrumentAggSortBy,
+	sortType coinank_enum.SortType, limit int) ([]string, error) {
+	apiKey := e.config.CoinSource.CoinAnkAPIKey
+	if apiKey == "" {
+		return nil, fmt.Errorf("coinank_api_key is not configured")
+	}
+
+	key := fmt.Sprintf("coinank_rank:%s:%s:%s:%d", ranking, sortBy, sortType, limit)
+	cached, _, err := strategyDataCache.getOrFetch(key, func() (interface{}, error) {
+		client := coinank.NewCoinankClient(coinank_enum.MainUrl, apiKey)
+		ctx, cancel := context.WithTimeout(context.Background(), coinAnkRequestTimeout)
+		defer cancel()
+		return fetchCoinAnkRanking(ctx, client, ranking, sortBy, sortType, limit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CoinAnk %s ranking failed: %w", ranking, err)
+	}
+	baseCoins, _ := cached.([]string)
+
+	symbols := make([]string, 0, len(baseCoins))
+	for _, coin := range baseCoins {
+		if coin != "" {
+			symbols = append(symbols, market.Normalize(coin))
+		}
+	}
+	return symbols, nil
+}
+
+// fetchCoinAnkRanking returns the base coins of one CoinAnk ranking page
+func fetchCoinAnkRanking(ctx context.Context, client *coinank.CoinankClient, ranking string,
+	sortBy coinank_enum.InstrumentAggSortBy, sortType coinank_enum.SortType, limit int) ([]string, error) {
+	var coins []string
+	switch ranking {
+	case "oi":
+		rows, err := client.OiRank(ctx, sortBy, sortType, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			coins = append(coins, r.BaseCoin)
+		}
+	case "price":
+		rows, err := client.PriceRank(ctx, sortBy, sortType, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			coins = append(coins, r.BaseCoin)
+		}
+	case "volume":
+		rows, err := client.VolumeRank(ctx, sortBy, sortType, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			coins = append(coins, r.BaseCoin)
+		}
+	case "liquidation":
+		rows, err := client.LiquidationRank(ctx, sortBy, sortType, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			coins = append(coins, r.BaseCoin)
+		}
+	case "long_short":
+		rows, err := client.LongShortRank(ctx, sortBy, sortType, 1, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			coins = append(coins, r.BaseCoin)
+		}
+	default:
+		return nil, fmt.Errorf("unknown CoinAnk ranking: %s", ranking)
+	}
+	return coins, nil
+}
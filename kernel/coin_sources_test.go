@@ -0,0 +1,110 @@
+package kernel
+
+import (
+	"strings"
+	"testing"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+func TestTopTickerSymbols(t *testing.T) {
+	tickers := []market.Ticker24hr{
+		{Symbol: "AUSDT", QuoteVolume: "100", PriceChangePercent: "5"},
+		{Symbol: "BUSDT", QuoteVolume: "300", PriceChangePercent: "-8"},
+		{Symbol: "CUSDT", QuoteVolume: "200", PriceChangePercent: "12"},
+		{Symbol: "DUSDC", QuoteVolume: "900", PriceChangePercent: "50"}, // not a USDT pair
+		{Symbol: "EUSDT", QuoteVolume: "0", PriceChangePercent: "90"},   // no volume (delisted)
+	}
+
+	tests := []struct {
+		source store.CoinSource
+		limit  int
+		want   []string
+	}{
+		{store.CoinSourceVolumeTop, 2, []string{"BUSDT", "CUSDT"}},
+		{store.CoinSourceGainers, 2, []string{"CUSDT", "AUSDT"}},
+		{store.CoinSourceLosers, 1, []string{"BUSDT"}},
+		{store.CoinSourceVolumeTop, 10, []string{"BUSDT", "CUSDT", "AUSDT"}},
+	}
+	for _, tt := range tests {
+		got := topTickerSymbols(tickers, tt.source, tt.limit)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("topTickerSymbols(%s, %d) = %v, want %v", tt.source, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCoinSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     store.CoinSourceConfig
+		wantErr bool
+	}{
+		{"no sources", store.CoinSourceConfig{SourceType: "ai500"}, false},
+		{"custom without sources", store.CoinSourceConfig{SourceType: "custom"}, true},
+		{"symbols", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: store.CoinSourceSymbols, Symbols: []string{"BTC"}}}}, false},
+		{"empty symbols", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: store.CoinSourceSymbols}}}, true},
+		{"gainers 24h", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: store.CoinSourceGainers}}}, false},
+		{"losers 1h without key", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: store.CoinSourceLosers, Window: "1h"}}}, true},
+		{"losers 1h with key", store.CoinSourceConfig{CoinAnkAPIKey: "k", Sources: []store.CoinSourceSpec{{Type: store.CoinSourceLosers, Window: "1h"}}}, false},
+		{"bad window", store.CoinSourceConfig{CoinAnkAPIKey: "k", Sources: []store.CoinSourceSpec{{Type: store.CoinSourceGainers, Window: "3d"}}}, true},
+		{"coinank rank", store.CoinSourceConfig{CoinAnkAPIKey: "k", Sources: []store.CoinSourceSpec{{Type: store.CoinSourceCoinAnkRank, Ranking: "oi", SortBy: "openInterestCh4"}}}, false},
+		{"coinank unknown ranking", store.CoinSourceConfig{CoinAnkAPIKey: "k", Sources: []store.CoinSourceSpec{{Type: store.CoinSourceCoinAnkRank, Ranking: "funding"}}}, true},
+		{"coinank bad sort type", store.CoinSourceConfig{CoinAnkAPIKey: "k", Sources: []store.CoinSourceSpec{{Type: store.CoinSourceCoinAnkRank, Ranking: "oi", SortType: "up"}}}, true},
+		{"negative limit", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: store.CoinSourceVolumeTop, Limit: -1}}}, true},
+		{"unknown type", store.CoinSourceConfig{Sources: []store.CoinSourceSpec{{Type: "trending"}}}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateCoinSources(tt.cfg); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateCoinSources() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCollectCandidateCoinsCustom(t *testing.T) {
+	engine := NewStrategyEngine(&store.StrategyConfig{
+		CoinSource: store.CoinSourceConfig{
+			SourceType:    "custom",
+			ExcludedCoins: []string{"DOGE"},
+			Sources: []store.CoinSourceSpec{
+				{Type: store.CoinSourceSymbols, Symbols: []string{"btc", "ETHUSDT", "DOGE"}},
+				{Type: store.CoinSourceSymbols, Symbols: []string{"SOL", "BTC"}},
+			},
+		},
+	})
+
+	candidates, err := engine.GetCandidateCoins()
+	if err != nil {
+		t.Fatalf("GetCandidateCoins() error = %v", err)
+	}
+	got := candidateSymbols(candidates)
+	if strings.Join(got, ",") != "BTCUSDT,ETHUSDT,SOLUSDT" {
+		t.Fatalf("GetCandidateCoins() = %v, want [BTCUSDT ETHUSDT SOLUSDT]", got)
+	}
+	if len(candidates[0].Sources) != 2 || candidates[0].Sources[0] != "symbols" {
+		t.Errorf("BTCUSDT sources = %v, want both symbols sources", candidates[0].Sources)
+	}
+
+	symbols, err := engine.GetSourceCoins()
+	if err != nil || len(symbols) != 4 {
+		t.Errorf("GetSourceCoins() = %v, %v; want 4 deduplicated symbols", symbols, err)
+	}
+}
+
+func TestCandidateCoinsQuoteAsset(t *testing.T) {
+	engine := NewStrategyEngine(&store.StrategyConfig{CoinSource: store.CoinSourceConfig{
+		SourceType:    "custom",
+		Sources:       []store.CoinSourceSpec{{Type: store.CoinSourceSymbols, Symbols: []string{"BTC", "ETHUSDT", "BTCUSDT"}}},
+		ExcludedCoins: []string{"ETH"},
+	}})
+	engine.SetQuoteAsset("usdc")
+
+	candidates, err := engine.GetCandidateCoins()
+	if err != nil {
+		t.Fatalf("GetCandidateCoins() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Symbol != "BTCUSDC" {
+		t.Errorf("candidates = %+v, want only BTCUSDC", candidates)
+	}
+}
@@ -0,0 +1,130 @@
+package kernel
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Strategy Data Cache
+// ============================================================================
+// Short-TTL in-memory cache for external indicator data (quant data, rankings, coin pools).
+// Shared by all StrategyEngine instances, so traders watching the same symbols don't refetch,
+// and repeated calls within one cycle are deduped. Concurrent misses on the same key wait for
+// a single in-flight fetch. Errors are never cached.
+// Cached values are shared between traders and must be treated as read-only.
+
+// DefaultDataCacheTTL default cache TTL
+const DefaultDataCacheTTL = 60 * time.Second
+
+type dataCacheEntry struct {
+	value     interface{}
+	err       error
+	expiresAt time.Time
+	done      chan struct{} // closed when the fetch completes
+}
+
+type dataCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*dataCacheEntry
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+var strategyDataCache = newDataCache(DefaultDataCacheTTL)
+
+func newDataCache(ttl time.Duration) *dataCache {
+	return &dataCache{
+		ttl:     ttl,
+		entries: make(map[string]*dataCacheEntry),
+	}
+}
+
+// SetDataCacheTTL sets the strategy data cache TTL (0 disables caching)
+func SetDataCacheTTL(ttl time.Duration) {
+	strategyDataCache.mu.Lock()
+	defer strategyDataCache.mu.Unlock()
+	if ttl < 0 {
+		ttl = 0
+	}
+	strategyDataCache.ttl = ttl
+	strategyDataCache.entries = make(map[string]*dataCacheEntry)
+}
+
+// DataCacheStats returns cumulative strategy data cache hit/miss counts
+func DataCacheStats() (hits, misses uint64) {
+	return strategyDataCache.hits.Load(), strategyDataCache.misses.Load()
+}
+
+// getOrFetch returns the cached value for key, calling fetch on miss or expiry
+// hit reports whether the value came from cache (including waiting on an in-flight fetch)
+func (c *dataCache) getOrFetch(key string, fetch func() (interface{}, error)) (value interface{}, hit bool, err error) {
+	c.mu.Lock()
+	if c.ttl <= 0 {
+		c.mu.Unlock()
+		c.misses.Add(1)
+		value, err = fetch()
+		return value, false, err
+	}
+
+	now := time.Now()
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.done:
+			if now.Before(entry.expiresAt) && entry.err == nil {
+				c.mu.Unlock()
+				c.hits.Add(1)
+				return entry.value, true, nil
+			}
+		default:
+			// Fetch in flight: wait for it instead of hitting the API again
+			c.mu.Unlock()
+			<-entry.done
+			if entry.err != nil {
+				c.misses.Add(1)
+				return nil, false, entry.err
+			}
+			c.hits.Add(1)
+			return entry.value, true, nil
+		}
+	}
+
+	entry := &dataCacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	ttl := c.ttl
+	c.mu.Unlock()
+
+	c.misses.Add(1)
+	entry.value, entry.err = fetch()
+	entry.expiresAt = time.Now().Add(ttl)
+	close(entry.done)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	c.evictExpired()
+	return entry.value, false, entry.err
+}
+
+// evictExpired removes completed entries past their expiry
+func (c *dataCache) evictExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, entry := range c.entries {
+		select {
+		case <-entry.done:
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package kernel
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDataCache_HitAndExpiry(t *testing.T) {
+	c := newDataCache(50 * time.Millisecond)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, hit, err := c.getOrFetch("k", fetch)
+	if err != nil || hit || v.(int) != 1 {
+		t.Fatalf("first call: v=%v hit=%v err=%v", v, hit, err)
+	}
+	v, hit, _ = c.getOrFetch("k", fetch)
+	if !hit || v.(int) != 1 || calls != 1 {
+		t.Fatalf("second call should hit cache: v=%v hit=%v calls=%d", v, hit, calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	v, hit, _ = c.getOrFetch("k", fetch)
+	if hit || v.(int) != 2 {
+		t.Fatalf("expired entry should refetch: v=%v hit=%v", v, hit)
+	}
+
+	if c.hits.Load() != 1 || c.misses.Load() != 2 {
+		t.Errorf("expected 1 hit / 2 misses, got %d / %d", c.hits.Load(), c.misses.Load())
+	}
+}
+
+func TestDataCache_ErrorsNotCached(t *testing.T) {
+	c := newDataCache(time.Minute)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("boom")
+		}
+		return "ok", nil
+	}
+
+	if _, _, err := c.getOrFetch("k", fetch); err == nil {
+		t.Fatal("expected error on first call")
+	}
+	v, hit, err := c.getOrFetch("k", fetch)
+	if err != nil || hit || v != "ok" {
+		t.Fatalf("error should not be cached: v=%v hit=%v err=%v", v, hit, err)
+	}
+}
+
+func TestDataCache_Disabled(t *testing.T) {
+	c := newDataCache(0)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	c.getOrFetch("k", fetch)
+	c.getOrFetch("k", fetch)
+	if calls != 2 {
+		t.Errorf("TTL 0 should disable caching, fetch called %d times", calls)
+	}
+}
+
+func TestDataCache_ConcurrentMissesDedupe(t *testing.T) {
+	c := newDataCache(time.Minute)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "v", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, _, err := c.getOrFetch("k", fetch); err != nil || v != "v" {
+				t.Errorf("unexpected result: v=%v err=%v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("concurrent misses should share one fetch, got %d", calls.Load())
+	}
+}
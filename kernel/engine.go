@@ -2,6 +2,7 @@ package kernel
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,7 +13,9 @@ import (
 	"nofx/security"
 	"nofx/store"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,10 +26,10 @@ import (
 var (
 	// Safe regex: precisely match ```json code blocks
 	reJSONFence      = regexp.MustCompile(`(?is)` + "```json\\s*(\\[\\s*\\{.*?\\}\\s*\\])\\s*```")
-	reJSONArray      = regexp.MustCompile(`(?is)\[\s*\{.*?\}\s*\]`)
 	reArrayHead      = regexp.MustCompile(`^\[\s*\{`)
 	reArrayOpenSpace = regexp.MustCompile(`^\[\s+\{`)
 	reInvisibleRunes = regexp.MustCompile("[\u200B\u200C\u200D\uFEFF]")
+	reAutoStopLevel  = regexp.MustCompile(`(?i)"(stop_loss|take_profit)"\s*:\s*"auto"`)
 
 	// XML tag extraction (supports any characters in reasoning chain)
 	reReasoningTag = regexp.MustCompile(`(?s)<reasoning>(.*?)</reasoning>`)
@@ -68,7 +71,7 @@ type AccountInfo struct {
 // CandidateCoin candidate coin (from coin pool)
 type CandidateCoin struct {
 	Symbol  string   `json:"symbol"`
-	Sources []string `json:"sources"` // Sources: "ai500" and/or "oi_top"
+	Sources []string `json:"sources"` // Sources: "ai500", "oi_top", "static" or a pluggable source type
 }
 
 // OITopData open interest growth top data (for AI decision reference)
@@ -119,9 +122,12 @@ type Context struct {
 	MultiTFMarket   map[string]map[string]*market.Data `json:"-"`
 	OITopDataMap    map[string]*OITopData              `json:"-"`
 	QuantDataMap    map[string]*QuantData              `json:"-"`
+	OrderBook          map[string]*OrderBook      `json:"-"` // Top-N order book per position/top candidate (if enabled)
 	OIRankingData      *nofxos.OIRankingData      `json:"-"` // Market-wide OI ranking data
 	NetFlowRankingData *nofxos.NetFlowRankingData `json:"-"` // Market-wide fund flow ranking data
 	PriceRankingData   *nofxos.PriceRankingData   `json:"-"` // Market-wide price gainers/losers
+	MarginLimitPct     float64                    `json:"-"` // Portfolio margin usage limit (%), new opens blocked above it
+	UnavailableSources []string                   `json:"-"` // Enabled data sources that failed this cycle (the AI is told)
 	BTCETHLeverage     int                          `json:"-"`
 	AltcoinLeverage int                                `json:"-"`
 	Timeframes      []string                           `json:"-"`
@@ -130,13 +136,22 @@ type Context struct {
 // Decision AI trading decision
 type Decision struct {
 	Symbol string `json:"symbol"`
-	Action string `json:"action"` // "open_long", "open_short", "close_long", "close_short", "hold", "wait"
+	Action string `json:"action"` // "open_long", "open_short", "close_long", "close_short", "reduce_long", "reduce_short", "hold", "wait"
 
 	// Opening position parameters
 	Leverage        int     `json:"leverage,omitempty"`
 	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
 	StopLoss        float64 `json:"stop_loss,omitempty"`
 	TakeProfit      float64 `json:"take_profit,omitempty"`
+	// Optional scaled exits: multiple partial take profits, percent of position summing to 100
+	TakeProfitLevels []TakeProfitLevel `json:"take_profit_levels,omitempty"`
+
+	// Post-only (maker) entry: rest a limit at the best bid/ask instead of a market order, cancelled if
+	// unfilled within risk_control.post_only_timeout_sec
+	PostOnly bool `json:"post_only,omitempty"`
+
+	// Reducing position parameters: percent of the open position to close (0-100, exclusive)
+	ClosePercentage float64 `json:"close_percentage,omitempty"`
 
 	// Common parameters
 	Confidence int     `json:"confidence,omitempty"` // Confidence level (0-100)
@@ -144,6 +159,12 @@ type Decision struct {
 	Reasoning  string  `json:"reasoning"`
 }
 
+// TakeProfitLevel one partial take-profit exit (scaled exits)
+type TakeProfitLevel struct {
+	Price   float64 `json:"price"`
+	Percent float64 `json:"percent"` // Percent of position quantity to close at this price
+}
+
 // FullDecision AI's complete decision (including chain of thought)
 type FullDecision struct {
 	SystemPrompt        string     `json:"system_prompt"`
@@ -151,8 +172,10 @@ type FullDecision struct {
 	CoTTrace            string     `json:"cot_trace"`
 	Decisions           []Decision `json:"decisions"`
 	RawResponse         string     `json:"raw_response"`
+	RepairedResponse    string     `json:"repaired_response,omitempty"` // Response to the "valid JSON only" re-prompt, empty if none
 	Timestamp           time.Time  `json:"timestamp"`
 	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+	PromptLanguage      string     `json:"prompt_language,omitempty"` // Language the reasoning was requested in, empty if not set
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -193,6 +216,15 @@ type OIDeltaData struct {
 type StrategyEngine struct {
 	config       *store.StrategyConfig
 	nofxosClient *nofxos.Client
+
+	// Trader-level custom prompt (merged on top of the strategy prompt) and prompt language
+	// (overrides the strategy's prompt_language), updated while cycles build prompts
+	promptMu           sync.RWMutex
+	traderPrompt       string
+	overrideBasePrompt bool
+	promptLanguage     string
+	// Trader's stablecoin quote asset (USDT/USDC); candidate coins are quoted in it
+	quoteAsset string
 }
 
 // NewStrategyEngine creates strategy execution engine
@@ -215,8 +247,45 @@ func (e *StrategyEngine) GetRiskControlConfig() store.RiskControlConfig {
 	return e.config.RiskControl
 }
 
-// GetLanguage returns the language from config or falls back to auto-detection
+// SetTraderPrompt sets the trader's custom prompt
+// override=true replaces the strategy's base rules (schema and output format are always kept)
+func (e *StrategyEngine) SetTraderPrompt(prompt string, override bool) {
+	e.promptMu.Lock()
+	e.traderPrompt = strings.TrimSpace(prompt)
+	e.overrideBasePrompt = override
+	e.promptMu.Unlock()
+}
+
+// traderPromptSettings returns the trader's custom prompt and whether it replaces the base rules
+func (e *StrategyEngine) traderPromptSettings() (string, bool) {
+	e.promptMu.RLock()
+	defer e.promptMu.RUnlock()
+	return e.traderPrompt, e.overrideBasePrompt
+}
+
+// SetQuoteAsset sets the trader's quote asset: candidate coins, including those of USDT-quoted
+// data providers, are traded as pairs of that quote ("" or USDT keeps provider symbols as-is)
+func (e *StrategyEngine) SetQuoteAsset(quote string) {
+	e.quoteAsset = strings.ToUpper(strings.TrimSpace(quote))
+}
+
+// quoteSymbol normalizes a candidate symbol to the trader's quote asset
+func (e *StrategyEngine) quoteSymbol(symbol string) string {
+	if e.quoteAsset == "" || e.quoteAsset == market.QuoteUSDT {
+		return market.Normalize(symbol)
+	}
+	return market.WithQuote(symbol, e.quoteAsset)
+}
+
+// GetLanguage returns the template language: Chinese or English prompt language first,
+// then the language from config, falling back to auto-detection
 func (e *StrategyEngine) GetLanguage() Language {
+	switch e.PromptLanguage() {
+	case "zh", "zh-tw":
+		return LangChinese
+	case "en":
+		return LangEnglish
+	}
 	switch e.config.Language {
 	case "zh":
 		return LangChinese
@@ -256,52 +325,68 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 	}
 
 	// 1. Fetch market data using strategy config
-	if len(ctx.MarketDataMap) == 0 {
-		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
-			return nil, fmt.Errorf("failed to fetch market data: %w", err)
-		}
-	}
-
-	// Ensure OITopDataMap is initialized
-	if ctx.OITopDataMap == nil {
-		ctx.OITopDataMap = make(map[string]*OITopData)
-		oiPositions, err := engine.nofxosClient.GetOITopPositions()
-		if err == nil {
-			for _, pos := range oiPositions {
-				ctx.OITopDataMap[pos.Symbol] = &OITopData{
-					Rank:              pos.Rank,
-					OIDeltaPercent:    pos.OIDeltaPercent,
-					OIDeltaValue:      pos.OIDeltaValue,
-					PriceDeltaPercent: pos.PriceDeltaPercent,
-				}
-			}
-		}
+	if err := PrepareContext(ctx, engine); err != nil {
+		return nil, err
 	}
 
 	// 2. Build System Prompt using strategy engine
 	riskConfig := engine.GetRiskControlConfig()
 	systemPrompt := engine.BuildSystemPrompt(ctx.Account.TotalEquity, variant)
 
-	// 3. Build User Prompt using strategy engine
-	userPrompt := engine.BuildUserPrompt(ctx)
+	// 3. Build User Prompt using strategy engine (trimmed to what the context budget leaves)
+	userPrompt := engine.BuildUserPromptWithin(ctx, engine.UserPromptBudget(systemPrompt))
 
-	// 4. Call AI API
+	// 4. Call AI API (queued behind the global concurrent AI call limit)
+	release, waited := mcp.AcquireCallSlot()
+	if waited > 0 {
+		logger.Infof("⏳ Waited %v for an AI call slot (%d still queued)", waited.Round(time.Millisecond), mcp.CallsWaiting())
+	}
 	aiCallStart := time.Now()
 	aiResponse, err := mcpClient.CallWithMessages(systemPrompt, userPrompt)
 	aiCallDuration := time.Since(aiCallStart)
+	release()
 	if err != nil {
 		return nil, fmt.Errorf("AI API call failed: %w", err)
 	}
 
-	// 5. Parse AI response
-	decision, err := parseFullDecisionResponse(
-		aiResponse,
-		ctx.Account.TotalEquity,
-		riskConfig.BTCETHMaxLeverage,
-		riskConfig.AltcoinMaxLeverage,
-		riskConfig.BTCETHMaxPositionValueRatio,
-		riskConfig.AltcoinMaxPositionValueRatio,
-	)
+	// 5. Parse AI response (fill ATR-based SL/TP before validation)
+	parse := func(response string) (*FullDecision, error) {
+		return parseFullDecisionResponse(
+			response,
+			ctx.Account.TotalEquity,
+			riskConfig.BTCETHMaxLeverage,
+			riskConfig.AltcoinMaxLeverage,
+			riskConfig.BTCETHMaxPositionValueRatio,
+			riskConfig.AltcoinMaxPositionValueRatio,
+			func(decisions []Decision) {
+				engine.applyATRStops(decisions, ctx.MarketDataMap)
+			},
+		)
+	}
+	decision, err := parse(aiResponse)
+
+	// 6. Malformed JSON: ask once for the same answer as valid JSON before failing the cycle
+	var repairedResponse string
+	if errors.Is(err, errMalformedResponse) && !engine.GetConfig().ResponseRepair.DisableReprompt {
+		repaired, repairDuration, repairErr := repromptForValidJSON(mcpClient, systemPrompt, aiResponse, err)
+		aiCallDuration += repairDuration
+		if repairErr != nil {
+			logger.Warnf("⚠️  %v", repairErr)
+		} else {
+			repairedResponse = repaired
+			cotTrace := ""
+			if decision != nil {
+				cotTrace = decision.CoTTrace
+			}
+			decision, err = parse(repaired)
+			if decision != nil && cotTrace != "" {
+				decision.CoTTrace = cotTrace // keep the original reasoning, the repair answer is JSON only
+			}
+			if err == nil {
+				logger.Infof("✓ AI response repaired by re-prompt")
+			}
+		}
+	}
 
 	if decision != nil {
 		decision.Timestamp = time.Now()
@@ -309,6 +394,8 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		decision.UserPrompt = userPrompt
 		decision.AIRequestDurationMs = aiCallDuration.Milliseconds()
 		decision.RawResponse = aiResponse
+		decision.RepairedResponse = repairedResponse
+		decision.PromptLanguage = engine.PromptLanguage()
 	}
 
 	if err != nil {
@@ -322,6 +409,33 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 // Market Data Fetching
 // ============================================================================
 
+// PrepareContext fills the market data and OI ranking of ctx that aren't set yet.
+// After it returns, prompt building only reads ctx, so one context can be shared by parallel AI calls.
+func PrepareContext(ctx *Context, engine *StrategyEngine) error {
+	if len(ctx.MarketDataMap) == 0 {
+		if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+			return fmt.Errorf("failed to fetch market data: %w", err)
+		}
+	}
+
+	// Ensure OITopDataMap is initialized
+	if ctx.OITopDataMap == nil {
+		ctx.OITopDataMap = make(map[string]*OITopData)
+		oiPositions, err := engine.nofxosClient.GetOITopPositions()
+		if err == nil {
+			for _, pos := range oiPositions {
+				ctx.OITopDataMap[pos.Symbol] = &OITopData{
+					Rank:              pos.Rank,
+					OIDeltaPercent:    pos.OIDeltaPercent,
+					OIDeltaValue:      pos.OIDeltaValue,
+					PriceDeltaPercent: pos.PriceDeltaPercent,
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // fetchMarketDataWithStrategy fetches market data using strategy config (multiple timeframes)
 func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 	config := engine.GetConfig()
@@ -331,6 +445,14 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 	primaryTimeframe := config.Indicators.Klines.PrimaryTimeframe
 	klineCount := config.Indicators.Klines.PrimaryCount
 
+	// An explicit analysis interval overrides the primary timeframe
+	if interval := config.Indicators.AnalysisInterval; interval != "" {
+		primaryTimeframe = interval
+		if len(timeframes) > 0 && !slices.Contains(timeframes, interval) {
+			timeframes = append([]string{interval}, timeframes...)
+		}
+	}
+
 	// Compatible with old configuration
 	if len(timeframes) == 0 {
 		if primaryTimeframe != "" {
@@ -404,17 +526,35 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 // Candidate Coins
 // ============================================================================
 
-// GetCandidateCoins gets candidate coins based on strategy configuration
+// GetCandidateCoins gets candidate coins based on strategy configuration,
+// ranked and truncated to coin_source.max_candidates when set
 func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
+	candidates, err := e.collectCandidateCoins()
+	if err != nil {
+		return nil, err
+	}
+	return e.limitCandidates(candidates), nil
+}
+
+// collectCandidateCoins gets all candidate coins from the configured sources (excluded coins removed)
+func (e *StrategyEngine) collectCandidateCoins() ([]CandidateCoin, error) {
 	var candidates []CandidateCoin
 	symbolSources := make(map[string][]string)
+	var symbolOrder []string // First-seen order, so the merged list is deterministic
+	addSource := func(symbol, source string) {
+		symbol = e.quoteSymbol(symbol)
+		if _, exists := symbolSources[symbol]; !exists {
+			symbolOrder = append(symbolOrder, symbol)
+		}
+		symbolSources[symbol] = append(symbolSources[symbol], source)
+	}
 
 	coinSource := e.config.CoinSource
 
 	switch coinSource.SourceType {
 	case "static":
 		for _, symbol := range coinSource.StaticCoins {
-			symbol = market.Normalize(symbol)
+			symbol = e.quoteSymbol(symbol)
 			candidates = append(candidates, CandidateCoin{
 				Symbol:  symbol,
 				Sources: []string{"static"},
@@ -428,7 +568,7 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 		if !coinSource.UseAI500 {
 			logger.Infof("⚠️  source_type is 'ai500' but use_ai500 is false, falling back to static coins")
 			for _, symbol := range coinSource.StaticCoins {
-				symbol = market.Normalize(symbol)
+				symbol = e.quoteSymbol(symbol)
 				candidates = append(candidates, CandidateCoin{
 					Symbol:  symbol,
 					Sources: []string{"static"},
@@ -447,7 +587,7 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 		if !coinSource.UseOITop {
 			logger.Infof("⚠️  source_type is 'oi_top' but use_oi_top is false, falling back to static coins")
 			for _, symbol := range coinSource.StaticCoins {
-				symbol = market.Normalize(symbol)
+				symbol = e.quoteSymbol(symbol)
 				candidates = append(candidates, CandidateCoin{
 					Symbol:  symbol,
 					Sources: []string{"static"},
@@ -462,41 +602,69 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 		return e.filterExcludedCoins(coins), nil
 
 	case "mixed":
+		// AI500 and OI Top are independent external calls, fetch them concurrently
+		var poolCoins, oiCoins []CandidateCoin
+		var poolErr, oiErr error
+		var wg sync.WaitGroup
+		if coinSource.UseAI500 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				poolCoins, poolErr = e.getAI500Coins(coinSource.AI500Limit)
+			}()
+		}
+		if coinSource.UseOITop {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				oiCoins, oiErr = e.getOITopCoins(coinSource.OITopLimit)
+			}()
+		}
+		wg.Wait()
+
+		// Merge in a fixed order so source lists are deterministic
 		if coinSource.UseAI500 {
-			poolCoins, err := e.getAI500Coins(coinSource.AI500Limit)
-			if err != nil {
-				logger.Infof("⚠️  Failed to get AI500 coins: %v", err)
+			if poolErr != nil {
+				logger.Infof("⚠️  Failed to get AI500 coins: %v", poolErr)
 			} else {
 				for _, coin := range poolCoins {
-					symbolSources[coin.Symbol] = append(symbolSources[coin.Symbol], "ai500")
+					addSource(coin.Symbol, "ai500")
 				}
 			}
 		}
 
 		if coinSource.UseOITop {
-			oiCoins, err := e.getOITopCoins(coinSource.OITopLimit)
-			if err != nil {
-				logger.Infof("⚠️  Failed to get OI Top: %v", err)
+			if oiErr != nil {
+				logger.Infof("⚠️  Failed to get OI Top: %v", oiErr)
 			} else {
 				for _, coin := range oiCoins {
-					symbolSources[coin.Symbol] = append(symbolSources[coin.Symbol], "oi_top")
+					addSource(coin.Symbol, "oi_top")
 				}
 			}
 		}
 
 		for _, symbol := range coinSource.StaticCoins {
-			symbol = market.Normalize(symbol)
-			if _, exists := symbolSources[symbol]; !exists {
-				symbolSources[symbol] = []string{"static"}
-			} else {
-				symbolSources[symbol] = append(symbolSources[symbol], "static")
-			}
+			addSource(symbol, "static")
+		}
+
+		e.mergeSourceCoins(coinSource.Sources, addSource)
+
+		for _, symbol := range symbolOrder {
+			candidates = append(candidates, CandidateCoin{
+				Symbol:  symbol,
+				Sources: symbolSources[symbol],
+			})
 		}
+		return e.filterExcludedCoins(candidates), nil
 
-		for symbol, sources := range symbolSources {
+	case "custom":
+		if failed := e.mergeSourceCoins(coinSource.Sources, addSource); failed == len(coinSource.Sources) && failed > 0 {
+			return nil, fmt.Errorf("all %d custom coin sources failed", failed)
+		}
+		for _, symbol := range symbolOrder {
 			candidates = append(candidates, CandidateCoin{
 				Symbol:  symbol,
-				Sources: sources,
+				Sources: symbolSources[symbol],
 			})
 		}
 		return e.filterExcludedCoins(candidates), nil
@@ -506,6 +674,27 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 	}
 }
 
+// mergeSourceCoins fetches the pluggable coin sources and adds their symbols in config order,
+// labeled by source type. Failed sources are logged and skipped; returns how many failed.
+func (e *StrategyEngine) mergeSourceCoins(specs []store.CoinSourceSpec, addSource func(symbol, source string)) int {
+	if len(specs) == 0 {
+		return 0
+	}
+	coins, errs := e.fetchSourceCoins(specs)
+	failed := 0
+	for i, spec := range specs {
+		if errs[i] != nil {
+			logger.Infof("⚠️  Failed to get %s coins: %v", spec.Type, errs[i])
+			failed++
+			continue
+		}
+		for _, symbol := range coins[i] {
+			addSource(symbol, string(spec.Type))
+		}
+	}
+	return failed
+}
+
 // filterExcludedCoins removes excluded coins from the candidates list
 func (e *StrategyEngine) filterExcludedCoins(candidates []CandidateCoin) []CandidateCoin {
 	if len(e.config.CoinSource.ExcludedCoins) == 0 {
@@ -515,8 +704,7 @@ func (e *StrategyEngine) filterExcludedCoins(candidates []CandidateCoin) []Candi
 	// Build excluded set for O(1) lookup
 	excluded := make(map[string]bool)
 	for _, coin := range e.config.CoinSource.ExcludedCoins {
-		normalized := market.Normalize(coin)
-		excluded[normalized] = true
+		excluded[e.quoteSymbol(coin)] = true
 	}
 
 	// Filter out excluded coins
@@ -537,15 +725,18 @@ func (e *StrategyEngine) getAI500Coins(limit int) ([]CandidateCoin, error) {
 		limit = 30
 	}
 
-	symbols, err := e.nofxosClient.GetTopRatedCoins(limit)
+	cached, _, err := strategyDataCache.getOrFetch(fmt.Sprintf("ai500:%d", limit), func() (interface{}, error) {
+		return e.nofxosClient.GetTopRatedCoins(limit)
+	})
 	if err != nil {
 		return nil, err
 	}
+	symbols, _ := cached.([]string)
 
 	var candidates []CandidateCoin
 	for _, symbol := range symbols {
 		candidates = append(candidates, CandidateCoin{
-			Symbol:  symbol,
+			Symbol:  e.quoteSymbol(symbol),
 			Sources: []string{"ai500"},
 		})
 	}
@@ -557,19 +748,21 @@ func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 		limit = 20
 	}
 
-	positions, err := e.nofxosClient.GetOITopPositions()
+	cached, _, err := strategyDataCache.getOrFetch("oi_top", func() (interface{}, error) {
+		return e.nofxosClient.GetOITopPositions()
+	})
 	if err != nil {
 		return nil, err
 	}
+	positions, _ := cached.([]nofxos.OIPosition)
 
 	var candidates []CandidateCoin
 	for i, pos := range positions {
 		if i >= limit {
 			break
 		}
-		symbol := market.Normalize(pos.Symbol)
 		candidates = append(candidates, CandidateCoin{
-			Symbol:  symbol,
+			Symbol:  e.quoteSymbol(pos.Symbol),
 			Sources: []string{"oi_top"},
 		})
 	}
@@ -580,9 +773,26 @@ func (e *StrategyEngine) getOITopCoins(limit int) ([]CandidateCoin, error) {
 // External & Quant Data
 // ============================================================================
 
+// AnalysisInterval returns the kline interval used to analyze coins:
+// indicators.analysis_interval, falling back to the primary timeframe (empty if neither is set)
+func (e *StrategyEngine) AnalysisInterval() string {
+	if interval := e.config.Indicators.AnalysisInterval; interval != "" {
+		return interval
+	}
+	return e.config.Indicators.Klines.PrimaryTimeframe
+}
+
 // FetchMarketData fetches market data based on strategy configuration
 func (e *StrategyEngine) FetchMarketData(symbol string) (*market.Data, error) {
-	return market.Get(symbol)
+	interval := e.config.Indicators.AnalysisInterval
+	if interval == "" {
+		return market.Get(symbol)
+	}
+	count := e.config.Indicators.Klines.PrimaryCount
+	if count <= 0 {
+		count = 30
+	}
+	return market.GetWithTimeframes(symbol, []string{interval}, interval, count)
 }
 
 // FetchExternalData fetches external data sources
@@ -674,10 +884,13 @@ func (e *StrategyEngine) FetchQuantData(symbol string) (*QuantData, error) {
 		include = "netflow,oi,price"
 	}
 
-	nofxosData, err := e.nofxosClient.GetCoinData(symbol, include)
+	cached, _, err := strategyDataCache.getOrFetch("quant:"+symbol+":"+include, func() (interface{}, error) {
+		return e.nofxosClient.GetCoinData(symbol, include)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch quant data: %w", err)
 	}
+	nofxosData, _ := cached.(*nofxos.QuantData)
 
 	if nofxosData == nil {
 		return nil, nil
@@ -743,6 +956,7 @@ func (e *StrategyEngine) FetchQuantDataBatch(symbols []string) map[string]*Quant
 		return result
 	}
 
+	hitsBefore, missesBefore := DataCacheStats()
 	for _, symbol := range symbols {
 		data, err := e.FetchQuantData(symbol)
 		if err != nil {
@@ -753,15 +967,17 @@ func (e *StrategyEngine) FetchQuantDataBatch(symbols []string) map[string]*Quant
 			result[symbol] = data
 		}
 	}
+	hits, misses := DataCacheStats()
+	logger.Infof("📦 Quant data cache: %d hits, %d misses (%d symbols)", hits-hitsBefore, misses-missesBefore, len(symbols))
 
 	return result
 }
 
 // FetchOIRankingData fetches market-wide OI ranking data
-func (e *StrategyEngine) FetchOIRankingData() *nofxos.OIRankingData {
+func (e *StrategyEngine) FetchOIRankingData() (*nofxos.OIRankingData, error) {
 	indicators := e.config.Indicators
 	if !indicators.EnableOIRanking {
-		return nil
+		return nil, nil
 	}
 
 	duration := indicators.OIRankingDuration
@@ -776,23 +992,34 @@ func (e *StrategyEngine) FetchOIRankingData() *nofxos.OIRankingData {
 
 	logger.Infof("📊 Fetching OI ranking data (duration: %s, limit: %d)", duration, limit)
 
-	data, err := e.nofxosClient.GetOIRanking(duration, limit)
+	cached, hit, err := strategyDataCache.getOrFetch(fmt.Sprintf("oi_ranking:%s:%d", duration, limit), func() (interface{}, error) {
+		return breakerFor(SourceOIRanking).call(func() (interface{}, error) {
+			return e.nofxosClient.GetOIRanking(duration, limit)
+		})
+	})
 	if err != nil {
 		logger.Warnf("⚠️  Failed to fetch OI ranking data: %v", err)
-		return nil
+		return nil, fmt.Errorf("%s: %w", SourceOIRanking, err)
+	}
+	data, _ := cached.(*nofxos.OIRankingData)
+	if data == nil {
+		return nil, fmt.Errorf("%s: empty response", SourceOIRanking)
+	}
+	if hit {
+		logger.Infof("📦 OI ranking data served from cache")
 	}
 
 	logger.Infof("✓ OI ranking data ready: %d top, %d low positions",
 		len(data.TopPositions), len(data.LowPositions))
 
-	return data
+	return data, nil
 }
 
 // FetchNetFlowRankingData fetches market-wide NetFlow ranking data
-func (e *StrategyEngine) FetchNetFlowRankingData() *nofxos.NetFlowRankingData {
+func (e *StrategyEngine) FetchNetFlowRankingData() (*nofxos.NetFlowRankingData, error) {
 	indicators := e.config.Indicators
 	if !indicators.EnableNetFlowRanking {
-		return nil
+		return nil, nil
 	}
 
 	duration := indicators.NetFlowRankingDuration
@@ -807,24 +1034,35 @@ func (e *StrategyEngine) FetchNetFlowRankingData() *nofxos.NetFlowRankingData {
 
 	logger.Infof("💰 Fetching NetFlow ranking data (duration: %s, limit: %d)", duration, limit)
 
-	data, err := e.nofxosClient.GetNetFlowRanking(duration, limit)
+	cached, hit, err := strategyDataCache.getOrFetch(fmt.Sprintf("netflow_ranking:%s:%d", duration, limit), func() (interface{}, error) {
+		return breakerFor(SourceNetFlowRanking).call(func() (interface{}, error) {
+			return e.nofxosClient.GetNetFlowRanking(duration, limit)
+		})
+	})
 	if err != nil {
 		logger.Warnf("⚠️  Failed to fetch NetFlow ranking data: %v", err)
-		return nil
+		return nil, fmt.Errorf("%s: %w", SourceNetFlowRanking, err)
+	}
+	data, _ := cached.(*nofxos.NetFlowRankingData)
+	if data == nil {
+		return nil, fmt.Errorf("%s: empty response", SourceNetFlowRanking)
+	}
+	if hit {
+		logger.Infof("📦 NetFlow ranking data served from cache")
 	}
 
 	logger.Infof("✓ NetFlow ranking data ready: inst_in=%d, inst_out=%d, retail_in=%d, retail_out=%d",
 		len(data.InstitutionFutureTop), len(data.InstitutionFutureLow),
 		len(data.PersonalFutureTop), len(data.PersonalFutureLow))
 
-	return data
+	return data, nil
 }
 
 // FetchPriceRankingData fetches market-wide price ranking data (gainers/losers)
-func (e *StrategyEngine) FetchPriceRankingData() *nofxos.PriceRankingData {
+func (e *StrategyEngine) FetchPriceRankingData() (*nofxos.PriceRankingData, error) {
 	indicators := e.config.Indicators
 	if !indicators.EnablePriceRanking {
-		return nil
+		return nil, nil
 	}
 
 	durations := indicators.PriceRankingDuration
@@ -839,23 +1077,55 @@ func (e *StrategyEngine) FetchPriceRankingData() *nofxos.PriceRankingData {
 
 	logger.Infof("📈 Fetching Price ranking data (durations: %s, limit: %d)", durations, limit)
 
-	data, err := e.nofxosClient.GetPriceRanking(durations, limit)
+	cached, hit, err := strategyDataCache.getOrFetch(fmt.Sprintf("price_ranking:%s:%d", durations, limit), func() (interface{}, error) {
+		return breakerFor(SourcePriceRanking).call(func() (interface{}, error) {
+			return e.nofxosClient.GetPriceRanking(durations, limit)
+		})
+	})
 	if err != nil {
 		logger.Warnf("⚠️  Failed to fetch Price ranking data: %v", err)
-		return nil
+		return nil, fmt.Errorf("%s: %w", SourcePriceRanking, err)
+	}
+	data, _ := cached.(*nofxos.PriceRankingData)
+	if data == nil {
+		return nil, fmt.Errorf("%s: empty response", SourcePriceRanking)
+	}
+	if hit {
+		logger.Infof("📦 Price ranking data served from cache")
 	}
 
 	logger.Infof("✓ Price ranking data ready for %d durations", len(data.Durations))
 
-	return data
+	return data, nil
 }
 
 // ============================================================================
 // Prompt Building - System Prompt
 // ============================================================================
 
+// DefaultPromptVariant trading mode variant used unless the trader selects another one
+const DefaultPromptVariant = "balanced"
+
+// PromptVariants trading mode variants of the system prompt
+var PromptVariants = []string{DefaultPromptVariant, "aggressive", "conservative", "scalping"}
+
+// PromptVariantFor returns the variant a trader's system_prompt_template selects:
+// the template itself when it names a variant, otherwise DefaultPromptVariant
+func PromptVariantFor(template string) string {
+	template = strings.ToLower(strings.TrimSpace(template))
+	if slices.Contains(PromptVariants, template) {
+		return template
+	}
+	return DefaultPromptVariant
+}
+
 // BuildSystemPrompt builds System Prompt according to strategy configuration
 func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string) string {
+	traderPrompt, override := e.traderPromptSettings()
+	if override && traderPrompt != "" {
+		return e.buildOverrideSystemPrompt(accountEquity, traderPrompt)
+	}
+
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
 	promptSections := e.config.PromptSections
@@ -904,6 +1174,36 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n",
 		accountEquity*btcEthPosValueRatio, accountEquity, btcEthPosValueRatio))
 	sb.WriteString(fmt.Sprintf("- Max Margin Usage: ≤%.0f%%\n", riskControl.MaxMarginUsage*100))
+	if riskControl.MaxHoldMinutes > 0 {
+		sb.WriteString(fmt.Sprintf("- Max Hold Time: positions are closed automatically after %d minutes\n", riskControl.MaxHoldMinutes))
+	}
+	if riskControl.MaxLeverage > 0 {
+		sb.WriteString(fmt.Sprintf("- Max Leverage: %dx for every symbol, higher requests are clamped\n", riskControl.MaxLeverage))
+	}
+	if riskControl.MaxNetDirectionalExposurePct > 0 {
+		sb.WriteString(fmt.Sprintf("- Net Directional Exposure: |total long - total short| position value ≤%.0f%% of equity; opens beyond it in the same direction are rejected\n",
+			riskControl.MaxNetDirectionalExposurePct))
+	}
+	for _, group := range riskControl.CorrelationGroups {
+		if group.MaxSameDirection > 0 && len(group.Symbols) > 0 {
+			sb.WriteString(fmt.Sprintf("- Correlated Group '%s' (%s): max %d positions in the same direction\n",
+				group.Name, strings.Join(group.Symbols, ", "), group.MaxSameDirection))
+		}
+	}
+	for _, w := range riskControl.TradingWindows {
+		days := "daily"
+		if len(w.Days) > 0 {
+			days = strings.Join(w.Days, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("- Trading Window: new positions only %s–%s UTC (%s); closes are always allowed\n", w.Start, w.End, days))
+	}
+	if riskControl.AutoFlip {
+		sb.WriteString("- Auto-Flip: open_long/open_short on a symbol with an opposite position closes it first (no separate close needed)\n")
+	}
+	if riskControl.BreakevenTriggerPct > 0 {
+		sb.WriteString(fmt.Sprintf("- Breakeven Stop: once a position's P&L reaches +%.1f%%, its stop loss is moved to entry + fees automatically\n",
+			riskControl.BreakevenTriggerPct))
+	}
 	sb.WriteString(fmt.Sprintf("- Min Position Size: ≥%.0f USDT\n\n", riskControl.MinPositionSize))
 
 	sb.WriteString("## AI GUIDED (Recommended, you should follow):\n")
@@ -920,7 +1220,14 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	sb.WriteString("- Low confidence (60-69): Use 30-50%% of max position value limit\n")
 	sb.WriteString(fmt.Sprintf("- Example: With equity %.0f and BTC/ETH ratio %.1fx, max is %.0f USDT\n",
 		accountEquity, btcEthPosValueRatio, accountEquity*btcEthPosValueRatio))
-	sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n")
+	sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n")
+	if riskControl.SizingModel != "" && riskControl.SizingModel != store.SizingModelFixedUSD {
+		sb.WriteString(fmt.Sprintf("- NOTE: Position size is determined by code using the `%s` sizing model; your position_size_usd is only used as fallback\n", riskControl.SizingModel))
+	}
+	if riskControl.VolatilityScaling && riskControl.SizingModel != store.SizingModelVolatilityTarget {
+		sb.WriteString("- NOTE: Code scales position_size_usd inversely to each coin's ATR volatility (volatile coins get smaller positions)\n")
+	}
+	sb.WriteString("\n")
 
 	// 4. Trading frequency (editable)
 	if promptSections.TradingFrequency != "" {
@@ -959,6 +1266,48 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	}
 
 	// 7. Output format
+	e.writeOutputFormat(&sb, accountEquity, btcEthPosValueRatio)
+	e.writeLanguageInstruction(&sb)
+
+	// 8. Custom Prompt
+	if e.config.CustomPrompt != "" {
+		sb.WriteString("# 📌 Personalized Trading Strategy\n\n")
+		sb.WriteString(e.config.CustomPrompt)
+		sb.WriteString("\n\n")
+		sb.WriteString("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n")
+	}
+
+	// 9. Trader custom prompt (supplement)
+	if traderPrompt != "" {
+		sb.WriteString("\n# 📌 Trader Instructions\n\n")
+		sb.WriteString(traderPrompt)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// buildOverrideSystemPrompt builds System Prompt when the trader's custom prompt replaces the base rules
+// Schema and output format are kept so responses can still be parsed
+func (e *StrategyEngine) buildOverrideSystemPrompt(accountEquity float64, traderPrompt string) string {
+	var sb strings.Builder
+	sb.WriteString(GetSchemaPrompt(e.GetLanguage()))
+	sb.WriteString("\n\n---\n\n")
+	sb.WriteString(traderPrompt)
+	sb.WriteString("\n\n")
+
+	btcEthPosValueRatio := e.config.RiskControl.BTCETHMaxPositionValueRatio
+	if btcEthPosValueRatio <= 0 {
+		btcEthPosValueRatio = 5.0
+	}
+	e.writeOutputFormat(&sb, accountEquity, btcEthPosValueRatio)
+	e.writeLanguageInstruction(&sb)
+	return sb.String()
+}
+
+// writeOutputFormat writes the decision output format section
+func (e *StrategyEngine) writeOutputFormat(sb *strings.Builder, accountEquity, btcEthPosValueRatio float64) {
+	riskControl := e.config.RiskControl
 	sb.WriteString("# Output Format (Strictly Follow)\n\n")
 	sb.WriteString("**Must use XML tags <reasoning> and <decision> to separate chain of thought and decision JSON, avoiding parsing errors**\n\n")
 	sb.WriteString("## Format Requirements\n\n")
@@ -973,31 +1322,35 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	examplePositionSize := accountEquity * btcEthPosValueRatio
 	sb.WriteString(fmt.Sprintf("  {\"symbol\": \"BTCUSDT\", \"action\": \"open_short\", \"leverage\": %d, \"position_size_usd\": %.0f, \"stop_loss\": 97000, \"take_profit\": 91000, \"confidence\": 85, \"risk_usd\": 300},\n",
 		riskControl.BTCETHMaxLeverage, examplePositionSize))
-	sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\"}\n")
+	sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\"},\n")
+	sb.WriteString("  {\"symbol\": \"SOLUSDT\", \"action\": \"reduce_short\", \"close_percentage\": 50}\n")
 	sb.WriteString("]\n```\n")
 	sb.WriteString("</decision>\n\n")
 	sb.WriteString("## Field Description\n\n")
-	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | hold | wait\n")
+	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | reduce_long | reduce_short | hold | wait\n")
 	sb.WriteString(fmt.Sprintf("- `confidence`: 0-100 (opening recommended ≥ %d)\n", riskControl.MinConfidence))
 	sb.WriteString("- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n")
-	sb.WriteString("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n")
-
-	// 8. Custom Prompt
-	if e.config.CustomPrompt != "" {
-		sb.WriteString("# 📌 Personalized Trading Strategy\n\n")
-		sb.WriteString(e.config.CustomPrompt)
-		sb.WriteString("\n\n")
-		sb.WriteString("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n")
+	if e.atrStopsEnabled() {
+		sb.WriteString(fmt.Sprintf("- `stop_loss` / `take_profit` may be set to \"auto\" to use ATR-based levels (stop: entry ∓ %.1f×ATR, take profit: entry ± %.1f×ATR)\n",
+			riskControl.ATRStopLossMultiplier, riskControl.ATRTakeProfitMultiplier))
 	}
-
-	return sb.String()
+	sb.WriteString("- Required when reducing: close_percentage (1-99, percent of the open position to close; the rest stays open with the same entry)\n")
+	if riskControl.PostOnlyEntries {
+		timeoutSec := riskControl.PostOnlyTimeoutSec
+		if timeoutSec <= 0 {
+			timeoutSec = 60
+		}
+		sb.WriteString(fmt.Sprintf("- Optional `post_only`: true opens with a maker limit at the best bid/ask (lower fees), cancelled if not filled within %ds; use when not chasing momentum\n", timeoutSec))
+	}
+	sb.WriteString("- Optional scaled exits: `take_profit_levels` [{\"price\": 93000, \"percent\": 50}, {\"price\": 91000, \"percent\": 50}] (percents sum to 100), e.g. scale out at 1R/2R/3R\n")
+	sb.WriteString("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n")
 }
 
 func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 	indicators := e.config.Indicators
 	kline := indicators.Klines
 
-	sb.WriteString(fmt.Sprintf("- %s price series", kline.PrimaryTimeframe))
+	sb.WriteString(fmt.Sprintf("- %s price series", e.AnalysisInterval()))
 	if kline.EnableMultiTimeframe {
 		sb.WriteString(fmt.Sprintf(" + %s K-line series\n", kline.LongerTimeframe))
 	} else {
@@ -1059,6 +1412,10 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 	if indicators.EnableQuantData {
 		sb.WriteString("- Quantitative data (institutional/retail fund flow, position changes, multi-period price changes)\n")
 	}
+
+	if indicators.EnableOrderBook {
+		sb.WriteString("- Order book depth (top-N bid/ask notional, imbalance, spread; avoid entering thin books)\n")
+	}
 }
 
 // ============================================================================
@@ -1066,7 +1423,19 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 // ============================================================================
 
 // BuildUserPrompt builds User Prompt based on strategy configuration
+// (trimmed to the strategy's context budget, not counting the system prompt)
 func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
+	return e.BuildUserPromptWithin(ctx, e.UserPromptBudget(""))
+}
+
+// BuildUserPromptWithin builds User Prompt, trimming the least important sections to fit budget
+func (e *StrategyEngine) BuildUserPromptWithin(ctx *Context, budget PromptBudget) string {
+	return fitUserPrompt(e.buildUserPromptSections(ctx), budget)
+}
+
+// buildUserPromptSections builds the User Prompt sections in display order
+func (e *StrategyEngine) buildUserPromptSections(ctx *Context) promptSections {
+	var sections promptSections
 	var sb strings.Builder
 
 	// System status
@@ -1089,21 +1458,34 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		ctx.Account.MarginUsedPct,
 		ctx.Account.PositionCount))
 
+	if ctx.MarginLimitPct > 0 && ctx.Account.MarginUsedPct >= ctx.MarginLimitPct {
+		sb.WriteString(fmt.Sprintf("⚠️ MARGIN CONSTRAINED: margin usage %.1f%% ≥ limit %.1f%%. New positions will be rejected; only close/hold decisions are allowed.\n\n",
+			ctx.Account.MarginUsedPct, ctx.MarginLimitPct))
+	}
+
+	sections.add("status", trimRankNever, &sb)
+
 	// Recently completed orders (placed before positions to ensure visibility)
 	if len(ctx.RecentOrders) > 0 {
-		sb.WriteString("## Recent Completed Trades\n")
+		trades := &promptSection{
+			name:     "recent trades",
+			trimRank: trimRankRecentTrades,
+			header:   "## Recent Completed Trades\n",
+			footer:   "\n",
+			omitNote: func(n int) string { return fmt.Sprintf("(%d older trades omitted)\n", n) },
+		}
 		for i, order := range ctx.RecentOrders {
 			resultStr := "Profit"
 			if order.RealizedPnL < 0 {
 				resultStr = "Loss"
 			}
-			sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
+			trades.items = append(trades.items, fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
 				i+1, order.Symbol, order.Side,
 				order.EntryPrice, order.ExitPrice,
 				resultStr, order.RealizedPnL, order.PnLPct,
 				order.EntryTime, order.ExitTime, order.HoldDuration))
 		}
-		sb.WriteString("\n")
+		sections = append(sections, trades)
 	}
 
 	// Historical trading statistics (helps AI understand past performance)
@@ -1166,6 +1548,7 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		}
 		sb.WriteString("\n")
 	}
+	sections.add("trading stats", trimRankTradingStats, &sb)
 
 	// Position information
 	if len(ctx.Positions) > 0 {
@@ -1185,7 +1568,15 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		positionSymbols[normalizedSymbol] = true
 	}
 
-	sb.WriteString(fmt.Sprintf("## Candidate Coins (%d coins)\n\n", len(ctx.MarketDataMap)))
+	sections.add("positions", trimRankNever, &sb)
+
+	candidates := &promptSection{
+		name:     "candidate coins",
+		trimRank: trimRankCandidates,
+		header:   fmt.Sprintf("## Candidate Coins (%d coins)\n\n", len(ctx.MarketDataMap)),
+		footer:   "\n",
+		omitNote: func(n int) string { return fmt.Sprintf("(%d lower-ranked candidates omitted)\n\n", n) },
+	}
 	displayedCount := 0
 	for _, coin := range ctx.CandidateCoins {
 		// Skip if this coin is already a position (data already shown in positions section)
@@ -1209,9 +1600,14 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 				sb.WriteString(e.formatQuantData(quantData))
 			}
 		}
+		if book, hasBook := ctx.OrderBook[coin.Symbol]; hasBook {
+			sb.WriteString(formatOrderBook(book))
+		}
 		sb.WriteString("\n")
+		candidates.items = append(candidates.items, sb.String())
+		sb.Reset()
 	}
-	sb.WriteString("\n")
+	sections = append(sections, candidates)
 
 	// Get language for market data formatting
 	nofxosLang := nofxos.LangEnglish
@@ -1222,22 +1618,34 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	// OI Ranking data (market-wide open interest changes)
 	if ctx.OIRankingData != nil {
 		sb.WriteString(nofxos.FormatOIRankingForAI(ctx.OIRankingData, nofxosLang))
+		sections.add("OI ranking", trimRankOIRanking, &sb)
 	}
 
 	// NetFlow Ranking data (market-wide fund flow)
 	if ctx.NetFlowRankingData != nil {
 		sb.WriteString(nofxos.FormatNetFlowRankingForAI(ctx.NetFlowRankingData, nofxosLang))
+		sections.add("net flow ranking", trimRankNetFlowRanking, &sb)
 	}
 
 	// Price Ranking data (market-wide gainers/losers)
 	if ctx.PriceRankingData != nil {
 		sb.WriteString(nofxos.FormatPriceRankingForAI(ctx.PriceRankingData, nofxosLang))
+		sections.add("price ranking", trimRankPriceRanking, &sb)
+	}
+
+	// Enabled data sources that failed: tell the AI instead of silently omitting the section
+	if len(ctx.UnavailableSources) > 0 {
+		sb.WriteString("## ⚠️ Data Availability\n\n")
+		sb.WriteString(fmt.Sprintf("Unavailable this cycle (decide without them, don't assume neutral values): %s\n\n",
+			strings.Join(ctx.UnavailableSources, ", ")))
+		sections.add("data availability", trimRankNever, &sb)
 	}
 
 	sb.WriteString("---\n\n")
 	sb.WriteString("Now please analyze and output your decision (Chain of Thought + JSON)\n")
+	sections.add("footer", trimRankNever, &sb)
 
-	return sb.String()
+	return sections
 }
 
 func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Context) string {
@@ -1274,6 +1682,9 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 				sb.WriteString(e.formatQuantData(quantData))
 			}
 		}
+		if book, hasBook := ctx.OrderBook[pos.Symbol]; hasBook {
+			sb.WriteString(formatOrderBook(book))
+		}
 		sb.WriteString("\n")
 	}
 
@@ -1282,15 +1693,26 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 
 func (e *StrategyEngine) formatCoinSourceTag(sources []string) string {
 	if len(sources) > 1 {
-		return " (AI500+OI_Top dual signal)"
+		if len(sources) == 2 && sources[0] == "ai500" && sources[1] == "oi_top" {
+			return " (AI500+OI_Top dual signal)"
+		}
+		return fmt.Sprintf(" (Multi-source: %s)", strings.Join(sources, "+"))
 	} else if len(sources) == 1 {
 		switch sources[0] {
 		case "ai500":
 			return " (AI500)"
 		case "oi_top":
 			return " (OI_Top position growth)"
-		case "static":
+		case "static", string(store.CoinSourceSymbols):
 			return " (Manual selection)"
+		case string(store.CoinSourceVolumeTop):
+			return " (Top 24h volume)"
+		case string(store.CoinSourceGainers):
+			return " (Top gainer)"
+		case string(store.CoinSourceLosers):
+			return " (Top loser)"
+		case string(store.CoinSourceCoinAnkRank):
+			return " (CoinAnk ranking)"
 		}
 	}
 	return ""
@@ -1346,8 +1768,7 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 	} else {
 		// Compatible with old data format
 		if data.IntradaySeries != nil {
-			klineConfig := indicators.Klines
-			sb.WriteString(fmt.Sprintf("Intraday series (%s intervals, oldest → latest):\n\n", klineConfig.PrimaryTimeframe))
+			sb.WriteString(fmt.Sprintf("Intraday series (%s intervals, oldest → latest):\n\n", e.AnalysisInterval()))
 
 			if len(data.IntradaySeries.MidPrices) > 0 {
 				sb.WriteString(fmt.Sprintf("Mid prices: %s\n\n", formatFloatSlice(data.IntradaySeries.MidPrices)))
@@ -1582,7 +2003,8 @@ func formatFloatSlice(values []float64) string {
 // AI Response Parsing
 // ============================================================================
 
-func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) (*FullDecision, error) {
+// preValidate (optional) post-processes decisions before validation (e.g. filling ATR-based SL/TP)
+func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64, preValidate func([]Decision)) (*FullDecision, error) {
 	cotTrace := extractCoTTrace(aiResponse)
 
 	decisions, err := extractDecisions(aiResponse)
@@ -1590,7 +2012,13 @@ func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthL
 		return &FullDecision{
 			CoTTrace:  cotTrace,
 			Decisions: []Decision{},
-		}, fmt.Errorf("failed to extract decisions: %w", err)
+		}, fmt.Errorf("%w: %w", errMalformedResponse, err)
+	}
+
+	normalizeTakeProfitLevels(decisions)
+
+	if preValidate != nil {
+		preValidate(decisions)
 	}
 
 	if err := validateDecisions(decisions, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio); err != nil {
@@ -1642,21 +2070,12 @@ func extractDecisions(response string) ([]Decision, error) {
 
 	jsonPart = fixMissingQuotes(jsonPart)
 
+	var jsonContent string
 	if m := reJSONFence.FindStringSubmatch(jsonPart); m != nil && len(m) > 1 {
-		jsonContent := strings.TrimSpace(m[1])
-		jsonContent = compactArrayOpen(jsonContent)
-		jsonContent = fixMissingQuotes(jsonContent)
-		if err := validateJSONFormat(jsonContent); err != nil {
-			return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
-		}
-		var decisions []Decision
-		if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-			return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
-		}
-		return decisions, nil
+		jsonContent = strings.TrimSpace(m[1])
+	} else {
+		jsonContent = strings.TrimSpace(extractJSONArray(jsonPart))
 	}
-
-	jsonContent := strings.TrimSpace(reJSONArray.FindString(jsonPart))
 	if jsonContent == "" {
 		logger.Infof("⚠️  [SafeFallback] AI didn't output JSON decision, entering safe wait mode")
 
@@ -1676,6 +2095,7 @@ func extractDecisions(response string) ([]Decision, error) {
 
 	jsonContent = compactArrayOpen(jsonContent)
 	jsonContent = fixMissingQuotes(jsonContent)
+	jsonContent = replaceAutoStopLevels(jsonContent)
 
 	if err := validateJSONFormat(jsonContent); err != nil {
 		return nil, fmt.Errorf("JSON format validation failed: %w\nJSON content: %s\nFull response:\n%s", err, jsonContent, response)
@@ -1683,12 +2103,28 @@ func extractDecisions(response string) ([]Decision, error) {
 
 	var decisions []Decision
 	if err := json.Unmarshal([]byte(jsonContent), &decisions); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
+		// Retry once with trailing commas / Python literals fixed
+		repaired := repairDecisionJSON(jsonContent)
+		decisions = nil
+		if repaired == jsonContent || json.Unmarshal([]byte(repaired), &decisions) != nil {
+			return nil, fmt.Errorf("JSON parsing failed: %w\nJSON content: %s", err, jsonContent)
+		}
+		logger.Infof("🔧 Repaired malformed decision JSON (trailing commas / Python literals)")
+	}
+
+	if err := validateDecisionSchema(decisions); err != nil {
+		return nil, fmt.Errorf("decision schema validation failed: %w\nJSON content: %s", err, jsonContent)
 	}
 
 	return decisions, nil
 }
 
+// replaceAutoStopLevels replaces "stop_loss": "auto" / "take_profit": "auto" with 0,
+// so the strategy engine can fill them with ATR-based levels
+func replaceAutoStopLevels(jsonStr string) string {
+	return reAutoStopLevel.ReplaceAllString(jsonStr, `"$1": 0`)
+}
+
 func fixMissingQuotes(jsonStr string) string {
 	jsonStr = strings.ReplaceAll(jsonStr, "\u201c", "\"")
 	jsonStr = strings.ReplaceAll(jsonStr, "\u201d", "\"")
@@ -1770,18 +2206,27 @@ func validateDecisions(decisions []Decision, accountEquity float64, btcEthLevera
 
 func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) error {
 	validActions := map[string]bool{
-		"open_long":   true,
-		"open_short":  true,
-		"close_long":  true,
-		"close_short": true,
-		"hold":        true,
-		"wait":        true,
+		"open_long":    true,
+		"open_short":   true,
+		"close_long":   true,
+		"close_short":  true,
+		"reduce_long":  true,
+		"reduce_short": true,
+		"hold":         true,
+		"wait":         true,
 	}
 
 	if !validActions[d.Action] {
 		return fmt.Errorf("invalid action: %s", d.Action)
 	}
 
+	if d.Action == "reduce_long" || d.Action == "reduce_short" {
+		if d.ClosePercentage <= 0 || d.ClosePercentage >= 100 {
+			return fmt.Errorf("%s close_percentage must be between 0 and 100 (exclusive), got %.2f; use close_long/close_short to exit fully", d.Action, d.ClosePercentage)
+		}
+		return nil
+	}
+
 	if d.Action == "open_long" || d.Action == "open_short" {
 		maxLeverage := altcoinLeverage
 		posRatio := altcoinPosRatio
@@ -1839,6 +2284,10 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 			}
 		}
 
+		if err := validateTakeProfitLevels(d); err != nil {
+			return err
+		}
+
 		var entryPrice float64
 		if d.Action == "open_long" {
 			entryPrice = d.StopLoss + (d.TakeProfit-d.StopLoss)*0.2
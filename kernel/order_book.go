@@ -0,0 +1,101 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultOrderBookDepth levels per side fetched when indicators.order_book_depth is not set
+	DefaultOrderBookDepth = 10
+	// thinBookNotional top-N depth per side (USDT) below which a book is flagged as thin
+	thinBookNotional = 50000.0
+	// wideSpreadPct spread (%) above which a book is flagged as thin
+	wideSpreadPct = 0.1
+)
+
+// OrderBookLevel one price level of an order book
+type OrderBookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook top-N levels of a symbol's order book, bids best (highest) first, asks best (lowest) first
+type OrderBook struct {
+	Symbol string           `json:"symbol"`
+	Bids   []OrderBookLevel `json:"bids"`
+	Asks   []OrderBookLevel `json:"asks"`
+}
+
+// levelsNotional sums price × quantity over levels
+func levelsNotional(levels []OrderBookLevel) float64 {
+	total := 0.0
+	for _, l := range levels {
+		total += l.Price * l.Quantity
+	}
+	return total
+}
+
+// BidNotional quote value resting on the bid side
+func (b *OrderBook) BidNotional() float64 { return levelsNotional(b.Bids) }
+
+// AskNotional quote value resting on the ask side
+func (b *OrderBook) AskNotional() float64 { return levelsNotional(b.Asks) }
+
+// Imbalance returns (bid - ask) / (bid + ask) notional in [-1, 1]: positive when buyers
+// dominate the visible book, negative when sellers do, 0 for an empty book
+func (b *OrderBook) Imbalance() float64 {
+	bid, ask := b.BidNotional(), b.AskNotional()
+	if bid+ask <= 0 {
+		return 0
+	}
+	return (bid - ask) / (bid + ask)
+}
+
+// SpreadPct returns the best bid/ask spread as a percentage of the mid price (0 if a side is empty)
+func (b *OrderBook) SpreadPct() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	bid, ask := b.Bids[0].Price, b.Asks[0].Price
+	mid := (bid + ask) / 2
+	if mid <= 0 {
+		return 0
+	}
+	return (ask - bid) / mid * 100
+}
+
+// IsThin reports whether the book is too shallow or too wide to enter without heavy slippage
+func (b *OrderBook) IsThin() bool {
+	return b.BidNotional() < thinBookNotional || b.AskNotional() < thinBookNotional || b.SpreadPct() > wideSpreadPct
+}
+
+// formatOrderBook summarizes the order book depth and imbalance for the AI
+func formatOrderBook(book *OrderBook) string {
+	if book == nil || (len(book.Bids) == 0 && len(book.Asks) == 0) {
+		return ""
+	}
+
+	imbalance := book.Imbalance()
+	bias := "balanced"
+	switch {
+	case imbalance >= 0.2:
+		bias = "bid-heavy (buy pressure)"
+	case imbalance <= -0.2:
+		bias = "ask-heavy (sell pressure)"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📖 Order Book (top %d levels): Bid depth %s | Ask depth %s | Imbalance %+.2f %s | Spread %.4f%%\n",
+		max(len(book.Bids), len(book.Asks)), formatDepthValue(book.BidNotional()), formatDepthValue(book.AskNotional()),
+		imbalance, bias, book.SpreadPct()))
+	if book.IsThin() {
+		sb.WriteString("⚠️ Thin book: entering here risks heavy slippage, avoid new entries or size down\n")
+	}
+	return sb.String()
+}
+
+// formatDepthValue formats a depth notional compactly (e.g. 1.25M USDT)
+func formatDepthValue(v float64) string {
+	return strings.TrimPrefix(formatFlowValue(v), "+") + " USDT"
+}
@@ -0,0 +1,52 @@
+package kernel
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestOrderBookImbalance(t *testing.T) {
+	book := &OrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   []OrderBookLevel{{Price: 100, Quantity: 3000}, {Price: 99.9, Quantity: 1000}},
+		Asks:   []OrderBookLevel{{Price: 100.1, Quantity: 1000}},
+	}
+	bid, ask := book.BidNotional(), book.AskNotional()
+	want := (bid - ask) / (bid + ask)
+	if got := book.Imbalance(); math.Abs(got-want) > 1e-9 || got <= 0 {
+		t.Errorf("Imbalance() = %v, want %v (bid-heavy)", got, want)
+	}
+	if got := book.SpreadPct(); math.Abs(got-0.1/100.05*100) > 1e-9 {
+		t.Errorf("SpreadPct() = %v", got)
+	}
+	if got := (&OrderBook{}).Imbalance(); got != 0 {
+		t.Errorf("empty book Imbalance() = %v, want 0", got)
+	}
+}
+
+func TestFormatOrderBook(t *testing.T) {
+	deep := &OrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   []OrderBookLevel{{Price: 100, Quantity: 2000}},
+		Asks:   []OrderBookLevel{{Price: 100.01, Quantity: 2000}},
+	}
+	out := formatOrderBook(deep)
+	if !strings.Contains(out, " balanced |") || strings.Contains(out, "Thin book") {
+		t.Errorf("deep balanced book formatted as:\n%s", out)
+	}
+
+	thin := &OrderBook{
+		Symbol: "XYZUSDT",
+		Bids:   []OrderBookLevel{{Price: 1, Quantity: 100}},
+		Asks:   []OrderBookLevel{{Price: 1.01, Quantity: 5000}},
+	}
+	out = formatOrderBook(thin)
+	if !strings.Contains(out, "ask-heavy") || !strings.Contains(out, "Thin book") {
+		t.Errorf("thin ask-heavy book formatted as:\n%s", out)
+	}
+
+	if formatOrderBook(nil) != "" || formatOrderBook(&OrderBook{}) != "" {
+		t.Error("missing or empty book should format to nothing")
+	}
+}
@@ -0,0 +1,173 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"nofx/logger"
+)
+
+// Trim ranks of user prompt sections: over budget, the lowest rank is trimmed first.
+// Sections with rank 0 (status, account, positions) are never trimmed.
+const (
+	trimRankNever = iota
+	trimRankRecentTrades
+	trimRankPriceRanking
+	trimRankNetFlowRanking
+	trimRankOIRanking
+	trimRankTradingStats
+	trimRankCandidates
+)
+
+// PromptBudget size limit of a prompt (0 = no limit)
+type PromptBudget struct {
+	MaxTokens int
+	MaxChars  int
+}
+
+// unlimited reports whether the budget has no limit
+func (b PromptBudget) unlimited() bool {
+	return b.MaxTokens <= 0 && b.MaxChars <= 0
+}
+
+// fits reports whether text fits the budget
+func (b PromptBudget) fits(text string) bool {
+	if b.MaxChars > 0 && utf8.RuneCountInString(text) > b.MaxChars {
+		return false
+	}
+	if b.MaxTokens > 0 && EstimateTokens(text) > b.MaxTokens {
+		return false
+	}
+	return true
+}
+
+// EstimateTokens roughly estimates the tokens of text: ~4 ASCII characters per token,
+// one token per non-ASCII character (CJK text tokenizes close to one token per character)
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// UserPromptBudget returns the budget left for the user prompt once the system prompt is sent
+func (e *StrategyEngine) UserPromptBudget(systemPrompt string) PromptBudget {
+	cfg := e.config.ContextBudget
+	budget := PromptBudget{MaxTokens: cfg.MaxTokens, MaxChars: cfg.MaxChars}
+	if budget.MaxTokens > 0 {
+		budget.MaxTokens = max(budget.MaxTokens-EstimateTokens(systemPrompt), 1)
+	}
+	if budget.MaxChars > 0 {
+		budget.MaxChars = max(budget.MaxChars-utf8.RuneCountInString(systemPrompt), 1)
+	}
+	return budget
+}
+
+// promptSection a part of the user prompt; sections with items are trimmed item by item
+// from the end (items are ordered most important first) before being dropped
+type promptSection struct {
+	name     string
+	trimRank int
+	header   string
+	items    []string
+	footer   string
+	omitted  int                // items trimmed to fit the budget
+	dropped  bool               // section dropped to fit the budget
+	omitNote func(n int) string // line telling the AI how many items were left out
+}
+
+func (s *promptSection) render(sb *strings.Builder) {
+	if s.dropped {
+		return
+	}
+	sb.WriteString(s.header)
+	for _, item := range s.items {
+		sb.WriteString(item)
+	}
+	if s.omitted > 0 && s.omitNote != nil {
+		sb.WriteString(s.omitNote(s.omitted))
+	}
+	sb.WriteString(s.footer)
+}
+
+// promptSections the user prompt as an ordered list of sections
+type promptSections []*promptSection
+
+// add appends a section with the text written to sb so far and resets sb
+func (ps *promptSections) add(name string, trimRank int, sb *strings.Builder) {
+	if sb.Len() == 0 {
+		return
+	}
+	*ps = append(*ps, &promptSection{name: name, trimRank: trimRank, header: sb.String()})
+	sb.Reset()
+}
+
+func (ps promptSections) String() string {
+	var sb strings.Builder
+	for _, s := range ps {
+		s.render(&sb)
+	}
+	return sb.String()
+}
+
+// fit trims sections until the prompt fits the budget, lowest trim rank first.
+// Returns the prompt and a description of what was trimmed (empty if nothing).
+func (ps promptSections) fit(budget PromptBudget) (string, []string) {
+	prompt := ps.String()
+	if budget.unlimited() || budget.fits(prompt) {
+		return prompt, nil
+	}
+
+	for !budget.fits(prompt) {
+		var victim *promptSection
+		for _, s := range ps {
+			if s.trimRank == trimRankNever || s.dropped {
+				continue
+			}
+			if victim == nil || s.trimRank < victim.trimRank {
+				victim = s
+			}
+		}
+		if victim == nil {
+			break // Only untrimmable sections left
+		}
+		if len(victim.items) > 0 {
+			victim.items = victim.items[:len(victim.items)-1]
+			victim.omitted++
+		} else {
+			victim.dropped = true
+		}
+		prompt = ps.String()
+	}
+
+	var trimmed []string
+	for _, s := range ps {
+		switch {
+		case s.dropped:
+			trimmed = append(trimmed, s.name)
+		case s.omitted > 0:
+			trimmed = append(trimmed, fmt.Sprintf("%s (%d of %d)", s.name, s.omitted, s.omitted+len(s.items)))
+		}
+	}
+	return prompt, trimmed
+}
+
+// fitUserPrompt renders the sections within the budget, logging what was trimmed
+func fitUserPrompt(sections promptSections, budget PromptBudget) string {
+	prompt, trimmed := sections.fit(budget)
+	if len(trimmed) > 0 {
+		logger.Warnf("✂️  Prompt over context budget (max %d tokens / %d chars), trimmed: %s",
+			budget.MaxTokens, budget.MaxChars, strings.Join(trimmed, ", "))
+	}
+	if !budget.fits(prompt) {
+		logger.Warnf("⚠️  User prompt still over context budget after trimming (~%d tokens, %d chars)",
+			EstimateTokens(prompt), utf8.RuneCountInString(prompt))
+	}
+	return prompt
+}
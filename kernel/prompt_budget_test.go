@@ -0,0 +1,73 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Errorf("EstimateTokens(8 ASCII chars) = %d, want 2", got)
+	}
+	if got := EstimateTokens("历史交易"); got != 4 {
+		t.Errorf("EstimateTokens(4 CJK chars) = %d, want 4", got)
+	}
+}
+
+func TestPromptSectionsFit(t *testing.T) {
+	build := func() promptSections {
+		var sb strings.Builder
+		var sections promptSections
+		sb.WriteString("STATUS\n")
+		sections.add("status", trimRankNever, &sb)
+
+		trades := &promptSection{name: "recent trades", trimRank: trimRankRecentTrades, header: "## Trades\n",
+			omitNote: func(n int) string { return fmt.Sprintf("(%d omitted)\n", n) }}
+		candidates := &promptSection{name: "candidate coins", trimRank: trimRankCandidates, header: "## Candidates\n"}
+		for i := 0; i < 5; i++ {
+			trades.items = append(trades.items, strings.Repeat("t", 20)+"\n")
+			candidates.items = append(candidates.items, strings.Repeat("c", 20)+"\n")
+		}
+		sections = append(sections, trades)
+		sb.WriteString(strings.Repeat("s", 50) + "\n")
+		sections.add("trading stats", trimRankTradingStats, &sb)
+		sections = append(sections, candidates)
+		return sections
+	}
+	full := build().String()
+
+	t.Run("unlimited", func(t *testing.T) {
+		prompt, trimmed := build().fit(PromptBudget{})
+		if prompt != full || trimmed != nil {
+			t.Errorf("unlimited budget should not trim, trimmed %v", trimmed)
+		}
+	})
+
+	t.Run("trims recent trades first", func(t *testing.T) {
+		prompt, trimmed := build().fit(PromptBudget{MaxChars: len(full) - 30})
+		if len(prompt) > len(full)-30 {
+			t.Fatalf("prompt has %d chars, budget %d", len(prompt), len(full)-30)
+		}
+		if len(trimmed) != 1 || !strings.HasPrefix(trimmed[0], "recent trades (") {
+			t.Errorf("only recent trades should be trimmed, got %v", trimmed)
+		}
+		if !strings.Contains(prompt, "omitted)") || strings.Count(prompt, strings.Repeat("c", 20)) != 5 {
+			t.Error("candidates should be untouched and the omission noted")
+		}
+	})
+
+	t.Run("drops sections then trims candidates", func(t *testing.T) {
+		prompt, trimmed := build().fit(PromptBudget{MaxChars: 60})
+		if len(prompt) > 60 {
+			t.Fatalf("prompt has %d chars, budget 60", len(prompt))
+		}
+		if !strings.HasPrefix(prompt, "STATUS\n") {
+			t.Error("untrimmable sections must be kept")
+		}
+		want := "recent trades, trading stats, candidate coins ("
+		if got := strings.Join(trimmed, ", "); !strings.HasPrefix(got, want) {
+			t.Errorf("trimmed = %q, want prefix %q", got, want)
+		}
+	})
+}
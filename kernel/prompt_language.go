@@ -0,0 +1,80 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// promptLanguageNames maps supported prompt language codes to the name used in the instruction
+var promptLanguageNames = map[string]string{
+	"en":    "English",
+	"zh":    "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"es":    "Spanish",
+	"fr":    "French",
+	"de":    "German",
+	"pt":    "Portuguese",
+	"ru":    "Russian",
+	"vi":    "Vietnamese",
+	"tr":    "Turkish",
+}
+
+// NormalizePromptLanguage normalizes a prompt language code ("zh-CN", "ZH_cn" → "zh", "en-US" → "en").
+// Returns "" for an empty code and an error for an unsupported one.
+func NormalizePromptLanguage(code string) (string, error) {
+	code = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(code, "_", "-")))
+	switch code {
+	case "":
+		return "", nil
+	case "zh-cn", "zh-hans":
+		code = "zh"
+	case "zh-hk", "zh-hant":
+		code = "zh-tw"
+	default:
+		if _, ok := promptLanguageNames[code]; !ok {
+			// Region variants of supported languages (en-US, pt-BR, ...)
+			if base, _, found := strings.Cut(code, "-"); found {
+				code = base
+			}
+		}
+	}
+	if _, ok := promptLanguageNames[code]; !ok {
+		return "", fmt.Errorf("unsupported prompt language '%s'", code)
+	}
+	return code, nil
+}
+
+// SetPromptLanguage sets the trader-level prompt language (overrides the strategy's prompt_language)
+func (e *StrategyEngine) SetPromptLanguage(code string) {
+	e.promptMu.Lock()
+	e.promptLanguage = code
+	e.promptMu.Unlock()
+}
+
+// PromptLanguage returns the normalized language the AI is told to reason in ("" = not set)
+func (e *StrategyEngine) PromptLanguage() string {
+	e.promptMu.RLock()
+	code := e.promptLanguage
+	e.promptMu.RUnlock()
+	if code == "" {
+		code = e.config.PromptLanguage
+	}
+	lang, err := NormalizePromptLanguage(code)
+	if err != nil {
+		return ""
+	}
+	return lang
+}
+
+// writeLanguageInstruction tells the model which language to write its reasoning in
+func (e *StrategyEngine) writeLanguageInstruction(sb *strings.Builder) {
+	name := promptLanguageNames[e.PromptLanguage()]
+	if name == "" {
+		return
+	}
+	sb.WriteString("# Response Language\n\n")
+	sb.WriteString(fmt.Sprintf("- Write the <reasoning> chain of thought and any `reasoning` text in %s\n", name))
+	sb.WriteString("- Keep JSON keys, `action` values and symbols exactly as specified in English\n\n")
+}
@@ -0,0 +1,70 @@
+package kernel
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePromptLanguage(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"en", "en", false},
+		{"en-US", "en", false},
+		{"zh-CN", "zh", false},
+		{"ZH_cn", "zh", false},
+		{"zh-HK", "zh-tw", false},
+		{"pt-BR", "pt", false},
+		{" ja ", "ja", false},
+		{"klingon", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePromptLanguage(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizePromptLanguage(%q) = %q, %v; want %q, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBuildSystemPrompt_PromptLanguage(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+
+	t.Run("unset", func(t *testing.T) {
+		engine := NewStrategyEngine(&cfg)
+		if prompt := engine.BuildSystemPrompt(1000, "balanced"); strings.Contains(prompt, "Response Language") {
+			t.Error("prompt should not contain a language instruction when none is configured")
+		}
+	})
+
+	t.Run("strategy", func(t *testing.T) {
+		jaCfg := cfg
+		jaCfg.PromptLanguage = "ja"
+		engine := NewStrategyEngine(&jaCfg)
+		prompt := engine.BuildSystemPrompt(1000, "balanced")
+		if !strings.Contains(prompt, "in Japanese") {
+			t.Error("prompt should ask for Japanese reasoning")
+		}
+		if engine.GetLanguage() != LangEnglish {
+			t.Errorf("non-Chinese prompt language should keep English templates, got %s", engine.GetLanguage())
+		}
+	})
+
+	t.Run("trader override", func(t *testing.T) {
+		jaCfg := cfg
+		jaCfg.PromptLanguage = "ja"
+		engine := NewStrategyEngine(&jaCfg)
+		engine.SetPromptLanguage("zh-CN")
+		engine.SetTraderPrompt("Only trade SOL", true)
+		prompt := engine.BuildSystemPrompt(1000, "balanced")
+		if !strings.Contains(prompt, "in Simplified Chinese") {
+			t.Error("override prompt should ask for the trader's language")
+		}
+		if engine.GetLanguage() != LangChinese {
+			t.Errorf("Chinese prompt language should select Chinese templates, got %s", engine.GetLanguage())
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"nofx/mcp"
+	"strings"
+	"time"
+)
+
+// errMalformedResponse marks AI responses whose decision JSON couldn't be extracted or
+// doesn't match the Decision schema (as opposed to decisions that fail risk validation)
+var errMalformedResponse = errors.New("failed to extract decisions")
+
+// maxRepairEchoLen caps how much of the bad response is echoed back in the re-prompt
+const maxRepairEchoLen = 8000
+
+// extractJSONArray returns the first top-level JSON array of objects in s, matching brackets
+// while skipping string contents so nested arrays (take_profit_levels) don't cut it short.
+// An unterminated array returns everything from its opening bracket so parsing reports the error.
+func extractJSONArray(s string) string {
+	for start := strings.Index(s, "["); start >= 0; {
+		rest := strings.TrimLeft(s[start+1:], " \t\r\n")
+		if !strings.HasPrefix(rest, "{") {
+			next := strings.Index(s[start+1:], "[")
+			if next < 0 {
+				return ""
+			}
+			start += next + 1
+			continue
+		}
+
+		depth := 0
+		inString, escaped := false, false
+		for i := start; i < len(s); i++ {
+			c := s[i]
+			if inString {
+				switch {
+				case escaped:
+					escaped = false
+				case c == '\\':
+					escaped = true
+				case c == '"':
+					inString = false
+				}
+				continue
+			}
+			switch c {
+			case '"':
+				inString = true
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+				if depth == 0 {
+					return s[start : i+1]
+				}
+			}
+		}
+		return s[start:]
+	}
+	return ""
+}
+
+// repairDecisionJSON fixes common model slips outside of string values:
+// trailing commas before ] or }, and Python literals True/False/None
+func repairDecisionJSON(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+		case c == ',':
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == ']' || s[j] == '}') {
+				continue // drop trailing comma
+			}
+		default:
+			if lit, repl := pythonLiteralAt(s, i); lit != "" {
+				b.WriteString(repl)
+				i += len(lit) - 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+var pythonLiterals = [][2]string{{"True", "true"}, {"False", "false"}, {"None", "null"}}
+
+// pythonLiteralAt reports a standalone True/False/None token at s[i] and its JSON replacement
+func pythonLiteralAt(s string, i int) (string, string) {
+	if i > 0 && isIdentByte(s[i-1]) {
+		return "", ""
+	}
+	for _, p := range pythonLiterals {
+		if strings.HasPrefix(s[i:], p[0]) {
+			if end := i + len(p[0]); end < len(s) && isIdentByte(s[end]) {
+				continue
+			}
+			return p[0], p[1]
+		}
+	}
+	return "", ""
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// validateDecisionSchema checks the fields every decision must carry
+// (field types are already enforced when unmarshalling into Decision)
+func validateDecisionSchema(decisions []Decision) error {
+	if len(decisions) == 0 {
+		return fmt.Errorf("decision array is empty")
+	}
+	for i, d := range decisions {
+		if strings.TrimSpace(d.Symbol) == "" {
+			return fmt.Errorf("decision #%d is missing symbol", i+1)
+		}
+		if strings.TrimSpace(d.Action) == "" {
+			return fmt.Errorf("decision #%d is missing action", i+1)
+		}
+	}
+	return nil
+}
+
+// buildRepairPrompt asks the model to restate its previous answer as a bare decision array
+func buildRepairPrompt(badResponse string, parseErr error) string {
+	if len(badResponse) > maxRepairEchoLen {
+		badResponse = badResponse[:maxRepairEchoLen] + "..."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Your previous response could not be parsed as a trading decision array.\n")
+	fmt.Fprintf(&sb, "Error: %s\n\n", firstLine(parseErr.Error()))
+	sb.WriteString("Previous response:\n")
+	sb.WriteString(badResponse)
+	sb.WriteString("\n\nReturn ONLY valid JSON: a single array of decision objects with the same decisions, ")
+	sb.WriteString(`each containing at least "symbol" and "action". `)
+	sb.WriteString("No prose, no markdown fences, no comments, numbers without quotes or thousand separators.")
+	return sb.String()
+}
+
+// repromptForValidJSON makes one extra AI call asking for the previous answer as valid JSON
+func repromptForValidJSON(mcpClient mcp.AIClient, systemPrompt, badResponse string, parseErr error) (string, time.Duration, error) {
+	logger.Warnf("🔧 AI response malformed, re-prompting once for valid JSON: %v", firstLine(parseErr.Error()))
+
+	release, _ := mcp.AcquireCallSlot()
+	start := time.Now()
+	response, err := mcpClient.CallWithMessages(systemPrompt, buildRepairPrompt(badResponse, parseErr))
+	duration := time.Since(start)
+	release()
+	if err != nil {
+		return "", duration, fmt.Errorf("repair re-prompt failed: %w", err)
+	}
+	return response, duration, nil
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package kernel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSONArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "nested take profit levels",
+			input: `Sure, here you go: [{"symbol":"BTCUSDT","take_profit_levels":[{"price":1,"percent":50}]}] hope it helps [x]`,
+			want:  `[{"symbol":"BTCUSDT","take_profit_levels":[{"price":1,"percent":50}]}]`,
+		},
+		{
+			name:  "brackets inside strings",
+			input: `[{"symbol":"ETHUSDT","reasoning":"range [3000, 3100] }"}]`,
+			want:  `[{"symbol":"ETHUSDT","reasoning":"range [3000, 3100] }"}]`,
+		},
+		{
+			name:  "skips non-object arrays",
+			input: `levels [1, 2] then [ {"symbol":"SOLUSDT"} ]`,
+			want:  `[ {"symbol":"SOLUSDT"} ]`,
+		},
+		{
+			name:  "unterminated array",
+			input: `[{"symbol":"BTCUSDT"`,
+			want:  `[{"symbol":"BTCUSDT"`,
+		},
+		{
+			name:  "no array",
+			input: `I'll wait this cycle.`,
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractJSONArray(tt.input); got != tt.want {
+				t.Errorf("extractJSONArray() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairDecisionJSON(t *testing.T) {
+	input := `[{"symbol":"BTCUSDT","action":"wait","reasoning":"True, None,]","flag":True,"x":None,"y":False,},]`
+	want := `[{"symbol":"BTCUSDT","action":"wait","reasoning":"True, None,]","flag":true,"x":null,"y":false}]`
+	if got := repairDecisionJSON(input); got != want {
+		t.Errorf("repairDecisionJSON() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractDecisionsRepairsAndValidates(t *testing.T) {
+	decisions, err := extractDecisions("Analysis done.\n" + `[{"symbol":"BTCUSDT","action":"open_long","leverage":3,"take_profit_levels":[{"price":110,"percent":50},],},]`)
+	if err != nil {
+		t.Fatalf("extractDecisions() error = %v", err)
+	}
+	if len(decisions) != 1 || len(decisions[0].TakeProfitLevels) != 1 || decisions[0].Leverage != 3 {
+		t.Errorf("unexpected decisions: %+v", decisions)
+	}
+
+	if _, err := extractDecisions(`[{"symbol":"BTCUSDT","reasoning":"no action"}]`); err == nil || !strings.Contains(err.Error(), "missing action") {
+		t.Errorf("expected missing action error, got %v", err)
+	}
+
+	if _, err := extractDecisions(`[{"symbol":"BTCUSDT","action":"open_long","leverage":"high"}]`); err == nil {
+		t.Error("expected type error for non-numeric leverage")
+	}
+}
+
+func TestParseFullDecisionResponseMarksMalformed(t *testing.T) {
+	_, err := parseFullDecisionResponse(`[{"symbol":"BTCUSDT","action":}]`, 1000, 10, 5, 5, 1, nil)
+	if !errors.Is(err, errMalformedResponse) {
+		t.Errorf("expected errMalformedResponse, got %v", err)
+	}
+
+	_, err = parseFullDecisionResponse(`[{"symbol":"BTCUSDT","action":"fly"}]`, 1000, 10, 5, 5, 1, nil)
+	if err == nil || errors.Is(err, errMalformedResponse) {
+		t.Errorf("expected a validation (not malformed) error, got %v", err)
+	}
+}
+
+func TestBuildRepairPrompt(t *testing.T) {
+	bad := strings.Repeat("x", maxRepairEchoLen+100)
+	prompt := buildRepairPrompt(bad, errors.New("JSON parsing failed: boom\nJSON content: ..."))
+	if !strings.Contains(prompt, "Error: JSON parsing failed: boom\n") {
+		t.Errorf("prompt should include the first error line: %s", prompt[:200])
+	}
+	if strings.Contains(prompt, "JSON content:") {
+		t.Error("prompt should not include the verbose error tail")
+	}
+	if strings.Count(prompt, "x") > maxRepairEchoLen+10 {
+		t.Error("echoed response should be truncated")
+	}
+}
@@ -0,0 +1,94 @@
+package kernel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"nofx/logger"
+)
+
+// ============================================================================
+// Data Source Circuit Breaker
+// ============================================================================
+// Market-wide ranking sources (OI, net flow, price) are external APIs shared by all traders.
+// When one keeps failing, every cycle of every trader would wait on it and log the same error.
+// After sourceBreakerThreshold consecutive failures the source is skipped for a cooldown; the
+// first call after the cooldown probes it again, and a success closes the breaker.
+
+// Circuit breaker settings
+const (
+	sourceBreakerThreshold = 3
+	sourceBreakerCooldown  = 5 * time.Minute
+)
+
+// ErrSourceCircuitOpen returned while a failing data source is skipped
+var ErrSourceCircuitOpen = errors.New("data source temporarily skipped after repeated failures")
+
+// Optional data source names (as reported in unavailable sources)
+const (
+	SourceOIRanking      = "OI ranking"
+	SourceNetFlowRanking = "NetFlow ranking"
+	SourcePriceRanking   = "Price ranking"
+	SourceOrderBook      = "Order book"
+)
+
+type sourceBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	lastErr   error
+}
+
+var (
+	sourceBreakersMu sync.Mutex
+	sourceBreakers   = make(map[string]*sourceBreaker)
+)
+
+// breakerFor returns the shared breaker of a data source
+func breakerFor(name string) *sourceBreaker {
+	sourceBreakersMu.Lock()
+	defer sourceBreakersMu.Unlock()
+	b, ok := sourceBreakers[name]
+	if !ok {
+		b = &sourceBreaker{name: name, threshold: sourceBreakerThreshold, cooldown: sourceBreakerCooldown}
+		sourceBreakers[name] = b
+	}
+	return b
+}
+
+// call runs fetch unless the breaker is open, and records its outcome
+func (b *sourceBreaker) call(fetch func() (interface{}, error)) (interface{}, error) {
+	b.mu.Lock()
+	if now := time.Now(); now.Before(b.openUntil) {
+		until, lastErr := b.openUntil, b.lastErr
+		b.mu.Unlock()
+		return nil, fmt.Errorf("%w (until %s, last error: %v)", ErrSourceCircuitOpen, until.Format("15:04:05"), lastErr)
+	}
+	b.mu.Unlock()
+
+	value, err := fetch()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if b.failures >= b.threshold {
+			logger.Infof("✅ %s source recovered, circuit closed", b.name)
+		}
+		b.failures = 0
+		b.lastErr = nil
+		return value, nil
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		logger.Warnf("🔌 %s source failed %d times in a row, skipping it for %v: %v", b.name, b.failures, b.cooldown, err)
+	}
+	return nil, err
+}
@@ -0,0 +1,43 @@
+package kernel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSourceBreaker(t *testing.T) {
+	b := &sourceBreaker{name: "test", threshold: 2, cooldown: time.Hour}
+	calls := 0
+	failing := func() (interface{}, error) {
+		calls++
+		return nil, errors.New("down")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.call(failing); err == nil || errors.Is(err, ErrSourceCircuitOpen) {
+			t.Fatalf("call %d: err = %v, want the source error", i, err)
+		}
+	}
+	// Open: the source isn't called
+	if _, err := b.call(failing); !errors.Is(err, ErrSourceCircuitOpen) {
+		t.Fatalf("err = %v, want ErrSourceCircuitOpen", err)
+	}
+	if calls != 2 {
+		t.Errorf("source called %d times, want 2", calls)
+	}
+
+	// Cooldown over: a failed probe reopens immediately, a successful one closes
+	b.openUntil = time.Now().Add(-time.Second)
+	b.call(failing)
+	if _, err := b.call(failing); !errors.Is(err, ErrSourceCircuitOpen) {
+		t.Fatalf("failed probe should reopen the breaker, err = %v", err)
+	}
+	b.openUntil = time.Now().Add(-time.Second)
+	if v, err := b.call(func() (interface{}, error) { return 1, nil }); err != nil || v != 1 {
+		t.Fatalf("probe = %v, %v", v, err)
+	}
+	if b.failures != 0 {
+		t.Errorf("failures = %d after success, want 0", b.failures)
+	}
+}
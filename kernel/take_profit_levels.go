@@ -0,0 +1,63 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ============================================================================
+// Take Profit Laddering (scaled exits)
+// ============================================================================
+
+// takeProfitPercentTolerance tolerance for take_profit_levels percent sum (AI rounding)
+const takeProfitPercentTolerance = 0.5
+
+// normalizeTakeProfitLevels sorts take_profit_levels from nearest to furthest and
+// fills take_profit with the furthest level when the AI only provided levels
+func normalizeTakeProfitLevels(decisions []Decision) {
+	for i := range decisions {
+		d := &decisions[i]
+		if len(d.TakeProfitLevels) == 0 {
+			continue
+		}
+
+		// Long: ascending price (nearest first); Short: descending
+		levels := d.TakeProfitLevels
+		if d.Action == "open_short" {
+			sort.SliceStable(levels, func(a, b int) bool { return levels[a].Price > levels[b].Price })
+		} else {
+			sort.SliceStable(levels, func(a, b int) bool { return levels[a].Price < levels[b].Price })
+		}
+
+		if d.TakeProfit <= 0 {
+			d.TakeProfit = levels[len(levels)-1].Price
+		}
+	}
+}
+
+// validateTakeProfitLevels validates scaled take-profit levels of an opening decision
+func validateTakeProfitLevels(d *Decision) error {
+	if len(d.TakeProfitLevels) == 0 {
+		return nil
+	}
+
+	totalPercent := 0.0
+	for i, level := range d.TakeProfitLevels {
+		if level.Price <= 0 || level.Percent <= 0 {
+			return fmt.Errorf("take_profit_levels[%d]: price and percent must be greater than 0", i)
+		}
+		if d.Action == "open_long" && level.Price <= d.StopLoss {
+			return fmt.Errorf("take_profit_levels[%d]: price %.4f must be above stop loss %.4f for long", i, level.Price, d.StopLoss)
+		}
+		if d.Action == "open_short" && level.Price >= d.StopLoss {
+			return fmt.Errorf("take_profit_levels[%d]: price %.4f must be below stop loss %.4f for short", i, level.Price, d.StopLoss)
+		}
+		totalPercent += level.Percent
+	}
+
+	if math.Abs(totalPercent-100) > takeProfitPercentTolerance {
+		return fmt.Errorf("take_profit_levels percents must sum to 100, got %.2f", totalPercent)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package kernel
+
+import "testing"
+
+func TestNormalizeTakeProfitLevels(t *testing.T) {
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", StopLoss: 90, TakeProfitLevels: []TakeProfitLevel{
+			{Price: 130, Percent: 25}, {Price: 110, Percent: 50}, {Price: 120, Percent: 25},
+		}},
+		{Symbol: "ETHUSDT", Action: "open_short", StopLoss: 110, TakeProfit: 85, TakeProfitLevels: []TakeProfitLevel{
+			{Price: 80, Percent: 50}, {Price: 95, Percent: 50},
+		}},
+	}
+	normalizeTakeProfitLevels(decisions)
+
+	long := decisions[0]
+	if long.TakeProfitLevels[0].Price != 110 || long.TakeProfitLevels[2].Price != 130 {
+		t.Errorf("long levels should be sorted nearest first, got %+v", long.TakeProfitLevels)
+	}
+	if long.TakeProfit != 130 {
+		t.Errorf("take_profit should default to furthest level, got %v", long.TakeProfit)
+	}
+
+	short := decisions[1]
+	if short.TakeProfitLevels[0].Price != 95 {
+		t.Errorf("short levels should be sorted nearest first, got %+v", short.TakeProfitLevels)
+	}
+	if short.TakeProfit != 85 {
+		t.Errorf("explicit take_profit should be kept, got %v", short.TakeProfit)
+	}
+}
+
+func TestValidateTakeProfitLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		decision  Decision
+		wantError bool
+	}{
+		{
+			name:     "no levels",
+			decision: Decision{Action: "open_long", StopLoss: 90, TakeProfit: 120},
+		},
+		{
+			name: "valid long ladder",
+			decision: Decision{Action: "open_long", StopLoss: 90, TakeProfitLevels: []TakeProfitLevel{
+				{Price: 110, Percent: 33.3}, {Price: 120, Percent: 33.3}, {Price: 130, Percent: 33.4},
+			}},
+		},
+		{
+			name: "percent sum not 100",
+			decision: Decision{Action: "open_long", StopLoss: 90, TakeProfitLevels: []TakeProfitLevel{
+				{Price: 110, Percent: 50}, {Price: 120, Percent: 30},
+			}},
+			wantError: true,
+		},
+		{
+			name: "short level above stop loss",
+			decision: Decision{Action: "open_short", StopLoss: 110, TakeProfitLevels: []TakeProfitLevel{
+				{Price: 120, Percent: 50}, {Price: 90, Percent: 50},
+			}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTakeProfitLevels(&tt.decision)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateTakeProfitLevels() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package kernel
+
+import (
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func TestBuildSystemPrompt_TraderPrompt(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+
+	t.Run("supplement", func(t *testing.T) {
+		engine := NewStrategyEngine(&cfg)
+		engine.SetTraderPrompt("Only trade SOL on weekends", false)
+		prompt := engine.BuildSystemPrompt(1000, "balanced")
+
+		for _, want := range []string{"Hard Constraints", "Trader Instructions", "Only trade SOL on weekends", "Output Format"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("supplemented prompt should contain %q", want)
+			}
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		engine := NewStrategyEngine(&cfg)
+		engine.SetTraderPrompt("Only trade SOL on weekends", true)
+		prompt := engine.BuildSystemPrompt(1000, "balanced")
+
+		if strings.Contains(prompt, "Hard Constraints") {
+			t.Error("override prompt should not contain base rules")
+		}
+		for _, want := range []string{"Only trade SOL on weekends", "Output Format", "<decision>"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("override prompt should contain %q", want)
+			}
+		}
+	})
+
+	t.Run("override without prompt keeps base", func(t *testing.T) {
+		engine := NewStrategyEngine(&cfg)
+		engine.SetTraderPrompt("  ", true)
+		prompt := engine.BuildSystemPrompt(1000, "balanced")
+		if !strings.Contains(prompt, "Hard Constraints") {
+			t.Error("empty override prompt should fall back to base rules")
+		}
+	})
+}
+
+func TestSetTraderPromptConcurrent(t *testing.T) {
+	cfg := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&cfg)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			engine.SetTraderPrompt("Only trade SOL", i%2 == 0)
+			engine.SetPromptLanguage("en")
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		engine.BuildSystemPrompt(1000, DefaultPromptVariant)
+	}
+	<-done
+}
+
+func TestPromptVariantFor(t *testing.T) {
+	cases := map[string]string{
+		"":             DefaultPromptVariant,
+		"default":      DefaultPromptVariant,
+		"Aggressive":   "aggressive",
+		"scalping":     "scalping",
+		"conservative": "conservative",
+	}
+	for template, want := range cases {
+		if got := PromptVariantFor(template); got != want {
+			t.Errorf("PromptVariantFor(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
@@ -115,3 +115,26 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateDecisionReduce(t *testing.T) {
+	tests := []struct {
+		name      string
+		decision  Decision
+		wantError bool
+	}{
+		{name: "reduce long half", decision: Decision{Symbol: "BTCUSDT", Action: "reduce_long", ClosePercentage: 50}},
+		{name: "reduce short quarter", decision: Decision{Symbol: "SOLUSDT", Action: "reduce_short", ClosePercentage: 25}},
+		{name: "missing percentage", decision: Decision{Symbol: "BTCUSDT", Action: "reduce_long"}, wantError: true},
+		{name: "full close via reduce", decision: Decision{Symbol: "BTCUSDT", Action: "reduce_long", ClosePercentage: 100}, wantError: true},
+		{name: "negative percentage", decision: Decision{Symbol: "BTCUSDT", Action: "reduce_short", ClosePercentage: -10}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDecision(&tt.decision, 1000, 10, 5, 10.0, 1.5)
+			if (err != nil) != tt.wantError {
+				t.Errorf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package logger
 // Config is the logger configuration (simplified version)
 type Config struct {
 	Level string `json:"level"` // Log level: debug, info, warn, error (default: info)
+	// Per-trader log files (disabled by default)
+	TraderLogs TraderLogConfig `json:"trader_logs"`
 }
 
 // SetDefaults sets default values
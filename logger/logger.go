@@ -45,6 +45,11 @@ func (f *compactFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		}
 	}
 
+	// Lines of a traced trading cycle carry its correlation ID, so one cycle can be grepped out
+	if traceID, ok := entry.Data[TraceField].(string); ok && traceID != "" {
+		caller += " [trace=" + traceID + "]"
+	}
+
 	msg := fmt.Sprintf("%s [%s] %s %s\n", timestamp, level, caller, entry.Message)
 	return []byte(msg), nil
 }
@@ -102,6 +107,8 @@ func Init(cfg *Config) error {
 
 	Log.SetReportCaller(true)
 
+	initTraderLogs(cfg.TraderLogs)
+
 	return nil
 }
 
@@ -117,6 +124,14 @@ func Shutdown() {
 		logFile.Close()
 		logFile = nil
 	}
+
+	promptLogMu.Lock()
+	closePromptLogWriters()
+	promptLogMu.Unlock()
+
+	traderLogMu.Lock()
+	closeTraderLogs()
+	traderLogMu.Unlock()
 }
 
 // ============================================================================
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PromptLogConfig per-trader AI prompt log files (opt-in, for debugging strategies)
+type PromptLogConfig struct {
+	Enabled    bool
+	Dir        string // Directory of the log files (default: data/prompt_logs)
+	MaxSizeMB  int    // A file is rotated once it exceeds this size (default: 10)
+	MaxBackups int    // Rotated files kept per trader, older ones are deleted (default: 5)
+}
+
+// SetDefaults sets default values
+func (c *PromptLogConfig) SetDefaults() {
+	if c.Dir == "" {
+		c.Dir = filepath.Join("data", "prompt_logs")
+	}
+	if c.MaxSizeMB <= 0 {
+		c.MaxSizeMB = 10
+	}
+	if c.MaxBackups <= 0 {
+		c.MaxBackups = 5
+	}
+}
+
+// PromptLogEntry the AI exchange of one decision cycle
+type PromptLogEntry struct {
+	TraderID     string
+	TraderName   string
+	Cycle        int
+	Timestamp    time.Time
+	SystemPrompt string
+	InputPrompt  string
+	CoTTrace     string
+	RawResponse  string
+}
+
+var (
+	promptLogMu      sync.Mutex
+	promptLogConfig  PromptLogConfig
+	promptLogWriters = make(map[string]*rotatingFile)
+)
+
+// InitPromptLog configures the per-trader prompt log files (disabled unless cfg.Enabled)
+func InitPromptLog(cfg PromptLogConfig) {
+	cfg.SetDefaults()
+
+	promptLogMu.Lock()
+	defer promptLogMu.Unlock()
+	closePromptLogWriters()
+	promptLogConfig = cfg
+	if cfg.Enabled {
+		Infof("📝 AI prompt logging enabled: %s (rotate at %d MB, keep %d files per trader)", cfg.Dir, cfg.MaxSizeMB, cfg.MaxBackups)
+	}
+}
+
+// LogPrompt appends a cycle's prompts and response to the trader's prompt log file (no-op when disabled)
+func LogPrompt(entry PromptLogEntry) {
+	promptLogMu.Lock()
+	defer promptLogMu.Unlock()
+	if !promptLogConfig.Enabled || entry.TraderID == "" {
+		return
+	}
+
+	w, ok := promptLogWriters[entry.TraderID]
+	if !ok {
+		w = &rotatingFile{
+			path:       filepath.Join(promptLogConfig.Dir, promptLogFileName(entry.TraderID)),
+			maxBytes:   int64(promptLogConfig.MaxSizeMB) * 1024 * 1024,
+			maxBackups: promptLogConfig.MaxBackups,
+		}
+		promptLogWriters[entry.TraderID] = w
+	}
+	if _, err := w.Write([]byte(formatPromptLogEntry(entry))); err != nil {
+		Warnf("⚠️ Failed to write prompt log for trader %s: %v", entry.TraderID, err)
+	}
+}
+
+// closePromptLogWriters closes all open prompt log files (caller holds promptLogMu)
+func closePromptLogWriters() {
+	for id, w := range promptLogWriters {
+		w.Close()
+		delete(promptLogWriters, id)
+	}
+}
+
+// promptLogUnsafe characters not allowed in prompt log file names
+var promptLogUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+func promptLogFileName(traderID string) string {
+	return "prompts_" + promptLogUnsafe.ReplaceAllString(traderID, "_") + ".log"
+}
+
+func formatPromptLogEntry(e PromptLogEntry) string {
+	var sb strings.Builder
+	ts := e.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	sb.WriteString(fmt.Sprintf("==================== %s | trader=%s (%s) | cycle=%d ====================\n",
+		ts.UTC().Format(time.RFC3339), e.TraderID, e.TraderName, e.Cycle))
+	for _, section := range []struct{ name, text string }{
+		{"SYSTEM PROMPT", e.SystemPrompt},
+		{"USER PROMPT", e.InputPrompt},
+		{"CHAIN OF THOUGHT", e.CoTTrace},
+		{"RAW RESPONSE", e.RawResponse},
+	} {
+		if section.text == "" {
+			continue
+		}
+		sb.WriteString("----- " + section.name + " -----\n")
+		sb.WriteString(section.text)
+		if !strings.HasSuffix(section.text, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// rotatingFile an append-only file rotated by size: file.log -> file.log.1 -> ... -> file.log.N
+type rotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the backups by one (dropping the oldest) and starts a new file
+func (r *rotatingFile) rotate() error {
+	r.Close()
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file, r.size = nil, 0
+	return err
+}
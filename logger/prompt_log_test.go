@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prompts_t1.log")
+	w := &rotatingFile{path: path, maxBytes: 100, maxBackups: 2}
+	defer w.Close()
+
+	chunk := []byte(strings.Repeat("x", 60) + "\n")
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+
+	for _, name := range []string{"prompts_t1.log", "prompts_t1.log.1", "prompts_t1.log.2"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s missing: %v", name, err)
+		}
+		if info.Size() > 100 {
+			t.Errorf("%s is %d bytes, want at most 100", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups, found %s.3", path)
+	}
+}
+
+func TestLogPrompt(t *testing.T) {
+	dir := t.TempDir()
+	InitPromptLog(PromptLogConfig{Enabled: true, Dir: dir})
+	defer InitPromptLog(PromptLogConfig{})
+
+	LogPrompt(PromptLogEntry{TraderID: "../t/1", TraderName: "Trend", Cycle: 7, SystemPrompt: "sys", InputPrompt: "user", RawResponse: "resp"})
+
+	data, err := os.ReadFile(filepath.Join(dir, "prompts_.._t_1.log"))
+	if err != nil {
+		t.Fatalf("prompt log not written: %v", err)
+	}
+	for _, want := range []string{"cycle=7", "----- SYSTEM PROMPT -----\nsys\n", "----- USER PROMPT -----\nuser\n", "----- RAW RESPONSE -----\nresp\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("prompt log missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "CHAIN OF THOUGHT") {
+		t.Error("empty sections should be skipped")
+	}
+
+	InitPromptLog(PromptLogConfig{Enabled: false, Dir: dir})
+	LogPrompt(PromptLogEntry{TraderID: "t2", SystemPrompt: "sys"})
+	if _, err := os.Stat(filepath.Join(dir, "prompts_t2.log")); !os.IsNotExist(err) {
+		t.Error("prompt logged while disabled")
+	}
+}
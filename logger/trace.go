@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TraceField log field holding the correlation ID of a trading cycle
+const TraceField = "trace_id"
+
+// Entry a logger entry carrying fields (such as the correlation ID)
+type Entry = logrus.Entry
+
+type traceKey struct{}
+
+// NewTraceID generates a new correlation ID
+func NewTraceID() string {
+	return uuid.New().String()
+}
+
+// WithTraceID returns a copy of ctx carrying the correlation ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceKey{}, traceID)
+}
+
+// TraceID returns the correlation ID carried by ctx ("" if none)
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceKey{}).(string)
+	return traceID
+}
+
+// FromContext returns a logger entry tagging every line with ctx's correlation ID
+func FromContext(ctx context.Context) *Entry {
+	if traceID := TraceID(ctx); traceID != "" {
+		return Log.WithField(TraceField, traceID)
+	}
+	return logrus.NewEntry(Log)
+}
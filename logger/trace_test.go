@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestTraceIDContext(t *testing.T) {
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID(no trace) = %q, want empty", got)
+	}
+
+	id := NewTraceID()
+	ctx := WithTraceID(context.Background(), id)
+	if got := TraceID(ctx); got != id {
+		t.Errorf("TraceID() = %q, want %q", got, id)
+	}
+	if NewTraceID() == id {
+		t.Error("NewTraceID should generate unique IDs")
+	}
+}
+
+func TestFormatterIncludesTraceID(t *testing.T) {
+	f := &compactFormatter{}
+
+	entry := FromContext(WithTraceID(context.Background(), "abc-123"))
+	entry.Message = "cycle started"
+	entry.Level = logrus.InfoLevel
+	out, err := f.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "[trace=abc-123] cycle started") {
+		t.Errorf("traced line = %q, want the trace ID before the message", out)
+	}
+
+	plain := FromContext(context.Background())
+	plain.Message = "no trace"
+	plain.Level = logrus.InfoLevel
+	out, _ = f.Format(plain)
+	if strings.Contains(string(out), "trace=") {
+		t.Errorf("untraced line = %q, should not carry a trace ID", out)
+	}
+}
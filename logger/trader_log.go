@@ -0,0 +1,307 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TraderLogConfig per-trader log files (opt-in): a trader's lines also go to its own file, so one
+// bot can be followed among many
+type TraderLogConfig struct {
+	Enabled    bool
+	Dir        string // Directory of the log files (default: data/trader_logs)
+	MaxSizeMB  int    // A file is rotated once it exceeds this size (default: 20)
+	MaxBackups int    // Rotated files kept per trader, older ones are deleted (default: 3)
+}
+
+// SetDefaults sets default values
+func (c *TraderLogConfig) SetDefaults() {
+	if c.Dir == "" {
+		c.Dir = filepath.Join("data", "trader_logs")
+	}
+	if c.MaxSizeMB <= 0 {
+		c.MaxSizeMB = 20
+	}
+	if c.MaxBackups <= 0 {
+		c.MaxBackups = 3
+	}
+}
+
+const (
+	// DefaultTraderLogTail lines returned by ReadTraderLog when no count is given
+	DefaultTraderLogTail = 500
+	// MaxTraderLogTail most lines ReadTraderLog returns
+	MaxTraderLogTail = 5000
+	// traderLogTailBytes how far from the end of the file a tail read looks
+	traderLogTailBytes = 4 << 20
+)
+
+// ErrTraderLogDisabled returned when reading a trader's log while trader log files are disabled
+var ErrTraderLogDisabled = errors.New("trader log files are disabled")
+
+// traderLogWriter a trader's log file, safe to close while the trader is logging
+type traderLogWriter struct {
+	mu   sync.Mutex
+	file *rotatingFile
+}
+
+func (w *traderLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Write(p)
+}
+
+func (w *traderLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// traderLog logger of one trader
+type traderLog struct {
+	logger *logrus.Logger
+	writer *traderLogWriter // nil when trader log files are disabled
+}
+
+// TraderLogger the logger of one trader, cached by the trader (and the exchange client it uses) so
+// logging takes no global lock. Settings changes (Init, SetTraderLevel, CloseTraderLog) reset it
+// when they happen; the next entry then resolves the trader's current logger.
+type TraderLogger struct {
+	traderID string
+	current  atomic.Pointer[logrus.Logger]
+}
+
+// Entry returns an entry of the trader's logger tagged with ctx's correlation ID. Its lines also go
+// to the trader's own file (when trader log files are enabled) and follow the trader's log level.
+// A nil TraderLogger logs through the global logger.
+func (l *TraderLogger) Entry(ctx context.Context) *Entry {
+	var lg *logrus.Logger
+	if l == nil {
+		lg = Log
+	} else if lg = l.current.Load(); lg == nil {
+		traderLogMu.Lock()
+		lg = traderLoggerLocked(l.traderID)
+		l.current.Store(lg)
+		traderLogMu.Unlock()
+	}
+	if traceID := TraceID(ctx); traceID != "" {
+		return lg.WithField(TraceField, traceID)
+	}
+	return logrus.NewEntry(lg)
+}
+
+// ForTrader returns the logger of a trader, the same one for every call with traderID
+func ForTrader(traderID string) *TraderLogger {
+	traderLogMu.Lock()
+	defer traderLogMu.Unlock()
+	l, ok := traderLoggers[traderID]
+	if !ok {
+		l = &TraderLogger{traderID: traderID}
+		traderLoggers[traderID] = l
+	}
+	return l
+}
+
+// resetTraderLogger makes a trader's cached logger resolve again on its next entry (caller holds
+// traderLogMu)
+func resetTraderLogger(traderID string) {
+	if l, ok := traderLoggers[traderID]; ok {
+		l.current.Store(nil)
+	}
+}
+
+// globalOutput writes to the global logger's current output, so trader lines keep following it
+// when it's replaced (Init, tests)
+type globalOutput struct{}
+
+func (globalOutput) Write(p []byte) (int, error) {
+	return Log.Out.Write(p)
+}
+
+var (
+	traderLogMu     sync.Mutex
+	traderLogConfig TraderLogConfig
+	traderLogs      = make(map[string]*traderLog)
+	traderLevels    = make(map[string]logrus.Level) // Per-trader level overrides
+	traderLoggers   = make(map[string]*TraderLogger)
+)
+
+// initTraderLogs configures the per-trader log files (disabled unless cfg.Enabled)
+func initTraderLogs(cfg TraderLogConfig) {
+	cfg.SetDefaults()
+
+	traderLogMu.Lock()
+	defer traderLogMu.Unlock()
+	closeTraderLogs()
+	traderLogConfig = cfg
+	if cfg.Enabled {
+		Infof("📝 Per-trader log files enabled: %s (rotate at %d MB, keep %d files per trader)", cfg.Dir, cfg.MaxSizeMB, cfg.MaxBackups)
+	}
+}
+
+// closeTraderLogs closes all trader log files, so trader loggers pick up the global logger's current
+// settings (caller holds traderLogMu)
+func closeTraderLogs() {
+	for id, tl := range traderLogs {
+		if tl.writer != nil {
+			tl.writer.Close()
+		}
+		delete(traderLogs, id)
+	}
+	for id := range traderLoggers {
+		resetTraderLogger(id)
+	}
+}
+
+func traderLogFileName(traderID string) string {
+	return "trader_" + promptLogUnsafe.ReplaceAllString(traderID, "_") + ".log"
+}
+
+// ParseLevel validates a log level name ("" = inherit the global level)
+func ParseLevel(level string) error {
+	if level == "" {
+		return nil
+	}
+	if _, err := logrus.ParseLevel(level); err != nil {
+		return fmt.Errorf("invalid log level %q (debug, info, warn, error)", level)
+	}
+	return nil
+}
+
+// SetTraderLevel sets a trader's log level ("" = the global level)
+func SetTraderLevel(traderID, level string) error {
+	traderLogMu.Lock()
+	defer traderLogMu.Unlock()
+
+	if level == "" {
+		delete(traderLevels, traderID)
+	} else {
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q (debug, info, warn, error)", level)
+		}
+		traderLevels[traderID] = parsed
+	}
+	if tl, ok := traderLogs[traderID]; ok {
+		tl.logger.SetLevel(traderLevelLocked(traderID))
+	}
+	resetTraderLogger(traderID) // A trader on the global logger gets its own for the override
+	return nil
+}
+
+// traderLevelLocked returns a trader's effective level (caller holds traderLogMu)
+func traderLevelLocked(traderID string) logrus.Level {
+	if level, ok := traderLevels[traderID]; ok {
+		return level
+	}
+	return Log.GetLevel()
+}
+
+// traderLoggerLocked returns the logger of a trader: the global output plus the trader's file, at the
+// trader's level, with the global formatter and caller reporting. Traders without a file or level
+// override use the global logger. Caller holds traderLogMu.
+func traderLoggerLocked(traderID string) *logrus.Logger {
+	if tl, ok := traderLogs[traderID]; ok {
+		return tl.logger
+	}
+	_, hasLevel := traderLevels[traderID]
+	if traderID == "" || (!traderLogConfig.Enabled && !hasLevel) {
+		return Log
+	}
+
+	tl := &traderLog{logger: logrus.New()}
+	tl.logger.SetLevel(traderLevelLocked(traderID))
+	tl.logger.SetFormatter(Log.Formatter)
+	tl.logger.SetReportCaller(Log.ReportCaller)
+	var out io.Writer = globalOutput{}
+	if traderLogConfig.Enabled {
+		tl.writer = &traderLogWriter{file: &rotatingFile{
+			path:       filepath.Join(traderLogConfig.Dir, traderLogFileName(traderID)),
+			maxBytes:   int64(traderLogConfig.MaxSizeMB) * 1024 * 1024,
+			maxBackups: traderLogConfig.MaxBackups,
+		}}
+		out = io.MultiWriter(globalOutput{}, tl.writer)
+	}
+	tl.logger.SetOutput(out)
+	traderLogs[traderID] = tl
+	return tl.logger
+}
+
+// TraderEntry returns a logger entry of a trader tagged with ctx's correlation ID (see
+// TraderLogger.Entry). Code logging often for the same trader keeps its ForTrader instead.
+func TraderEntry(traderID string, ctx context.Context) *Entry {
+	return ForTrader(traderID).Entry(ctx)
+}
+
+// CloseTraderLog closes a trader's log file (the trader was removed or reloaded); its level is kept
+func CloseTraderLog(traderID string) {
+	traderLogMu.Lock()
+	defer traderLogMu.Unlock()
+	if tl, ok := traderLogs[traderID]; ok {
+		if tl.writer != nil {
+			tl.writer.Close()
+		}
+		delete(traderLogs, traderID)
+	}
+	resetTraderLogger(traderID)
+}
+
+// ReadTraderLog returns the last tail lines of a trader's log file (DefaultTraderLogTail if tail <= 0,
+// at most MaxTraderLogTail); no lines if the trader hasn't logged yet
+func ReadTraderLog(traderID string, tail int) ([]string, error) {
+	traderLogMu.Lock()
+	cfg := traderLogConfig
+	traderLogMu.Unlock()
+	if !cfg.Enabled {
+		return nil, ErrTraderLogDisabled
+	}
+	if tail <= 0 {
+		tail = DefaultTraderLogTail
+	}
+	if tail > MaxTraderLogTail {
+		tail = MaxTraderLogTail
+	}
+
+	f, err := os.Open(filepath.Join(cfg.Dir, traderLogFileName(traderID)))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - traderLogTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(buf), "\n"), "\n")
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:] // Partial first line
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return []string{}, nil
+	}
+	if len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return lines, nil
+}
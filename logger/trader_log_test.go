@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestTraderLog(t *testing.T) {
+	out := Log.Out
+	Log.SetOutput(io.Discard)
+	defer Log.SetOutput(out)
+
+	dir := t.TempDir()
+	initTraderLogs(TraderLogConfig{Enabled: true, Dir: dir})
+	defer initTraderLogs(TraderLogConfig{})
+	defer SetTraderLevel("t1", "")
+
+	if err := SetTraderLevel("t1", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithTraceID(context.Background(), "abc123")
+	for i := 0; i < 5; i++ {
+		TraderEntry("t1", ctx).Warnf("line %d", i)
+	}
+	TraderEntry("t1", ctx).Info("filtered by the trader's level")
+	TraderEntry("t2", nil).Warn("other trader")
+
+	lines, err := ReadTraderLog("t1", 3)
+	if err != nil {
+		t.Fatalf("ReadTraderLog() error = %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines %q, want 3", len(lines), lines)
+	}
+	if !strings.HasSuffix(lines[2], "line 4") || !strings.Contains(lines[2], "[trace=abc123]") {
+		t.Errorf("last line = %q", lines[2])
+	}
+	all, _ := ReadTraderLog("t1", 0)
+	for _, l := range all {
+		if strings.Contains(l, "filtered") || strings.Contains(l, "other trader") {
+			t.Errorf("unexpected line in t1 log: %q", l)
+		}
+	}
+
+	if lines, err := ReadTraderLog("never-logged", 10); err != nil || len(lines) != 0 {
+		t.Errorf("ReadTraderLog(never-logged) = %v, %v, want no lines", lines, err)
+	}
+
+	initTraderLogs(TraderLogConfig{})
+	if _, err := ReadTraderLog("t1", 10); !errors.Is(err, ErrTraderLogDisabled) {
+		t.Errorf("ReadTraderLog() while disabled error = %v, want ErrTraderLogDisabled", err)
+	}
+}
+
+// TestTraderLogFollowsGlobal checks that a cached trader logger picks up changes of the global level
+// and output made by Init, and of the trader's own level
+func TestTraderLogFollowsGlobal(t *testing.T) {
+	out, level := Log.Out, Log.GetLevel()
+	defer func() {
+		Log.SetOutput(out)
+		Log.SetLevel(level)
+	}()
+
+	cfg := TraderLogConfig{Enabled: true, Dir: t.TempDir()}
+	initTraderLogs(cfg)
+	defer initTraderLogs(TraderLogConfig{})
+	defer SetTraderLevel("t1", "")
+
+	var first, second bytes.Buffer
+	Log.SetOutput(&first)
+	Log.SetLevel(logrus.InfoLevel)
+	l := ForTrader("t1")
+	l.Entry(nil).Info("before")
+
+	// What Init does: new global settings, then the trader logs are re-initialized
+	Log.SetOutput(&second)
+	Log.SetLevel(logrus.WarnLevel)
+	initTraderLogs(cfg)
+	l.Entry(nil).Info("filtered by the new global level")
+	l.Entry(nil).Warn("after")
+
+	if !strings.Contains(first.String(), "before") || strings.Contains(first.String(), "after") {
+		t.Errorf("first output = %q, want only the line logged before the switch", first.String())
+	}
+	if strings.Contains(second.String(), "filtered") || !strings.Contains(second.String(), "after") {
+		t.Errorf("second output = %q, want only the warning", second.String())
+	}
+
+	if err := SetTraderLevel("t1", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	l.Entry(nil).Debug("trader level")
+	if !strings.Contains(second.String(), "trader level") {
+		t.Errorf("second output = %q, want the debug line allowed by the trader's level", second.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, level := range []string{"", "debug", "info", "warn", "error"} {
+		if err := ParseLevel(level); err != nil {
+			t.Errorf("ParseLevel(%q) error = %v", level, err)
+		}
+	}
+	if err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) should fail")
+	}
+}
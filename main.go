@@ -7,14 +7,18 @@ import (
 	"nofx/config"
 	"nofx/crypto"
 	"nofx/experience"
+	"nofx/integrations/discord"
+	"nofx/kernel"
 	"nofx/logger"
 	"nofx/manager"
 	"nofx/mcp"
 	"nofx/store"
+	"nofx/trader"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -24,17 +28,31 @@ func main() {
 	// Load .env environment variables
 	_ = godotenv.Load()
 
+	// Initialize global configuration (loaded from .env)
+	config.Init()
+	cfg := config.Get()
+
 	// Initialize logger
-	logger.Init(nil)
+	logger.Init(&logger.Config{
+		Level: cfg.LogLevel,
+		TraderLogs: logger.TraderLogConfig{
+			Enabled:    cfg.TraderLogEnabled,
+			Dir:        cfg.TraderLogDir,
+			MaxSizeMB:  cfg.TraderLogMaxSizeMB,
+			MaxBackups: cfg.TraderLogMaxBackups,
+		},
+	})
 
 	logger.Info("╔════════════════════════════════════════════════════════════╗")
 	logger.Info("║           🚀 NOFX - AI-Powered Trading System              ║")
 	logger.Info("╚════════════════════════════════════════════════════════════╝")
-
-	// Initialize global configuration (loaded from .env)
-	config.Init()
-	cfg := config.Get()
 	logger.Info("✅ Configuration loaded")
+	logger.InitPromptLog(logger.PromptLogConfig{
+		Enabled:    cfg.PromptLogEnabled,
+		Dir:        cfg.PromptLogDir,
+		MaxSizeMB:  cfg.PromptLogMaxSizeMB,
+		MaxBackups: cfg.PromptLogMaxBackups,
+	})
 
 	// Initialize encryption service BEFORE database (so EncryptedString can decrypt on read)
 	logger.Info("🔐 Initializing encryption service...")
@@ -73,6 +91,9 @@ func main() {
 		Password: cfg.DBPassword,
 		DBName:   cfg.DBName,
 		SSLMode:  cfg.DBSSLMode,
+
+		JournalMode: cfg.DBJournalMode,
+		BusyTimeout: time.Duration(cfg.DBBusyTimeoutMs) * time.Millisecond,
 	})
 	if err != nil {
 		logger.Fatalf("❌ Failed to initialize database: %v", err)
@@ -80,9 +101,27 @@ func main() {
 	defer st.Close()
 	backtest.UseDatabase(st.DB())
 
+	// Start background data retention (equity snapshot downsampling, history pruning)
+	retentionCfg := store.DefaultRetentionConfig()
+	retentionCfg.EquityFullResolutionWindow = time.Duration(cfg.EquityRetentionDays) * 24 * time.Hour
+	retentionCfg.EquityDownsampleBucket = time.Duration(cfg.EquityDownsampleMinutes) * time.Minute
+	retentionCfg.RecordPrune.MaxAge = time.Duration(cfg.RecordRetentionDays) * 24 * time.Hour
+	retentionCfg.RecordPrune.MaxPerTrader = cfg.RecordRetentionMax
+	retentionCfg.RecordPrune.BatchSize = cfg.RecordPruneBatchSize
+	retentionCfg.DecisionArchiveAge = time.Duration(cfg.DecisionArchiveDays) * 24 * time.Hour
+	retentionCfg.APIAuditMaxAge = time.Duration(cfg.APIAuditRetentionDays) * 24 * time.Hour
+	stopRetention := st.StartRetentionJob(retentionCfg)
+	defer stopRetention()
+
 	// Initialize installation ID for experience improvement (anonymous statistics)
 	initInstallationID(st)
 
+	// Restore the global trading pause before any trader starts
+	initTradingPause(st)
+
+	// Sync the admin role with the users listed in ADMIN_EMAILS
+	initAdmins(st, cfg.AdminEmails)
+
 	// Set JWT secret
 	auth.SetJWTSecret(cfg.JWTSecret)
 	logger.Info("🔑 JWT secret configured")
@@ -95,6 +134,18 @@ func main() {
 	// time.Sleep(500 * time.Millisecond)
 	logger.Info("📊 Using CoinAnk API for all market data (WebSocket cache disabled)")
 
+	// Validity window of signed exchange requests (timestamps follow each exchange's clock)
+	trader.SetRecvWindow(int64(cfg.ExchangeRecvWindowMs))
+
+	// Share indicator data (quant data, rankings) between traders for a short TTL
+	kernel.SetDataCacheTTL(time.Duration(cfg.StrategyDataCacheTTLSeconds) * time.Second)
+
+	// Limit concurrent AI decision calls across all traders (traders queue for a slot)
+	mcp.SetMaxConcurrentCalls(cfg.MaxConcurrentAICalls)
+	if cfg.MaxConcurrentAICalls > 0 {
+		logger.Infof("🤖 Max concurrent AI calls: %d", cfg.MaxConcurrentAICalls)
+	}
+
 	// Create TraderManager and BacktestManager
 	traderManager := manager.NewTraderManager()
 	mcpClient := newSharedMCPClient()
@@ -136,6 +187,17 @@ func main() {
 		}
 	}()
 
+	// Start Discord bot (optional)
+	discordBot := discord.New(discord.Config{
+		BotToken: cfg.DiscordBotToken,
+		GuildID:  cfg.DiscordGuildID,
+		UserMap:  discord.ParseUserMap(cfg.DiscordUserMap),
+	}, traderManager, st)
+	if discordBot != nil {
+		discordBot.Start()
+		logger.Info("🤖 Discord bot enabled")
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -146,6 +208,10 @@ func main() {
 	<-quit
 	logger.Info("📴 Shutdown signal received, closing system...")
 
+	if discordBot != nil {
+		discordBot.Stop()
+	}
+
 	// Stop all traders
 	traderManager.StopAll()
 	logger.Info("✅ System shut down safely")
@@ -161,6 +227,36 @@ func newSharedMCPClient() mcp.AIClient {
 	return mcp.NewDeepSeekClient()
 }
 
+// initTradingPause restores the admin's global trading pause, so an emergency brake survives a restart
+func initTradingPause(st *store.Store) {
+	value, err := st.GetSystemConfig(trader.TradingPausedConfigKey)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to load global trading pause: %v", err)
+		return
+	}
+	if value == "true" {
+		trader.SetTradingPaused(true)
+		logger.Warn("⏸ Trading is globally paused (resume with POST /api/admin/resume-all)")
+	}
+}
+
+// initAdmins gives the admin role to the registered users with the configured emails and takes it
+// from users no longer listed. Only existing accounts are promoted: registering with a listed email
+// doesn't prove owning it, so such a user becomes admin on the next restart.
+func initAdmins(st *store.Store, emails []string) {
+	promoted, demoted, err := st.User().SyncAdmins(emails)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to sync admin role: %v", err)
+		return
+	}
+	if promoted > 0 {
+		logger.Infof("🛡 Granted admin role to %d user(s) from ADMIN_EMAILS", promoted)
+	}
+	if demoted > 0 {
+		logger.Infof("🛡 Revoked admin role from %d user(s) no longer in ADMIN_EMAILS", demoted)
+	}
+}
+
 // initInstallationID initializes the anonymous installation ID for experience improvement
 // This ID is persisted in database and used for anonymous usage statistics
 func initInstallationID(st *store.Store) {
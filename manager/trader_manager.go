@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"nofx/config"
 	"nofx/debate"
 	"nofx/kernel"
 	"nofx/logger"
@@ -46,6 +47,7 @@ type TraderManager struct {
 	traders          map[string]*trader.AutoTrader // key: trader ID
 	loadErrors       map[string]error              // key: trader ID, stores last load error
 	competitionCache *CompetitionCache
+	lifecycleLocks   map[string]*sync.Mutex // key: trader ID, see LockTrader
 	mu               sync.RWMutex
 }
 
@@ -60,6 +62,24 @@ func NewTraderManager() *TraderManager {
 	}
 }
 
+// LockTrader serializes the lifecycle operations of one trader (start, restart, history reset), so a
+// trader checked to be stopped can't be started until the operation is done. Returns the unlock func.
+func (tm *TraderManager) LockTrader(traderID string) func() {
+	tm.mu.Lock()
+	if tm.lifecycleLocks == nil {
+		tm.lifecycleLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := tm.lifecycleLocks[traderID]
+	if !ok {
+		lock = &sync.Mutex{}
+		tm.lifecycleLocks[traderID] = lock
+	}
+	tm.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
 // GetLoadError returns the last load error for a trader
 func (tm *TraderManager) GetLoadError(traderID string) error {
 	tm.mu.RLock()
@@ -142,7 +162,7 @@ func (tm *TraderManager) AutoStartRunningTraders(st *store.Store) {
 	// Build set of running trader IDs
 	runningTraderIDs := make(map[string]bool)
 	for _, traderCfg := range traderList {
-		if traderCfg.IsRunning {
+		if traderCfg.IsRunning && !traderCfg.Disabled {
 			runningTraderIDs[traderCfg.ID] = true
 		}
 	}
@@ -423,6 +443,7 @@ func (tm *TraderManager) RemoveTrader(traderID string) {
 			t.Stop()
 		}
 		delete(tm.traders, traderID)
+		logger.CloseTraderLog(traderID)
 		logger.Infof("✓ Trader %s removed from memory", traderID)
 	}
 }
@@ -655,6 +676,7 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		AIModel:               aiModelCfg.Provider,
 		Exchange:              exchangeCfg.ExchangeType, // Exchange type: binance/bybit/okx/etc
 		ExchangeID:            exchangeCfg.ID,           // Exchange account UUID (for multi-account)
+		QuoteAsset:            traderCfg.QuoteAsset,
 		BinanceAPIKey:         "",
 		BinanceSecretKey:      "",
 		HyperliquidPrivateKey: "",
@@ -669,8 +691,60 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		IsCrossMargin:        traderCfg.IsCrossMargin,
 		ShowInCompetition:    traderCfg.ShowInCompetition,
 		StrategyConfig:       strategyConfig,
+		EquityHighWaterMark:  traderCfg.EquityHighWaterMark,
+		PromptLanguage:       traderCfg.PromptLanguage,
+		ConsensusThreshold:   traderCfg.ConsensusThreshold,
+		AIRequestTimeout:     time.Duration(traderCfg.AIRequestTimeoutSec) * time.Second,
+		DisplayDecimals:      traderCfg.DisplayDecimals,
+		LogLevel:             traderCfg.LogLevel,
+		PromptVariant:        kernel.PromptVariantFor(traderCfg.SystemPromptTemplate),
+	}
+
+	// Multi-model consensus: resolve the voting models' credentials
+	if traderCfg.ConsensusModels != "" {
+		specs, err := trader.ParseConsensusModels(traderCfg.ConsensusModels)
+		if err != nil {
+			return fmt.Errorf("invalid consensus models for trader %s: %w", traderCfg.Name, err)
+		}
+		for _, spec := range specs {
+			if spec.ID == traderCfg.AIModelID {
+				continue // The trader's own model always votes
+			}
+			model, err := st.AIModel().Get(traderCfg.UserID, spec.ID)
+			if err != nil {
+				logger.Warnf("⚠️ Consensus model %s for trader %s not found, skipping: %v", spec.ID, traderCfg.Name, err)
+				continue
+			}
+			traderConfig.ConsensusModels = append(traderConfig.ConsensusModels, trader.ConsensusModelConfig{
+				ID:              model.ID,
+				Provider:        model.Provider,
+				APIKey:          string(model.APIKey),
+				CustomAPIURL:    model.CustomAPIURL,
+				CustomModelName: model.CustomModelName,
+				Weight:          spec.Weight,
+			})
+		}
+		if len(traderConfig.ConsensusModels) > 0 {
+			logger.Infof("🗳 Trader %s uses consensus of %d models", traderCfg.Name, len(traderConfig.ConsensusModels)+1)
+		}
 	}
 
+	// Order sync retry/health settings (global)
+	globalCfg := config.Get()
+	traderConfig.OrderSync = trader.DefaultOrderSyncConfig()
+	traderConfig.OrderSync.MaxRetries = globalCfg.OrderSyncMaxRetries
+	traderConfig.OrderSync.UnhealthyAfter = globalCfg.OrderSyncUnhealthyAfter
+	traderConfig.OrderSync.PauseOnUnhealthy = globalCfg.OrderSyncPauseOnUnhealthy
+	traderConfig.OrderFill = trader.OrderFillConfig{
+		PollAttempts:  globalCfg.OrderFillPollAttempts,
+		PollInterval:  time.Duration(globalCfg.OrderFillPollIntervalMs) * time.Millisecond,
+		UseUserStream: globalCfg.OrderFillUserStream,
+	}.ForExchange(exchangeCfg.ExchangeType, globalCfg.OrderFillOverrides)
+	traderConfig.SignalRateLimitPerMinute = globalCfg.SignalRateLimitPerMinute
+	traderConfig.TransferReconcileInterval = time.Duration(globalCfg.TransferReconcileMin) * time.Minute
+	traderConfig.FundingReconcileInterval = time.Duration(globalCfg.FundingReconcileMin) * time.Minute
+	traderConfig.BalanceAnomalyPct = globalCfg.BalanceSyncMaxChangePct
+
 	logger.Infof("📊 Loading trader %s: ScanIntervalMinutes=%d (from DB), ScanInterval=%v",
 		traderCfg.Name, traderCfg.ScanIntervalMinutes, traderConfig.ScanInterval)
 
@@ -679,17 +753,21 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 	case "binance":
 		traderConfig.BinanceAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.BinanceSecretKey = string(exchangeCfg.SecretKey)
+		traderConfig.BinanceTestnet = exchangeCfg.Testnet
 	case "bybit":
 		traderConfig.BybitAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.BybitSecretKey = string(exchangeCfg.SecretKey)
+		traderConfig.BybitTestnet = exchangeCfg.Testnet
 	case "okx":
 		traderConfig.OKXAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.OKXSecretKey = string(exchangeCfg.SecretKey)
 		traderConfig.OKXPassphrase = string(exchangeCfg.Passphrase)
+		traderConfig.OKXTestnet = exchangeCfg.Testnet
 	case "bitget":
 		traderConfig.BitgetAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.BitgetSecretKey = string(exchangeCfg.SecretKey)
 		traderConfig.BitgetPassphrase = string(exchangeCfg.Passphrase)
+		traderConfig.BitgetTestnet = exchangeCfg.Testnet
 	case "hyperliquid":
 		traderConfig.HyperliquidPrivateKey = string(exchangeCfg.APIKey)
 		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
@@ -706,6 +784,38 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 	case "gateio":
 		traderConfig.GateAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.GateSecretKey = string(exchangeCfg.SecretKey)
+	case "coinbase":
+		traderConfig.CoinbaseAPIKey = string(exchangeCfg.APIKey)
+		traderConfig.CoinbaseSecretKey = string(exchangeCfg.SecretKey)
+		traderConfig.CoinbasePassphrase = string(exchangeCfg.Passphrase)
+	case "backpack":
+		traderConfig.BackpackAPIKey = string(exchangeCfg.APIKey)
+		traderConfig.BackpackSecretKey = string(exchangeCfg.SecretKey)
+	}
+	traderConfig.ExtraAPIKeys = exchangeCfg.AdditionalAPIKeys()
+	traderConfig.Slippage = exchangeCfg.SlippageSettings()
+
+	// Mirror exchanges: every order is replicated on these accounts too
+	if traderCfg.MirrorExchangeIDs != "" {
+		ids, err := trader.ParseMirrorExchangeIDs(traderCfg.MirrorExchangeIDs)
+		if err != nil {
+			return fmt.Errorf("invalid mirror exchanges for trader %s: %w", traderCfg.Name, err)
+		}
+		for _, id := range ids {
+			if id == exchangeCfg.ID {
+				continue
+			}
+			mirror, err := st.Exchange().GetByID(traderCfg.UserID, id)
+			if err != nil {
+				logger.Warnf("⚠️ Mirror exchange %s for trader %s not found, skipping: %v", id, traderCfg.Name, err)
+				continue
+			}
+			if !mirror.Enabled {
+				logger.Warnf("⚠️ Mirror exchange %s for trader %s is not enabled, skipping", id, traderCfg.Name)
+				continue
+			}
+			traderConfig.MirrorExchanges = append(traderConfig.MirrorExchanges, mirror)
+		}
 	}
 
 	// Set API keys based on AI model (convert EncryptedString to string)
@@ -739,6 +849,12 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 	tm.traders[traderCfg.ID] = at
 	logger.Infof("✓ Trader '%s' (%s + %s/%s) loaded to memory", traderCfg.Name, aiModelCfg.Provider, exchangeCfg.ExchangeType, exchangeCfg.AccountName)
 
+	// Disabled traders (kill-switch) stay stopped until explicitly re-enabled
+	if traderCfg.IsRunning && traderCfg.Disabled {
+		logger.Infof("🛑 Trader '%s' is disabled (kill-switch), not auto-starting", traderCfg.Name)
+		return nil
+	}
+
 	// Auto-start if trader was running before shutdown
 	if traderCfg.IsRunning {
 		logger.Infof("🔄 Auto-starting trader '%s' (was running before shutdown)...", traderCfg.Name)
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"testing"
+	"time"
 )
 
 // TestRemoveTrader tests removing trader from memory
@@ -85,3 +86,29 @@ func TestGetTrader_AfterRemove(t *testing.T) {
 		t.Error("getting removed trader should return error")
 	}
 }
+
+// TestLockTrader tests that lifecycle operations on one trader wait for each other
+func TestLockTrader(t *testing.T) {
+	tm := NewTraderManager()
+
+	unlock := tm.LockTrader("t1")
+	tm.LockTrader("t2")() // Other traders aren't blocked
+
+	acquired := make(chan struct{})
+	go func() {
+		defer tm.LockTrader("t1")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock of the same trader acquired while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second lock not acquired after unlock")
+	}
+}
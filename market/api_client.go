@@ -158,3 +158,27 @@ func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
 
 	return price, nil
 }
+
+// GetTickers24hr gets 24h ticker statistics for all symbols
+func (c *APIClient) GetTickers24hr() ([]Ticker24hr, error) {
+	url := fmt.Sprintf("%s/fapi/v1/ticker/24hr", baseURL)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ticker request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var tickers []Ticker24hr
+	if err := json.Unmarshal(body, &tickers); err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
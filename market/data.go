@@ -30,40 +30,45 @@ var (
 
 // Note: Kline data now uses free/open API (coinank_api.Kline) which doesn't require authentication
 
+// coinankIntervals kline intervals supported by the CoinAnk kline API
+var coinankIntervals = map[string]coinank_enum.Interval{
+	"1m":  coinank_enum.Minute1,
+	"3m":  coinank_enum.Minute3,
+	"5m":  coinank_enum.Minute5,
+	"15m": coinank_enum.Minute15,
+	"30m": coinank_enum.Minute30,
+	"1h":  coinank_enum.Hour1,
+	"2h":  coinank_enum.Hour2,
+	"4h":  coinank_enum.Hour4,
+	"6h":  coinank_enum.Hour6,
+	"8h":  coinank_enum.Hour8,
+	"12h": coinank_enum.Hour12,
+	"1d":  coinank_enum.Day1,
+	"3d":  coinank_enum.Day3,
+	"1w":  coinank_enum.Week1,
+}
+
+// klineIntervalOrder supported kline intervals from shortest to longest
+var klineIntervalOrder = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
+
+// SupportedKlineIntervals returns the kline intervals that can be fetched, shortest first
+func SupportedKlineIntervals() []string {
+	return append([]string(nil), klineIntervalOrder...)
+}
+
+// ValidateKlineInterval checks that a kline interval is supported by the kline data source
+func ValidateKlineInterval(interval string) error {
+	if _, ok := coinankIntervals[interval]; !ok {
+		return fmt.Errorf("unsupported kline interval %q (supported: %s)", interval, strings.Join(klineIntervalOrder, ", "))
+	}
+	return nil
+}
+
 // getKlinesFromCoinAnk fetches kline data from CoinAnk API (replacement for WSMonitorCli)
 func getKlinesFromCoinAnk(symbol, interval string, limit int) ([]Kline, error) {
 	// Map interval string to coinank enum
-	var coinankInterval coinank_enum.Interval
-	switch interval {
-	case "1m":
-		coinankInterval = coinank_enum.Minute1
-	case "3m":
-		coinankInterval = coinank_enum.Minute3
-	case "5m":
-		coinankInterval = coinank_enum.Minute5
-	case "15m":
-		coinankInterval = coinank_enum.Minute15
-	case "30m":
-		coinankInterval = coinank_enum.Minute30
-	case "1h":
-		coinankInterval = coinank_enum.Hour1
-	case "2h":
-		coinankInterval = coinank_enum.Hour2
-	case "4h":
-		coinankInterval = coinank_enum.Hour4
-	case "6h":
-		coinankInterval = coinank_enum.Hour6
-	case "8h":
-		coinankInterval = coinank_enum.Hour8
-	case "12h":
-		coinankInterval = coinank_enum.Hour12
-	case "1d":
-		coinankInterval = coinank_enum.Day1
-	case "3d":
-		coinankInterval = coinank_enum.Day3
-	case "1w":
-		coinankInterval = coinank_enum.Week1
-	default:
+	coinankInterval, ok := coinankIntervals[interval]
+	if !ok {
 		return nil, fmt.Errorf("unsupported interval: %s", interval)
 	}
 
@@ -93,6 +98,30 @@ func getKlinesFromCoinAnk(symbol, interval string, limit int) ([]Kline, error) {
 	return klines, nil
 }
 
+// GetATR fetches recent klines for symbol and returns ATR(period) on that timeframe with the last close
+func GetATR(symbol, timeframe string, period int) (atr float64, lastClose float64, err error) {
+	limit := period * 4
+	if limit < 100 {
+		limit = 100
+	}
+
+	var klines []Kline
+	if IsXyzDexAsset(symbol) {
+		klines, err = getKlinesFromHyperliquid(symbol, timeframe, limit)
+	} else {
+		klines, err = getKlinesFromCoinAnk(symbol, timeframe, limit)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	atr = calculateATR(klines, period)
+	if atr <= 0 {
+		return 0, 0, fmt.Errorf("not enough %s klines for %s ATR%d (got %d)", timeframe, symbol, period, len(klines))
+	}
+	return atr, klines[len(klines)-1].Close, nil
+}
+
 // getKlinesFromHyperliquid fetches kline data from Hyperliquid API for xyz dex assets
 func getKlinesFromHyperliquid(symbol, interval string, limit int) ([]Kline, error) {
 	// Remove xyz: prefix if present for the API call
@@ -1036,7 +1065,7 @@ func IsXyzDexAsset(symbol string) bool {
 	base := strings.ToUpper(symbol)
 	// Remove any prefix/suffix
 	base = strings.TrimPrefix(base, "XYZ:")
-	for _, suffix := range []string{"USDT", "USD", "-USDC"} {
+	for _, suffix := range []string{"USDT", "USD", "-USDC", "USDC"} {
 		if strings.HasSuffix(base, suffix) {
 			base = strings.TrimSuffix(base, suffix)
 			break
@@ -1045,11 +1074,53 @@ func IsXyzDexAsset(symbol string) bool {
 	return xyzDexAssets[base]
 }
 
+// Supported stablecoin quote assets for crypto perps
+const (
+	QuoteUSDT = "USDT"
+	QuoteUSDC = "USDC"
+)
+
+// SupportedQuoteAssets lists quote assets accepted in trading symbols
+var SupportedQuoteAssets = []string{QuoteUSDT, QuoteUSDC}
+
+// IsSupportedQuoteAsset checks whether quote is a supported stablecoin quote asset
+func IsSupportedQuoteAsset(quote string) bool {
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	for _, q := range SupportedQuoteAssets {
+		if q == quote {
+			return true
+		}
+	}
+	return false
+}
+
+// QuoteAsset returns the stablecoin quote asset of symbol (e.g. "BTCUSDC" -> "USDC"),
+// or empty string if symbol has no supported quote suffix
+func QuoteAsset(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	for _, q := range SupportedQuoteAssets {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return q
+		}
+	}
+	return ""
+}
+
 // Normalize normalizes symbol
-// For crypto: ensures it's a USDT trading pair
+// For crypto: keeps an existing USDT/USDC quote, otherwise ensures it's a USDT trading pair
 // For xyz dex assets (stocks, forex, commodities): uses xyz: prefix without USDT suffix
 func Normalize(symbol string) string {
+	return NormalizeWithQuote(symbol, QuoteUSDT)
+}
+
+// NormalizeWithQuote normalizes symbol like Normalize, appending quote (USDT/USDC)
+// to crypto symbols that don't already have a supported quote suffix
+func NormalizeWithQuote(symbol, quote string) string {
 	symbol = strings.ToUpper(symbol)
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if !IsSupportedQuoteAsset(quote) {
+		quote = QuoteUSDT
+	}
 
 	// Check if this is an xyz dex asset
 	if IsXyzDexAsset(symbol) {
@@ -1059,7 +1130,7 @@ func Normalize(symbol string) string {
 		if strings.HasPrefix(strings.ToLower(base), "xyz:") {
 			base = base[4:] // Remove first 4 characters ("xyz:")
 		}
-		for _, suffix := range []string{"USDT", "USD", "-USDC"} {
+		for _, suffix := range []string{"USDT", "USD", "-USDC", "USDC"} {
 			if strings.HasSuffix(base, suffix) {
 				base = strings.TrimSuffix(base, suffix)
 				break
@@ -1069,10 +1140,98 @@ func Normalize(symbol string) string {
 	}
 
 	// For regular crypto assets
-	if strings.HasSuffix(symbol, "USDT") {
+	if QuoteAsset(symbol) != "" {
+		return symbol
+	}
+	return symbol + quote
+}
+
+// WithQuote normalizes symbol and replaces its stablecoin quote with quote (e.g. "BTCUSDT" -> "BTCUSDC"),
+// so coin lists of USDT-quoted data providers can be traded on a USDC-quoted trader.
+// xyz dex assets and unsupported quotes leave the normalized symbol unchanged
+func WithQuote(symbol, quote string) string {
+	normalized := NormalizeWithQuote(symbol, quote)
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if IsXyzDexAsset(normalized) || !IsSupportedQuoteAsset(quote) {
+		return normalized
+	}
+	return strings.TrimSuffix(normalized, QuoteAsset(normalized)) + quote
+}
+
+// Asset classes of the symbols an exchange / data provider trades
+const (
+	AssetClassCrypto = "crypto" // USDT/USDC perps (xyz dex assets keep their xyz: prefix)
+	AssetClassStock  = "stock"  // Plain tickers (AAPL)
+	AssetClassForex  = "forex"  // BASE/QUOTE pairs (EUR/USD)
+	AssetClassMetal  = "metal"  // BASE/QUOTE pairs (XAU/USD)
+)
+
+// metalAliases maps commodity names to their ISO 4217 codes
+var metalAliases = map[string]string{
+	"GOLD":   "XAU",
+	"SILVER": "XAG",
+}
+
+// AssetClassForExchange returns the asset class traded on an exchange / data provider type
+func AssetClassForExchange(exchange string) string {
+	switch strings.ToLower(strings.TrimSpace(exchange)) {
+	case "alpaca":
+		return AssetClassStock
+	case "forex", "twelvedata":
+		return AssetClassForex
+	case "metals":
+		return AssetClassMetal
+	default:
+		return AssetClassCrypto
+	}
+}
+
+// NormalizeForExchange normalizes symbol for the asset class of exchange:
+// crypto gets a USDT/USDC quote like NormalizeWithQuote, stocks stay plain tickers and
+// forex/metals become BASE/QUOTE pairs, so non-crypto symbols are never suffixed with USDT
+func NormalizeForExchange(symbol, exchange, quote string) string {
+	switch AssetClassForExchange(exchange) {
+	case AssetClassStock:
+		return stripCryptoQuote(symbol)
+	case AssetClassForex, AssetClassMetal:
+		return normalizeFXPair(symbol)
+	default:
+		return NormalizeWithQuote(symbol, quote)
+	}
+}
+
+// stripCryptoQuote upper-cases symbol and removes an xyz: prefix or stablecoin quote added by crypto normalization
+func stripCryptoQuote(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	symbol = strings.TrimPrefix(symbol, "XYZ:")
+	if q := QuoteAsset(symbol); q != "" {
+		symbol = strings.TrimSuffix(symbol, q)
+	}
+	return symbol
+}
+
+// normalizeFXPair converts forex/metal symbols to BASE/QUOTE form
+// Examples: "eurusd" -> "EUR/USD", "XAU" -> "XAU/USD", "GOLDUSD" -> "XAU/USD", "USD/JPY" -> "USD/JPY"
+func normalizeFXPair(symbol string) string {
+	symbol = stripCryptoQuote(symbol)
+	if strings.Contains(symbol, "/") {
+		return symbol
+	}
+	symbol = strings.NewReplacer("-", "", "_", "").Replace(symbol)
+	for name, code := range metalAliases {
+		if strings.HasPrefix(symbol, name) {
+			symbol = code + strings.TrimPrefix(symbol, name)
+			break
+		}
+	}
+	switch len(symbol) {
+	case 3:
+		return symbol + "/USD"
+	case 6:
+		return symbol[:3] + "/" + symbol[3:]
+	default:
 		return symbol
 	}
-	return symbol + "USDT"
 }
 
 // parseFloat parses float value
@@ -500,3 +500,106 @@ func TestIsStaleData_EmptyKlines(t *testing.T) {
 		t.Error("Expected false for empty klines, got true")
 	}
 }
+
+// TestNormalize_QuoteAssets tests USDT/USDC quote handling in symbol normalization
+func TestNormalize_QuoteAssets(t *testing.T) {
+	tests := []struct {
+		symbol string
+		quote  string
+		want   string
+	}{
+		{"btc", "", "BTCUSDT"},
+		{"BTCUSDT", "", "BTCUSDT"},
+		{"ETHUSDC", "", "ETHUSDC"},
+		{"sol", "USDC", "SOLUSDC"},
+		{"SOLUSDT", "USDC", "SOLUSDT"},
+		{"doge", "BUSD", "DOGEUSDT"}, // Unsupported quote falls back to USDT
+	}
+
+	for _, tt := range tests {
+		got := NormalizeWithQuote(tt.symbol, tt.quote)
+		if tt.quote == "" {
+			got = Normalize(tt.symbol)
+		}
+		if got != tt.want {
+			t.Errorf("normalize(%q, %q) = %q, want %q", tt.symbol, tt.quote, got, tt.want)
+		}
+	}
+}
+
+// TestNormalizeForExchange tests asset-class aware normalization
+func TestNormalizeForExchange(t *testing.T) {
+	tests := []struct {
+		symbol   string
+		exchange string
+		quote    string
+		want     string
+	}{
+		{"btc", "binance", "", "BTCUSDT"},
+		{"sol", "hyperliquid", "USDC", "SOLUSDC"},
+		{"TSLA", "hyperliquid", "", "xyz:TSLA"},
+		{"aapl", "alpaca", "", "AAPL"},
+		{"TSLAUSDT", "alpaca", "", "TSLA"},
+		{"eurusd", "forex", "", "EUR/USD"},
+		{"USD/JPY", "forex", "", "USD/JPY"},
+		{"EURUSDT", "forex", "", "EUR/USD"},
+		{"XAU", "metals", "", "XAU/USD"},
+		{"GOLD", "metals", "", "XAU/USD"},
+		{"silverusd", "metals", "", "XAG/USD"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeForExchange(tt.symbol, tt.exchange, tt.quote); got != tt.want {
+			t.Errorf("NormalizeForExchange(%q, %q, %q) = %q, want %q", tt.symbol, tt.exchange, tt.quote, got, tt.want)
+		}
+	}
+}
+
+// TestQuoteAsset tests quote asset detection
+func TestQuoteAsset(t *testing.T) {
+	cases := map[string]string{
+		"BTCUSDT": "USDT",
+		"btcusdc": "USDC",
+		"BTC":     "",
+		"USDC":    "",
+	}
+	for symbol, want := range cases {
+		if got := QuoteAsset(symbol); got != want {
+			t.Errorf("QuoteAsset(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestWithQuote(t *testing.T) {
+	cases := []struct {
+		symbol, quote, want string
+	}{
+		{"BTCUSDT", "USDC", "BTCUSDC"},
+		{"eth", "USDC", "ETHUSDC"},
+		{"SOLUSDC", "USDT", "SOLUSDT"},
+		{"BTCUSDT", "", "BTCUSDT"},
+		{"xyz:TSLA", "USDC", "xyz:TSLA"},
+	}
+	for _, c := range cases {
+		if got := WithQuote(c.symbol, c.quote); got != c.want {
+			t.Errorf("WithQuote(%q, %q) = %q, want %q", c.symbol, c.quote, got, c.want)
+		}
+	}
+}
+
+// TestValidateKlineInterval tests analysis interval validation against CoinAnk intervals
+func TestValidateKlineInterval(t *testing.T) {
+	for _, interval := range []string{"1m", "5m", "15m", "1h", "4h", "1d"} {
+		if err := ValidateKlineInterval(interval); err != nil {
+			t.Errorf("ValidateKlineInterval(%q) unexpected error: %v", interval, err)
+		}
+	}
+	for _, interval := range []string{"", "2m", "10m", "1H", "1M"} {
+		if err := ValidateKlineInterval(interval); err == nil {
+			t.Errorf("ValidateKlineInterval(%q) expected error", interval)
+		}
+	}
+	if got := SupportedKlineIntervals(); len(got) != len(coinankIntervals) || got[0] != "1m" {
+		t.Errorf("SupportedKlineIntervals() = %v", got)
+	}
+}
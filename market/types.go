@@ -118,6 +118,8 @@ type Ticker24hr struct {
 	PriceChangePercent string `json:"priceChangePercent"`
 	Volume             string `json:"volume"`
 	QuoteVolume        string `json:"quoteVolume"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
 }
 
 // SymbolFeatures feature data structure
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Global limit on concurrent AI decision calls, shared by all traders so a fleet firing
+// cycles at the same time queues up instead of hitting provider-side 429s on shared keys.
+var (
+	callSlotsMu  sync.RWMutex
+	callSlots    chan struct{} // nil = unlimited
+	callsWaiting atomic.Int64
+)
+
+// SetMaxConcurrentCalls sets the max number of concurrent AI calls (0 or less = unlimited).
+// Calls already holding a slot keep it; the new limit applies to calls acquiring after this.
+func SetMaxConcurrentCalls(n int) {
+	callSlotsMu.Lock()
+	defer callSlotsMu.Unlock()
+	if n <= 0 {
+		callSlots = nil
+		return
+	}
+	callSlots = make(chan struct{}, n)
+}
+
+// CallsWaiting returns the number of AI calls currently queued for a slot
+func CallsWaiting() int {
+	return int(callsWaiting.Load())
+}
+
+// AcquireCallSlot blocks until an AI call slot is free.
+// Returns the release func (must be called when the call completes) and how long the caller queued.
+func AcquireCallSlot() (release func(), waited time.Duration) {
+	callSlotsMu.RLock()
+	slots := callSlots
+	callSlotsMu.RUnlock()
+	if slots == nil {
+		return func() {}, 0
+	}
+
+	release = func() { <-slots }
+	select {
+	case slots <- struct{}{}:
+		return release, 0
+	default:
+	}
+
+	start := time.Now()
+	callsWaiting.Add(1)
+	slots <- struct{}{}
+	callsWaiting.Add(-1)
+	return release, time.Since(start)
+}
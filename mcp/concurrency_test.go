@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcquireCallSlot_Limit(t *testing.T) {
+	SetMaxConcurrentCalls(2)
+	defer SetMaxConcurrentCalls(0)
+
+	var inFlight, maxInFlight atomic.Int32
+	var queued atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, waited := AcquireCallSlot()
+			if waited > 0 {
+				queued.Add(1)
+			}
+			n := inFlight.Add(1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			inFlight.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max concurrent calls = %d, want <= 2", got)
+	}
+	if queued.Load() == 0 {
+		t.Error("expected some calls to queue")
+	}
+	if CallsWaiting() != 0 {
+		t.Errorf("CallsWaiting() = %d after all calls finished", CallsWaiting())
+	}
+}
+
+func TestAcquireCallSlot_Unlimited(t *testing.T) {
+	SetMaxConcurrentCalls(0)
+	for i := 0; i < 100; i++ {
+		if _, waited := AcquireCallSlot(); waited != 0 {
+			t.Fatalf("unlimited slot should not wait, waited %v", waited)
+		}
+	}
+}
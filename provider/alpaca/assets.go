@@ -0,0 +1,60 @@
+package alpaca
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// TradingAPIURL Alpaca trading API (asset list); the paper endpoint serves the same assets without a funded account
+const TradingAPIURL = "https://paper-api.alpaca.markets/v2"
+
+// Asset a tradable Alpaca asset
+type Asset struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+	Class    string `json:"class"`
+	Tradable bool   `json:"tradable"`
+}
+
+// ListAssets fetches the active US equities
+func (c *Client) ListAssets(ctx context.Context) ([]Asset, error) {
+	if c.apiKey == "" || c.secretKey == "" {
+		return nil, fmt.Errorf("alpaca API keys not configured")
+	}
+
+	params := url.Values{}
+	params.Set("status", "active")
+	params.Set("asset_class", "us_equity")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", TradingAPIURL+"/assets?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("APCA-API-KEY-ID", c.apiKey)
+	req.Header.Set("APCA-API-SECRET-KEY", c.secretKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alpaca API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var assets []Asset
+	if err := json.Unmarshal(body, &assets); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return assets, nil
+}
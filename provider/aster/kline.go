@@ -0,0 +1,130 @@
+package aster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BaseURL = "https://fapi.asterdex.com"
+)
+
+// Candle represents a single OHLCV candle from Aster futures
+type Candle struct {
+	OpenTime    int64   // Open time in milliseconds
+	CloseTime   int64   // Close time in milliseconds
+	Open        float64 // Open price
+	High        float64 // High price
+	Low         float64 // Low price
+	Close       float64 // Close price
+	Volume      float64 // Volume in base asset
+	QuoteVolume float64 // Volume in quote asset
+	Trades      int     // Number of trades
+}
+
+// Client is the Aster public market data client (Binance-compatible API)
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a new Aster public API client
+func NewClient() *Client {
+	return &Client{
+		baseURL: BaseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCandles fetches futures klines for a symbol
+// symbol: "BTCUSDT"
+// interval: "1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w", "1M"
+// limit: number of candles (max 1500)
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, limit int) ([]Candle, error) {
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol))
+	params.Set("interval", interval)
+	params.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/fapi/v1/klines?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aster API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// [openTime, open, high, low, close, volume, closeTime, quoteVolume, trades, ...]
+	var raw [][]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(body))
+	}
+
+	candles := make([]Candle, 0, len(raw))
+	for _, r := range raw {
+		candle, err := parseCandle(r)
+		if err != nil {
+			continue
+		}
+		candles = append(candles, candle)
+	}
+
+	return candles, nil
+}
+
+func parseCandle(r []interface{}) (Candle, error) {
+	var candle Candle
+	if len(r) < 9 {
+		return candle, fmt.Errorf("invalid kline data")
+	}
+
+	openTime, ok1 := r[0].(float64)
+	closeTime, ok2 := r[6].(float64)
+	if !ok1 || !ok2 {
+		return candle, fmt.Errorf("invalid kline timestamps")
+	}
+	candle.OpenTime = int64(openTime)
+	candle.CloseTime = int64(closeTime)
+	candle.Open = parseFloat(r[1])
+	candle.High = parseFloat(r[2])
+	candle.Low = parseFloat(r[3])
+	candle.Close = parseFloat(r[4])
+	candle.Volume = parseFloat(r[5])
+	candle.QuoteVolume = parseFloat(r[7])
+	if trades, ok := r[8].(float64); ok {
+		candle.Trades = int(trades)
+	}
+	return candle, nil
+}
+
+func parseFloat(v interface{}) float64 {
+	switch val := v.(type) {
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	case float64:
+		return val
+	}
+	return 0
+}
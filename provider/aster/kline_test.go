@@ -0,0 +1,29 @@
+package aster
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseCandle(t *testing.T) {
+	var raw [][]interface{}
+	data := `[[1700000000000,"100.5","101","99.5","100.8","12.5",1700000299999,"1260.0",42,"6","600","0"]]`
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	candle, err := parseCandle(raw[0])
+	if err != nil {
+		t.Fatalf("parseCandle() error = %v", err)
+	}
+	if candle.OpenTime != 1700000000000 || candle.CloseTime != 1700000299999 {
+		t.Errorf("unexpected times: %+v", candle)
+	}
+	if candle.Open != 100.5 || candle.Close != 100.8 || candle.Volume != 12.5 || candle.QuoteVolume != 1260 || candle.Trades != 42 {
+		t.Errorf("unexpected values: %+v", candle)
+	}
+
+	if _, err := parseCandle([]interface{}{1.0, "1"}); err == nil {
+		t.Error("expected error for short kline")
+	}
+}
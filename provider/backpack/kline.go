@@ -0,0 +1,224 @@
+package backpack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BaseURL = "https://api.backpack.exchange"
+	// MaxCandles maximum candles requested at once
+	MaxCandles = 1000
+)
+
+// Candle represents a single OHLCV candle from Backpack perpetuals
+type Candle struct {
+	OpenTime    int64   // Open time in milliseconds
+	CloseTime   int64   // Close time in milliseconds
+	Open        float64 // Open price
+	High        float64 // High price
+	Low         float64 // Low price
+	Close       float64 // Close price
+	Volume      float64 // Volume in base asset
+	QuoteVolume float64 // Volume in quote asset
+}
+
+// rawCandle Backpack kline response item (prices and volumes are decimal strings)
+type rawCandle struct {
+	Start       string `json:"start"` // "2006-01-02 15:04:05" UTC open time
+	Open        string `json:"open"`
+	High        string `json:"high"`
+	Low         string `json:"low"`
+	Close       string `json:"close"`
+	Volume      string `json:"volume"`
+	QuoteVolume string `json:"quoteVolume"`
+}
+
+// Client is the Backpack public market data client
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a new Backpack public API client
+func NewClient() *Client {
+	return &Client{
+		baseURL: BaseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCandles fetches perpetual candles for a symbol
+// symbol: "BTCUSDT" or "BTC_USDC_PERP"
+// interval: Backpack interval (use MapTimeframe)
+// limit: number of candles (max 1000)
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, limit int) ([]Candle, error) {
+	if limit <= 0 || limit > MaxCandles {
+		limit = MaxCandles
+	}
+	duration := getIntervalDuration(interval)
+
+	params := url.Values{}
+	params.Set("symbol", FormatMarket(symbol))
+	params.Set("interval", interval)
+	params.Set("startTime", strconv.FormatInt(time.Now().Add(-time.Duration(limit)*duration).Unix(), 10))
+
+	body, err := c.get(ctx, "/api/v1/klines?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var raws []rawCandle
+	if err := json.Unmarshal(body, &raws); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(body))
+	}
+
+	candles := make([]Candle, 0, len(raws))
+	for _, r := range raws {
+		candle, err := parseCandle(r, duration)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime < candles[j].OpenTime })
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// parseCandle converts a raw kline into a Candle
+func parseCandle(r rawCandle, interval time.Duration) (Candle, error) {
+	start, err := time.ParseInLocation("2006-01-02 15:04:05", r.Start, time.UTC)
+	if err != nil {
+		return Candle{}, fmt.Errorf("invalid candle start %q: %w", r.Start, err)
+	}
+
+	values := make([]float64, 6)
+	for i, s := range []string{r.Open, r.High, r.Low, r.Close, r.Volume, r.QuoteVolume} {
+		if s == "" {
+			continue // Empty volume on candles without trades
+		}
+		if values[i], err = strconv.ParseFloat(s, 64); err != nil {
+			return Candle{}, fmt.Errorf("invalid candle value %q: %w", s, err)
+		}
+	}
+
+	openTime := start.UnixMilli()
+	return Candle{
+		OpenTime:    openTime,
+		CloseTime:   openTime + interval.Milliseconds() - 1,
+		Open:        values[0],
+		High:        values[1],
+		Low:         values[2],
+		Close:       values[3],
+		Volume:      values[4],
+		QuoteVolume: values[5],
+	}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("backpack API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// FormatMarket converts a symbol to a Backpack USDC perpetual market
+// Examples:
+//   - "BTCUSDT" -> "BTC_USDC_PERP"
+//   - "ethusdc" -> "ETH_USDC_PERP"
+//   - "SOL_USDC_PERP" -> "SOL_USDC_PERP"
+//   - "sol" -> "SOL_USDC_PERP"
+func FormatMarket(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.HasSuffix(symbol, "_PERP") {
+		return symbol
+	}
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			symbol = strings.TrimSuffix(symbol, quote)
+			break
+		}
+	}
+	return symbol + "_USDC_PERP"
+}
+
+// MapTimeframe maps common timeframe strings to Backpack intervals
+func MapTimeframe(interval string) string {
+	switch interval {
+	case "1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w":
+		return interval
+	case "1M":
+		return "1month"
+	default:
+		return "5m" // Default to 5 minutes
+	}
+}
+
+// getIntervalDuration returns the duration for a given Backpack interval
+func getIntervalDuration(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "3m":
+		return 3 * time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "1h":
+		return time.Hour
+	case "2h":
+		return 2 * time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "6h":
+		return 6 * time.Hour
+	case "8h":
+		return 8 * time.Hour
+	case "12h":
+		return 12 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	case "3d":
+		return 3 * 24 * time.Hour
+	case "1w":
+		return 7 * 24 * time.Hour
+	case "1month":
+		return 30 * 24 * time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
@@ -0,0 +1,44 @@
+package backpack
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseCandle(t *testing.T) {
+	var raw rawCandle
+	data := `{"start":"2024-01-01 00:00:00","open":"100.5","high":"101","low":"99.5","close":"100","volume":"12.5","quoteVolume":"1251.2","trades":"7"}`
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	candle, err := parseCandle(raw, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("parseCandle() error = %v", err)
+	}
+	if candle.OpenTime != 1704067200000 || candle.CloseTime != 1704067499999 {
+		t.Errorf("unexpected times: %+v", candle)
+	}
+	if candle.Open != 100.5 || candle.High != 101 || candle.Close != 100 || candle.Volume != 12.5 || candle.QuoteVolume != 1251.2 {
+		t.Errorf("unexpected values: %+v", candle)
+	}
+
+	if _, err := parseCandle(rawCandle{Start: "yesterday"}, time.Minute); err == nil {
+		t.Error("expected error for invalid start time")
+	}
+}
+
+func TestFormatMarket(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":       "BTC_USDC_PERP",
+		"ethusdc":       "ETH_USDC_PERP",
+		"SOL_USDC_PERP": "SOL_USDC_PERP",
+		"SOL":           "SOL_USDC_PERP",
+	}
+	for input, want := range tests {
+		if got := FormatMarket(input); got != want {
+			t.Errorf("FormatMarket(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BaseURL = "https://api.international.coinbase.com"
+	// MaxCandles maximum candles returned per request
+	MaxCandles = 300
+)
+
+// Candle represents a single OHLCV candle from Coinbase International perpetuals
+type Candle struct {
+	OpenTime    int64   // Open time in milliseconds
+	CloseTime   int64   // Close time in milliseconds
+	Open        float64 // Open price
+	High        float64 // High price
+	Low         float64 // Low price
+	Close       float64 // Close price
+	Volume      float64 // Volume in base asset
+	QuoteVolume float64 // Volume in quote asset (approximated as volume × close)
+}
+
+// rawCandle Coinbase International candle response item
+type rawCandle struct {
+	Start  string      `json:"start"` // RFC3339 open time
+	Open   json.Number `json:"open"`
+	High   json.Number `json:"high"`
+	Low    json.Number `json:"low"`
+	Close  json.Number `json:"close"`
+	Volume json.Number `json:"volume"`
+}
+
+// Client is the Coinbase International public market data client
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a new Coinbase International public API client
+func NewClient() *Client {
+	return &Client{
+		baseURL: BaseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCandles fetches perpetual candles for a symbol
+// symbol: "BTCUSDT" or "BTC-PERP"
+// interval: Coinbase granularity (use MapTimeframe)
+// limit: number of candles (max 300)
+func (c *Client) GetCandles(ctx context.Context, symbol, granularity string, limit int) ([]Candle, error) {
+	if limit <= 0 || limit > MaxCandles {
+		limit = MaxCandles
+	}
+	interval := getGranularityDuration(granularity)
+
+	params := url.Values{}
+	params.Set("granularity", granularity)
+	params.Set("start", time.Now().UTC().Add(-time.Duration(limit)*interval).Format(time.RFC3339))
+
+	path := fmt.Sprintf("/api/v1/instruments/%s/candles?%s", url.PathEscape(FormatInstrument(symbol)), params.Encode())
+	body, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Aggregations []rawCandle `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(body))
+	}
+
+	candles := make([]Candle, 0, len(resp.Aggregations))
+	for _, r := range resp.Aggregations {
+		candle, err := parseCandle(r, interval)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+
+	// Newest first on the wire, callers expect oldest first
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime < candles[j].OpenTime })
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// parseCandle converts a raw aggregation into a Candle
+func parseCandle(r rawCandle, interval time.Duration) (Candle, error) {
+	start, err := time.Parse(time.RFC3339, r.Start)
+	if err != nil {
+		return Candle{}, fmt.Errorf("invalid candle start %q: %w", r.Start, err)
+	}
+
+	values := make([]float64, 5)
+	for i, n := range []json.Number{r.Open, r.High, r.Low, r.Close, r.Volume} {
+		if values[i], err = strconv.ParseFloat(n.String(), 64); err != nil {
+			return Candle{}, fmt.Errorf("invalid candle value %q: %w", n, err)
+		}
+	}
+
+	openTime := start.UnixMilli()
+	return Candle{
+		OpenTime:    openTime,
+		CloseTime:   openTime + interval.Milliseconds() - 1,
+		Open:        values[0],
+		High:        values[1],
+		Low:         values[2],
+		Close:       values[3],
+		Volume:      values[4],
+		QuoteVolume: values[4] * values[3],
+	}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coinbase API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// FormatInstrument converts a symbol to a Coinbase International perpetual instrument
+// Examples:
+//   - "BTCUSDT" -> "BTC-PERP"
+//   - "ETHUSDC" -> "ETH-PERP"
+//   - "BTC-PERP" -> "BTC-PERP"
+//   - "sol" -> "SOL-PERP"
+func FormatInstrument(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.HasSuffix(symbol, "-PERP") {
+		return symbol
+	}
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			symbol = strings.TrimSuffix(symbol, quote)
+			break
+		}
+	}
+	return symbol + "-PERP"
+}
+
+// MapTimeframe maps common timeframe strings to Coinbase granularity
+func MapTimeframe(interval string) string {
+	switch interval {
+	case "1m":
+		return "ONE_MINUTE"
+	case "3m", "5m":
+		return "FIVE_MINUTE" // Coinbase doesn't have 3m, use 5m
+	case "15m":
+		return "FIFTEEN_MINUTE"
+	case "30m":
+		return "THIRTY_MINUTE"
+	case "1h":
+		return "ONE_HOUR"
+	case "2h", "4h":
+		return "TWO_HOUR" // Coinbase doesn't have 4h, use 2h
+	case "6h", "8h", "12h":
+		return "SIX_HOUR" // Coinbase doesn't have 8h/12h, use 6h
+	case "1d", "3d", "1w", "1M":
+		return "ONE_DAY"
+	default:
+		return "FIVE_MINUTE" // Default to 5 minutes
+	}
+}
+
+// getGranularityDuration returns the duration for a given Coinbase granularity
+func getGranularityDuration(granularity string) time.Duration {
+	switch granularity {
+	case "ONE_MINUTE":
+		return time.Minute
+	case "FIVE_MINUTE":
+		return 5 * time.Minute
+	case "FIFTEEN_MINUTE":
+		return 15 * time.Minute
+	case "THIRTY_MINUTE":
+		return 30 * time.Minute
+	case "ONE_HOUR":
+		return time.Hour
+	case "TWO_HOUR":
+		return 2 * time.Hour
+	case "SIX_HOUR":
+		return 6 * time.Hour
+	case "ONE_DAY":
+		return 24 * time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
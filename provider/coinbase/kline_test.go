@@ -0,0 +1,44 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseCandle(t *testing.T) {
+	var raw rawCandle
+	data := `{"start":"2024-01-01T00:00:00Z","open":"100.5","high":101,"low":"99.5","close":"100","volume":"12.5"}`
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	candle, err := parseCandle(raw, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("parseCandle() error = %v", err)
+	}
+	if candle.OpenTime != 1704067200000 || candle.CloseTime != 1704067499999 {
+		t.Errorf("unexpected times: %+v", candle)
+	}
+	if candle.Open != 100.5 || candle.High != 101 || candle.Close != 100 || candle.Volume != 12.5 || candle.QuoteVolume != 1250 {
+		t.Errorf("unexpected values: %+v", candle)
+	}
+
+	if _, err := parseCandle(rawCandle{Start: "yesterday"}, time.Minute); err == nil {
+		t.Error("expected error for invalid start time")
+	}
+}
+
+func TestFormatInstrument(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":  "BTC-PERP",
+		"ethusdc":  "ETH-PERP",
+		"BTC-PERP": "BTC-PERP",
+		"SOL":      "SOL-PERP",
+	}
+	for input, want := range tests {
+		if got := FormatInstrument(input); got != want {
+			t.Errorf("FormatInstrument(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
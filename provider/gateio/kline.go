@@ -0,0 +1,263 @@
+package gateio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	BaseURL = "https://api.gateio.ws/api/v4"
+	// Settle currency for USDT-margined perpetual futures
+	SettleUSDT = "usdt"
+	// Settle currency for BTC-margined (inverse, _USD) perpetual futures
+	SettleBTC = "btc"
+)
+
+// Candle represents a single OHLCV candle from Gate.io futures
+type Candle struct {
+	OpenTime    int64   // Open time in milliseconds
+	CloseTime   int64   // Close time in milliseconds
+	Open        float64 // Open price
+	High        float64 // High price
+	Low         float64 // Low price
+	Close       float64 // Close price
+	Volume      float64 // Volume in base asset (contracts × quanto multiplier)
+	QuoteVolume float64 // Volume in quote asset
+}
+
+// rawCandle Gate.io futures candlestick response item
+type rawCandle struct {
+	T   int64   `json:"t"`   // Open time in seconds
+	V   float64 `json:"v"`   // Volume in contracts
+	C   string  `json:"c"`   // Close price
+	H   string  `json:"h"`   // High price
+	L   string  `json:"l"`   // Low price
+	O   string  `json:"o"`   // Open price
+	Sum string  `json:"sum"` // Volume in quote currency
+}
+
+// contractInfo Gate.io futures contract (subset)
+type contractInfo struct {
+	Name             string `json:"name"`
+	QuantoMultiplier string `json:"quanto_multiplier"`
+}
+
+// Client is the Gate.io public market data client, safe for concurrent use
+type Client struct {
+	baseURL     string
+	client      *http.Client
+	multipliers sync.Map // settle/contract -> float64
+}
+
+// NewClient creates a new Gate.io public API client
+func NewClient() *Client {
+	return &Client{
+		baseURL: BaseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCandles fetches futures candlesticks for a symbol
+// symbol: "BTCUSDT" or "BTC_USDT"
+// interval: Gate.io interval (use MapTimeframe)
+// limit: number of candles (max 2000)
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, limit int) ([]Candle, error) {
+	contract := FormatContract(symbol)
+	settle := SettleCurrency(contract)
+
+	params := url.Values{}
+	params.Set("contract", contract)
+	params.Set("interval", interval)
+	params.Set("limit", strconv.Itoa(limit))
+
+	body, err := c.get(ctx, fmt.Sprintf("/futures/%s/candlesticks?%s", settle, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []rawCandle
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w (body: %s)", err, string(body))
+	}
+
+	// Contract volume -> base volume; fall back to quote/close if multiplier unavailable
+	multiplier, err := c.getMultiplier(ctx, settle, contract)
+	if err != nil {
+		multiplier = 0
+	}
+
+	intervalMs := getIntervalDuration(interval).Milliseconds()
+	candles := make([]Candle, len(raw))
+	for i, r := range raw {
+		open, _ := strconv.ParseFloat(r.O, 64)
+		high, _ := strconv.ParseFloat(r.H, 64)
+		low, _ := strconv.ParseFloat(r.L, 64)
+		closePrice, _ := strconv.ParseFloat(r.C, 64)
+		quoteVolume, _ := strconv.ParseFloat(r.Sum, 64)
+
+		volume := r.V * multiplier
+		if multiplier == 0 && closePrice > 0 {
+			volume = quoteVolume / closePrice
+		}
+
+		openTime := r.T * 1000
+		candles[i] = Candle{
+			OpenTime:    openTime,
+			CloseTime:   openTime + intervalMs - 1,
+			Open:        open,
+			High:        high,
+			Low:         low,
+			Close:       closePrice,
+			Volume:      volume,
+			QuoteVolume: quoteVolume,
+		}
+	}
+
+	return candles, nil
+}
+
+// getMultiplier returns the contract's quanto multiplier (base asset per contract), cached
+func (c *Client) getMultiplier(ctx context.Context, settle, contract string) (float64, error) {
+	key := settle + "/" + contract
+	if v, ok := c.multipliers.Load(key); ok {
+		return v.(float64), nil
+	}
+
+	body, err := c.get(ctx, fmt.Sprintf("/futures/%s/contracts/%s", settle, url.PathEscape(contract)))
+	if err != nil {
+		return 0, err
+	}
+
+	var info contractInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return 0, fmt.Errorf("failed to parse contract info: %w", err)
+	}
+	multiplier, err := strconv.ParseFloat(info.QuantoMultiplier, 64)
+	if err != nil || multiplier <= 0 {
+		return 0, fmt.Errorf("invalid quanto multiplier for %s: %q", contract, info.QuantoMultiplier)
+	}
+
+	c.multipliers.Store(key, multiplier)
+	return multiplier, nil
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gate.io API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// FormatContract converts a symbol to Gate.io contract format
+// Examples:
+//   - "BTCUSDT" -> "BTC_USDT"
+//   - "BTC_USDT" -> "BTC_USDT"
+//   - "btcusdt" -> "BTC_USDT"
+func FormatContract(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.Contains(symbol, "_") {
+		return symbol
+	}
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote) + "_" + quote
+		}
+	}
+	return symbol + "_USDT"
+}
+
+// SettleCurrency returns the settle currency of a Gate.io contract: _USD contracts are
+// BTC-margined (inverse), the others settle in their quote (e.g. "BTC_USDT" -> "usdt")
+func SettleCurrency(contract string) string {
+	_, quote, found := strings.Cut(strings.ToUpper(contract), "_")
+	switch {
+	case !found || quote == "":
+		return SettleUSDT
+	case quote == "USD":
+		return SettleBTC
+	default:
+		return strings.ToLower(quote)
+	}
+}
+
+// MapTimeframe maps common timeframe strings to Gate.io format.
+// Intervals Gate.io lacks map to a neighbouring one; unknown intervals are an error.
+func MapTimeframe(interval string) (string, error) {
+	switch interval {
+	case "10s", "1m", "5m", "15m", "30m", "1h", "4h", "8h", "1d":
+		return interval, nil
+	case "3m":
+		return "5m", nil // Gate.io doesn't have 3m, use 5m
+	case "2h":
+		return "1h", nil // Gate.io doesn't have 2h, use 1h
+	case "6h":
+		return "4h", nil // Gate.io doesn't have 6h, use 4h
+	case "12h":
+		return "8h", nil // Gate.io doesn't have 12h, use 8h
+	case "3d":
+		return "1d", nil // Gate.io doesn't have 3d, use 1d
+	case "1w":
+		return "7d", nil
+	case "1M":
+		return "30d", nil
+	default:
+		return "", fmt.Errorf("unsupported interval for gate.io: %s", interval)
+	}
+}
+
+// getIntervalDuration returns the duration for a given Gate.io interval
+func getIntervalDuration(interval string) time.Duration {
+	switch interval {
+	case "10s":
+		return 10 * time.Second
+	case "1m":
+		return time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "1h":
+		return time.Hour
+	case "4h":
+		return 4 * time.Hour
+	case "8h":
+		return 8 * time.Hour
+	case "1d":
+		return 24 * time.Hour
+	case "7d":
+		return 7 * 24 * time.Hour
+	case "30d":
+		return 30 * 24 * time.Hour
+	default:
+		return 5 * time.Minute
+	}
+}
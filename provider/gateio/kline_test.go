@@ -0,0 +1,52 @@
+package gateio
+
+import "testing"
+
+func TestFormatContract(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":  "BTC_USDT",
+		"btcusdt":  "BTC_USDT",
+		"BTC_USDT": "BTC_USDT",
+		"ETHUSDC":  "ETH_USDC",
+		"SOL":      "SOL_USDT",
+	}
+	for input, want := range tests {
+		if got := FormatContract(input); got != want {
+			t.Errorf("FormatContract(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMapTimeframe(t *testing.T) {
+	tests := map[string]string{
+		"1m":  "1m",
+		"3m":  "5m",
+		"4h":  "4h",
+		"12h": "8h",
+		"1w":  "7d",
+	}
+	for input, want := range tests {
+		if got, err := MapTimeframe(input); err != nil || got != want {
+			t.Errorf("MapTimeframe(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"bad", "", "2m"} {
+		if got, err := MapTimeframe(input); err == nil {
+			t.Errorf("MapTimeframe(%q) = %q, want error", input, got)
+		}
+	}
+}
+
+func TestSettleCurrency(t *testing.T) {
+	tests := map[string]string{
+		"BTC_USDT": SettleUSDT,
+		"ETH_USDC": "usdc",
+		"BTC_USD":  SettleBTC,
+		"BTC":      SettleUSDT,
+	}
+	for contract, want := range tests {
+		if got := SettleCurrency(contract); got != want {
+			t.Errorf("SettleCurrency(%q) = %q, want %q", contract, got, want)
+		}
+	}
+}
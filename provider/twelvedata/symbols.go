@@ -0,0 +1,84 @@
+package twelvedata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ForexPair a forex pair from the /forex_pairs reference endpoint
+type ForexPair struct {
+	Symbol        string `json:"symbol"` // e.g. "EUR/USD"
+	CurrencyGroup string `json:"currency_group"`
+	CurrencyBase  string `json:"currency_base"`
+	CurrencyQuote string `json:"currency_quote"`
+}
+
+// Commodity a commodity from the /commodities reference endpoint
+type Commodity struct {
+	Symbol   string `json:"symbol"` // e.g. "XAU/USD"
+	Name     string `json:"name"`
+	Category string `json:"category"` // e.g. "Precious Metal"
+}
+
+// ListForexPairs fetches all forex pairs
+func (c *Client) ListForexPairs(ctx context.Context) ([]ForexPair, error) {
+	var pairs []ForexPair
+	if err := c.getReference(ctx, "forex_pairs", &pairs); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// ListCommodities fetches all commodities (metals, energy, agriculture)
+func (c *Client) ListCommodities(ctx context.Context) ([]Commodity, error) {
+	var commodities []Commodity
+	if err := c.getReference(ctx, "commodities", &commodities); err != nil {
+		return nil, err
+	}
+	return commodities, nil
+}
+
+// getReference fetches a reference data endpoint ({"data": [...], "status": "ok"}) into out
+func (c *Client) getReference(ctx context.Context, endpoint string, out interface{}) error {
+	if c.apiKey == "" {
+		return fmt.Errorf("twelve data API key not configured")
+	}
+
+	params := url.Values{}
+	params.Set("apikey", c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s?%s", BaseURL, endpoint, params.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		Data    json.RawMessage `json:"data"`
+		Status  string          `json:"status"`
+		Message string          `json:"message,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.Status == "error" {
+		return fmt.Errorf("twelve data API error: %s", result.Message)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIAuditStore API request audit log storage
+type APIAuditStore struct {
+	db *gorm.DB
+}
+
+// APIAuditLog one audited API request
+type APIAuditLog struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"column:user_id;default:'';index:idx_api_audit_user_time" json:"user_id"` // Empty for unauthenticated requests
+	Method    string    `gorm:"column:method;not null" json:"method"`
+	Path      string    `gorm:"column:path;not null" json:"path"` // Route pattern, e.g. /api/traders/:id/start
+	Status    int       `gorm:"column:status;not null" json:"status"`
+	LatencyMs int64     `gorm:"column:latency_ms;default:0" json:"latency_ms"`
+	ClientIP  string    `gorm:"column:client_ip;default:''" json:"client_ip"`
+	CreatedAt time.Time `gorm:"column:created_at;index:idx_api_audit_user_time,sort:desc;index:idx_api_audit_created" json:"created_at"`
+}
+
+func (APIAuditLog) TableName() string { return "api_audit_logs" }
+
+// NewAPIAuditStore creates a new APIAuditStore
+func NewAPIAuditStore(db *gorm.DB) *APIAuditStore {
+	return &APIAuditStore{db: db}
+}
+
+// SaveBatch inserts audit log entries
+func (s *APIAuditStore) SaveBatch(entries []*APIAuditLog) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.db.CreateInBatches(entries, 100).Error
+}
+
+// List returns the most recent audit log entries, optionally filtered by user and path
+func (s *APIAuditStore) List(userID, path string, limit int) ([]*APIAuditLog, error) {
+	var entries []*APIAuditLog
+	query := s.db.Model(&APIAuditLog{})
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if path != "" {
+		query = query.Where("path = ?", path)
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// DeleteBefore deletes audit log entries created before t, returns the number deleted
+func (s *APIAuditStore) DeleteBefore(t time.Time) (int64, error) {
+	result := s.db.Where("created_at < ?", t.UTC()).Delete(&APIAuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Balance adjustment sources
+const (
+	BalanceAdjustmentSync     = "sync"     // Estimated by balance sync from the unexplained equity change
+	BalanceAdjustmentManual   = "manual"   // Amount given by the user
+	BalanceAdjustmentTransfer = "transfer" // Imported from the exchange's deposit/withdrawal history
+	BalanceAdjustmentReset    = "reset"    // Balance sync moved initial_balance to equity, offsets earlier contributions
+)
+
+// BalanceAdjustmentStore deposits/withdrawals recorded per trader, kept apart from trading PnL
+type BalanceAdjustmentStore struct {
+	db *gorm.DB
+}
+
+// BalanceAdjustment a deposit (positive amount) or withdrawal (negative amount) on a trader's account.
+// PnL is equity - initial_balance - net contributions, so funding changes don't show up as profit or loss.
+type BalanceAdjustment struct {
+	ID         int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string  `gorm:"column:trader_id;not null;index:idx_balance_adjustment_trader" json:"trader_id"`
+	Amount     float64 `gorm:"column:amount;not null" json:"amount"`   // + deposit, - withdrawal
+	Equity     float64 `gorm:"column:equity;default:0" json:"equity"`  // Account equity when recorded
+	Source     string  `gorm:"column:source;default:''" json:"source"` // sync / manual
+	Note       string  `gorm:"column:note;default:''" json:"note"`
+	ExternalID string  `gorm:"column:external_id;default:'';index:idx_balance_adjustment_external" json:"external_id,omitempty"` // Exchange transfer ID (transfer source)
+	CreatedAt  int64   `gorm:"column:created_at;not null" json:"created_at"`                                                     // Unix milliseconds
+}
+
+func (BalanceAdjustment) TableName() string { return "trader_balance_adjustments" }
+
+// NewBalanceAdjustmentStore creates a new BalanceAdjustmentStore
+func NewBalanceAdjustmentStore(db *gorm.DB) *BalanceAdjustmentStore {
+	return &BalanceAdjustmentStore{db: db}
+}
+
+// Create records a balance adjustment
+func (s *BalanceAdjustmentStore) Create(adj *BalanceAdjustment) error {
+	if adj.CreatedAt == 0 {
+		adj.CreatedAt = time.Now().UTC().UnixMilli()
+	}
+	if err := s.db.Create(adj).Error; err != nil {
+		return fmt.Errorf("failed to create balance adjustment: %w", err)
+	}
+	return nil
+}
+
+// List lists a trader's balance adjustments (oldest first)
+func (s *BalanceAdjustmentStore) List(traderID string) ([]*BalanceAdjustment, error) {
+	var adjs []*BalanceAdjustment
+	err := s.db.Where("trader_id = ?", traderID).Order("created_at ASC, id ASC").Find(&adjs).Error
+	return adjs, err
+}
+
+// NetContributions sum of a trader's deposits minus withdrawals
+func (s *BalanceAdjustmentStore) NetContributions(traderID string) (float64, error) {
+	var total float64
+	err := s.db.Model(&BalanceAdjustment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("trader_id = ?", traderID).
+		Scan(&total).Error
+	return total, err
+}
+
+// NetContributionsForTraders net contributions of several traders (traders without adjustments are omitted)
+func (s *BalanceAdjustmentStore) NetContributionsForTraders(traderIDs []string) (map[string]float64, error) {
+	result := make(map[string]float64)
+	if len(traderIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		TraderID string
+		Total    float64
+	}
+	err := s.db.Model(&BalanceAdjustment{}).
+		Select("trader_id, COALESCE(SUM(amount), 0) AS total").
+		Where("trader_id IN ?", traderIDs).
+		Group("trader_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum balance adjustments: %w", err)
+	}
+	for _, r := range rows {
+		result[r.TraderID] = r.Total
+	}
+	return result, nil
+}
+
+// ExistsExternal checks whether a transfer with the exchange transfer ID was already recorded for the trader
+func (s *BalanceAdjustmentStore) ExistsExternal(traderID, externalID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&BalanceAdjustment{}).
+		Where("trader_id = ? AND external_id = ?", traderID, externalID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LatestTime returns the created_at of the trader's most recent adjustment (0 if none)
+func (s *BalanceAdjustmentStore) LatestTime(traderID string) (int64, error) {
+	var latest int64
+	err := s.db.Model(&BalanceAdjustment{}).
+		Select("COALESCE(MAX(created_at), 0)").
+		Where("trader_id = ?", traderID).
+		Scan(&latest).Error
+	return latest, err
+}
+
+// LastResetTime returns the created_at of the trader's most recent reset adjustment, i.e. when
+// balance sync last moved initial_balance to equity (0 if it never did)
+func (s *BalanceAdjustmentStore) LastResetTime(traderID string) (int64, error) {
+	var latest int64
+	err := s.db.Model(&BalanceAdjustment{}).
+		Select("COALESCE(MAX(created_at), 0)").
+		Where("trader_id = ? AND source = ?", traderID, BalanceAdjustmentReset).
+		Scan(&latest).Error
+	return latest, err
+}
+
+// ContributionsUntil net contributions recorded up to and including ts (adjs oldest first, as List returns them)
+func ContributionsUntil(adjs []*BalanceAdjustment, ts time.Time) float64 {
+	var total float64
+	for _, adj := range adjs {
+		if adj.CreatedAt > ts.UnixMilli() {
+			break
+		}
+		total += adj.Amount
+	}
+	return total
+}
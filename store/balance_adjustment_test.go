@@ -0,0 +1,53 @@
+package store
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// TestRealizedSinceLastReset checks the trading result the balance sync estimate uses: only PnL,
+// fees and funding booked after initial_balance was last reset count
+func TestRealizedSinceLastReset(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "adjust.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer st.Close()
+
+	const reset = int64(2_000)
+	rows := []interface{}{
+		// Before the reset: already part of initial_balance
+		&TraderPosition{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", EntryTime: 500, ExitTime: 1_000, RealizedPnL: 100, Fee: 5, Status: "CLOSED"},
+		&FundingPayment{TraderID: "t1", Symbol: "BTCUSDT", Amount: -3, ExternalID: "f1", CreatedAt: 1_500},
+		&BalanceAdjustment{TraderID: "t1", Amount: 250, Source: BalanceAdjustmentTransfer, CreatedAt: 1_800},
+		&BalanceAdjustment{TraderID: "t1", Amount: -250, Source: BalanceAdjustmentReset, CreatedAt: reset},
+		// After the reset
+		&TraderPosition{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", EntryTime: 1_200, ExitTime: 3_000, RealizedPnL: 40, Fee: 2, Status: "CLOSED"},
+		&TraderPosition{TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", EntryTime: 3_500, RealizedPnL: 10, Fee: 1, Status: "OPEN"},
+		&FundingPayment{TraderID: "t1", Symbol: "ETHUSDT", Amount: 0.5, ExternalID: "f2", CreatedAt: 4_000},
+		&BalanceAdjustment{TraderID: "t1", Amount: 100, Source: BalanceAdjustmentSync, CreatedAt: 4_500},
+	}
+	for _, row := range rows {
+		if err := st.gdb.Create(row).Error; err != nil {
+			t.Fatalf("seed %T: %v", row, err)
+		}
+	}
+
+	since, err := st.BalanceAdjustment().LastResetTime("t1")
+	if err != nil || since != reset {
+		t.Fatalf("LastResetTime() = %d, %v, want %d", since, err, reset)
+	}
+	pnl, fee, err := st.Position().RealizedSince("t1", since)
+	if err != nil || pnl != 50 || fee != 3 {
+		t.Errorf("RealizedSince() = %v, %v, %v, want 50, 3", pnl, fee, err)
+	}
+	funding, err := st.FundingPayment().NetFundingSince("t1", since)
+	if err != nil || math.Abs(funding-0.5) > 1e-9 {
+		t.Errorf("NetFundingSince() = %v, %v, want 0.5", funding, err)
+	}
+
+	if since, _ := st.BalanceAdjustment().LastResetTime("t2"); since != 0 {
+		t.Errorf("LastResetTime() of a trader never reset = %d, want 0", since)
+	}
+}
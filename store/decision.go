@@ -3,6 +3,7 @@ package store
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -24,12 +25,18 @@ type DecisionRecordDB struct {
 	CoTTrace            string    `gorm:"column:cot_trace;default:''"`
 	DecisionJSON        string    `gorm:"column:decision_json;default:''"`
 	RawResponse         string    `gorm:"column:raw_response;default:''"`
+	RepairedResponse    string    `gorm:"column:repaired_response;default:''"`
 	CandidateCoins      string    `gorm:"column:candidate_coins;default:''"`
 	ExecutionLog        string    `gorm:"column:execution_log;default:''"`
 	Decisions           string    `gorm:"column:decisions;default:'[]'"`
 	Success             bool      `gorm:"default:false"`
 	ErrorMessage        string    `gorm:"column:error_message;default:''"`
 	AIRequestDurationMs int64     `gorm:"column:ai_request_duration_ms;default:0"`
+	Notes               string    `gorm:"column:notes;default:''"`
+	Tags                string    `gorm:"column:tags;default:'[]'"`
+	ArchivedPayload     string    `gorm:"column:archived_payload;default:''"` // gzip+base64 of the archived text fields
+	UnavailableSources  string    `gorm:"column:unavailable_sources;default:''"`
+	TraceID             string    `gorm:"column:trace_id;default:'';index:idx_decision_records_trace"`
 	CreatedAt           time.Time `json:"created_at"`
 }
 
@@ -45,7 +52,8 @@ type DecisionRecord struct {
 	InputPrompt         string             `json:"input_prompt"`
 	CoTTrace            string             `json:"cot_trace"`
 	DecisionJSON        string             `json:"decision_json"`
-	RawResponse         string             `json:"raw_response"` // Raw AI response for debugging
+	RawResponse         string             `json:"raw_response"`                // Raw AI response for debugging
+	RepairedResponse    string             `json:"repaired_response,omitempty"` // AI answer to the "valid JSON only" re-prompt
 	CandidateCoins      []string           `json:"candidate_coins"`
 	ExecutionLog        []string           `json:"execution_log"`
 	Success             bool               `json:"success"`
@@ -54,6 +62,11 @@ type DecisionRecord struct {
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
+	Notes               string             `json:"notes"`                         // User annotation
+	Tags                []string           `json:"tags"`                          // User labels (e.g. "good call")
+	Archived            bool               `json:"archived,omitempty"`            // Prompts/responses moved to the compressed archive
+	UnavailableSources  []string           `json:"unavailable_sources,omitempty"` // Enabled data sources the AI lacked this cycle
+	TraceID             string             `json:"trace_id,omitempty"`            // Correlation ID of the cycle (tags its log lines, orders and fills)
 }
 
 // AccountSnapshot account state snapshot
@@ -80,19 +93,31 @@ type PositionSnapshot struct {
 
 // DecisionAction decision action
 type DecisionAction struct {
-	Action     string    `json:"action"`
-	Symbol     string    `json:"symbol"`
-	Quantity   float64   `json:"quantity"`
-	Leverage   int       `json:"leverage"`
-	Price      float64   `json:"price"`
-	StopLoss   float64   `json:"stop_loss,omitempty"`   // Stop loss price
-	TakeProfit float64   `json:"take_profit,omitempty"` // Take profit price
-	Confidence int       `json:"confidence,omitempty"`  // AI confidence (0-100)
-	Reasoning  string    `json:"reasoning,omitempty"`   // Brief reasoning
-	OrderID    int64     `json:"order_id"`
-	Timestamp  time.Time `json:"timestamp"`
-	Success    bool      `json:"success"`
-	Error      string    `json:"error"`
+	Action         string          `json:"action"`
+	Symbol         string          `json:"symbol"`
+	Quantity       float64         `json:"quantity"`
+	Leverage       int             `json:"leverage"`
+	Price          float64         `json:"price"`
+	StopLoss       float64         `json:"stop_loss,omitempty"`       // Stop loss price
+	TakeProfit     float64         `json:"take_profit,omitempty"`     // Take profit price
+	ExitOrderType  string          `json:"exit_order_type,omitempty"` // Order type of the placed SL/TP: "market" or "limit"
+	PostOnly       bool            `json:"post_only,omitempty"`       // Opened with a resting post-only (maker) limit
+	Confidence     int             `json:"confidence,omitempty"`      // AI confidence (0-100)
+	ATR            float64         `json:"atr,omitempty"`             // ATR used for volatility scaling
+	SizeMultiplier float64         `json:"size_multiplier,omitempty"` // Volatility scaling multiplier applied to the position size
+	Reasoning      string          `json:"reasoning,omitempty"`       // Brief reasoning
+	Votes          []ConsensusVote `json:"votes,omitempty"`           // Multi-model consensus votes on this symbol
+	OrderID        int64           `json:"order_id"`
+	Timestamp      time.Time       `json:"timestamp"`
+	Success        bool            `json:"success"`
+	Error          string          `json:"error"`
+}
+
+// ConsensusVote one model's vote in a multi-model consensus cycle
+type ConsensusVote struct {
+	Model  string  `json:"model"`
+	Action string  `json:"action"` // Action the model proposed for the symbol ("wait" if none, "error" if the call failed)
+	Weight float64 `json:"weight"`
 }
 
 // Statistics statistics information
@@ -102,6 +127,8 @@ type Statistics struct {
 	FailedCycles        int `json:"failed_cycles"`
 	TotalOpenPositions  int `json:"total_open_positions"`
 	TotalClosePositions int `json:"total_close_positions"`
+	// Cumulative funding received (+) / paid (-) on held perp positions
+	TotalFunding float64 `json:"total_funding"`
 }
 
 // NewDecisionStore creates a new DecisionStore
@@ -134,13 +161,24 @@ func (db *DecisionRecordDB) toRecord() *DecisionRecord {
 		CoTTrace:            db.CoTTrace,
 		DecisionJSON:        db.DecisionJSON,
 		RawResponse:         db.RawResponse,
+		RepairedResponse:    db.RepairedResponse,
 		Success:             db.Success,
 		ErrorMessage:        db.ErrorMessage,
 		AIRequestDurationMs: db.AIRequestDurationMs,
+		Notes:               db.Notes,
+		Tags:                []string{},
+		Archived:            db.ArchivedPayload != "",
+		TraceID:             db.TraceID,
 	}
 	json.Unmarshal([]byte(db.CandidateCoins), &record.CandidateCoins)
 	json.Unmarshal([]byte(db.ExecutionLog), &record.ExecutionLog)
 	json.Unmarshal([]byte(db.Decisions), &record.Decisions)
+	if db.Tags != "" {
+		json.Unmarshal([]byte(db.Tags), &record.Tags)
+	}
+	if db.UnavailableSources != "" {
+		json.Unmarshal([]byte(db.UnavailableSources), &record.UnavailableSources)
+	}
 	return record
 }
 
@@ -156,6 +194,11 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	candidateCoinsJSON, _ := json.Marshal(record.CandidateCoins)
 	executionLogJSON, _ := json.Marshal(record.ExecutionLog)
 	decisionsJSON, _ := json.Marshal(record.Decisions)
+	var unavailableSources string
+	if len(record.UnavailableSources) > 0 {
+		data, _ := json.Marshal(record.UnavailableSources)
+		unavailableSources = string(data)
+	}
 
 	dbRecord := &DecisionRecordDB{
 		TraderID:            record.TraderID,
@@ -166,12 +209,15 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 		CoTTrace:            record.CoTTrace,
 		DecisionJSON:        record.DecisionJSON,
 		RawResponse:         record.RawResponse,
+		RepairedResponse:    record.RepairedResponse,
 		CandidateCoins:      string(candidateCoinsJSON),
 		ExecutionLog:        string(executionLogJSON),
 		Decisions:           string(decisionsJSON),
 		Success:             record.Success,
 		ErrorMessage:        record.ErrorMessage,
 		AIRequestDurationMs: record.AIRequestDurationMs,
+		UnavailableSources:  unavailableSources,
+		TraceID:             record.TraceID,
 	}
 
 	if err := s.db.Create(dbRecord).Error; err != nil {
@@ -205,6 +251,40 @@ func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRec
 	return records, nil
 }
 
+// GetRecordsBySymbol gets a page of a trader's records whose decisions include an action on symbol
+// (newest first), plus the total number of matching records
+func (s *DecisionStore) GetRecordsBySymbol(traderID, symbol string, limit, offset int) ([]*DecisionRecord, int64, error) {
+	// Decisions are stored as marshaled []DecisionAction, so a symbol match is an exact "symbol":"X" field
+	pattern := "%" + escapeLike(fmt.Sprintf(`"symbol":%q`, symbol)) + "%"
+	query := s.db.Model(&DecisionRecordDB{}).
+		Where(`trader_id = ? AND decisions LIKE ? ESCAPE '\'`, traderID, pattern)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count decision records: %w", err)
+	}
+
+	var dbRecords []*DecisionRecordDB
+	err := query.Order("timestamp DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&dbRecords).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query decision records: %w", err)
+	}
+
+	records := make([]*DecisionRecord, len(dbRecords))
+	for i, db := range dbRecords {
+		records[i] = db.toRecord()
+	}
+	return records, total, nil
+}
+
+// escapeLike escapes LIKE wildcards so value matches literally (with ESCAPE '\')
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
 // GetAllLatestRecords gets the latest N records for all traders
 func (s *DecisionStore) GetAllLatestRecords(n int) ([]*DecisionRecord, error) {
 	var dbRecords []*DecisionRecordDB
@@ -246,6 +326,39 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	return records, nil
 }
 
+// GetRecordByID gets a single decision record (archived text fields are restored)
+func (s *DecisionStore) GetRecordByID(id int64) (*DecisionRecord, error) {
+	var dbRecord DecisionRecordDB
+	if err := s.db.Where("id = ?", id).First(&dbRecord).Error; err != nil {
+		return nil, fmt.Errorf("failed to query decision record: %w", err)
+	}
+	// A single record is shown in full, restore archived prompts/responses
+	if err := dbRecord.restoreArchived(); err != nil {
+		return nil, err
+	}
+	return dbRecord.toRecord(), nil
+}
+
+// Annotate sets the user's notes and tags on a decision record (replaces previous annotation)
+func (s *DecisionStore) Annotate(id int64, notes string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, _ := json.Marshal(tags)
+
+	result := s.db.Model(&DecisionRecordDB{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"notes": notes,
+		"tags":  string(tagsJSON),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to annotate decision record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("decision record %d not found", id)
+	}
+	return nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days)
@@ -266,6 +379,12 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	s.db.Model(&DecisionRecordDB{}).Where("trader_id = ?", traderID).Count(&totalCount)
 	s.db.Model(&DecisionRecordDB{}).Where("trader_id = ? AND success = ?", traderID, true).Count(&successCount)
 
+	// Include cycles removed by the retention pruner
+	var archived TraderArchivedStats
+	s.db.Where("trader_id = ?", traderID).Limit(1).Find(&archived)
+	totalCount += archived.PrunedCycles
+	successCount += archived.PrunedSuccessfulCycles
+
 	stats.TotalCycles = int(totalCount)
 	stats.SuccessfulCycles = int(successCount)
 	stats.FailedCycles = stats.TotalCycles - stats.SuccessfulCycles
@@ -273,10 +392,37 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	// Count from trader_positions table using raw query for cross-table
 	s.db.Raw("SELECT COUNT(*) FROM trader_positions WHERE trader_id = ?", traderID).Scan(&stats.TotalOpenPositions)
 	s.db.Raw("SELECT COUNT(*) FROM trader_positions WHERE trader_id = ? AND status = 'CLOSED'", traderID).Scan(&stats.TotalClosePositions)
+	s.db.Raw("SELECT COALESCE(SUM(amount), 0) FROM funding_payments WHERE trader_id = ?", traderID).Scan(&stats.TotalFunding)
 
 	return stats, nil
 }
 
+// AIUsage AI call volume of a trader's decision cycles within a time range
+type AIUsage struct {
+	Calls           int   `json:"calls"`
+	FailedCalls     int   `json:"failed_calls"`
+	TotalDurationMs int64 `json:"total_duration_ms"`
+	PromptChars     int64 `json:"prompt_chars"`   // System + user prompt characters sent
+	ResponseChars   int64 `json:"response_chars"` // Raw response characters received
+}
+
+// GetAIUsage sums the AI calls of the decision cycles in [start, end]
+func (s *DecisionStore) GetAIUsage(traderID string, start, end time.Time) (*AIUsage, error) {
+	usage := &AIUsage{}
+	err := s.db.Model(&DecisionRecordDB{}).
+		Select(`COUNT(*) AS calls,
+			COALESCE(SUM(CASE WHEN success THEN 0 ELSE 1 END), 0) AS failed_calls,
+			COALESCE(SUM(ai_request_duration_ms), 0) AS total_duration_ms,
+			COALESCE(SUM(LENGTH(system_prompt) + LENGTH(input_prompt)), 0) AS prompt_chars,
+			COALESCE(SUM(LENGTH(raw_response)), 0) AS response_chars`).
+		Where("trader_id = ? AND timestamp >= ? AND timestamp <= ?", traderID, start, end).
+		Scan(usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	return usage, nil
+}
+
 // GetAllStatistics gets statistics information for all traders
 func (s *DecisionStore) GetAllStatistics() (*Statistics, error) {
 	stats := &Statistics{}
@@ -285,6 +431,17 @@ func (s *DecisionStore) GetAllStatistics() (*Statistics, error) {
 	s.db.Model(&DecisionRecordDB{}).Count(&totalCount)
 	s.db.Model(&DecisionRecordDB{}).Where("success = ?", true).Count(&successCount)
 
+	// Include cycles removed by the retention pruner
+	var archived struct {
+		Cycles     int64
+		Successful int64
+	}
+	s.db.Model(&TraderArchivedStats{}).
+		Select("COALESCE(SUM(pruned_cycles), 0) as cycles, COALESCE(SUM(pruned_successful_cycles), 0) as successful").
+		Scan(&archived)
+	totalCount += archived.Cycles
+	successCount += archived.Successful
+
 	stats.TotalCycles = int(totalCount)
 	stats.SuccessfulCycles = int(successCount)
 	stats.FailedCycles = stats.TotalCycles - stats.SuccessfulCycles
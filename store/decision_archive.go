@@ -0,0 +1,136 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// decisionArchiveBatchSize records compressed per transaction by Archive
+const decisionArchiveBatchSize = 200
+
+// archivedDecisionText the large text fields of a decision record moved into archived_payload
+type archivedDecisionText struct {
+	SystemPrompt     string `json:"system_prompt,omitempty"`
+	InputPrompt      string `json:"input_prompt,omitempty"`
+	CoTTrace         string `json:"cot_trace,omitempty"`
+	RawResponse      string `json:"raw_response,omitempty"`
+	RepairedResponse string `json:"repaired_response,omitempty"`
+}
+
+// Archive compresses the prompts and responses of decision records older than olderThan into
+// archived_payload and clears the text columns, keeping the summary row (decisions, execution log,
+// success, timing). GetRecordByID restores the archived text. Returns the number of archived records.
+func (s *DecisionStore) Archive(olderThan time.Time) (int64, error) {
+	var archived int64
+	for {
+		var rows []*DecisionRecordDB
+		err := s.db.Select("id", "system_prompt", "input_prompt", "cot_trace", "raw_response", "repaired_response").
+			Where("timestamp < ? AND COALESCE(archived_payload, '') = ''", olderThan.UTC()).
+			Where("COALESCE(system_prompt, '') <> '' OR COALESCE(input_prompt, '') <> '' OR COALESCE(cot_trace, '') <> '' OR COALESCE(raw_response, '') <> '' OR COALESCE(repaired_response, '') <> ''").
+			Order("id ASC").
+			Limit(decisionArchiveBatchSize).
+			Find(&rows).Error
+		if err != nil {
+			return archived, fmt.Errorf("failed to query decision records to archive: %w", err)
+		}
+		if len(rows) == 0 {
+			return archived, nil
+		}
+
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			for _, row := range rows {
+				payload, err := compressDecisionText(archivedDecisionText{
+					SystemPrompt:     row.SystemPrompt,
+					InputPrompt:      row.InputPrompt,
+					CoTTrace:         row.CoTTrace,
+					RawResponse:      row.RawResponse,
+					RepairedResponse: row.RepairedResponse,
+				})
+				if err != nil {
+					return err
+				}
+				err = tx.Model(&DecisionRecordDB{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+					"archived_payload":  payload,
+					"system_prompt":     "",
+					"input_prompt":      "",
+					"cot_trace":         "",
+					"raw_response":      "",
+					"repaired_response": "",
+				}).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive decision records: %w", err)
+		}
+		archived += int64(len(rows))
+
+		if len(rows) < decisionArchiveBatchSize {
+			return archived, nil
+		}
+	}
+}
+
+// restoreArchived decompresses archived_payload back into the text fields
+func (db *DecisionRecordDB) restoreArchived() error {
+	if db.ArchivedPayload == "" {
+		return nil
+	}
+	text, err := decompressDecisionText(db.ArchivedPayload)
+	if err != nil {
+		return fmt.Errorf("failed to restore archived decision record %d: %w", db.ID, err)
+	}
+	db.SystemPrompt = text.SystemPrompt
+	db.InputPrompt = text.InputPrompt
+	db.CoTTrace = text.CoTTrace
+	db.RawResponse = text.RawResponse
+	db.RepairedResponse = text.RepairedResponse
+	return nil
+}
+
+func compressDecisionText(text archivedDecisionText) (string, error) {
+	data, err := json.Marshal(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decompressDecisionText(payload string) (*archivedDecisionText, error) {
+	compressed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	var text archivedDecisionText
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, err
+	}
+	return &text, nil
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	_ "github.com/lib/pq"      // PostgreSQL driver
 	_ "modernc.org/sqlite"     // SQLite driver
@@ -29,6 +31,10 @@ type DBConfig struct {
 	Password string // PostgreSQL password (for postgres)
 	DBName   string // PostgreSQL database name (for postgres)
 	SSLMode  string // PostgreSQL SSL mode (for postgres)
+
+	// SQLite tuning (for sqlite)
+	JournalMode string        // WAL (default) or DELETE/TRUNCATE/PERSIST
+	BusyTimeout time.Duration // Lock wait before failing with "database is locked", 0 = 5s
 }
 
 // DBDriver database driver abstraction
@@ -44,7 +50,7 @@ func NewDBDriver(cfg DBConfig) (*DBDriver, error) {
 
 	switch cfg.Type {
 	case DBTypeSQLite:
-		db, err = openSQLite(cfg.Path)
+		db, err = openSQLite(cfg)
 	case DBTypePostgres:
 		db, err = openPostgres(cfg)
 	default:
@@ -60,7 +66,7 @@ func NewDBDriver(cfg DBConfig) (*DBDriver, error) {
 
 // NewDBDriverFromEnv creates database driver from environment variables
 // DB_TYPE: sqlite (default) or postgres
-// For SQLite: DB_PATH (default: data/data.db)
+// For SQLite: DB_PATH (default: data/data.db), DB_JOURNAL_MODE (default: WAL), DB_BUSY_TIMEOUT_MS (default: 5000)
 // For PostgreSQL: DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME, DB_SSLMODE
 func NewDBDriverFromEnv() (*DBDriver, error) {
 	dbType := DBType(strings.ToLower(getEnv("DB_TYPE", "sqlite")))
@@ -68,7 +74,16 @@ func NewDBDriverFromEnv() (*DBDriver, error) {
 	switch dbType {
 	case DBTypeSQLite:
 		path := getEnv("DB_PATH", "data/data.db")
-		return NewDBDriver(DBConfig{Type: DBTypeSQLite, Path: path})
+		busyTimeout := DefaultSQLiteBusyTimeout
+		if ms, err := strconv.Atoi(os.Getenv("DB_BUSY_TIMEOUT_MS")); err == nil && ms > 0 {
+			busyTimeout = time.Duration(ms) * time.Millisecond
+		}
+		return NewDBDriver(DBConfig{
+			Type:        DBTypeSQLite,
+			Path:        path,
+			JournalMode: getEnv("DB_JOURNAL_MODE", DefaultSQLiteJournalMode),
+			BusyTimeout: busyTimeout,
+		})
 
 	case DBTypePostgres:
 		port := 5432
@@ -169,8 +184,14 @@ func (d *DBDriver) UpsertSyntax() string {
 }
 
 // openSQLite opens SQLite database
-func openSQLite(path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", path)
+func openSQLite(cfg DBConfig) (*sql.DB, error) {
+	journalMode, busyTimeout, err := cfg.sqliteSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// foreign_keys, journal_mode, synchronous and busy_timeout are applied to every connection via the DSN
+	db, err := sql.Open("sqlite", sqlitePragmaDSN(cfg.Path, journalMode, busyTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
 	}
@@ -179,29 +200,12 @@ func openSQLite(path string) (*sql.DB, error) {
 	db.SetMaxOpenConns(1)
 	db.SetMaxIdleConns(1)
 
-	// Enable foreign key constraints
-	if _, err := db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-
-	// Use DELETE mode for Docker compatibility
-	if _, err := db.Exec("PRAGMA journal_mode=DELETE"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to set journal_mode: %w", err)
-	}
-
-	// Set synchronous=FULL
-	if _, err := db.Exec("PRAGMA synchronous=FULL"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to set synchronous: %w", err)
-	}
-
-	// Set busy_timeout
-	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+	// Open a connection now so invalid settings fail here instead of on first query
+	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to set busy_timeout: %w", err)
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
 	}
+	checkJournalMode(db, journalMode)
 
 	return db, nil
 }
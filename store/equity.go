@@ -115,6 +115,33 @@ func (s *EquityStore) GetAllTradersLatest() (map[string]*EquitySnapshot, error)
 	return result, nil
 }
 
+// GetLatestForTraders gets latest equity for the given traders (traders without snapshots are absent)
+func (s *EquityStore) GetLatestForTraders(traderIDs []string) (map[string]*EquitySnapshot, error) {
+	result := make(map[string]*EquitySnapshot)
+	if len(traderIDs) == 0 {
+		return result, nil
+	}
+	var snapshots []*EquitySnapshot
+	err := s.db.Raw(`
+		SELECT e.id, e.trader_id, e.timestamp, e.total_equity, e.balance,
+		       e.unrealized_pnl, e.position_count, e.margin_used_pct, e.created_at
+		FROM trader_equity_snapshots e
+		INNER JOIN (
+			SELECT trader_id, MAX(timestamp) as max_ts
+			FROM trader_equity_snapshots
+			WHERE trader_id IN ?
+			GROUP BY trader_id
+		) latest ON e.trader_id = latest.trader_id AND e.timestamp = latest.max_ts
+	`, traderIDs).Scan(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest equity: %w", err)
+	}
+	for _, snap := range snapshots {
+		result[snap.TraderID] = snap
+	}
+	return result, nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *EquityStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days)
@@ -127,6 +154,71 @@ func (s *EquityStore) CleanOldRecords(traderID string, days int) (int64, error)
 	return result.RowsAffected, nil
 }
 
+// GetTraderIDs gets the distinct trader IDs that have equity records
+func (s *EquityStore) GetTraderIDs() ([]string, error) {
+	var traderIDs []string
+	err := s.db.Model(&EquitySnapshot{}).
+		Select("DISTINCT trader_id").
+		Pluck("trader_id", &traderIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity trader IDs: %w", err)
+	}
+	return traderIDs, nil
+}
+
+// Downsample reduces snapshots older than olderThan to one record per bucket.
+// The latest snapshot in each bucket is kept so the curve keeps its shape,
+// all other records in the bucket are deleted. Recent data stays at full resolution.
+// Returns the number of deleted records.
+func (s *EquityStore) Downsample(traderID string, olderThan time.Time, bucket time.Duration) (int64, error) {
+	if bucket <= 0 {
+		return 0, fmt.Errorf("invalid downsample bucket: %v", bucket)
+	}
+
+	type snapshotRef struct {
+		ID        int64
+		Timestamp time.Time
+	}
+	var refs []snapshotRef
+	err := s.db.Model(&EquitySnapshot{}).
+		Select("id, timestamp").
+		Where("trader_id = ? AND timestamp < ?", traderID, olderThan.UTC()).
+		Order("timestamp ASC, id ASC").
+		Scan(&refs).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query equity records for downsampling: %w", err)
+	}
+	if len(refs) < 2 {
+		return 0, nil
+	}
+
+	// Records are ordered by time, so the last record seen for a bucket is the one to keep
+	var toDelete []int64
+	for i := 0; i < len(refs)-1; i++ {
+		current := refs[i].Timestamp.UTC().Truncate(bucket)
+		next := refs[i+1].Timestamp.UTC().Truncate(bucket)
+		if current.Equal(next) {
+			toDelete = append(toDelete, refs[i].ID)
+		}
+	}
+
+	// Delete in batches to stay below SQL parameter limits
+	const batchSize = 500
+	var deleted int64
+	for start := 0; start < len(toDelete); start += batchSize {
+		end := start + batchSize
+		if end > len(toDelete) {
+			end = len(toDelete)
+		}
+		result := s.db.Where("id IN ?", toDelete[start:end]).Delete(&EquitySnapshot{})
+		if result.Error != nil {
+			return deleted, fmt.Errorf("failed to delete downsampled equity records: %w", result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+	return deleted, nil
+}
+
 // GetCount gets record count for specified trader
 func (s *EquityStore) GetCount(traderID string) (int, error) {
 	var count int64
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Equity alert directions
+const (
+	EquityAlertBelow = "below" // Fires when equity drops below the threshold
+	EquityAlertAbove = "above" // Fires when equity rises above the threshold
+)
+
+// EquityAlertStore per-trader equity alert rules
+type EquityAlertStore struct {
+	db *gorm.DB
+}
+
+// EquityAlert an "alert me if equity goes below/above X" rule
+type EquityAlert struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID        string     `gorm:"column:trader_id;not null;index:idx_equity_alert_trader" json:"trader_id"`
+	Direction       string     `gorm:"column:direction;not null" json:"direction"` // below / above
+	Threshold       float64    `gorm:"column:threshold;not null" json:"threshold"`
+	HysteresisPct   float64    `gorm:"column:hysteresis_pct;default:0" json:"hysteresis_pct"` // Re-arm distance past the threshold (%, 0 = default)
+	Note            string     `gorm:"column:note;default:''" json:"note"`
+	Enabled         bool       `gorm:"column:enabled;not null" json:"enabled"`          // No gorm default: a rule created disabled must stay disabled
+	Triggered       bool       `gorm:"column:triggered;default:false" json:"triggered"` // Fired and not re-armed yet
+	LastTriggeredAt *time.Time `gorm:"column:last_triggered_at" json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (EquityAlert) TableName() string { return "trader_equity_alerts" }
+
+// NewEquityAlertStore creates a new EquityAlertStore
+func NewEquityAlertStore(db *gorm.DB) *EquityAlertStore {
+	return &EquityAlertStore{db: db}
+}
+
+// Create creates an alert rule
+func (s *EquityAlertStore) Create(alert *EquityAlert) error {
+	if err := s.db.Create(alert).Error; err != nil {
+		return fmt.Errorf("failed to create equity alert: %w", err)
+	}
+	return nil
+}
+
+// List lists a trader's alert rules (oldest first)
+func (s *EquityAlertStore) List(traderID string) ([]*EquityAlert, error) {
+	var alerts []*EquityAlert
+	err := s.db.Where("trader_id = ?", traderID).Order("id ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+// ListEnabled lists a trader's enabled alert rules
+func (s *EquityAlertStore) ListEnabled(traderID string) ([]*EquityAlert, error) {
+	var alerts []*EquityAlert
+	err := s.db.Where("trader_id = ? AND enabled = ?", traderID, true).Order("id ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+// Get gets one of a trader's alert rules
+func (s *EquityAlertStore) Get(traderID string, id int64) (*EquityAlert, error) {
+	var alert EquityAlert
+	if err := s.db.Where("id = ? AND trader_id = ?", id, traderID).First(&alert).Error; err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Update saves an edited alert rule, re-arming it
+func (s *EquityAlertStore) Update(alert *EquityAlert) error {
+	return s.db.Model(&EquityAlert{}).
+		Where("id = ? AND trader_id = ?", alert.ID, alert.TraderID).
+		Updates(map[string]interface{}{
+			"direction":      alert.Direction,
+			"threshold":      alert.Threshold,
+			"hysteresis_pct": alert.HysteresisPct,
+			"note":           alert.Note,
+			"enabled":        alert.Enabled,
+			"triggered":      false,
+		}).Error
+}
+
+// SetTriggered records that an alert fired (triggered) or re-armed
+func (s *EquityAlertStore) SetTriggered(id int64, triggered bool) error {
+	updates := map[string]interface{}{"triggered": triggered}
+	if triggered {
+		updates["last_triggered_at"] = time.Now().UTC()
+	}
+	return s.db.Model(&EquityAlert{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete deletes one of a trader's alert rules
+func (s *EquityAlertStore) Delete(traderID string, id int64) error {
+	result := s.db.Where("id = ? AND trader_id = ?", id, traderID).Delete(&EquityAlert{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByTrader deletes all alert rules of a trader
+func (s *EquityAlertStore) DeleteByTrader(traderID string) error {
+	return s.db.Where("trader_id = ?", traderID).Delete(&EquityAlert{}).Error
+}
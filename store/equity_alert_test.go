@@ -0,0 +1,48 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEquityAlertStore(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "alerts.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer st.Close()
+
+	if err := st.Trader().Create(&Trader{ID: "t1", UserID: "u1", Name: "t", AIModelID: "m", ExchangeID: "e"}); err != nil {
+		t.Fatalf("create trader: %v", err)
+	}
+
+	disabled := &EquityAlert{TraderID: "t1", Direction: EquityAlertBelow, Threshold: 900, Enabled: false}
+	enabled := &EquityAlert{TraderID: "t1", Direction: EquityAlertAbove, Threshold: 1100, Enabled: true}
+	for _, a := range []*EquityAlert{disabled, enabled} {
+		if err := st.EquityAlert().Create(a); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if disabled.ID == 0 || enabled.ID == 0 || disabled.ID == enabled.ID {
+		t.Fatalf("alert IDs = %d, %d, want distinct auto-increment IDs", disabled.ID, enabled.ID)
+	}
+
+	got, err := st.EquityAlert().Get("t1", disabled.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Enabled {
+		t.Error("alert created disabled was stored enabled")
+	}
+	active, err := st.EquityAlert().ListEnabled("t1")
+	if err != nil || len(active) != 1 || active[0].ID != enabled.ID {
+		t.Errorf("ListEnabled() = %v, %v, want only the enabled alert", active, err)
+	}
+
+	if err := st.Trader().Delete("u1", "t1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if left, _ := st.EquityAlert().List("t1"); len(left) != 0 {
+		t.Errorf("%d alerts left after deleting the trader", len(left))
+	}
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"encoding/json"
 	"fmt"
 	"nofx/crypto"
 	"nofx/logger"
@@ -36,12 +37,77 @@ type Exchange struct {
 	LighterPrivateKey       crypto.EncryptedString `gorm:"column:lighter_private_key;default:''" json:"lighterPrivateKey"`
 	LighterAPIKeyPrivateKey crypto.EncryptedString `gorm:"column:lighter_api_key_private_key;default:''" json:"lighterAPIKeyPrivateKey"`
 	LighterAPIKeyIndex      int             `gorm:"column:lighter_api_key_index;default:0" json:"lighterAPIKeyIndex"`
+	ExtraAPIKeys            crypto.EncryptedString `gorm:"column:extra_api_keys;default:''" json:"-"` // JSON list of APIKeyCredential
+	Slippage                string          `gorm:"column:slippage;default:''" json:"-"`                 // JSON SlippageSettings
 	CreatedAt               time.Time       `json:"created_at"`
 	UpdatedAt               time.Time       `json:"updated_at"`
 }
 
 func (Exchange) TableName() string { return "exchanges" }
 
+// APIKeyCredential an additional API key of the same exchange account; requests rotate across
+// the primary and additional keys so per-key rate limits add up
+type APIKeyCredential struct {
+	APIKey     string `json:"api_key"`
+	SecretKey  string `json:"secret_key"`
+	Passphrase string `json:"passphrase,omitempty"` // OKX/Bitget/Coinbase: each key has its own passphrase
+}
+
+// AdditionalAPIKeys returns the additional API keys of the account (nil if none)
+func (e *Exchange) AdditionalAPIKeys() []APIKeyCredential {
+	if e.ExtraAPIKeys == "" {
+		return nil
+	}
+	var keys []APIKeyCredential
+	if err := json.Unmarshal([]byte(e.ExtraAPIKeys), &keys); err != nil {
+		logger.Warnf("⚠️ Exchange %s: unreadable additional API keys: %v", e.ID, err)
+		return nil
+	}
+	return keys
+}
+
+// EncodeAPIKeys serializes additional API keys for Exchange.ExtraAPIKeys
+func EncodeAPIKeys(keys []APIKeyCredential) crypto.EncryptedString {
+	if len(keys) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(keys)
+	return crypto.EncryptedString(data)
+}
+
+// SlippageSettings aggressive IOC limit price offsets of DEX market orders (Hyperliquid, Lighter), in basis points
+type SlippageSettings struct {
+	Bps       float64            `json:"bps,omitempty"`        // all symbols (0 = exchange default)
+	SymbolBps map[string]float64 `json:"symbol_bps,omitempty"` // per-symbol overrides
+}
+
+// IsZero reports whether no slippage is configured
+func (s SlippageSettings) IsZero() bool {
+	return s.Bps == 0 && len(s.SymbolBps) == 0
+}
+
+// SlippageSettings returns the IOC slippage settings of the account (zero if none)
+func (e *Exchange) SlippageSettings() SlippageSettings {
+	var settings SlippageSettings
+	if e.Slippage == "" {
+		return settings
+	}
+	if err := json.Unmarshal([]byte(e.Slippage), &settings); err != nil {
+		logger.Warnf("⚠️ Exchange %s: unreadable slippage settings: %v", e.ID, err)
+		return SlippageSettings{}
+	}
+	return settings
+}
+
+// EncodeSlippage serializes slippage settings for Exchange.Slippage
+func EncodeSlippage(settings SlippageSettings) string {
+	if settings.IsZero() {
+		return ""
+	}
+	data, _ := json.Marshal(settings)
+	return string(data)
+}
+
 // NewExchangeStore creates a new ExchangeStore
 func NewExchangeStore(db *gorm.DB) *ExchangeStore {
 	return &ExchangeStore{db: db}
@@ -175,6 +241,10 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 		return "LIGHTER DEX", "dex"
 	case "gateio":
 		return "Gate.io Futures", "cex"
+	case "coinbase":
+		return "Coinbase International", "cex"
+	case "backpack":
+		return "Backpack", "cex"
 	default:
 		return exchangeType + " Exchange", "cex"
 	}
@@ -271,6 +341,40 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 	return nil
 }
 
+// SetAdditionalAPIKeys replaces the additional API keys of an exchange account (empty = primary key only)
+func (s *ExchangeStore) SetAdditionalAPIKeys(userID, id string, keys []APIKeyCredential) error {
+	result := s.db.Model(&Exchange{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"extra_api_keys": EncodeAPIKeys(keys),
+			"updated_at":     time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	return nil
+}
+
+// SetSlippage replaces the IOC slippage settings of an exchange account
+func (s *ExchangeStore) SetSlippage(userID, id string, settings SlippageSettings) error {
+	result := s.db.Model(&Exchange{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"slippage":   EncodeSlippage(settings),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	return nil
+}
+
 // UpdateAccountName updates the account name for an exchange
 func (s *ExchangeStore) UpdateAccountName(userID, id, accountName string) error {
 	result := s.db.Model(&Exchange{}).
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FundingPaymentStore perpetual funding payments imported from the exchange per trader
+type FundingPaymentStore struct {
+	db *gorm.DB
+}
+
+// FundingPayment a funding fee received (positive amount) or paid (negative amount) on a held perp position.
+// Funding isn't part of any position's realized PnL, so it is summed separately and added on top.
+type FundingPayment struct {
+	ID         int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string  `gorm:"column:trader_id;not null;index:idx_funding_payments_trader" json:"trader_id"`
+	Symbol     string  `gorm:"column:symbol;not null" json:"symbol"`
+	Asset      string  `gorm:"column:asset;default:''" json:"asset"`
+	Amount     float64 `gorm:"column:amount;not null" json:"amount"`                                                         // + received, - paid
+	ExternalID string  `gorm:"column:external_id;not null;index:idx_funding_payments_external" json:"external_id,omitempty"` // Exchange record ID
+	CreatedAt  int64   `gorm:"column:created_at;not null" json:"created_at"`                                                 // Unix milliseconds, settlement time
+}
+
+func (FundingPayment) TableName() string { return "funding_payments" }
+
+// NewFundingPaymentStore creates a new FundingPaymentStore
+func NewFundingPaymentStore(db *gorm.DB) *FundingPaymentStore {
+	return &FundingPaymentStore{db: db}
+}
+
+// Create records a funding payment
+func (s *FundingPaymentStore) Create(p *FundingPayment) error {
+	if p.CreatedAt == 0 {
+		p.CreatedAt = time.Now().UTC().UnixMilli()
+	}
+	if err := s.db.Create(p).Error; err != nil {
+		return fmt.Errorf("failed to create funding payment: %w", err)
+	}
+	return nil
+}
+
+// ExistsExternal checks whether the exchange funding record was already recorded for the trader
+func (s *FundingPaymentStore) ExistsExternal(traderID, externalID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&FundingPayment{}).
+		Where("trader_id = ? AND external_id = ?", traderID, externalID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LatestTime returns the settlement time of the trader's most recent funding payment (0 if none)
+func (s *FundingPaymentStore) LatestTime(traderID string) (int64, error) {
+	var latest int64
+	err := s.db.Model(&FundingPayment{}).
+		Select("COALESCE(MAX(created_at), 0)").
+		Where("trader_id = ?", traderID).
+		Scan(&latest).Error
+	return latest, err
+}
+
+// NetFunding sum of a trader's funding received minus funding paid
+func (s *FundingPaymentStore) NetFunding(traderID string) (float64, error) {
+	return s.NetFundingSince(traderID, 0)
+}
+
+// NetFundingSince net funding of a trader settled at or after sinceMs (Unix milliseconds)
+func (s *FundingPaymentStore) NetFundingSince(traderID string, sinceMs int64) (float64, error) {
+	var total float64
+	err := s.db.Model(&FundingPayment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("trader_id = ? AND created_at >= ?", traderID, sinceMs).
+		Scan(&total).Error
+	return total, err
+}
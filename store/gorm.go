@@ -18,9 +18,19 @@ func DB() *gorm.DB {
 	return gormDB
 }
 
-// InitGorm initializes GORM with SQLite
+// InitGorm initializes GORM with SQLite using the default journal mode and busy timeout
 func InitGorm(dbPath string) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	return InitGormSQLite(DBConfig{Type: DBTypeSQLite, Path: dbPath})
+}
+
+// InitGormSQLite initializes GORM with SQLite (cfg.Path, cfg.JournalMode, cfg.BusyTimeout)
+func InitGormSQLite(cfg DBConfig) (*gorm.DB, error) {
+	journalMode, busyTimeout, err := cfg.sqliteSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(sqlite.Open(sqliteGormDSN(cfg.Path, journalMode, busyTimeout)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 		// Use UTC for all auto-generated timestamps (autoCreateTime, autoUpdateTime)
 		NowFunc: func() time.Time {
@@ -39,11 +49,8 @@ func InitGorm(dbPath string) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(1)
 	sqlDB.SetMaxIdleConns(1)
 
-	// Enable foreign keys for SQLite
-	db.Exec("PRAGMA foreign_keys = ON")
-	db.Exec("PRAGMA journal_mode = DELETE")
-	db.Exec("PRAGMA synchronous = FULL")
-	db.Exec("PRAGMA busy_timeout = 5000")
+	// foreign_keys, journal_mode, synchronous and busy_timeout are set per connection via the DSN
+	checkJournalMode(sqlDB, journalMode)
 
 	gormDB = db
 	return db, nil
@@ -84,7 +91,7 @@ func InitGormPostgres(host string, port int, user, password, dbname, sslmode str
 func InitGormWithConfig(cfg DBConfig) (*gorm.DB, error) {
 	switch cfg.Type {
 	case DBTypeSQLite:
-		return InitGorm(cfg.Path)
+		return InitGormSQLite(cfg)
 
 	case DBTypePostgres:
 		return InitGormPostgres(
@@ -0,0 +1,509 @@
+package store
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration a versioned schema migration
+// Migrations are applied in ascending Version order, each exactly once, inside a transaction.
+// Never change or renumber a released migration; add a new one instead.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *gorm.DB) error
+}
+
+// SchemaMigration applied migration record
+type SchemaMigration struct {
+	Version     int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Description string    `gorm:"column:description;default:''" json:"description"`
+	AppliedAt   time.Time `gorm:"column:applied_at" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// migrations registered schema migrations (append only)
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create debate tables",
+		Up:          migrateDebateTables,
+	},
+	{
+		Version:     2,
+		Description: "add traders.quote_asset",
+		Up:          migrateTraderQuoteAsset,
+	},
+	{
+		Version:     3,
+		Description: "add traders.disabled",
+		Up:          migrateTraderDisabled,
+	},
+	{
+		Version:     4,
+		Description: "add traders.flatten_on_stop",
+		Up:          migrateTraderFlattenOnStop,
+	},
+	{
+		Version:     5,
+		Description: "add decision_records.repaired_response",
+		Up:          migrateDecisionRepairedResponse,
+	},
+	{
+		Version:     6,
+		Description: "add traders.equity_high_water_mark",
+		Up:          migrateTraderEquityHighWaterMark,
+	},
+	{
+		Version:     7,
+		Description: "create api_audit_logs table",
+		Up:          migrateAPIAuditLogs,
+	},
+	{
+		Version:     8,
+		Description: "add traders.prompt_language",
+		Up:          migrateTraderPromptLanguage,
+	},
+	{
+		Version:     9,
+		Description: "add traders.consensus_models and consensus_threshold",
+		Up:          migrateTraderConsensus,
+	},
+	{
+		Version:     10,
+		Description: "create trader_equity_alerts table",
+		Up:          migrateEquityAlerts,
+	},
+	{
+		Version:     11,
+		Description: "add decision_records.archived_payload",
+		Up:          migrateDecisionArchivedPayload,
+	},
+	{
+		Version:     12,
+		Description: "add exchanges.extra_api_keys",
+		Up:          migrateExchangeExtraAPIKeys,
+	},
+	{
+		Version:     13,
+		Description: "add decision_records.unavailable_sources",
+		Up:          migrateDecisionUnavailableSources,
+	},
+	{
+		Version:     14,
+		Description: "add traders.ai_request_timeout_sec",
+		Up:          migrateTraderAIRequestTimeout,
+	},
+	{
+		Version:     15,
+		Description: "create trader_balance_adjustments table",
+		Up:          migrateBalanceAdjustments,
+	},
+	{
+		Version:     16,
+		Description: "add traders.display_decimals",
+		Up:          migrateTraderDisplayDecimals,
+	},
+	{
+		Version:     17,
+		Description: "add trader_balance_adjustments.external_id",
+		Up:          migrateBalanceAdjustmentExternalID,
+	},
+	{
+		Version:     18,
+		Description: "add exchanges.slippage",
+		Up:          migrateExchangeSlippage,
+	},
+	{
+		Version:     19,
+		Description: "add trader_positions.liquidation_price",
+		Up:          migratePositionLiquidationPrice,
+	},
+	{
+		Version:     20,
+		Description: "create funding_payments table",
+		Up:          migrateFundingPayments,
+	},
+	{
+		Version:     21,
+		Description: "add trader_fills.commission_usdt",
+		Up:          migrateFillCommissionUSDT,
+	},
+	{
+		Version:     22,
+		Description: "add decision_records.trace_id",
+		Up:          migrateDecisionTraceID,
+	},
+	{
+		Version:     23,
+		Description: "add trader_orders.trace_id and trader_fills.trace_id",
+		Up:          migrateOrderTraceID,
+	},
+	{
+		Version:     24,
+		Description: "add traders.mirror_exchange_ids",
+		Up:          migrateTraderMirrorExchanges,
+	},
+	{
+		Version:     25,
+		Description: "add traders.log_level",
+		Up:          migrateTraderLogLevel,
+	},
+	{
+		Version:     26,
+		Description: "add users.is_admin",
+		Up:          migrateUserIsAdmin,
+	},
+	{
+		Version:     27,
+		Description: "create trader_archived_stats table",
+		Up:          migrateArchivedStats,
+	},
+	{
+		Version:     28,
+		Description: "add decision_records.notes and tags",
+		Up:          migrateDecisionAnnotations,
+	},
+	{
+		Version:     29,
+		Description: "add traders.webhook_secret",
+		Up:          migrateTraderWebhookSecret,
+	},
+	{
+		Version:     30,
+		Description: "create strategy_backtest_summaries table",
+		Up:          migrateStrategyBacktestSummaries,
+	},
+}
+
+// Migrations returns all registered migrations in version order
+func Migrations() []Migration {
+	result := make([]Migration, len(migrations))
+	copy(result, migrations)
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
+
+// Migrate applies all pending migrations (idempotent, safe to call on every startup)
+func (s *Store) Migrate() error {
+	return ApplyMigrations(s.gdb, Migrations())
+}
+
+// ApplyMigrations applies pending migrations from list to db, recording them in schema_migrations
+func ApplyMigrations(db *gorm.DB, list []Migration) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := AppliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(list))
+	for _, m := range list {
+		if m.Version <= 0 || seen[m.Version] {
+			return fmt.Errorf("invalid or duplicate migration version: %d", m.Version)
+		}
+		seen[m.Version] = true
+
+		if applied[m.Version] {
+			continue
+		}
+		if err := ApplyMigration(db, m); err != nil {
+			return err
+		}
+		logger.Infof("✅ Applied migration %03d: %s", m.Version, m.Description)
+	}
+	return nil
+}
+
+// ApplyMigration applies a single migration and records it (in one transaction)
+func ApplyMigration(db *gorm.DB, m Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Up(tx); err != nil {
+			return fmt.Errorf("migration %03d (%s) failed: %w", m.Version, m.Description, err)
+		}
+		record := &SchemaMigration{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now().UTC(),
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return fmt.Errorf("failed to record migration %03d: %w", m.Version, err)
+		}
+		return nil
+	})
+}
+
+// AppliedMigrations returns the set of applied migration versions
+func AppliedMigrations(db *gorm.DB) (map[int]bool, error) {
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+	return applied, nil
+}
+
+// ============================================================================
+// Migrations
+// ============================================================================
+
+// migrateDebateTables creates debate arena tables
+func migrateDebateTables(tx *gorm.DB) error {
+	return NewDebateStore(tx).InitSchema()
+}
+
+// migrateTraderQuoteAsset adds quote_asset column to traders (USDT/USDC quoted pairs)
+func migrateTraderQuoteAsset(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "quote_asset") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN quote_asset TEXT DEFAULT 'USDT'`).Error
+}
+
+// migrateTraderDisabled adds the disabled (kill-switch) column to traders
+func migrateTraderDisabled(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "disabled") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN disabled BOOLEAN DEFAULT FALSE`).Error
+}
+
+// migrateTraderFlattenOnStop adds the flatten_on_stop column to traders
+func migrateTraderFlattenOnStop(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "flatten_on_stop") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN flatten_on_stop BOOLEAN DEFAULT FALSE`).Error
+}
+
+// migrateDecisionRepairedResponse adds the repaired AI response column to decision_records
+func migrateDecisionRepairedResponse(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&DecisionRecordDB{}, "repaired_response") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE decision_records ADD COLUMN repaired_response TEXT DEFAULT ''`).Error
+}
+
+// migrateTraderEquityHighWaterMark adds the equity_high_water_mark column to traders
+func migrateTraderEquityHighWaterMark(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "equity_high_water_mark") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN equity_high_water_mark DOUBLE PRECISION DEFAULT 0`).Error
+}
+
+// migrateAPIAuditLogs creates the API request audit log table
+func migrateAPIAuditLogs(tx *gorm.DB) error {
+	return tx.AutoMigrate(&APIAuditLog{})
+}
+
+// migrateTraderPromptLanguage adds the prompt_language column to traders
+func migrateTraderPromptLanguage(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "prompt_language") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN prompt_language TEXT DEFAULT ''`).Error
+}
+
+// migrateTraderConsensus adds the multi-model consensus columns to traders
+func migrateTraderConsensus(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&Trader{}, "consensus_models") {
+		if err := tx.Exec(`ALTER TABLE traders ADD COLUMN consensus_models TEXT DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasColumn(&Trader{}, "consensus_threshold") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN consensus_threshold DOUBLE PRECISION DEFAULT 0`).Error
+}
+
+// migrateEquityAlerts creates the trader_equity_alerts table
+func migrateEquityAlerts(tx *gorm.DB) error {
+	return tx.AutoMigrate(&EquityAlert{})
+}
+
+// migrateDecisionArchivedPayload adds the compressed archive column to decision_records
+func migrateDecisionArchivedPayload(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&DecisionRecordDB{}, "archived_payload") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE decision_records ADD COLUMN archived_payload TEXT DEFAULT ''`).Error
+}
+
+// migrateExchangeExtraAPIKeys adds the additional API keys column to exchanges
+func migrateExchangeExtraAPIKeys(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Exchange{}, "extra_api_keys") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE exchanges ADD COLUMN extra_api_keys TEXT DEFAULT ''`).Error
+}
+
+// migrateDecisionUnavailableSources adds the failed data sources column to decision_records
+func migrateDecisionUnavailableSources(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&DecisionRecordDB{}, "unavailable_sources") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE decision_records ADD COLUMN unavailable_sources TEXT DEFAULT ''`).Error
+}
+
+// migrateTraderAIRequestTimeout adds the AI decision timeout column to traders
+func migrateTraderAIRequestTimeout(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "ai_request_timeout_sec") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN ai_request_timeout_sec INTEGER DEFAULT 0`).Error
+}
+
+// migrateBalanceAdjustments creates the trader_balance_adjustments table
+func migrateBalanceAdjustments(tx *gorm.DB) error {
+	return tx.AutoMigrate(&BalanceAdjustment{})
+}
+
+// migrateTraderDisplayDecimals adds the equity/PnL display precision column to traders
+func migrateTraderDisplayDecimals(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "display_decimals") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN display_decimals INTEGER DEFAULT 0`).Error
+}
+
+// migrateBalanceAdjustmentExternalID adds the exchange transfer ID used to deduplicate imported transfers
+func migrateBalanceAdjustmentExternalID(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&BalanceAdjustment{}, "external_id") {
+		if err := tx.Exec(`ALTER TABLE trader_balance_adjustments ADD COLUMN external_id TEXT DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasIndex(&BalanceAdjustment{}, "idx_balance_adjustment_external") {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(&BalanceAdjustment{}, "idx_balance_adjustment_external")
+}
+
+// migrateExchangeSlippage adds the DEX IOC slippage settings column to exchanges
+func migrateExchangeSlippage(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Exchange{}, "slippage") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE exchanges ADD COLUMN slippage TEXT DEFAULT ''`).Error
+}
+
+// migratePositionLiquidationPrice adds the last known liquidation price column to trader_positions
+func migratePositionLiquidationPrice(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&TraderPosition{}, "liquidation_price") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE trader_positions ADD COLUMN liquidation_price DOUBLE PRECISION DEFAULT 0`).Error
+}
+
+// migrateFundingPayments creates the funding_payments table
+func migrateFundingPayments(tx *gorm.DB) error {
+	return tx.AutoMigrate(&FundingPayment{})
+}
+
+// migrateFillCommissionUSDT adds the quote-currency value of fill commissions; existing fills were
+// recorded in the quote currency, so their commission is copied over
+func migrateFillCommissionUSDT(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&TraderFill{}, "commission_usdt") {
+		if err := tx.Exec(`ALTER TABLE trader_fills ADD COLUMN commission_usdt DOUBLE PRECISION DEFAULT 0`).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec(`UPDATE trader_fills SET commission_usdt = commission
+		WHERE commission_usdt = 0 AND commission_asset IN ('', 'USDT', 'USDC')`).Error
+}
+
+// migrateDecisionTraceID adds the cycle correlation ID column (and its lookup index) to decision_records
+func migrateDecisionTraceID(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&DecisionRecordDB{}, "trace_id") {
+		if err := tx.Exec(`ALTER TABLE decision_records ADD COLUMN trace_id TEXT DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasIndex(&DecisionRecordDB{}, "idx_decision_records_trace") {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(&DecisionRecordDB{}, "idx_decision_records_trace")
+}
+
+// migrateOrderTraceID adds the placing cycle's correlation ID column to trader_orders and trader_fills
+func migrateOrderTraceID(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&TraderOrder{}, "trace_id") {
+		if err := tx.Exec(`ALTER TABLE trader_orders ADD COLUMN trace_id TEXT DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasColumn(&TraderFill{}, "trace_id") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE trader_fills ADD COLUMN trace_id TEXT DEFAULT ''`).Error
+}
+
+// migrateTraderMirrorExchanges adds the exchange accounts a trader replicates its orders on
+func migrateTraderMirrorExchanges(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "mirror_exchange_ids") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN mirror_exchange_ids TEXT DEFAULT ''`).Error
+}
+
+// migrateTraderLogLevel adds the per-trader log level
+func migrateTraderLogLevel(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "log_level") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN log_level TEXT DEFAULT ''`).Error
+}
+
+// migrateUserIsAdmin adds the admin role to users; the built-in "admin" user keeps admin access
+func migrateUserIsAdmin(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&User{}, "is_admin") {
+		if err := tx.Exec(`ALTER TABLE users ADD COLUMN is_admin BOOLEAN DEFAULT FALSE`).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec(`UPDATE users SET is_admin = ? WHERE id = ?`, true, "admin").Error
+}
+
+// migrateArchivedStats creates the table of lifetime totals kept for pruned records
+func migrateArchivedStats(tx *gorm.DB) error {
+	return tx.AutoMigrate(&TraderArchivedStats{})
+}
+
+// migrateDecisionAnnotations adds the user annotation columns (notes, tags) to decision_records
+func migrateDecisionAnnotations(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&DecisionRecordDB{}, "notes") {
+		if err := tx.Exec(`ALTER TABLE decision_records ADD COLUMN notes TEXT DEFAULT ''`).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasColumn(&DecisionRecordDB{}, "tags") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE decision_records ADD COLUMN tags TEXT DEFAULT '[]'`).Error
+}
+
+// migrateTraderWebhookSecret adds the per-trader signal webhook secret
+func migrateTraderWebhookSecret(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&Trader{}, "webhook_secret") {
+		return nil
+	}
+	return tx.Exec(`ALTER TABLE traders ADD COLUMN webhook_secret TEXT DEFAULT ''`).Error
+}
+
+// migrateStrategyBacktestSummaries creates the table of each strategy's last finished backtest
+func migrateStrategyBacktestSummaries(tx *gorm.DB) error {
+	return tx.AutoMigrate(&StrategyBacktestSummary{})
+}
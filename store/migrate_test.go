@@ -0,0 +1,136 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func openTestGorm(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+	db, err := InitGorm(path)
+	if err != nil {
+		t.Fatalf("InitGorm() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestApplyMigrationsOrderAndIdempotence(t *testing.T) {
+	db := openTestGorm(t, filepath.Join(t.TempDir(), "migrate.db"))
+
+	var ran []int
+	step := func(v int) Migration {
+		return Migration{Version: v, Description: "step", Up: func(tx *gorm.DB) error {
+			ran = append(ran, v)
+			return nil
+		}}
+	}
+	// Registered out of order: Migrations() returns them in version order
+	saved := migrations
+	defer func() { migrations = saved }()
+	migrations = []Migration{step(3), step(1), step(2)}
+
+	if err := ApplyMigrations(db, Migrations()); err != nil {
+		t.Fatalf("ApplyMigrations() error = %v", err)
+	}
+	if len(ran) != 3 || ran[0] != 1 || ran[1] != 2 || ran[2] != 3 {
+		t.Fatalf("ran %v, want [1 2 3]", ran)
+	}
+
+	// Second run applies nothing
+	if err := ApplyMigrations(db, Migrations()); err != nil {
+		t.Fatalf("second ApplyMigrations() error = %v", err)
+	}
+	if len(ran) != 3 {
+		t.Errorf("re-run applied migrations again: %v", ran)
+	}
+
+	// A new migration is applied alone
+	migrations = append(migrations, step(4))
+	if err := ApplyMigrations(db, Migrations()); err != nil {
+		t.Fatalf("ApplyMigrations() with new migration error = %v", err)
+	}
+	if len(ran) != 4 || ran[3] != 4 {
+		t.Errorf("ran %v, want only 4 added", ran)
+	}
+}
+
+func TestApplyMigrationsFailureRollsBack(t *testing.T) {
+	db := openTestGorm(t, filepath.Join(t.TempDir(), "migrate.db"))
+
+	failing := Migration{Version: 1, Description: "fails", Up: func(tx *gorm.DB) error {
+		if err := tx.Exec(`CREATE TABLE half_done (id INTEGER)`).Error; err != nil {
+			return err
+		}
+		return errors.New("boom")
+	}}
+	if err := ApplyMigrations(db, []Migration{failing}); err == nil {
+		t.Fatal("expected migration error")
+	}
+	applied, err := AppliedMigrations(db)
+	if err != nil {
+		t.Fatalf("AppliedMigrations() error = %v", err)
+	}
+	if applied[1] {
+		t.Error("failed migration recorded as applied")
+	}
+	if db.Migrator().HasTable("half_done") {
+		t.Error("failed migration's changes not rolled back")
+	}
+}
+
+func TestApplyMigrationsRejectsDuplicateVersions(t *testing.T) {
+	db := openTestGorm(t, filepath.Join(t.TempDir(), "migrate.db"))
+	noop := func(tx *gorm.DB) error { return nil }
+	if err := ApplyMigrations(db, []Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}}); err == nil {
+		t.Error("expected error for duplicate versions")
+	}
+	if err := ApplyMigrations(db, []Migration{{Version: 0, Up: noop}}); err == nil {
+		t.Error("expected error for version 0")
+	}
+}
+
+// TestStoreReopen applies the real migrations to a fresh database, then reopens it:
+// every registered migration is recorded once and re-running them on the existing schema succeeds
+func TestStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nofx.db")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	st.Close()
+
+	st, err = New(path)
+	if err != nil {
+		t.Fatalf("New() on existing database error = %v", err)
+	}
+	defer st.Close()
+
+	applied, err := AppliedMigrations(st.gdb)
+	if err != nil {
+		t.Fatalf("AppliedMigrations() error = %v", err)
+	}
+	for _, m := range Migrations() {
+		if !applied[m.Version] {
+			t.Errorf("migration %d (%s) not applied", m.Version, m.Description)
+		}
+	}
+	if len(applied) != len(Migrations()) {
+		t.Errorf("%d migrations recorded, want %d", len(applied), len(Migrations()))
+	}
+
+	// Every migration must tolerate a schema that already has its changes (e.g. created by initTables)
+	for _, m := range Migrations() {
+		if err := st.gdb.Transaction(m.Up); err != nil {
+			t.Errorf("migration %d (%s) is not idempotent: %v", m.Version, m.Description, err)
+		}
+	}
+}
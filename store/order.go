@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -37,6 +38,7 @@ type TraderOrder struct {
 	PriceProtect      bool    `gorm:"column:price_protect;default:false" json:"price_protect"`
 	OrderAction       string  `gorm:"column:order_action;default:''" json:"order_action"`
 	RelatedPositionID int64   `gorm:"column:related_position_id;default:0" json:"related_position_id"`
+	TraceID           string  `gorm:"column:trace_id;default:''" json:"trace_id,omitempty"` // Correlation ID of the trading cycle that placed it
 	CreatedAt         int64   `gorm:"column:created_at" json:"created_at"`         // Unix milliseconds UTC
 	UpdatedAt         int64   `gorm:"column:updated_at" json:"updated_at"`         // Unix milliseconds UTC
 	FilledAt          int64   `gorm:"column:filled_at" json:"filled_at"`           // Unix milliseconds UTC
@@ -64,8 +66,10 @@ type TraderFill struct {
 	QuoteQuantity   float64 `gorm:"column:quote_quantity;not null" json:"quote_quantity"`
 	Commission      float64 `gorm:"column:commission;not null" json:"commission"`
 	CommissionAsset string  `gorm:"column:commission_asset;not null" json:"commission_asset"`
+	CommissionUSDT  float64 `gorm:"column:commission_usdt;default:0" json:"commission_usdt"` // Commission converted to the quote currency
 	RealizedPnL     float64 `gorm:"column:realized_pnl;default:0" json:"realized_pnl"`
 	IsMaker         bool    `gorm:"column:is_maker;default:false" json:"is_maker"`
+	TraceID         string  `gorm:"column:trace_id;default:''" json:"trace_id,omitempty"` // Correlation ID of the trading cycle that placed the order
 	CreatedAt       int64   `gorm:"column:created_at" json:"created_at"` // Unix milliseconds UTC
 }
 
@@ -195,9 +199,22 @@ func (s *OrderStore) CreateFill(fill *TraderFill) error {
 		return nil
 	}
 
+	// Fees charged in the quote currency need no conversion
+	if fill.CommissionUSDT == 0 && isQuoteCommissionAsset(fill.CommissionAsset) {
+		fill.CommissionUSDT = fill.Commission
+	}
 	return s.db.Create(fill).Error
 }
 
+// isQuoteCommissionAsset reports whether a commission asset is the stablecoin quote currency
+func isQuoteCommissionAsset(asset string) bool {
+	switch strings.ToUpper(strings.TrimSpace(asset)) {
+	case "", "USDT", "USDC":
+		return true
+	}
+	return false
+}
+
 // GetFillByExchangeTradeID gets fill by exchange trade ID
 func (s *OrderStore) GetFillByExchangeTradeID(exchangeID, exchangeTradeID string) (*TraderFill, error) {
 	var fill TraderFill
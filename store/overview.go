@@ -0,0 +1,121 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TraderPerformance trader performance summary (admin overview)
+type TraderPerformance struct {
+	TraderID       string    `json:"trader_id"`
+	TraderName     string    `json:"trader_name"`
+	UserID         string    `json:"user_id"`
+	IsRunning      bool      `json:"is_running"`
+	InitialBalance float64   `json:"initial_balance"`
+	TotalEquity    float64   `json:"total_equity"`
+	TotalPnL       float64   `json:"total_pnl"`
+	TotalPnLPct    float64   `json:"total_pnl_pct"`
+	LastUpdate     time.Time `json:"last_update"`
+}
+
+// SystemOverview system-wide aggregate statistics across all users (admin overview)
+type SystemOverview struct {
+	TotalUsers          int64                `json:"total_users"`
+	TotalTraders        int64                `json:"total_traders"`
+	RunningTraders      int64                `json:"running_traders"`
+	TotalEquity         float64              `json:"total_equity"`
+	TotalInitialBalance float64              `json:"total_initial_balance"`
+	TotalPnL            float64              `json:"total_pnl"`
+	TotalPnLPct         float64              `json:"total_pnl_pct"`
+	ErrorsLastHour      int64                `json:"errors_last_hour"`
+	TopPerformers       []*TraderPerformance `json:"top_performers"`
+	BottomPerformers    []*TraderPerformance `json:"bottom_performers"`
+	GeneratedAt         time.Time            `json:"generated_at"`
+}
+
+// GetSystemOverview aggregates system-wide statistics from the database
+// (does not require traders to be loaded in memory)
+// topN: number of top/bottom performers to return
+func (s *Store) GetSystemOverview(topN int) (*SystemOverview, error) {
+	if topN <= 0 {
+		topN = 5
+	}
+	overview := &SystemOverview{
+		TopPerformers:    []*TraderPerformance{},
+		BottomPerformers: []*TraderPerformance{},
+		GeneratedAt:      time.Now().UTC(),
+	}
+
+	if err := s.gdb.Model(&User{}).Count(&overview.TotalUsers).Error; err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var traders []*Trader
+	err := s.gdb.Model(&Trader{}).
+		Select("id", "name", "user_id", "initial_balance", "is_running").
+		Find(&traders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traders: %w", err)
+	}
+	overview.TotalTraders = int64(len(traders))
+
+	latestEquity, err := s.Equity().GetAllTradersLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	performances := make([]*TraderPerformance, 0, len(traders))
+	for _, t := range traders {
+		if t.IsRunning {
+			overview.RunningTraders++
+		}
+
+		snap, ok := latestEquity[t.ID]
+		if !ok {
+			continue
+		}
+		perf := &TraderPerformance{
+			TraderID:       t.ID,
+			TraderName:     t.Name,
+			UserID:         t.UserID,
+			IsRunning:      t.IsRunning,
+			InitialBalance: t.InitialBalance,
+			TotalEquity:    snap.TotalEquity,
+			TotalPnL:       snap.TotalEquity - t.InitialBalance,
+			LastUpdate:     snap.Timestamp,
+		}
+		if t.InitialBalance > 0 {
+			perf.TotalPnLPct = perf.TotalPnL / t.InitialBalance * 100
+		}
+		performances = append(performances, perf)
+
+		overview.TotalEquity += snap.TotalEquity
+		overview.TotalInitialBalance += t.InitialBalance
+	}
+	overview.TotalPnL = overview.TotalEquity - overview.TotalInitialBalance
+	if overview.TotalInitialBalance > 0 {
+		overview.TotalPnLPct = overview.TotalPnL / overview.TotalInitialBalance * 100
+	}
+
+	// Failed decision cycles in the last hour
+	err = s.gdb.Model(&DecisionRecordDB{}).
+		Where("success = ? AND timestamp >= ?", false, time.Now().UTC().Add(-time.Hour)).
+		Count(&overview.ErrorsLastHour).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent errors: %w", err)
+	}
+
+	// Rank by return rate
+	sort.Slice(performances, func(i, j int) bool {
+		return performances[i].TotalPnLPct > performances[j].TotalPnLPct
+	})
+	for i := 0; i < len(performances) && i < topN; i++ {
+		overview.TopPerformers = append(overview.TopPerformers, performances[i])
+	}
+	for i := len(performances) - 1; i >= 0 && len(overview.BottomPerformers) < topN; i-- {
+		overview.BottomPerformers = append(overview.BottomPerformers, performances[i])
+	}
+
+	return overview, nil
+}
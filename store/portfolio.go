@@ -0,0 +1,204 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PortfolioTrader one trader's contribution to a user's portfolio
+type PortfolioTrader struct {
+	TraderID       string    `json:"trader_id"`
+	TraderName     string    `json:"trader_name"`
+	IsRunning      bool      `json:"is_running"`
+	InitialBalance float64   `json:"initial_balance"`
+	TotalEquity    float64   `json:"total_equity"`
+	TotalPnL       float64   `json:"total_pnl"`
+	TotalPnLPct    float64   `json:"total_pnl_pct"`
+	UnrealizedPnL  float64   `json:"unrealized_pnl"`
+	RealizedPnL    float64   `json:"realized_pnl"`
+	MarginUsed     float64   `json:"margin_used"`
+	OpenPositions  int       `json:"open_positions"`
+	EquitySharePct float64   `json:"equity_share_pct"` // Share of the portfolio's total equity
+	LastUpdate     time.Time `json:"last_update"`      // Zero if the trader has no equity snapshot yet
+}
+
+// PortfolioPoint combined equity of all traders at one point of the curve
+type PortfolioPoint struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TotalEquity   float64   `json:"total_equity"`
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	Traders       int       `json:"traders"` // Traders contributing to this point
+}
+
+// Portfolio aggregate view across all traders of a user
+type Portfolio struct {
+	TotalEquity         float64            `json:"total_equity"`
+	TotalInitialBalance float64            `json:"total_initial_balance"`
+	TotalPnL            float64            `json:"total_pnl"`
+	TotalPnLPct         float64            `json:"total_pnl_pct"`
+	UnrealizedPnL       float64            `json:"unrealized_pnl"`
+	RealizedPnL         float64            `json:"realized_pnl"`
+	MarginUsed          float64            `json:"margin_used"`
+	MarginUsedPct       float64            `json:"margin_used_pct"`
+	OpenPositions       int                `json:"open_positions"`
+	Traders             []*PortfolioTrader `json:"traders"`
+	EquityCurve         []PortfolioPoint   `json:"equity_curve"`
+	CurveBucket         string             `json:"curve_bucket"`
+	GeneratedAt         time.Time          `json:"generated_at"`
+}
+
+// GetUserPortfolio aggregates equity, PnL, margin and open positions across all traders of a user
+// from the database (latest equity snapshots + position records), plus a combined equity curve since `since`.
+// Snapshots are aligned on buckets of the given size: each point sums every trader's last known equity
+// at the end of the bucket, so traders snapshotting at different times still add up.
+func (s *Store) GetUserPortfolio(userID string, since time.Time, bucket time.Duration) (*Portfolio, error) {
+	if bucket <= 0 {
+		return nil, fmt.Errorf("invalid curve bucket: %v", bucket)
+	}
+	portfolio := &Portfolio{
+		Traders:     []*PortfolioTrader{},
+		EquityCurve: []PortfolioPoint{},
+		CurveBucket: bucket.String(),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	var traders []*Trader
+	err := s.gdb.Model(&Trader{}).
+		Select("id", "name", "initial_balance", "is_running").
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&traders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traders: %w", err)
+	}
+	if len(traders) == 0 {
+		return portfolio, nil
+	}
+	traderIDs := make([]string, 0, len(traders))
+	for _, t := range traders {
+		traderIDs = append(traderIDs, t.ID)
+	}
+
+	latestEquity, err := s.Equity().GetLatestForTraders(traderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Realized PnL includes partial closes booked on still-open positions
+	type positionAgg struct {
+		TraderID      string
+		RealizedPnL   float64 `gorm:"column:realized_pnl"`
+		OpenPositions int
+	}
+	var aggs []positionAgg
+	err = s.gdb.Model(&TraderPosition{}).
+		Select("trader_id, COALESCE(SUM(realized_pnl), 0) AS realized_pnl, "+
+			"SUM(CASE WHEN status = 'OPEN' THEN 1 ELSE 0 END) AS open_positions").
+		Where("trader_id IN ?", traderIDs).
+		Group("trader_id").
+		Scan(&aggs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate positions: %w", err)
+	}
+	positionsByTrader := make(map[string]positionAgg, len(aggs))
+	for _, a := range aggs {
+		positionsByTrader[a.TraderID] = a
+	}
+
+	for _, t := range traders {
+		pt := &PortfolioTrader{
+			TraderID:       t.ID,
+			TraderName:     t.Name,
+			IsRunning:      t.IsRunning,
+			InitialBalance: t.InitialBalance,
+			RealizedPnL:    positionsByTrader[t.ID].RealizedPnL,
+			OpenPositions:  positionsByTrader[t.ID].OpenPositions,
+		}
+		if snap, ok := latestEquity[t.ID]; ok {
+			pt.TotalEquity = snap.TotalEquity
+			pt.UnrealizedPnL = snap.UnrealizedPnL
+			pt.MarginUsed = snap.TotalEquity * snap.MarginUsedPct / 100
+			pt.TotalPnL = snap.TotalEquity - t.InitialBalance
+			pt.LastUpdate = snap.Timestamp
+			if t.InitialBalance > 0 {
+				pt.TotalPnLPct = pt.TotalPnL / t.InitialBalance * 100
+			}
+			// Traders without equity data don't count towards the totals
+			portfolio.TotalInitialBalance += t.InitialBalance
+		}
+		portfolio.Traders = append(portfolio.Traders, pt)
+
+		portfolio.TotalEquity += pt.TotalEquity
+		portfolio.UnrealizedPnL += pt.UnrealizedPnL
+		portfolio.RealizedPnL += pt.RealizedPnL
+		portfolio.MarginUsed += pt.MarginUsed
+		portfolio.OpenPositions += pt.OpenPositions
+	}
+	portfolio.TotalPnL = portfolio.TotalEquity - portfolio.TotalInitialBalance
+	if portfolio.TotalInitialBalance > 0 {
+		portfolio.TotalPnLPct = portfolio.TotalPnL / portfolio.TotalInitialBalance * 100
+	}
+	if portfolio.TotalEquity > 0 {
+		portfolio.MarginUsedPct = portfolio.MarginUsed / portfolio.TotalEquity * 100
+		for _, pt := range portfolio.Traders {
+			pt.EquitySharePct = pt.TotalEquity / portfolio.TotalEquity * 100
+		}
+	}
+	sort.SliceStable(portfolio.Traders, func(i, j int) bool {
+		return portfolio.Traders[i].TotalEquity > portfolio.Traders[j].TotalEquity
+	})
+
+	curve, err := s.combinedEquityCurve(traderIDs, since, bucket)
+	if err != nil {
+		return nil, err
+	}
+	portfolio.EquityCurve = curve
+
+	return portfolio, nil
+}
+
+// combinedEquityCurve sums the equity snapshots of several traders aligned on time buckets.
+// Each trader's last known value is carried forward until its next snapshot.
+func (s *Store) combinedEquityCurve(traderIDs []string, since time.Time, bucket time.Duration) ([]PortfolioPoint, error) {
+	type snapshotRow struct {
+		TraderID      string
+		Timestamp     time.Time
+		TotalEquity   float64
+		UnrealizedPnL float64 `gorm:"column:unrealized_pnl"`
+	}
+	var rows []snapshotRow
+	err := s.gdb.Model(&EquitySnapshot{}).
+		Select("trader_id, timestamp, total_equity, unrealized_pnl").
+		Where("trader_id IN ? AND timestamp >= ?", traderIDs, since.UTC()).
+		Order("timestamp ASC, id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity records: %w", err)
+	}
+
+	curve := []PortfolioPoint{}
+	last := make(map[string]snapshotRow, len(traderIDs))
+	emit := func(ts time.Time) {
+		point := PortfolioPoint{Timestamp: ts, Traders: len(last)}
+		for _, r := range last {
+			point.TotalEquity += r.TotalEquity
+			point.UnrealizedPnL += r.UnrealizedPnL
+		}
+		curve = append(curve, point)
+	}
+
+	var current time.Time
+	for i, r := range rows {
+		b := r.Timestamp.UTC().Truncate(bucket)
+		if i > 0 && !b.Equal(current) {
+			emit(current)
+		}
+		current = b
+		last[r.TraderID] = r
+	}
+	if len(rows) > 0 {
+		emit(current)
+	}
+	return curve, nil
+}
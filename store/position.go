@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ type TraderStats struct {
 	SharpeRatio    float64 `json:"sharpe_ratio"`
 	TotalPnL       float64 `json:"total_pnl"`
 	TotalFee       float64 `json:"total_fee"`
+	TotalFunding   float64 `json:"total_funding"` // Net funding received (+) / paid (-), not part of TotalPnL
+	NetPnL         float64 `json:"net_pnl"`       // Realized PnL including funding: TotalPnL + TotalFunding
 	AvgWin         float64 `json:"avg_win"`
 	AvgLoss        float64 `json:"avg_loss"`
 	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
@@ -45,6 +48,7 @@ type TraderPosition struct {
 	RealizedPnL        float64 `gorm:"column:realized_pnl;default:0" json:"realized_pnl"`
 	Fee                float64 `gorm:"column:fee;default:0" json:"fee"`
 	Leverage           int     `gorm:"column:leverage;default:1" json:"leverage"`
+	LiquidationPrice   float64 `gorm:"column:liquidation_price;default:0" json:"liquidation_price"` // Last known, 0 = unknown
 	Status             string  `gorm:"column:status;default:OPEN;index:idx_positions_status" json:"status"`
 	CloseReason        string  `gorm:"column:close_reason;default:''" json:"close_reason"`
 	Source             string  `gorm:"column:source;default:system" json:"source"`
@@ -195,6 +199,15 @@ func (s *PositionStore) ReducePositionQuantity(id int64, reduceQty float64, exit
 	}).Error
 }
 
+// UpdateLeverage records a leverage/margin change of an open position (liquidationPrice 0 = unknown)
+func (s *PositionStore) UpdateLeverage(id int64, leverage int, liquidationPrice float64) error {
+	return s.db.Model(&TraderPosition{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"leverage":          leverage,
+		"liquidation_price": liquidationPrice,
+		"updated_at":        time.Now().UTC().UnixMilli(),
+	}).Error
+}
+
 // UpdatePositionExchangeInfo updates exchange_id and exchange_type
 func (s *PositionStore) UpdatePositionExchangeInfo(id int64, exchangeID, exchangeType string) error {
 	return s.db.Model(&TraderPosition{}).Where("id = ?", id).Updates(map[string]interface{}{
@@ -224,11 +237,22 @@ func (s *PositionStore) ClosePositionFully(id int64, exitPrice float64, exitOrde
 		"realized_pnl":   totalRealizedPnL,
 		"fee":            totalFee,
 		"status":         "CLOSED",
-		"close_reason":   closeReason,
+		"close_reason":   keepCloseReason(closeReason),
 		"updated_at":     time.Now().UTC().UnixMilli(),
 	}).Error
 }
 
+// keepCloseReason sets close_reason unless a reason was already recorded with SetCloseReason
+func keepCloseReason(closeReason string) interface{} {
+	return gorm.Expr("CASE WHEN close_reason IS NULL OR close_reason = '' THEN ? ELSE close_reason END", closeReason)
+}
+
+// SetCloseReason records why the trader closed a position (e.g. "max_hold_time"). Closes are
+// usually recorded later by order sync, which keeps this reason instead of its generic one.
+func (s *PositionStore) SetCloseReason(id int64, closeReason string) error {
+	return s.db.Model(&TraderPosition{}).Where("id = ?", id).Update("close_reason", closeReason).Error
+}
+
 // DeleteAllOpenPositions deletes all OPEN positions for a trader
 func (s *PositionStore) DeleteAllOpenPositions(traderID string) error {
 	return s.db.Where("trader_id = ? AND status = ?", traderID, "OPEN").Delete(&TraderPosition{}).Error
@@ -253,6 +277,19 @@ func (s *PositionStore) GetOpenPositions(traderID string) ([]*TraderPosition, er
 	return positions, nil
 }
 
+// GetSymbolsHeldSince returns the symbols of positions still open or closed at or after sinceMs
+func (s *PositionStore) GetSymbolsHeldSince(traderID string, sinceMs int64) ([]string, error) {
+	var symbols []string
+	err := s.db.Model(&TraderPosition{}).
+		Distinct("symbol").
+		Where("trader_id = ? AND (status = ? OR exit_time >= ?)", traderID, "OPEN", sinceMs).
+		Pluck("symbol", &symbols).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held symbols: %w", err)
+	}
+	return symbols, nil
+}
+
 // GetOpenPositionBySymbol gets open position for specified symbol and direction
 func (s *PositionStore) GetOpenPositionBySymbol(traderID, symbol, side string) (*TraderPosition, error) {
 	var pos TraderPosition
@@ -347,6 +384,7 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 	stats["win_trades"] = r.Wins
 	stats["total_pnl"] = r.TotalPnL
 	stats["total_fee"] = r.TotalFee
+	stats["total_funding"] = s.netFunding(traderID)
 	if r.Total > 0 {
 		stats["win_rate"] = float64(r.Wins) / float64(r.Total) * 100
 	} else {
@@ -356,16 +394,30 @@ func (s *PositionStore) GetPositionStats(traderID string) (map[string]interface{
 	return stats, nil
 }
 
+// RealizedSince realized PnL and fees booked at or after sinceMs (Unix milliseconds): positions
+// closed since then, and open positions (fees, partial closes) opened since then
+func (s *PositionStore) RealizedSince(traderID string, sinceMs int64) (pnl, fee float64, err error) {
+	var r struct {
+		TotalPnL float64 `gorm:"column:total_pnl"`
+		TotalFee float64 `gorm:"column:total_fee"`
+	}
+	err = s.db.Model(&TraderPosition{}).
+		Select("COALESCE(SUM(realized_pnl), 0) as total_pnl, COALESCE(SUM(fee), 0) as total_fee").
+		Where("trader_id = ? AND ((status = ? AND exit_time >= ?) OR (status = ? AND entry_time >= ?))",
+			traderID, "CLOSED", sinceMs, "OPEN", sinceMs).
+		Scan(&r).Error
+	return r.TotalPnL, r.TotalFee, err
+}
+
 // GetFullStats gets complete trading statistics
 func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
-	stats := &TraderStats{}
-
 	var count int64
 	if err := s.db.Model(&TraderPosition{}).Where("trader_id = ? AND status = ?", traderID, "CLOSED").Count(&count).Error; err != nil {
 		return nil, err
 	}
 	if count == 0 {
-		return stats, nil
+		funding := s.netFunding(traderID)
+		return &TraderStats{TotalFunding: funding, NetPnL: funding}, nil
 	}
 
 	var positions []TraderPosition
@@ -376,6 +428,24 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 		return nil, fmt.Errorf("failed to query position statistics: %w", err)
 	}
 
+	stats := calculateTraderStats(positions)
+	stats.TotalFunding = s.netFunding(traderID)
+	stats.NetPnL = stats.TotalPnL + stats.TotalFunding
+	return stats, nil
+}
+
+// netFunding net funding payments of the trader (0 if they can't be read)
+func (s *PositionStore) netFunding(traderID string) float64 {
+	total, err := NewFundingPaymentStore(s.db).NetFunding(traderID)
+	if err != nil {
+		return 0
+	}
+	return total
+}
+
+// calculateTraderStats computes trading statistics of closed positions ordered by exit time
+func calculateTraderStats(positions []TraderPosition) *TraderStats {
+	stats := &TraderStats{}
 	var pnls []float64
 	var totalWin, totalLoss float64
 
@@ -413,7 +483,7 @@ func (s *PositionStore) GetFullStats(traderID string) (*TraderStats, error) {
 		stats.MaxDrawdownPct = calculateMaxDrawdownFromPnls(pnls)
 	}
 
-	return stats, nil
+	return stats
 }
 
 // RecentTrade recent trade record
@@ -442,30 +512,7 @@ func (s *PositionStore) GetRecentTrades(traderID string, limit int) ([]RecentTra
 
 	var trades []RecentTrade
 	for _, pos := range positions {
-		t := RecentTrade{
-			Symbol:      pos.Symbol,
-			Side:        strings.ToLower(pos.Side),
-			EntryPrice:  pos.EntryPrice,
-			ExitPrice:   pos.ExitPrice,
-			RealizedPnL: pos.RealizedPnL,
-			EntryTime:   pos.EntryTime / 1000, // Convert ms to seconds for API compatibility
-		}
-
-		if pos.ExitTime > 0 {
-			t.ExitTime = pos.ExitTime / 1000 // Convert ms to seconds
-			durationMs := pos.ExitTime - pos.EntryTime
-			t.HoldDuration = formatDurationMs(durationMs)
-		}
-
-		if pos.EntryPrice > 0 {
-			if t.Side == "long" {
-				t.PnLPct = (pos.ExitPrice - pos.EntryPrice) / pos.EntryPrice * 100 * float64(pos.Leverage)
-			} else {
-				t.PnLPct = (pos.EntryPrice - pos.ExitPrice) / pos.EntryPrice * 100 * float64(pos.Leverage)
-			}
-		}
-
-		trades = append(trades, t)
+		trades = append(trades, recentTradeFromPosition(pos))
 	}
 
 	return trades, nil
@@ -555,6 +602,33 @@ func calculateMaxDrawdownFromPnls(pnls []float64) float64 {
 	return maxDD
 }
 
+// recentTradeFromPosition converts a closed position to a trade record
+func recentTradeFromPosition(pos TraderPosition) RecentTrade {
+	t := RecentTrade{
+		Symbol:      pos.Symbol,
+		Side:        strings.ToLower(pos.Side),
+		EntryPrice:  pos.EntryPrice,
+		ExitPrice:   pos.ExitPrice,
+		RealizedPnL: pos.RealizedPnL,
+		EntryTime:   pos.EntryTime / 1000, // Convert ms to seconds for API compatibility
+	}
+
+	if pos.ExitTime > 0 {
+		t.ExitTime = pos.ExitTime / 1000 // Convert ms to seconds
+		durationMs := pos.ExitTime - pos.EntryTime
+		t.HoldDuration = formatDurationMs(durationMs)
+	}
+
+	if pos.EntryPrice > 0 {
+		if t.Side == "long" {
+			t.PnLPct = (pos.ExitPrice - pos.EntryPrice) / pos.EntryPrice * 100 * float64(pos.Leverage)
+		} else {
+			t.PnLPct = (pos.EntryPrice - pos.ExitPrice) / pos.EntryPrice * 100 * float64(pos.Leverage)
+		}
+	}
+	return t
+}
+
 // SymbolStats per-symbol trading statistics
 type SymbolStats struct {
 	Symbol      string  `json:"symbol"`
@@ -574,6 +648,11 @@ func (s *PositionStore) GetSymbolStats(traderID string, limit int) ([]SymbolStat
 		return nil, fmt.Errorf("failed to query symbol stats: %w", err)
 	}
 
+	return calculateSymbolStats(positions, limit), nil
+}
+
+// calculateSymbolStats groups closed positions by symbol, sorted by total PnL descending (limit <= 0 = all)
+func calculateSymbolStats(positions []TraderPosition, limit int) []SymbolStats {
 	// Group by symbol
 	symbolMap := make(map[string]*SymbolStats)
 	symbolHoldMins := make(map[string][]float64)
@@ -625,7 +704,105 @@ func (s *PositionStore) GetSymbolStats(traderID string, limit int) ([]SymbolStat
 		stats = stats[:limit]
 	}
 
-	return stats, nil
+	return stats
+}
+
+// PeriodStats trading statistics of positions closed within a time range
+type PeriodStats struct {
+	Stats       *TraderStats  `json:"stats"`
+	Symbols     []SymbolStats `json:"symbols"`
+	BestTrades  []RecentTrade `json:"best_trades"`
+	WorstTrades []RecentTrade `json:"worst_trades"`
+}
+
+// GetPeriodStats gets statistics of positions closed in [startMs, endMs] (Unix milliseconds),
+// with the top best/worst trades by realized PnL
+func (s *PositionStore) GetPeriodStats(traderID string, startMs, endMs int64, topTrades int) (*PeriodStats, error) {
+	var positions []TraderPosition
+	err := s.db.Where("trader_id = ? AND status = ? AND exit_time >= ? AND exit_time <= ?", traderID, "CLOSED", startMs, endMs).
+		Order("exit_time ASC").
+		Find(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query period statistics: %w", err)
+	}
+
+	result := &PeriodStats{
+		Stats:       calculateTraderStats(positions),
+		Symbols:     calculateSymbolStats(positions, 0),
+		BestTrades:  []RecentTrade{},
+		WorstTrades: []RecentTrade{},
+	}
+
+	byPnL := append([]TraderPosition(nil), positions...)
+	sort.SliceStable(byPnL, func(i, j int) bool { return byPnL[i].RealizedPnL > byPnL[j].RealizedPnL })
+	for i := 0; i < len(byPnL) && i < topTrades && byPnL[i].RealizedPnL > 0; i++ {
+		result.BestTrades = append(result.BestTrades, recentTradeFromPosition(byPnL[i]))
+	}
+	for i := len(byPnL) - 1; i >= 0 && len(byPnL)-1-i < topTrades && byPnL[i].RealizedPnL < 0; i-- {
+		result.WorstTrades = append(result.WorstTrades, recentTradeFromPosition(byPnL[i]))
+	}
+	return result, nil
+}
+
+// RealizedPnLPoint realized PnL booked in one time bucket, with the running total
+type RealizedPnLPoint struct {
+	Time          int64   `json:"time"`           // Bucket start, Unix milliseconds UTC
+	RealizedPnL   float64 `json:"realized_pnl"`   // PnL of positions closed in this bucket
+	Fee           float64 `json:"fee"`            // Fees of positions closed in this bucket
+	Trades        int     `json:"trades"`         // Positions closed in this bucket
+	CumulativePnL float64 `json:"cumulative_pnl"` // Realized PnL booked up to the end of this bucket
+}
+
+// GetRealizedPnLSeries aggregates closed-position realized PnL into UTC buckets of bucketMs milliseconds
+// (e.g. hour or day). Only buckets with closes are returned. sinceMs > 0 limits the buckets to closes
+// from that time on; the cumulative total still includes everything booked before it.
+func (s *PositionStore) GetRealizedPnLSeries(traderID string, bucketMs, sinceMs int64) ([]RealizedPnLPoint, error) {
+	if bucketMs <= 0 {
+		return nil, fmt.Errorf("invalid bucket size: %d", bucketMs)
+	}
+
+	var rows []struct {
+		Bucket      int64
+		RealizedPnl float64
+		Fee         float64
+		Trades      int
+	}
+	// exit_time is integer milliseconds, so integer division buckets it the same way on SQLite and PostgreSQL
+	err := s.db.Raw(`SELECT (exit_time / ?) * ? AS bucket,
+			COALESCE(SUM(realized_pnl), 0) AS realized_pnl,
+			COALESCE(SUM(fee), 0) AS fee,
+			COUNT(*) AS trades
+		FROM trader_positions
+		WHERE trader_id = ? AND status = ? AND exit_time > 0 AND exit_time >= ?
+		GROUP BY 1
+		ORDER BY 1`, bucketMs, bucketMs, traderID, "CLOSED", sinceMs).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query realized PnL series: %w", err)
+	}
+
+	var cumulative float64
+	if sinceMs > 0 {
+		err := s.db.Model(&TraderPosition{}).
+			Select("COALESCE(SUM(realized_pnl), 0)").
+			Where("trader_id = ? AND status = ? AND exit_time > 0 AND exit_time < ?", traderID, "CLOSED", sinceMs).
+			Scan(&cumulative).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to query realized PnL before %d: %w", sinceMs, err)
+		}
+	}
+
+	points := make([]RealizedPnLPoint, 0, len(rows))
+	for _, row := range rows {
+		cumulative += row.RealizedPnl
+		points = append(points, RealizedPnLPoint{
+			Time:          row.Bucket,
+			RealizedPnL:   row.RealizedPnl,
+			Fee:           row.Fee,
+			Trades:        row.Trades,
+			CumulativePnL: cumulative,
+		})
+	}
+	return points, nil
 }
 
 // HoldingTimeStats holding duration analysis
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TraderArchivedStats running totals of records removed by the retention pruner,
+// so lifetime statistics stay correct after old rows are deleted
+type TraderArchivedStats struct {
+	TraderID               string    `gorm:"column:trader_id;primaryKey" json:"trader_id"`
+	PrunedCycles           int64     `gorm:"column:pruned_cycles;default:0" json:"pruned_cycles"`
+	PrunedSuccessfulCycles int64     `gorm:"column:pruned_successful_cycles;default:0" json:"pruned_successful_cycles"`
+	PrunedEquitySnapshots  int64     `gorm:"column:pruned_equity_snapshots;default:0" json:"pruned_equity_snapshots"`
+	PrunedFills            int64     `gorm:"column:pruned_fills;default:0" json:"pruned_fills"`
+	PrunedFillVolume       float64   `gorm:"column:pruned_fill_volume;default:0" json:"pruned_fill_volume"`
+	PrunedFillCommission   float64   `gorm:"column:pruned_fill_commission;default:0" json:"pruned_fill_commission"`
+	PrunedFillRealizedPnL  float64   `gorm:"column:pruned_fill_realized_pnl;default:0" json:"pruned_fill_realized_pnl"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+func (TraderArchivedStats) TableName() string { return "trader_archived_stats" }
+
+// RecordPrunePolicy limits how many history rows are kept per trader
+type RecordPrunePolicy struct {
+	MaxAge       time.Duration // Delete rows older than this (0 = no age limit)
+	MaxPerTrader int           // Keep at most this many newest rows per trader and table (0 = no count limit)
+	BatchSize    int           // Rows deleted per transaction (default 500)
+	BatchPause   time.Duration // Pause between batches so other writers can grab the DB lock
+}
+
+// Enabled returns whether any limit is configured
+func (p RecordPrunePolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxPerTrader > 0
+}
+
+// pruneTarget describes one history table handled by the pruner
+type pruneTarget struct {
+	name  string
+	model interface{}
+	// cutoffCond builds the age condition for this table's time column
+	cutoffCond func(cutoff time.Time) (string, interface{})
+	// keepCond excludes rows that must survive pruning (optional)
+	keepCond func(traderID string) (string, []interface{})
+	// archive adds aggregates of the rows about to be deleted to the trader's archived stats
+	archive func(tx *gorm.DB, traderID string, ids []int64) error
+}
+
+// GetArchivedStats returns the aggregates of pruned records for a trader (zero values if nothing was pruned)
+func (s *Store) GetArchivedStats(traderID string) (*TraderArchivedStats, error) {
+	stats := &TraderArchivedStats{TraderID: traderID}
+	err := s.gdb.Where("trader_id = ?", traderID).Limit(1).Find(stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived stats: %w", err)
+	}
+	return stats, nil
+}
+
+// PruneRecords deletes decision records, equity snapshots and fills outside the policy window.
+// Deletion runs in small batches, each in its own transaction together with the archived stats update.
+// Returns deleted row counts by table name.
+func (s *Store) PruneRecords(policy RecordPrunePolicy) (map[string]int64, error) {
+	deleted := make(map[string]int64)
+	if !policy.Enabled() {
+		return deleted, nil
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 500
+	}
+
+	for _, target := range s.pruneTargets() {
+		traderIDs, err := s.distinctTraderIDs(target.model)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list traders for %s: %w", target.name, err)
+		}
+		for _, traderID := range traderIDs {
+			n, err := s.pruneTraderTable(target, traderID, policy)
+			deleted[target.name] += n
+			if err != nil {
+				return deleted, fmt.Errorf("failed to prune %s for trader %s: %w", target.name, traderID, err)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+func (s *Store) pruneTargets() []pruneTarget {
+	return []pruneTarget{
+		{
+			name:  "decision_records",
+			model: &DecisionRecordDB{},
+			cutoffCond: func(cutoff time.Time) (string, interface{}) {
+				return "timestamp < ?", cutoff.UTC()
+			},
+			// Annotated decisions are user-labeled data, never prune them
+			keepCond: func(traderID string) (string, []interface{}) {
+				return "COALESCE(notes, '') = '' AND COALESCE(tags, '[]') IN ('', '[]')", nil
+			},
+			archive: func(tx *gorm.DB, traderID string, ids []int64) error {
+				var agg struct {
+					Total      int64
+					Successful int64
+				}
+				err := tx.Model(&DecisionRecordDB{}).
+					Select("COUNT(*) as total, SUM(CASE WHEN success THEN 1 ELSE 0 END) as successful").
+					Where("id IN ?", ids).
+					Scan(&agg).Error
+				if err != nil {
+					return err
+				}
+				return addArchivedStats(tx, traderID, map[string]interface{}{
+					"pruned_cycles":            gorm.Expr("pruned_cycles + ?", agg.Total),
+					"pruned_successful_cycles": gorm.Expr("pruned_successful_cycles + ?", agg.Successful),
+				})
+			},
+		},
+		{
+			name:  "trader_equity_snapshots",
+			model: &EquitySnapshot{},
+			cutoffCond: func(cutoff time.Time) (string, interface{}) {
+				return "timestamp < ?", cutoff.UTC()
+			},
+			archive: func(tx *gorm.DB, traderID string, ids []int64) error {
+				return addArchivedStats(tx, traderID, map[string]interface{}{
+					"pruned_equity_snapshots": gorm.Expr("pruned_equity_snapshots + ?", len(ids)),
+				})
+			},
+		},
+		{
+			name:  "trader_fills",
+			model: &TraderFill{},
+			cutoffCond: func(cutoff time.Time) (string, interface{}) {
+				return "created_at < ?", cutoff.UTC().UnixMilli()
+			},
+			// Keep the newest fill per exchange/symbol: order sync derives its resume point from it
+			keepCond: func(traderID string) (string, []interface{}) {
+				return "id NOT IN (SELECT MAX(id) FROM trader_fills WHERE trader_id = ? GROUP BY exchange_id, symbol)",
+					[]interface{}{traderID}
+			},
+			archive: func(tx *gorm.DB, traderID string, ids []int64) error {
+				var agg struct {
+					Total      int64
+					Volume     float64
+					Commission float64
+					Realized   float64
+				}
+				err := tx.Model(&TraderFill{}).
+					Select(`COUNT(*) as total,
+						COALESCE(SUM(quote_quantity), 0) as volume,
+						COALESCE(SUM(commission), 0) as commission,
+						COALESCE(SUM(realized_pnl), 0) as realized`).
+					Where("id IN ?", ids).
+					Scan(&agg).Error
+				if err != nil {
+					return err
+				}
+				return addArchivedStats(tx, traderID, map[string]interface{}{
+					"pruned_fills":             gorm.Expr("pruned_fills + ?", agg.Total),
+					"pruned_fill_volume":       gorm.Expr("pruned_fill_volume + ?", agg.Volume),
+					"pruned_fill_commission":   gorm.Expr("pruned_fill_commission + ?", agg.Commission),
+					"pruned_fill_realized_pnl": gorm.Expr("pruned_fill_realized_pnl + ?", agg.Realized),
+				})
+			},
+		},
+	}
+}
+
+// pruneTraderTable deletes one trader's rows outside the window, batch by batch
+func (s *Store) pruneTraderTable(target pruneTarget, traderID string, policy RecordPrunePolicy) (int64, error) {
+	conds, args, err := s.pruneConditions(target, traderID, policy)
+	if err != nil || len(conds) == 0 {
+		return 0, err
+	}
+
+	var deleted int64
+	for {
+		var ids []int64
+		query := s.gdb.Model(target.model).Where("trader_id = ?", traderID).Where(conds, args...)
+		if target.keepCond != nil {
+			keep, keepArgs := target.keepCond(traderID)
+			query = query.Where(keep, keepArgs...)
+		}
+		if err := query.Order("id ASC").Limit(policy.BatchSize).Pluck("id", &ids).Error; err != nil {
+			return deleted, err
+		}
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		err := s.gdb.Transaction(func(tx *gorm.DB) error {
+			if err := target.archive(tx, traderID, ids); err != nil {
+				return fmt.Errorf("failed to archive stats: %w", err)
+			}
+			result := tx.Where("id IN ?", ids).Delete(target.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		if len(ids) < policy.BatchSize {
+			return deleted, nil
+		}
+		if policy.BatchPause > 0 {
+			time.Sleep(policy.BatchPause)
+		}
+	}
+}
+
+// pruneConditions builds "older than MaxAge OR beyond the newest MaxPerTrader rows"
+func (s *Store) pruneConditions(target pruneTarget, traderID string, policy RecordPrunePolicy) (string, []interface{}, error) {
+	var conds string
+	var args []interface{}
+
+	if policy.MaxAge > 0 {
+		cond, arg := target.cutoffCond(time.Now().Add(-policy.MaxAge))
+		conds = cond
+		args = append(args, arg)
+	}
+
+	if policy.MaxPerTrader > 0 {
+		// IDs are auto-increment, so the Nth newest ID marks the count boundary
+		var boundary []int64
+		err := s.gdb.Model(target.model).
+			Where("trader_id = ?", traderID).
+			Order("id DESC").
+			Offset(policy.MaxPerTrader-1).
+			Limit(1).
+			Pluck("id", &boundary).Error
+		if err != nil {
+			return "", nil, err
+		}
+		if len(boundary) > 0 {
+			if conds != "" {
+				conds = "(" + conds + " OR id < ?)"
+			} else {
+				conds = "id < ?"
+			}
+			args = append(args, boundary[0])
+		}
+	}
+
+	return conds, args, nil
+}
+
+// distinctTraderIDs lists trader IDs that have rows in the given table
+func (s *Store) distinctTraderIDs(model interface{}) ([]string, error) {
+	var traderIDs []string
+	err := s.gdb.Model(model).Distinct("trader_id").Pluck("trader_id", &traderIDs).Error
+	return traderIDs, err
+}
+
+// addArchivedStats applies counter increments to a trader's archived stats row, creating it if missing
+func addArchivedStats(tx *gorm.DB, traderID string, updates map[string]interface{}) error {
+	row := TraderArchivedStats{TraderID: traderID}
+	if err := tx.Where(TraderArchivedStats{TraderID: traderID}).FirstOrCreate(&row).Error; err != nil {
+		return err
+	}
+	updates["updated_at"] = time.Now().UTC()
+	return tx.Model(&TraderArchivedStats{}).Where("trader_id = ?", traderID).Updates(updates).Error
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ResetTraderHistory deletes all recorded history of a trader (decisions, orders, fills, positions,
+// equity snapshots, archived stats, balance adjustments, funding payments and equity alerts)
+// in one transaction, keeping its configuration.
+// The equity high-water mark is cleared as well since it was derived from the deleted curve.
+// Returns deleted row counts by table name.
+func (s *Store) ResetTraderHistory(traderID string) (map[string]int64, error) {
+	tables := []struct {
+		name  string
+		model interface{}
+	}{
+		{"decision_records", &DecisionRecordDB{}},
+		{"trader_fills", &TraderFill{}},
+		{"trader_orders", &TraderOrder{}},
+		{"trader_positions", &TraderPosition{}},
+		{"trader_equity_snapshots", &EquitySnapshot{}},
+		{"trader_archived_stats", &TraderArchivedStats{}},
+		{"trader_balance_adjustments", &BalanceAdjustment{}},
+		{"funding_payments", &FundingPayment{}},
+		{"trader_equity_alerts", &EquityAlert{}},
+	}
+
+	deleted := make(map[string]int64, len(tables))
+	err := s.gdb.Transaction(func(tx *gorm.DB) error {
+		for _, t := range tables {
+			result := tx.Where("trader_id = ?", traderID).Delete(t.model)
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete from %s: %w", t.name, result.Error)
+			}
+			deleted[t.name] = result.RowsAffected
+		}
+		return tx.Model(&Trader{}).
+			Where("id = ?", traderID).
+			Update("equity_high_water_mark", 0).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
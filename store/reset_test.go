@@ -0,0 +1,68 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestResetTraderHistory seeds every per-trader table for two traders, resets one, and checks that
+// no table with a trader_id column keeps rows of the reset trader
+func TestResetTraderHistory(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "reset.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer st.Close()
+
+	// Not trader history: debate sessions only reference the trader that executes their decision
+	notHistory := map[string]bool{"debate_sessions": true}
+
+	seed := func(traderID string) {
+		rows := []interface{}{
+			&DecisionRecordDB{TraderID: traderID},
+			&TraderOrder{TraderID: traderID, ExchangeOrderID: traderID + "-order"},
+			&TraderFill{TraderID: traderID, ExchangeTradeID: traderID + "-fill"},
+			&TraderPosition{TraderID: traderID},
+			&EquitySnapshot{TraderID: traderID},
+			&TraderArchivedStats{TraderID: traderID},
+			&BalanceAdjustment{TraderID: traderID},
+			&FundingPayment{TraderID: traderID},
+			&EquityAlert{TraderID: traderID},
+		}
+		for _, row := range rows {
+			if err := st.gdb.Create(row).Error; err != nil {
+				t.Fatalf("seed %T: %v", row, err)
+			}
+		}
+	}
+	seed("t1")
+	seed("t2")
+
+	if _, err := st.ResetTraderHistory("t1"); err != nil {
+		t.Fatalf("ResetTraderHistory() error = %v", err)
+	}
+
+	tables, err := st.gdb.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("GetTables() error = %v", err)
+	}
+	checked := 0
+	for _, table := range tables {
+		if notHistory[table] || !st.gdb.Migrator().HasColumn(table, "trader_id") {
+			continue
+		}
+		checked++
+		var left, kept int64
+		st.gdb.Table(table).Where("trader_id = ?", "t1").Count(&left)
+		st.gdb.Table(table).Where("trader_id = ?", "t2").Count(&kept)
+		if left != 0 {
+			t.Errorf("%s: %d rows of the reset trader left", table, left)
+		}
+		if kept == 0 {
+			t.Errorf("%s: rows of another trader deleted (or table not seeded)", table)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no per-trader tables found")
+	}
+}
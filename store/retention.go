@@ -0,0 +1,146 @@
+package store
+
+import (
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+// RetentionConfig background data retention configuration
+type RetentionConfig struct {
+	// Equity snapshots newer than this window keep full resolution (0 = downsampling disabled)
+	EquityFullResolutionWindow time.Duration
+	// Target resolution for equity snapshots older than the window (e.g. 1h)
+	EquityDownsampleBucket time.Duration
+	// Pruning of decision records, equity snapshots and fills (disabled unless a limit is set)
+	RecordPrune RecordPrunePolicy
+	// Decision records older than this have their prompts/responses compressed into an archive (0 = disabled)
+	DecisionArchiveAge time.Duration
+	// API audit log entries older than this are deleted (0 = keep all)
+	APIAuditMaxAge time.Duration
+	// How often the retention job runs
+	Interval time.Duration
+}
+
+// DefaultRetentionConfig returns the default retention configuration
+// (keep 7 days at full resolution, downsample older equity to hourly, run every hour)
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		EquityFullResolutionWindow: 7 * 24 * time.Hour,
+		EquityDownsampleBucket:     time.Hour,
+		RecordPrune: RecordPrunePolicy{
+			BatchSize:  500,
+			BatchPause: 100 * time.Millisecond,
+		},
+		Interval: time.Hour,
+	}
+}
+
+// RunRetention runs all retention tasks once
+func (s *Store) RunRetention(cfg RetentionConfig) {
+	// Prune first so downsampling doesn't work on rows that are about to be deleted
+	if cfg.RecordPrune.Enabled() {
+		s.pruneRecords(cfg.RecordPrune)
+	}
+	if cfg.EquityFullResolutionWindow > 0 && cfg.EquityDownsampleBucket > 0 {
+		s.downsampleEquity(cfg)
+	}
+	if cfg.DecisionArchiveAge > 0 {
+		s.archiveDecisions(cfg.DecisionArchiveAge)
+	}
+	if cfg.APIAuditMaxAge > 0 {
+		s.pruneAPIAudit(cfg.APIAuditMaxAge)
+	}
+}
+
+// pruneAPIAudit deletes API audit log entries older than maxAge
+func (s *Store) pruneAPIAudit(maxAge time.Duration) {
+	deleted, err := s.APIAudit().DeleteBefore(time.Now().Add(-maxAge))
+	if err != nil {
+		logger.Warnf("⚠️ API audit retention: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Infof("🧹 API audit retention: deleted %d entries older than %v", deleted, maxAge)
+	}
+}
+
+// archiveDecisions compresses the prompts/responses of decision records older than maxAge
+func (s *Store) archiveDecisions(maxAge time.Duration) {
+	archived, err := s.Decision().Archive(time.Now().Add(-maxAge))
+	if err != nil {
+		logger.Warnf("⚠️ Decision archival: %v", err)
+	}
+	if archived > 0 {
+		logger.Infof("🗜️ Decision archival: compressed prompts/responses of %d records older than %v", archived, maxAge)
+	}
+}
+
+// downsampleEquity downsamples equity snapshots of all traders
+func (s *Store) downsampleEquity(cfg RetentionConfig) {
+	traderIDs, err := s.Equity().GetTraderIDs()
+	if err != nil {
+		logger.Warnf("⚠️ Equity retention: %v", err)
+		return
+	}
+
+	olderThan := time.Now().UTC().Add(-cfg.EquityFullResolutionWindow)
+	var total int64
+	for _, traderID := range traderIDs {
+		deleted, err := s.Equity().Downsample(traderID, olderThan, cfg.EquityDownsampleBucket)
+		if err != nil {
+			logger.Warnf("⚠️ Equity retention failed for trader %s: %v", traderID, err)
+			continue
+		}
+		total += deleted
+	}
+	if total > 0 {
+		logger.Infof("🧹 Equity retention: downsampled %d snapshots older than %s to %s resolution",
+			total, olderThan.Format("2006-01-02 15:04"), cfg.EquityDownsampleBucket)
+	}
+}
+
+// pruneRecords deletes history rows outside the configured window
+func (s *Store) pruneRecords(policy RecordPrunePolicy) {
+	deleted, err := s.PruneRecords(policy)
+	if err != nil {
+		logger.Warnf("⚠️ Record pruning: %v", err)
+	}
+	for table, n := range deleted {
+		if n > 0 {
+			logger.Infof("🧹 Record pruning: deleted %d rows from %s (max age: %v, max per trader: %d)",
+				n, table, policy.MaxAge, policy.MaxPerTrader)
+		}
+	}
+}
+
+// StartRetentionJob starts the background retention job, returns a function that stops it
+func (s *Store) StartRetentionJob(cfg RetentionConfig) (stop func()) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+
+	stopCh := make(chan struct{})
+	var once sync.Once
+	go func() {
+		// Run once at startup so long histories shrink without waiting a full interval
+		s.RunRetention(cfg)
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunRetention(cfg)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	logger.Infof("🧹 Data retention job started (equity full resolution: %v, bucket: %v, record max age: %v, max per trader: %d, decision archive age: %v, interval: %v)",
+		cfg.EquityFullResolutionWindow, cfg.EquityDownsampleBucket, cfg.RecordPrune.MaxAge, cfg.RecordPrune.MaxPerTrader, cfg.DecisionArchiveAge, cfg.Interval)
+	return func() {
+		once.Do(func() { close(stopCh) })
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"time"
+)
+
+// SQLite defaults: WAL lets readers run while a trader writes, and the busy timeout makes
+// concurrent writers wait for the lock instead of failing with "database is locked"
+const (
+	DefaultSQLiteJournalMode = "WAL"
+	DefaultSQLiteBusyTimeout = 5 * time.Second
+)
+
+// sqliteJournalModes journal modes accepted in DBConfig.JournalMode.
+// DELETE is the fallback for filesystems without shared memory support (some Docker volume mounts, NFS).
+var sqliteJournalModes = map[string]bool{
+	"WAL":      true,
+	"DELETE":   true,
+	"TRUNCATE": true,
+	"PERSIST":  true,
+}
+
+// sqliteSettings returns the journal mode and busy timeout to use, applying defaults
+func (cfg DBConfig) sqliteSettings() (string, time.Duration, error) {
+	mode := strings.ToUpper(strings.TrimSpace(cfg.JournalMode))
+	if mode == "" {
+		mode = DefaultSQLiteJournalMode
+	}
+	if !sqliteJournalModes[mode] {
+		return "", 0, fmt.Errorf("unsupported SQLite journal mode: %s (use WAL, DELETE, TRUNCATE or PERSIST)", cfg.JournalMode)
+	}
+
+	timeout := cfg.BusyTimeout
+	if timeout < 0 {
+		return "", 0, fmt.Errorf("invalid SQLite busy timeout: %v", timeout)
+	}
+	if timeout == 0 {
+		timeout = DefaultSQLiteBusyTimeout
+	}
+	return mode, timeout, nil
+}
+
+// appendDSNParams appends query parameters to a SQLite path/DSN
+func appendDSNParams(path string, params []string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + strings.Join(params, "&")
+}
+
+// sqliteGormDSN builds the DSN for the GORM driver (mattn/go-sqlite3).
+// Settings in the DSN are applied to every connection the pool opens, unlike a one-off PRAGMA.
+func sqliteGormDSN(path, journalMode string, busyTimeout time.Duration) string {
+	return appendDSNParams(path, []string{
+		"_foreign_keys=1",
+		"_journal_mode=" + journalMode,
+		"_synchronous=FULL",
+		fmt.Sprintf("_busy_timeout=%d", busyTimeout.Milliseconds()),
+	})
+}
+
+// sqlitePragmaDSN builds the DSN for the database/sql driver (modernc.org/sqlite)
+func sqlitePragmaDSN(path, journalMode string, busyTimeout time.Duration) string {
+	// busy_timeout first so the other pragmas already wait for locks
+	return appendDSNParams(path, []string{
+		fmt.Sprintf("_pragma=busy_timeout(%d)", busyTimeout.Milliseconds()),
+		"_pragma=foreign_keys(1)",
+		"_pragma=journal_mode(" + journalMode + ")",
+		"_pragma=synchronous(FULL)",
+	})
+}
+
+// checkJournalMode warns if SQLite didn't switch to the requested journal mode
+// (e.g. WAL is not possible on the filesystem and SQLite silently kept the old mode)
+func checkJournalMode(db *sql.DB, want string) {
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		logger.Warnf("⚠️ Failed to read SQLite journal mode: %v", err)
+		return
+	}
+	if !strings.EqualFold(mode, want) {
+		logger.Warnf("⚠️ SQLite journal mode is %s, requested %s (set DB_JOURNAL_MODE=DELETE if the filesystem doesn't support WAL)", mode, want)
+		return
+	}
+	logger.Infof("✓ SQLite journal mode: %s", strings.ToUpper(mode))
+}
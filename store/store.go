@@ -28,6 +28,10 @@ type Store struct {
 	strategy *StrategyStore
 	equity   *EquityStore
 	order    *OrderStore
+	apiAudit *APIAuditStore
+	alerts   *EquityAlertStore
+	adjusts  *BalanceAdjustmentStore
+	funding  *FundingPaymentStore
 
 	mu sync.RWMutex
 }
@@ -53,6 +57,12 @@ func New(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("failed to initialize table structure: %w", err)
 	}
 
+	// Apply versioned schema migrations
+	if err := s.Migrate(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to apply database migrations: %w", err)
+	}
+
 	// Initialize default data
 	if err := s.initDefaultData(); err != nil {
 		sqlDB.Close()
@@ -84,6 +94,12 @@ func NewWithConfig(cfg DBConfig) (*Store, error) {
 		return nil, fmt.Errorf("failed to initialize table structure: %w", err)
 	}
 
+	// Apply versioned schema migrations
+	if err := s.Migrate(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to apply database migrations: %w", err)
+	}
+
 	// Initialize default data
 	if err := s.initDefaultData(); err != nil {
 		sqlDB.Close()
@@ -279,6 +295,46 @@ func (s *Store) Order() *OrderStore {
 	return s.order
 }
 
+// APIAudit gets API audit log storage
+func (s *Store) APIAudit() *APIAuditStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.apiAudit == nil {
+		s.apiAudit = NewAPIAuditStore(s.gdb)
+	}
+	return s.apiAudit
+}
+
+// EquityAlert gets equity alert rule storage
+func (s *Store) EquityAlert() *EquityAlertStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.alerts == nil {
+		s.alerts = NewEquityAlertStore(s.gdb)
+	}
+	return s.alerts
+}
+
+// BalanceAdjustment gets deposit/withdrawal record storage
+func (s *Store) BalanceAdjustment() *BalanceAdjustmentStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.adjusts == nil {
+		s.adjusts = NewBalanceAdjustmentStore(s.gdb)
+	}
+	return s.adjusts
+}
+
+// FundingPayment gets perpetual funding payment storage
+func (s *Store) FundingPayment() *FundingPaymentStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.funding == nil {
+		s.funding = NewFundingPaymentStore(s.gdb)
+	}
+	return s.funding
+}
+
 // Close closes database connection
 func (s *Store) Close() error {
 	if s.driver != nil {
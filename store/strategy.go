@@ -35,6 +35,9 @@ type StrategyConfig struct {
 	// language setting: "zh" for Chinese, "en" for English
 	// This determines the language used for data formatting and prompt generation
 	Language string `json:"language,omitempty"`
+	// language the AI writes its reasoning in ("en", "zh", "ja", ...); "zh"/"en" also select the
+	// prompt templates. Empty keeps the model's default
+	PromptLanguage string `json:"prompt_language,omitempty"`
 	// coin source configuration
 	CoinSource CoinSourceConfig `json:"coin_source"`
 	// quantitative data configuration
@@ -45,6 +48,27 @@ type StrategyConfig struct {
 	RiskControl RiskControlConfig `json:"risk_control"`
 	// editable sections of System Prompt
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
+	// handling of malformed AI responses
+	ResponseRepair ResponseRepairConfig `json:"response_repair,omitempty"`
+	// prompt size cap, least important context is trimmed to fit
+	ContextBudget ContextBudgetConfig `json:"context_budget,omitempty"`
+}
+
+// ContextBudgetConfig caps the size of the prompt sent to the AI (system + user prompt).
+// When over budget, user prompt sections are trimmed in order: recent trades, market-wide
+// rankings, trading statistics, then candidate coins from the lowest ranked
+type ContextBudgetConfig struct {
+	// max estimated tokens (0 = no limit)
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// max characters (0 = no limit)
+	MaxChars int `json:"max_chars,omitempty"`
+}
+
+// ResponseRepairConfig handling of malformed AI responses
+type ResponseRepairConfig struct {
+	// don't re-prompt the model once for valid JSON when its decision JSON can't be parsed
+	// (the cycle fails immediately instead)
+	DisableReprompt bool `json:"disable_reprompt,omitempty"`
 }
 
 // PromptSectionsConfig editable sections of System Prompt
@@ -59,9 +83,38 @@ type PromptSectionsConfig struct {
 	DecisionProcess string `json:"decision_process,omitempty"`
 }
 
+// CoinSource pluggable candidate coin source type (coin_source.sources[].type)
+type CoinSource string
+
+const (
+	CoinSourceSymbols     CoinSource = "symbols"      // explicit symbol list
+	CoinSourceVolumeTop   CoinSource = "volume_top"   // top N by 24h quote volume
+	CoinSourceGainers     CoinSource = "gainers"      // top N price gainers over a window
+	CoinSourceLosers      CoinSource = "losers"       // top N price losers over a window
+	CoinSourceCoinAnkRank CoinSource = "coinank_rank" // custom CoinAnk instrument ranking query
+)
+
+// CoinSourceSpec one pluggable candidate coin source with its parameters
+type CoinSourceSpec struct {
+	Type CoinSource `json:"type"`
+	// symbols: the explicit symbol list
+	Symbols []string `json:"symbols,omitempty"`
+	// max coins taken from this source (default 10, ignored for symbols)
+	Limit int `json:"limit,omitempty"`
+	// gainers/losers: price change window "5m" | "15m" | "30m" | "1h" | "4h" | "6h" | "12h" | "24h" (default "24h");
+	// windows other than 24h are served by CoinAnk and need coinank_api_key
+	Window string `json:"window,omitempty"`
+	// coinank_rank: ranking list "oi" | "price" | "volume" | "liquidation" | "long_short"
+	Ranking string `json:"ranking,omitempty"`
+	// coinank_rank: CoinAnk sortBy field, e.g. "openInterestCh4", "priceChangeH1", "turnover24h"
+	SortBy string `json:"sort_by,omitempty"`
+	// coinank_rank: "desc" (default) | "asc"
+	SortType string `json:"sort_type,omitempty"`
+}
+
 // CoinSourceConfig coin source configuration
 type CoinSourceConfig struct {
-	// source type: "static" | "ai500" | "oi_top" | "mixed"
+	// source type: "static" | "ai500" | "oi_top" | "mixed" | "custom"
 	SourceType string `json:"source_type"`
 	// static coin list (used when source_type = "static")
 	StaticCoins []string `json:"static_coins,omitempty"`
@@ -75,6 +128,15 @@ type CoinSourceConfig struct {
 	UseOITop bool `json:"use_oi_top"`
 	// OI Top maximum count
 	OITopLimit int `json:"oi_top_limit,omitempty"`
+	// max candidate coins passed to the AI after merging all sources (0 = no limit)
+	MaxCandidates int `json:"max_candidates,omitempty"`
+	// how candidates are ranked before truncating to max_candidates:
+	// "sources" (default, coins from more sources first) | "volume" | "oi_change" | "volatility"
+	CandidateRanking string `json:"candidate_ranking,omitempty"`
+	// pluggable sources: the only sources when source_type = "custom", merged with the others when "mixed"
+	Sources []CoinSourceSpec `json:"sources,omitempty"`
+	// CoinAnk open API key, required by coinank_rank and by gainers/losers windows other than 24h
+	CoinAnkAPIKey string `json:"coinank_api_key,omitempty"`
 	// Note: API URLs are now built automatically using NofxOSAPIKey from IndicatorConfig
 }
 
@@ -82,6 +144,8 @@ type CoinSourceConfig struct {
 type IndicatorConfig struct {
 	// K-line configuration
 	Klines KlineConfig `json:"klines"`
+	// kline interval used to analyze coins ("5m", "15m", "1h", ...), overrides klines.primary_timeframe; empty = primary timeframe
+	AnalysisInterval string `json:"analysis_interval,omitempty"`
 	// raw kline data (OHLCV) - always enabled, required for AI analysis
 	EnableRawKlines bool `json:"enable_raw_klines"`
 	// technical indicator switches
@@ -127,6 +191,10 @@ type IndicatorConfig struct {
 	EnablePriceRanking   bool   `json:"enable_price_ranking"`             // whether to enable price ranking data
 	PriceRankingDuration string `json:"price_ranking_duration,omitempty"` // durations: "1h" or "1h,4h,24h"
 	PriceRankingLimit    int    `json:"price_ranking_limit,omitempty"`    // number of entries per ranking (default 10)
+
+	// Order book depth (top-N bid/ask levels of positions and top candidates, fetched from the exchange)
+	EnableOrderBook bool `json:"enable_order_book"`          // whether to include order book imbalance
+	OrderBookDepth  int  `json:"order_book_depth,omitempty"` // levels per side (default 10)
 }
 
 // KlineConfig K-line configuration
@@ -165,6 +233,8 @@ type RiskControlConfig struct {
 	BTCETHMaxLeverage int `json:"btc_eth_max_leverage"`
 	// Altcoin exchange leverage for opening positions (AI guided)
 	AltcoinMaxLeverage int `json:"altcoin_max_leverage"`
+	// Hard leverage ceiling for every symbol, requests above it are clamped (CODE ENFORCED, 0 = no ceiling)
+	MaxLeverage int `json:"max_leverage,omitempty"`
 
 	// BTC/ETH single position max value = equity × this ratio (CODE ENFORCED, default: 5)
 	BTCETHMaxPositionValueRatio float64 `json:"btc_eth_max_position_value_ratio"`
@@ -173,6 +243,12 @@ type RiskControlConfig struct {
 
 	// Max margin utilization (e.g. 0.9 = 90%) (CODE ENFORCED)
 	MaxMarginUsage float64 `json:"max_margin_usage"`
+	// Portfolio margin guard: block new opens while margin usage (%) exceeds this, e.g. 80 (CODE ENFORCED, 0 = use MaxMarginUsage)
+	MaxTotalMarginUsedPct float64 `json:"max_total_margin_used_pct,omitempty"`
+	// Keep this % of available balance free when sizing new positions, e.g. 20 (CODE ENFORCED, 0 = size up to 98% of max affordable)
+	MinFreeMarginPct float64 `json:"min_free_margin_pct,omitempty"`
+	// Block opens that would push |long - short| notional beyond this % of equity in their direction, e.g. 150 (CODE ENFORCED, 0 = disabled)
+	MaxNetDirectionalExposurePct float64 `json:"max_net_directional_exposure_pct,omitempty"`
 	// Min position size in USDT (CODE ENFORCED)
 	MinPositionSize float64 `json:"min_position_size"`
 
@@ -180,8 +256,123 @@ type RiskControlConfig struct {
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio"`
 	// Min AI confidence to open position (AI guided)
 	MinConfidence int `json:"min_confidence"`
+
+	// ATR-based stop loss: entry ∓ multiplier × ATR, used when AI leaves stop_loss unset or "auto" (0 = disabled)
+	ATRStopLossMultiplier float64 `json:"atr_stop_loss_multiplier,omitempty"`
+	// ATR-based take profit: entry ± multiplier × ATR, used when AI leaves take_profit unset or "auto" (0 = disabled)
+	ATRTakeProfitMultiplier float64 `json:"atr_take_profit_multiplier,omitempty"`
+	// Timeframe of the ATR used for SL/TP (default: primary timeframe)
+	ATRTimeframe string `json:"atr_timeframe,omitempty"`
+
+	// Position sizing model (CODE ENFORCED, default: fixed_usd = use AI's position_size_usd)
+	SizingModel string `json:"sizing_model,omitempty"`
+	// fixed_fraction: position value = equity × fraction (default: 0.1)
+	SizingEquityFraction float64 `json:"sizing_equity_fraction,omitempty"`
+	// volatility_target: equity fraction a 1×ATR move should gain/lose (default: 0.01)
+	SizingVolatilityTarget float64 `json:"sizing_volatility_target,omitempty"`
+	// kelly: fraction of full Kelly to use (default: 0.5 = half Kelly)
+	SizingKellyScale float64 `json:"sizing_kelly_scale,omitempty"`
+	// kelly: max fraction of equity per position (default: 0.25)
+	SizingKellyMaxFraction float64 `json:"sizing_kelly_max_fraction,omitempty"`
+
+	// Volatility scaling: position size × (reference ATR% / symbol ATR%), applied after the sizing model (CODE ENFORCED)
+	VolatilityScaling bool `json:"volatility_scaling,omitempty"`
+	// ATR% (ATR / price × 100) at which the size is left unchanged (default: 2)
+	VolatilityReferenceATRPct float64 `json:"volatility_reference_atr_pct,omitempty"`
+	// Timeframe of the klines the ATR is computed from (default: 1h)
+	VolatilityTimeframe string `json:"volatility_timeframe,omitempty"`
+	// Bounds of the size multiplier (default: 0.25 – 2)
+	VolatilityMinMultiplier float64 `json:"volatility_min_multiplier,omitempty"`
+	VolatilityMaxMultiplier float64 `json:"volatility_max_multiplier,omitempty"`
+
+	// Correlated symbol groups with a cap on same-direction positions per group (CODE ENFORCED)
+	CorrelationGroups []CorrelationGroup `json:"correlation_groups,omitempty"`
+
+	// UTC time windows in which new positions may be opened; closes and SL/TP management are unaffected (CODE ENFORCED, empty = always)
+	TradingWindows []TradingWindow `json:"trading_windows,omitempty"`
+
+	// Auto-flip: opening against an opposite position closes it first, then opens the new side (CODE ENFORCED)
+	AutoFlip bool `json:"auto_flip,omitempty"`
+
+	// Drawdown alert: notify when account equity falls this far (%) below its high-water mark (0 = disabled)
+	DrawdownAlertPct float64 `json:"drawdown_alert_pct,omitempty"`
+
+	// Max hold time: positions held longer than this many minutes are closed (CODE ENFORCED, 0 = disabled)
+	MaxHoldMinutes int `json:"max_hold_minutes,omitempty"`
+
+	// Breakeven stop: once position P&L (% of margin) reaches this, the stop loss moves to entry + fees (CODE ENFORCED, 0 = disabled)
+	BreakevenTriggerPct float64 `json:"breakeven_trigger_pct,omitempty"`
+
+	// Exit order type for stop loss / take profit: "market" (default, market order on trigger) or "limit"
+	// (stop-limit / take-profit-limit at ExitLimitOffsetPct beyond the trigger). Exchanges without
+	// stop-limit support fall back to market triggers.
+	ExitOrderType string `json:"exit_order_type,omitempty"`
+	// Limit offset from the trigger price in % for limit exits (default 0.2)
+	ExitLimitOffsetPct float64 `json:"exit_limit_offset_pct,omitempty"`
+
+	// Attach stop loss / take profit to the entry order (exchange-native bracket) so a position is never
+	// left without a stop if the process dies right after opening. Only market exits can be attached;
+	// exchanges without support use the regular open-then-protect flow.
+	AttachSLTPToEntry bool `json:"attach_sltp_to_entry,omitempty"`
+
+	// Iceberg exit: full closes (flatten, drawdown/max-hold closes) of positions above this notional are
+	// split into sequential reduce-only slices to limit market impact (CODE ENFORCED, 0 = disabled)
+	IcebergExitThresholdUSD float64 `json:"iceberg_exit_threshold_usd,omitempty"`
+	// Fraction of the position closed per slice (default 0.25)
+	IcebergSliceFraction float64 `json:"iceberg_slice_fraction,omitempty"`
+	// Delay between slices in milliseconds (default 1000)
+	IcebergSliceDelayMs int `json:"iceberg_slice_delay_ms,omitempty"`
+
+	// Sliced entries (TWAP-lite): market opens above this notional are split into child orders placed a
+	// short interval apart and aggregated into one position, to limit slippage on thin books (CODE ENFORCED, 0 = disabled)
+	SlicedEntryThresholdUSD float64 `json:"sliced_entry_threshold_usd,omitempty"`
+	// Number of child orders (default 4, max 20; fewer when a child would fall below the exchange minimum)
+	SlicedEntryCount int `json:"sliced_entry_count,omitempty"`
+	// Delay between child orders in milliseconds (default 2000, max 10000)
+	SlicedEntryIntervalMs int `json:"sliced_entry_interval_ms,omitempty"`
+
+	// Post-only entries: the AI may request maker-limit opens ("post_only": true) resting at the best
+	// bid/ask to earn maker fees instead of paying taker fees. Exchanges without post-only support open
+	// with a market order.
+	PostOnlyEntries bool `json:"post_only_entries,omitempty"`
+	// Seconds a post-only entry may rest before the unfilled part is cancelled (default 60)
+	PostOnlyTimeoutSec int `json:"post_only_timeout_sec,omitempty"`
+
+	// Max open orders per symbol: before a new stop loss / take profit is placed, stale protective orders
+	// (orphans, duplicates) are cancelled so the new order stays within the limit (CODE ENFORCED, default 10)
+	MaxOpenOrdersPerSymbol int `json:"max_open_orders_per_symbol,omitempty"`
+
+	// Losing streak cool-off: after this many consecutive losing trades, new trading pauses for
+	// LossCooldownMinutes counted from the last loss; a winning trade resets the streak (CODE ENFORCED, 0 = disabled)
+	MaxConsecutiveLosses int `json:"max_consecutive_losses,omitempty"`
+	// Cool-off length in minutes after a losing streak (default 60)
+	LossCooldownMinutes int `json:"loss_cooldown_minutes,omitempty"`
+}
+
+// CorrelationGroup symbols that move together and count as one concentrated bet
+type CorrelationGroup struct {
+	Name    string   `json:"name"`    // e.g. "majors"
+	Symbols []string `json:"symbols"` // e.g. ["BTCUSDT", "ETHUSDT", "SOLUSDT"]
+	// Max concurrent positions in the same direction within the group (0 = no limit)
+	MaxSameDirection int `json:"max_same_direction"`
+}
+
+// TradingWindow UTC time range in which new positions may be opened
+type TradingWindow struct {
+	Start string `json:"start"` // "HH:MM" UTC, inclusive
+	End   string `json:"end"`   // "HH:MM" UTC, exclusive; End <= Start wraps past midnight
+	// weekdays the window starts on ("mon" ... "sun"), empty = every day
+	Days []string `json:"days,omitempty"`
 }
 
+// Position sizing models (RiskControlConfig.SizingModel)
+const (
+	SizingModelFixedUSD         = "fixed_usd"         // AI decides position_size_usd (capped by risk control)
+	SizingModelFixedFraction    = "fixed_fraction"    // Fixed fraction of equity
+	SizingModelVolatilityTarget = "volatility_target" // Size inversely proportional to ATR
+	SizingModelKelly            = "kelly"             // Capped Kelly from historical win rate / payoff
+)
+
 // NewStrategyStore creates a new StrategyStore
 func NewStrategyStore(db *gorm.DB) *StrategyStore {
 	return &StrategyStore{db: db}
@@ -340,7 +531,14 @@ func (s *StrategyStore) Delete(userID, id string) error {
 		return fmt.Errorf("cannot delete system default strategy")
 	}
 
-	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Strategy{}).Error
+	result := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Strategy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return s.DeleteBacktestSummaries(id)
+	}
+	return nil
 }
 
 // List get user's strategy list
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StrategyBacktestSummary last finished backtest result of a strategy, one row per user and parameter set
+type StrategyBacktestSummary struct {
+	StrategyID     string    `gorm:"column:strategy_id;primaryKey" json:"strategy_id"`
+	UserID         string    `gorm:"column:user_id;primaryKey" json:"user_id"`
+	ParamsHash     string    `gorm:"column:params_hash;primaryKey" json:"params_hash"`
+	RunID          string    `gorm:"column:run_id;not null" json:"run_id"`
+	ConfigHash     string    `gorm:"column:config_hash;not null;default:''" json:"config_hash"` // Strategy config hash at run start
+	State          string    `gorm:"column:state;default:''" json:"state"`
+	Symbols        string    `gorm:"column:symbols;default:'[]'" json:"-"` // JSON array
+	StartTS        int64     `gorm:"column:start_ts;default:0" json:"start_ts"`
+	EndTS          int64     `gorm:"column:end_ts;default:0" json:"end_ts"`
+	InitialBalance float64   `gorm:"column:initial_balance;default:0" json:"initial_balance"`
+	EquityLast     float64   `gorm:"column:equity_last;default:0" json:"equity_last"`
+	MaxDrawdownPct float64   `gorm:"column:max_drawdown_pct;default:0" json:"max_drawdown_pct"`
+	Metrics        string    `gorm:"column:metrics;default:''" json:"-"` // JSON backtest metrics
+	CompletedAt    time.Time `gorm:"column:completed_at" json:"completed_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (StrategyBacktestSummary) TableName() string { return "strategy_backtest_summaries" }
+
+// HashStrategyConfig returns a content hash of a strategy config JSON.
+// The JSON is re-encoded first so key order and whitespace don't change the hash.
+func HashStrategyConfig(configJSON string) string {
+	data := []byte(configJSON)
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err == nil {
+		if canonical, err := json.Marshal(v); err == nil {
+			data = canonical
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveBacktestSummary stores (or replaces) the summary for a strategy, user and parameter set
+func (s *StrategyStore) SaveBacktestSummary(summary *StrategyBacktestSummary) error {
+	summary.UpdatedAt = time.Now().UTC()
+	return s.db.Save(summary).Error
+}
+
+// GetBacktestSummary returns a user's summary for a strategy and parameter set,
+// or the most recently completed one when paramsHash is empty. Returns nil if none exists.
+func (s *StrategyStore) GetBacktestSummary(userID, strategyID, paramsHash string) (*StrategyBacktestSummary, error) {
+	var summary StrategyBacktestSummary
+	query := s.db.Where("strategy_id = ? AND user_id = ?", strategyID, userID)
+	if paramsHash != "" {
+		query = query.Where("params_hash = ?", paramsHash)
+	}
+	err := query.Order("completed_at DESC").First(&summary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// DeleteBacktestSummaries removes all cached backtest summaries of a strategy
+func (s *StrategyStore) DeleteBacktestSummaries(strategyID string) error {
+	return s.db.Where("strategy_id = ?", strategyID).Delete(&StrategyBacktestSummary{}).Error
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"nofx/crypto"
 	"time"
 
 	"gorm.io/gorm"
@@ -30,6 +31,18 @@ type Trader struct {
 	IsRunning           bool      `gorm:"column:is_running;default:false" json:"is_running"`
 	IsCrossMargin       bool      `gorm:"column:is_cross_margin;default:true" json:"is_cross_margin"`
 	ShowInCompetition   bool      `gorm:"column:show_in_competition;default:true" json:"show_in_competition"`
+	QuoteAsset          string    `gorm:"column:quote_asset;default:USDT" json:"quote_asset"`          // Stablecoin quote asset: USDT or USDC
+	Disabled            bool      `gorm:"column:disabled;default:false" json:"disabled"`               // Kill-switch: never started (not even on restart) until re-enabled
+	FlattenOnStop       bool      `gorm:"column:flatten_on_stop;default:false" json:"flatten_on_stop"` // Close all positions and cancel orders when stopped by the user
+	EquityHighWaterMark float64   `gorm:"column:equity_high_water_mark;default:0" json:"equity_high_water_mark"` // Highest account equity seen (0 = not tracked yet)
+	PromptLanguage      string    `gorm:"column:prompt_language;default:''" json:"prompt_language"`              // AI reasoning language, overrides the strategy's (empty = use strategy)
+	ConsensusModels     string    `gorm:"column:consensus_models;default:''" json:"consensus_models"`            // Extra AI model IDs voting each cycle, comma-separated with optional ":weight" (e.g. "m1:2,m2")
+	ConsensusThreshold  float64   `gorm:"column:consensus_threshold;default:0" json:"consensus_threshold"`       // Share of vote weight needed to execute (0 = simple majority)
+	AIRequestTimeoutSec int       `gorm:"column:ai_request_timeout_sec;default:0" json:"ai_request_timeout_sec"` // Max seconds to wait for the cycle's AI decision (0 = default 120s)
+	DisplayDecimals     int       `gorm:"column:display_decimals;default:0" json:"display_decimals"`             // Decimal places of equity/PnL amounts (0 = chosen from account size)
+	MirrorExchangeIDs   string    `gorm:"column:mirror_exchange_ids;default:''" json:"mirror_exchange_ids"`      // Extra exchange account IDs every order is replicated on, comma-separated (empty = single exchange)
+	LogLevel            string    `gorm:"column:log_level;default:''" json:"log_level"`                          // Log level of the trader's lines: debug/info/warn/error (empty = global level)
+	WebhookSecret       crypto.EncryptedString `gorm:"column:webhook_secret;default:''" json:"-"`                // HMAC secret of signed signal webhooks (empty = webhooks disabled)
 	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt           time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
@@ -97,6 +110,39 @@ func (s *TraderStore) UpdateStatus(userID, id string, isRunning bool) error {
 		Update("is_running", isRunning).Error
 }
 
+// SetDisabled sets the trader kill-switch. Disabling also clears the running flag,
+// so the trader stays stopped across restarts until it is explicitly re-enabled.
+func (s *TraderStore) SetDisabled(userID, id string, disabled bool) error {
+	updates := map[string]interface{}{"disabled": disabled}
+	if disabled {
+		updates["is_running"] = false
+	}
+	result := s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("trader %s not found", id)
+	}
+	return nil
+}
+
+// SetWebhookSecret sets the HMAC secret of the trader's signal webhook (empty disables webhooks)
+func (s *TraderStore) SetWebhookSecret(userID, id, secret string) error {
+	result := s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("webhook_secret", crypto.EncryptedString(secret))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("trader %s not found", id)
+	}
+	return nil
+}
+
 // UpdateShowInCompetition updates trader competition visibility
 func (s *TraderStore) UpdateShowInCompetition(userID, id string, showInCompetition bool) error {
 	return s.db.Model(&Trader{}).
@@ -116,6 +162,18 @@ func (s *TraderStore) Update(trader *Trader) error {
 		"strategy_id":    trader.StrategyID,
 		"is_cross_margin": trader.IsCrossMargin,
 		"show_in_competition": trader.ShowInCompetition,
+		"flatten_on_stop":     trader.FlattenOnStop,
+		"prompt_language":     trader.PromptLanguage,
+		"consensus_models":    trader.ConsensusModels,
+		"consensus_threshold": trader.ConsensusThreshold,
+		"ai_request_timeout_sec": trader.AIRequestTimeoutSec,
+		"display_decimals": trader.DisplayDecimals,
+		"mirror_exchange_ids": trader.MirrorExchangeIDs,
+		"log_level": trader.LogLevel,
+	}
+
+	if trader.QuoteAsset != "" {
+		updates["quote_asset"] = trader.QuoteAsset
 	}
 
 	// Only update these if > 0
@@ -141,6 +199,13 @@ func (s *TraderStore) UpdateInitialBalance(userID, id string, newBalance float64
 		Update("initial_balance", newBalance).Error
 }
 
+// UpdateEquityHighWaterMark updates the persisted account equity high-water mark
+func (s *TraderStore) UpdateEquityHighWaterMark(id string, hwm float64) error {
+	return s.db.Model(&Trader{}).
+		Where("id = ?", id).
+		Update("equity_high_water_mark", hwm).Error
+}
+
 // UpdateCustomPrompt updates custom prompt
 func (s *TraderStore) UpdateCustomPrompt(userID, id string, customPrompt string, overrideBase bool) error {
 	return s.db.Model(&Trader{}).
@@ -155,6 +220,9 @@ func (s *TraderStore) UpdateCustomPrompt(userID, id string, customPrompt string,
 func (s *TraderStore) Delete(userID, id string) error {
 	// Delete associated equity snapshots first
 	s.db.Where("trader_id = ?", id).Delete(&EquitySnapshot{})
+	NewEquityAlertStore(s.db).DeleteByTrader(id)
+	s.db.Where("trader_id = ?", id).Delete(&BalanceAdjustment{})
+	s.db.Where("trader_id = ?", id).Delete(&FundingPayment{})
 
 	// Delete the trader
 	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Trader{}).Error
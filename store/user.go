@@ -20,6 +20,7 @@ type User struct {
 	PasswordHash string    `gorm:"column:password_hash;not null" json:"-"`
 	OTPSecret    string    `gorm:"column:otp_secret" json:"-"`
 	OTPVerified  bool      `gorm:"column:otp_verified;default:false" json:"otp_verified"`
+	IsAdmin      bool      `gorm:"column:is_admin;default:false" json:"is_admin"` // Can use the /api/admin routes
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -127,6 +128,41 @@ func (s *UserStore) UpdatePassword(userID, passwordHash string) error {
 	}).Error
 }
 
+// IsAdmin reports whether the user has the admin role
+func (s *UserStore) IsAdmin(userID string) (bool, error) {
+	var user User
+	err := s.db.Select("is_admin").Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return false, err
+	}
+	return user.IsAdmin, nil
+}
+
+// SyncAdmins makes the registered users with the given (lower-case) emails the only admins, besides
+// the built-in admin user, returning how many users were promoted and demoted
+func (s *UserStore) SyncAdmins(emails []string) (promoted, demoted int, err error) {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if len(emails) > 0 {
+			result := tx.Model(&User{}).Where("LOWER(email) IN ? AND is_admin = ?", emails, false).Update("is_admin", true)
+			if result.Error != nil {
+				return result.Error
+			}
+			promoted = int(result.RowsAffected)
+		}
+		query := tx.Model(&User{}).Where("is_admin = ? AND id <> ?", true, "admin")
+		if len(emails) > 0 {
+			query = query.Where("LOWER(email) NOT IN ?", emails)
+		}
+		result := query.Update("is_admin", false)
+		if result.Error != nil {
+			return result.Error
+		}
+		demoted = int(result.RowsAffected)
+		return nil
+	})
+	return promoted, demoted, err
+}
+
 // EnsureAdmin ensures admin user exists
 func (s *UserStore) EnsureAdmin() error {
 	var count int64
@@ -140,5 +176,6 @@ func (s *UserStore) EnsureAdmin() error {
 		PasswordHash: "",
 		OTPSecret:    "",
 		OTPVerified:  true,
+		IsAdmin:      true,
 	})
 }
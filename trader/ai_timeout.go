@@ -0,0 +1,42 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+// defaultAIRequestTimeout bounds a cycle's AI decision when the trader doesn't configure one
+const defaultAIRequestTimeout = 120 * time.Second
+
+// aiRequestTimeout returns the configured AI decision timeout, or the default
+func (at *AutoTrader) aiRequestTimeout() time.Duration {
+	if at.config.AIRequestTimeout > 0 {
+		return at.config.AIRequestTimeout
+	}
+	return defaultAIRequestTimeout
+}
+
+// requestDecisionWithTimeout runs requestDecision under the trader's AI request timeout, so a hung
+// AI endpoint fails the cycle instead of stalling the trader. The abandoned call keeps running until
+// its own HTTP timeout and its late result is dropped; the next cycle builds a fresh context.
+func (at *AutoTrader) requestDecisionWithTimeout(ctx *kernel.Context) (*kernel.FullDecision, map[string][]store.ConsensusVote, error) {
+	type decisionResult struct {
+		decision *kernel.FullDecision
+		votes    map[string][]store.ConsensusVote
+		err      error
+	}
+
+	timeout := at.aiRequestTimeout()
+	result, err := fetchWithTimeout(context.Background(), timeout, func() decisionResult {
+		decision, votes, err := at.requestDecision(ctx)
+		return decisionResult{decision: decision, votes: votes, err: err}
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("AI request timed out after %v: %w", timeout, err)
+	}
+	return result.decision, result.votes, result.err
+}
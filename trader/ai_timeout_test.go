@@ -0,0 +1,18 @@
+package trader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAIRequestTimeout(t *testing.T) {
+	at := &AutoTrader{}
+	if got := at.aiRequestTimeout(); got != defaultAIRequestTimeout {
+		t.Errorf("unset timeout = %v, want default %v", got, defaultAIRequestTimeout)
+	}
+
+	at.config.AIRequestTimeout = 45 * time.Second
+	if got := at.aiRequestTimeout(); got != 45*time.Second {
+		t.Errorf("configured timeout = %v, want 45s", got)
+	}
+}
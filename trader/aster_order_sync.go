@@ -2,7 +2,6 @@ package trader
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -22,7 +21,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Aster trades from: %s", startTime.Format(time.RFC3339))
+	t.log().Infof("🔄 Syncing Aster trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 500)
@@ -30,7 +29,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Aster", len(trades))
+	t.log().Infof("📥 Received %d trades from Aster", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -41,6 +40,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 	syncedCount := 0
 
 	for _, trade := range trades {
@@ -68,6 +68,9 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 		// Normalize side for storage
 		side := strings.ToUpper(trade.Side)
 
+		// Fees charged in the base coin or a discount token are valued in the quote currency
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.Price)
+
 		// Create order record - use Unix milliseconds UTC
 		tradeTimeMs := trade.Time.UTC().UnixMilli()
 		orderRecord := &store.TraderOrder{
@@ -75,6 +78,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 			ExchangeID:      exchangeID,   // UUID
 			ExchangeType:    exchangeType, // Exchange type
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			Symbol:          symbol,
 			Side:            side,
 			PositionSide:    "BOTH", // Aster uses one-way position mode
@@ -85,7 +89,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 			Status:          "FILLED",
 			FilledQuantity:  trade.Quantity,
 			AvgFillPrice:    trade.Price,
-			Commission:      trade.Fee,
+			Commission:      feeUSDT,
 			FilledAt:        tradeTimeMs,
 			CreatedAt:       tradeTimeMs,
 			UpdatedAt:       tradeTimeMs,
@@ -93,7 +97,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -104,6 +108,7 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 			ExchangeType:    exchangeType, // Exchange type
 			OrderID:         orderRecord.ID,
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			ExchangeTradeID: trade.TradeID,
 			Symbol:          symbol,
 			Side:            side,
@@ -111,34 +116,35 @@ func (t *AsterTrader) SyncOrdersFromAster(traderID string, exchangeID string, ex
 			Quantity:        trade.Quantity,
 			QuoteQuantity:   trade.Price * trade.Quantity,
 			Commission:      trade.Fee,
-			CommissionAsset: "USDT",
+			CommissionAsset: commissionAsset(trade.FeeAsset),
+			CommissionUSDT:  feeUSDT,
 			RealizedPnL:     trade.RealizedPnL,
 			IsMaker:         false,
 			CreatedAt:       tradeTimeMs,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			symbol, positionSide, orderAction,
-			trade.Quantity, trade.Price, trade.Fee, trade.RealizedPnL,
+			trade.Quantity, trade.Price, feeUSDT, trade.RealizedPnL,
 			tradeTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.Quantity, trade.Price, trade.RealizedPnL, trade.Fee, orderAction)
 	}
 
-	logger.Infof("✅ Aster order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Aster order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
@@ -179,15 +185,7 @@ func deriveAsterOrderAction(side, positionSide string, realizedPnL float64) stri
 	}
 }
 
-// StartOrderSync starts background order sync task for Aster
-func (t *AsterTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromAster(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Infof("⚠️  Aster order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Aster order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Aster
+func (t *AsterTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromAster(traderID, exchangeID, exchangeType, st)
 }
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"nofx/logger"
 	"math"
 	"math/big"
 	"net/http"
@@ -25,14 +24,20 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// asterDefaultRecvWindow recvWindow (ms) of signed requests when none is configured
+const asterDefaultRecvWindow = 50000
+
 // AsterTrader Aster trading platform implementation
 type AsterTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	ctx        context.Context
 	user       string            // Main wallet address (ERC20)
 	signer     string            // API wallet address
 	privateKey *ecdsa.PrivateKey // API wallet private key
 	client     *http.Client
 	baseURL    string
+	clock      *ClockSync
 
 	// Cache symbol precision information
 	symbolPrecision map[string]SymbolPrecision
@@ -70,6 +75,7 @@ func NewAsterTrader(user, signer, privateKeyHex string) (*AsterTrader, error) {
 		client = res.GetResult()
 	}
 
+	baseURL := "https://fapi.asterdex.com"
 	return &AsterTrader{
 		ctx:             context.Background(),
 		user:            user,
@@ -77,10 +83,25 @@ func NewAsterTrader(user, signer, privateKeyHex string) (*AsterTrader, error) {
 		privateKey:      privKey,
 		symbolPrecision: make(map[string]SymbolPrecision),
 		client:          client,
-		baseURL:         "https://fapi.asterdex.com",
+		baseURL:         baseURL,
+		clock:           NewClockSync("Aster", httpServerTimeFetcher(client, baseURL+"/fapi/v1/time", parseAsterServerTime)),
 	}, nil
 }
 
+// parseAsterServerTime parses /fapi/v1/time: {"serverTime":1499827319559}
+func parseAsterServerTime(body []byte) (int64, error) {
+	var resp struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, err
+	}
+	if resp.ServerTime == 0 {
+		return 0, fmt.Errorf("invalid Aster server time response: %s", string(body))
+	}
+	return resp.ServerTime, nil
+}
+
 // genNonce Generate microsecond timestamp
 func (t *AsterTrader) genNonce() uint64 {
 	return uint64(time.Now().UnixMicro())
@@ -268,9 +289,9 @@ func (t *AsterTrader) normalize(v interface{}) (interface{}, error) {
 
 // sign Sign request parameters
 func (t *AsterTrader) sign(params map[string]interface{}, nonce uint64) error {
-	// Add timestamp and receive window
-	params["recvWindow"] = "50000"
-	params["timestamp"] = strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	// Add timestamp (exchange clock) and receive window
+	params["recvWindow"] = strconv.FormatInt(recvWindowOr(asterDefaultRecvWindow), 10)
+	params["timestamp"] = strconv.FormatInt(t.clock.Now().UnixMilli(), 10)
 
 	// Normalize parameters to JSON string
 	jsonStr, err := t.normalizeAndStringify(params)
@@ -469,13 +490,13 @@ func (t *AsterTrader) GetBalance() (map[string]interface{}, error) {
 	}
 
 	if !foundUSDT {
-		logger.Infof("⚠️  USDT asset record not found!")
+		t.log().Infof("⚠️  USDT asset record not found!")
 	}
 
 	// Get positions to calculate margin used and real unrealized PnL
 	positions, err := t.GetPositions()
 	if err != nil {
-		logger.Infof("⚠️  Failed to get position information: %v", err)
+		t.log().Infof("⚠️  Failed to get position information: %v", err)
 		// fallback: use simple calculation when unable to get positions
 		return map[string]interface{}{
 			"totalWalletBalance":    crossWalletBalance,
@@ -577,7 +598,7 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
 	}
 
 	// Set leverage first (non-fatal if position already exists)
@@ -585,7 +606,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 		// Error -2030: Cannot adjust leverage when position exists
 		// This is expected when adding to an existing position, continue with current leverage
 		if strings.Contains(err.Error(), "-2030") {
-			logger.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
+			t.log().Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
 		} else {
 			return nil, fmt.Errorf("failed to set leverage: %w", err)
 		}
@@ -620,7 +641,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log().Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -650,7 +671,7 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
 	}
 
 	// Set leverage first (non-fatal if position already exists)
@@ -658,7 +679,7 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 		// Error -2030: Cannot adjust leverage when position exists
 		// This is expected when adding to an existing position, continue with current leverage
 		if strings.Contains(err.Error(), "-2030") {
-			logger.Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
+			t.log().Infof("  ⚠ Cannot change leverage (position exists), using current leverage: %v", err)
 		} else {
 			return nil, fmt.Errorf("failed to set leverage: %w", err)
 		}
@@ -693,7 +714,7 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log().Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -738,7 +759,7 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		if quantity == 0 {
 			return nil, fmt.Errorf("no long position found for %s", symbol)
 		}
-		logger.Infof("  📊 Retrieved long position quantity: %.8f", quantity)
+		t.log().Infof("  📊 Retrieved long position quantity: %.8f", quantity)
 	}
 
 	price, err := t.GetMarketPrice(symbol)
@@ -768,7 +789,7 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log().Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -791,11 +812,11 @@ func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]int
 		return nil, err
 	}
 
-	logger.Infof("✓ Successfully closed long position: %s quantity: %s", symbol, qtyStr)
+	t.log().Infof("✓ Successfully closed long position: %s quantity: %s", symbol, qtyStr)
 
 	// Cancel all pending orders for this symbol after closing position (stop-loss/take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return result, nil
@@ -821,7 +842,7 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		if quantity == 0 {
 			return nil, fmt.Errorf("no short position found for %s", symbol)
 		}
-		logger.Infof("  📊 Retrieved short position quantity: %.8f", quantity)
+		t.log().Infof("  📊 Retrieved short position quantity: %.8f", quantity)
 	}
 
 	price, err := t.GetMarketPrice(symbol)
@@ -851,7 +872,7 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 	priceStr := t.formatFloatWithPrecision(formattedPrice, prec.PricePrecision)
 	qtyStr := t.formatFloatWithPrecision(formattedQty, prec.QuantityPrecision)
 
-	logger.Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
+	t.log().Infof("  📏 Precision handling: price %.8f -> %s (precision=%d), quantity %.8f -> %s (precision=%d)",
 		limitPrice, priceStr, prec.PricePrecision, quantity, qtyStr, prec.QuantityPrecision)
 
 	params := map[string]interface{}{
@@ -874,11 +895,11 @@ func (t *AsterTrader) CloseShort(symbol string, quantity float64) (map[string]in
 		return nil, err
 	}
 
-	logger.Infof("✓ Successfully closed short position: %s quantity: %s", symbol, qtyStr)
+	t.log().Infof("✓ Successfully closed short position: %s quantity: %s", symbol, qtyStr)
 
 	// Cancel all pending orders for this symbol after closing position (stop-loss/take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	return result, nil
@@ -904,30 +925,30 @@ func (t *AsterTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 		// Ignore error if it indicates no need to change
 		if strings.Contains(err.Error(), "No need to change") ||
 			strings.Contains(err.Error(), "Margin type cannot be changed") {
-			logger.Infof("  ✓ %s margin mode is already %s or cannot be changed due to existing positions", symbol, marginType)
+			t.log().Infof("  ✓ %s margin mode is already %s or cannot be changed due to existing positions", symbol, marginType)
 			return nil
 		}
 		// Detect multi-assets mode (error code -4168)
 		if strings.Contains(err.Error(), "Multi-Assets mode") ||
 			strings.Contains(err.Error(), "-4168") ||
 			strings.Contains(err.Error(), "4168") {
-			logger.Infof("  ⚠️ %s detected multi-assets mode, forcing cross margin mode", symbol)
-			logger.Infof("  💡 Tip: To use isolated margin mode, please disable multi-assets mode on the exchange")
+			t.log().Infof("  ⚠️ %s detected multi-assets mode, forcing cross margin mode", symbol)
+			t.log().Infof("  💡 Tip: To use isolated margin mode, please disable multi-assets mode on the exchange")
 			return nil
 		}
 		// Detect unified account API
 		if strings.Contains(err.Error(), "unified") ||
 			strings.Contains(err.Error(), "portfolio") ||
 			strings.Contains(err.Error(), "Portfolio") {
-			logger.Infof("  ❌ %s detected unified account API, cannot perform futures trading", symbol)
+			t.log().Infof("  ❌ %s detected unified account API, cannot perform futures trading", symbol)
 			return fmt.Errorf("please use 'Spot & Futures Trading' API permission, not 'Unified Account API'")
 		}
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		t.log().Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Don't return error, let trading continue
 		return nil
 	}
 
-	logger.Infof("  ✓ %s margin mode has been set to %s", symbol, marginType)
+	t.log().Infof("  ✓ %s margin mode has been set to %s", symbol, marginType)
 	return nil
 }
 
@@ -1087,19 +1108,19 @@ func (t *AsterTrader) CancelStopLossOrders(symbol string) error {
 			if err != nil {
 				errMsg := fmt.Sprintf("order ID %d: %v", int64(orderID), err)
 				cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-				logger.Infof("  ⚠ Failed to cancel stop-loss order: %s", errMsg)
+				t.log().Infof("  ⚠ Failed to cancel stop-loss order: %s", errMsg)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled stop-loss order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
+			t.log().Infof("  ✓ Canceled stop-loss order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s no stop-loss orders to cancel", symbol)
+		t.log().Infof("  ℹ %s no stop-loss orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	// Return error if all cancellations failed
@@ -1146,19 +1167,19 @@ func (t *AsterTrader) CancelTakeProfitOrders(symbol string) error {
 			if err != nil {
 				errMsg := fmt.Sprintf("order ID %d: %v", int64(orderID), err)
 				cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-				logger.Infof("  ⚠ Failed to cancel take-profit order: %s", errMsg)
+				t.log().Infof("  ⚠ Failed to cancel take-profit order: %s", errMsg)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled take-profit order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
+			t.log().Infof("  ✓ Canceled take-profit order (order ID: %d, type: %s, direction: %s)", int64(orderID), orderType, positionSide)
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s no take-profit orders to cancel", symbol)
+		t.log().Infof("  ℹ %s no take-profit orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
 	}
 
 	// Return error if all cancellations failed
@@ -1169,6 +1190,23 @@ func (t *AsterTrader) CancelTakeProfitOrders(symbol string) error {
 	return nil
 }
 
+// CancelOrder Cancel a single order by order ID
+func (t *AsterTrader) CancelOrder(symbol string, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+
+	params := map[string]interface{}{
+		"symbol":  symbol,
+		"orderId": id,
+	}
+	if _, err := t.request("DELETE", "/fapi/v3/order", params); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
 // CancelAllOrders Cancel all orders
 func (t *AsterTrader) CancelAllOrders(symbol string) error {
 	params := map[string]interface{}{
@@ -1215,20 +1253,20 @@ func (t *AsterTrader) CancelStopOrders(symbol string) error {
 
 			_, err := t.request("DELETE", "/fapi/v3/order", cancelParams)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order %d: %v", int64(orderID), err)
+				t.log().Infof("  ⚠ Failed to cancel order %d: %v", int64(orderID), err)
 				continue
 			}
 
 			canceledCount++
-			logger.Infof("  ✓ Canceled take-profit/stop-loss order for %s (order ID: %d, type: %s)",
+			t.log().Infof("  ✓ Canceled take-profit/stop-loss order for %s (order ID: %d, type: %s)",
 				symbol, int64(orderID), orderType)
 		}
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ %s no take-profit/stop-loss orders to cancel", symbol)
+		t.log().Infof("  ℹ %s no take-profit/stop-loss orders to cancel", symbol)
 	} else {
-		logger.Infof("  ✓ Canceled %d take-profit/stop-loss order(s) for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d take-profit/stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	return nil
@@ -1351,18 +1389,19 @@ func (t *AsterTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLR
 
 // AsterTradeRecord represents a trade from Aster API
 type AsterTradeRecord struct {
-	ID           int64  `json:"id"`
-	Symbol       string `json:"symbol"`
-	OrderID      int64  `json:"orderId"`
-	Side         string `json:"side"`         // BUY or SELL
-	PositionSide string `json:"positionSide"` // LONG or SHORT
-	Price        string `json:"price"`
-	Qty          string `json:"qty"`
-	RealizedPnl  string `json:"realizedPnl"`
-	Commission   string `json:"commission"`
-	Time         int64  `json:"time"`
-	Buyer        bool   `json:"buyer"`
-	Maker        bool   `json:"maker"`
+	ID              int64  `json:"id"`
+	Symbol          string `json:"symbol"`
+	OrderID         int64  `json:"orderId"`
+	Side            string `json:"side"`         // BUY or SELL
+	PositionSide    string `json:"positionSide"` // LONG or SHORT
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	RealizedPnl     string `json:"realizedPnl"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	Buyer           bool   `json:"buyer"`
+	Maker           bool   `json:"maker"`
 }
 
 // GetTrades retrieves trade history from Aster
@@ -1380,13 +1419,13 @@ func (t *AsterTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord,
 	// Use existing request method with signing
 	body, err := t.request("GET", "/fapi/v3/userTrades", params)
 	if err != nil {
-		logger.Infof("⚠️  Aster userTrades API error: %v", err)
+		t.log().Infof("⚠️  Aster userTrades API error: %v", err)
 		return []TradeRecord{}, nil
 	}
 
 	var asterTrades []AsterTradeRecord
 	if err := json.Unmarshal(body, &asterTrades); err != nil {
-		logger.Infof("⚠️  Failed to parse Aster trades response: %v", err)
+		t.log().Infof("⚠️  Failed to parse Aster trades response: %v", err)
 		return []TradeRecord{}, nil
 	}
 
@@ -1400,6 +1439,7 @@ func (t *AsterTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord,
 
 		trade := TradeRecord{
 			TradeID:      strconv.FormatInt(at.ID, 10),
+			OrderID:      strconv.FormatInt(at.OrderID, 10),
 			Symbol:       at.Symbol,
 			Side:         at.Side,
 			PositionSide: at.PositionSide,
@@ -1407,6 +1447,7 @@ func (t *AsterTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord,
 			Quantity:     qty,
 			RealizedPnL:  pnl,
 			Fee:          fee,
+			FeeAsset:     at.CommissionAsset,
 			Time:         time.UnixMilli(at.Time).UTC(),
 		}
 		result = append(result, trade)
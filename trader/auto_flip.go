@@ -0,0 +1,107 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+// oppositeSideForOpen returns the position side an open action would flip ("long"/"short"), or "" for other actions
+func oppositeSideForOpen(action string) string {
+	switch action {
+	case "open_long":
+		return "short"
+	case "open_short":
+		return "long"
+	}
+	return ""
+}
+
+// autoFlipEnabled reports whether the strategy closes opposite positions before opening (risk_control.auto_flip)
+func (at *AutoTrader) autoFlipEnabled() bool {
+	return at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.AutoFlip
+}
+
+// autoFlipBeforeOpen closes the opposite position of an open decision when auto-flip is enabled,
+// and waits until the close order is filled so the new side can be opened right after.
+// Returns the action record of the close leg (nil if nothing had to be flipped); on error the
+// new position must not be opened.
+func (at *AutoTrader) autoFlipBeforeOpen(decision *kernel.Decision) (*store.DecisionAction, error) {
+	side := oppositeSideForOpen(decision.Action)
+	if side == "" || !at.autoFlipEnabled() {
+		return nil, nil
+	}
+	// Don't close the opposite side if the open itself will be rejected by the trading window
+	if at.enforceTradingWindow(time.Now()) != nil {
+		return nil, nil
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	hasOpposite := false
+	for _, pos := range positions {
+		if pos["symbol"] == decision.Symbol && pos["side"] == side {
+			hasOpposite = true
+			break
+		}
+	}
+	if !hasOpposite {
+		return nil, nil
+	}
+
+	closeAction := "close_" + side
+	at.log().Infof("  🔁 Auto-flip: %s has a %s position, closing it before %s", decision.Symbol, side, decision.Action)
+
+	leg := &store.DecisionAction{
+		Action:     closeAction,
+		Symbol:     decision.Symbol,
+		Confidence: decision.Confidence,
+		Reasoning:  fmt.Sprintf("auto-flip before %s", decision.Action),
+		Timestamp:  time.Now().UTC(),
+	}
+	fail := func(err error) (*store.DecisionAction, error) {
+		leg.Error = err.Error()
+		return leg, err
+	}
+
+	marketData, err := market.Get(decision.Symbol)
+	if err != nil {
+		return fail(err)
+	}
+	leg.Price = marketData.CurrentPrice
+
+	quantity, entryPrice := at.openPositionSize(decision.Symbol, side)
+	leg.Quantity = quantity
+
+	var order map[string]interface{}
+	if side == "long" {
+		order, err = at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
+	} else {
+		order, err = at.trader.CloseShort(decision.Symbol, 0)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("auto-flip close failed: %w", err))
+	}
+	if orderID, ok := order["orderId"].(int64); ok {
+		leg.OrderID = orderID
+	}
+
+	at.recordAndConfirmOrder(order, decision.Symbol, closeAction, quantity, marketData.CurrentPrice, 0, entryPrice)
+
+	// Only open the new side once the old one is really gone
+	if orderID := orderIDString(order); orderID != "" && orderID != "0" {
+		status := at.confirmOrderFill(decision.Symbol, orderID)
+		statusStr, _ := status["status"].(string)
+		if statusStr != "FILLED" {
+			return fail(fmt.Errorf("auto-flip close order %s not filled (status %q), %s skipped", orderID, statusStr, decision.Action))
+		}
+	}
+
+	leg.Success = true
+	at.log().Infof("  ✓ Auto-flip: %s %s position closed", decision.Symbol, side)
+	return leg, nil
+}
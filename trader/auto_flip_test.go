@@ -0,0 +1,34 @@
+package trader
+
+import "testing"
+
+func TestOppositeSideForOpen(t *testing.T) {
+	cases := map[string]string{
+		"open_long":   "short",
+		"open_short":  "long",
+		"close_long":  "",
+		"reduce_long": "",
+		"hold":        "",
+	}
+	for action, want := range cases {
+		if got := oppositeSideForOpen(action); got != want {
+			t.Errorf("oppositeSideForOpen(%q) = %q, want %q", action, got, want)
+		}
+	}
+}
+
+func TestOrderIDString(t *testing.T) {
+	cases := []struct {
+		result map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"orderId": int64(123456789012)}, "123456789012"},
+		{map[string]interface{}{"orderId": float64(42)}, "42"},
+		{map[string]interface{}{"orderId": "a1b2-c3"}, "a1b2-c3"},
+	}
+	for _, c := range cases {
+		if got := orderIDString(c.result); got != c.want {
+			t.Errorf("orderIDString(%v) = %q, want %q", c.result, got, c.want)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package trader
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"nofx/kernel"
@@ -9,6 +11,7 @@ import (
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
+	"nofx/provider/nofxos"
 	"nofx/store"
 	"strings"
 	"sync"
@@ -23,21 +26,25 @@ type AutoTraderConfig struct {
 	AIModel string // AI model: "qwen" or "deepseek"
 
 	// Trading platform selection
-	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "hyperliquid", "aster" or "lighter"
+	Exchange   string // Exchange type: "binance", "bybit", "okx", "bitget", "hyperliquid", "aster", "lighter", "coinbase" or "backpack"
 	ExchangeID string // Exchange account UUID (for multi-account support)
+	QuoteAsset string // Stablecoin quote asset of traded pairs and balances: USDT ("" = USDT) or USDC
 
 	// Binance API configuration
 	BinanceAPIKey    string
 	BinanceSecretKey string
+	BinanceTestnet   bool
 
 	// Bybit API configuration
 	BybitAPIKey    string
 	BybitSecretKey string
+	BybitTestnet   bool
 
 	// OKX API configuration
 	OKXAPIKey    string
 	OKXSecretKey string
 	OKXPassphrase string
+	OKXTestnet    bool
 
 	// Gate.io API configuration
 	GateAPIKey    string
@@ -47,6 +54,25 @@ type AutoTraderConfig struct {
 	BitgetAPIKey    string
 	BitgetSecretKey string
 	BitgetPassphrase string
+	BitgetTestnet    bool
+
+	// Coinbase International API configuration
+	CoinbaseAPIKey     string
+	CoinbaseSecretKey  string
+	CoinbasePassphrase string
+
+	// Backpack API configuration (ED25519 key pair, base64 encoded)
+	BackpackAPIKey    string
+	BackpackSecretKey string
+
+	// Additional API keys of the exchange account, rotated with the primary key (OKX, Bitget, Coinbase, Backpack)
+	ExtraAPIKeys []store.APIKeyCredential
+
+	// IOC limit offset of DEX market orders (Hyperliquid, Lighter), zero = exchange default
+	Slippage store.SlippageSettings
+
+	// Exchange accounts every order is replicated on besides the primary one (empty = single exchange)
+	MirrorExchanges []*store.Exchange
 
 	// Hyperliquid configuration
 	HyperliquidPrivateKey string
@@ -86,6 +112,21 @@ type AutoTraderConfig struct {
 	MaxDrawdown     float64       // Maximum drawdown percentage (hint)
 	StopTradingTime time.Duration // Pause duration after risk control triggers
 
+	// Order sync (retry / health tracking for background exchange order sync)
+	OrderSync OrderSyncConfig
+
+	// Order fill confirmation (poll attempts / interval, user data stream)
+	OrderFill OrderFillConfig
+
+	// External signals (webhook / API), max accepted per minute (0 = default 6)
+	SignalRateLimitPerMinute int
+
+	// Max change (%) of an auto-fetched initial balance vs the last recorded equity before it's rejected (0 = no check)
+	BalanceAnomalyPct float64
+
+	// Persisted account equity high-water mark (0 = seeded from the first equity reading)
+	EquityHighWaterMark float64
+
 	// Position mode
 	IsCrossMargin bool // true=cross margin mode, false=isolated margin mode
 
@@ -94,6 +135,32 @@ type AutoTraderConfig struct {
 
 	// Strategy configuration (use complete strategy config)
 	StrategyConfig *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
+
+	// Language the AI reasons in, overrides the strategy's prompt_language (empty = use strategy)
+	PromptLanguage string
+
+	// Multi-model consensus: extra models voting on each cycle's decisions (empty = disabled)
+	ConsensusModels []ConsensusModelConfig
+	// Share of the total vote weight (primary model weighs 1) an action needs to execute (0 = more than half)
+	ConsensusThreshold float64
+
+	// Max time to wait for the cycle's AI decision before failing the cycle (0 = default 120s)
+	AIRequestTimeout time.Duration
+
+	// Decimal places of equity/PnL amounts in account responses and logs (0 = chosen from account size)
+	DisplayDecimals int
+
+	// Log level of this trader's lines: debug, info, warn, error ("" = global level)
+	LogLevel string
+
+	// Trading mode variant of the system prompt (kernel.PromptVariants, "" = balanced)
+	PromptVariant string
+
+	// Interval of importing the exchange's deposit/withdrawal history into balance adjustments (0 = disabled)
+	TransferReconcileInterval time.Duration
+
+	// Interval of importing the exchange's funding payment history of held symbols (0 = disabled)
+	FundingReconcileInterval time.Duration
 }
 
 // AutoTrader automatic trader
@@ -107,15 +174,21 @@ type AutoTrader struct {
 	config                AutoTraderConfig
 	trader                Trader // Use Trader interface (supports multiple platforms)
 	mcpClient             mcp.AIClient
+	consensusVoters       []consensusVoter // Extra models voting on decisions (multi-model consensus)
 	store                 *store.Store             // Data storage (decision records, etc.)
 	strategyEngine        *kernel.StrategyEngine // Strategy engine (uses strategy configuration)
 	cycleNumber           int                      // Current cycle number
 	initialBalance        float64
 	dailyPnL              float64
+	lastMarginUsedPct     float64 // Margin usage (%) at last cycle
 	customPrompt          string // Custom trading strategy prompt
 	overrideBasePrompt    bool   // Whether to override base prompt
 	lastResetTime         time.Time
 	stopUntil             time.Time
+	stopReason            string          // Why trading is paused until stopUntil
+	stopMu                sync.RWMutex    // Guards stopUntil and stopReason (read by GetStatus)
+	lossStreak            lossStreakState // Consecutive losing trades (risk_control.max_consecutive_losses)
+	lossStreakMu          sync.Mutex
 	isRunning             bool
 	isRunningMutex        sync.RWMutex       // Mutex to protect isRunning flag
 	startTime             time.Time          // System start time
@@ -125,8 +198,26 @@ type AutoTrader struct {
 	monitorWg             sync.WaitGroup     // Used to wait for monitoring goroutine to finish
 	peakPnLCache          map[string]float64 // Peak profit cache (symbol -> peak P&L percentage)
 	peakPnLCacheMutex     sync.RWMutex       // Cache read-write lock
+	breakevenMoved        map[string]float64 // Positions whose stop was moved to breakeven (symbol_side -> entry price)
+	breakevenMu           sync.Mutex
 	lastBalanceSyncTime   time.Time          // Last balance sync time
 	userID                string             // User ID
+	orderSyncHealth       *OrderSyncHealth   // Order sync health (nil if exchange has no order sync)
+	orderSyncTrigger      chan struct{}      // Requests an immediate order sync (nil if exchange has no order sync)
+	tpLadders             map[string][]kernel.TakeProfitLevel // Active take profit ladders (symbol_side -> levels)
+	tpLaddersMutex        sync.Mutex
+	makerEntries          map[string]*makerEntry // Resting post-only entries (order ID -> entry)
+	makerEntriesMu        sync.Mutex
+	signalCh              chan Signal        // External signals handled between scan intervals
+	signalLimiter         *signalRateLimiter // Rate limit for incoming signals
+	equity                equityTracker      // Equity high-water mark / drawdown tracking
+	equityMu              sync.RWMutex
+	orderBooks            orderBookCache // Order books fetched this cycle (indicators.enable_order_book)
+	traderLog             *logger.TraderLogger // Trader's logger, see log()
+	traceCtx              context.Context // Current cycle's context, carrying its correlation ID
+	execTraceCtx          context.Context // Context of a running out-of-cycle execution (manual open), preferred over traceCtx
+	traceMu               sync.RWMutex
+	execMu                sync.Mutex // Serializes cycles with out-of-cycle executions (manual opens)
 }
 
 // NewAutoTrader creates an automatic trader
@@ -139,6 +230,11 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	if config.Name == "" {
 		config.Name = "Default Trader"
 	}
+	levelErr := logger.SetTraderLevel(config.ID, config.LogLevel)
+	log := logger.ForTrader(config.ID).Entry(nil)
+	if levelErr != nil {
+		log.Warnf("⚠️ [%s] %v, using the global log level", config.Name, levelErr)
+	}
 	if config.AIModel == "" {
 		if config.UseQwen {
 			config.AIModel = "qwen"
@@ -158,27 +254,27 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	case "claude":
 		mcpClient = mcp.NewClaudeClient()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using Claude AI", config.Name)
+		log.Infof("🤖 [%s] Using Claude AI", config.Name)
 
 	case "kimi":
 		mcpClient = mcp.NewKimiClient()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using Kimi (Moonshot) AI", config.Name)
+		log.Infof("🤖 [%s] Using Kimi (Moonshot) AI", config.Name)
 
 	case "gemini":
 		mcpClient = mcp.NewGeminiClient()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using Google Gemini AI", config.Name)
+		log.Infof("🤖 [%s] Using Google Gemini AI", config.Name)
 
 	case "grok":
 		mcpClient = mcp.NewGrokClient()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using xAI Grok AI", config.Name)
+		log.Infof("🤖 [%s] Using xAI Grok AI", config.Name)
 
 	case "openai":
 		mcpClient = mcp.NewOpenAIClient()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using OpenAI", config.Name)
+		log.Infof("🤖 [%s] Using OpenAI", config.Name)
 
 	case "qwen":
 		mcpClient = mcp.NewQwenClient()
@@ -187,12 +283,12 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 			apiKey = config.CustomAPIKey
 		}
 		mcpClient.SetAPIKey(apiKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using Alibaba Cloud Qwen AI", config.Name)
+		log.Infof("🤖 [%s] Using Alibaba Cloud Qwen AI", config.Name)
 
 	case "custom":
 		mcpClient = mcp.New()
 		mcpClient.SetAPIKey(config.CustomAPIKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using custom AI API: %s (model: %s)", config.Name, config.CustomAPIURL, config.CustomModelName)
+		log.Infof("🤖 [%s] Using custom AI API: %s (model: %s)", config.Name, config.CustomAPIURL, config.CustomModelName)
 
 	default: // deepseek or empty
 		mcpClient = mcp.NewDeepSeekClient()
@@ -201,11 +297,11 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 			apiKey = config.CustomAPIKey
 		}
 		mcpClient.SetAPIKey(apiKey, config.CustomAPIURL, config.CustomModelName)
-		logger.Infof("🤖 [%s] Using DeepSeek AI", config.Name)
+		log.Infof("🤖 [%s] Using DeepSeek AI", config.Name)
 	}
 
 	if config.CustomAPIURL != "" || config.CustomModelName != "" {
-		logger.Infof("🔧 [%s] Custom config - URL: %s, Model: %s", config.Name, config.CustomAPIURL, config.CustomModelName)
+		log.Infof("🔧 [%s] Custom config - URL: %s, Model: %s", config.Name, config.CustomAPIURL, config.CustomModelName)
 	}
 
 	// Set default trading platform
@@ -213,73 +309,30 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		config.Exchange = "binance"
 	}
 
-	// Create corresponding trader based on configuration
-	var trader Trader
-	var err error
-
 	// Record position mode (general)
 	marginModeStr := "Cross Margin"
 	if !config.IsCrossMargin {
 		marginModeStr = "Isolated Margin"
 	}
-	logger.Infof("📊 [%s] Position mode: %s", config.Name, marginModeStr)
-
-	switch config.Exchange {
-	case "binance":
-		logger.Infof("🏦 [%s] Using Binance Futures trading", config.Name)
-		trader = NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, userID)
-	case "bybit":
-		logger.Infof("🏦 [%s] Using Bybit Futures trading", config.Name)
-		trader = NewBybitTrader(config.BybitAPIKey, config.BybitSecretKey)
-	case "okx":
-		logger.Infof("🏦 [%s] Using OKX Futures trading", config.Name)
-		trader = NewOKXTrader(config.OKXAPIKey, config.OKXSecretKey, config.OKXPassphrase)
-	case "bitget":
-		logger.Infof("🏦 [%s] Using Bitget Futures trading", config.Name)
-		trader = NewBitgetTrader(config.BitgetAPIKey, config.BitgetSecretKey, config.BitgetPassphrase)
-	case "hyperliquid":
-		logger.Infof("🏦 [%s] Using Hyperliquid trading", config.Name)
-		trader, err = NewHyperliquidTrader(config.HyperliquidPrivateKey, config.HyperliquidWalletAddr, config.HyperliquidTestnet)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize Hyperliquid trader: %w", err)
-		}
-	case "aster":
-		logger.Infof("🏦 [%s] Using Aster trading", config.Name)
-		trader, err = NewAsterTrader(config.AsterUser, config.AsterSigner, config.AsterPrivateKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize Aster trader: %w", err)
-		}
-	case "gateio":
-		logger.Infof("🏦 [%s] Using Gate.io Futures trading", config.Name)
-		trader = NewGateTrader(config.GateAPIKey, config.GateSecretKey)
-	case "lighter":
-		logger.Infof("🏦 [%s] Using LIGHTER trading", config.Name)
-
-		if config.LighterWalletAddr == "" || config.LighterAPIKeyPrivateKey == "" {
-			return nil, fmt.Errorf("Lighter requires wallet address and API Key private key")
-		}
+	log.Infof("📊 [%s] Position mode: %s", config.Name, marginModeStr)
 
-		// Lighter only supports mainnet (testnet disabled)
-		trader, err = NewLighterTraderV2(
-			config.LighterWalletAddr,
-			config.LighterAPIKeyPrivateKey,
-			config.LighterAPIKeyIndex,
-			false, // Always use mainnet for Lighter
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize LIGHTER trader: %w", err)
-		}
-		logger.Infof("✓ LIGHTER trader initialized successfully")
-	default:
-		return nil, fmt.Errorf("unsupported trading platform: %s", config.Exchange)
+	// Create corresponding trader based on configuration
+	log.Infof("🏦 [%s] Using %s trading", config.Name, config.Exchange)
+	trader, err := NewTraderFromExchangeConfig(config.exchangeConfig(), userID)
+	if err != nil {
+		return nil, err
+	}
+	applyQuoteAsset(trader, config.QuoteAsset, config.Name, log)
+	if len(config.MirrorExchanges) > 0 {
+		trader = newMirroredTrader(&config, trader, userID)
 	}
 
 	// Validate initial balance configuration, auto-fetch from exchange if 0
 	if config.InitialBalance <= 0 {
-		logger.Infof("📊 [%s] Initial balance not set, attempting to fetch current balance from exchange...", config.Name)
+		log.Infof("📊 [%s] Initial balance not set, attempting to fetch current balance from exchange...", config.Name)
 		account, err := trader.GetBalance()
 		if err != nil {
-			logger.Warnf("[%s] Initial balance not set and unable to fetch balance from exchange: %v. Proceeding with 0 balance.", config.Name, err)
+			log.Warnf("[%s] Initial balance not set and unable to fetch balance from exchange: %v. Proceeding with 0 balance.", config.Name, err)
 		} else {
 		// Try multiple balance field names (different exchanges return different formats)
 		balanceKeys := []string{"total_equity", "totalWalletBalance", "wallet_balance", "totalEq", "balance"}
@@ -290,15 +343,25 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 				break
 			}
 		}
+		// Guard against transient garbage from the API: compare with the trader's last recorded equity
+		var anomaly *BalanceAnomaly
 		if foundBalance > 0 {
+			anomaly = CheckBalanceChange(lastRecordedEquity(st, config.ID), foundBalance, config.BalanceAnomalyPct)
+		}
+		if anomaly != nil {
+			// Keep PnL sane for this session without persisting the suspicious value
+			config.InitialBalance = anomaly.OldBalance
+			log.Warnf("⚠️ [%s] Auto-fetched balance rejected: %v. Using last recorded equity %.2f USDT (not saved), sync balance manually to confirm",
+				config.Name, anomaly, anomaly.OldBalance)
+		} else if foundBalance > 0 {
 			config.InitialBalance = foundBalance
-			logger.Infof("✓ [%s] Auto-fetched initial balance: %.2f USDT", config.Name, foundBalance)
+			log.Infof("✓ [%s] Auto-fetched initial balance: %.2f USDT", config.Name, foundBalance)
 			// Save to database so it persists across restarts
 			if st != nil {
 				if err := st.Trader().UpdateInitialBalance(userID, config.ID, foundBalance); err != nil {
-					logger.Infof("⚠️  [%s] Failed to save initial balance to database: %v", config.Name, err)
+					log.Infof("⚠️  [%s] Failed to save initial balance to database: %v", config.Name, err)
 				} else {
-					logger.Infof("✓ [%s] Initial balance saved to database", config.Name)
+					log.Infof("✓ [%s] Initial balance saved to database", config.Name)
 				}
 			}
 		} else {
@@ -311,7 +374,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	var cycleNumber int
 	if st != nil {
 		cycleNumber, _ = st.Decision().GetLastCycleNumber(config.ID)
-		logger.Infof("📊 [%s] Decision records will be stored to database", config.Name)
+		log.Infof("📊 [%s] Decision records will be stored to database", config.Name)
 	}
 
 	// Create strategy engine (must have strategy config)
@@ -319,9 +382,29 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("[%s] strategy not configured", config.Name)
 	}
 	strategyEngine := kernel.NewStrategyEngine(config.StrategyConfig)
-	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
+	strategyEngine.SetPromptLanguage(config.PromptLanguage)
+	strategyEngine.SetQuoteAsset(config.QuoteAsset)
+	log.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
+
+	// Order sync health (only for exchanges that support order sync)
+	var orderSyncHealth *OrderSyncHealth
+	var orderSyncTrigger chan struct{}
+	if _, ok := trader.(OrderSyncer); ok && st != nil {
+		orderSyncHealth = NewOrderSyncHealth(config.OrderSync.withDefaults().UnhealthyAfter)
+		orderSyncTrigger = make(chan struct{}, 1)
+	}
+
+	// Let slow models use the whole AI request timeout (the HTTP clients default to mcp.DefaultTimeout)
+	consensusVoters := newConsensusVoters(config.ConsensusModels)
+	if config.AIRequestTimeout > 0 {
+		mcpClient.SetTimeout(config.AIRequestTimeout)
+		for _, voter := range consensusVoters {
+			voter.client.SetTimeout(config.AIRequestTimeout)
+		}
+	}
 
 	return &AutoTrader{
+		traderLog:             logger.ForTrader(config.ID),
 		id:                    config.ID,
 		name:                  config.Name,
 		aiModel:               config.AIModel,
@@ -331,6 +414,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		config:                config,
 		trader:                trader,
 		mcpClient:             mcpClient,
+		consensusVoters:       consensusVoters,
 		store:                 st,
 		strategyEngine:        strategyEngine,
 		cycleNumber:           cycleNumber,
@@ -344,8 +428,15 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		monitorWg:             sync.WaitGroup{},
 		peakPnLCache:          make(map[string]float64),
 		peakPnLCacheMutex:     sync.RWMutex{},
+		breakevenMoved:        make(map[string]float64),
 		lastBalanceSyncTime:   time.Now(),
 		userID:                userID,
+		orderSyncHealth:       orderSyncHealth,
+		orderSyncTrigger:      orderSyncTrigger,
+		tpLadders:             make(map[string][]kernel.TakeProfitLevel),
+		signalCh:              make(chan Signal, signalQueueSize),
+		signalLimiter:         newSignalRateLimiter(config.SignalRateLimitPerMinute, signalRateWindow),
+		equity:                equityTracker{hwm: config.EquityHighWaterMark, lastNotifiedHWM: config.EquityHighWaterMark},
 	}, nil
 }
 
@@ -358,77 +449,40 @@ func (at *AutoTrader) Run() error {
 	at.stopMonitorCh = make(chan struct{})
 	at.startTime = time.Now()
 
-	logger.Info("🚀 AI-driven automatic trading system started")
-	logger.Infof("💰 Initial balance: %.2f USDT", at.initialBalance)
-	logger.Infof("⚙️  Scan interval: %v", at.config.ScanInterval)
-	logger.Info("🤖 AI will make full decisions on leverage, position size, stop loss/take profit, etc.")
+	at.log().Info("🚀 AI-driven automatic trading system started")
+	at.log().Infof("💰 Initial balance: %s USDT", formatQuote(at.initialBalance, at.displayDecimals(at.initialBalance)))
+	at.log().Infof("⚙️  Scan interval: %v", at.config.ScanInterval)
+	at.log().Info("🤖 AI will make full decisions on leverage, position size, stop loss/take profit, etc.")
 	at.monitorWg.Add(1)
 	defer at.monitorWg.Done()
 
 	// Start drawdown monitoring
 	at.startDrawdownMonitor()
 
-	// Start Lighter order sync if using Lighter exchange
-	if at.exchange == "lighter" {
-		if lighterTrader, ok := at.trader.(*LighterTraderV2); ok && at.store != nil {
-			lighterTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Lighter order+position sync enabled (every 30s)", at.name)
-		}
+	// Start exchange order sync (with retry and health tracking)
+	if syncer, ok := at.trader.(OrderSyncer); ok && at.store != nil && at.orderSyncHealth != nil {
+		syncCfg := at.config.OrderSync.withDefaults()
+		startOrderSyncLoop(at.exchange, syncer, at.id, at.exchangeID, at.exchange, at.store,
+			syncCfg, at.orderSyncHealth, at.orderSyncTrigger, at.stopMonitorCh)
+		at.log().Infof("🔄 [%s] %s order+position sync enabled (every %v)", at.name, at.exchange, syncCfg.Interval)
 	}
 
-	// Start Hyperliquid order sync if using Hyperliquid exchange
-	if at.exchange == "hyperliquid" {
-		if hyperliquidTrader, ok := at.trader.(*HyperliquidTrader); ok && at.store != nil {
-			hyperliquidTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Hyperliquid order+position sync enabled (every 30s)", at.name)
-		}
+	// Import deposits/withdrawals so funding changes don't show up as PnL
+	if provider, ok := at.trader.(TransferHistoryProvider); ok && at.store != nil && at.config.TransferReconcileInterval > 0 {
+		startTransferReconcileLoop(at.name, provider, at.id, at.store, at.config.TransferReconcileInterval, at.stopMonitorCh)
+		at.log().Infof("💸 [%s] Transfer history reconcile enabled (every %v)", at.name, at.config.TransferReconcileInterval)
 	}
 
-	// Start Bybit order sync if using Bybit exchange
-	if at.exchange == "bybit" {
-		if bybitTrader, ok := at.trader.(*BybitTrader); ok && at.store != nil {
-			bybitTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Bybit order+position sync enabled (every 30s)", at.name)
-		}
+	// Import funding payments so realized PnL includes the cost/income of holding perps
+	if provider, ok := at.trader.(FundingHistoryProvider); ok && at.store != nil && at.config.FundingReconcileInterval > 0 {
+		startFundingReconcileLoop(at.name, provider, at.id, at.store, at.config.FundingReconcileInterval, at.stopMonitorCh)
+		at.log().Infof("💰 [%s] Funding history reconcile enabled (every %v)", at.name, at.config.FundingReconcileInterval)
 	}
 
-	// Start OKX order sync if using OKX exchange
-	if at.exchange == "okx" {
-		if okxTrader, ok := at.trader.(*OKXTrader); ok && at.store != nil {
-			okxTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] OKX order+position sync enabled (every 30s)", at.name)
-		}
-	}
-
-	// Start Bitget order sync if using Bitget exchange
-	if at.exchange == "bitget" {
-		if bitgetTrader, ok := at.trader.(*BitgetTrader); ok && at.store != nil {
-			bitgetTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Bitget order+position sync enabled (every 30s)", at.name)
-		}
-	}
-
-	// Start Aster order sync if using Aster exchange
-	if at.exchange == "aster" {
-		if asterTrader, ok := at.trader.(*AsterTrader); ok && at.store != nil {
-			asterTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Aster order+position sync enabled (every 30s)", at.name)
-		}
-	}
-
-	// Start Gate.io order sync if using Gate.io exchange
-	if at.exchange == "gateio" {
-		if gateTrader, ok := at.trader.(*GateTrader); ok && at.store != nil {
-			gateTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Gate.io order+position sync enabled (every 30s)", at.name)
-		}
-	}
-
-	// Start Binance order sync if using Binance exchange
-	if at.exchange == "binance" {
-		if binanceTrader, ok := at.trader.(*FuturesTrader); ok && at.store != nil {
-			binanceTrader.StartOrderSync(at.id, at.exchangeID, at.exchange, at.store, 30*time.Second)
-			logger.Infof("🔄 [%s] Binance order+position sync enabled (every 30s)", at.name)
+	// Start private order stream for event-driven fill confirmation (polling remains the fallback)
+	if waiter, ok := orderFillWaiter(at.trader); ok && at.config.OrderFill.UseUserStream {
+		if err := waiter.StartOrderStream(at.stopMonitorCh); err != nil {
+			at.log().Warnf("⚠️ [%s] Order stream unavailable, confirming fills by polling: %v", at.name, err)
 		}
 	}
 
@@ -437,7 +491,7 @@ func (at *AutoTrader) Run() error {
 
 	// Execute immediately on first run
 	if err := at.runCycle(); err != nil {
-		logger.Infof("❌ Execution failed: %v", err)
+		at.log().Infof("❌ Execution failed: %v", err)
 	}
 
 	for {
@@ -452,10 +506,12 @@ func (at *AutoTrader) Run() error {
 		select {
 		case <-ticker.C:
 			if err := at.runCycle(); err != nil {
-				logger.Infof("❌ Execution failed: %v", err)
+				at.log().Infof("❌ Execution failed: %v", err)
 			}
+		case sig := <-at.signalCh:
+			at.handleSignal(sig)
 		case <-at.stopMonitorCh:
-			logger.Infof("[%s] ⏹ Stop signal received, exiting automatic trading main loop", at.name)
+			at.log().Infof("[%s] ⏹ Stop signal received, exiting automatic trading main loop", at.name)
 			return nil
 		}
 	}
@@ -475,23 +531,26 @@ func (at *AutoTrader) Stop() {
 
 	close(at.stopMonitorCh) // Notify monitoring goroutine to stop
 	at.monitorWg.Wait()     // Wait for monitoring goroutine to finish
-	logger.Info("⏹ Automatic trading system stopped")
+	at.log().Info("⏹ Automatic trading system stopped")
 }
 
 // runCycle runs one trading cycle (using AI full decision-making)
 func (at *AutoTrader) runCycle() error {
+	at.execMu.Lock()
+	defer at.execMu.Unlock()
 	at.callCount++
+	cycleCtx := at.startTrace()
 
-	logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
-	logger.Info(strings.Repeat("=", 70))
+	at.log().Info("\n" + strings.Repeat("=", 70) + "\n")
+	at.log().Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
+	at.log().Info(strings.Repeat("=", 70))
 
 	// 0. Check if trader is stopped (early exit to prevent trades after Stop() is called)
 	at.isRunningMutex.RLock()
 	running := at.isRunning
 	at.isRunningMutex.RUnlock()
 	if !running {
-		logger.Infof("⏹ Trader is stopped, aborting cycle #%d", at.callCount)
+		at.log().Infof("⏹ Trader is stopped, aborting cycle #%d", at.callCount)
 		return nil
 	}
 
@@ -499,14 +558,40 @@ func (at *AutoTrader) runCycle() error {
 	record := &store.DecisionRecord{
 		ExecutionLog: []string{},
 		Success:      true,
+		TraceID:      logger.TraceID(cycleCtx),
+	}
+
+	// 0.1 Global emergency pause (admin pause-all): skip trading, monitoring goroutines keep running
+	if IsTradingPaused() {
+		at.log().Infof("⏸ [%s] Trading globally paused by admin, skipping cycle #%d", at.name, at.callCount)
+		record.Success = false
+		record.ErrorMessage = "Trading globally paused"
+		at.saveDecision(record)
+		return nil
+	}
+
+	// 1. Check if trading needs to be stopped (a losing streak starts a cool-off)
+	at.checkLossStreak(time.Now())
+	if stopUntil, stopReason := at.riskPause(); time.Now().Before(stopUntil) {
+		remaining := stopUntil.Sub(time.Now())
+		reason := ""
+		if stopReason != "" {
+			reason = " (" + stopReason + ")"
+		}
+		at.log().Infof("⏸ Risk control: Trading paused%s, remaining %.0f minutes", reason, remaining.Minutes())
+		record.Success = false
+		record.ErrorMessage = fmt.Sprintf("Risk control paused%s, remaining %.0f minutes", reason, remaining.Minutes())
+		at.saveDecision(record)
+		return nil
 	}
 
-	// 1. Check if trading needs to be stopped
-	if time.Now().Before(at.stopUntil) {
-		remaining := at.stopUntil.Sub(time.Now())
-		logger.Infof("⏸ Risk control: Trading paused, remaining %.0f minutes", remaining.Minutes())
+	// 1.1 Pause trading while order sync is unhealthy (local orders/positions may be stale)
+	if at.orderSyncHealth != nil && at.config.OrderSync.PauseOnUnhealthy && !at.orderSyncHealth.IsHealthy() {
+		at.log().Infof("⏸ [%s] Order sync unhealthy (%d consecutive failures), trading paused",
+			at.name, at.orderSyncHealth.ConsecutiveFailures())
 		record.Success = false
-		record.ErrorMessage = fmt.Sprintf("Risk control paused, remaining %.0f minutes", remaining.Minutes())
+		record.ErrorMessage = fmt.Sprintf("Order sync unhealthy (%d consecutive failures), trading paused",
+			at.orderSyncHealth.ConsecutiveFailures())
 		at.saveDecision(record)
 		return nil
 	}
@@ -515,7 +600,7 @@ func (at *AutoTrader) runCycle() error {
 	if time.Since(at.lastResetTime) > 24*time.Hour {
 		at.dailyPnL = 0
 		at.lastResetTime = time.Now()
-		logger.Info("📅 Daily P&L reset")
+		at.log().Info("📅 Daily P&L reset")
 	}
 
 	// 4. Collect trading context
@@ -530,21 +615,34 @@ func (at *AutoTrader) runCycle() error {
 	// Save equity snapshot independently (decoupled from AI decision, used for drawing profit curve)
 	at.saveEquitySnapshot(ctx)
 
-	logger.Info(strings.Repeat("=", 70))
+	// Cancel leftover take profit ladder orders of closed positions
+	at.reconcileTakeProfitLadders(ctx.Positions)
+
+	at.log().Info(strings.Repeat("=", 70))
 	for _, coin := range ctx.CandidateCoins {
 		record.CandidateCoins = append(record.CandidateCoins, coin.Symbol)
 	}
+	if len(ctx.UnavailableSources) > 0 {
+		record.UnavailableSources = ctx.UnavailableSources
+		record.ExecutionLog = append(record.ExecutionLog,
+			fmt.Sprintf("⚠️ Data unavailable this cycle: %s", strings.Join(ctx.UnavailableSources, ", ")))
+	}
 
-	logger.Infof("📊 Account equity: %.2f USDT | Available: %.2f USDT | Positions: %d",
-		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount)
+	decimals := at.displayDecimals(ctx.Account.TotalEquity)
+	at.log().Infof("📊 Account equity: %s USDT | Available: %s USDT | Positions: %d",
+		formatQuote(ctx.Account.TotalEquity, decimals), formatQuote(ctx.Account.AvailableBalance, decimals), ctx.Account.PositionCount)
 
 	// 5. Use strategy engine to call AI for decision
-	logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
-	aiDecision, err := kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	at.log().Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
+	aiDecision, consensusVotes, err := at.requestDecisionWithTimeout(ctx)
+	if consensusVotes != nil {
+		record.ExecutionLog = append(record.ExecutionLog,
+			fmt.Sprintf("Consensus of %d models (threshold %s)", len(at.consensusVoters)+1, consensusThresholdLabel(at.config.ConsensusThreshold)))
+	}
 
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
-		logger.Infof("⏱️ AI call duration: %.2f seconds", float64(record.AIRequestDurationMs)/1000)
+		at.log().Infof("⏱️ AI call duration: %.2f seconds", float64(record.AIRequestDurationMs)/1000)
 		record.ExecutionLog = append(record.ExecutionLog,
 			fmt.Sprintf("AI call duration: %d ms", record.AIRequestDurationMs))
 	}
@@ -555,6 +653,7 @@ func (at *AutoTrader) runCycle() error {
 		record.InputPrompt = aiDecision.UserPrompt
 		record.CoTTrace = aiDecision.CoTTrace
 		record.RawResponse = aiDecision.RawResponse // Save raw AI response for debugging
+		record.RepairedResponse = aiDecision.RepairedResponse
 		if len(aiDecision.Decisions) > 0 {
 			decisionJSON, _ := json.MarshalIndent(aiDecision.Decisions, "", "  ")
 			record.DecisionJSON = string(decisionJSON)
@@ -567,18 +666,18 @@ func (at *AutoTrader) runCycle() error {
 
 		// Print system prompt and AI chain of thought (output even with errors for debugging)
 		if aiDecision != nil {
-			logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-			logger.Infof("📋 System prompt (error case)")
-			logger.Info(strings.Repeat("=", 70))
-			logger.Info(aiDecision.SystemPrompt)
-			logger.Info(strings.Repeat("=", 70))
+			at.log().Info("\n" + strings.Repeat("=", 70) + "\n")
+			at.log().Infof("📋 System prompt (error case)")
+			at.log().Info(strings.Repeat("=", 70))
+			at.log().Info(aiDecision.SystemPrompt)
+			at.log().Info(strings.Repeat("=", 70))
 
 			if aiDecision.CoTTrace != "" {
-				logger.Info("\n" + strings.Repeat("-", 70) + "\n")
-				logger.Info("💭 AI chain of thought analysis (error case):")
-				logger.Info(strings.Repeat("-", 70))
-				logger.Info(aiDecision.CoTTrace)
-				logger.Info(strings.Repeat("-", 70))
+				at.log().Info("\n" + strings.Repeat("-", 70) + "\n")
+				at.log().Info("💭 AI chain of thought analysis (error case):")
+				at.log().Info(strings.Repeat("-", 70))
+				at.log().Info(aiDecision.CoTTrace)
+				at.log().Info(strings.Repeat("-", 70))
 			}
 		}
 
@@ -609,26 +708,26 @@ func (at *AutoTrader) runCycle() error {
 	//           d.Leverage, d.PositionSizeUSD, d.StopLoss, d.TakeProfit)
 	//     }
 	// }
-	logger.Info()
-	logger.Info(strings.Repeat("-", 70))
+	at.log().Info()
+	at.log().Info(strings.Repeat("-", 70))
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
-	logger.Info(strings.Repeat("-", 70))
+	at.log().Info(strings.Repeat("-", 70))
 
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
 	sortedDecisions := sortDecisionsByPriority(aiDecision.Decisions)
 
-	logger.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
+	at.log().Info("🔄 Execution order (optimized): Close positions first → Open positions later")
 	for i, d := range sortedDecisions {
-		logger.Infof("  [%d] %s %s", i+1, d.Symbol, d.Action)
+		at.log().Infof("  [%d] %s %s", i+1, d.Symbol, d.Action)
 	}
-	logger.Info()
+	at.log().Info()
 
 	// Check if trader is stopped before executing any decisions (prevent trades after Stop())
 	at.isRunningMutex.RLock()
 	running = at.isRunning
 	at.isRunningMutex.RUnlock()
 	if !running {
-		logger.Infof("⏹ Trader stopped before decision execution, aborting cycle #%d", at.callCount)
+		at.log().Infof("⏹ Trader stopped before decision execution, aborting cycle #%d", at.callCount)
 		return nil
 	}
 
@@ -639,7 +738,7 @@ func (at *AutoTrader) runCycle() error {
 		running = at.isRunning
 		at.isRunningMutex.RUnlock()
 		if !running {
-			logger.Infof("⏹ Trader stopped during decision execution, aborting remaining decisions")
+			at.log().Infof("⏹ Trader stopped during decision execution, aborting remaining decisions")
 			break
 		}
 
@@ -653,12 +752,33 @@ func (at *AutoTrader) runCycle() error {
 			TakeProfit: d.TakeProfit,
 			Confidence: d.Confidence,
 			Reasoning:  d.Reasoning,
+			Votes:      consensusVotes[d.Symbol],
 			Timestamp:  time.Now().UTC(),
 			Success:    false,
 		}
 
-		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
-			logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
+		// Auto-flip: close the opposite position first, recorded as its own leg
+		if closeLeg, err := at.autoFlipBeforeOpen(&d); closeLeg != nil || err != nil {
+			if closeLeg != nil {
+				record.Decisions = append(record.Decisions, *closeLeg)
+			}
+			if err != nil {
+				at.log().Infof("❌ Auto-flip failed (%s %s): %v", d.Symbol, d.Action, err)
+				actionRecord.Error = err.Error()
+				record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s auto-flip failed: %v", d.Symbol, d.Action, err))
+				record.Decisions = append(record.Decisions, actionRecord)
+				continue
+			}
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s succeeded (auto-flip)", closeLeg.Symbol, closeLeg.Action))
+		}
+
+		if err := at.executeDecisionWithRecord(&d, &actionRecord); errors.Is(err, ErrMinNotional) {
+			// Below the exchange minimum: nothing to retry, skip the action instead of failing the cycle
+			at.log().Infof("⏭ Skipped decision (%s %s): %v", d.Symbol, d.Action, err)
+			actionRecord.Error = err.Error()
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⏭ %s %s skipped: %v", d.Symbol, d.Action, err))
+		} else if err != nil {
+			at.log().Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
 			actionRecord.Error = err.Error()
 			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
 		} else {
@@ -673,7 +793,7 @@ func (at *AutoTrader) runCycle() error {
 
 	// 9. Save decision record
 	if err := at.saveDecision(record); err != nil {
-		logger.Infof("⚠ Failed to save decision record: %v", err)
+		at.log().Infof("⚠ Failed to save decision record: %v", err)
 	}
 
 	return nil
@@ -815,25 +935,22 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get candidate coins: %w", err)
 	}
-	logger.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
+	at.log().Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
 
-	// 4. Calculate total P&L
-	totalPnL := totalEquity - at.initialBalance
-	totalPnLPct := 0.0
-	if at.initialBalance > 0 {
-		totalPnLPct = (totalPnL / at.initialBalance) * 100
-	}
+	// 4. Calculate total P&L (deposits/withdrawals excluded)
+	totalPnL, totalPnLPct := TradingPnL(totalEquity, at.initialBalance, netContributions(at.store, at.id))
 
 	marginUsedPct := 0.0
 	if totalEquity > 0 {
 		marginUsedPct = (totalMarginUsed / totalEquity) * 100
 	}
+	at.lastMarginUsedPct = marginUsedPct
 
 	// 5. Get leverage from strategy config
 	strategyConfig := at.strategyEngine.GetConfig()
 	btcEthLeverage := strategyConfig.RiskControl.BTCETHMaxLeverage
 	altcoinLeverage := strategyConfig.RiskControl.AltcoinMaxLeverage
-	logger.Infof("📋 [%s] Strategy leverage config: BTC/ETH=%dx, Altcoin=%dx", at.name, btcEthLeverage, altcoinLeverage)
+	at.log().Infof("📋 [%s] Strategy leverage config: BTC/ETH=%dx, Altcoin=%dx", at.name, btcEthLeverage, altcoinLeverage)
 
 	// 6. Build context
 	ctx := &kernel.Context{
@@ -854,6 +971,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		},
 		Positions:      positionInfos,
 		CandidateCoins: candidateCoins,
+		MarginLimitPct: at.maxTotalMarginUsedPct(),
 	}
 
 	// 7. Add recent closed trades (if store is available)
@@ -861,9 +979,9 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		// Get recent 10 closed trades for AI context
 		recentTrades, err := at.store.Position().GetRecentTrades(at.id, 10)
 		if err != nil {
-			logger.Infof("⚠️ [%s] Failed to get recent trades: %v", at.name, err)
+			at.log().Infof("⚠️ [%s] Failed to get recent trades: %v", at.name, err)
 		} else {
-			logger.Infof("📊 [%s] Found %d recent closed trades for AI context", at.name, len(recentTrades))
+			at.log().Infof("📊 [%s] Found %d recent closed trades for AI context", at.name, len(recentTrades))
 			for _, trade := range recentTrades {
 				// Convert Unix timestamps to formatted strings for AI readability
 				entryTimeStr := ""
@@ -891,29 +1009,32 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		// Get trading statistics for AI context
 		stats, err := at.store.Position().GetFullStats(at.id)
 		if err != nil {
-			logger.Infof("⚠️ [%s] Failed to get trading stats: %v", at.name, err)
+			at.log().Infof("⚠️ [%s] Failed to get trading stats: %v", at.name, err)
 		} else if stats == nil {
-			logger.Infof("⚠️ [%s] GetFullStats returned nil", at.name)
+			at.log().Infof("⚠️ [%s] GetFullStats returned nil", at.name)
 		} else if stats.TotalTrades == 0 {
-			logger.Infof("⚠️ [%s] GetFullStats returned 0 trades (traderID=%s)", at.name, at.id)
+			at.log().Infof("⚠️ [%s] GetFullStats returned 0 trades (traderID=%s)", at.name, at.id)
 		} else {
 			ctx.TradingStats = &kernel.TradingStats{
 				TotalTrades:    stats.TotalTrades,
 				WinRate:        stats.WinRate,
 				ProfitFactor:   stats.ProfitFactor,
 				SharpeRatio:    stats.SharpeRatio,
-				TotalPnL:       stats.TotalPnL,
+				TotalPnL:       stats.NetPnL, // Includes funding payments
 				AvgWin:         stats.AvgWin,
 				AvgLoss:        stats.AvgLoss,
 				MaxDrawdownPct: stats.MaxDrawdownPct,
 			}
-			logger.Infof("📈 [%s] Trading stats: %d trades, %.1f%% win rate, PF=%.2f, Sharpe=%.2f, DD=%.1f%%",
+			at.log().Infof("📈 [%s] Trading stats: %d trades, %.1f%% win rate, PF=%.2f, Sharpe=%.2f, DD=%.1f%%",
 				at.name, stats.TotalTrades, stats.WinRate, stats.ProfitFactor, stats.SharpeRatio, stats.MaxDrawdownPct)
 		}
 	} else {
-		logger.Infof("⚠️ [%s] Store is nil, cannot get recent trades", at.name)
+		at.log().Infof("⚠️ [%s] Store is nil, cannot get recent trades", at.name)
 	}
 
+	// 8-11. Fetch quant data and market-wide rankings concurrently, each with its own timeout
+	fetcher := newParallelFetcher(context.Background(), contextFetchTimeout, at.log())
+
 	// 8. Get quantitative data (if enabled in strategy config)
 	if strategyConfig.Indicators.EnableQuantData {
 		// Collect symbols to query (candidate coins + position coins)
@@ -930,41 +1051,69 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 			symbols = append(symbols, sym)
 		}
 
-		logger.Infof("📊 [%s] Fetching quantitative data for %d symbols...", at.name, len(symbols))
-		ctx.QuantDataMap = at.strategyEngine.FetchQuantDataBatch(symbols)
-		logger.Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(ctx.QuantDataMap))
+		at.log().Infof("📊 [%s] Fetching quantitative data for %d symbols...", at.name, len(symbols))
+		goFetch(fetcher, "Quant data", func() map[string]*kernel.QuantData {
+			return at.strategyEngine.FetchQuantDataBatch(symbols)
+		}, func(data map[string]*kernel.QuantData) {
+			ctx.QuantDataMap = data
+			at.log().Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(data))
+		})
 	}
 
 	// 9. Get OI ranking data (market-wide position changes)
 	if strategyConfig.Indicators.EnableOIRanking {
-		logger.Infof("📊 [%s] Fetching OI ranking data...", at.name)
-		ctx.OIRankingData = at.strategyEngine.FetchOIRankingData()
-		if ctx.OIRankingData != nil {
-			logger.Infof("📊 [%s] OI ranking data ready: %d top, %d low positions",
-				at.name, len(ctx.OIRankingData.TopPositions), len(ctx.OIRankingData.LowPositions))
-		}
+		at.log().Infof("📊 [%s] Fetching OI ranking data...", at.name)
+		goFetchErr(fetcher, kernel.SourceOIRanking, at.strategyEngine.FetchOIRankingData, func(data *nofxos.OIRankingData) {
+			ctx.OIRankingData = data
+			if data != nil {
+				at.log().Infof("📊 [%s] OI ranking data ready: %d top, %d low positions",
+					at.name, len(data.TopPositions), len(data.LowPositions))
+			}
+		})
 	}
 
 	// 10. Get NetFlow ranking data (market-wide fund flow)
 	if strategyConfig.Indicators.EnableNetFlowRanking {
-		logger.Infof("💰 [%s] Fetching NetFlow ranking data...", at.name)
-		ctx.NetFlowRankingData = at.strategyEngine.FetchNetFlowRankingData()
-		if ctx.NetFlowRankingData != nil {
-			logger.Infof("💰 [%s] NetFlow ranking data ready: inst_in=%d, inst_out=%d",
-				at.name, len(ctx.NetFlowRankingData.InstitutionFutureTop), len(ctx.NetFlowRankingData.InstitutionFutureLow))
-		}
+		at.log().Infof("💰 [%s] Fetching NetFlow ranking data...", at.name)
+		goFetchErr(fetcher, kernel.SourceNetFlowRanking, at.strategyEngine.FetchNetFlowRankingData, func(data *nofxos.NetFlowRankingData) {
+			ctx.NetFlowRankingData = data
+			if data != nil {
+				at.log().Infof("💰 [%s] NetFlow ranking data ready: inst_in=%d, inst_out=%d",
+					at.name, len(data.InstitutionFutureTop), len(data.InstitutionFutureLow))
+			}
+		})
 	}
 
 	// 11. Get Price ranking data (market-wide gainers/losers)
 	if strategyConfig.Indicators.EnablePriceRanking {
-		logger.Infof("📈 [%s] Fetching Price ranking data...", at.name)
-		ctx.PriceRankingData = at.strategyEngine.FetchPriceRankingData()
-		if ctx.PriceRankingData != nil {
-			logger.Infof("📈 [%s] Price ranking data ready for %d durations",
-				at.name, len(ctx.PriceRankingData.Durations))
+		at.log().Infof("📈 [%s] Fetching Price ranking data...", at.name)
+		goFetchErr(fetcher, kernel.SourcePriceRanking, at.strategyEngine.FetchPriceRankingData, func(data *nofxos.PriceRankingData) {
+			ctx.PriceRankingData = data
+			if data != nil {
+				at.log().Infof("📈 [%s] Price ranking data ready for %d durations",
+					at.name, len(data.Durations))
+			}
+		})
+	}
+
+	// 12. Get order books of positions and top candidates (if enabled and supported by the exchange)
+	if strategyConfig.Indicators.EnableOrderBook {
+		if provider, ok := at.trader.(OrderBookProvider); ok {
+			symbols := orderBookSymbols(positionInfos, candidateCoins)
+			at.log().Infof("📖 [%s] Fetching order books for %d symbols...", at.name, len(symbols))
+			goFetchErr(fetcher, kernel.SourceOrderBook, func() (map[string]*kernel.OrderBook, error) {
+				return at.fetchOrderBooks(provider, symbols)
+			}, func(books map[string]*kernel.OrderBook) {
+				ctx.OrderBook = books
+			})
 		}
 	}
 
+	if timedOut := fetcher.Wait(); len(timedOut) > 0 {
+		at.log().Warnf("⚠️ [%s] Context data timed out: %s (cycle continues without it)", at.name, strings.Join(timedOut, ", "))
+	}
+	ctx.UnavailableSources = fetcher.Unavailable()
+
 	return ctx, nil
 }
 
@@ -979,6 +1128,10 @@ func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actio
 		return at.executeCloseLongWithRecord(decision, actionRecord)
 	case "close_short":
 		return at.executeCloseShortWithRecord(decision, actionRecord)
+	case "reduce_long":
+		return at.executeReduceWithRecord(decision, actionRecord, "long")
+	case "reduce_short":
+		return at.executeReduceWithRecord(decision, actionRecord, "short")
 	case "hold", "wait":
 		// No execution needed, just record
 		return nil
@@ -988,9 +1141,29 @@ func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actio
 }
 
 // ExecuteDecision executes a trading decision from external sources (e.g., debate consensus)
-// This is a public method that can be called by other modules
-func (at *AutoTrader) ExecuteDecision(d *kernel.Decision) error {
-	logger.Infof("[%s] Executing external decision: %s %s", at.name, d.Action, d.Symbol)
+// This is a public method that can be called by other modules. Like a manual open, it waits for a
+// running cycle to finish, runs under its own correlation ID and opens nothing while risk control
+// pauses the trader.
+func (at *AutoTrader) ExecuteDecision(d *kernel.Decision) (err error) {
+	at.execMu.Lock()
+	defer at.execMu.Unlock()
+	at.withExecTrace(func(context.Context) {
+		err = at.executeExternalDecisionLocked(d)
+	})
+	return err
+}
+
+// executeExternalDecisionLocked runs ExecuteDecision's decision; the caller holds execMu
+func (at *AutoTrader) executeExternalDecisionLocked(d *kernel.Decision) error {
+	at.log().Infof("[%s] Executing external decision: %s %s", at.name, d.Action, d.Symbol)
+	if IsTradingPaused() {
+		return ErrTradingPaused
+	}
+	if d.Action == "open_long" || d.Action == "open_short" {
+		if reason := at.riskControlPause(time.Now()); reason != "" {
+			return fmt.Errorf("%w: %s", ErrRiskControlPaused, reason)
+		}
+	}
 
 	// Create a minimal action record for tracking
 	actionRecord := &store.DecisionAction{
@@ -1002,21 +1175,37 @@ func (at *AutoTrader) ExecuteDecision(d *kernel.Decision) error {
 		Confidence: d.Confidence,
 		Reasoning:  d.Reasoning,
 	}
+	return at.executeExternalDecision(d, actionRecord)
+}
+
+// executeExternalDecision runs an external decision (auto-flip first) through the same executors
+// and code-enforced risk checks as the AI's decisions, filling actionRecord
+func (at *AutoTrader) executeExternalDecision(d *kernel.Decision, actionRecord *store.DecisionAction) error {
+	// Auto-flip: close the opposite position first
+	if _, err := at.autoFlipBeforeOpen(d); err != nil {
+		at.log().Errorf("[%s] External decision auto-flip failed: %v", at.name, err)
+		return err
+	}
 
 	// Execute the decision
 	err := at.executeDecisionWithRecord(d, actionRecord)
 	if err != nil {
-		logger.Errorf("[%s] External decision execution failed: %v", at.name, err)
+		at.log().Errorf("[%s] External decision execution failed: %v", at.name, err)
 		return err
 	}
 
-	logger.Infof("[%s] External decision executed successfully: %s %s", at.name, d.Action, d.Symbol)
+	at.log().Infof("[%s] External decision executed successfully: %s %s", at.name, d.Action, d.Symbol)
 	return nil
 }
 
 // executeOpenLongWithRecord executes open long position and records detailed information
 func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  📈 Open long: %s", decision.Symbol)
+	at.log().Infof("  📈 Open long: %s", decision.Symbol)
+
+	// [CODE ENFORCED] Maintenance window: no new positions outside the allowed trading windows
+	if err := at.enforceTradingWindow(time.Now()); err != nil {
+		return err
+	}
 
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
@@ -1029,12 +1218,20 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 		return err
 	}
 
+	// [CODE ENFORCED] Check correlated group same-direction limit
+	if err := at.enforceCorrelationGroupLimits(decision.Symbol, "long", positions); err != nil {
+		return err
+	}
+
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
 		if pos["symbol"] == decision.Symbol && pos["side"] == "long" {
 			return fmt.Errorf("❌ %s already has long position, close it first", decision.Symbol)
 		}
 	}
+	if at.hasMakerEntry(decision.Symbol) {
+		return fmt.Errorf("❌ %s has a post-only entry waiting for a fill", decision.Symbol)
+	}
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
@@ -1062,33 +1259,42 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Portfolio margin usage limit
+	if err := at.enforceMaxTotalMarginUsage(positions, equity); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] Clamp leverage to the configured ceiling and the exchange's per-symbol maximum
+	at.enforceMaxLeverage(decision)
+	at.enforceExchangeMaxLeverage(decision)
+	actionRecord.Leverage = decision.Leverage
+
+	// [CODE ENFORCED] Position sizing model (overrides AI size unless fixed_usd)
+	at.applySizingModel(decision, equity, marketData)
+
+	// [CODE ENFORCED] Volatility scaling (risk-parity style size adjustment)
+	at.applyVolatilityScaling(decision, actionRecord)
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
-	}
+	// [CODE ENFORCED] Auto-adjust position size if insufficient margin, keeping the free-margin buffer
+	actualPositionSize := at.enforceAffordableSize(decision.PositionSizeUSD, availableBalance, decision.Leverage)
+	decision.PositionSizeUSD = actualPositionSize
 
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
 		return err
 	}
 
+	// [CODE ENFORCED] Net directional exposure limit (checked on the final size)
+	if err := at.enforceNetDirectionalExposure(decision.Symbol, "long", positions, decision.PositionSizeUSD, equity); err != nil {
+		return err
+	}
+
 	// Calculate quantity with adjusted position size
 	quantity := actualPositionSize / marketData.CurrentPrice
 	actionRecord.Quantity = quantity
@@ -1096,44 +1302,71 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 
 	// Set margin mode
 	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		at.log().Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	// Open position (an insufficient-margin rejection is retried once with a smaller size)
+	order, quantity, attachedSL, attachedTP, err := at.openPositionWithRetry(decision, "LONG", quantity, marketData.CurrentPrice)
 	if err != nil {
 		return err
 	}
+	actionRecord.Quantity = quantity
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
 		actionRecord.OrderID = orderID
 	}
 
-	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+	// Post-only entries rest on the book: the watcher places the exits once the order fills
+	if isPostOnlyOrder(order) {
+		actionRecord.PostOnly = true
+		if price, ok := order["price"].(float64); ok {
+			actionRecord.Price = price
+		}
+		if orderID, ok := order["orderId"].(int64); ok {
+			actionRecord.OrderID = orderID
+		}
+		at.trackMakerEntry(decision, "LONG", quantity, order)
+		return nil
+	}
 
-	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.log().Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+
+	// Record order to database and poll for confirmation (sliced entries recorded each child already)
+	if !isSlicedOrder(order) {
+		at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	}
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit (unless already attached to the entry order or placed by a sliced entry)
+	slType, tpType := "", ""
+	if attachedSL {
+		slType = attachedExitType(order, "stopLossType")
+	} else if slType, err = at.placeStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
+		at.log().Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if attachedTP {
+		tpType = attachedExitType(order, "takeProfitType")
+	} else {
+		tpType = at.placeTakeProfits(decision, "LONG", quantity)
 	}
+	actionRecord.ExitOrderType = mergeExitOrderTypes(slType, tpType)
 
 	return nil
 }
 
 // executeOpenShortWithRecord executes open short position and records detailed information
 func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  📉 Open short: %s", decision.Symbol)
+	at.log().Infof("  📉 Open short: %s", decision.Symbol)
+
+	// [CODE ENFORCED] Maintenance window: no new positions outside the allowed trading windows
+	if err := at.enforceTradingWindow(time.Now()); err != nil {
+		return err
+	}
 
 	// ⚠️ Get current positions for multiple checks
 	positions, err := at.trader.GetPositions()
@@ -1146,12 +1379,20 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 		return err
 	}
 
+	// [CODE ENFORCED] Check correlated group same-direction limit
+	if err := at.enforceCorrelationGroupLimits(decision.Symbol, "short", positions); err != nil {
+		return err
+	}
+
 	// Check if there's already a position in the same symbol and direction
 	for _, pos := range positions {
 		if pos["symbol"] == decision.Symbol && pos["side"] == "short" {
 			return fmt.Errorf("❌ %s already has short position, close it first", decision.Symbol)
 		}
 	}
+	if at.hasMakerEntry(decision.Symbol) {
+		return fmt.Errorf("❌ %s has a post-only entry waiting for a fill", decision.Symbol)
+	}
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
@@ -1179,33 +1420,42 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// [CODE ENFORCED] Portfolio margin usage limit
+	if err := at.enforceMaxTotalMarginUsage(positions, equity); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] Clamp leverage to the configured ceiling and the exchange's per-symbol maximum
+	at.enforceMaxLeverage(decision)
+	at.enforceExchangeMaxLeverage(decision)
+	actionRecord.Leverage = decision.Leverage
+
+	// [CODE ENFORCED] Position sizing model (overrides AI size unless fixed_usd)
+	at.applySizingModel(decision, equity, marketData)
+
+	// [CODE ENFORCED] Volatility scaling (risk-parity style size adjustment)
+	at.applyVolatilityScaling(decision, actionRecord)
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
-	}
+	// [CODE ENFORCED] Auto-adjust position size if insufficient margin, keeping the free-margin buffer
+	actualPositionSize := at.enforceAffordableSize(decision.PositionSizeUSD, availableBalance, decision.Leverage)
+	decision.PositionSizeUSD = actualPositionSize
 
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
 		return err
 	}
 
+	// [CODE ENFORCED] Net directional exposure limit (checked on the final size)
+	if err := at.enforceNetDirectionalExposure(decision.Symbol, "short", positions, decision.PositionSizeUSD, equity); err != nil {
+		return err
+	}
+
 	// Calculate quantity with adjusted position size
 	quantity := actualPositionSize / marketData.CurrentPrice
 	actionRecord.Quantity = quantity
@@ -1213,44 +1463,66 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 
 	// Set margin mode
 	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		at.log().Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	// Open position (an insufficient-margin rejection is retried once with a smaller size)
+	order, quantity, attachedSL, attachedTP, err := at.openPositionWithRetry(decision, "SHORT", quantity, marketData.CurrentPrice)
 	if err != nil {
 		return err
 	}
+	actionRecord.Quantity = quantity
 
 	// Record order ID
 	if orderID, ok := order["orderId"].(int64); ok {
 		actionRecord.OrderID = orderID
 	}
 
-	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+	// Post-only entries rest on the book: the watcher places the exits once the order fills
+	if isPostOnlyOrder(order) {
+		actionRecord.PostOnly = true
+		if price, ok := order["price"].(float64); ok {
+			actionRecord.Price = price
+		}
+		if orderID, ok := order["orderId"].(int64); ok {
+			actionRecord.OrderID = orderID
+		}
+		at.trackMakerEntry(decision, "SHORT", quantity, order)
+		return nil
+	}
 
-	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	at.log().Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
+
+	// Record order to database and poll for confirmation (sliced entries recorded each child already)
+	if !isSlicedOrder(order) {
+		at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	}
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit (unless already attached to the entry order or placed by a sliced entry)
+	slType, tpType := "", ""
+	if attachedSL {
+		slType = attachedExitType(order, "stopLossType")
+	} else if slType, err = at.placeStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
+		at.log().Infof("  ⚠ Failed to set stop loss: %v", err)
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if attachedTP {
+		tpType = attachedExitType(order, "takeProfitType")
+	} else {
+		tpType = at.placeTakeProfits(decision, "SHORT", quantity)
 	}
+	actionRecord.ExitOrderType = mergeExitOrderTypes(slType, tpType)
 
 	return nil
 }
 
 // executeCloseLongWithRecord executes close long position and records detailed information
 func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  🔄 Close long: %s", decision.Symbol)
+	at.log().Infof("  🔄 Close long: %s", decision.Symbol)
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
@@ -1260,7 +1532,7 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 	actionRecord.Price = marketData.CurrentPrice
 
 	// Normalize symbol for database lookup
-	normalizedSymbol := market.Normalize(decision.Symbol)
+	normalizedSymbol := at.normalizeSymbol(decision.Symbol)
 
 	// Get entry price and quantity - prioritize local database for accurate quantity
 	var entryPrice float64
@@ -1271,7 +1543,7 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 		if openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, normalizedSymbol, "LONG"); err == nil && openPos != nil {
 			quantity = openPos.Quantity
 			entryPrice = openPos.EntryPrice
-			logger.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+			at.log().Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 		}
 	}
 
@@ -1291,12 +1563,17 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 				}
 			}
 		}
-		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+		at.log().Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
 	// Close position
 	order, err := at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
 	if err != nil {
+		err = classifyOrderError(at.exchange, err)
+		if at.closeRejectedAsFlat(decision.Symbol, "long", err) {
+			at.log().Infof("  ✓ %s long position already closed on the exchange", decision.Symbol)
+			return nil
+		}
 		return err
 	}
 
@@ -1308,13 +1585,13 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
 
-	logger.Infof("  ✓ Position closed successfully")
+	at.log().Infof("  ✓ Position closed successfully")
 	return nil
 }
 
 // executeCloseShortWithRecord executes close short position and records detailed information
 func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
-	logger.Infof("  🔄 Close short: %s", decision.Symbol)
+	at.log().Infof("  🔄 Close short: %s", decision.Symbol)
 
 	// Get current price
 	marketData, err := market.Get(decision.Symbol)
@@ -1324,7 +1601,7 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 	actionRecord.Price = marketData.CurrentPrice
 
 	// Normalize symbol for database lookup
-	normalizedSymbol := market.Normalize(decision.Symbol)
+	normalizedSymbol := at.normalizeSymbol(decision.Symbol)
 
 	// Get entry price and quantity - prioritize local database for accurate quantity
 	var entryPrice float64
@@ -1335,7 +1612,7 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 		if openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, normalizedSymbol, "SHORT"); err == nil && openPos != nil {
 			quantity = openPos.Quantity
 			entryPrice = openPos.EntryPrice
-			logger.Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+			at.log().Infof("  📊 Using local position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 		}
 	}
 
@@ -1355,12 +1632,17 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 				}
 			}
 		}
-		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
+		at.log().Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
 	// Close position
 	order, err := at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
 	if err != nil {
+		err = classifyOrderError(at.exchange, err)
+		if at.closeRejectedAsFlat(decision.Symbol, "short", err) {
+			at.log().Infof("  ✓ %s short position already closed on the exchange", decision.Symbol)
+			return nil
+		}
 		return err
 	}
 
@@ -1372,7 +1654,7 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 	// Record order to database and poll for confirmation
 	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
 
-	logger.Infof("  ✓ Position closed successfully")
+	at.log().Infof("  ✓ Position closed successfully")
 	return nil
 }
 
@@ -1396,6 +1678,12 @@ func (at *AutoTrader) GetExchange() string {
 	return at.exchange
 }
 
+// normalizeSymbol normalizes symbol for the asset class of the trader's exchange,
+// crypto symbols in the trader's quote asset ("BTC" -> "BTCUSDC" on a USDC trader)
+func (at *AutoTrader) normalizeSymbol(symbol string) string {
+	return market.NormalizeForExchange(symbol, at.exchange, at.config.QuoteAsset)
+}
+
 // GetShowInCompetition returns whether trader should be shown in competition
 func (at *AutoTrader) GetShowInCompetition() bool {
 	return at.showInCompetition
@@ -1409,11 +1697,25 @@ func (at *AutoTrader) SetShowInCompetition(show bool) {
 // SetCustomPrompt sets custom trading strategy prompt
 func (at *AutoTrader) SetCustomPrompt(prompt string) {
 	at.customPrompt = prompt
+	if at.strategyEngine != nil {
+		at.strategyEngine.SetTraderPrompt(at.customPrompt, at.overrideBasePrompt)
+	}
 }
 
 // SetOverrideBasePrompt sets whether to override base prompt
 func (at *AutoTrader) SetOverrideBasePrompt(override bool) {
 	at.overrideBasePrompt = override
+	if at.strategyEngine != nil {
+		at.strategyEngine.SetTraderPrompt(at.customPrompt, at.overrideBasePrompt)
+	}
+}
+
+// GetStrategyConfig returns the strategy configuration the running engine uses (nil without an engine)
+func (at *AutoTrader) GetStrategyConfig() *store.StrategyConfig {
+	if at.strategyEngine == nil {
+		return nil
+	}
+	return at.strategyEngine.GetConfig()
 }
 
 // GetSystemPromptTemplate gets current system prompt template name (from strategy config)
@@ -1424,14 +1726,22 @@ func (at *AutoTrader) GetSystemPromptTemplate() string {
 			return "custom"
 		}
 	}
+	if at.customPrompt != "" {
+		return "custom"
+	}
 	return "strategy"
 }
 
 // saveEquitySnapshot saves equity snapshot independently (for drawing profit curve, decoupled from AI decision)
 func (at *AutoTrader) saveEquitySnapshot(ctx *kernel.Context) {
-	if at.store == nil || ctx == nil {
+	if ctx == nil {
 		return
 	}
+	at.updateEquityHighWaterMark(ctx.Account.TotalEquity)
+	if at.store == nil {
+		return
+	}
+	at.checkEquityAlerts(ctx.Account.TotalEquity)
 
 	snapshot := &store.EquitySnapshot{
 		TraderID:      at.id,
@@ -1444,7 +1754,7 @@ func (at *AutoTrader) saveEquitySnapshot(ctx *kernel.Context) {
 	}
 
 	if err := at.store.Equity().Save(snapshot); err != nil {
-		logger.Infof("⚠️ Failed to save equity snapshot: %v", err)
+		at.log().Infof("⚠️ Failed to save equity snapshot: %v", err)
 	}
 }
 
@@ -1462,12 +1772,23 @@ func (at *AutoTrader) saveDecision(record *store.DecisionRecord) error {
 		record.Timestamp = time.Now().UTC()
 	}
 
+	logger.LogPrompt(logger.PromptLogEntry{
+		TraderID:     at.id,
+		TraderName:   at.name,
+		Cycle:        record.CycleNumber,
+		Timestamp:    record.Timestamp,
+		SystemPrompt: record.SystemPrompt,
+		InputPrompt:  record.InputPrompt,
+		CoTTrace:     record.CoTTrace,
+		RawResponse:  record.RawResponse,
+	})
+
 	if err := at.store.Decision().LogDecision(record); err != nil {
-		logger.Infof("⚠️ Failed to save decision record: %v", err)
+		at.log().Infof("⚠️ Failed to save decision record: %v", err)
 		return err
 	}
 
-	logger.Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
+	at.log().Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
 	return nil
 }
 
@@ -1487,7 +1808,8 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 	isRunning := at.isRunning
 	at.isRunningMutex.RUnlock()
 
-	return map[string]interface{}{
+	stopUntil, stopReason := at.riskPause()
+	status := map[string]interface{}{
 		"trader_id":       at.id,
 		"trader_name":     at.name,
 		"ai_model":        at.aiModel,
@@ -1498,10 +1820,44 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		"call_count":      at.callCount,
 		"initial_balance": at.initialBalance,
 		"scan_interval":   at.config.ScanInterval.String(),
-		"stop_until":      at.stopUntil.Format(time.RFC3339),
+		"stop_until":      stopUntil.Format(time.RFC3339),
 		"last_reset_time": at.lastResetTime.Format(time.RFC3339),
 		"ai_provider":     aiProvider,
 	}
+	status["display_decimals"] = at.displayDecimals(at.initialBalance)
+	if at.orderSyncHealth != nil {
+		status["order_sync"] = at.orderSyncHealth.Status()
+	}
+	if mirror, ok := at.trader.(*MirrorTrader); ok {
+		status["mirror_exchanges"] = mirrorVenueStatus(mirror.Venues()[1:])
+	}
+	status["margin_used_pct"] = at.lastMarginUsedPct
+	status["max_total_margin_used_pct"] = at.maxTotalMarginUsedPct()
+	hwm, drawdownPct, drawdownAlert := at.equityHighWaterMark()
+	status["equity_high_water_mark"] = hwm
+	status["drawdown_from_hwm_pct"] = drawdownPct
+	status["drawdown_alert"] = drawdownAlert
+	status["drawdown_alert_pct"] = at.drawdownAlertPct()
+	if time.Now().Before(stopUntil) && stopReason != "" {
+		status["stop_reason"] = stopReason
+	}
+	if limit := at.maxConsecutiveLosses(); limit > 0 {
+		streak, cooldownUntil := at.lossStreakStatus(time.Now())
+		status["loss_streak"] = streak
+		status["max_consecutive_losses"] = limit
+		if !cooldownUntil.IsZero() {
+			status["loss_cooldown_until"] = cooldownUntil.Format(time.RFC3339)
+		}
+	}
+	if windows := at.tradingWindows(); len(windows) > 0 {
+		now := time.Now()
+		open := inTradingWindow(windows, now)
+		status["trading_window_open"] = open
+		if next, ok := nextTradingWindowOpen(windows, now); ok && !open {
+			status["next_trading_window"] = next.Format(time.RFC3339)
+		}
+	}
+	return status
 }
 
 // GetAccountInfo gets account information (for API)
@@ -1564,40 +1920,62 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 	// Note: Lighter API may return 0 for unrealized PnL, this is a known limitation
 	diff := math.Abs(totalUnrealizedProfit - totalUnrealizedPnLCalculated)
 	if diff > 5.0 { // Only warn if difference is significant (> 5 USDT)
-		logger.Infof("⚠️ Unrealized P&L inconsistency (Lighter API limitation): API=%.4f, Calculated=%.4f, Diff=%.4f",
+		at.log().Infof("⚠️ Unrealized P&L inconsistency (Lighter API limitation): API=%.4f, Calculated=%.4f, Diff=%.4f",
 			totalUnrealizedProfit, totalUnrealizedPnLCalculated, diff)
 	}
 
-	totalPnL := totalEquity - at.initialBalance
-	totalPnLPct := 0.0
-	if at.initialBalance > 0 {
-		totalPnLPct = (totalPnL / at.initialBalance) * 100
-	} else {
-		logger.Infof("⚠️ Initial Balance abnormal: %.2f, cannot calculate P&L percentage", at.initialBalance)
+	contributions := netContributions(at.store, at.id)
+	funding := netFunding(at.store, at.id)
+	totalPnL, totalPnLPct := TradingPnL(totalEquity, at.initialBalance, contributions)
+	if at.initialBalance+contributions <= 0 {
+		at.log().Infof("⚠️ Initial Balance abnormal: %.2f, cannot calculate P&L percentage", at.initialBalance)
 	}
 
+	// Amounts are rounded to the trader's display precision here so API consumers don't re-round
+	// them to 2 decimals; percentages keep full precision
+	decimals := at.displayDecimals(totalEquity)
+	quote := func(v float64) float64 { return roundQuote(v, decimals) }
+
 	marginUsedPct := 0.0
 	if totalEquity > 0 {
 		marginUsedPct = (totalMarginUsed / totalEquity) * 100
 	}
 
+	// Drawdown from the equity high-water mark (live equity vs tracked high)
+	hwm, _, drawdownAlert := at.equityHighWaterMark()
+	if totalEquity > hwm {
+		hwm = totalEquity
+	}
+	drawdownPct := 0.0
+	if hwm > 0 {
+		drawdownPct = (hwm - totalEquity) / hwm * 100
+	}
+
 	return map[string]interface{}{
 		// Core fields
-		"total_equity":      totalEquity,           // Account equity = wallet + unrealized
-		"wallet_balance":    totalWalletBalance,    // Wallet balance (excluding unrealized P&L)
-		"unrealized_profit": totalUnrealizedProfit, // Unrealized P&L (official value from exchange API)
-		"available_balance": availableBalance,      // Available balance
+		"total_equity":      quote(totalEquity),           // Account equity = wallet + unrealized
+		"wallet_balance":    quote(totalWalletBalance),    // Wallet balance (excluding unrealized P&L)
+		"unrealized_profit": quote(totalUnrealizedProfit), // Unrealized P&L (official value from exchange API)
+		"available_balance": quote(availableBalance),      // Available balance
+		"display_decimals":  decimals,                     // Decimal places of the amounts in this response
 
 		// P&L statistics
-		"total_pnl":       totalPnL,          // Total P&L = equity - initial
-		"total_pnl_pct":   totalPnLPct,       // Total P&L percentage
-		"initial_balance": at.initialBalance, // Initial balance
-		"daily_pnl":       at.dailyPnL,       // Daily P&L
+		"total_pnl":         quote(totalPnL),          // Total P&L = equity - initial - net contributions
+		"total_pnl_pct":     totalPnLPct,              // Total P&L percentage
+		"initial_balance":   quote(at.initialBalance), // Initial balance
+		"net_contributions": quote(contributions),     // Deposits - withdrawals since initial balance
+		"funding_total":     quote(funding),           // Cumulative funding received - paid (included in total P&L)
+		"daily_pnl":         quote(at.dailyPnL),       // Daily P&L
 
 		// Position information
-		"position_count":  len(positions),  // Position count
-		"margin_used":     totalMarginUsed, // Margin used
-		"margin_used_pct": marginUsedPct,   // Margin usage rate
+		"position_count":  len(positions),         // Position count
+		"margin_used":     quote(totalMarginUsed), // Margin used
+		"margin_used_pct": marginUsedPct,          // Margin usage rate
+
+		// Equity curve
+		"equity_high_water_mark": quote(hwm),    // Highest equity seen
+		"drawdown_from_hwm_pct":  drawdownPct,   // Drawdown from the high-water mark (%)
+		"drawdown_alert":         drawdownAlert, // Drawdown alert threshold breached
 	}, nil
 }
 
@@ -1668,7 +2046,7 @@ func sortDecisionsByPriority(decisions []kernel.Decision) []kernel.Decision {
 	// Define priority
 	getActionPriority := func(action string) int {
 		switch action {
-		case "close_long", "close_short":
+		case "close_long", "close_short", "reduce_long", "reduce_short":
 			return 1 // Highest priority: close positions first
 		case "open_long", "open_short":
 			return 2 // Second priority: open positions later
@@ -1704,14 +2082,14 @@ func (at *AutoTrader) startDrawdownMonitor() {
 		ticker := time.NewTicker(1 * time.Minute) // Check every minute
 		defer ticker.Stop()
 
-		logger.Info("📊 Started position drawdown monitoring (check every minute)")
+		at.log().Info("📊 Started position drawdown monitoring (check every minute)")
 
 		for {
 			select {
 			case <-ticker.C:
 				at.checkPositionDrawdown()
 			case <-at.stopMonitorCh:
-				logger.Info("⏹ Stopped position drawdown monitoring")
+				at.log().Info("⏹ Stopped position drawdown monitoring")
 				return
 			}
 		}
@@ -1723,10 +2101,13 @@ func (at *AutoTrader) checkPositionDrawdown() {
 	// Get current positions
 	positions, err := at.trader.GetPositions()
 	if err != nil {
-		logger.Infof("❌ Drawdown monitoring: failed to get positions: %v", err)
+		at.log().Infof("❌ Drawdown monitoring: failed to get positions: %v", err)
 		return
 	}
 
+	openPositions := make(map[string]bool, len(positions))
+	defer at.pruneBreakevenState(openPositions)
+
 	for _, pos := range positions {
 		symbol := pos["symbol"].(string)
 		side := pos["side"].(string)
@@ -1736,6 +2117,12 @@ func (at *AutoTrader) checkPositionDrawdown() {
 		if quantity < 0 {
 			quantity = -quantity // Short position quantity is negative, convert to positive
 		}
+		openPositions[symbol+"_"+side] = true
+
+		// Close positions held beyond risk_control.max_hold_minutes
+		if at.enforceMaxHoldTime(symbol, side, pos) {
+			continue
+		}
 
 		// Calculate current P&L percentage
 		leverage := 10 // Default value
@@ -1773,46 +2160,36 @@ func (at *AutoTrader) checkPositionDrawdown() {
 			drawdownPct = ((peakPnLPct - currentPnLPct) / peakPnLPct) * 100
 		}
 
+		// Move stop loss to breakeven once profit reaches risk_control.breakeven_trigger_pct
+		at.moveStopToBreakeven(symbol, side, entryPrice, markPrice, quantity, currentPnLPct)
+
 		// Check close position condition: profit > 5% and drawdown >= 40%
 		if currentPnLPct > 5.0 && drawdownPct >= 40.0 {
-			logger.Infof("🚨 Drawdown close position condition triggered: %s %s | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
+			at.log().Infof("🚨 Drawdown close position condition triggered: %s %s | Current profit: %.2f%% | Peak profit: %.2f%% | Drawdown: %.2f%%",
 				symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
 
 			// Execute close position
-			if err := at.emergencyClosePosition(symbol, side); err != nil {
-				logger.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
+			if err := at.emergencyClosePosition(symbol, side, quantity, markPrice, entryPrice); err != nil {
+				at.log().Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
 			} else {
-				logger.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
+				at.log().Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
 				// Clear cache for this position after closing
 				at.ClearPeakPnLCache(symbol, side)
 			}
 		} else if currentPnLPct > 5.0 {
 			// Record situations close to close position condition (for debugging)
-			logger.Infof("📊 Drawdown monitoring: %s %s | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
+			at.log().Infof("📊 Drawdown monitoring: %s %s | Profit: %.2f%% | Peak: %.2f%% | Drawdown: %.2f%%",
 				symbol, side, currentPnLPct, peakPnLPct, drawdownPct)
 		}
 	}
 }
 
-// emergencyClosePosition emergency close position function
-func (at *AutoTrader) emergencyClosePosition(symbol, side string) error {
-	switch side {
-	case "long":
-		order, err := at.trader.CloseLong(symbol, 0) // 0 = close all
-		if err != nil {
-			return err
-		}
-		logger.Infof("✅ Emergency close long position succeeded, order ID: %v", order["orderId"])
-	case "short":
-		order, err := at.trader.CloseShort(symbol, 0) // 0 = close all
-		if err != nil {
-			return err
-		}
-		logger.Infof("✅ Emergency close short position succeeded, order ID: %v", order["orderId"])
-	default:
-		return fmt.Errorf("unknown position direction: %s", side)
+// emergencyClosePosition emergency close position function (sliced when above the iceberg exit threshold)
+func (at *AutoTrader) emergencyClosePosition(symbol, side string, quantity, markPrice, entryPrice float64) error {
+	if err := at.closeFullPosition(symbol, side, quantity, markPrice, entryPrice); err != nil {
+		return err
 	}
-
+	at.log().Infof("✅ Emergency close %s position succeeded: %s", side, symbol)
 	return nil
 }
 
@@ -1863,21 +2240,9 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 		return
 	}
 
-	// Get order ID (supports multiple types)
-	var orderID string
-	switch v := orderResult["orderId"].(type) {
-	case int64:
-		orderID = fmt.Sprintf("%d", v)
-	case float64:
-		orderID = fmt.Sprintf("%.0f", v)
-	case string:
-		orderID = v
-	default:
-		orderID = fmt.Sprintf("%v", v)
-	}
-
+	orderID := orderIDString(orderResult)
 	if orderID == "" || orderID == "0" {
-		logger.Infof("  ⚠️ Order ID is empty, skipping record")
+		at.log().Infof("  ⚠️ Order ID is empty, skipping record")
 		return
 	}
 
@@ -1893,69 +2258,77 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	var actualPrice = price
 	var actualQty = quantity
 	var fee float64
+	var rawFee float64
+	var feeAsset string
+
+	// Exchanges with a private order stream (Binance, Bybit) confirm the fill from the stream (polling as
+	// fallback) before the OrderSync shortcut below, then sync the fill right away
+	if _, ok := orderFillWaiter(at.trader); ok && at.config.OrderFill.UseUserStream && at.orderSyncTrigger != nil {
+		rememberOrderTrace(at.exchangeID, orderID, at.traceID())
+		statusStr := "not confirmed"
+		if status := at.confirmOrderFill(symbol, orderID); status != nil {
+			statusStr, _ = status["status"].(string)
+		}
+		at.log().Infof("  📝 Order submitted (id: %s, %s), syncing fills now", orderID, statusStr)
+		at.requestOrderSync()
+		return
+	}
 
 	// Exchanges with OrderSync: Skip immediate order recording, let OrderSync handle it
 	// This ensures accurate data from GetTrades API and avoids duplicate records
 	switch at.exchange {
-	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster":
-		logger.Infof("  📝 Order submitted (id: %s), will be synced by OrderSync", orderID)
+	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "coinbase", "backpack":
+		rememberOrderTrace(at.exchangeID, orderID, at.traceID())
+		at.log().Infof("  📝 Order submitted (id: %s), will be synced by OrderSync", orderID)
 		return
 	}
 
 	// For exchanges without OrderSync (e.g., Binance): record immediately and poll for fill data
 	orderRecord := at.createOrderRecord(orderID, symbol, action, positionSide, quantity, price, leverage)
 	if err := at.store.Order().CreateOrder(orderRecord); err != nil {
-		logger.Infof("  ⚠️ Failed to record order: %v", err)
+		at.log().Infof("  ⚠️ Failed to record order: %v", err)
 	} else {
-		logger.Infof("  📝 Order recorded: %s [%s] %s", orderID, action, symbol)
+		at.log().Infof("  📝 Order recorded: %s [%s] %s", orderID, action, symbol)
 	}
 
-	// Wait for order to be filled and get actual fill data
-	time.Sleep(500 * time.Millisecond)
-	for i := 0; i < 5; i++ {
-		status, err := at.trader.GetOrderStatus(symbol, orderID)
-		if err == nil {
-			statusStr, _ := status["status"].(string)
-			if statusStr == "FILLED" {
-				// Get actual fill price
-				if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
-					actualPrice = avgPrice
-				}
-				// Get actual executed quantity
-				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
-					actualQty = execQty
-				}
-				// Get commission/fee
-				if commission, ok := status["commission"].(float64); ok {
-					fee = commission
-				}
-				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+	// Wait for order to be filled and get actual fill data (order stream or polling)
+	if status := at.confirmOrderFill(symbol, orderID); status != nil {
+		statusStr, _ := status["status"].(string)
+		if statusStr == "FILLED" {
+			// Get actual fill price
+			if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
+				actualPrice = avgPrice
+			}
+			// Get actual executed quantity
+			if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
+				actualQty = execQty
+			}
+			// Get commission/fee, valued in the quote currency when charged in another asset
+			rawFee, feeAsset, fee = OrderStatusCommission(at.trader, symbol, status, actualPrice)
+			at.log().Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
 
-				// Update order status to FILLED
-				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, "FILLED", actualQty, actualPrice, fee); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
-				}
+			// Update order status to FILLED
+			if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, "FILLED", actualQty, actualPrice, fee); err != nil {
+				at.log().Infof("  ⚠️ Failed to update order status: %v", err)
+			}
 
-				// Record fill details
-				at.recordOrderFill(orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, fee)
-				break
-			} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
-				logger.Infof("  ⚠️ Order %s, skipping position record", statusStr)
+			// Record fill details
+			at.recordOrderFill(orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, rawFee, feeAsset, fee)
+		} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
+			at.log().Infof("  ⚠️ Order %s, skipping position record", statusStr)
 
-				// Update order status
-				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, statusStr, 0, 0, 0); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
-				}
-				return
+			// Update order status
+			if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, statusStr, 0, 0, 0); err != nil {
+				at.log().Infof("  ⚠️ Failed to update order status: %v", err)
 			}
+			return
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
 
 	// Normalize symbol for position record consistency
-	normalizedSymbolForPosition := market.Normalize(symbol)
+	normalizedSymbolForPosition := at.normalizeSymbol(symbol)
 
-	logger.Infof("  📝 Recording position (ID: %s, action: %s, price: %.6f, qty: %.6f, fee: %.4f)",
+	at.log().Infof("  📝 Recording position (ID: %s, action: %s, price: %.6f, qty: %.6f, fee: %.4f)",
 		orderID, action, actualPrice, actualQty, fee)
 
 	// Record position change with actual fill data (use normalized symbol)
@@ -2000,9 +2373,9 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			UpdatedAt:    nowMs,
 		}
 		if err := at.store.Position().Create(pos); err != nil {
-			logger.Infof("  ⚠️ Failed to record position: %v", err)
+			at.log().Infof("  ⚠️ Failed to record position: %v", err)
 		} else {
-			logger.Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
+			at.log().Infof("  📊 Position recorded [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
 		}
 
 	case "close_long", "close_short":
@@ -2017,9 +2390,9 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 			quantity, price, fee, 0, // realizedPnL will be calculated
 			time.Now().UTC().UnixMilli(), orderID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to process close position: %v", err)
+			at.log().Infof("  ⚠️ Failed to process close position: %v", err)
 		} else {
-			logger.Infof("  ✅ Position closed [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
+			at.log().Infof("  ✅ Position closed [%s] %s %s @ %.4f", at.id[:8], symbol, side, price)
 		}
 	}
 }
@@ -2045,7 +2418,7 @@ func (at *AutoTrader) createOrderRecord(orderID, symbol, action, positionSide st
 	reduceOnly := (action == "close_long" || action == "close_short")
 
 	// Normalize symbol for consistency
-	normalizedSymbol := market.Normalize(symbol)
+	normalizedSymbol := at.normalizeSymbol(symbol)
 
 	return &store.TraderOrder{
 		TraderID:        at.id,
@@ -2068,13 +2441,14 @@ func (at *AutoTrader) createOrderRecord(orderID, symbol, action, positionSide st
 		ReduceOnly:      reduceOnly,
 		ClosePosition:   reduceOnly,
 		OrderAction:     orderAction,
+		TraceID:         at.traceID(),
 		CreatedAt:       time.Now().UTC().UnixMilli(),
 		UpdatedAt:       time.Now().UTC().UnixMilli(),
 	}
 }
 
-// recordOrderFill records order fill/trade details
-func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symbol, action string, price, quantity, fee float64) {
+// recordOrderFill records order fill/trade details (fee is charged in feeAsset, feeUSDT is its quote-currency value)
+func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symbol, action string, price, quantity, fee float64, feeAsset string, feeUSDT float64) {
 	if at.store == nil {
 		return
 	}
@@ -2092,7 +2466,7 @@ func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symb
 	tradeID := fmt.Sprintf("%s-%d", exchangeOrderID, time.Now().UnixNano())
 
 	// Normalize symbol for consistency
-	normalizedSymbol := market.Normalize(symbol)
+	normalizedSymbol := at.normalizeSymbol(symbol)
 
 	fill := &store.TraderFill{
 		TraderID:         at.id,
@@ -2107,9 +2481,11 @@ func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symb
 		Quantity:         quantity,
 		QuoteQuantity:    price * quantity,
 		Commission:       fee,
-		CommissionAsset:  "USDT",
+		CommissionAsset:  commissionAsset(feeAsset),
+		CommissionUSDT:   feeUSDT,
 		RealizedPnL:      0, // Will be calculated for close orders
 		IsMaker:          false, // Market orders are usually taker
+		TraceID:          at.traceID(),
 		CreatedAt:        time.Now().UTC().UnixMilli(),
 	}
 
@@ -2133,9 +2509,9 @@ func (at *AutoTrader) recordOrderFill(orderRecordID int64, exchangeOrderID, symb
 	}
 
 	if err := at.store.Order().CreateFill(fill); err != nil {
-		logger.Infof("  ⚠️ Failed to record fill: %v", err)
+		at.log().Infof("  ⚠️ Failed to record fill: %v", err)
 	} else {
-		logger.Infof("  📋 Fill recorded: %.4f @ %.6f, fee: %.4f", quantity, price, fee)
+		at.log().Infof("  📋 Fill recorded: %.4f @ %.6f, fee: %.4f", quantity, price, fee)
 	}
 }
 
@@ -2180,7 +2556,7 @@ func (at *AutoTrader) enforcePositionValueRatio(positionSizeUSD float64, equity
 
 	// Check if position size exceeds limit
 	if positionSizeUSD > maxPositionValue {
-		logger.Infof("  ⚠️ [RISK CONTROL] Position %.2f USDT exceeds limit (equity %.2f × %.1fx = %.2f USDT max for %s), capping",
+		at.log().Infof("  ⚠️ [RISK CONTROL] Position %.2f USDT exceeds limit (equity %.2f × %.1fx = %.2f USDT max for %s), capping",
 			positionSizeUSD, equity, maxPositionValueRatio, maxPositionValue, symbol)
 		return maxPositionValue, true
 	}
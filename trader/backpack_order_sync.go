@@ -0,0 +1,242 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/store"
+	"sort"
+	"time"
+)
+
+// BackpackTrade represents a fill from Backpack
+type BackpackTrade struct {
+	Symbol    string // Generic symbol (BTCUSDT)
+	TradeID   string
+	OrderID   string
+	Side      string // BUY or SELL
+	FillPrice float64
+	FillQty   float64
+	Fee       float64
+	FeeAsset  string
+	IsMaker   bool
+	ExecTime  time.Time
+}
+
+// parseBackpackTime parses Backpack timestamps (ISO 8601 in UTC, usually without zone)
+func parseBackpackTime(s string) time.Time {
+	if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return ts.UTC()
+	}
+	ts, _ := time.ParseInLocation("2006-01-02T15:04:05", s, time.UTC)
+	return ts
+}
+
+// getFills queries the account's perpetual fills with extra filters (orderId, from, limit, ...)
+func (t *BackpackTrader) getFills(filters map[string]interface{}) ([]BackpackTrade, error) {
+	params := map[string]interface{}{"marketType": "PERP"}
+	for k, v := range filters {
+		params[k] = v
+	}
+
+	data, err := t.doRequest("GET", backpackFillsPath, "fillHistoryQueryAll", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	var fills []struct {
+		TradeID   json.Number   `json:"tradeId"`
+		OrderID   string        `json:"orderId"`
+		Symbol    string        `json:"symbol"` // BTC_USDC_PERP
+		Side      string        `json:"side"`   // Bid, Ask
+		Price     backpackFloat `json:"price"`
+		Quantity  backpackFloat `json:"quantity"`
+		Fee       backpackFloat `json:"fee"`
+		FeeSymbol string        `json:"feeSymbol"`
+		IsMaker   bool          `json:"isMaker"`
+		Timestamp string        `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &fills); err != nil {
+		return nil, fmt.Errorf("failed to parse fills: %w", err)
+	}
+
+	trades := make([]BackpackTrade, 0, len(fills))
+	for _, fill := range fills {
+		tradeID := fill.TradeID.String()
+		if tradeID == "" {
+			// Fills without trade ID (e.g. settlements) can't be deduplicated
+			continue
+		}
+		trades = append(trades, BackpackTrade{
+			Symbol:    t.convertSymbolBack(fill.Symbol),
+			TradeID:   tradeID,
+			OrderID:   fill.OrderID,
+			Side:      backpackSide(fill.Side),
+			FillPrice: float64(fill.Price),
+			FillQty:   float64(fill.Quantity),
+			Fee:       float64(fill.Fee),
+			FeeAsset:  fill.FeeSymbol,
+			IsMaker:   fill.IsMaker,
+			ExecTime:  parseBackpackTime(fill.Timestamp),
+		})
+	}
+	return trades, nil
+}
+
+// GetTrades retrieves fills from Backpack since startTime
+func (t *BackpackTrader) GetTrades(startTime time.Time, limit int) ([]BackpackTrade, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	return t.getFills(map[string]interface{}{
+		"from":  startTime.UnixMilli(),
+		"limit": limit,
+	})
+}
+
+// backpackOrderAction infers the order action of a fill.
+// Fills don't say whether they opened or closed, so a fill against a locally open position
+// on the opposite side is a close, anything else opens (or adds to) a position.
+func backpackOrderAction(side string, openLong, openShort bool) string {
+	if side == "SELL" {
+		if openLong {
+			return "close_long"
+		}
+		return "open_short"
+	}
+	if openShort {
+		return "close_short"
+	}
+	return "open_long"
+}
+
+// SyncOrdersFromBackpack syncs Backpack fills to local database
+// Also creates/updates position records to ensure orders/fills/positions data consistency
+// exchangeID: Exchange account UUID (from exchanges.id)
+// exchangeType: Exchange type ("backpack")
+func (t *BackpackTrader) SyncOrdersFromBackpack(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	if st == nil {
+		return fmt.Errorf("store is nil")
+	}
+
+	// Get recent trades (last 24 hours)
+	startTime := time.Now().Add(-24 * time.Hour)
+
+	t.log().Infof("🔄 Syncing Backpack trades from: %s", startTime.Format(time.RFC3339))
+
+	trades, err := t.GetTrades(startTime, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	t.log().Infof("📥 Received %d trades from Backpack", len(trades))
+
+	// Sort trades by time ASC (oldest first) for proper position building
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ExecTime.UnixMilli() < trades[j].ExecTime.UnixMilli()
+	})
+
+	// Process trades one by one (no transaction to avoid deadlock)
+	orderStore := st.Order()
+	positionStore := st.Position()
+	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
+	syncedCount := 0
+
+	for _, trade := range trades {
+		// Check if trade already exists (use exchangeID which is UUID, not exchange type)
+		existing, err := orderStore.GetOrderByExchangeID(exchangeID, trade.TradeID)
+		if err == nil && existing != nil {
+			continue // Order already exists, skip
+		}
+
+		symbol := trade.Symbol
+		longPos, _ := positionStore.GetOpenPositionBySymbol(traderID, symbol, "LONG")
+		shortPos, _ := positionStore.GetOpenPositionBySymbol(traderID, symbol, "SHORT")
+		orderAction := backpackOrderAction(trade.Side, longPos != nil, shortPos != nil)
+
+		positionSide := "LONG"
+		if orderAction == "open_short" || orderAction == "close_short" {
+			positionSide = "SHORT"
+		}
+
+		// Fees charged in the base coin or a discount token are valued in the quote currency
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.FillPrice)
+
+		// Create order record - use UTC time in milliseconds to avoid timezone issues
+		execTimeMs := trade.ExecTime.UnixMilli()
+		orderRecord := &store.TraderOrder{
+			TraderID:        traderID,
+			ExchangeID:      exchangeID,   // UUID
+			ExchangeType:    exchangeType, // Exchange type
+			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
+			Symbol:          symbol,
+			Side:            trade.Side,
+			PositionSide:    "BOTH", // Backpack nets positions per market
+			Type:            "MARKET",
+			OrderAction:     orderAction,
+			Quantity:        trade.FillQty,
+			Price:           trade.FillPrice,
+			Status:          "FILLED",
+			FilledQuantity:  trade.FillQty,
+			AvgFillPrice:    trade.FillPrice,
+			Commission:      feeUSDT,
+			FilledAt:        execTimeMs,
+			CreatedAt:       execTimeMs,
+			UpdatedAt:       execTimeMs,
+		}
+
+		if err := orderStore.CreateOrder(orderRecord); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			continue
+		}
+
+		fillRecord := &store.TraderFill{
+			TraderID:        traderID,
+			ExchangeID:      exchangeID,   // UUID
+			ExchangeType:    exchangeType, // Exchange type
+			OrderID:         orderRecord.ID,
+			ExchangeOrderID: trade.OrderID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
+			ExchangeTradeID: trade.TradeID,
+			Symbol:          symbol,
+			Side:            trade.Side,
+			Price:           trade.FillPrice,
+			Quantity:        trade.FillQty,
+			QuoteQuantity:   trade.FillPrice * trade.FillQty,
+			Commission:      trade.Fee,
+			CommissionAsset: trade.FeeAsset,
+			CommissionUSDT:  feeUSDT,
+			IsMaker:         trade.IsMaker,
+			CreatedAt:       execTimeMs,
+		}
+
+		if err := orderStore.CreateFill(fillRecord); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+		}
+
+		// Realized PnL isn't reported per fill, PositionBuilder derives it from the entry price
+		if err := posBuilder.ProcessTrade(
+			traderID, exchangeID, exchangeType,
+			symbol, positionSide, orderAction,
+			trade.FillQty, trade.FillPrice, feeUSDT, 0,
+			execTimeMs, trade.TradeID,
+		); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+		} else {
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.FillQty)
+		}
+
+		syncedCount++
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f fee=%.6f action=%s",
+			trade.TradeID, symbol, trade.Side, trade.FillQty, trade.FillPrice, trade.Fee, orderAction)
+	}
+
+	t.log().Infof("✅ Backpack order sync completed: %d new trades synced", syncedCount)
+	return nil
+}
+
+// SyncOrders implements OrderSyncer for Backpack
+func (t *BackpackTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromBackpack(traderID, exchangeID, exchangeType, st)
+}
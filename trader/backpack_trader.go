@@ -0,0 +1,970 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/store"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backpack Exchange API endpoints
+const (
+	backpackBaseURL        = "https://api.backpack.exchange"
+	backpackCollateralPath = "/api/v1/capital/collateral"
+	backpackPositionPath   = "/api/v1/position"
+	backpackAccountPath    = "/api/v1/account"
+	backpackMarketPath     = "/api/v1/market"
+	backpackTickerPath     = "/api/v1/ticker"
+	backpackOrderPath      = "/api/v1/order"
+	backpackOrdersPath     = "/api/v1/orders"
+	backpackFillsPath      = "/wapi/v1/history/fills"
+
+	// backpackWindow validity window of signed requests in milliseconds
+	backpackWindow = "5000"
+)
+
+// Client ID tags (last decimal digit) used to tell our exit orders apart in the open order list.
+// Backpack client IDs are uint32, so a string prefix like other exchanges use isn't possible.
+const (
+	backpackTagOrder      uint32 = 0
+	backpackTagStopLoss   uint32 = 1
+	backpackTagTakeProfit uint32 = 2
+)
+
+// BackpackTrader Backpack Exchange perpetual futures trader
+// Perpetuals are USDC-margined and quoted as BASE_USDC_PERP; nofx symbols (BTCUSDT) are mapped to them.
+// Requests are signed with the account's ED25519 key: the API key is the base64 public key,
+// the secret the base64 private key seed.
+type BackpackTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
+	apiKey    string
+	secretKey string
+
+	// Additional API keys of the account requests rotate across (nil = primary key only)
+	keys *APIKeyPool
+
+	// HTTP client
+	httpClient *http.Client
+
+	// Account-wide leverage limit (0 = not fetched yet)
+	leverageLimit int
+	leverageMutex sync.Mutex
+
+	// Leverage requested per symbol (Backpack margins at account level, this is only reported on positions)
+	leverages      map[string]int
+	leveragesMutex sync.RWMutex
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Market info cache
+	marketsCache      map[string]*BackpackMarket
+	marketsCacheMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// BackpackMarket Backpack market info
+type BackpackMarket struct {
+	Symbol      string  // Market symbol (BTC_USDC_PERP)
+	StepSize    float64 // Quantity step
+	TickSize    float64 // Price tick
+	MinQuantity float64 // Minimum order quantity
+}
+
+// backpackOrder Backpack order (subset)
+type backpackOrder struct {
+	ID               string        `json:"id"`
+	ClientID         uint32        `json:"clientId"`
+	Symbol           string        `json:"symbol"`
+	Side             string        `json:"side"`      // Bid, Ask
+	OrderType        string        `json:"orderType"` // Market, Limit
+	Quantity         backpackFloat `json:"quantity"`
+	ExecutedQuantity backpackFloat `json:"executedQuantity"`
+	ExecutedQuote    backpackFloat `json:"executedQuoteQuantity"`
+	Price            backpackFloat `json:"price"`
+	TriggerPrice     backpackFloat `json:"triggerPrice"`
+	Status           string        `json:"status"` // New, PartiallyFilled, Filled, Cancelled, Expired, TriggerPending, TriggerFailed
+	ReduceOnly       bool          `json:"reduceOnly"`
+}
+
+// backpackFloat decodes Backpack numeric fields, which are sent as JSON strings (sometimes numbers or null)
+type backpackFloat float64
+
+func (f *backpackFloat) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = backpackFloat(v)
+	return nil
+}
+
+// NewBackpackTrader creates a Backpack trader
+func NewBackpackTrader(apiKey, secretKey string) *BackpackTrader {
+	trader := &BackpackTrader{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: http.DefaultTransport,
+		},
+		leverages:     make(map[string]int),
+		cacheDuration: 15 * time.Second,
+		marketsCache:  make(map[string]*BackpackMarket),
+	}
+
+	logger.Infof("🎒 [Backpack] Trader initialized")
+	return trader
+}
+
+// AddAPIKeys rotates requests across the primary and the additional API keys (MultiKeyTrader)
+func (t *BackpackTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	t.keys = NewAPIKeyPool(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey}, keys)
+}
+
+// backpackParamString formats a request parameter the way it appears in the signing payload
+func backpackParamString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// backpackSigningPayload builds the string signed for a request:
+// instruction=<instruction>&<params sorted by key>&timestamp=<ms>&window=<ms>
+func backpackSigningPayload(instruction string, params map[string]interface{}, timestamp, window string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("instruction=" + instruction)
+	for _, k := range keys {
+		b.WriteString("&" + k + "=" + backpackParamString(params[k]))
+	}
+	b.WriteString("&timestamp=" + timestamp + "&window=" + window)
+	return b.String()
+}
+
+// sign generates the Backpack request signature
+// Signature = BASE64(ED25519_SIGN(payload, BASE64_DECODE(secret seed)))
+func (t *BackpackTrader) sign(secretKey, payload string) (string, error) {
+	seed, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("invalid Backpack API secret: expected base64 encoded %d-byte ED25519 seed", ed25519.SeedSize)
+	}
+	signature := ed25519.Sign(ed25519.NewKeyFromSeed(seed), []byte(payload))
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// doRequest executes an HTTP request; instruction "" is a public request without signature.
+// GET parameters are sent in the query string, other methods send them as JSON body.
+func (t *BackpackTrader) doRequest(method, path, instruction string, params map[string]interface{}) ([]byte, error) {
+	reqURL := backpackBaseURL + path
+	var bodyBytes []byte
+	if method == "GET" {
+		if len(params) > 0 {
+			query := url.Values{}
+			for k, v := range params {
+				query.Set(k, backpackParamString(v))
+			}
+			reqURL += "?" + query.Encode()
+		}
+	} else if params != nil {
+		var err error
+		bodyBytes, err = json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "application/json")
+
+	var key *PooledAPIKey
+	if instruction != "" {
+		var cred store.APIKeyCredential
+		key, cred = t.keys.Next(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey})
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		signature, err := t.sign(cred.SecretKey, backpackSigningPayload(instruction, params, timestamp, backpackWindow))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-API-Key", cred.APIKey)
+		req.Header.Set("X-Signature", signature)
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Window", backpackWindow)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if instruction != "" {
+		t.keys.ObserveResponse(key, resp)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Backpack API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// convertSymbol converts generic symbol to Backpack perpetual format
+// e.g., BTCUSDT -> BTC_USDC_PERP
+func (t *BackpackTrader) convertSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.HasSuffix(symbol, "_PERP") {
+		return symbol
+	}
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote) + "_USDC_PERP"
+		}
+	}
+	return symbol + "_USDC_PERP"
+}
+
+// convertSymbolBack converts Backpack perpetual format to generic symbol
+// e.g., BTC_USDC_PERP -> BTCUSDT
+func (t *BackpackTrader) convertSymbolBack(market string) string {
+	base := strings.TrimSuffix(strings.ToUpper(market), "_PERP")
+	if i := strings.Index(base, "_"); i > 0 {
+		base = base[:i]
+	}
+	return base + "USDT"
+}
+
+// GetBalance gets account balance
+func (t *BackpackTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", backpackCollateralPath, "collateralQuery", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	var collateral struct {
+		NetEquity          backpackFloat `json:"netEquity"`          // Collateral value + unrealized P&L
+		NetEquityAvailable backpackFloat `json:"netEquityAvailable"` // Equity not locked by orders or positions
+		PnlUnrealized      backpackFloat `json:"pnlUnrealized"`      // Unrealized P&L
+	}
+	if err := json.Unmarshal(data, &collateral); err != nil {
+		return nil, fmt.Errorf("failed to parse balance data: %w, raw: %s", err, string(data))
+	}
+
+	totalEquity := float64(collateral.NetEquity)
+	unrealizedPnL := float64(collateral.PnlUnrealized)
+	availableBalance := math.Max(float64(collateral.NetEquityAvailable), 0)
+	t.log().Infof("✓ [Backpack] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    totalEquity - unrealizedPnL,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+		"total_equity":          totalEquity,
+	}
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+func (t *BackpackTrader) GetPositions() ([]map[string]interface{}, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", backpackPositionPath, "positionQuery", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var positions []struct {
+		Symbol              string        `json:"symbol"`              // BTC_USDC_PERP
+		NetQuantity         backpackFloat `json:"netQuantity"`         // Signed position size (negative = short)
+		EntryPrice          backpackFloat `json:"entryPrice"`          // Average entry price
+		MarkPrice           backpackFloat `json:"markPrice"`           // Mark price
+		PnlUnrealized       backpackFloat `json:"pnlUnrealized"`       // Unrealized P&L
+		EstLiquidationPrice backpackFloat `json:"estLiquidationPrice"` // Estimated liquidation price
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for _, pos := range positions {
+		netQty := float64(pos.NetQuantity)
+		if netQty == 0 {
+			continue
+		}
+
+		// Normalize side
+		side := "long"
+		if netQty < 0 {
+			side = "short"
+		}
+		symbol := t.convertSymbolBack(pos.Symbol)
+
+		posMap := map[string]interface{}{
+			"symbol":           symbol,
+			"positionAmt":      math.Abs(netQty),
+			"entryPrice":       float64(pos.EntryPrice),
+			"markPrice":        float64(pos.MarkPrice),
+			"unRealizedProfit": float64(pos.PnlUnrealized),
+			"leverage":         float64(t.getLeverage(symbol)),
+			"liquidationPrice": float64(pos.EstLiquidationPrice),
+			"side":             side,
+		}
+		result = append(result, posMap)
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// getMarket gets market info (cached)
+func (t *BackpackTrader) getMarket(symbol string) (*BackpackMarket, error) {
+	market := t.convertSymbol(symbol)
+
+	t.marketsCacheMutex.RLock()
+	if info, ok := t.marketsCache[market]; ok {
+		t.marketsCacheMutex.RUnlock()
+		return info, nil
+	}
+	t.marketsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", backpackMarketPath, "", map[string]interface{}{"symbol": market})
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Symbol  string `json:"symbol"`
+		Filters struct {
+			Price struct {
+				TickSize backpackFloat `json:"tickSize"`
+			} `json:"price"`
+			Quantity struct {
+				StepSize    backpackFloat `json:"stepSize"`
+				MinQuantity backpackFloat `json:"minQuantity"`
+			} `json:"quantity"`
+		} `json:"filters"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse market info: %w", err)
+	}
+
+	info := &BackpackMarket{
+		Symbol:      market,
+		StepSize:    float64(raw.Filters.Quantity.StepSize),
+		TickSize:    float64(raw.Filters.Price.TickSize),
+		MinQuantity: float64(raw.Filters.Quantity.MinQuantity),
+	}
+
+	t.marketsCacheMutex.Lock()
+	t.marketsCache[market] = info
+	t.marketsCacheMutex.Unlock()
+
+	return info, nil
+}
+
+// SetMarginMode sets margin mode
+// Backpack accounts are always cross margin (the whole collateral backs all positions)
+func (t *BackpackTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		t.log().Infof("  ⚠️ Backpack only supports account cross margin, %s stays on cross margin", t.convertSymbol(symbol))
+	}
+	return nil
+}
+
+// SetLeverage sets leverage
+// Backpack has a single account-wide leverage limit: it is raised when a symbol needs more,
+// never lowered, so existing positions keep their margin. The per-symbol value is only reported.
+func (t *BackpackTrader) SetLeverage(symbol string, leverage int) error {
+	t.leveragesMutex.Lock()
+	t.leverages[strings.ToUpper(symbol)] = leverage
+	t.leveragesMutex.Unlock()
+
+	t.leverageMutex.Lock()
+	defer t.leverageMutex.Unlock()
+
+	if t.leverageLimit == 0 {
+		data, err := t.doRequest("GET", backpackAccountPath, "accountQuery", nil)
+		if err != nil {
+			return fmt.Errorf("failed to get account settings: %w", err)
+		}
+		var account struct {
+			LeverageLimit backpackFloat `json:"leverageLimit"`
+		}
+		if err := json.Unmarshal(data, &account); err != nil {
+			return fmt.Errorf("failed to parse account settings: %w", err)
+		}
+		t.leverageLimit = int(account.LeverageLimit)
+	}
+
+	if leverage <= t.leverageLimit {
+		t.log().Infof("  ✓ %s leverage %dx within account limit %dx", t.convertSymbol(symbol), leverage, t.leverageLimit)
+		return nil
+	}
+
+	params := map[string]interface{}{"leverageLimit": strconv.Itoa(leverage)}
+	if _, err := t.doRequest("PATCH", backpackAccountPath, "accountUpdate", params); err != nil {
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+	t.leverageLimit = leverage
+
+	t.log().Infof("  ✓ Backpack account leverage limit raised to %dx for %s", leverage, t.convertSymbol(symbol))
+	return nil
+}
+
+// getLeverage returns the leverage recorded for a symbol (1 if never set)
+func (t *BackpackTrader) getLeverage(symbol string) int {
+	t.leveragesMutex.RLock()
+	defer t.leveragesMutex.RUnlock()
+	if leverage, ok := t.leverages[strings.ToUpper(symbol)]; ok && leverage > 0 {
+		return leverage
+	}
+	return 1
+}
+
+// placeOrder places an order, tagging its client ID with the order kind
+func (t *BackpackTrader) placeOrder(params map[string]interface{}, tag uint32) (*backpackOrder, error) {
+	params["clientId"] = genBackpackClientID(tag)
+
+	data, err := t.doRequest("POST", backpackOrderPath, "orderExecute", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order backpackOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	return &order, nil
+}
+
+// marketOrder places a market order; reduceOnly orders can only reduce the position
+func (t *BackpackTrader) marketOrder(symbol, side string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	params := map[string]interface{}{
+		"symbol":    t.convertSymbol(symbol),
+		"side":      side,
+		"orderType": "Market",
+		"quantity":  qtyStr,
+	}
+	if reduceOnly {
+		params["reduceOnly"] = true
+	}
+	order, err := t.placeOrder(params, backpackTagOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	return map[string]interface{}{
+		"orderId": order.ID,
+		"symbol":  symbol,
+		"status":  backpackOrderStatus(order.Status),
+	}, nil
+}
+
+// OpenLong opens long position
+func (t *BackpackTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders first
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	t.log().Infof("  📊 Backpack OpenLong: symbol=%s, qty=%.6f, leverage=%d", t.convertSymbol(symbol), quantity, leverage)
+
+	result, err := t.marketOrder(symbol, "Bid", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open long position: %w", err)
+	}
+
+	t.log().Infof("✓ Backpack opened long position successfully: %s", symbol)
+	return result, nil
+}
+
+// OpenShort opens short position
+func (t *BackpackTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders first
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	t.log().Infof("  📊 Backpack OpenShort: symbol=%s, qty=%.6f, leverage=%d", t.convertSymbol(symbol), quantity, leverage)
+
+	result, err := t.marketOrder(symbol, "Ask", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open short position: %w", err)
+	}
+
+	t.log().Infof("✓ Backpack opened short position successfully: %s", symbol)
+	return result, nil
+}
+
+// positionQuantity returns the size of the open position on one side (0 if none)
+func (t *BackpackTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	symbol = t.convertSymbolBack(t.convertSymbol(symbol))
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+	return 0, nil
+}
+
+// CloseLong closes long position
+func (t *BackpackTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "long"); err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("long position not found for %s", symbol)
+		}
+	}
+
+	t.log().Infof("  📊 Backpack CloseLong: symbol=%s, qty=%.6f", t.convertSymbol(symbol), quantity)
+
+	result, err := t.marketOrder(symbol, "Ask", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close long position: %w", err)
+	}
+
+	t.log().Infof("✓ Backpack closed long position successfully: %s", symbol)
+	return result, nil
+}
+
+// CloseShort closes short position
+func (t *BackpackTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "short"); err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("short position not found for %s", symbol)
+		}
+	}
+
+	t.log().Infof("  📊 Backpack CloseShort: symbol=%s, qty=%.6f", t.convertSymbol(symbol), quantity)
+
+	result, err := t.marketOrder(symbol, "Bid", math.Abs(quantity), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close short position: %w", err)
+	}
+
+	t.log().Infof("✓ Backpack closed short position successfully: %s", symbol)
+	return result, nil
+}
+
+// GetMarketPrice gets market price (last trade)
+func (t *BackpackTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.doRequest("GET", backpackTickerPath, "", map[string]interface{}{"symbol": t.convertSymbol(symbol)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	var ticker struct {
+		LastPrice backpackFloat `json:"lastPrice"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, err
+	}
+
+	price := float64(ticker.LastPrice)
+	if price <= 0 {
+		return 0, fmt.Errorf("no price data received")
+	}
+	return price, nil
+}
+
+// backpackExitSide returns the order side closing a position
+func backpackExitSide(positionSide string) string {
+	if strings.ToUpper(positionSide) == "SHORT" {
+		return "Bid"
+	}
+	return "Ask"
+}
+
+// placeTriggerOrder places a reduce-only market order that triggers at triggerPrice
+func (t *BackpackTrader) placeTriggerOrder(symbol, positionSide string, quantity, triggerPrice float64, tag uint32) error {
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	_, err := t.placeOrder(map[string]interface{}{
+		"symbol":       t.convertSymbol(symbol),
+		"side":         backpackExitSide(positionSide),
+		"orderType":    "Market",
+		"quantity":     qtyStr,
+		"triggerPrice": t.formatPrice(symbol, triggerPrice),
+		"reduceOnly":   true,
+	}, tag)
+	return err
+}
+
+// SetStopLoss sets stop loss order (reduce-only trigger market order)
+func (t *BackpackTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, stopPrice, backpackTagStopLoss); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+
+	t.log().Infof("  ✓ [Backpack] Stop loss set: %s @ %.4f", t.convertSymbol(symbol), stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order (reduce-only trigger market order)
+func (t *BackpackTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, takeProfitPrice, backpackTagTakeProfit); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+
+	t.log().Infof("  ✓ [Backpack] Take profit set: %s @ %.4f", t.convertSymbol(symbol), takeProfitPrice)
+	return nil
+}
+
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a reduce-only trigger order for quantity
+func (t *BackpackTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// formatPrice rounds a price to the market tick size
+func (t *BackpackTrader) formatPrice(symbol string, price float64) string {
+	tickSize := 0.0
+	if info, err := t.getMarket(symbol); err == nil {
+		tickSize = info.TickSize
+	}
+	return formatLimitPrice(price, tickSize, false)
+}
+
+// isBackpackStopLoss reports whether an open order is a stop loss (our tag, or any reduce-only trigger order)
+func isBackpackStopLoss(order backpackOrder) bool {
+	switch order.ClientID % 10 {
+	case backpackTagStopLoss:
+		return true
+	case backpackTagTakeProfit:
+		return false
+	}
+	return order.ReduceOnly && order.TriggerPrice > 0
+}
+
+// isBackpackTakeProfit reports whether an open order is a take profit (our tag)
+func isBackpackTakeProfit(order backpackOrder) bool {
+	return order.ClientID%10 == backpackTagTakeProfit
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *BackpackTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelOrders(symbol, isBackpackStopLoss)
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *BackpackTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelOrders(symbol, isBackpackTakeProfit)
+}
+
+// cancelOrders cancels the open orders of a symbol matching the filter
+func (t *BackpackTrader) cancelOrders(symbol string, match func(backpackOrder) bool) error {
+	orders, err := t.listOpenOrders(symbol)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, order := range orders {
+		if !match(order) {
+			continue
+		}
+		if err := t.CancelOrder(symbol, order.ID); err != nil {
+			t.log().Infof("  ⚠️ Failed to cancel Backpack order %s: %v", order.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to cancel %d orders", failed)
+	}
+	return nil
+}
+
+// listOpenOrders gets the open orders of a symbol (including untriggered trigger orders)
+func (t *BackpackTrader) listOpenOrders(symbol string) ([]backpackOrder, error) {
+	params := map[string]interface{}{
+		"marketType": "PERP",
+		"symbol":     t.convertSymbol(symbol),
+	}
+	data, err := t.doRequest("GET", backpackOrdersPath, "orderQueryAll", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var orders []backpackOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse open orders: %w", err)
+	}
+	return orders, nil
+}
+
+// CancelOrder cancels a single pending order by order ID
+func (t *BackpackTrader) CancelOrder(symbol string, orderID string) error {
+	params := map[string]interface{}{
+		"symbol":  t.convertSymbol(symbol),
+		"orderId": orderID,
+	}
+	if _, err := t.doRequest("DELETE", backpackOrderPath, "orderCancel", params); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders
+func (t *BackpackTrader) CancelAllOrders(symbol string) error {
+	params := map[string]interface{}{"symbol": t.convertSymbol(symbol)}
+	if _, err := t.doRequest("DELETE", backpackOrdersPath, "orderCancelAll", params); err != nil {
+		return fmt.Errorf("failed to cancel orders: %w", err)
+	}
+	return nil
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *BackpackTrader) CancelStopOrders(symbol string) error {
+	return t.cancelOrders(symbol, func(order backpackOrder) bool {
+		return isBackpackStopLoss(order) || isBackpackTakeProfit(order)
+	})
+}
+
+// FormatQuantity formats quantity to the market's step size
+func (t *BackpackTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	info, err := t.getMarket(symbol)
+	if err != nil || info.StepSize <= 0 {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+	return formatLimitPrice(quantity, info.StepSize, false), nil
+}
+
+// GetOrderStatus gets order status
+// Open orders are returned by the order endpoint; finished orders are rebuilt from their fills
+func (t *BackpackTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	params := map[string]interface{}{
+		"symbol":  t.convertSymbol(symbol),
+		"orderId": orderID,
+	}
+	data, err := t.doRequest("GET", backpackOrderPath, "orderQuery", params)
+	if err == nil {
+		var order backpackOrder
+		if err := json.Unmarshal(data, &order); err != nil {
+			return nil, err
+		}
+		avgPrice := 0.0
+		if order.ExecutedQuantity > 0 {
+			avgPrice = float64(order.ExecutedQuote) / float64(order.ExecutedQuantity)
+		}
+		return map[string]interface{}{
+			"orderId":     order.ID,
+			"symbol":      symbol,
+			"status":      backpackOrderStatus(order.Status),
+			"avgPrice":    avgPrice,
+			"executedQty": float64(order.ExecutedQuantity),
+			"side":        backpackSide(order.Side),
+			"type":        strings.ToUpper(order.OrderType),
+			"commission":  0.0,
+		}, nil
+	}
+
+	fills, fillErr := t.getFills(map[string]interface{}{"orderId": orderID})
+	if fillErr != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	if len(fills) == 0 {
+		return map[string]interface{}{
+			"orderId": orderID,
+			"symbol":  symbol,
+			"status":  "CANCELED",
+		}, nil
+	}
+
+	var qty, notional, fee float64
+	for _, fill := range fills {
+		qty += fill.FillQty
+		notional += fill.FillQty * fill.FillPrice
+		fee += fill.Fee
+	}
+	return map[string]interface{}{
+		"orderId":     orderID,
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    notional / qty,
+		"executedQty": qty,
+		"side":        fills[0].Side,
+		"type":        "MARKET",
+		"commission":  fee,
+	}, nil
+}
+
+// backpackOrderStatus maps a Backpack order status to FILLED/PARTIALLY_FILLED/NEW/CANCELED
+func backpackOrderStatus(status string) string {
+	switch status {
+	case "Filled":
+		return "FILLED"
+	case "PartiallyFilled":
+		return "PARTIALLY_FILLED"
+	case "Cancelled", "Expired", "TriggerFailed":
+		return "CANCELED"
+	default:
+		return "NEW"
+	}
+}
+
+// backpackSide maps a Backpack order side (Bid/Ask) to BUY/SELL
+func backpackSide(side string) string {
+	if side == "Ask" {
+		return "SELL"
+	}
+	return "BUY"
+}
+
+// GetClosedPnL retrieves closed position PnL records
+// Closed positions are rebuilt from fills by SyncOrders
+func (t *BackpackTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return []ClosedPnLRecord{}, nil
+}
+
+// GetOpenOrders gets all open/pending orders for a symbol
+func (t *BackpackTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	orders, err := t.listOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		side := backpackSide(order.Side)
+
+		// Exits sit on the opposite side of the position, entries on the same side
+		positionSide := "LONG"
+		if (side == "BUY") == order.ReduceOnly {
+			positionSide = "SHORT"
+		}
+
+		orderType := strings.ToUpper(order.OrderType)
+		switch {
+		case isBackpackStopLoss(order):
+			orderType = "STOP_MARKET"
+		case isBackpackTakeProfit(order):
+			orderType = "TAKE_PROFIT_MARKET"
+		}
+
+		result = append(result, OpenOrder{
+			OrderID:      order.ID,
+			Symbol:       t.convertSymbolBack(order.Symbol),
+			Side:         side,
+			PositionSide: positionSide,
+			Type:         orderType,
+			Price:        float64(order.Price),
+			StopPrice:    float64(order.TriggerPrice),
+			Quantity:     float64(order.Quantity),
+			Status:       "NEW",
+		})
+	}
+	return result, nil
+}
+
+// clearCache clears all caches
+func (t *BackpackTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// genBackpackClientID generates a unique uint32 client ID whose last decimal digit is the order tag
+func genBackpackClientID(tag uint32) uint32 {
+	return uint32(time.Now().UnixMilli()%400000000)*10 + tag
+}
@@ -0,0 +1,115 @@
+package trader
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+)
+
+func TestBackpackConvertSymbol(t *testing.T) {
+	bt := &BackpackTrader{}
+	tests := map[string]string{
+		"BTCUSDT":       "BTC_USDC_PERP",
+		"ethusdc":       "ETH_USDC_PERP",
+		"SOL_USDC_PERP": "SOL_USDC_PERP",
+		"DOGE":          "DOGE_USDC_PERP",
+	}
+	for input, want := range tests {
+		if got := bt.convertSymbol(input); got != want {
+			t.Errorf("convertSymbol(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if got := bt.convertSymbolBack("BTC_USDC_PERP"); got != "BTCUSDT" {
+		t.Errorf("convertSymbolBack(BTC_USDC_PERP) = %q, want BTCUSDT", got)
+	}
+}
+
+func TestBackpackSigningPayload(t *testing.T) {
+	params := map[string]interface{}{
+		"symbol":     "SOL_USDC_PERP",
+		"side":       "Bid",
+		"quantity":   "1.5",
+		"reduceOnly": true,
+		"clientId":   uint32(42),
+	}
+	got := backpackSigningPayload("orderExecute", params, "1700000000000", "5000")
+	want := "instruction=orderExecute&clientId=42&quantity=1.5&reduceOnly=true&side=Bid&symbol=SOL_USDC_PERP&timestamp=1700000000000&window=5000"
+	if got != want {
+		t.Errorf("payload = %q\nwant      %q", got, want)
+	}
+
+	if got := backpackSigningPayload("balanceQuery", nil, "1", "5000"); got != "instruction=balanceQuery&timestamp=1&window=5000" {
+		t.Errorf("payload without params = %q", got)
+	}
+}
+
+func TestBackpackSign(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	bt := &BackpackTrader{}
+	payload := "instruction=balanceQuery&timestamp=1&window=5000"
+
+	sig, err := bt.sign(base64.StdEncoding.EncodeToString(seed), payload)
+	if err != nil {
+		t.Fatalf("sign() error = %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		t.Fatalf("signature is not base64: %v", err)
+	}
+	pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if !ed25519.Verify(pub, []byte(payload), raw) {
+		t.Error("signature does not verify with the public key")
+	}
+
+	if _, err := bt.sign("not-a-seed", payload); err == nil {
+		t.Error("expected error for invalid secret")
+	}
+}
+
+func TestBackpackOrderClassification(t *testing.T) {
+	stopLoss := backpackOrder{ClientID: genBackpackClientID(backpackTagStopLoss), ReduceOnly: true, TriggerPrice: 90}
+	takeProfit := backpackOrder{ClientID: genBackpackClientID(backpackTagTakeProfit), ReduceOnly: true, TriggerPrice: 110}
+	manualStop := backpackOrder{ReduceOnly: true, TriggerPrice: 95}
+	entry := backpackOrder{ClientID: genBackpackClientID(backpackTagOrder), Price: 100}
+
+	if !isBackpackStopLoss(stopLoss) || isBackpackTakeProfit(stopLoss) {
+		t.Error("tagged stop loss misclassified")
+	}
+	if isBackpackStopLoss(takeProfit) || !isBackpackTakeProfit(takeProfit) {
+		t.Error("tagged take profit misclassified")
+	}
+	if !isBackpackStopLoss(manualStop) {
+		t.Error("untagged reduce-only trigger order should count as stop loss")
+	}
+	if isBackpackStopLoss(entry) || isBackpackTakeProfit(entry) {
+		t.Error("entry order classified as exit order")
+	}
+}
+
+func TestBackpackOrderStatus(t *testing.T) {
+	tests := map[string]string{
+		"Filled":          "FILLED",
+		"PartiallyFilled": "PARTIALLY_FILLED",
+		"New":             "NEW",
+		"TriggerPending":  "NEW",
+		"Cancelled":       "CANCELED",
+		"TriggerFailed":   "CANCELED",
+	}
+	for status, want := range tests {
+		if got := backpackOrderStatus(status); got != want {
+			t.Errorf("backpackOrderStatus(%s) = %s, want %s", status, got, want)
+		}
+	}
+}
+
+func TestParseBackpackTime(t *testing.T) {
+	if got := parseBackpackTime("2024-01-01T00:00:00.250").UnixMilli(); got != 1704067200250 {
+		t.Errorf("parseBackpackTime() = %d, want 1704067200250", got)
+	}
+	if got := parseBackpackTime("2024-01-01T00:00:00Z").UnixMilli(); got != 1704067200000 {
+		t.Errorf("parseBackpackTime(RFC3339) = %d, want 1704067200000", got)
+	}
+}
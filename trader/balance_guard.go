@@ -0,0 +1,82 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/store"
+)
+
+// BalanceAnomaly a balance reading that deviates implausibly from the reference value
+// (e.g. the exchange API momentarily returning 0 or a transient value)
+type BalanceAnomaly struct {
+	OldBalance    float64 `json:"old_balance"`
+	NewBalance    float64 `json:"new_balance"`
+	ChangePercent float64 `json:"change_percent"`
+	MaxChangePct  float64 `json:"max_change_percent"`
+}
+
+func (a *BalanceAnomaly) Error() string {
+	return fmt.Sprintf("balance changed %.2f%% (%.2f → %.2f), more than the allowed %.0f%%",
+		a.ChangePercent, a.OldBalance, a.NewBalance, a.MaxChangePct)
+}
+
+// CheckBalanceChange returns a *BalanceAnomaly if newBalance deviates from oldBalance by more than maxChangePct percent.
+// Returns nil when there is no reference (oldBalance <= 0) or the check is disabled (maxChangePct <= 0).
+func CheckBalanceChange(oldBalance, newBalance, maxChangePct float64) *BalanceAnomaly {
+	if oldBalance <= 0 || maxChangePct <= 0 {
+		return nil
+	}
+	changePct := (newBalance - oldBalance) / oldBalance * 100
+	if newBalance > 0 && math.Abs(changePct) <= maxChangePct {
+		return nil
+	}
+	return &BalanceAnomaly{
+		OldBalance:    oldBalance,
+		NewBalance:    newBalance,
+		ChangePercent: changePct,
+		MaxChangePct:  maxChangePct,
+	}
+}
+
+// lastRecordedEquity returns the trader's most recent equity snapshot (0 if none)
+func lastRecordedEquity(st *store.Store, traderID string) float64 {
+	if st == nil {
+		return 0
+	}
+	snapshots, err := st.Equity().GetLatest(traderID, 1)
+	if err != nil || len(snapshots) == 0 {
+		return 0
+	}
+	return snapshots[0].TotalEquity
+}
+
+// TradingPnL profit and loss from trading alone: deposits/withdrawals (net contributions) are
+// added to the capital base instead of being counted as profit or loss
+func TradingPnL(equity, initialBalance, netContributions float64) (pnl, pnlPct float64) {
+	capital := initialBalance + netContributions
+	pnl = equity - capital
+	if capital > 0 {
+		pnlPct = pnl / capital * 100
+	}
+	return pnl, pnlPct
+}
+
+// EstimateBalanceAdjustment the part of an equity change not explained by trading:
+// equity - (initial balance + net contributions) - (realized + unrealized PnL).
+// realizedPnL must only cover the time since initial_balance was set (earlier PnL is part of it),
+// net of fees and including net funding payments, which also move equity.
+func EstimateBalanceAdjustment(equity, initialBalance, netContributions, realizedPnL, unrealizedPnL float64) float64 {
+	return equity - (initialBalance + netContributions) - (realizedPnL + unrealizedPnL)
+}
+
+// netContributions returns the trader's recorded deposits minus withdrawals (0 without a store)
+func netContributions(st *store.Store, traderID string) float64 {
+	if st == nil {
+		return 0
+	}
+	total, err := st.BalanceAdjustment().NetContributions(traderID)
+	if err != nil {
+		return 0
+	}
+	return total
+}
@@ -0,0 +1,68 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckBalanceChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		old, new    float64
+		maxPct      float64
+		wantAnomaly bool
+	}{
+		{"small change", 1000, 1100, 50, false},
+		{"exactly at threshold", 1000, 500, 50, false},
+		{"large drop", 1000, 300, 50, true},
+		{"large jump", 1000, 2600, 50, true},
+		{"zero from api", 1000, 0, 50, true},
+		{"no reference", 0, 5000, 50, false},
+		{"check disabled", 1000, 10, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomaly := CheckBalanceChange(tt.old, tt.new, tt.maxPct)
+			if (anomaly != nil) != tt.wantAnomaly {
+				t.Fatalf("CheckBalanceChange(%v, %v, %v) = %v, want anomaly=%v", tt.old, tt.new, tt.maxPct, anomaly, tt.wantAnomaly)
+			}
+			if anomaly != nil && anomaly.OldBalance != tt.old {
+				t.Errorf("anomaly.OldBalance = %v, want %v", anomaly.OldBalance, tt.old)
+			}
+		})
+	}
+}
+
+func TestTradingPnL(t *testing.T) {
+	tests := []struct {
+		name                           string
+		equity, initial, contributions float64
+		wantPnL, wantPct               float64
+	}{
+		{"no contributions", 1100, 1000, 0, 100, 10},
+		{"deposit is not profit", 1600, 1000, 500, 100, 100.0 / 15},
+		{"withdrawal is not loss", 700, 1000, -400, 100, 100.0 / 6},
+		{"no capital", 50, 0, 0, 50, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pnl, pct := TradingPnL(tt.equity, tt.initial, tt.contributions)
+			if math.Abs(pnl-tt.wantPnL) > 1e-9 || math.Abs(pct-tt.wantPct) > 1e-9 {
+				t.Errorf("TradingPnL() = (%v, %v), want (%v, %v)", pnl, pct, tt.wantPnL, tt.wantPct)
+			}
+		})
+	}
+}
+
+func TestEstimateBalanceAdjustment(t *testing.T) {
+	// 1000 initial, +50 realized, -20 unrealized => 1030 expected; equity 1530 means a 500 deposit
+	if got := EstimateBalanceAdjustment(1530, 1000, 0, 50, -20); math.Abs(got-500) > 1e-9 {
+		t.Errorf("deposit estimate = %v, want 500", got)
+	}
+	// A previous 500 deposit is already accounted for, 200 withdrawn since
+	if got := EstimateBalanceAdjustment(1330, 1000, 500, 50, -20); math.Abs(got+200) > 1e-9 {
+		t.Errorf("withdrawal estimate = %v, want -200", got)
+	}
+}
@@ -0,0 +1,36 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GetFundingPayments returns the FUNDING_FEE income of the given symbols (positive = received)
+func (t *FuturesTrader) GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error) {
+	var payments []FundingPayment
+	for _, symbol := range symbols {
+		incomes, err := t.client.NewGetIncomeHistoryService().
+			Symbol(symbol).
+			IncomeType("FUNDING_FEE").
+			StartTime(since.UnixMilli()).
+			Limit(1000).
+			Do(context.Background(), t.requestOpts()...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding fee history of %s: %w", symbol, err)
+		}
+		for _, income := range incomes {
+			amount, _ := strconv.ParseFloat(income.Income, 64)
+			payments = append(payments, FundingPayment{
+				// Funding settlements of several symbols can share a transaction ID
+				ID:     income.Symbol + "_" + strconv.FormatInt(income.TranID, 10),
+				Symbol: income.Symbol,
+				Asset:  income.Asset,
+				Amount: amount,
+				Time:   time.UnixMilli(income.Time).UTC(),
+			})
+		}
+	}
+	return payments, nil
+}
@@ -6,13 +6,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"nofx/hook"
+	"nofx/kernel"
 	"nofx/logger"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/adshao/go-binance/v2/futures"
+	"github.com/adshao/go-binance/v2/portfolio"
 )
 
 // getBrOrderID generates unique order ID (for futures contracts)
@@ -45,8 +48,28 @@ func getBrOrderID() string {
 
 // FuturesTrader Binance futures trader
 type FuturesTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	client *futures.Client
 
+	// Portfolio Margin API client (nil on testnet) and the detected account mode
+	pmClient    *portfolio.Client
+	accountMode int32
+
+	// Exchange clock offset (pushed into the clients' TimeOffset on every sync)
+	clock *ClockSync
+
+	// Final order states pushed by the user data stream
+	orderHub *orderUpdateHub
+
+	// Margin asset balances are read in ("" = account totals, USDT)
+	quoteAsset string
+
+	// Price tick sizes by symbol, filled from one exchangeInfo download
+	tickSizeMu      sync.Mutex
+	tickSizes       map[string]float64
+	tickSizesLoaded time.Time
+
 	// Balance cache
 	cachedBalance     map[string]interface{}
 	balanceCacheTime  time.Time
@@ -62,7 +85,8 @@ type FuturesTrader struct {
 }
 
 // NewFuturesTrader creates futures trader
-func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
+// testnet points the client at Binance Futures testnet instead of mainnet
+func NewFuturesTrader(apiKey, secretKey string, userId string, testnet bool) *FuturesTrader {
 	client := futures.NewClient(apiKey, secretKey)
 
 	hookRes := hook.HookExec[hook.NewBinanceTraderResult](hook.NEW_BINANCE_TRADER, userId, client)
@@ -70,12 +94,24 @@ func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
 		client = hookRes.GetResult()
 	}
 
-	// Sync time to avoid "Timestamp ahead" error
-	syncBinanceServerTime(client)
+	// Set per client rather than via futures.UseTestnet, which is global and would affect mainnet traders
+	if testnet {
+		client.BaseURL = futures.BaseApiTestnetUrl
+		logger.Infof("🧪 [Binance] Using futures testnet: %s", client.BaseURL)
+	}
+
+	// Sync time to avoid "Timestamp ahead" / recvWindow errors, refreshed periodically on use
 	trader := &FuturesTrader{
 		client:        client,
+		orderHub:      newOrderUpdateHub(),
 		cacheDuration: 15 * time.Second, // 15-second cache
 	}
+	if !testnet {
+		// Portfolio Margin has no testnet
+		trader.pmClient = newBinancePortfolioClient(apiKey, secretKey, client)
+	}
+	trader.clock = newBinanceClockSync(client, trader.pmClient)
+	trader.clock.Refresh()
 
 	// Set dual-side position mode (Hedge Mode)
 	// This is required because the code uses PositionSide (LONG/SHORT)
@@ -91,66 +127,98 @@ func (t *FuturesTrader) setDualSidePosition() error {
 	// Try to set dual-side position mode
 	err := t.client.NewChangePositionModeService().
 		DualSide(true). // true = dual-side position (Hedge Mode)
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		// If error message contains "No need to change", it means already in dual-side position mode
 		if strings.Contains(err.Error(), "No need to change position side") {
-			logger.Infof("  ✓ Account is already in dual-side position mode (Hedge Mode)")
+			t.log().Infof("  ✓ Account is already in dual-side position mode (Hedge Mode)")
 			return nil
 		}
 		// Other errors are returned (but won't interrupt initialization in the caller)
 		return err
 	}
 
-	logger.Infof("  ✓ Account has been switched to dual-side position mode (Hedge Mode)")
-	logger.Infof("  ℹ️  Dual-side position mode allows holding both long and short positions simultaneously")
+	t.log().Infof("  ✓ Account has been switched to dual-side position mode (Hedge Mode)")
+	t.log().Infof("  ℹ️  Dual-side position mode allows holding both long and short positions simultaneously")
 	return nil
 }
 
-// syncBinanceServerTime syncs Binance server time to ensure request timestamps are valid
-func syncBinanceServerTime(client *futures.Client) {
-	serverTime, err := client.NewServerTimeService().Do(context.Background())
-	if err != nil {
-		logger.Infof("⚠️ Failed to sync Binance server time: %v", err)
-		return
+// newBinanceClockSync creates a clock sync that keeps the TimeOffset (local - server) of the futures
+// and Portfolio Margin (optional) clients up to date. The SDKs read TimeOffset on every signed request,
+// so it is only ever written atomically.
+func newBinanceClockSync(client *futures.Client, pmClient *portfolio.Client) *ClockSync {
+	clock := NewClockSync("Binance", func() (int64, error) {
+		return client.NewServerTimeService().Do(context.Background())
+	})
+	clock.onSync = func(offsetMs int64) {
+		atomic.StoreInt64(&client.TimeOffset, -offsetMs)
+		if pmClient != nil {
+			atomic.StoreInt64(&pmClient.TimeOffset, -offsetMs)
+		}
 	}
+	return clock
+}
 
-	now := time.Now().UnixMilli()
-	offset := now - serverTime
-	client.TimeOffset = offset
-	logger.Infof("⏱ Binance server time synced, offset %dms", offset)
+// requestOpts options of signed requests: the configured recvWindow, if any (exchange default 5000ms)
+func (t *FuturesTrader) requestOpts() []futures.RequestOption {
+	if ms := recvWindowOr(0); ms > 0 {
+		return []futures.RequestOption{futures.WithRecvWindow(ms)}
+	}
+	return nil
 }
 
 // GetBalance gets account balance (with cache)
 func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
+	// Called every cycle: re-sync server time when due
+	t.clock.Refresh()
+
 	// First check if cache is valid
 	t.balanceCacheMutex.RLock()
 	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
 		cacheAge := time.Since(t.balanceCacheTime)
 		t.balanceCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached account balance (cache age: %.1f seconds ago)", cacheAge.Seconds())
+		t.log().Infof("✓ Using cached account balance (cache age: %.1f seconds ago)", cacheAge.Seconds())
 		return t.cachedBalance, nil
 	}
 	t.balanceCacheMutex.RUnlock()
 
+	if t.isPortfolioMargin() {
+		return t.getPortfolioMarginBalance()
+	}
+
 	// Cache expired or doesn't exist, call API
-	logger.Infof("🔄 Cache expired, calling Binance API to get account balance...")
-	account, err := t.client.NewGetAccountService().Do(context.Background())
+	t.log().Infof("🔄 Cache expired, calling Binance API to get account balance...")
+	account, err := t.client.NewGetAccountService().Do(context.Background(), t.requestOpts()...)
 	if err != nil {
-		logger.Infof("❌ Binance API call failed: %v", err)
+		if t.detectPortfolioMargin() {
+			return t.getPortfolioMarginBalance()
+		}
+		t.log().Infof("❌ Binance API call failed: %v", err)
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
+	t.markFuturesAccount()
 
 	result := make(map[string]interface{})
 	result["totalWalletBalance"], _ = strconv.ParseFloat(account.TotalWalletBalance, 64)
 	result["availableBalance"], _ = strconv.ParseFloat(account.AvailableBalance, 64)
 	result["totalUnrealizedProfit"], _ = strconv.ParseFloat(account.TotalUnrealizedProfit, 64)
+	if t.quoteAsset != "" {
+		// USDC-margined contracts: the account totals only cover USDT in single-asset mode
+		for _, asset := range account.Assets {
+			if asset.Asset == t.quoteAsset {
+				result["totalWalletBalance"], _ = strconv.ParseFloat(asset.WalletBalance, 64)
+				result["availableBalance"], _ = strconv.ParseFloat(asset.AvailableBalance, 64)
+				result["totalUnrealizedProfit"], _ = strconv.ParseFloat(asset.UnrealizedProfit, 64)
+				break
+			}
+		}
+	}
 
-	logger.Infof("✓ Binance API returned: total balance=%s, available=%s, unrealized PnL=%s",
-		account.TotalWalletBalance,
-		account.AvailableBalance,
-		account.TotalUnrealizedProfit)
+	t.log().Infof("✓ Binance API returned: total balance=%.4f, available=%.4f, unrealized PnL=%.4f",
+		result["totalWalletBalance"],
+		result["availableBalance"],
+		result["totalUnrealizedProfit"])
 
 	// Update cache
 	t.balanceCacheMutex.Lock()
@@ -161,6 +229,11 @@ func (t *FuturesTrader) GetBalance() (map[string]interface{}, error) {
 	return result, nil
 }
 
+// SetQuoteAsset reads balances in the margin asset of quote-asset contracts (implements QuoteAssetConfigurable)
+func (t *FuturesTrader) SetQuoteAsset(quote string) {
+	t.quoteAsset = quote
+}
+
 // GetPositions gets all positions (with cache)
 func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 	// First check if cache is valid
@@ -168,17 +241,25 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
 		cacheAge := time.Since(t.positionsCacheTime)
 		t.positionsCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached position information (cache age: %.1f seconds ago)", cacheAge.Seconds())
+		t.log().Infof("✓ Using cached position information (cache age: %.1f seconds ago)", cacheAge.Seconds())
 		return t.cachedPositions, nil
 	}
 	t.positionsCacheMutex.RUnlock()
 
+	if t.isPortfolioMargin() {
+		return t.getPortfolioMarginPositions()
+	}
+
 	// Cache expired or doesn't exist, call API
-	logger.Infof("🔄 Cache expired, calling Binance API to get position information...")
-	positions, err := t.client.NewGetPositionRiskService().Do(context.Background())
+	t.log().Infof("🔄 Cache expired, calling Binance API to get position information...")
+	positions, err := t.client.NewGetPositionRiskService().Do(context.Background(), t.requestOpts()...)
 	if err != nil {
+		if t.detectPortfolioMargin() {
+			return t.getPortfolioMarginPositions()
+		}
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
+	t.markFuturesAccount()
 
 	var result []map[string]interface{}
 	for _, pos := range positions {
@@ -216,6 +297,41 @@ func (t *FuturesTrader) GetPositions() ([]map[string]interface{}, error) {
 	return result, nil
 }
 
+// clearCache drops cached balance and positions so the next query reflects the latest order
+func (t *FuturesTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// AdjustPositionMargin adds (amount > 0) or removes (amount < 0) isolated margin of an open position
+// (implements PositionMarginAdjuster)
+func (t *FuturesTrader) AdjustPositionMargin(symbol, positionSide string, amount float64) error {
+	actionType := 1 // 1 = add margin, 2 = reduce margin
+	if amount < 0 {
+		actionType = 2
+		amount = -amount
+	}
+	side := futures.PositionSideTypeLong
+	if strings.EqualFold(positionSide, "SHORT") {
+		side = futures.PositionSideTypeShort
+	}
+	err := t.client.NewUpdatePositionMarginService().
+		Symbol(symbol).
+		PositionSide(side).
+		Amount(strconv.FormatFloat(amount, 'f', -1, 64)).
+		Type(actionType).
+		Do(context.Background(), t.requestOpts()...)
+	if err != nil {
+		return fmt.Errorf("failed to adjust position margin: %w", err)
+	}
+	return nil
+}
+
 // SetMarginMode sets margin mode
 func (t *FuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	var marginType futures.MarginType
@@ -229,7 +345,7 @@ func (t *FuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	err := t.client.NewChangeMarginTypeService().
 		Symbol(symbol).
 		MarginType(marginType).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	marginModeStr := "Cross Margin"
 	if !isCrossMargin {
@@ -239,31 +355,31 @@ func (t *FuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	if err != nil {
 		// If error message contains "No need to change", margin mode is already set to target value
 		if contains(err.Error(), "No need to change margin type") {
-			logger.Infof("  ✓ %s margin mode is already %s", symbol, marginModeStr)
+			t.log().Infof("  ✓ %s margin mode is already %s", symbol, marginModeStr)
 			return nil
 		}
 		// If there is an open position, margin mode cannot be changed, but this doesn't affect trading
 		if contains(err.Error(), "Margin type cannot be changed if there exists position") {
-			logger.Infof("  ⚠️ %s has open positions, cannot change margin mode, continuing with current mode", symbol)
+			t.log().Infof("  ⚠️ %s has open positions, cannot change margin mode, continuing with current mode", symbol)
 			return nil
 		}
 		// Detect Multi-Assets mode (error code -4168)
 		if contains(err.Error(), "Multi-Assets mode") || contains(err.Error(), "-4168") || contains(err.Error(), "4168") {
-			logger.Infof("  ⚠️ %s detected Multi-Assets mode, forcing Cross Margin mode", symbol)
-			logger.Infof("  💡 Tip: To use Isolated Margin mode, please disable Multi-Assets mode in Binance")
+			t.log().Infof("  ⚠️ %s detected Multi-Assets mode, forcing Cross Margin mode", symbol)
+			t.log().Infof("  💡 Tip: To use Isolated Margin mode, please disable Multi-Assets mode in Binance")
 			return nil
 		}
 		// Detect Unified Account API (Portfolio Margin)
 		if contains(err.Error(), "unified") || contains(err.Error(), "portfolio") || contains(err.Error(), "Portfolio") {
-			logger.Infof("  ❌ %s detected Unified Account API, unable to trade futures", symbol)
+			t.log().Infof("  ❌ %s detected Unified Account API, unable to trade futures", symbol)
 			return fmt.Errorf("please use 'Spot & Futures Trading' API permission, do not use 'Unified Account API'")
 		}
-		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+		t.log().Infof("  ⚠️ Failed to set margin mode: %v", err)
 		// Don't return error, let trading continue
 		return nil
 	}
 
-	logger.Infof("  ✓ %s margin mode set to %s", symbol, marginModeStr)
+	t.log().Infof("  ✓ %s margin mode set to %s", symbol, marginModeStr)
 	return nil
 }
 
@@ -285,7 +401,7 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 
 	// If current leverage is already the target leverage, skip
 	if currentLeverage == leverage && currentLeverage > 0 {
-		logger.Infof("  ✓ %s leverage is already %dx, no need to change", symbol, leverage)
+		t.log().Infof("  ✓ %s leverage is already %dx, no need to change", symbol, leverage)
 		return nil
 	}
 
@@ -293,21 +409,21 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 	_, err = t.client.NewChangeLeverageService().
 		Symbol(symbol).
 		Leverage(leverage).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		// If error message contains "No need to change", leverage is already the target value
 		if contains(err.Error(), "No need to change") {
-			logger.Infof("  ✓ %s leverage is already %dx", symbol, leverage)
+			t.log().Infof("  ✓ %s leverage is already %dx", symbol, leverage)
 			return nil
 		}
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("  ✓ %s leverage changed to %dx", symbol, leverage)
+	t.log().Infof("  ✓ %s leverage changed to %dx", symbol, leverage)
 
 	// Wait 5 seconds after changing leverage (to avoid cooldown period errors)
-	logger.Infof("  ⏱ Waiting 5 seconds for cooldown period...")
+	t.log().Infof("  ⏱ Waiting 5 seconds for cooldown period...")
 	time.Sleep(5 * time.Second)
 
 	return nil
@@ -317,7 +433,7 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log().Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
@@ -352,14 +468,15 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 		Type(futures.OrderTypeMarket).
 		Quantity(quantityStr).
 		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to open long position: %w", err)
 	}
 
-	logger.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	t.log().Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
+	t.clearCache()
+	t.log().Infof("  Order ID: %d", order.OrderID)
 
 	result := make(map[string]interface{})
 	result["orderId"] = order.OrderID
@@ -372,7 +489,7 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+		t.log().Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
 
 	// Set leverage
@@ -407,14 +524,15 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 		Type(futures.OrderTypeMarket).
 		Quantity(quantityStr).
 		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to open short position: %w", err)
 	}
 
-	logger.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
+	t.log().Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
+	t.clearCache()
+	t.log().Infof("  Order ID: %d", order.OrderID)
 
 	result := make(map[string]interface{})
 	result["orderId"] = order.OrderID
@@ -423,6 +541,69 @@ func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int)
 	return result, nil
 }
 
+// OpenLongPostOnly opens a long position with a post-only (GTX) limit buy at price
+func (t *FuturesTrader) OpenLongPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPostOnly(symbol, futures.SideTypeBuy, futures.PositionSideTypeLong, quantity, leverage, price)
+}
+
+// OpenShortPostOnly opens a short position with a post-only (GTX) limit sell at price
+func (t *FuturesTrader) OpenShortPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPostOnly(symbol, futures.SideTypeSell, futures.PositionSideTypeShort, quantity, leverage, price)
+}
+
+// openPostOnly places a Good-Till-Crossing limit entry. Binance expires a GTX order that would
+// take liquidity instead of filling it, which is reported as an error here.
+func (t *FuturesTrader) openPostOnly(symbol string, side futures.SideType, posSide futures.PositionSideType, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	if err := t.CancelAllOrders(symbol); err != nil {
+		t.log().Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	quantityFloat, parseErr := strconv.ParseFloat(quantityStr, 64)
+	if parseErr != nil || quantityFloat <= 0 {
+		return nil, fmt.Errorf("position size too small, rounded to 0 (original: %.8f → formatted: %s). Suggest increasing position amount or selecting a lower-priced coin", quantity, quantityStr)
+	}
+	if err := t.CheckMinNotional(symbol, quantityFloat); err != nil {
+		return nil, err
+	}
+	tickSize, err := t.getPriceTickSize(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round away from the spread so the order stays on the maker side
+	order, err := t.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.OrderTypeLimit).
+		TimeInForce(futures.TimeInForceTypeGTX).
+		Price(formatLimitPrice(price, tickSize, side == futures.SideTypeSell)).
+		Quantity(quantityStr).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background(), t.requestOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place post-only %s order: %w", strings.ToLower(string(posSide)), err)
+	}
+	if order.Status == futures.OrderStatusTypeExpired {
+		return nil, fmt.Errorf("post-only order for %s would cross the book at %.8f, rejected", symbol, price)
+	}
+	t.clearCache()
+	t.log().Infof("✓ Post-only %s order placed: %s quantity: %s, order ID: %d", strings.ToLower(string(posSide)), symbol, quantityStr, order.OrderID)
+
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  order.Symbol,
+		"status":  order.Status,
+	}, nil
+}
+
 // CloseLong closes a long position
 func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
@@ -458,17 +639,18 @@ func (t *FuturesTrader) CloseLong(symbol string, quantity float64) (map[string]i
 		Type(futures.OrderTypeMarket).
 		Quantity(quantityStr).
 		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to close long position: %w", err)
 	}
 
-	logger.Infof("✓ Closed long position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log().Infof("✓ Closed long position successfully: %s quantity: %s", symbol, quantityStr)
+	t.clearCache()
 
 	// After closing position, cancel all pending orders for this symbol (stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -513,17 +695,18 @@ func (t *FuturesTrader) CloseShort(symbol string, quantity float64) (map[string]
 		Type(futures.OrderTypeMarket).
 		Quantity(quantityStr).
 		NewClientOrderID(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to close short position: %w", err)
 	}
 
-	logger.Infof("✓ Closed short position successfully: %s quantity: %s", symbol, quantityStr)
+	t.log().Infof("✓ Closed short position successfully: %s quantity: %s", symbol, quantityStr)
+	t.clearCache()
 
 	// After closing position, cancel all pending orders for this symbol (stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -542,7 +725,7 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 	// 1. Cancel legacy stop-loss orders
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, order := range orders {
@@ -554,25 +737,27 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 				_, err := t.client.NewCancelOrderService().
 					Symbol(symbol).
 					OrderID(order.OrderID).
-					Do(context.Background())
+					Do(context.Background(), t.requestOpts()...)
 
 				if err != nil {
 					errMsg := fmt.Sprintf("Order ID %d: %v", order.OrderID, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel legacy stop-loss order: %s", errMsg)
+					t.log().Infof("  ⚠ Failed to cancel legacy stop-loss order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy stop-loss order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
+				t.log().Infof("  ✓ Canceled legacy stop-loss order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
 			}
 		}
 	}
 
 	// 2. Cancel Algo stop-loss orders
-	algoOrders, err := t.client.NewListOpenAlgoOrdersService().
-		Symbol(symbol).
-		Do(context.Background())
+	algoService := t.client.NewListOpenAlgoOrdersService()
+	if symbol != "" {
+		algoService = algoService.Symbol(symbol)
+	}
+	algoOrders, err := algoService.Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, algoOrder := range algoOrders {
@@ -580,25 +765,25 @@ func (t *FuturesTrader) CancelStopLossOrders(symbol string) error {
 			if algoOrder.OrderType == futures.AlgoOrderTypeStopMarket || algoOrder.OrderType == futures.AlgoOrderTypeStop {
 				_, err := t.client.NewCancelAlgoOrderService().
 					AlgoID(algoOrder.AlgoId).
-					Do(context.Background())
+					Do(context.Background(), t.requestOpts()...)
 
 				if err != nil {
 					errMsg := fmt.Sprintf("Algo ID %d: %v", algoOrder.AlgoId, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel Algo stop-loss order: %s", errMsg)
+					t.log().Infof("  ⚠ Failed to cancel Algo stop-loss order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled Algo stop-loss order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
+				t.log().Infof("  ✓ Canceled Algo stop-loss order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
 			}
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s has no stop-loss orders to cancel", symbol)
+		t.log().Infof("  ℹ %s has no stop-loss orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d stop-loss order(s) for %s", canceledCount, symbol)
 	}
 
 	// If all cancellations failed, return error
@@ -618,7 +803,7 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 	// 1. Cancel legacy take-profit orders
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, order := range orders {
@@ -630,25 +815,27 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 				_, err := t.client.NewCancelOrderService().
 					Symbol(symbol).
 					OrderID(order.OrderID).
-					Do(context.Background())
+					Do(context.Background(), t.requestOpts()...)
 
 				if err != nil {
 					errMsg := fmt.Sprintf("Order ID %d: %v", order.OrderID, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel legacy take-profit order: %s", errMsg)
+					t.log().Infof("  ⚠ Failed to cancel legacy take-profit order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy take-profit order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
+				t.log().Infof("  ✓ Canceled legacy take-profit order (Order ID: %d, Type: %s, Side: %s)", order.OrderID, orderType, order.PositionSide)
 			}
 		}
 	}
 
 	// 2. Cancel Algo take-profit orders
-	algoOrders, err := t.client.NewListOpenAlgoOrdersService().
-		Symbol(symbol).
-		Do(context.Background())
+	algoService := t.client.NewListOpenAlgoOrdersService()
+	if symbol != "" {
+		algoService = algoService.Symbol(symbol)
+	}
+	algoOrders, err := algoService.Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, algoOrder := range algoOrders {
@@ -656,25 +843,25 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 			if algoOrder.OrderType == futures.AlgoOrderTypeTakeProfitMarket || algoOrder.OrderType == futures.AlgoOrderTypeTakeProfit {
 				_, err := t.client.NewCancelAlgoOrderService().
 					AlgoID(algoOrder.AlgoId).
-					Do(context.Background())
+					Do(context.Background(), t.requestOpts()...)
 
 				if err != nil {
 					errMsg := fmt.Sprintf("Algo ID %d: %v", algoOrder.AlgoId, err)
 					cancelErrors = append(cancelErrors, fmt.Errorf("%s", errMsg))
-					logger.Infof("  ⚠ Failed to cancel Algo take-profit order: %s", errMsg)
+					t.log().Infof("  ⚠ Failed to cancel Algo take-profit order: %s", errMsg)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled Algo take-profit order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
+				t.log().Infof("  ✓ Canceled Algo take-profit order (Algo ID: %d, Type: %s)", algoOrder.AlgoId, algoOrder.OrderType)
 			}
 		}
 	}
 
 	if canceledCount == 0 && len(cancelErrors) == 0 {
-		logger.Infof("  ℹ %s has no take-profit orders to cancel", symbol)
+		t.log().Infof("  ℹ %s has no take-profit orders to cancel", symbol)
 	} else if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d take-profit order(s) for %s", canceledCount, symbol)
 	}
 
 	// If all cancellations failed, return error
@@ -685,32 +872,62 @@ func (t *FuturesTrader) CancelTakeProfitOrders(symbol string) error {
 	return nil
 }
 
+// CancelOrder cancels a single pending order by order ID.
+// Stop-loss/take-profit orders live in the Algo system with their own IDs, so an unknown
+// regular order ID is retried as an Algo ID.
+func (t *FuturesTrader) CancelOrder(symbol string, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+
+	_, err = t.client.NewCancelOrderService().
+		Symbol(symbol).
+		OrderID(id).
+		Do(context.Background(), t.requestOpts()...)
+	if err == nil {
+		t.log().Infof("  ✓ Canceled order %s for %s", orderID, symbol)
+		return nil
+	}
+	if !contains(err.Error(), "-2011") && !contains(err.Error(), "Unknown order") {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+
+	if _, algoErr := t.client.NewCancelAlgoOrderService().
+		AlgoID(id).
+		Do(context.Background(), t.requestOpts()...); algoErr != nil {
+		return fmt.Errorf("failed to cancel order %s: %v (algo: %v)", orderID, err, algoErr)
+	}
+	t.log().Infof("  ✓ Canceled Algo order %s for %s", orderID, symbol)
+	return nil
+}
+
 // CancelAllOrders cancels all pending orders for this symbol
 // Now uses both legacy API and new Algo Order API
 func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 	// 1. Cancel all legacy orders
 	err := t.client.NewCancelAllOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
-		logger.Infof("  ⚠ Failed to cancel legacy orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel legacy orders: %v", err)
 	} else {
-		logger.Infof("  ✓ Canceled all legacy pending orders for %s", symbol)
+		t.log().Infof("  ✓ Canceled all legacy pending orders for %s", symbol)
 	}
 
 	// 2. Cancel all Algo orders
 	err = t.client.NewCancelAllAlgoOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		// Ignore "no algo orders" error
 		if !contains(err.Error(), "no algo") && !contains(err.Error(), "No algo") {
-			logger.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
+			t.log().Infof("  ⚠ Failed to cancel Algo orders: %v", err)
 		}
 	} else {
-		logger.Infof("  ✓ Canceled all Algo orders for %s", symbol)
+		t.log().Infof("  ✓ Canceled all Algo orders for %s", symbol)
 	}
 
 	return nil
@@ -724,7 +941,7 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 	// 1. Cancel legacy stop orders (for backward compatibility)
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, order := range orders {
@@ -740,15 +957,15 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 				_, err := t.client.NewCancelOrderService().
 					Symbol(symbol).
 					OrderID(order.OrderID).
-					Do(context.Background())
+					Do(context.Background(), t.requestOpts()...)
 
 				if err != nil {
-					logger.Infof("  ⚠ Failed to cancel legacy order %d: %v", order.OrderID, err)
+					t.log().Infof("  ⚠ Failed to cancel legacy order %d: %v", order.OrderID, err)
 					continue
 				}
 
 				canceledCount++
-				logger.Infof("  ✓ Canceled legacy stop order for %s (Order ID: %d, Type: %s)",
+				t.log().Infof("  ✓ Canceled legacy stop order for %s (Order ID: %d, Type: %s)",
 					symbol, order.OrderID, orderType)
 			}
 		}
@@ -757,20 +974,20 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 	// 2. Cancel Algo orders (new API)
 	err = t.client.NewCancelAllAlgoOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		// Ignore "no algo orders" error
 		if !contains(err.Error(), "no algo") && !contains(err.Error(), "No algo") {
-			logger.Infof("  ⚠ Failed to cancel Algo orders: %v", err)
+			t.log().Infof("  ⚠ Failed to cancel Algo orders: %v", err)
 		}
 	} else {
-		logger.Infof("  ✓ Canceled all Algo orders for %s", symbol)
+		t.log().Infof("  ✓ Canceled all Algo orders for %s", symbol)
 		canceledCount++
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ %s has no take-profit/stop-loss orders to cancel", symbol)
+		t.log().Infof("  ℹ %s has no take-profit/stop-loss orders to cancel", symbol)
 	}
 
 	return nil
@@ -780,10 +997,10 @@ func (t *FuturesTrader) CancelStopOrders(symbol string) error {
 func (t *FuturesTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
 	var result []OpenOrder
 
-	// 1. Get legacy open orders
+	// 1. Get legacy open orders (all symbols when symbol is empty)
 	orders, err := t.client.NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
@@ -808,9 +1025,11 @@ func (t *FuturesTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
 	}
 
 	// 2. Get Algo orders (new API for stop-loss/take-profit)
-	algoOrders, err := t.client.NewListOpenAlgoOrdersService().
-		Symbol(symbol).
-		Do(context.Background())
+	algoService := t.client.NewListOpenAlgoOrdersService()
+	if symbol != "" {
+		algoService = algoService.Symbol(symbol)
+	}
+	algoOrders, err := algoService.Do(context.Background(), t.requestOpts()...)
 
 	if err == nil {
 		for _, algoOrder := range algoOrders {
@@ -834,6 +1053,11 @@ func (t *FuturesTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
 	return result, nil
 }
 
+// GetAllOpenOrders gets open orders of all symbols in one call
+func (t *FuturesTrader) GetAllOpenOrders() ([]OpenOrder, error) {
+	return t.GetOpenOrders("")
+}
+
 // GetMarketPrice gets market price
 func (t *FuturesTrader) GetMarketPrice(symbol string) (float64, error) {
 	prices, err := t.client.NewListPricesService().Symbol(symbol).Do(context.Background())
@@ -853,6 +1077,86 @@ func (t *FuturesTrader) GetMarketPrice(symbol string) (float64, error) {
 	return price, nil
 }
 
+// GetBestBidAsk gets the best bid and ask from the book ticker
+func (t *FuturesTrader) GetBestBidAsk(symbol string) (float64, float64, error) {
+	tickers, err := t.client.NewListBookTickersService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get book ticker: %w", err)
+	}
+	if len(tickers) == 0 {
+		return 0, 0, fmt.Errorf("book ticker not found for %s", symbol)
+	}
+	bid, _ := strconv.ParseFloat(tickers[0].BidPrice, 64)
+	ask, _ := strconv.ParseFloat(tickers[0].AskPrice, 64)
+	return bid, ask, nil
+}
+
+// binanceDepthLimits order book depths accepted by the futures depth endpoint
+var binanceDepthLimits = []int{5, 10, 20, 50, 100, 500, 1000}
+
+// GetOrderBook gets the top depth levels of the order book
+func (t *FuturesTrader) GetOrderBook(symbol string, depth int) (*kernel.OrderBook, error) {
+	limit := binanceDepthLimits[len(binanceDepthLimits)-1]
+	for _, l := range binanceDepthLimits {
+		if l >= depth {
+			limit = l
+			break
+		}
+	}
+	res, err := t.client.NewDepthService().Symbol(symbol).Limit(limit).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	return &kernel.OrderBook{
+		Symbol: symbol,
+		Bids:   binanceBookLevels(res.Bids, depth),
+		Asks:   binanceBookLevels(res.Asks, depth),
+	}, nil
+}
+
+// binanceBookLevels converts up to depth price levels of one book side
+func binanceBookLevels(levels []futures.Bid, depth int) []kernel.OrderBookLevel {
+	result := make([]kernel.OrderBookLevel, 0, min(len(levels), depth))
+	for _, l := range levels {
+		if len(result) >= depth {
+			break
+		}
+		price, quantity, err := l.Parse()
+		if err != nil || price <= 0 || quantity <= 0 {
+			continue
+		}
+		result = append(result, kernel.OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return result
+}
+
+// GetSymbolInfo gets per-symbol trading limits (max leverage and maintenance tiers from leverage brackets)
+func (t *FuturesTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	brackets, err := t.client.NewGetLeverageBracketService().Symbol(symbol).Do(context.Background(), t.requestOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leverage brackets: %w", err)
+	}
+
+	info := &SymbolInfo{Symbol: symbol}
+	for _, b := range brackets {
+		if b.Symbol != symbol {
+			continue
+		}
+		for _, bracket := range b.Brackets {
+			if bracket.InitialLeverage > info.MaxLeverage {
+				info.MaxLeverage = bracket.InitialLeverage
+			}
+			info.MaintenanceTiers = append(info.MaintenanceTiers, MaintenanceMarginTier{
+				NotionalCap:      bracket.NotionalCap,
+				MaintMarginRatio: bracket.MaintMarginRatio,
+				MaintAmount:      bracket.Cum,
+			})
+		}
+	}
+	return info, nil
+}
+
 // CalculatePositionSize calculates position size
 func (t *FuturesTrader) CalculatePositionSize(balance, riskPercent, price float64, leverage int) float64 {
 	riskAmount := balance * (riskPercent / 100.0)
@@ -885,13 +1189,13 @@ func (t *FuturesTrader) SetStopLoss(symbol string, positionSide string, quantity
 		WorkingType(futures.WorkingTypeContractPrice).
 		ClosePosition(true).
 		ClientAlgoId(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
 
-	logger.Infof("  Stop-loss price set (Algo Order): %.4f", stopPrice)
+	t.log().Infof("  Stop-loss price set (Algo Order): %.4f", stopPrice)
 	return nil
 }
 
@@ -919,16 +1223,145 @@ func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quanti
 		WorkingType(futures.WorkingTypeContractPrice).
 		ClosePosition(true).
 		ClientAlgoId(getBrOrderID()).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 
 	if err != nil {
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
 
-	logger.Infof("  Take-profit price set (Algo Order): %.4f", takeProfitPrice)
+	t.log().Infof("  Take-profit price set (Algo Order): %.4f", takeProfitPrice)
+	return nil
+}
+
+// SetPartialTakeProfit sets a take-profit market order (Algo TAKE_PROFIT_MARKET) for quantity only
+// (implements PartialTakeProfitSetter). Unlike SetTakeProfit it doesn't use closePosition, which
+// would close the whole position at the first ladder level. The account runs in hedge mode, where
+// Binance rejects the reduceOnly flag: the position side makes the order reduce-only.
+func (t *FuturesTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	side := futures.SideTypeSell
+	posSide := futures.PositionSideTypeLong
+	if positionSide != "LONG" {
+		side = futures.SideTypeBuy
+		posSide = futures.PositionSideTypeShort
+	}
+
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.client.NewCreateAlgoOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.AlgoOrderTypeTakeProfitMarket).
+		Quantity(quantityStr).
+		TriggerPrice(fmt.Sprintf("%.8f", takeProfitPrice)).
+		WorkingType(futures.WorkingTypeContractPrice).
+		ClientAlgoId(getBrOrderID()).
+		Do(context.Background(), t.requestOpts()...)
+	if err != nil {
+		return fmt.Errorf("failed to set partial take-profit: %w", err)
+	}
+
+	t.log().Infof("  Partial take-profit set (Algo Order): %s @ %.4f", quantityStr, takeProfitPrice)
 	return nil
 }
 
+// SetStopLossLimit sets a stop-limit order (Algo STOP): a limit order at limitPrice is placed when stopPrice triggers
+func (t *FuturesTrader) SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error {
+	if err := t.placeLimitExitOrder(symbol, positionSide, futures.AlgoOrderTypeStop, quantity, stopPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set stop-limit: %w", err)
+	}
+	t.log().Infof("  Stop-limit set (Algo Order): trigger %.4f, limit %.4f", stopPrice, limitPrice)
+	return nil
+}
+
+// SetTakeProfitLimit sets a take-profit-limit order (Algo TAKE_PROFIT): a limit order at limitPrice is placed when takeProfitPrice triggers
+func (t *FuturesTrader) SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error {
+	if err := t.placeLimitExitOrder(symbol, positionSide, futures.AlgoOrderTypeTakeProfit, quantity, takeProfitPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set take-profit-limit: %w", err)
+	}
+	t.log().Infof("  Take-profit-limit set (Algo Order): trigger %.4f, limit %.4f", takeProfitPrice, limitPrice)
+	return nil
+}
+
+// placeLimitExitOrder places a triggered limit Algo order closing the position.
+// Limit algo orders can't use closePosition, so the quantity is sent explicitly.
+func (t *FuturesTrader) placeLimitExitOrder(symbol, positionSide string, orderType futures.AlgoOrderType, quantity, triggerPrice, limitPrice float64) error {
+	var side futures.SideType
+	var posSide futures.PositionSideType
+
+	if positionSide == "LONG" {
+		side = futures.SideTypeSell
+		posSide = futures.PositionSideTypeLong
+	} else {
+		side = futures.SideTypeBuy
+		posSide = futures.PositionSideTypeShort
+	}
+
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	tickSize, err := t.getPriceTickSize(symbol)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.client.NewCreateAlgoOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(orderType).
+		Quantity(quantityStr).
+		Price(formatLimitPrice(limitPrice, tickSize, side == futures.SideTypeBuy)).
+		TimeInForce(futures.TimeInForceTypeGTC).
+		TriggerPrice(fmt.Sprintf("%.8f", triggerPrice)).
+		WorkingType(futures.WorkingTypeContractPrice).
+		ClientAlgoId(getBrOrderID()).
+		Do(context.Background(), t.requestOpts()...)
+	return err
+}
+
+// tickSizeCacheTTL how long the price tick sizes of exchangeInfo are reused
+const tickSizeCacheTTL = time.Hour
+
+// getPriceTickSize gets the price tick size from the PRICE_FILTER filter.
+// exchangeInfo lists every symbol, so one download caches the tick sizes of all of them.
+func (t *FuturesTrader) getPriceTickSize(symbol string) (float64, error) {
+	t.tickSizeMu.Lock()
+	defer t.tickSizeMu.Unlock()
+
+	if tickSize, ok := t.tickSizes[symbol]; ok && time.Since(t.tickSizesLoaded) < tickSizeCacheTTL {
+		return tickSize, nil
+	}
+
+	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trading rules: %w", err)
+	}
+
+	tickSizes := make(map[string]float64, len(exchangeInfo.Symbols))
+	for _, s := range exchangeInfo.Symbols {
+		for _, filter := range s.Filters {
+			if filter["filterType"] == "PRICE_FILTER" {
+				tickSizeStr, _ := filter["tickSize"].(string)
+				if tickSize, err := strconv.ParseFloat(tickSizeStr, 64); err == nil && tickSize > 0 {
+					tickSizes[s.Symbol] = tickSize
+				}
+			}
+		}
+	}
+	t.tickSizes = tickSizes
+	t.tickSizesLoaded = time.Now()
+
+	if tickSize, ok := tickSizes[symbol]; ok {
+		return tickSize, nil
+	}
+	return 0, fmt.Errorf("price tick size not found for %s", symbol)
+}
+
 // GetMinNotional gets minimum notional value (Binance requirement)
 func (t *FuturesTrader) GetMinNotional(symbol string) float64 {
 	// Use conservative default value of 10 USDT to ensure order passes exchange validation
@@ -969,14 +1402,14 @@ func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
 				if filter["filterType"] == "LOT_SIZE" {
 					stepSize := filter["stepSize"].(string)
 					precision := calculatePrecision(stepSize)
-					logger.Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
+					t.log().Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
 					return precision, nil
 				}
 			}
 		}
 	}
 
-	logger.Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
+	t.log().Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
 	return 3, nil // Default precision is 3
 }
 
@@ -1060,7 +1493,7 @@ func (t *FuturesTrader) GetOrderStatus(symbol string, orderID string) (map[strin
 	order, err := t.client.NewGetOrderService().
 		Symbol(symbol).
 		OrderID(orderIDInt).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order status: %w", err)
 	}
@@ -1162,7 +1595,7 @@ func (t *FuturesTrader) GetTrades(startTime time.Time, limit int) ([]TradeRecord
 		IncomeType("REALIZED_PNL").
 		StartTime(startTime.UnixMilli()).
 		Limit(int64(limit)).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get income history: %w", err)
 	}
@@ -1204,7 +1637,7 @@ func (t *FuturesTrader) GetTradesForSymbol(symbol string, startTime time.Time, l
 		Symbol(symbol).
 		StartTime(startTime.UnixMilli()).
 		Limit(limit).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade history for %s: %w", symbol, err)
 	}
@@ -1218,6 +1651,7 @@ func (t *FuturesTrader) GetTradesForSymbol(symbol string, startTime time.Time, l
 
 		trade := TradeRecord{
 			TradeID:      strconv.FormatInt(at.ID, 10),
+			OrderID:      strconv.FormatInt(at.OrderID, 10),
 			Symbol:       at.Symbol,
 			Side:         string(at.Side),
 			PositionSide: string(at.PositionSide),
@@ -1225,6 +1659,7 @@ func (t *FuturesTrader) GetTradesForSymbol(symbol string, startTime time.Time, l
 			Quantity:     qty,
 			RealizedPnL:  pnl,
 			Fee:          fee,
+			FeeAsset:     at.CommissionAsset,
 			Time:         time.UnixMilli(at.Time).UTC(),
 		}
 		trades = append(trades, trade)
@@ -1247,7 +1682,7 @@ func (t *FuturesTrader) GetTradesForSymbolFromID(symbol string, fromID int64, li
 		Symbol(symbol).
 		FromID(fromID).
 		Limit(limit).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trade history for %s from ID %d: %w", symbol, fromID, err)
 	}
@@ -1261,6 +1696,7 @@ func (t *FuturesTrader) GetTradesForSymbolFromID(symbol string, fromID int64, li
 
 		trade := TradeRecord{
 			TradeID:      strconv.FormatInt(at.ID, 10),
+			OrderID:      strconv.FormatInt(at.OrderID, 10),
 			Symbol:       at.Symbol,
 			Side:         string(at.Side),
 			PositionSide: string(at.PositionSide),
@@ -1268,6 +1704,7 @@ func (t *FuturesTrader) GetTradesForSymbolFromID(symbol string, fromID int64, li
 			Quantity:     qty,
 			RealizedPnL:  pnl,
 			Fee:          fee,
+			FeeAsset:     at.CommissionAsset,
 			Time:         time.UnixMilli(at.Time).UTC(),
 		}
 		trades = append(trades, trade)
@@ -1283,7 +1720,7 @@ func (t *FuturesTrader) GetCommissionSymbols(lastSyncTime time.Time) ([]string,
 		IncomeType("COMMISSION").
 		StartTime(lastSyncTime.UnixMilli()).
 		Limit(1000).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commission history: %w", err)
 	}
@@ -1310,7 +1747,7 @@ func (t *FuturesTrader) GetPnLSymbols(lastSyncTime time.Time) ([]string, error)
 		IncomeType("REALIZED_PNL").
 		StartTime(lastSyncTime.UnixMilli()).
 		Limit(1000).
-		Do(context.Background())
+		Do(context.Background(), t.requestOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PnL history: %w", err)
 	}
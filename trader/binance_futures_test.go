@@ -343,7 +343,7 @@ func TestNewFuturesTrader(t *testing.T) {
 	defer mockServer.Close()
 
 	// Test successful creation
-	trader := NewFuturesTrader("test_api_key", "test_secret_key", "test_user")
+	trader := NewFuturesTrader("test_api_key", "test_secret_key", "test_user", false)
 
 	// Modify client to use mock server
 	trader.client.BaseURL = mockServer.URL
@@ -418,3 +418,37 @@ func TestGetBrOrderID(t *testing.T) {
 		ids[id] = true
 	}
 }
+
+// TestFuturesTrader_PriceTickSizeCached tests that one exchangeInfo download serves every symbol
+func TestFuturesTrader_PriceTickSizeCached(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fapi/v1/exchangeInfo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols":[
+			{"symbol":"BTCUSDT","filters":[{"filterType":"PRICE_FILTER","tickSize":"0.10"}]},
+			{"symbol":"ETHUSDT","filters":[{"filterType":"PRICE_FILTER","tickSize":"0.01"}]}]}`))
+	}))
+	defer mockServer.Close()
+
+	client := futures.NewClient("test_api_key", "test_secret_key")
+	client.BaseURL = mockServer.URL
+	trader := &FuturesTrader{client: client}
+
+	for _, tc := range []struct {
+		symbol string
+		want   float64
+	}{{"BTCUSDT", 0.1}, {"ETHUSDT", 0.01}, {"BTCUSDT", 0.1}} {
+		got, err := trader.getPriceTickSize(tc.symbol)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.symbol)
+	}
+	assert.Equal(t, 1, requests, "exchangeInfo should be downloaded once")
+
+	_, err := trader.getPriceTickSize("DOGEUSDT")
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"nofx/store"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// binanceKeyRoundTripper re-signs the SDK's signed requests (those carrying a signature parameter)
+// with the next key of the pool. Unsigned requests (market data, listenKey) keep the primary key.
+type binanceKeyRoundTripper struct {
+	base    http.RoundTripper
+	keys    *APIKeyPool
+	primary store.APIKeyCredential
+}
+
+func (b *binanceKeyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	payload, ok := binanceSignedPayload(req.URL.RawQuery)
+	if !ok {
+		return b.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+	}
+
+	key, cred := b.keys.Next(b.primary)
+	mac := hmac.New(sha256.New, []byte(cred.SecretKey))
+	mac.Write([]byte(payload + string(body)))
+	signature := url.Values{"signature": {hex.EncodeToString(mac.Sum(nil))}}.Encode()
+
+	out := req.Clone(req.Context())
+	if payload == "" {
+		out.URL.RawQuery = signature
+	} else {
+		out.URL.RawQuery = payload + "&" + signature
+	}
+	out.Header.Set("X-MBX-APIKEY", cred.APIKey)
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+	}
+
+	resp, err := b.base.RoundTrip(out)
+	if err == nil {
+		b.keys.ObserveResponse(key, resp)
+	}
+	return resp, err
+}
+
+// binanceSignedPayload strips the trailing signature parameter the SDK appends to signed requests,
+// returning the signed query string; false for unsigned requests
+func binanceSignedPayload(rawQuery string) (string, bool) {
+	var idx int
+	switch {
+	case strings.HasPrefix(rawQuery, "signature="):
+		idx = 0
+	case strings.Contains(rawQuery, "&signature="):
+		idx = strings.LastIndex(rawQuery, "&signature=")
+	default:
+		return "", false
+	}
+	return rawQuery[:idx], true
+}
+
+// AddAPIKeys rotates signed requests of the futures and Portfolio Margin clients across the primary
+// and the additional API keys (MultiKeyTrader). Only HMAC keys can be pooled.
+func (t *FuturesTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	if t.client.KeyType != "" && t.client.KeyType != common.KeyTypeHmac {
+		t.log().Warnf("⚠️ [Binance] Additional API keys ignored: only HMAC keys can be rotated")
+		return
+	}
+	primary := store.APIKeyCredential{APIKey: t.client.APIKey, SecretKey: t.client.SecretKey}
+	pool := NewAPIKeyPool(primary, keys)
+	if pool == nil {
+		return
+	}
+
+	base := http.DefaultTransport
+	httpClient := &http.Client{}
+	if t.client.HTTPClient != nil {
+		// Keep the transport and timeout set up for this client (proxy hooks); never mutate the
+		// client itself, it may be http.DefaultClient
+		*httpClient = *t.client.HTTPClient
+		if t.client.HTTPClient.Transport != nil {
+			base = t.client.HTTPClient.Transport
+		}
+	}
+	httpClient.Transport = &binanceKeyRoundTripper{base: base, keys: pool, primary: primary}
+
+	t.client.HTTPClient = httpClient
+	if t.pmClient != nil {
+		t.pmClient.HTTPClient = httpClient
+	}
+}
@@ -2,7 +2,6 @@ package trader
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -39,19 +38,19 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		if err == nil && lastFillTimeMs > 0 {
 			// If recovered time is in the future, it's clearly wrong - use default
 			if lastFillTimeMs > nowMs {
-				logger.Infof("⚠️ DB sync time %d is in the future (now: %d), using default",
+				t.log().Infof("⚠️ DB sync time %d is in the future (now: %d), using default",
 					lastFillTimeMs, nowMs)
 				lastSyncTimeMs = nowMs - 24*60*60*1000 // 24 hours ago
 			} else {
 				// Add 1 second buffer to avoid re-fetching the same fill
 				lastSyncTimeMs = lastFillTimeMs + 1000
-				logger.Infof("📅 Recovered last sync time from DB: %s (UTC)",
+				t.log().Infof("📅 Recovered last sync time from DB: %s (UTC)",
 					time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"))
 			}
 		} else {
 			// First sync: go back 24 hours
 			lastSyncTimeMs = nowMs - 24*60*60*1000
-			logger.Infof("📅 First sync, starting from 24 hours ago: %s (UTC)",
+			t.log().Infof("📅 First sync, starting from 24 hours ago: %s (UTC)",
 				time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"))
 		}
 	}
@@ -60,13 +59,13 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// This prevents race condition where trades happen between query and lastSyncTime update
 	syncStartTimeMs := nowMs
 
-	logger.Infof("🔄 Syncing Binance trades from: %s (UTC)",
+	t.log().Infof("🔄 Syncing Binance trades from: %s (UTC)",
 		time.UnixMilli(lastSyncTimeMs).UTC().Format("2006-01-02 15:04:05"))
 
 	// Step 1: Get max trade IDs from local DB for incremental sync
 	maxTradeIDs, err := orderStore.GetMaxTradeIDsByExchange(exchangeID)
 	if err != nil {
-		logger.Infof("  ⚠️ Failed to get max trade IDs: %v, will use time-based query", err)
+		t.log().Infof("  ⚠️ Failed to get max trade IDs: %v, will use time-based query", err)
 		maxTradeIDs = make(map[string]int64)
 	}
 
@@ -78,9 +77,9 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// Method 1: COMMISSION income detection
 	commissionSymbols, err := t.GetCommissionSymbols(lastSyncTime)
 	if err != nil {
-		logger.Infof("  ⚠️ Failed to get commission symbols: %v", err)
+		t.log().Infof("  ⚠️ Failed to get commission symbols: %v", err)
 	} else {
-		logger.Infof("  📋 COMMISSION symbols found: %d - %v", len(commissionSymbols), commissionSymbols)
+		t.log().Infof("  📋 COMMISSION symbols found: %d - %v", len(commissionSymbols), commissionSymbols)
 		for _, s := range commissionSymbols {
 			symbolMap[s] = true
 		}
@@ -88,14 +87,14 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 
 	// Method 2: Always include active positions (catches trades that COMMISSION missed)
 	positionSymbols := t.getPositionSymbols()
-	logger.Infof("  📋 Position symbols found: %d - %v", len(positionSymbols), positionSymbols)
+	t.log().Infof("  📋 Position symbols found: %d - %v", len(positionSymbols), positionSymbols)
 	for _, s := range positionSymbols {
 		symbolMap[s] = true
 	}
 
 	// Method 3: Include symbols from recent fills in DB (in case some were partially synced)
 	recentSymbols, _ := orderStore.GetRecentFillSymbolsByExchange(exchangeID, lastSyncTimeMs)
-	logger.Infof("  📋 Recent fill symbols found: %d - %v", len(recentSymbols), recentSymbols)
+	t.log().Infof("  📋 Recent fill symbols found: %d - %v", len(recentSymbols), recentSymbols)
 	for _, s := range recentSymbols {
 		symbolMap[s] = true
 	}
@@ -103,12 +102,12 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// Method 4: FALLBACK - Query REALIZED_PNL income to find symbols with closed trades
 	// This catches trades that COMMISSION missed (VIP users, BNB fee discount)
 	if len(symbolMap) == 0 {
-		logger.Infof("  🔍 No symbols found, trying REALIZED_PNL fallback...")
+		t.log().Infof("  🔍 No symbols found, trying REALIZED_PNL fallback...")
 		pnlSymbols, err := t.GetPnLSymbols(lastSyncTime)
 		if err != nil {
-			logger.Infof("  ⚠️ Failed to get PnL symbols: %v", err)
+			t.log().Infof("  ⚠️ Failed to get PnL symbols: %v", err)
 		} else {
-			logger.Infof("  📋 REALIZED_PNL symbols found: %d - %v", len(pnlSymbols), pnlSymbols)
+			t.log().Infof("  📋 REALIZED_PNL symbols found: %d - %v", len(pnlSymbols), pnlSymbols)
 			for _, s := range pnlSymbols {
 				symbolMap[s] = true
 			}
@@ -121,7 +120,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	}
 
 	if len(changedSymbols) == 0 {
-		logger.Infof("📭 No symbols with new trades to sync")
+		t.log().Infof("📭 No symbols with new trades to sync")
 		// Update last sync time even if no changes
 		binanceSyncStateMutex.Lock()
 		binanceSyncState[exchangeID] = syncStartTimeMs
@@ -129,7 +128,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		return nil
 	}
 
-	logger.Infof("📊 Found %d symbols with new trades: %v", len(changedSymbols), changedSymbols)
+	t.log().Infof("📊 Found %d symbols with new trades: %v", len(changedSymbols), changedSymbols)
 
 	// Step 3: Query trades for changed symbols using fromId (incremental) or time-based (new symbols)
 	var allTrades []TradeRecord
@@ -149,14 +148,14 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		apiCalls++
 
 		if queryErr != nil {
-			logger.Infof("  ⚠️ Failed to get trades for %s: %v", symbol, queryErr)
+			t.log().Infof("  ⚠️ Failed to get trades for %s: %v", symbol, queryErr)
 			failedSymbols = append(failedSymbols, symbol)
 			continue
 		}
 		allTrades = append(allTrades, trades...)
 	}
 
-	logger.Infof("📥 Received %d trades from Binance (%d API calls)", len(allTrades), apiCalls)
+	t.log().Infof("📥 Received %d trades from Binance (%d API calls)", len(allTrades), apiCalls)
 
 	// Only update last sync time if ALL symbols were successfully queried
 	// This prevents data loss when some symbols fail due to rate limit or network issues
@@ -165,7 +164,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		binanceSyncState[exchangeID] = syncStartTimeMs
 		binanceSyncStateMutex.Unlock()
 	} else {
-		logger.Infof("  ⚠️ %d symbols failed, not updating lastSyncTime to retry next time: %v", len(failedSymbols), failedSymbols)
+		t.log().Infof("  ⚠️ %d symbols failed, not updating lastSyncTime to retry next time: %v", len(failedSymbols), failedSymbols)
 	}
 
 	if len(allTrades) == 0 {
@@ -180,6 +179,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 	// Process trades one by one
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 	syncedCount := 0
 
 	for _, trade := range allTrades {
@@ -209,6 +209,9 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 		// Normalize side
 		side := strings.ToUpper(trade.Side)
 
+		// Fees charged in the base coin or a discount token are valued in the quote currency
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.Price)
+
 		// Create order record - use Unix milliseconds UTC
 		tradeTimeMs := trade.Time.UTC().UnixMilli()
 		orderRecord := &store.TraderOrder{
@@ -216,6 +219,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 			ExchangeID:      exchangeID,
 			ExchangeType:    exchangeType,
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			Symbol:          symbol,
 			Side:            side,
 			PositionSide:    positionSide,
@@ -226,7 +230,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 			Status:          "FILLED",
 			FilledQuantity:  trade.Quantity,
 			AvgFillPrice:    trade.Price,
-			Commission:      trade.Fee,
+			Commission:      feeUSDT,
 			FilledAt:        tradeTimeMs,
 			CreatedAt:       tradeTimeMs,
 			UpdatedAt:       tradeTimeMs,
@@ -234,7 +238,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -245,6 +249,7 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 			ExchangeType:    exchangeType,
 			OrderID:         orderRecord.ID,
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			ExchangeTradeID: trade.TradeID,
 			Symbol:          symbol,
 			Side:            side,
@@ -252,35 +257,36 @@ func (t *FuturesTrader) SyncOrdersFromBinance(traderID string, exchangeID string
 			Quantity:        trade.Quantity,
 			QuoteQuantity:   trade.Price * trade.Quantity,
 			Commission:      trade.Fee,
-			CommissionAsset: "USDT",
+			CommissionAsset: commissionAsset(trade.FeeAsset),
+			CommissionUSDT:  feeUSDT,
 			RealizedPnL:     trade.RealizedPnL,
 			IsMaker:         false,
 			CreatedAt:       tradeTimeMs,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			symbol, positionSide, orderAction,
-			trade.Quantity, trade.Price, trade.Fee, trade.RealizedPnL,
+			trade.Quantity, trade.Price, feeUSDT, trade.RealizedPnL,
 			tradeTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s time=%s(UTC)",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s time=%s(UTC)",
 			trade.TradeID, symbol, side, trade.Quantity, trade.Price, trade.RealizedPnL, trade.Fee, orderAction,
 			trade.Time.UTC().Format("01-02 15:04:05"))
 	}
 
-	logger.Infof("✅ Binance order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Binance order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
@@ -342,24 +348,7 @@ func (t *FuturesTrader) determineOrderAction(side, positionSide string, realized
 	return "open_short"
 }
 
-// StartOrderSync starts background order sync task for Binance
-func (t *FuturesTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	// Run first sync immediately
-	go func() {
-		logger.Infof("🔄 Running initial Binance order sync...")
-		if err := t.SyncOrdersFromBinance(traderID, exchangeID, exchangeType, st); err != nil {
-			logger.Infof("⚠️  Initial Binance order sync failed: %v", err)
-		}
-	}()
-
-	// Then run periodically
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromBinance(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Infof("⚠️  Binance order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Binance order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Binance
+func (t *FuturesTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromBinance(traderID, exchangeID, exchangeType, st)
 }
@@ -0,0 +1,151 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/adshao/go-binance/v2/portfolio"
+)
+
+// Binance account modes, detected on first balance/position query
+const (
+	binanceAccountUnknown   int32 = iota
+	binanceAccountFutures         // Standard USDT-M futures account (fapi)
+	binanceAccountPortfolio       // Portfolio Margin account (papi, unified cross-asset collateral)
+)
+
+// newBinancePortfolioClient creates the Portfolio Margin API client sharing the futures client's transport
+func newBinancePortfolioClient(apiKey, secretKey string, futuresClient *futures.Client) *portfolio.Client {
+	client := portfolio.NewClient(apiKey, secretKey)
+	if futuresClient.HTTPClient != nil {
+		client.HTTPClient = futuresClient.HTTPClient
+	}
+	return client
+}
+
+// pmRequestOpts options of signed Portfolio Margin requests (see requestOpts)
+func (t *FuturesTrader) pmRequestOpts() []portfolio.RequestOption {
+	if ms := recvWindowOr(0); ms > 0 {
+		return []portfolio.RequestOption{portfolio.WithRecvWindow(ms)}
+	}
+	return nil
+}
+
+// isPortfolioMargin reports whether the account was detected as a Portfolio Margin account
+func (t *FuturesTrader) isPortfolioMargin() bool {
+	return atomic.LoadInt32(&t.accountMode) == binanceAccountPortfolio
+}
+
+// markFuturesAccount records that the standard futures API works for this account
+func (t *FuturesTrader) markFuturesAccount() {
+	atomic.CompareAndSwapInt32(&t.accountMode, binanceAccountUnknown, binanceAccountFutures)
+}
+
+// detectPortfolioMargin is called when a standard futures query fails before the account mode is
+// known: Portfolio Margin accounts can't use the fapi account endpoints, so the PM account endpoint
+// is tried instead. Returns true if the account is a Portfolio Margin account.
+func (t *FuturesTrader) detectPortfolioMargin() bool {
+	if t.pmClient == nil || atomic.LoadInt32(&t.accountMode) != binanceAccountUnknown {
+		return t.isPortfolioMargin()
+	}
+
+	account, err := t.pmClient.NewGetAccountService().Do(context.Background(), t.pmRequestOpts()...)
+	if err != nil || account.AccountStatus == "" {
+		return false
+	}
+	if atomic.CompareAndSwapInt32(&t.accountMode, binanceAccountUnknown, binanceAccountPortfolio) {
+		t.log().Infof("✓ [Binance] Portfolio Margin account detected (status %s, uniMMR %s), using unified account balance",
+			account.AccountStatus, account.UniMMR)
+	}
+	return true
+}
+
+// getPortfolioMarginBalance gets the unified Portfolio Margin balance mapped to the normalized balance map:
+// totalEquity is the USD account equity without collateral haircuts, availableBalance is what can still
+// be used as margin (collateral rates applied)
+func (t *FuturesTrader) getPortfolioMarginBalance() (map[string]interface{}, error) {
+	account, err := t.pmClient.NewGetAccountService().Do(context.Background(), t.pmRequestOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio margin account: %w", err)
+	}
+
+	// Unrealized PnL of the UM (USDT-M) book, the account endpoint only reports equity
+	unrealized := 0.0
+	if detail, err := t.pmClient.NewGetUMAccountDetailService().Do(context.Background(), t.pmRequestOpts()...); err == nil {
+		for _, asset := range detail.Assets {
+			pnl, _ := strconv.ParseFloat(asset.CrossUnPnl, 64)
+			unrealized += pnl
+		}
+	} else {
+		t.log().Infof("⚠️ [Binance] Failed to get portfolio margin UM account detail: %v", err)
+	}
+
+	actualEquity, _ := strconv.ParseFloat(account.ActualEquity, 64)
+	accountEquity, _ := strconv.ParseFloat(account.AccountEquity, 64)
+	available, _ := strconv.ParseFloat(account.TotalAvailableBalance, 64)
+	maintMargin, _ := strconv.ParseFloat(account.AccountMaintMargin, 64)
+	uniMMR, _ := strconv.ParseFloat(account.UniMMR, 64)
+
+	result := map[string]interface{}{
+		"totalEquity":           actualEquity,
+		"totalWalletBalance":    actualEquity - unrealized,
+		"availableBalance":      available,
+		"totalUnrealizedProfit": unrealized,
+		"collateralEquity":      accountEquity, // Equity after collateral rates
+		"maintMargin":           maintMargin,
+		"uniMMR":                uniMMR, // Unified maintenance margin ratio (liquidation below 1.05)
+		"accountType":           "portfolio_margin",
+	}
+
+	t.log().Infof("✓ Binance PM API returned: equity=%s (collateral %s), available=%s, uniMMR=%s",
+		account.ActualEquity, account.AccountEquity, account.TotalAvailableBalance, account.UniMMR)
+
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// getPortfolioMarginPositions gets the UM positions of a Portfolio Margin account
+func (t *FuturesTrader) getPortfolioMarginPositions() ([]map[string]interface{}, error) {
+	positions, err := t.pmClient.NewGetUMPositionRiskService().Do(context.Background(), t.pmRequestOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio margin positions: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for _, pos := range positions {
+		posAmt, _ := strconv.ParseFloat(pos.PositionAmt, 64)
+		if posAmt == 0 {
+			continue
+		}
+
+		posMap := make(map[string]interface{})
+		posMap["symbol"] = pos.Symbol
+		posMap["positionAmt"] = posAmt
+		posMap["entryPrice"], _ = strconv.ParseFloat(pos.EntryPrice, 64)
+		posMap["markPrice"], _ = strconv.ParseFloat(pos.MarkPrice, 64)
+		posMap["unRealizedProfit"], _ = strconv.ParseFloat(pos.UnrealizedProfit, 64)
+		posMap["leverage"], _ = strconv.ParseFloat(pos.Leverage, 64)
+		posMap["liquidationPrice"], _ = strconv.ParseFloat(pos.LiquidationPrice, 64)
+		if posAmt > 0 {
+			posMap["side"] = "long"
+		} else {
+			posMap["side"] = "short"
+		}
+		result = append(result, posMap)
+	}
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
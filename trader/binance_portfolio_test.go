@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/adshao/go-binance/v2/portfolio"
+)
+
+func TestBinancePortfolioMarginBalance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fapi/v2/account", "/fapi/v3/account":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`))
+		case "/papi/v1/account":
+			w.Write([]byte(`{"uniMMR":"5.2","accountEquity":"1180.5","actualEquity":"1250.0","accountMaintMargin":"30.1","accountStatus":"NORMAL","totalAvailableBalance":"900.25"}`))
+		case "/papi/v1/um/account":
+			w.Write([]byte(`{"assets":[{"asset":"USDT","crossUnPnl":"40.0"},{"asset":"USDC","crossUnPnl":"10.0"}],"positions":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := futures.NewClient("key", "secret")
+	client.BaseURL = srv.URL
+	pmClient := portfolio.NewClient("key", "secret")
+	pmClient.BaseURL = srv.URL
+	tr := &FuturesTrader{
+		client:        client,
+		pmClient:      pmClient,
+		clock:         newBinanceClockSync(client, pmClient),
+		cacheDuration: 0,
+	}
+
+	balance, err := tr.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance() error: %v", err)
+	}
+	if !tr.isPortfolioMargin() {
+		t.Fatal("account should be detected as portfolio margin")
+	}
+
+	want := map[string]float64{
+		"totalEquity":           1250,
+		"totalWalletBalance":    1200,
+		"availableBalance":      900.25,
+		"totalUnrealizedProfit": 50,
+		"collateralEquity":      1180.5,
+	}
+	for key, v := range want {
+		if got, _ := balance[key].(float64); got != v {
+			t.Errorf("balance[%s] = %v, want %v", key, balance[key], v)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GetTransfers returns transfers into/out of the futures wallet (TRANSFER income, positive = in)
+func (t *FuturesTrader) GetTransfers(since time.Time) ([]Transfer, error) {
+	incomes, err := t.client.NewGetIncomeHistoryService().
+		IncomeType("TRANSFER").
+		StartTime(since.UnixMilli()).
+		Limit(1000).
+		Do(context.Background(), t.requestOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer income history: %w", err)
+	}
+
+	transfers := make([]Transfer, 0, len(incomes))
+	for _, income := range incomes {
+		amount, _ := strconv.ParseFloat(income.Income, 64)
+		transfers = append(transfers, Transfer{
+			ID:     strconv.FormatInt(income.TranID, 10),
+			Asset:  income.Asset,
+			Amount: amount,
+			Time:   time.UnixMilli(income.Time).UTC(),
+		})
+	}
+	return transfers, nil
+}
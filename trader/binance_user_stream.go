@@ -0,0 +1,151 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// binanceListenKeyKeepalive listen keys expire after 60 minutes without keepalive
+	binanceListenKeyKeepalive = 30 * time.Minute
+	// binanceStreamReadTimeout Binance pings every 3 minutes, a silent connection beyond this is dead
+	binanceStreamReadTimeout = 10 * time.Minute
+)
+
+// binanceUserDataEvent subset of a futures user data stream event
+type binanceUserDataEvent struct {
+	Event string `json:"e"`
+	Order struct {
+		Symbol          string `json:"s"`
+		OrderID         int64  `json:"i"`
+		Status          string `json:"X"`
+		ExecType        string `json:"x"`
+		AvgPrice        string `json:"ap"`
+		FilledQty       string `json:"z"`
+		Commission      string `json:"n"` // Commission of this trade only
+		CommissionAsset string `json:"N"` // Not pushed without a commission
+	} `json:"o"`
+}
+
+// StartOrderStream implements OrderFillWaiter using the Binance futures user data stream (listenKey)
+func (t *FuturesTrader) StartOrderStream(stopCh <-chan struct{}) error {
+	if t.orderHub == nil {
+		return errOrderStreamDown
+	}
+	if !t.orderHub.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	wsBase := futures.BaseWsMainUrl
+	if t.client.BaseURL == futures.BaseApiTestnetUrl {
+		wsBase = futures.BaseWsTestnetUrl
+	}
+	t.orderHub.runStreamLoop("Binance", t.log, stopCh, func(stopCh <-chan struct{}, onConnected func()) error {
+		return t.serveUserDataStream(wsBase, stopCh, onConnected)
+	})
+	return nil
+}
+
+// WaitOrderFill implements OrderFillWaiter
+func (t *FuturesTrader) WaitOrderFill(symbol, orderID string, timeout time.Duration) (map[string]interface{}, error) {
+	return t.orderHub.wait(orderID, timeout)
+}
+
+// serveUserDataStream runs one user data stream connection until it fails or stopCh is closed
+func (t *FuturesTrader) serveUserDataStream(wsBase string, stopCh <-chan struct{}, onConnected func()) error {
+	listenKey, err := t.client.NewStartUserStreamService().Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to create listen key: %w", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsBase+"/"+listenKey, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect user data stream: %w", err)
+	}
+	defer conn.Close()
+	onConnected()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(binanceListenKeyKeepalive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := t.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(context.Background()); err != nil {
+					t.log().Warnf("⚠️ [Binance] Listen key keepalive failed: %v", err)
+				}
+			case <-stopCh:
+				conn.Close()
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(binanceStreamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+
+	// Commissions arrive per trade, summed per order until it's final
+	commissions := make(map[int64]float64)
+	commissionAssets := make(map[int64]string)
+	for {
+		conn.SetReadDeadline(time.Now().Add(binanceStreamReadTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var event binanceUserDataEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+		switch event.Event {
+		case "listenKeyExpired":
+			return fmt.Errorf("listen key expired")
+		case "ORDER_TRADE_UPDATE":
+		default:
+			continue
+		}
+
+		o := event.Order
+		if fee, err := strconv.ParseFloat(o.Commission, 64); err == nil && o.ExecType == "TRADE" {
+			commissions[o.OrderID] += fee
+			if o.CommissionAsset != "" {
+				commissionAssets[o.OrderID] = o.CommissionAsset
+			}
+		}
+		status := o.Status
+		if status == "EXPIRED_IN_MATCH" {
+			status = "EXPIRED"
+		}
+		if !isFinalOrderStatus(status) {
+			continue
+		}
+
+		avgPrice, _ := strconv.ParseFloat(o.AvgPrice, 64)
+		executedQty, _ := strconv.ParseFloat(o.FilledQty, 64)
+		orderID := strconv.FormatInt(o.OrderID, 10)
+		t.orderHub.publish(orderID, map[string]interface{}{
+			"orderId":         o.OrderID,
+			"symbol":          o.Symbol,
+			"status":          status,
+			"avgPrice":        avgPrice,
+			"executedQty":     executedQty,
+			"commission":      commissions[o.OrderID],
+			"commissionAsset": commissionAssets[o.OrderID],
+		})
+		delete(commissions, o.OrderID)
+		delete(commissionAssets, o.OrderID)
+	}
+}
@@ -3,7 +3,6 @@ package trader
 import (
 	"encoding/json"
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -134,7 +133,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Bitget trades from: %s", startTime.Format(time.RFC3339))
+	t.log().Infof("🔄 Syncing Bitget trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 100)
@@ -142,7 +141,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Bitget", len(trades))
+	t.log().Infof("📥 Received %d trades from Bitget", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -153,6 +152,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 	syncedCount := 0
 
 	for _, trade := range trades {
@@ -174,6 +174,9 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 		// Normalize side for storage
 		side := strings.ToUpper(trade.Side)
 
+		// Fees charged in the base coin or a discount token are valued in the quote currency
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.FillPrice)
+
 		// Create order record - use UTC time in milliseconds to avoid timezone issues
 		execTimeMs := trade.ExecTime.UTC().UnixMilli()
 		orderRecord := &store.TraderOrder{
@@ -181,6 +184,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 			ExchangeID:      exchangeID,   // UUID
 			ExchangeType:    exchangeType, // Exchange type
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			Symbol:          symbol,
 			Side:            side,
 			PositionSide:    "BOTH", // Bitget uses one-way position mode
@@ -191,7 +195,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 			Status:          "FILLED",
 			FilledQuantity:  trade.FillQty,
 			AvgFillPrice:    trade.FillPrice,
-			Commission:      trade.Fee,
+			Commission:      feeUSDT,
 			FilledAt:        execTimeMs,
 			CreatedAt:       execTimeMs,
 			UpdatedAt:       execTimeMs,
@@ -199,7 +203,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -210,6 +214,7 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 			ExchangeType:    exchangeType, // Exchange type
 			OrderID:         orderRecord.ID,
 			ExchangeOrderID: trade.OrderID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			ExchangeTradeID: trade.TradeID,
 			Symbol:          symbol,
 			Side:            side,
@@ -218,45 +223,38 @@ func (t *BitgetTrader) SyncOrdersFromBitget(traderID string, exchangeID string,
 			QuoteQuantity:   trade.FillPrice * trade.FillQty,
 			Commission:      trade.Fee,
 			CommissionAsset: trade.FeeAsset,
+			CommissionUSDT:  feeUSDT,
 			RealizedPnL:     trade.ProfitLoss,
 			IsMaker:         false,
 			CreatedAt:       execTimeMs,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			symbol, positionSide, trade.OrderAction,
-			trade.FillQty, trade.FillPrice, trade.Fee, trade.ProfitLoss,
+			trade.FillQty, trade.FillPrice, feeUSDT, trade.ProfitLoss,
 			execTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, trade.OrderAction, trade.FillQty)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.FillQty, trade.FillPrice, trade.ProfitLoss, trade.Fee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ Bitget order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Bitget order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
-// StartOrderSync starts background order sync task for Bitget
-func (t *BitgetTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromBitget(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Infof("⚠️  Bitget order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Bitget order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Bitget
+func (t *BitgetTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromBitget(traderID, exchangeID, exchangeType, st)
 }
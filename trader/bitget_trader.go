@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"nofx/logger"
+	"nofx/store"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,15 +34,29 @@ const (
 	bitgetPositionModePath = "/api/v2/mix/account/set-position-mode"
 )
 
+// bitgetCodeRateLimited Bitget error code "Too Many Requests"
+const bitgetCodeRateLimited = "429"
+
 // BitgetTrader Bitget futures trader
 type BitgetTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	apiKey     string
 	secretKey  string
 	passphrase string
 
+	// Demo trading: requests carry the paptrading header
+	testnet bool
+
+	// Additional API keys of the account requests rotate across (nil = primary key only)
+	keys *APIKeyPool
+
 	// HTTP client
 	httpClient *http.Client
 
+	// Exchange clock offset for request timestamps
+	clock *ClockSync
+
 	// Balance cache
 	cachedBalance     map[string]interface{}
 	balanceCacheTime  time.Time
@@ -80,8 +96,8 @@ type BitgetResponse struct {
 	RequestTime int64       `json:"requestTime"`
 }
 
-// NewBitgetTrader creates a Bitget trader
-func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
+// NewBitgetTrader creates a Bitget trader (testnet = Bitget demo trading, same host with paptrading header)
+func NewBitgetTrader(apiKey, secretKey, passphrase string, testnet bool) *BitgetTrader {
 	httpClient := &http.Client{
 		Timeout:   30 * time.Second,
 		Transport: http.DefaultTransport,
@@ -91,10 +107,12 @@ func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
 		apiKey:         apiKey,
 		secretKey:      secretKey,
 		passphrase:     passphrase,
+		testnet:        testnet,
 		httpClient:     httpClient,
 		cacheDuration:  15 * time.Second,
 		contractsCache: make(map[string]*BitgetContract),
 	}
+	trader.clock = NewClockSync("Bitget", httpServerTimeFetcher(httpClient, bitgetBaseURL+"/api/v2/public/time", parseBitgetServerTime))
 
 	// Set one-way position mode (net mode)
 	if err := trader.setPositionMode(); err != nil {
@@ -106,6 +124,19 @@ func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
 	return trader
 }
 
+// parseBitgetServerTime parses /api/v2/public/time: {"data":{"serverTime":"1688008631614"}}
+func parseBitgetServerTime(body []byte) (int64, error) {
+	var resp struct {
+		Data struct {
+			ServerTime json.RawMessage `json:"serverTime"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, err
+	}
+	return parseMillisField(resp.Data.ServerTime)
+}
+
 // setPositionMode sets one-way position mode
 func (t *BitgetTrader) setPositionMode() error {
 	body := map[string]interface{}{
@@ -121,15 +152,20 @@ func (t *BitgetTrader) setPositionMode() error {
 		return err
 	}
 
-	logger.Infof("  ✓ Bitget account switched to one-way position mode")
+	t.log().Infof("  ✓ Bitget account switched to one-way position mode")
 	return nil
 }
 
+// AddAPIKeys rotates requests across the primary and the additional API keys (MultiKeyTrader)
+func (t *BitgetTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	t.keys = NewAPIKeyPool(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase}, keys)
+}
+
 // sign generates Bitget API signature
-func (t *BitgetTrader) sign(timestamp, method, requestPath, body string) string {
+func (t *BitgetTrader) sign(secretKey, timestamp, method, requestPath, body string) string {
 	// Signature = BASE64(HMAC_SHA256(timestamp + method + requestPath + body, secretKey))
 	preHash := timestamp + method + requestPath + body
-	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h := hmac.New(sha256.New, []byte(secretKey))
 	h.Write([]byte(preHash))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
@@ -161,14 +197,15 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 		}
 	}
 
-	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	key, cred := t.keys.Next(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase})
+	timestamp := fmt.Sprintf("%d", t.clock.Now().UnixMilli())
 
 	// Signature includes body for POST, nothing for GET (query is in path)
 	signBody := ""
 	if method != "GET" && bodyBytes != nil {
 		signBody = string(bodyBytes)
 	}
-	signature := t.sign(timestamp, method, path, signBody)
+	signature := t.sign(cred.SecretKey, timestamp, method, path, signBody)
 
 	url := bitgetBaseURL + path
 	req, err := http.NewRequest(method, url, bytes.NewReader(bodyBytes))
@@ -176,12 +213,16 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("ACCESS-KEY", t.apiKey)
+	req.Header.Set("ACCESS-KEY", cred.APIKey)
 	req.Header.Set("ACCESS-SIGN", signature)
 	req.Header.Set("ACCESS-TIMESTAMP", timestamp)
-	req.Header.Set("ACCESS-PASSPHRASE", t.passphrase)
+	req.Header.Set("ACCESS-PASSPHRASE", cred.Passphrase)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("locale", "en-US")
+	// Demo trading uses the live host and keys created in demo mode
+	if t.testnet {
+		req.Header.Set("paptrading", "1")
+	}
 	// Channel code only for order endpoints
 	if strings.Contains(path, "/order/") {
 		req.Header.Set("X-CHANNEL-API-CODE", "7fygt")
@@ -192,6 +233,7 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	t.keys.ObserveResponse(key, resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -204,6 +246,9 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 	}
 
 	if bitgetResp.Code != "00000" {
+		if bitgetResp.Code == bitgetCodeRateLimited {
+			t.keys.CoolDown(key, 0)
+		}
 		return nil, fmt.Errorf("Bitget API error: code=%s, msg=%s", bitgetResp.Code, bitgetResp.Msg)
 	}
 
@@ -254,7 +299,7 @@ func (t *BitgetTrader) GetBalance() (map[string]interface{}, error) {
 			totalEquity, _ = strconv.ParseFloat(acc.AccountEquity, 64)
 			availableBalance, _ = strconv.ParseFloat(acc.Available, 64)
 			unrealizedPnL, _ = strconv.ParseFloat(acc.UnrealizedPL, 64)
-			logger.Infof("✓ [Bitget] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
+			t.log().Infof("✓ [Bitget] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
 			break
 		}
 	}
@@ -451,13 +496,13 @@ func (t *BitgetTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 			return nil
 		}
 		if strings.Contains(err.Error(), "position") {
-			logger.Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
+			t.log().Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
 			return nil
 		}
 		return err
 	}
 
-	logger.Infof("  ✓ %s margin mode set to %s", symbol, marginMode)
+	t.log().Infof("  ✓ %s margin mode set to %s", symbol, marginMode)
 	return nil
 }
 
@@ -477,11 +522,11 @@ func (t *BitgetTrader) SetLeverage(symbol string, leverage int) error {
 		if strings.Contains(err.Error(), "same") {
 			return nil
 		}
-		logger.Infof("  ⚠️ Failed to set %s leverage: %v", symbol, err)
+		t.log().Infof("  ⚠️ Failed to set %s leverage: %v", symbol, err)
 		return err
 	}
 
-	logger.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
+	t.log().Infof("  ✓ %s leverage set to %dx", symbol, leverage)
 	return nil
 }
 
@@ -494,7 +539,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	// Format quantity
@@ -511,7 +556,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
+	t.log().Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -530,7 +575,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget opened long position successfully: %s", symbol)
+	t.log().Infof("✓ Bitget opened long position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -548,7 +593,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	// Format quantity
@@ -565,7 +610,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
+	t.log().Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -584,7 +629,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget opened short position successfully: %s", symbol)
+	t.log().Infof("✓ Bitget opened short position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -629,7 +674,7 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget CloseLong: symbol=%s, qty=%s", symbol, qtyStr)
+	t.log().Infof("  📊 Bitget CloseLong: symbol=%s, qty=%s", symbol, qtyStr)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -647,7 +692,7 @@ func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]in
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget closed long position successfully: %s", symbol)
+	t.log().Infof("✓ Bitget closed long position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -697,7 +742,7 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 		"clientOid":   genBitgetClientOid(),
 	}
 
-	logger.Infof("  📊 Bitget CloseShort: symbol=%s, qty=%s", symbol, qtyStr)
+	t.log().Infof("  📊 Bitget CloseShort: symbol=%s, qty=%s", symbol, qtyStr)
 
 	data, err := t.doRequest("POST", bitgetOrderPath, body)
 	if err != nil {
@@ -715,7 +760,7 @@ func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]i
 	// Clear cache
 	t.clearCache()
 
-	logger.Infof("✓ Bitget closed short position successfully: %s", symbol)
+	t.log().Infof("✓ Bitget closed short position successfully: %s", symbol)
 
 	return map[string]interface{}{
 		"orderId": order.OrderId,
@@ -761,45 +806,54 @@ func (t *BitgetTrader) GetMarketPrice(symbol string) (float64, error) {
 // SetStopLoss sets stop loss order
 func (t *BitgetTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
 	// Bitget V2 uses plan order for stop loss
-	symbol = t.convertSymbol(symbol)
+	if err := t.placeExitPlanOrder(symbol, positionSide, "loss_plan", quantity, stopPrice, 0); err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
 
-	side := "sell"
-	holdSide := "long"
-	if strings.ToUpper(positionSide) == "SHORT" {
-		side = "buy"
-		holdSide = "short"
+	t.log().Infof("  ✓ [Bitget] Stop loss set: %s @ %.4f", t.convertSymbol(symbol), stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order
+func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	// Bitget V2 uses plan order for take profit
+	if err := t.placeExitPlanOrder(symbol, positionSide, "profit_plan", quantity, takeProfitPrice, 0); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
 	}
 
-	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+	t.log().Infof("  ✓ [Bitget] Take profit set: %s @ %.4f", t.convertSymbol(symbol), takeProfitPrice)
+	return nil
+}
 
-	body := map[string]interface{}{
-		"planType":     "loss_plan",
-		"symbol":       symbol,
-		"productType":  "USDT-FUTURES",
-		"marginMode":   "crossed",
-		"marginCoin":   "USDT",
-		"triggerPrice": fmt.Sprintf("%.8f", stopPrice),
-		"triggerType":  "mark_price",
-		"side":         side,
-		"tradeSide":    "close",
-		"orderType":    "market",
-		"size":         qtyStr,
-		"holdSide":     holdSide,
-		"clientOid":    genBitgetClientOid(),
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a profit plan order for size on the held side
+func (t *BitgetTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// SetStopLossLimit sets a stop-limit plan order: a limit order at limitPrice is placed when stopPrice triggers
+func (t *BitgetTrader) SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error {
+	if err := t.placeExitPlanOrder(symbol, positionSide, "loss_plan", quantity, stopPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set stop-limit: %w", err)
 	}
 
-	_, err := t.doRequest("POST", "/api/v2/mix/order/place-plan-order", body)
-	if err != nil {
-		return fmt.Errorf("failed to set stop loss: %w", err)
+	t.log().Infof("  ✓ [Bitget] Stop-limit set: %s trigger %.4f, limit %.4f", t.convertSymbol(symbol), stopPrice, limitPrice)
+	return nil
+}
+
+// SetTakeProfitLimit sets a take-profit-limit plan order: a limit order at limitPrice is placed when takeProfitPrice triggers
+func (t *BitgetTrader) SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error {
+	if err := t.placeExitPlanOrder(symbol, positionSide, "profit_plan", quantity, takeProfitPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set take-profit-limit: %w", err)
 	}
 
-	logger.Infof("  ✓ [Bitget] Stop loss set: %s @ %.4f", symbol, stopPrice)
+	t.log().Infof("  ✓ [Bitget] Take-profit-limit set: %s trigger %.4f, limit %.4f", t.convertSymbol(symbol), takeProfitPrice, limitPrice)
 	return nil
 }
 
-// SetTakeProfit sets take profit order
-func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
-	// Bitget V2 uses plan order for take profit
+// placeExitPlanOrder places a plan order closing the position
+// planType: "loss_plan" or "profit_plan"; limitPrice 0 = market order on trigger
+func (t *BitgetTrader) placeExitPlanOrder(symbol, positionSide, planType string, quantity, triggerPrice, limitPrice float64) error {
 	symbol = t.convertSymbol(symbol)
 
 	side := "sell"
@@ -812,12 +866,12 @@ func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantit
 	qtyStr, _ := t.FormatQuantity(symbol, quantity)
 
 	body := map[string]interface{}{
-		"planType":     "profit_plan",
+		"planType":     planType,
 		"symbol":       symbol,
 		"productType":  "USDT-FUTURES",
 		"marginMode":   "crossed",
 		"marginCoin":   "USDT",
-		"triggerPrice": fmt.Sprintf("%.8f", takeProfitPrice),
+		"triggerPrice": fmt.Sprintf("%.8f", triggerPrice),
 		"triggerType":  "mark_price",
 		"side":         side,
 		"tradeSide":    "close",
@@ -827,13 +881,17 @@ func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantit
 		"clientOid":    genBitgetClientOid(),
 	}
 
-	_, err := t.doRequest("POST", "/api/v2/mix/order/place-plan-order", body)
-	if err != nil {
-		return fmt.Errorf("failed to set take profit: %w", err)
+	if limitPrice > 0 {
+		tickSize := 0.0
+		if contract, err := t.getContract(symbol); err == nil {
+			tickSize = math.Pow10(-contract.PricePlace)
+		}
+		body["orderType"] = "limit"
+		body["price"] = formatLimitPrice(limitPrice, tickSize, side == "buy")
 	}
 
-	logger.Infof("  ✓ [Bitget] Take profit set: %s @ %.4f", symbol, takeProfitPrice)
-	return nil
+	_, err := t.doRequest("POST", "/api/v2/mix/order/place-plan-order", body)
+	return err
 }
 
 // CancelStopLossOrders cancels stop loss orders
@@ -886,6 +944,26 @@ func (t *BitgetTrader) cancelPlanOrders(symbol string, planType string) error {
 	return nil
 }
 
+// CancelOrder cancels a single pending order by order ID, falling back to the plan
+// order endpoint for stop-loss/take-profit orders
+func (t *BitgetTrader) CancelOrder(symbol string, orderID string) error {
+	body := map[string]interface{}{
+		"symbol":      t.convertSymbol(symbol),
+		"productType": "USDT-FUTURES",
+		"marginCoin":  "USDT",
+		"orderId":     orderID,
+	}
+
+	_, err := t.doRequest("POST", bitgetCancelOrderPath, body)
+	if err == nil {
+		return nil
+	}
+	if _, planErr := t.doRequest("POST", "/api/v2/mix/order/cancel-plan-order", body); planErr != nil {
+		return fmt.Errorf("failed to cancel order %s: %v (plan: %v)", orderID, err, planErr)
+	}
+	return nil
+}
+
 // CancelAllOrders cancels all pending orders
 func (t *BitgetTrader) CancelAllOrders(symbol string) error {
 	symbol = t.convertSymbol(symbol)
@@ -0,0 +1,78 @@
+package trader
+
+import "nofx/kernel"
+
+// BracketOrderOpener is implemented by exchanges that can attach stop loss / take profit to the entry order.
+// The exits are placed atomically with the open, so a crash between opening and protecting the position
+// can't leave it without a stop. A zero stopLoss / takeProfit means "don't attach".
+type BracketOrderOpener interface {
+	OpenLongWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error)
+	OpenShortWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error)
+}
+
+// bracketExits returns the stop loss and take profit to attach to the entry order.
+// A take profit ladder can't be expressed as a single attached order, so only the stop loss is
+// attached then and the ladder is placed after the open as usual.
+func bracketExits(decision *kernel.Decision) (stopLoss, takeProfit float64) {
+	if decision.StopLoss > 0 {
+		stopLoss = decision.StopLoss
+	}
+	if len(decision.TakeProfitLevels) == 0 && decision.TakeProfit > 0 {
+		takeProfit = decision.TakeProfit
+	}
+	return stopLoss, takeProfit
+}
+
+// openPosition opens a position, attaching SL/TP to the entry order when risk_control.attach_sltp_to_entry
+// is enabled and the exchange supports it. attachedSL / attachedTP report which exits were placed with
+// the entry and must not be placed again.
+func (at *AutoTrader) openPosition(decision *kernel.Decision, positionSide string, quantity float64) (order map[string]interface{}, attachedSL, attachedTP bool, err error) {
+	open := at.trader.OpenLong
+	if positionSide == "SHORT" {
+		open = at.trader.OpenShort
+	}
+
+	// Post-only entries rest on the book, their exits are placed by the maker entry watcher once filled
+	if decision.PostOnly && !at.postOnlyEntriesEnabled() {
+		at.log().Infof("  ⚠ Post-only entry requested but risk_control.post_only_entries is off, opening with a market order")
+	} else if decision.PostOnly {
+		if opener, ok := at.trader.(PostOnlyOpener); ok {
+			order, err = at.openPostOnly(opener, decision, positionSide, quantity)
+			return order, false, false, err
+		}
+		if _, mirrored := at.trader.(*MirrorTrader); mirrored {
+			at.log().Infof("  ⚠ Post-only entries aren't mirrored (a resting entry is tracked on one exchange), opening with a market order")
+		} else {
+			at.log().Infof("  ⚠ %s does not support post-only entries, opening with a market order", at.exchange)
+		}
+	}
+
+	if at.config.StrategyConfig == nil || !at.config.StrategyConfig.RiskControl.AttachSLTPToEntry {
+		order, err = open(decision.Symbol, quantity, decision.Leverage)
+		return order, false, false, err
+	}
+
+	opener, ok := capableTrader[BracketOrderOpener](at.trader)
+	if !ok {
+		at.log().Infof("  ⚠ %s does not support SL/TP attached to the entry order, placing them after the open", at.exchange)
+		order, err = open(decision.Symbol, quantity, decision.Leverage)
+		return order, false, false, err
+	}
+	if orderType, _ := at.exitOrderSettings(); orderType == ExitOrderLimit {
+		at.log().Infof("  ⚠ Limit exits can't be attached to the entry order, placing them after the open")
+		order, err = open(decision.Symbol, quantity, decision.Leverage)
+		return order, false, false, err
+	}
+
+	stopLoss, takeProfit := bracketExits(decision)
+	openBracket := opener.OpenLongWithBracket
+	if positionSide == "SHORT" {
+		openBracket = opener.OpenShortWithBracket
+	}
+	order, err = openBracket(decision.Symbol, quantity, decision.Leverage, stopLoss, takeProfit)
+	if err != nil {
+		return nil, false, false, err
+	}
+	at.log().Infof("  ✓ Stop loss %.4f / take profit %.4f attached to the entry order", stopLoss, takeProfit)
+	return order, stopLoss > 0, takeProfit > 0, nil
+}
@@ -0,0 +1,28 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/kernel"
+)
+
+func TestBracketExits(t *testing.T) {
+	sl, tp := bracketExits(&kernel.Decision{StopLoss: 95, TakeProfit: 110})
+	if sl != 95 || tp != 110 {
+		t.Errorf("single take profit: got sl=%v tp=%v, want 95/110", sl, tp)
+	}
+
+	// A ladder is placed after the open, only the stop loss is attached
+	sl, tp = bracketExits(&kernel.Decision{
+		StopLoss:         95,
+		TakeProfit:       110,
+		TakeProfitLevels: []kernel.TakeProfitLevel{{Price: 105, Percent: 50}, {Price: 110, Percent: 50}},
+	})
+	if sl != 95 || tp != 0 {
+		t.Errorf("ladder: got sl=%v tp=%v, want 95/0", sl, tp)
+	}
+
+	if sl, tp = bracketExits(&kernel.Decision{}); sl != 0 || tp != 0 {
+		t.Errorf("no exits: got sl=%v tp=%v", sl, tp)
+	}
+}
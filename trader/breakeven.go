@@ -0,0 +1,118 @@
+package trader
+
+import (
+	"strings"
+)
+
+// breakevenFeeRate assumed taker fee per side; the breakeven stop covers the round trip
+const breakevenFeeRate = 0.0005
+
+// breakevenStopPrice returns the stop price at which closing the position roughly breaks even after fees
+func breakevenStopPrice(side string, entryPrice float64) float64 {
+	if side == "long" {
+		return entryPrice * (1 + 2*breakevenFeeRate)
+	}
+	return entryPrice * (1 - 2*breakevenFeeRate)
+}
+
+// stopProtectsBreakeven reports whether an existing stop at stopPrice is already at or beyond break-even
+func stopProtectsBreakeven(side string, stopPrice, breakeven float64) bool {
+	if side == "long" {
+		return stopPrice >= breakeven
+	}
+	return stopPrice <= breakeven
+}
+
+// breakevenTriggerPct returns risk_control.breakeven_trigger_pct (0 = disabled)
+func (at *AutoTrader) breakevenTriggerPct() float64 {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.BreakevenTriggerPct
+}
+
+// moveStopToBreakeven moves the stop loss of a position to entry (+ fees) the first time its P&L
+// reaches the breakeven trigger. Called by the drawdown monitor; each position (symbol, side, entry price)
+// is handled once, so the stop is not re-issued every check.
+func (at *AutoTrader) moveStopToBreakeven(symbol, side string, entryPrice, markPrice, quantity, currentPnLPct float64) {
+	trigger := at.breakevenTriggerPct()
+	if trigger <= 0 || currentPnLPct < trigger || entryPrice <= 0 || quantity <= 0 {
+		return
+	}
+
+	posKey := symbol + "_" + side
+	at.breakevenMu.Lock()
+	movedAt, moved := at.breakevenMoved[posKey]
+	at.breakevenMu.Unlock()
+	if moved && movedAt == entryPrice {
+		return
+	}
+
+	stopPrice := breakevenStopPrice(side, entryPrice)
+	// The stop must stay on the losing side of the mark price or it would trigger immediately
+	if (side == "long" && markPrice <= stopPrice) || (side == "short" && markPrice >= stopPrice) {
+		return
+	}
+
+	// Only this position side's stops are replaced (hedge mode keeps the other side's stop);
+	// without the open orders the move is retried on the next check
+	positionSide := strings.ToUpper(side)
+	orders, err := at.trader.GetOpenOrders(symbol)
+	if err != nil {
+		at.log().Infof("⚠️ Breakeven: failed to get open orders for %s %s: %v", symbol, side, err)
+		return
+	}
+	var currentStops []OpenOrder
+	for _, o := range orders {
+		if protectiveOrderKind(o, positionSide) == "stop_loss" {
+			currentStops = append(currentStops, o)
+		}
+	}
+	// Never loosen a stop that is already tighter than break-even (e.g. trailed by the AI)
+	for _, o := range currentStops {
+		if stopProtectsBreakeven(side, o.StopPrice, stopPrice) {
+			at.markBreakevenMoved(posKey, entryPrice)
+			return
+		}
+	}
+
+	at.log().Infof("🛡️ Breakeven: %s %s profit %.2f%% ≥ %.2f%%, moving stop loss to %.4f (entry %.4f + fees)",
+		symbol, side, currentPnLPct, trigger, stopPrice, entryPrice)
+
+	for _, o := range currentStops {
+		if err := at.trader.CancelOrder(symbol, o.OrderID); err != nil {
+			at.log().Infof("⚠️ Breakeven: failed to cancel stop loss %s for %s %s: %v", o.OrderID, symbol, side, err)
+			return
+		}
+	}
+	if _, err := at.placeStopLoss(symbol, positionSide, quantity, stopPrice); err != nil {
+		at.log().Infof("❌ Breakeven: failed to set stop loss for %s %s: %v, restoring previous stop", symbol, side, err)
+		for _, o := range currentStops {
+			if _, err := at.placeStopLoss(symbol, positionSide, quantity, o.StopPrice); err != nil {
+				at.log().Infof("❌ Breakeven: failed to restore stop loss @ %.4f: %v", o.StopPrice, err)
+			}
+		}
+		return
+	}
+
+	at.markBreakevenMoved(posKey, entryPrice)
+	at.log().Infof("✅ Breakeven: %s %s stop loss moved to %.4f", symbol, side, stopPrice)
+}
+
+// markBreakevenMoved records that the stop of a position was moved (or already protects) break-even
+func (at *AutoTrader) markBreakevenMoved(posKey string, entryPrice float64) {
+	at.breakevenMu.Lock()
+	defer at.breakevenMu.Unlock()
+	at.breakevenMoved[posKey] = entryPrice
+}
+
+// pruneBreakevenState forgets positions that are no longer open
+func (at *AutoTrader) pruneBreakevenState(openPositions map[string]bool) {
+	at.breakevenMu.Lock()
+	defer at.breakevenMu.Unlock()
+	for posKey := range at.breakevenMoved {
+		if !openPositions[posKey] {
+			delete(at.breakevenMoved, posKey)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"math"
+	"testing"
+
+	"nofx/store"
+)
+
+// fakeBreakevenTrader records stop losses placed over a fixed set of open orders
+type fakeBreakevenTrader struct {
+	fakeOrderBookTrader
+	stops []float64
+}
+
+func (f *fakeBreakevenTrader) SetStopLoss(symbol, positionSide string, quantity, stopPrice float64) error {
+	f.stops = append(f.stops, stopPrice)
+	return nil
+}
+
+func TestBreakevenStopPrice(t *testing.T) {
+	if got := breakevenStopPrice("long", 100); math.Abs(got-100.1) > 1e-9 {
+		t.Errorf("long breakeven = %v, want 100.1", got)
+	}
+	if got := breakevenStopPrice("short", 100); math.Abs(got-99.9) > 1e-9 {
+		t.Errorf("short breakeven = %v, want 99.9", got)
+	}
+}
+
+func TestStopProtectsBreakeven(t *testing.T) {
+	cases := []struct {
+		side      string
+		stop      float64
+		breakeven float64
+		want      bool
+	}{
+		{"long", 95, 100.1, false},
+		{"long", 100.1, 100.1, true},
+		{"long", 103, 100.1, true},
+		{"short", 105, 99.9, false},
+		{"short", 99.9, 99.9, true},
+		{"short", 97, 99.9, true},
+	}
+	for _, c := range cases {
+		if got := stopProtectsBreakeven(c.side, c.stop, c.breakeven); got != c.want {
+			t.Errorf("stopProtectsBreakeven(%q, %v, %v) = %v, want %v", c.side, c.stop, c.breakeven, got, c.want)
+		}
+	}
+}
+
+func TestPruneBreakevenState(t *testing.T) {
+	at := &AutoTrader{breakevenMoved: map[string]float64{"BTCUSDT_long": 100, "ETHUSDT_short": 2000}}
+	at.pruneBreakevenState(map[string]bool{"BTCUSDT_long": true})
+	if _, ok := at.breakevenMoved["ETHUSDT_short"]; ok {
+		t.Error("closed position should be forgotten")
+	}
+	if at.breakevenMoved["BTCUSDT_long"] != 100 {
+		t.Error("open position should be kept")
+	}
+}
+
+func TestMoveStopToBreakevenHedgeMode(t *testing.T) {
+	ft := &fakeBreakevenTrader{fakeOrderBookTrader: fakeOrderBookTrader{orders: []OpenOrder{
+		{OrderID: "sl-long", Side: "SELL", PositionSide: "LONG", Type: "STOP_MARKET", StopPrice: 95},
+		{OrderID: "tp-long", Side: "SELL", PositionSide: "LONG", Type: "TAKE_PROFIT_MARKET", StopPrice: 120},
+		{OrderID: "sl-short", Side: "BUY", PositionSide: "SHORT", Type: "STOP_MARKET", StopPrice: 110},
+	}}}
+	cfg := &store.StrategyConfig{}
+	cfg.RiskControl.BreakevenTriggerPct = 2
+	at := &AutoTrader{
+		trader:         ft,
+		config:         AutoTraderConfig{StrategyConfig: cfg},
+		breakevenMoved: make(map[string]float64),
+	}
+
+	at.moveStopToBreakeven("BTCUSDT", "long", 100, 105, 1, 5)
+
+	if len(ft.cancelled) != 1 || ft.cancelled[0] != "sl-long" {
+		t.Errorf("cancelled %v, want only the long stop", ft.cancelled)
+	}
+	if len(ft.stops) != 1 || math.Abs(ft.stops[0]-100.1) > 1e-9 {
+		t.Errorf("stops placed %v, want one at 100.1", ft.stops)
+	}
+	if at.breakevenMoved["BTCUSDT_long"] != 100 {
+		t.Error("move not recorded")
+	}
+}
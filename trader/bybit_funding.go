@@ -0,0 +1,94 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// bybitFundingLogMaxPages max transaction log pages read per funding import
+const bybitFundingLogMaxPages = 20
+
+// GetFundingPayments returns the funding settlements of the given symbols from the transaction log.
+// Like transfers, only the last 7 days are covered.
+func (t *BybitTrader) GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error) {
+	now := t.clock.Now()
+	if earliest := now.Add(-bybitTransactionLogWindow).Add(time.Minute); since.Before(earliest) {
+		since = earliest
+	}
+	held := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		held[s] = true
+	}
+
+	var payments []FundingPayment
+	cursor := ""
+	for page := 0; page < bybitFundingLogMaxPages; page++ {
+		query := fmt.Sprintf("accountType=UNIFIED&category=linear&type=SETTLEMENT&startTime=%d&endTime=%d&limit=50",
+			since.UnixMilli(), now.UnixMilli())
+		if cursor != "" {
+			query += "&cursor=" + cursor
+		}
+		body, err := t.transactionLogRequest(query)
+		if err != nil {
+			return nil, err
+		}
+		entries, next, err := parseBybitFundingLog(body)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range entries {
+			if held[p.Symbol] {
+				payments = append(payments, p)
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return payments, nil
+}
+
+// parseBybitFundingLog parses SETTLEMENT transaction log entries into funding payments and returns
+// the next page cursor. Bybit reports funding as a fee (positive = paid), so the sign is flipped.
+func parseBybitFundingLog(body []byte) ([]FundingPayment, string, error) {
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			NextPageCursor string `json:"nextPageCursor"`
+			List           []struct {
+				ID              string `json:"id"`
+				Symbol          string `json:"symbol"`
+				Currency        string `json:"currency"`
+				Funding         string `json:"funding"`
+				TransactionTime string `json:"transactionTime"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, "", fmt.Errorf("Bybit API error: %s", result.RetMsg)
+	}
+
+	payments := make([]FundingPayment, 0, len(result.Result.List))
+	for _, entry := range result.Result.List {
+		fee, _ := strconv.ParseFloat(entry.Funding, 64)
+		if fee == 0 {
+			continue // Settlement without funding (e.g. USDC session PnL only)
+		}
+		ms, _ := strconv.ParseInt(entry.TransactionTime, 10, 64)
+		payments = append(payments, FundingPayment{
+			ID:     entry.ID,
+			Symbol: entry.Symbol,
+			Asset:  entry.Currency,
+			Amount: -fee,
+			Time:   time.UnixMilli(ms).UTC(),
+		})
+	}
+	return payments, result.Result.NextPageCursor, nil
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -26,6 +25,7 @@ type BybitTrade struct {
 	ExecPrice   float64
 	ExecQty     float64
 	ExecFee     float64
+	FeeAsset    string // Asset the fee was charged in (the contract's settle coin)
 	ExecTime    time.Time
 	IsMaker     bool
 	OrderType   string
@@ -43,11 +43,11 @@ func (t *BybitTrader) GetTrades(startTime time.Time, limit int) ([]BybitTrade, e
 func (t *BybitTrader) getTradesViaHTTP(startTime time.Time, limit int) ([]BybitTrade, error) {
 	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/execution/list?" + queryParams
+	url := t.baseURL + "/v5/execution/list?" + queryParams
 
 	// Generate timestamp
-	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
-	recvWindow := "5000"
+	timestamp := fmt.Sprintf("%d", t.clock.Now().UnixMilli())
+	recvWindow := strconv.FormatInt(recvWindowOr(bybitDefaultRecvWindow), 10)
 
 	// Build signature payload: timestamp + api_key + recv_window + queryString
 	signPayload := timestamp + t.apiKey + recvWindow + queryParams
@@ -71,8 +71,8 @@ func (t *BybitTrader) getTradesViaHTTP(startTime time.Time, limit int) ([]BybitT
 	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use http.DefaultClient for the request
-	resp, err := http.DefaultClient.Do(req)
+	// Sent through the SDK client, which re-signs with exchange time and the next pooled key
+	resp, err := t.client.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
 	}
@@ -120,6 +120,7 @@ func (t *BybitTrader) parseTradesResult(list []map[string]interface{}) ([]BybitT
 		closedSizeStr, _ := item["closedSize"].(string)
 		closedPnlStr, _ := item["closedPnl"].(string)
 		execTimeStr, _ := item["execTime"].(string)
+		feeAsset, _ := item["feeCurrency"].(string)
 
 		execPrice, _ := strconv.ParseFloat(execPriceStr, 64)
 		execQty, _ := strconv.ParseFloat(execQtyStr, 64)
@@ -128,6 +129,17 @@ func (t *BybitTrader) parseTradesResult(list []map[string]interface{}) ([]BybitT
 		closedPnl, _ := strconv.ParseFloat(closedPnlStr, 64)
 		execTimeMs, _ := strconv.ParseInt(execTimeStr, 10, 64)
 		execTime := time.UnixMilli(execTimeMs).UTC()
+		if feeAsset == "" {
+			// Linear contracts charge fees in their settle coin: USDC for *PERP contracts, else the symbol's quote
+			switch {
+			case strings.HasSuffix(symbol, "PERP"):
+				feeAsset = market.QuoteUSDC
+			case market.QuoteAsset(symbol) != "":
+				feeAsset = market.QuoteAsset(symbol)
+			default:
+				feeAsset = t.settleAsset()
+			}
+		}
 
 		// Determine order action based on side and closedSize
 		// If closedSize > 0, it's a close trade
@@ -157,6 +169,7 @@ func (t *BybitTrader) parseTradesResult(list []map[string]interface{}) ([]BybitT
 			ExecPrice:   execPrice,
 			ExecQty:     execQty,
 			ExecFee:     execFee,
+			FeeAsset:    feeAsset,
 			ExecTime:    execTime,
 			IsMaker:     isMaker,
 			OrderType:   orderType,
@@ -183,7 +196,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Bybit trades from: %s", startTime.Format(time.RFC3339))
+	t.log().Infof("🔄 Syncing Bybit trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 1000)
@@ -191,7 +204,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Bybit", len(trades))
+	t.log().Infof("📥 Received %d trades from Bybit", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -202,6 +215,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 	syncedCount := 0
 
 	for _, trade := range trades {
@@ -223,6 +237,9 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 		// Normalize side for storage
 		side := strings.ToUpper(trade.Side)
 
+		// Fees charged in another asset than the quote currency are valued in it
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.ExecFee, trade.ExecPrice)
+
 		// Create order record - use UTC time in milliseconds to avoid timezone issues
 		execTimeMs := trade.ExecTime.UTC().UnixMilli()
 		orderRecord := &store.TraderOrder{
@@ -230,6 +247,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 			ExchangeID:      exchangeID,   // UUID
 			ExchangeType:    exchangeType, // Exchange type
 			ExchangeOrderID: trade.ExecID, // Use ExecID as unique identifier
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			Symbol:          symbol,
 			Side:            side,
 			PositionSide:    "BOTH", // Bybit uses one-way position mode
@@ -240,7 +258,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 			Status:          "FILLED",
 			FilledQuantity:  trade.ExecQty,
 			AvgFillPrice:    trade.ExecPrice,
-			Commission:      trade.ExecFee,
+			Commission:      feeUSDT,
 			FilledAt:        execTimeMs,
 			CreatedAt:       execTimeMs,
 			UpdatedAt:       execTimeMs,
@@ -248,7 +266,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.ExecID, err)
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.ExecID, err)
 			continue
 		}
 
@@ -259,6 +277,7 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 			ExchangeType:    exchangeType, // Exchange type
 			OrderID:         orderRecord.ID,
 			ExchangeOrderID: trade.OrderID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			ExchangeTradeID: trade.ExecID,
 			Symbol:          symbol,
 			Side:            side,
@@ -266,46 +285,39 @@ func (t *BybitTrader) SyncOrdersFromBybit(traderID string, exchangeID string, ex
 			Quantity:        trade.ExecQty,
 			QuoteQuantity:   trade.ExecPrice * trade.ExecQty,
 			Commission:      trade.ExecFee,
-			CommissionAsset: "USDT",
+			CommissionAsset: commissionAsset(trade.FeeAsset),
+			CommissionUSDT:  feeUSDT,
 			RealizedPnL:     trade.ClosedPnL,
 			IsMaker:         trade.IsMaker,
 			CreatedAt:       execTimeMs,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.ExecID, err)
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.ExecID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			symbol, positionSide, trade.OrderAction,
-			trade.ExecQty, trade.ExecPrice, trade.ExecFee, trade.ClosedPnL,
+			trade.ExecQty, trade.ExecPrice, feeUSDT, trade.ClosedPnL,
 			execTimeMs, trade.ExecID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.ExecID, err)
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.ExecID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.ExecID, trade.OrderAction, trade.ExecQty)
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.ExecID, trade.OrderAction, trade.ExecQty)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.ExecID, symbol, side, trade.ExecQty, trade.ExecPrice, trade.ClosedPnL, trade.ExecFee, trade.OrderAction)
 	}
 
-	logger.Infof("✅ Bybit order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Bybit order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
-// StartOrderSync starts background order sync task for Bybit
-func (t *BybitTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromBybit(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Infof("⚠️  Bybit order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Bybit order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Bybit
+func (t *BybitTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromBybit(traderID, exchangeID, exchangeType, st)
 }
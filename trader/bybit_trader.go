@@ -1,6 +1,7 @@
 package trader
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
@@ -10,7 +11,9 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"nofx/kernel"
 	"nofx/logger"
+	"nofx/store"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,11 +22,25 @@ import (
 	bybit "github.com/bybit-exchange/bybit.go.api"
 )
 
+// bybitDefaultRecvWindow recvWindow (ms) of hand-signed requests when none is configured
+const bybitDefaultRecvWindow = 5000
+
 // BybitTrader Bybit USDT Perpetual Futures Trader
 type BybitTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	client    *bybit.Client
+	signer    *headerRoundTripper // Transport of client, signs every private request
 	apiKey    string
 	secretKey string
+	baseURL   string // REST base URL (mainnet or testnet)
+	clock     *ClockSync
+
+	// Final order states pushed by the private order stream
+	orderHub *orderUpdateHub
+
+	// Settle coin of the traded linear contracts ("" = USDT)
+	settleCoin string
 
 	// Balance cache
 	cachedBalance     map[string]interface{}
@@ -44,46 +61,137 @@ type BybitTrader struct {
 }
 
 // NewBybitTrader creates a Bybit trader
-func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
+func NewBybitTrader(apiKey, secretKey string, testnet bool) *BybitTrader {
 	const src = "Up000938"
 
-	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(bybit.MAINNET))
-
-	// Set HTTP transport
-	if client != nil && client.HTTPClient != nil {
-		defaultTransport := client.HTTPClient.Transport
-		if defaultTransport == nil {
-			defaultTransport = http.DefaultTransport
-		}
+	baseURL := bybit.MAINNET
+	if testnet {
+		baseURL = bybit.TESTNET
+	}
+	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(baseURL))
+	clock := NewClockSync("Bybit", httpServerTimeFetcher(nil, baseURL+"/v5/market/time", parseBybitServerTime))
 
-		client.HTTPClient.Transport = &headerRoundTripper{
-			base:      defaultTransport,
-			refererID: src,
-		}
+	// Own HTTP client: the SDK defaults to http.DefaultClient, whose transport would be shared by
+	// every Bybit trader (and their keys)
+	signer := &headerRoundTripper{
+		base:      http.DefaultTransport,
+		refererID: src,
+		clock:     clock,
+		apiKey:    apiKey,
+		secretKey: secretKey,
+	}
+	if client != nil {
+		client.HTTPClient = &http.Client{Transport: signer}
 	}
 
 	trader := &BybitTrader{
 		client:        client,
+		signer:        signer,
 		apiKey:        apiKey,
 		secretKey:     secretKey,
+		baseURL:       baseURL,
+		clock:         clock,
+		orderHub:      newOrderUpdateHub(),
 		cacheDuration: 15 * time.Second,
 		qtyStepCache:  make(map[string]float64),
 	}
 
-	logger.Infof("🔵 [Bybit] Trader initialized")
+	if testnet {
+		logger.Infof("🔵 [Bybit] Trader initialized (testnet)")
+	} else {
+		logger.Infof("🔵 [Bybit] Trader initialized")
+	}
 
 	return trader
 }
 
 // headerRoundTripper HTTP RoundTripper for adding custom headers
+// and re-signing private requests with exchange time (the SDK always signs with local time)
+// and the next key of the pool (nil = primary key only)
 type headerRoundTripper struct {
 	base      http.RoundTripper
 	refererID string
+	clock     *ClockSync
+	apiKey    string
+	secretKey string
+	keys      *APIKeyPool
 }
 
 func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Referer", h.refererID)
-	return h.base.RoundTrip(req)
+	if h.clock == nil || req.Header.Get("X-BAPI-TIMESTAMP") == "" {
+		return h.base.RoundTrip(req)
+	}
+	key, cred := h.keys.Next(store.APIKeyCredential{APIKey: h.apiKey, SecretKey: h.secretKey})
+	resigned, err := h.resign(req, cred)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.base.RoundTrip(resigned)
+	if err == nil {
+		h.keys.ObserveResponse(key, resp)
+	}
+	return resp, err
+}
+
+// resign replaces the signed timestamp with exchange time and recomputes the V5 signature with cred:
+// HMAC(timestamp + apiKey + recvWindow + (body for POST | query string for GET))
+func (h *headerRoundTripper) resign(req *http.Request, cred store.APIKeyCredential) (*http.Request, error) {
+	payload := req.URL.RawQuery
+	var body []byte
+	if req.Method == http.MethodPost && req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+		payload = string(body)
+	}
+
+	timestamp := strconv.FormatInt(h.clock.Now().UnixMilli(), 10)
+	recvWindow := req.Header.Get("X-BAPI-RECV-WINDOW")
+	if ms := recvWindowOr(0); ms > 0 {
+		recvWindow = strconv.FormatInt(ms, 10)
+	}
+	mac := hmac.New(sha256.New, []byte(cred.SecretKey))
+	mac.Write([]byte(timestamp + cred.APIKey + recvWindow + payload))
+
+	out := req.Clone(req.Context())
+	out.Header.Set("X-BAPI-API-KEY", cred.APIKey)
+	out.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	if recvWindow != "" {
+		out.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	}
+	out.Header.Set("X-BAPI-SIGN", hex.EncodeToString(mac.Sum(nil)))
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+	}
+	return out, nil
+}
+
+// AddAPIKeys rotates private requests across the primary and the additional API keys (MultiKeyTrader)
+func (t *BybitTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	if t.signer == nil {
+		return
+	}
+	t.signer.keys = NewAPIKeyPool(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey}, keys)
+}
+
+// parseBybitServerTime parses /v5/market/time: {"retCode":0,"time":1688639403423}
+func parseBybitServerTime(body []byte) (int64, error) {
+	var resp struct {
+		RetCode int   `json:"retCode"`
+		Time    int64 `json:"time"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, err
+	}
+	if resp.RetCode != 0 || resp.Time == 0 {
+		return 0, fmt.Errorf("invalid Bybit server time response: %s", string(body))
+	}
+	return resp.Time, nil
 }
 
 // GetBalance retrieves account balance
@@ -161,6 +269,19 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 	return balance, nil
 }
 
+// SetQuoteAsset lists positions of quote-settled (USDC) linear contracts (implements QuoteAssetConfigurable)
+func (t *BybitTrader) SetQuoteAsset(quote string) {
+	t.settleCoin = quote
+}
+
+// settleAsset returns the settle coin positions are listed in
+func (t *BybitTrader) settleAsset() string {
+	if t.settleCoin == "" {
+		return "USDT"
+	}
+	return t.settleCoin
+}
+
 // GetPositions retrieves all positions
 func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 	// Check cache
@@ -175,7 +296,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 	// Call API
 	params := map[string]interface{}{
 		"category":   "linear",
-		"settleCoin": "USDT",
+		"settleCoin": t.settleAsset(),
 	}
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).GetPositionList(context.Background())
@@ -236,7 +357,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 		positionSide, _ := pos["side"].(string) // Buy = long, Sell = short
 
 		// Log raw position data for debugging
-		logger.Infof("[Bybit] GetPositions raw: symbol=%v, side=%s, size=%v", pos["symbol"], positionSide, sizeStr)
+		t.log().Infof("[Bybit] GetPositions raw: symbol=%v, side=%s, size=%v", pos["symbol"], positionSide, sizeStr)
 
 		// Convert to unified format (use lowercase for consistency with other exchanges)
 		// Bybit returns "Buy" for long, "Sell" for short
@@ -248,7 +369,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 			positionAmt = -size
 		}
 
-		logger.Infof("[Bybit] GetPositions converted: symbol=%v, rawSide=%s -> side=%s", pos["symbol"], positionSide, side)
+		t.log().Infof("[Bybit] GetPositions converted: symbol=%v, rawSide=%s -> side=%s", pos["symbol"], positionSide, side)
 
 		position := map[string]interface{}{
 			"symbol":           pos["symbol"],
@@ -278,63 +399,57 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 
 // OpenLong opens a long position
 func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenLong called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
-
-	// First cancel all pending orders for this symbol (clean up old orders)
-	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
-	}
-	// Also cancel conditional orders (stop-loss/take-profit) - Bybit keeps them separate
-	if err := t.CancelStopOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
-	}
-
-	// Set leverage first
-	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
-	}
-
-	// Use FormatQuantity to format quantity
-	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+	return t.openPosition(symbol, "Buy", quantity, leverage, 0, 0, 0)
+}
 
-	params := map[string]interface{}{
-		"category":    "linear",
-		"symbol":      symbol,
-		"side":        "Buy",
-		"orderType":   "Market",
-		"qty":         qtyStr,
-		"positionIdx": 0, // One-way position mode
-	}
+// OpenShort opens a short position
+func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Sell", quantity, leverage, 0, 0, 0)
+}
 
-	logger.Infof("[Bybit] OpenLong placing order: %+v", params)
+// OpenLongWithBracket opens a long position with stop loss / take profit attached to the entry order
+func (t *BybitTrader) OpenLongWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Buy", quantity, leverage, stopLoss, takeProfit, 0)
+}
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("Bybit open long failed: %w", err)
-	}
+// OpenShortWithBracket opens a short position with stop loss / take profit attached to the entry order
+func (t *BybitTrader) OpenShortWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Sell", quantity, leverage, stopLoss, takeProfit, 0)
+}
 
-	// Clear cache
-	t.clearCache()
+// OpenLongPostOnly opens a long position with a post-only limit buy at price
+func (t *BybitTrader) OpenLongPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Buy", quantity, leverage, 0, 0, price)
+}
 
-	return t.parseOrderResult(result)
+// OpenShortPostOnly opens a short position with a post-only limit sell at price
+func (t *BybitTrader) OpenShortPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Sell", quantity, leverage, 0, 0, price)
 }
 
-// OpenShort opens a short position
-func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenShort called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
+// openPosition places a market entry order (side "Buy" = long, "Sell" = short), or a post-only
+// limit at postOnlyPrice when it is non-zero (Bybit cancels it instead of letting it cross the book).
+// Non-zero stopLoss / takeProfit are attached to the order in Partial mode, so they cover exactly
+// the opened quantity and show up as cancellable tpsl orders.
+func (t *BybitTrader) openPosition(symbol, side string, quantity float64, leverage int, stopLoss, takeProfit, postOnlyPrice float64) (map[string]interface{}, error) {
+	direction := "long"
+	if side == "Sell" {
+		direction = "short"
+	}
+	t.log().Infof("[Bybit] ===== Open %s called: symbol=%s, qty=%.6f, leverage=%d =====", direction, symbol, quantity, leverage)
 
 	// First cancel all pending orders for this symbol (clean up old orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
+		t.log().Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
 	}
 	// Also cancel conditional orders (stop-loss/take-profit) - Bybit keeps them separate
 	if err := t.CancelStopOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
+		t.log().Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
 	}
 
 	// Set leverage first
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
+		t.log().Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
 	}
 
 	// Use FormatQuantity to format quantity
@@ -343,17 +458,35 @@ func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	params := map[string]interface{}{
 		"category":    "linear",
 		"symbol":      symbol,
-		"side":        "Sell",
+		"side":        side,
 		"orderType":   "Market",
 		"qty":         qtyStr,
 		"positionIdx": 0, // One-way position mode
 	}
+	if postOnlyPrice > 0 {
+		params["orderType"] = "Limit"
+		params["price"] = formatLimitPrice(postOnlyPrice, t.getTickSize(symbol), side == "Sell")
+		params["timeInForce"] = "PostOnly"
+	}
+	if stopLoss > 0 || takeProfit > 0 {
+		params["tpslMode"] = "Partial"
+	}
+	if stopLoss > 0 {
+		params["stopLoss"] = fmt.Sprintf("%v", stopLoss)
+		params["slTriggerBy"] = "LastPrice"
+		params["slOrderType"] = "Market"
+	}
+	if takeProfit > 0 {
+		params["takeProfit"] = fmt.Sprintf("%v", takeProfit)
+		params["tpTriggerBy"] = "LastPrice"
+		params["tpOrderType"] = "Market"
+	}
 
-	logger.Infof("[Bybit] OpenShort placing order: %+v", params)
+	t.log().Infof("[Bybit] Open %s placing order: %+v", direction, params)
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("Bybit open short failed: %w", err)
+		return nil, fmt.Errorf("Bybit open %s failed: %w", direction, err)
 	}
 
 	// Clear cache
@@ -477,6 +610,26 @@ func (t *BybitTrader) SetLeverage(symbol string, leverage int) error {
 	return nil
 }
 
+// AdjustPositionMargin adds (amount > 0) or removes (amount < 0) isolated margin of an open position
+// (implements PositionMarginAdjuster)
+func (t *BybitTrader) AdjustPositionMargin(symbol, positionSide string, amount float64) error {
+	params := map[string]interface{}{
+		"category":    "linear",
+		"symbol":      symbol,
+		"margin":      strconv.FormatFloat(amount, 'f', -1, 64), // Negative reduces margin
+		"positionIdx": 0,                                        // One-way position mode
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).UpdatePositionMargin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to adjust position margin: %w", err)
+	}
+	if result.RetCode != 0 {
+		return fmt.Errorf("failed to adjust position margin: %s", result.RetMsg)
+	}
+	return nil
+}
+
 // SetMarginMode sets position margin mode
 func (t *BybitTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	tradeMode := 1 // Isolated margin
@@ -542,6 +695,76 @@ func (t *BybitTrader) GetMarketPrice(symbol string) (float64, error) {
 	return lastPrice, nil
 }
 
+// GetBestBidAsk gets the best bid and ask from the market ticker
+func (t *BybitTrader) GetBestBidAsk(symbol string) (float64, float64, error) {
+	params := map[string]interface{}{
+		"category": "linear",
+		"symbol":   symbol,
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).GetMarketTickers(context.Background())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get market ticker: %w", err)
+	}
+	if result.RetCode != 0 {
+		return 0, 0, fmt.Errorf("API error: %s", result.RetMsg)
+	}
+	resultData, ok := result.Result.(map[string]interface{})
+	if !ok {
+		return 0, 0, fmt.Errorf("return format error")
+	}
+	list, _ := resultData["list"].([]interface{})
+	if len(list) == 0 {
+		return 0, 0, fmt.Errorf("ticker not found for %s", symbol)
+	}
+
+	ticker, _ := list[0].(map[string]interface{})
+	bidStr, _ := ticker["bid1Price"].(string)
+	askStr, _ := ticker["ask1Price"].(string)
+	bid, _ := strconv.ParseFloat(bidStr, 64)
+	ask, _ := strconv.ParseFloat(askStr, 64)
+	return bid, ask, nil
+}
+
+// GetOrderBook gets the top depth levels of the order book
+func (t *BybitTrader) GetOrderBook(symbol string, depth int) (*kernel.OrderBook, error) {
+	params := map[string]interface{}{
+		"category": "linear",
+		"symbol":   symbol,
+		"limit":    min(depth, 500),
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).GetOrderBookInfo(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("API error: %s", result.RetMsg)
+	}
+	resultData, ok := result.Result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("return format error")
+	}
+
+	return &kernel.OrderBook{
+		Symbol: symbol,
+		Bids:   parseOrderBookLevels(bybitBookRows(resultData["b"]), depth),
+		Asks:   parseOrderBookLevels(bybitBookRows(resultData["a"]), depth),
+	}, nil
+}
+
+// bybitBookRows converts a decoded [["price","size"], ...] book side
+func bybitBookRows(v interface{}) [][]interface{} {
+	raw, _ := v.([]interface{})
+	rows := make([][]interface{}, 0, len(raw))
+	for _, r := range raw {
+		if row, ok := r.([]interface{}); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
 // SetStopLoss sets stop loss order
 func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
 	side := "Sell" // LONG stop loss uses Sell
@@ -584,7 +807,7 @@ func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity,
 		return fmt.Errorf("failed to set stop loss: %s", result.RetMsg)
 	}
 
-	logger.Infof("  ✓ [Bybit] Stop loss order set: %s @ %.2f", symbol, stopPrice)
+	t.log().Infof("  ✓ [Bybit] Stop loss order set: %s @ %.2f", symbol, stopPrice)
 	return nil
 }
 
@@ -630,7 +853,73 @@ func (t *BybitTrader) SetTakeProfit(symbol string, positionSide string, quantity
 		return fmt.Errorf("failed to set take profit: %s", result.RetMsg)
 	}
 
-	logger.Infof("  ✓ [Bybit] Take profit order set: %s @ %.2f", symbol, takeProfitPrice)
+	t.log().Infof("  ✓ [Bybit] Take profit order set: %s @ %.2f", symbol, takeProfitPrice)
+	return nil
+}
+
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a reduce-only conditional order with an explicit quantity
+func (t *BybitTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// SetStopLossLimit sets a stop-limit order: a reduce-only limit order at limitPrice is placed when stopPrice triggers
+func (t *BybitTrader) SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error {
+	if err := t.placeConditionalLimitOrder(symbol, positionSide, quantity, stopPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set stop-limit: %w", err)
+	}
+	t.log().Infof("  ✓ [Bybit] Stop-limit order set: %s trigger %.4f, limit %.4f", symbol, stopPrice, limitPrice)
+	return nil
+}
+
+// SetTakeProfitLimit sets a take-profit-limit order: a reduce-only limit order at limitPrice is placed when takeProfitPrice triggers
+func (t *BybitTrader) SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error {
+	if err := t.placeConditionalLimitOrder(symbol, positionSide, quantity, takeProfitPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set take-profit-limit: %w", err)
+	}
+	t.log().Infof("  ✓ [Bybit] Take-profit-limit order set: %s trigger %.4f, limit %.4f", symbol, takeProfitPrice, limitPrice)
+	return nil
+}
+
+// placeConditionalLimitOrder places a reduce-only conditional limit order closing the position
+func (t *BybitTrader) placeConditionalLimitOrder(symbol, positionSide string, quantity, triggerPrice, limitPrice float64) error {
+	side := "Sell"
+	if positionSide == "SHORT" {
+		side = "Buy"
+	}
+
+	currentPrice, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return err
+	}
+	triggerDirection := 2 // Price fall trigger
+	if triggerPrice > currentPrice {
+		triggerDirection = 1 // Price rise trigger
+	}
+
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	params := map[string]interface{}{
+		"category":         "linear",
+		"symbol":           symbol,
+		"side":             side,
+		"orderType":        "Limit",
+		"qty":              qtyStr,
+		"price":            formatLimitPrice(limitPrice, t.getTickSize(symbol), side == "Buy"),
+		"timeInForce":      "GTC",
+		"triggerPrice":     fmt.Sprintf("%v", triggerPrice),
+		"triggerDirection": triggerDirection,
+		"triggerBy":        "LastPrice",
+		"reduceOnly":       true,
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
+	if err != nil {
+		return err
+	}
+	if result.RetCode != 0 {
+		return fmt.Errorf("%s", result.RetMsg)
+	}
 	return nil
 }
 
@@ -644,6 +933,25 @@ func (t *BybitTrader) CancelTakeProfitOrders(symbol string) error {
 	return t.cancelConditionalOrders(symbol, "TakeProfit")
 }
 
+// CancelOrder cancels a single pending order (regular or conditional) by order ID
+func (t *BybitTrader) CancelOrder(symbol string, orderID string) error {
+	params := map[string]interface{}{
+		"category": "linear",
+		"symbol":   symbol,
+		"orderId":  orderID,
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).CancelOrder(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	if result.RetCode != 0 {
+		return fmt.Errorf("failed to cancel order %s: %s", orderID, result.RetMsg)
+	}
+
+	return nil
+}
+
 // CancelAllOrders cancels all pending orders
 func (t *BybitTrader) CancelAllOrders(symbol string) error {
 	params := map[string]interface{}{
@@ -662,14 +970,81 @@ func (t *BybitTrader) CancelAllOrders(symbol string) error {
 // CancelStopOrders cancels all stop loss and take profit orders
 func (t *BybitTrader) CancelStopOrders(symbol string) error {
 	if err := t.CancelStopLossOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel stop loss orders: %v", err)
+		t.log().Infof("⚠️ [Bybit] Failed to cancel stop loss orders: %v", err)
 	}
 	if err := t.CancelTakeProfitOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel take profit orders: %v", err)
+		t.log().Infof("⚠️ [Bybit] Failed to cancel take profit orders: %v", err)
 	}
 	return nil
 }
 
+// GetSymbolInfo gets per-symbol trading limits (max leverage from instruments info)
+func (t *BybitTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	url := fmt.Sprintf("%s/v5/market/instruments-info?category=linear&symbol=%s", t.baseURL, symbol)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instruments info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				LeverageFilter struct {
+					MaxLeverage string `json:"maxLeverage"`
+				} `json:"leverageFilter"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse instruments info: %w", err)
+	}
+	if result.RetCode != 0 || len(result.Result.List) == 0 {
+		return nil, fmt.Errorf("instrument %s not found: %s", symbol, result.RetMsg)
+	}
+
+	maxLeverage, _ := strconv.ParseFloat(result.Result.List[0].LeverageFilter.MaxLeverage, 64)
+	return &SymbolInfo{Symbol: symbol, MaxLeverage: int(maxLeverage)}, nil
+}
+
+// getTickSize retrieves the price tick size for a trading pair (0 if unavailable)
+func (t *BybitTrader) getTickSize(symbol string) float64 {
+	url := fmt.Sprintf("%s/v5/market/instruments-info?category=linear&symbol=%s", t.baseURL, symbol)
+	resp, err := http.Get(url)
+	if err != nil {
+		t.log().Infof("⚠️ [Bybit] Failed to get price filter for %s: %v", symbol, err)
+		return 0
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		RetCode int `json:"retCode"`
+		Result  struct {
+			List []struct {
+				PriceFilter struct {
+					TickSize string `json:"tickSize"`
+				} `json:"priceFilter"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0
+	}
+	if result.RetCode != 0 || len(result.Result.List) == 0 {
+		return 0
+	}
+
+	tickSize, _ := strconv.ParseFloat(result.Result.List[0].PriceFilter.TickSize, 64)
+	return tickSize
+}
+
 // getQtyStep retrieves the quantity step for a trading pair
 func (t *BybitTrader) getQtyStep(symbol string) float64 {
 	// Check cache first
@@ -681,10 +1056,10 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 	t.qtyStepCacheMutex.RUnlock()
 
 	// Call public API directly to get contract information
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
+	url := fmt.Sprintf("%s/v5/market/instruments-info?category=linear&symbol=%s", t.baseURL, symbol)
 	resp, err := http.Get(url)
 	if err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
+		t.log().Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
 		return 1 // Default to integer
 	}
 	defer resp.Body.Close()
@@ -723,7 +1098,7 @@ func (t *BybitTrader) getQtyStep(symbol string) float64 {
 	t.qtyStepCache[symbol] = qtyStep
 	t.qtyStepCacheMutex.Unlock()
 
-	logger.Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
+	t.log().Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
 
 	return qtyStep
 }
@@ -844,28 +1219,32 @@ func (t *BybitTrader) GetOrderStatus(symbol string, orderID string) (map[string]
 }
 
 func (t *BybitTrader) cancelConditionalOrders(symbol string, orderType string) error {
-	// First get all conditional orders
-	params := map[string]interface{}{
-		"category":    "linear",
-		"symbol":      symbol,
-		"orderFilter": "StopOrder", // Conditional orders
-	}
+	// First get all conditional orders, plus TP/SL attached to entry orders (tpslOrder)
+	var list []interface{}
+	for _, filter := range []string{"StopOrder", "tpslOrder"} {
+		params := map[string]interface{}{
+			"category":    "linear",
+			"symbol":      symbol,
+			"orderFilter": filter,
+		}
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).GetOpenOrders(context.Background())
-	if err != nil {
-		return fmt.Errorf("failed to get conditional orders: %w", err)
-	}
+		result, err := t.client.NewUtaBybitServiceWithParams(params).GetOpenOrders(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to get conditional orders: %w", err)
+		}
 
-	if result.RetCode != 0 {
-		return nil // No orders
-	}
+		if result.RetCode != 0 {
+			continue // No orders
+		}
 
-	resultData, ok := result.Result.(map[string]interface{})
-	if !ok {
-		return nil
-	}
+		resultData, ok := result.Result.(map[string]interface{})
+		if !ok {
+			continue
+		}
 
-	list, _ := resultData["list"].([]interface{})
+		orders, _ := resultData["list"].([]interface{})
+		list = append(list, orders...)
+	}
 
 	// Cancel matching orders
 	for _, item := range list {
@@ -879,7 +1258,7 @@ func (t *BybitTrader) cancelConditionalOrders(symbol string, orderType string) e
 
 		// Filter by type
 		shouldCancel := false
-		if orderType == "StopLoss" && (stopOrderType == "StopLoss" || stopOrderType == "Stop") {
+		if orderType == "StopLoss" && (stopOrderType == "StopLoss" || stopOrderType == "PartialStopLoss" || stopOrderType == "Stop") {
 			shouldCancel = true
 		}
 		if orderType == "TakeProfit" && (stopOrderType == "TakeProfit" || stopOrderType == "PartialTakeProfit") {
@@ -909,11 +1288,11 @@ func (t *BybitTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLR
 func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
 	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/position/closed-pnl?" + queryParams
+	url := t.baseURL + "/v5/position/closed-pnl?" + queryParams
 
 	// Generate timestamp
-	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
-	recvWindow := "5000"
+	timestamp := fmt.Sprintf("%d", t.clock.Now().UnixMilli())
+	recvWindow := strconv.FormatInt(recvWindowOr(bybitDefaultRecvWindow), 10)
 
 	// Build signature payload: timestamp + api_key + recv_window + queryString
 	signPayload := timestamp + t.apiKey + recvWindow + queryParams
@@ -937,8 +1316,8 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]Clo
 	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Use http.DefaultClient for the request
-	resp, err := http.DefaultClient.Do(req)
+	// Sent through the SDK client, which re-signs with exchange time and the next pooled key
+	resp, err := t.client.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
 	}
@@ -66,7 +66,7 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 	}))
 
 	// Create real Bybit trader (for interface compliance testing)
-	trader := NewBybitTrader("test_api_key", "test_secret_key")
+	trader := NewBybitTrader("test_api_key", "test_secret_key", false)
 
 	// Create base suite
 	baseSuite := NewTraderTestSuite(t, trader)
@@ -128,7 +128,7 @@ func TestNewBybitTrader(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trader := NewBybitTrader(tt.apiKey, tt.secretKey)
+			trader := NewBybitTrader(tt.apiKey, tt.secretKey, false)
 
 			if tt.wantNil {
 				assert.Nil(t, trader)
@@ -140,6 +140,15 @@ func TestNewBybitTrader(t *testing.T) {
 	}
 }
 
+// TestNewBybitTrader_Testnet Test testnet flag selects testnet base URL
+func TestNewBybitTrader_Testnet(t *testing.T) {
+	mainnet := NewBybitTrader("test", "test", false)
+	assert.Equal(t, "https://api.bybit.com", mainnet.baseURL)
+
+	testnet := NewBybitTrader("test", "test", true)
+	assert.Equal(t, "https://api-testnet.bybit.com", testnet.baseURL)
+}
+
 // TestBybitTrader_SymbolFormat Test symbol format
 func TestBybitTrader_SymbolFormat(t *testing.T) {
 	// Bybit uses uppercase symbol format (e.g. BTCUSDT)
@@ -176,7 +185,7 @@ func TestBybitTrader_SymbolFormat(t *testing.T) {
 
 // TestBybitTrader_FormatQuantity Test quantity formatting
 func TestBybitTrader_FormatQuantity(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 
 	tests := []struct {
 		name     string
@@ -335,7 +344,7 @@ func convertBybitSide(side string) string {
 // TestBybitTrader_CategoryLinear Test using only linear category
 func TestBybitTrader_CategoryLinear(t *testing.T) {
 	// Bybit trader should only use linear category (USDT perpetual contracts)
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 	assert.NotNil(t, trader)
 
 	// Verify default configuration
@@ -344,7 +353,7 @@ func TestBybitTrader_CategoryLinear(t *testing.T) {
 
 // TestBybitTrader_CacheDuration Test cache duration
 func TestBybitTrader_CacheDuration(t *testing.T) {
-	trader := NewBybitTrader("test", "test")
+	trader := NewBybitTrader("test", "test", false)
 
 	// Verify default cache time is 15 seconds
 	assert.Equal(t, 15*time.Second, trader.cacheDuration)
@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// bybitTransactionLogWindow max time range of one transaction log query
+const bybitTransactionLogWindow = 7 * 24 * time.Hour
+
+// GetTransfers returns transfers into/out of the unified trading account from the transaction log.
+// The log only covers the last 7 days per query, older transfers are not returned.
+func (t *BybitTrader) GetTransfers(since time.Time) ([]Transfer, error) {
+	now := t.clock.Now()
+	if earliest := now.Add(-bybitTransactionLogWindow).Add(time.Minute); since.Before(earliest) {
+		since = earliest
+	}
+
+	var transfers []Transfer
+	for _, logType := range []string{"TRANSFER_IN", "TRANSFER_OUT"} {
+		entries, err := t.getTransactionLogViaHTTP(logType, since, now)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, entries...)
+	}
+	return transfers, nil
+}
+
+// getTransactionLogViaHTTP makes direct HTTP call to Bybit API for the transaction log of one type
+func (t *BybitTrader) getTransactionLogViaHTTP(logType string, startTime, endTime time.Time) ([]Transfer, error) {
+	body, err := t.transactionLogRequest(fmt.Sprintf("accountType=UNIFIED&type=%s&startTime=%d&endTime=%d&limit=50",
+		logType, startTime.UnixMilli(), endTime.UnixMilli()))
+	if err != nil {
+		return nil, err
+	}
+	return parseBybitTransactionLog(body)
+}
+
+// transactionLogRequest signs and sends a /v5/account/transaction-log query, returns the raw response
+func (t *BybitTrader) transactionLogRequest(queryParams string) ([]byte, error) {
+	url := t.baseURL + "/v5/account/transaction-log?" + queryParams
+
+	timestamp := fmt.Sprintf("%d", t.clock.Now().UnixMilli())
+	recvWindow := strconv.FormatInt(recvWindowOr(bybitDefaultRecvWindow), 10)
+
+	// Signature payload: timestamp + api_key + recv_window + queryString
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(timestamp + t.apiKey + recvWindow + queryParams))
+	signature := hex.EncodeToString(h.Sum(nil))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-BAPI-API-KEY", t.apiKey)
+	req.Header.Set("X-BAPI-SIGN", signature)
+	req.Header.Set("X-BAPI-SIGN-TYPE", "2")
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+// parseBybitTransactionLog parses /v5/account/transaction-log entries into transfers (cashFlow is signed)
+func parseBybitTransactionLog(body []byte) ([]Transfer, error) {
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				ID              string `json:"id"`
+				Currency        string `json:"currency"`
+				CashFlow        string `json:"cashFlow"`
+				TransactionTime string `json:"transactionTime"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+	}
+
+	transfers := make([]Transfer, 0, len(result.Result.List))
+	for _, entry := range result.Result.List {
+		amount, _ := strconv.ParseFloat(entry.CashFlow, 64)
+		ms, _ := strconv.ParseInt(entry.TransactionTime, 10, 64)
+		transfers = append(transfers, Transfer{
+			ID:     entry.ID,
+			Asset:  entry.Currency,
+			Amount: amount,
+			Time:   time.UnixMilli(ms).UTC(),
+		})
+	}
+	return transfers, nil
+}
@@ -0,0 +1,176 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	bybit "github.com/bybit-exchange/bybit.go.api"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// bybitStreamPingInterval Bybit drops private connections without a ping for 20s+
+	bybitStreamPingInterval = 15 * time.Second
+	bybitStreamReadTimeout  = time.Minute
+)
+
+// bybitStreamMessage private stream message (operation response or topic push)
+type bybitStreamMessage struct {
+	Op      string          `json:"op"`
+	Success *bool           `json:"success"`
+	RetMsg  string          `json:"ret_msg"`
+	Topic   string          `json:"topic"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// bybitOrderUpdate entry of the private "order" topic
+type bybitOrderUpdate struct {
+	Category    string `json:"category"`
+	Symbol      string `json:"symbol"`
+	OrderID     string `json:"orderId"`
+	OrderStatus string `json:"orderStatus"`
+	AvgPrice    string `json:"avgPrice"`
+	CumExecQty  string `json:"cumExecQty"`
+	CumExecFee  string `json:"cumExecFee"`
+}
+
+// bybitUnifiedOrderStatus maps a Bybit order status to the unified GetOrderStatus format.
+// Partially filled orders that were cancelled count as filled for the executed part.
+func bybitUnifiedOrderStatus(status string, executedQty float64) string {
+	switch status {
+	case "Filled":
+		return "FILLED"
+	case "PartiallyFilledCanceled":
+		if executedQty > 0 {
+			return "FILLED"
+		}
+		return "CANCELED"
+	case "Cancelled", "Deactivated":
+		return "CANCELED"
+	case "Rejected":
+		return "REJECTED"
+	case "PartiallyFilled":
+		return "PARTIALLY_FILLED"
+	}
+	return "NEW"
+}
+
+// StartOrderStream implements OrderFillWaiter using the Bybit v5 private WebSocket "order" topic
+func (t *BybitTrader) StartOrderStream(stopCh <-chan struct{}) error {
+	if t.orderHub == nil {
+		return errOrderStreamDown
+	}
+	if !t.orderHub.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	url := bybit.WEBSOCKET_PRIVATE_MAINNET
+	if t.baseURL == bybit.TESTNET {
+		url = bybit.WEBSOCKET_PRIVATE_TESTNET
+	}
+	t.orderHub.runStreamLoop("Bybit", t.log, stopCh, func(stopCh <-chan struct{}, onConnected func()) error {
+		return t.serveOrderStream(url, stopCh, onConnected)
+	})
+	return nil
+}
+
+// WaitOrderFill implements OrderFillWaiter
+func (t *BybitTrader) WaitOrderFill(symbol, orderID string, timeout time.Duration) (map[string]interface{}, error) {
+	return t.orderHub.wait(orderID, timeout)
+}
+
+// serveOrderStream runs one private stream connection until it fails or stopCh is closed
+func (t *BybitTrader) serveOrderStream(url string, stopCh <-chan struct{}, onConnected func()) error {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect private stream: %w", err)
+	}
+	defer conn.Close()
+
+	// Auth signature: HMAC-SHA256("GET/realtime" + expires), expires in exchange time
+	expires := t.clock.Now().Add(10 * time.Second).UnixMilli()
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte("GET/realtime" + strconv.FormatInt(expires, 10)))
+	auth := map[string]interface{}{
+		"op":   "auth",
+		"args": []interface{}{t.apiKey, expires, hex.EncodeToString(mac.Sum(nil))},
+	}
+	if err := conn.WriteJSON(auth); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(map[string]interface{}{"op": "subscribe", "args": []string{"order"}}); err != nil {
+		return err
+	}
+
+	// Writes from the ping goroutine only, after auth/subscribe
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(bybitStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := conn.WriteJSON(map[string]string{"op": "ping"}); err != nil {
+					return
+				}
+			case <-stopCh:
+				conn.Close()
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(bybitStreamReadTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var msg bybitStreamMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+		switch {
+		case msg.Op == "auth":
+			if msg.Success == nil || !*msg.Success {
+				return fmt.Errorf("private stream auth failed: %s", msg.RetMsg)
+			}
+			onConnected()
+			continue
+		case msg.Op == "subscribe" && msg.Success != nil && !*msg.Success:
+			return fmt.Errorf("order topic subscription failed: %s", msg.RetMsg)
+		case msg.Topic != "order":
+			continue
+		}
+
+		var updates []bybitOrderUpdate
+		if err := json.Unmarshal(msg.Data, &updates); err != nil {
+			continue
+		}
+		for _, u := range updates {
+			if u.Category != "" && u.Category != "linear" {
+				continue
+			}
+			avgPrice, _ := strconv.ParseFloat(u.AvgPrice, 64)
+			executedQty, _ := strconv.ParseFloat(u.CumExecQty, 64)
+			commission, _ := strconv.ParseFloat(u.CumExecFee, 64)
+			t.orderHub.publish(u.OrderID, map[string]interface{}{
+				"orderId":     u.OrderID,
+				"symbol":      u.Symbol,
+				"status":      bybitUnifiedOrderStatus(u.OrderStatus, executedQty),
+				"avgPrice":    avgPrice,
+				"executedQty": executedQty,
+				"commission":  commission,
+			})
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/logger"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// clockResyncInterval how often the exchange offset is refreshed (lazily, on use)
+	clockResyncInterval = 30 * time.Minute
+	// clockSkewWarnThreshold skew above this is logged as a warning (signed requests usually allow 5s)
+	clockSkewWarnThreshold = time.Second
+)
+
+// recvWindowMs validity window of signed requests in ms (0 = each exchange's default)
+var recvWindowMs atomic.Int64
+
+// SetRecvWindow sets the recvWindow of signed requests on exchanges that support it
+// (Binance, Bybit, Aster); 0 keeps each exchange's default
+func SetRecvWindow(ms int64) {
+	if ms < 0 {
+		ms = 0
+	}
+	recvWindowMs.Store(ms)
+}
+
+// recvWindowOr returns the configured recvWindow in ms, or def when none is configured
+func recvWindowOr(def int64) int64 {
+	if ms := recvWindowMs.Load(); ms > 0 {
+		return ms
+	}
+	return def
+}
+
+// ServerTimeFetcher returns the exchange server time in Unix milliseconds
+type ServerTimeFetcher func() (int64, error)
+
+// ClockSync tracks the offset between the local clock and an exchange's server clock,
+// so signed requests use exchange time instead of a possibly drifting host clock.
+// The first use syncs synchronously, afterwards the offset is refreshed in the background.
+type ClockSync struct {
+	exchange string
+	fetch    ServerTimeFetcher
+	onSync   func(offsetMs int64) // Optional hook (e.g. push offset into an SDK client)
+
+	offsetMs   atomic.Int64 // server - local, milliseconds
+	lastSyncMs atomic.Int64 // Local time of last sync attempt (0 = never)
+	syncing    atomic.Bool
+	initOnce   sync.Once
+}
+
+// NewClockSync creates a clock sync for an exchange
+func NewClockSync(exchange string, fetch ServerTimeFetcher) *ClockSync {
+	return &ClockSync{exchange: exchange, fetch: fetch}
+}
+
+// Sync queries the server time and updates the offset.
+// Assumes the server stamped its time halfway through the round trip.
+func (c *ClockSync) Sync() error {
+	start := time.Now()
+	serverMs, err := c.fetch()
+	end := time.Now()
+	c.lastSyncMs.Store(end.UnixMilli())
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Server time sync failed, using local clock offset %dms: %v", c.exchange, c.offsetMs.Load(), err)
+		return err
+	}
+
+	rtt := end.Sub(start)
+	localMid := start.Add(rtt / 2).UnixMilli()
+	offset := serverMs - localMid
+	c.offsetMs.Store(offset)
+
+	skew := time.Duration(offset) * time.Millisecond
+	if skew >= clockSkewWarnThreshold || skew <= -clockSkewWarnThreshold {
+		logger.Warnf("⏱ [%s] Local clock skew %v vs exchange server (RTT %v), correcting request timestamps",
+			c.exchange, skew, rtt.Round(time.Millisecond))
+	} else {
+		logger.Infof("⏱ [%s] Server time synced, offset %dms (RTT %v)", c.exchange, offset, rtt.Round(time.Millisecond))
+	}
+
+	if c.onSync != nil {
+		c.onSync(offset)
+	}
+	return nil
+}
+
+// Refresh syncs synchronously on first use, then re-syncs in the background once the interval passed
+func (c *ClockSync) Refresh() {
+	if c == nil || c.fetch == nil {
+		return
+	}
+	c.initOnce.Do(func() {
+		c.syncing.Store(true)
+		defer c.syncing.Store(false)
+		c.Sync()
+	})
+
+	if time.Now().UnixMilli()-c.lastSyncMs.Load() < clockResyncInterval.Milliseconds() {
+		return
+	}
+	if !c.syncing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer c.syncing.Store(false)
+		c.Sync()
+	}()
+}
+
+// Now returns the current time on the exchange's clock (local time if c is nil)
+func (c *ClockSync) Now() time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	c.Refresh()
+	return time.Now().Add(time.Duration(c.offsetMs.Load()) * time.Millisecond)
+}
+
+// Offset returns the last measured server - local offset
+func (c *ClockSync) Offset() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.offsetMs.Load()) * time.Millisecond
+}
+
+// httpServerTimeFetcher builds a fetcher for a public JSON server-time endpoint.
+// parse extracts the millisecond timestamp from the decoded response.
+func httpServerTimeFetcher(client *http.Client, url string, parse func(body []byte) (int64, error)) ServerTimeFetcher {
+	return func() (int64, error) {
+		if client == nil {
+			client = &http.Client{Timeout: 10 * time.Second}
+		}
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("server time request failed (status %d): %s", resp.StatusCode, string(body))
+		}
+		return parse(body)
+	}
+}
+
+// parseMillisField parses a millisecond timestamp given as JSON string or number
+func parseMillisField(raw json.RawMessage) (int64, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	var n int64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return 0, fmt.Errorf("invalid server time: %s", string(raw))
+	}
+	return n, nil
+}
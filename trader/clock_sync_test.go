@@ -0,0 +1,98 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClockSyncOffset(t *testing.T) {
+	skew := 5 * time.Second
+	var hookOffset int64
+	clock := NewClockSync("test", func() (int64, error) {
+		return time.Now().Add(skew).UnixMilli(), nil
+	})
+	clock.onSync = func(offsetMs int64) { hookOffset = offsetMs }
+
+	now := clock.Now()
+	if diff := now.Sub(time.Now()) - skew; diff > 100*time.Millisecond || diff < -100*time.Millisecond {
+		t.Errorf("Now() should be ~%v ahead of local time, off by %v", skew, diff)
+	}
+	if off := clock.Offset(); off < skew-100*time.Millisecond || off > skew+100*time.Millisecond {
+		t.Errorf("Offset() = %v, want ~%v", off, skew)
+	}
+	if hookOffset == 0 {
+		t.Error("onSync hook was not called")
+	}
+}
+
+func TestClockSyncFailureKeepsOffset(t *testing.T) {
+	fail := false
+	clock := NewClockSync("test", func() (int64, error) {
+		if fail {
+			return 0, errors.New("unreachable")
+		}
+		return time.Now().Add(-2 * time.Second).UnixMilli(), nil
+	})
+	if err := clock.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	before := clock.Offset()
+
+	fail = true
+	if err := clock.Sync(); err == nil {
+		t.Fatal("expected sync error")
+	}
+	if clock.Offset() != before {
+		t.Errorf("failed sync changed offset: %v -> %v", before, clock.Offset())
+	}
+}
+
+func TestNilClockSyncUsesLocalTime(t *testing.T) {
+	var clock *ClockSync
+	if d := time.Since(clock.Now()); d > time.Second || d < -time.Second {
+		t.Errorf("nil clock Now() should be local time, off by %v", d)
+	}
+}
+
+func TestParseServerTimes(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) (int64, error)
+		body  string
+		want  int64
+	}{
+		{name: "okx", parse: parseOKXServerTime, body: `{"code":"0","data":[{"ts":"1597026383085"}]}`, want: 1597026383085},
+		{name: "bitget", parse: parseBitgetServerTime, body: `{"code":"00000","data":{"serverTime":"1688008631614"}}`, want: 1688008631614},
+		{name: "bybit", parse: parseBybitServerTime, body: `{"retCode":0,"result":{},"time":1688639403423}`, want: 1688639403423},
+		{name: "aster", parse: parseAsterServerTime, body: `{"serverTime":1499827319559}`, want: 1499827319559},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse([]byte(tt.body))
+			if err != nil || got != tt.want {
+				t.Errorf("parse = %d, %v; want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecvWindowConfig(t *testing.T) {
+	defer SetRecvWindow(0)
+
+	if got := recvWindowOr(5000); got != 5000 {
+		t.Errorf("unset recvWindow = %d, want exchange default 5000", got)
+	}
+	SetRecvWindow(20000)
+	if got := recvWindowOr(5000); got != 20000 {
+		t.Errorf("configured recvWindow = %d, want 20000", got)
+	}
+	trader := &FuturesTrader{}
+	if opts := trader.requestOpts(); len(opts) != 1 {
+		t.Errorf("Binance request options = %d, want recvWindow option", len(opts))
+	}
+	SetRecvWindow(-1)
+	if got := recvWindowOr(5000); got != 5000 {
+		t.Errorf("negative recvWindow = %d, want default", got)
+	}
+}
@@ -0,0 +1,235 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"nofx/store"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CoinbaseTrade represents a fill from Coinbase International
+type CoinbaseTrade struct {
+	Symbol    string // Generic symbol (BTCUSDT)
+	TradeID   string // fill_id
+	OrderID   string
+	Side      string // BUY or SELL
+	FillPrice float64
+	FillQty   float64
+	Fee       float64
+	FeeAsset  string
+	ExecTime  time.Time
+}
+
+// getFills queries the portfolio's fills with extra filters (order_id, time_from, ...)
+func (t *CoinbaseTrader) getFills(filters url.Values) ([]CoinbaseTrade, error) {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"portfolios": {portfolio}}
+	for k, v := range filters {
+		query[k] = v
+	}
+
+	data, err := t.doRequest("GET", coinbaseFillsPath, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	var resp struct {
+		Results []struct {
+			FillID    string        `json:"fill_id"`
+			OrderID   string        `json:"order_id"`
+			Symbol    string        `json:"symbol"` // BTC-PERP
+			Side      string        `json:"side"`   // BUY, SELL
+			FillPrice coinbaseFloat `json:"fill_price"`
+			FillQty   coinbaseFloat `json:"fill_qty"`
+			Fee       coinbaseFloat `json:"fee"`
+			FeeAsset  string        `json:"fee_asset"`
+			EventTime string        `json:"event_time"` // RFC3339
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse fills: %w", err)
+	}
+
+	trades := make([]CoinbaseTrade, 0, len(resp.Results))
+	for _, fill := range resp.Results {
+		execTime, _ := time.Parse(time.RFC3339Nano, fill.EventTime)
+		trades = append(trades, CoinbaseTrade{
+			Symbol:    t.convertSymbolBack(fill.Symbol),
+			TradeID:   fill.FillID,
+			OrderID:   fill.OrderID,
+			Side:      fill.Side,
+			FillPrice: float64(fill.FillPrice),
+			FillQty:   float64(fill.FillQty),
+			Fee:       float64(fill.Fee),
+			FeeAsset:  fill.FeeAsset,
+			ExecTime:  execTime.UTC(),
+		})
+	}
+	return trades, nil
+}
+
+// GetTrades retrieves fills from Coinbase International since startTime
+func (t *CoinbaseTrader) GetTrades(startTime time.Time, limit int) ([]CoinbaseTrade, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	return t.getFills(url.Values{
+		"time_from":    {startTime.UTC().Format(time.RFC3339)},
+		"result_limit": {strconv.Itoa(limit)},
+	})
+}
+
+// coinbaseOrderAction infers the order action of a fill.
+// Fills don't say whether they opened or closed, so a fill against a locally open position
+// on the opposite side is a close, anything else opens (or adds to) a position.
+func coinbaseOrderAction(side string, openLong, openShort bool) string {
+	if side == "SELL" {
+		if openLong {
+			return "close_long"
+		}
+		return "open_short"
+	}
+	if openShort {
+		return "close_short"
+	}
+	return "open_long"
+}
+
+// SyncOrdersFromCoinbase syncs Coinbase International fills to local database
+// Also creates/updates position records to ensure orders/fills/positions data consistency
+// exchangeID: Exchange account UUID (from exchanges.id)
+// exchangeType: Exchange type ("coinbase")
+func (t *CoinbaseTrader) SyncOrdersFromCoinbase(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	if st == nil {
+		return fmt.Errorf("store is nil")
+	}
+
+	// Get recent trades (last 24 hours)
+	startTime := time.Now().Add(-24 * time.Hour)
+
+	t.log().Infof("🔄 Syncing Coinbase trades from: %s", startTime.Format(time.RFC3339))
+
+	trades, err := t.GetTrades(startTime, 100)
+	if err != nil {
+		return fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	t.log().Infof("📥 Received %d trades from Coinbase", len(trades))
+
+	// Sort trades by time ASC (oldest first) for proper position building
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ExecTime.UnixMilli() < trades[j].ExecTime.UnixMilli()
+	})
+
+	// Process trades one by one (no transaction to avoid deadlock)
+	orderStore := st.Order()
+	positionStore := st.Position()
+	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
+	syncedCount := 0
+
+	for _, trade := range trades {
+		// Check if trade already exists (use exchangeID which is UUID, not exchange type)
+		existing, err := orderStore.GetOrderByExchangeID(exchangeID, trade.TradeID)
+		if err == nil && existing != nil {
+			continue // Order already exists, skip
+		}
+
+		symbol := trade.Symbol
+		longPos, _ := positionStore.GetOpenPositionBySymbol(traderID, symbol, "LONG")
+		shortPos, _ := positionStore.GetOpenPositionBySymbol(traderID, symbol, "SHORT")
+		orderAction := coinbaseOrderAction(trade.Side, longPos != nil, shortPos != nil)
+
+		positionSide := "LONG"
+		if orderAction == "open_short" || orderAction == "close_short" {
+			positionSide = "SHORT"
+		}
+
+		// Fees charged in the base coin or a discount token are valued in the quote currency
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.FillPrice)
+
+		// Create order record - use UTC time in milliseconds to avoid timezone issues
+		execTimeMs := trade.ExecTime.UnixMilli()
+		orderRecord := &store.TraderOrder{
+			TraderID:        traderID,
+			ExchangeID:      exchangeID,   // UUID
+			ExchangeType:    exchangeType, // Exchange type
+			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
+			Symbol:          symbol,
+			Side:            trade.Side,
+			PositionSide:    "BOTH", // Coinbase International nets positions per instrument
+			Type:            "MARKET",
+			OrderAction:     orderAction,
+			Quantity:        trade.FillQty,
+			Price:           trade.FillPrice,
+			Status:          "FILLED",
+			FilledQuantity:  trade.FillQty,
+			AvgFillPrice:    trade.FillPrice,
+			Commission:      feeUSDT,
+			FilledAt:        execTimeMs,
+			CreatedAt:       execTimeMs,
+			UpdatedAt:       execTimeMs,
+		}
+
+		if err := orderStore.CreateOrder(orderRecord); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			continue
+		}
+
+		fillRecord := &store.TraderFill{
+			TraderID:        traderID,
+			ExchangeID:      exchangeID,   // UUID
+			ExchangeType:    exchangeType, // Exchange type
+			OrderID:         orderRecord.ID,
+			ExchangeOrderID: trade.OrderID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
+			ExchangeTradeID: trade.TradeID,
+			Symbol:          symbol,
+			Side:            trade.Side,
+			Price:           trade.FillPrice,
+			Quantity:        trade.FillQty,
+			QuoteQuantity:   trade.FillPrice * trade.FillQty,
+			Commission:      trade.Fee,
+			CommissionAsset: trade.FeeAsset,
+			CommissionUSDT:  feeUSDT,
+			IsMaker:         false,
+			CreatedAt:       execTimeMs,
+		}
+
+		if err := orderStore.CreateFill(fillRecord); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+		}
+
+		// Realized PnL isn't reported per fill, PositionBuilder derives it from the entry price
+		if err := posBuilder.ProcessTrade(
+			traderID, exchangeID, exchangeType,
+			symbol, positionSide, orderAction,
+			trade.FillQty, trade.FillPrice, feeUSDT, 0,
+			execTimeMs, trade.TradeID,
+		); err != nil {
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+		} else {
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.FillQty)
+		}
+
+		syncedCount++
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f fee=%.6f action=%s",
+			trade.TradeID, symbol, trade.Side, trade.FillQty, trade.FillPrice, trade.Fee, orderAction)
+	}
+
+	t.log().Infof("✅ Coinbase order sync completed: %d new trades synced", syncedCount)
+	return nil
+}
+
+// SyncOrders implements OrderSyncer for Coinbase
+func (t *CoinbaseTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromCoinbase(traderID, exchangeID, exchangeType, st)
+}
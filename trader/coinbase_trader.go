@@ -0,0 +1,974 @@
+package trader
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Coinbase International Exchange (INTX) API endpoints
+const (
+	coinbaseBaseURL         = "https://api.international.coinbase.com"
+	coinbasePortfoliosPath  = "/api/v1/portfolios"
+	coinbaseOrdersPath      = "/api/v1/orders"
+	coinbaseInstrumentsPath = "/api/v1/instruments"
+	coinbaseFillsPath       = "/api/v1/portfolios/fills"
+)
+
+// Client order ID prefixes used to tell our exit orders apart in the open order list
+const (
+	coinbaseStopLossPrefix   = "nofx-sl-"
+	coinbaseTakeProfitPrefix = "nofx-tp-"
+)
+
+// CoinbaseTrader Coinbase International perpetual futures trader
+// Perpetuals are USDC-margined and quoted as BASE-PERP (BTC-PERP); nofx symbols (BTCUSDT) are mapped to them.
+// Margin is held at portfolio level: all positions of the portfolio share cross margin.
+type CoinbaseTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
+	apiKey     string
+	secretKey  string // Base64 encoded API secret
+	passphrase string
+
+	// Additional API keys of the account requests rotate across (nil = primary key only)
+	keys *APIKeyPool
+
+	// HTTP client
+	httpClient *http.Client
+
+	// Portfolio used for trading (the account's default portfolio)
+	portfolioID    string
+	portfolioMutex sync.Mutex
+
+	// Leverage requested per symbol (INTX margins at portfolio level, this is only reported on positions)
+	leverages      map[string]int
+	leveragesMutex sync.RWMutex
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Positions cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	// Instrument info cache
+	instrumentsCache      map[string]*CoinbaseInstrument
+	instrumentsCacheMutex sync.RWMutex
+
+	// Cache duration
+	cacheDuration time.Duration
+}
+
+// CoinbaseInstrument Coinbase International instrument info
+type CoinbaseInstrument struct {
+	Symbol         string  // Instrument symbol (BTC-PERP)
+	BaseIncrement  float64 // Quantity step
+	QuoteIncrement float64 // Price tick
+	MinQuantity    float64 // Minimum order quantity
+	MaxLeverage    int     // 1 / base initial margin fraction
+}
+
+// coinbaseOrder Coinbase International order (subset)
+type coinbaseOrder struct {
+	OrderID       string        `json:"order_id"`
+	ClientOrderID string        `json:"client_order_id"`
+	Side          string        `json:"side"` // BUY, SELL
+	Symbol        string        `json:"symbol"`
+	Type          string        `json:"type"` // MARKET, LIMIT, STOP, STOP_LIMIT
+	Price         coinbaseFloat `json:"price"`
+	StopPrice     coinbaseFloat `json:"stop_price"`
+	Size          coinbaseFloat `json:"size"`
+	ExecQty       coinbaseFloat `json:"exec_qty"`
+	AvgPrice      coinbaseFloat `json:"avg_price"`
+	Fee           coinbaseFloat `json:"fee"`
+	OrderStatus   string        `json:"order_status"` // WORKING, DONE, ...
+	EventType     string        `json:"event_type"`   // NEW, TRADE, CANCELED, ...
+	CloseOnly     bool          `json:"close_only"`
+}
+
+// coinbaseFloat decodes Coinbase numeric fields, which are sent either as JSON strings or numbers
+type coinbaseFloat float64
+
+func (f *coinbaseFloat) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = coinbaseFloat(v)
+	return nil
+}
+
+// NewCoinbaseTrader creates a Coinbase International trader
+func NewCoinbaseTrader(apiKey, secretKey, passphrase string) *CoinbaseTrader {
+	trader := &CoinbaseTrader{
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+		passphrase: passphrase,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: http.DefaultTransport,
+		},
+		leverages:        make(map[string]int),
+		cacheDuration:    15 * time.Second,
+		instrumentsCache: make(map[string]*CoinbaseInstrument),
+	}
+
+	logger.Infof("🔵 [Coinbase] Trader initialized")
+	return trader
+}
+
+// AddAPIKeys rotates requests across the primary and the additional API keys (MultiKeyTrader)
+func (t *CoinbaseTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	t.keys = NewAPIKeyPool(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase}, keys)
+}
+
+// sign generates the Coinbase API signature
+func (t *CoinbaseTrader) sign(secretKey, timestamp, method, requestPath, body string) string {
+	// Signature = BASE64(HMAC_SHA256(timestamp + method + requestPath + body, BASE64_DECODE(secret)))
+	key, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil {
+		key = []byte(secretKey)
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// doRequest executes a signed HTTP request; the signature covers the path without the query string
+func (t *CoinbaseTrader) doRequest(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	key, cred := t.keys.Next(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase})
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := t.sign(cred.SecretKey, timestamp, method, path, string(bodyBytes))
+
+	reqURL := coinbaseBaseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("CB-ACCESS-KEY", cred.APIKey)
+	req.Header.Set("CB-ACCESS-SIGN", signature)
+	req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("CB-ACCESS-PASSPHRASE", cred.Passphrase)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	t.keys.ObserveResponse(key, resp)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Coinbase API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// convertSymbol converts generic symbol to Coinbase International format
+// e.g., BTCUSDT -> BTC-PERP
+func (t *CoinbaseTrader) convertSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if strings.HasSuffix(symbol, "-PERP") {
+		return symbol
+	}
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote) + "-PERP"
+		}
+	}
+	return symbol + "-PERP"
+}
+
+// convertSymbolBack converts Coinbase International format to generic symbol
+// e.g., BTC-PERP -> BTCUSDT
+func (t *CoinbaseTrader) convertSymbolBack(instrument string) string {
+	return strings.TrimSuffix(strings.ToUpper(instrument), "-PERP") + "USDT"
+}
+
+// getPortfolioID returns the trading portfolio (default portfolio, otherwise the first one)
+func (t *CoinbaseTrader) getPortfolioID() (string, error) {
+	t.portfolioMutex.Lock()
+	defer t.portfolioMutex.Unlock()
+	if t.portfolioID != "" {
+		return t.portfolioID, nil
+	}
+
+	data, err := t.doRequest("GET", coinbasePortfoliosPath, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get portfolios: %w", err)
+	}
+
+	var portfolios []struct {
+		PortfolioID string `json:"portfolio_id"`
+		Name        string `json:"name"`
+		IsDefault   bool   `json:"is_default"`
+	}
+	if err := json.Unmarshal(data, &portfolios); err != nil {
+		return "", fmt.Errorf("failed to parse portfolios: %w, raw: %s", err, string(data))
+	}
+	if len(portfolios) == 0 {
+		return "", fmt.Errorf("no Coinbase International portfolio found for this API key")
+	}
+
+	t.portfolioID = portfolios[0].PortfolioID
+	for _, p := range portfolios {
+		if p.IsDefault {
+			t.portfolioID = p.PortfolioID
+			break
+		}
+	}
+	t.log().Infof("✓ [Coinbase] Using portfolio %s", t.portfolioID)
+	return t.portfolioID, nil
+}
+
+// GetBalance gets account balance
+func (t *CoinbaseTrader) GetBalance() (map[string]interface{}, error) {
+	// Check cache
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		t.balanceCacheMutex.RUnlock()
+		return t.cachedBalance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.doRequest("GET", coinbasePortfoliosPath+"/"+portfolio+"/summary", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	var summary struct {
+		Collateral          coinbaseFloat `json:"collateral"`            // Collateral value (wallet balance)
+		UnrealizedPnL       coinbaseFloat `json:"unrealized_pnl"`        // Unrealized P&L
+		TotalBalance        coinbaseFloat `json:"total_balance"`         // Collateral + unrealized P&L
+		PortfolioIMNotional coinbaseFloat `json:"portfolio_im_notional"` // Initial margin in use
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse balance data: %w, raw: %s", err, string(data))
+	}
+
+	totalEquity := float64(summary.TotalBalance)
+	unrealizedPnL := float64(summary.UnrealizedPnL)
+	if totalEquity == 0 {
+		totalEquity = float64(summary.Collateral) + unrealizedPnL
+	}
+	availableBalance := math.Max(totalEquity-float64(summary.PortfolioIMNotional), 0)
+	t.log().Infof("✓ [Coinbase] Balance: equity=%.2f, available=%.2f", totalEquity, availableBalance)
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    totalEquity - unrealizedPnL,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+		"total_equity":          totalEquity,
+	}
+
+	// Update cache
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// GetPositions gets all positions
+func (t *CoinbaseTrader) GetPositions() ([]map[string]interface{}, error) {
+	// Check cache
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		t.positionsCacheMutex.RUnlock()
+		return t.cachedPositions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.doRequest("GET", coinbasePortfoliosPath+"/"+portfolio+"/positions", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var positions []struct {
+		Symbol        string        `json:"symbol"`         // BTC-PERP
+		NetSize       coinbaseFloat `json:"net_size"`       // Signed position size (negative = short)
+		EntryVwap     coinbaseFloat `json:"entry_vwap"`     // Average entry price
+		MarkPrice     coinbaseFloat `json:"mark_price"`     // Mark price
+		UnrealizedPnL coinbaseFloat `json:"unrealized_pnl"` // Unrealized P&L
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse position data: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for _, pos := range positions {
+		netSize := float64(pos.NetSize)
+		if netSize == 0 {
+			continue
+		}
+
+		// Normalize side
+		side := "long"
+		if netSize < 0 {
+			side = "short"
+		}
+		symbol := t.convertSymbolBack(pos.Symbol)
+
+		posMap := map[string]interface{}{
+			"symbol":           symbol,
+			"positionAmt":      math.Abs(netSize),
+			"entryPrice":       float64(pos.EntryVwap),
+			"markPrice":        float64(pos.MarkPrice),
+			"unRealizedProfit": float64(pos.UnrealizedPnL),
+			"leverage":         float64(t.getLeverage(symbol)),
+			"liquidationPrice": 0.0, // Portfolio-level liquidation, no per-position price
+			"side":             side,
+		}
+		result = append(result, posMap)
+	}
+
+	// Update cache
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// getInstrument gets instrument info (cached)
+func (t *CoinbaseTrader) getInstrument(symbol string) (*CoinbaseInstrument, error) {
+	instrument := t.convertSymbol(symbol)
+
+	t.instrumentsCacheMutex.RLock()
+	if info, ok := t.instrumentsCache[instrument]; ok {
+		t.instrumentsCacheMutex.RUnlock()
+		return info, nil
+	}
+	t.instrumentsCacheMutex.RUnlock()
+
+	data, err := t.doRequest("GET", coinbaseInstrumentsPath+"/"+instrument, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Symbol         string        `json:"symbol"`
+		BaseIncrement  coinbaseFloat `json:"base_increment"`
+		QuoteIncrement coinbaseFloat `json:"quote_increment"`
+		MinQuantity    coinbaseFloat `json:"min_quantity"`
+		BaseIMF        coinbaseFloat `json:"base_imf"` // Base initial margin fraction
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse instrument info: %w", err)
+	}
+
+	info := &CoinbaseInstrument{
+		Symbol:         instrument,
+		BaseIncrement:  float64(raw.BaseIncrement),
+		QuoteIncrement: float64(raw.QuoteIncrement),
+		MinQuantity:    float64(raw.MinQuantity),
+	}
+	if raw.BaseIMF > 0 {
+		info.MaxLeverage = int(math.Round(1 / float64(raw.BaseIMF)))
+	}
+
+	t.instrumentsCacheMutex.Lock()
+	t.instrumentsCache[instrument] = info
+	t.instrumentsCacheMutex.Unlock()
+
+	return info, nil
+}
+
+// GetSymbolInfo gets per-symbol trading limits (max leverage from the initial margin fraction)
+func (t *CoinbaseTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	info, err := t.getInstrument(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &SymbolInfo{Symbol: symbol, MaxLeverage: info.MaxLeverage}, nil
+}
+
+// SetMarginMode sets margin mode
+// Coinbase International portfolios are always cross margin; isolation requires a separate portfolio
+func (t *CoinbaseTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		t.log().Infof("  ⚠️ Coinbase International only supports portfolio cross margin, %s stays on cross margin", t.convertSymbol(symbol))
+	}
+	return nil
+}
+
+// SetLeverage sets leverage
+// Coinbase International margins positions at portfolio level, so the leverage is only used for sizing and reporting
+func (t *CoinbaseTrader) SetLeverage(symbol string, leverage int) error {
+	t.leveragesMutex.Lock()
+	t.leverages[strings.ToUpper(symbol)] = leverage
+	t.leveragesMutex.Unlock()
+
+	t.log().Infof("  ✓ %s leverage recorded as %dx (portfolio margin)", t.convertSymbol(symbol), leverage)
+	return nil
+}
+
+// getLeverage returns the leverage recorded for a symbol (1 if never set)
+func (t *CoinbaseTrader) getLeverage(symbol string) int {
+	t.leveragesMutex.RLock()
+	defer t.leveragesMutex.RUnlock()
+	if leverage, ok := t.leverages[strings.ToUpper(symbol)]; ok && leverage > 0 {
+		return leverage
+	}
+	return 1
+}
+
+// placeOrder places an order on the trading portfolio
+func (t *CoinbaseTrader) placeOrder(body map[string]interface{}) (*coinbaseOrder, error) {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+	body["portfolio"] = portfolio
+	if _, ok := body["client_order_id"]; !ok {
+		body["client_order_id"] = genCoinbaseClientOrderID("nofx-")
+	}
+
+	data, err := t.doRequest("POST", coinbaseOrdersPath, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var order coinbaseOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	return &order, nil
+}
+
+// marketOrder places an IOC market order; closeOnly orders can only reduce the position
+func (t *CoinbaseTrader) marketOrder(symbol, side string, quantity float64, closeOnly bool) (map[string]interface{}, error) {
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	order, err := t.placeOrder(map[string]interface{}{
+		"instrument": t.convertSymbol(symbol),
+		"side":       side,
+		"size":       qtyStr,
+		"type":       "MARKET",
+		"tif":        "IOC",
+		"close_only": closeOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear cache
+	t.clearCache()
+
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"status":  coinbaseOrderStatus(order),
+	}, nil
+}
+
+// OpenLong opens long position
+func (t *CoinbaseTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders first
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	t.log().Infof("  📊 Coinbase OpenLong: symbol=%s, qty=%.6f, leverage=%d", t.convertSymbol(symbol), quantity, leverage)
+
+	result, err := t.marketOrder(symbol, "BUY", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open long position: %w", err)
+	}
+
+	t.log().Infof("✓ Coinbase opened long position successfully: %s", symbol)
+	return result, nil
+}
+
+// OpenShort opens short position
+func (t *CoinbaseTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// Cancel old orders first
+	t.CancelAllOrders(symbol)
+
+	// Set leverage
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
+	}
+
+	t.log().Infof("  📊 Coinbase OpenShort: symbol=%s, qty=%.6f, leverage=%d", t.convertSymbol(symbol), quantity, leverage)
+
+	result, err := t.marketOrder(symbol, "SELL", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open short position: %w", err)
+	}
+
+	t.log().Infof("✓ Coinbase opened short position successfully: %s", symbol)
+	return result, nil
+}
+
+// positionQuantity returns the size of the open position on one side (0 if none)
+func (t *CoinbaseTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	symbol = t.convertSymbolBack(t.convertSymbol(symbol))
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+	return 0, nil
+}
+
+// CloseLong closes long position
+func (t *CoinbaseTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "long"); err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("long position not found for %s", symbol)
+		}
+	}
+
+	t.log().Infof("  📊 Coinbase CloseLong: symbol=%s, qty=%.6f", t.convertSymbol(symbol), quantity)
+
+	result, err := t.marketOrder(symbol, "SELL", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close long position: %w", err)
+	}
+
+	t.log().Infof("✓ Coinbase closed long position successfully: %s", symbol)
+	return result, nil
+}
+
+// CloseShort closes short position
+func (t *CoinbaseTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	// If quantity is 0, get current position
+	if quantity == 0 {
+		var err error
+		if quantity, err = t.positionQuantity(symbol, "short"); err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, fmt.Errorf("short position not found for %s", symbol)
+		}
+	}
+
+	t.log().Infof("  📊 Coinbase CloseShort: symbol=%s, qty=%.6f", t.convertSymbol(symbol), quantity)
+
+	result, err := t.marketOrder(symbol, "BUY", math.Abs(quantity), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close short position: %w", err)
+	}
+
+	t.log().Infof("✓ Coinbase closed short position successfully: %s", symbol)
+	return result, nil
+}
+
+// GetMarketPrice gets market price (last trade, mark price if no trade yet)
+func (t *CoinbaseTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.doRequest("GET", coinbaseInstrumentsPath+"/"+t.convertSymbol(symbol)+"/quote", nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	var quote struct {
+		TradePrice coinbaseFloat `json:"trade_price"`
+		MarkPrice  coinbaseFloat `json:"mark_price"`
+	}
+	if err := json.Unmarshal(data, &quote); err != nil {
+		return 0, err
+	}
+
+	price := float64(quote.TradePrice)
+	if price <= 0 {
+		price = float64(quote.MarkPrice)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("no price data received")
+	}
+	return price, nil
+}
+
+// coinbaseExitSide returns the order side closing a position
+func coinbaseExitSide(positionSide string) string {
+	if strings.ToUpper(positionSide) == "SHORT" {
+		return "BUY"
+	}
+	return "SELL"
+}
+
+// SetStopLoss sets stop loss order (close-only stop market order)
+func (t *CoinbaseTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	_, err := t.placeOrder(map[string]interface{}{
+		"client_order_id": genCoinbaseClientOrderID(coinbaseStopLossPrefix),
+		"instrument":      t.convertSymbol(symbol),
+		"side":            coinbaseExitSide(positionSide),
+		"size":            qtyStr,
+		"type":            "STOP",
+		"tif":             "GTC",
+		"stop_price":      t.formatPrice(symbol, stopPrice),
+		"close_only":      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set stop loss: %w", err)
+	}
+
+	t.log().Infof("  ✓ [Coinbase] Stop loss set: %s @ %.4f", t.convertSymbol(symbol), stopPrice)
+	return nil
+}
+
+// SetTakeProfit sets take profit order
+// A close-only limit order rests at the take profit price and fills once price reaches it
+func (t *CoinbaseTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+
+	_, err := t.placeOrder(map[string]interface{}{
+		"client_order_id": genCoinbaseClientOrderID(coinbaseTakeProfitPrefix),
+		"instrument":      t.convertSymbol(symbol),
+		"side":            coinbaseExitSide(positionSide),
+		"size":            qtyStr,
+		"type":            "LIMIT",
+		"tif":             "GTC",
+		"price":           t.formatPrice(symbol, takeProfitPrice),
+		"close_only":      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+
+	t.log().Infof("  ✓ [Coinbase] Take profit set: %s @ %.4f", t.convertSymbol(symbol), takeProfitPrice)
+	return nil
+}
+
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a close-only limit order for size
+func (t *CoinbaseTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// formatPrice rounds a price to the instrument tick size
+func (t *CoinbaseTrader) formatPrice(symbol string, price float64) string {
+	tickSize := 0.0
+	if info, err := t.getInstrument(symbol); err == nil {
+		tickSize = info.QuoteIncrement
+	}
+	return formatLimitPrice(price, tickSize, false)
+}
+
+// isCoinbaseStopLoss reports whether an open order is a stop loss (our prefix, or any close-only stop)
+func isCoinbaseStopLoss(order coinbaseOrder) bool {
+	if strings.HasPrefix(order.ClientOrderID, coinbaseStopLossPrefix) {
+		return true
+	}
+	return order.CloseOnly && strings.HasPrefix(order.Type, "STOP")
+}
+
+// isCoinbaseTakeProfit reports whether an open order is a take profit (our prefix, or a close-only limit)
+func isCoinbaseTakeProfit(order coinbaseOrder) bool {
+	if strings.HasPrefix(order.ClientOrderID, coinbaseTakeProfitPrefix) {
+		return true
+	}
+	return order.CloseOnly && order.Type == "LIMIT"
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *CoinbaseTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelOrders(symbol, isCoinbaseStopLoss)
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *CoinbaseTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelOrders(symbol, isCoinbaseTakeProfit)
+}
+
+// cancelOrders cancels the open orders of a symbol matching the filter
+func (t *CoinbaseTrader) cancelOrders(symbol string, match func(coinbaseOrder) bool) error {
+	orders, err := t.listOpenOrders(symbol)
+	if err != nil {
+		return err
+	}
+
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return err
+	}
+	query := url.Values{"portfolio": {portfolio}}
+
+	var failed int
+	for _, order := range orders {
+		if !match(order) {
+			continue
+		}
+		if _, err := t.doRequest("DELETE", coinbaseOrdersPath+"/"+order.OrderID, query, nil); err != nil {
+			t.log().Infof("  ⚠️ Failed to cancel Coinbase order %s: %v", order.OrderID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to cancel %d orders", failed)
+	}
+	return nil
+}
+
+// listOpenOrders gets the open orders of a symbol
+func (t *CoinbaseTrader) listOpenOrders(symbol string) ([]coinbaseOrder, error) {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"portfolio":    {portfolio},
+		"instrument":   {t.convertSymbol(symbol)},
+		"result_limit": {"100"},
+	}
+	data, err := t.doRequest("GET", coinbaseOrdersPath, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var resp struct {
+		Results []coinbaseOrder `json:"results"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse open orders: %w", err)
+	}
+	return resp.Results, nil
+}
+
+// CancelOrder cancels a single pending order by order ID
+func (t *CoinbaseTrader) CancelOrder(symbol string, orderID string) error {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"portfolio": {portfolio}}
+	if _, err := t.doRequest("DELETE", coinbaseOrdersPath+"/"+orderID, query, nil); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders
+func (t *CoinbaseTrader) CancelAllOrders(symbol string) error {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{
+		"portfolio":  {portfolio},
+		"instrument": {t.convertSymbol(symbol)},
+	}
+	if _, err := t.doRequest("DELETE", coinbaseOrdersPath, query, nil); err != nil {
+		return fmt.Errorf("failed to cancel orders: %w", err)
+	}
+	return nil
+}
+
+// CancelStopOrders cancels stop loss and take profit orders
+func (t *CoinbaseTrader) CancelStopOrders(symbol string) error {
+	return t.cancelOrders(symbol, func(order coinbaseOrder) bool {
+		return isCoinbaseStopLoss(order) || isCoinbaseTakeProfit(order)
+	})
+}
+
+// FormatQuantity formats quantity to the instrument's base increment
+func (t *CoinbaseTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	info, err := t.getInstrument(symbol)
+	if err != nil || info.BaseIncrement <= 0 {
+		return fmt.Sprintf("%.4f", quantity), nil
+	}
+	return formatLimitPrice(quantity, info.BaseIncrement, false), nil
+}
+
+// GetOrderStatus gets order status
+// Open orders are returned by the orders endpoint; finished orders are rebuilt from their fills
+func (t *CoinbaseTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	portfolio, err := t.getPortfolioID()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.doRequest("GET", coinbaseOrdersPath+"/"+orderID, url.Values{"portfolio": {portfolio}}, nil)
+	if err == nil {
+		var order coinbaseOrder
+		if err := json.Unmarshal(data, &order); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"orderId":     order.OrderID,
+			"symbol":      symbol,
+			"status":      coinbaseOrderStatus(&order),
+			"avgPrice":    float64(order.AvgPrice),
+			"executedQty": float64(order.ExecQty),
+			"side":        order.Side,
+			"type":        order.Type,
+			"commission":  float64(order.Fee),
+		}, nil
+	}
+
+	fills, fillErr := t.getFills(url.Values{"order_id": {orderID}})
+	if fillErr != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	if len(fills) == 0 {
+		return map[string]interface{}{
+			"orderId": orderID,
+			"symbol":  symbol,
+			"status":  "CANCELED",
+		}, nil
+	}
+
+	var qty, notional, fee float64
+	for _, fill := range fills {
+		qty += fill.FillQty
+		notional += fill.FillQty * fill.FillPrice
+		fee += fill.Fee
+	}
+	return map[string]interface{}{
+		"orderId":     orderID,
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    notional / qty,
+		"executedQty": qty,
+		"side":        fills[0].Side,
+		"type":        "MARKET",
+		"commission":  fee,
+	}, nil
+}
+
+// coinbaseOrderStatus maps a Coinbase order to FILLED/PARTIALLY_FILLED/NEW/CANCELED
+func coinbaseOrderStatus(order *coinbaseOrder) string {
+	switch strings.ToUpper(order.EventType) {
+	case "CANCELED", "CANCELLED", "EXPIRED", "REJECTED":
+		return "CANCELED"
+	}
+	if order.ExecQty > 0 && order.Size > 0 && order.ExecQty >= order.Size {
+		return "FILLED"
+	}
+	if order.ExecQty > 0 {
+		return "PARTIALLY_FILLED"
+	}
+	return "NEW"
+}
+
+// GetClosedPnL retrieves closed position PnL records
+// Coinbase International has no closed-position history; closed positions are rebuilt from fills by SyncOrders
+func (t *CoinbaseTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return []ClosedPnLRecord{}, nil
+}
+
+// GetOpenOrders gets all open/pending orders for a symbol
+func (t *CoinbaseTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	orders, err := t.listOpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		// Exits sit on the opposite side of the position, entries on the same side
+		positionSide := "LONG"
+		if (order.Side == "BUY") == order.CloseOnly {
+			positionSide = "SHORT"
+		}
+
+		orderType := order.Type
+		switch {
+		case isCoinbaseStopLoss(order):
+			orderType = "STOP_MARKET"
+		case isCoinbaseTakeProfit(order):
+			orderType = "TAKE_PROFIT_LIMIT"
+		}
+
+		result = append(result, OpenOrder{
+			OrderID:      order.OrderID,
+			Symbol:       t.convertSymbolBack(order.Symbol),
+			Side:         order.Side,
+			PositionSide: positionSide,
+			Type:         orderType,
+			Price:        float64(order.Price),
+			StopPrice:    float64(order.StopPrice),
+			Quantity:     float64(order.Size),
+			Status:       "NEW",
+		})
+	}
+	return result, nil
+}
+
+// clearCache clears all caches
+func (t *CoinbaseTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// genCoinbaseClientOrderID generates unique client order ID with the given prefix
+func genCoinbaseClientOrderID(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+}
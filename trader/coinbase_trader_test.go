@@ -0,0 +1,82 @@
+package trader
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCoinbaseConvertSymbol(t *testing.T) {
+	ct := &CoinbaseTrader{}
+	tests := map[string]string{
+		"BTCUSDT":  "BTC-PERP",
+		"ethusdc":  "ETH-PERP",
+		"SOL-PERP": "SOL-PERP",
+		"DOGE":     "DOGE-PERP",
+	}
+	for input, want := range tests {
+		if got := ct.convertSymbol(input); got != want {
+			t.Errorf("convertSymbol(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if got := ct.convertSymbolBack("BTC-PERP"); got != "BTCUSDT" {
+		t.Errorf("convertSymbolBack(BTC-PERP) = %q, want BTCUSDT", got)
+	}
+}
+
+func TestCoinbaseSign(t *testing.T) {
+	ct := &CoinbaseTrader{}
+	got := ct.sign("c2VjcmV0LWtleQ==", "1700000000", "POST", "/api/v1/orders", `{"size":"1"}`)
+	if want := "JvLtSISuNZMtBfRoarbYqpHEdkoGb2exGx35ehT8DIc="; got != want {
+		t.Errorf("sign() = %q, want %q", got, want)
+	}
+}
+
+func TestCoinbaseFloatDecoding(t *testing.T) {
+	var v struct {
+		A coinbaseFloat `json:"a"`
+		B coinbaseFloat `json:"b"`
+		C coinbaseFloat `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"1.5","b":2,"c":""}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 1.5 || v.B != 2 || v.C != 0 {
+		t.Errorf("decoded %+v", v)
+	}
+}
+
+func TestCoinbaseOrderAction(t *testing.T) {
+	tests := []struct {
+		side                string
+		openLong, openShort bool
+		want                string
+	}{
+		{"BUY", false, false, "open_long"},
+		{"SELL", false, false, "open_short"},
+		{"SELL", true, false, "close_long"},
+		{"BUY", false, true, "close_short"},
+		{"BUY", true, false, "open_long"},
+	}
+	for _, tt := range tests {
+		if got := coinbaseOrderAction(tt.side, tt.openLong, tt.openShort); got != tt.want {
+			t.Errorf("coinbaseOrderAction(%s, %v, %v) = %s, want %s", tt.side, tt.openLong, tt.openShort, got, tt.want)
+		}
+	}
+}
+
+func TestCoinbaseOrderStatus(t *testing.T) {
+	tests := []struct {
+		order coinbaseOrder
+		want  string
+	}{
+		{coinbaseOrder{Size: 1, ExecQty: 1, EventType: "TRADE"}, "FILLED"},
+		{coinbaseOrder{Size: 1, ExecQty: 0.4, EventType: "TRADE"}, "PARTIALLY_FILLED"},
+		{coinbaseOrder{Size: 1, EventType: "NEW"}, "NEW"},
+		{coinbaseOrder{Size: 1, ExecQty: 0.4, EventType: "CANCELED"}, "CANCELED"},
+	}
+	for _, tt := range tests {
+		if got := coinbaseOrderStatus(&tt.order); got != tt.want {
+			t.Errorf("coinbaseOrderStatus(%+v) = %s, want %s", tt.order, got, tt.want)
+		}
+	}
+}
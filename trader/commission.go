@@ -0,0 +1,72 @@
+package trader
+
+import (
+	"strings"
+
+	"nofx/logger"
+	"nofx/market"
+)
+
+// commissionPricer quotes the assets fees can be charged in (satisfied by every Trader)
+type commissionPricer interface {
+	GetMarketPrice(symbol string) (float64, error)
+}
+
+// commissionConverter converts trading fees charged in a non-quote asset (the base coin, or a
+// fee-discount token such as BNB/HYPE) to the quote currency, so PnL accounting never adds up
+// amounts of different assets. Prices are cached for the converter's lifetime (one sync run).
+type commissionConverter struct {
+	pricer commissionPricer
+	prices map[string]float64
+}
+
+// newCommissionConverter creates a converter pricing fee assets with pricer
+func newCommissionConverter(pricer commissionPricer) *commissionConverter {
+	return &commissionConverter{pricer: pricer, prices: make(map[string]float64)}
+}
+
+// ToQuote returns the quote-currency value of fee charged in asset on a fill of symbol at fillPrice.
+// Fees in a stablecoin (or without an asset) are returned as is, fees in the base coin are valued at
+// the fill price and other assets at their current <ASSET>USDT price. When the asset can't be priced
+// the raw amount is returned, as it was before conversion existed.
+func (c *commissionConverter) ToQuote(symbol, asset string, fee, fillPrice float64) float64 {
+	asset = strings.ToUpper(strings.TrimSpace(asset))
+	if fee == 0 || asset == "" || market.IsSupportedQuoteAsset(asset) {
+		return fee
+	}
+
+	normalized := market.Normalize(symbol)
+	if base := strings.TrimSuffix(normalized, market.QuoteAsset(normalized)); asset == base && fillPrice > 0 {
+		return fee * fillPrice
+	}
+
+	price, ok := c.prices[asset]
+	if !ok {
+		var err error
+		if price, err = c.pricer.GetMarketPrice(asset + market.QuoteUSDT); err != nil || price <= 0 {
+			logger.Warnf("⚠️ Can't price commission asset %s, recording the fee unconverted: %v", asset, err)
+			price = 0
+		}
+		c.prices[asset] = price
+	}
+	if price <= 0 {
+		return fee
+	}
+	return fee * price
+}
+
+// OrderStatusCommission reads the fee of a filled GetOrderStatus result: the amount charged, the asset
+// it was charged in (as recorded on fills) and its quote-currency value
+func OrderStatusCommission(pricer commissionPricer, symbol string, status map[string]interface{}, fillPrice float64) (fee float64, asset string, feeQuote float64) {
+	fee, _ = status["commission"].(float64)
+	asset, _ = status["commissionAsset"].(string)
+	return fee, commissionAsset(asset), newCommissionConverter(pricer).ToQuote(symbol, asset, fee, fillPrice)
+}
+
+// commissionAsset returns the recorded asset of a fee (the quote currency when the exchange didn't say)
+func commissionAsset(asset string) string {
+	if asset == "" {
+		return market.QuoteUSDT
+	}
+	return strings.ToUpper(asset)
+}
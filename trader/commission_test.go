@@ -0,0 +1,84 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// fakePricer quotes fixed prices and counts lookups
+type fakePricer struct {
+	prices  map[string]float64
+	lookups int
+}
+
+func (p *fakePricer) GetMarketPrice(symbol string) (float64, error) {
+	p.lookups++
+	if price, ok := p.prices[symbol]; ok {
+		return price, nil
+	}
+	return 0, fmt.Errorf("unknown symbol %s", symbol)
+}
+
+func TestCommissionConverterToQuote(t *testing.T) {
+	pricer := &fakePricer{prices: map[string]float64{"BNBUSDT": 600}}
+	fees := newCommissionConverter(pricer)
+
+	tests := []struct {
+		name   string
+		symbol string
+		asset  string
+		fee    float64
+		price  float64
+		want   float64
+	}{
+		{"quote asset", "BTCUSDT", "USDT", 0.5, 60000, 0.5},
+		{"usdc quote", "ETHUSDC", "usdc", 0.2, 3000, 0.2},
+		{"no asset", "BTCUSDT", "", 0.5, 60000, 0.5},
+		{"base coin at fill price", "ETHUSDT", "ETH", 0.0001, 3000, 0.3},
+		{"discount token at market price", "BTCUSDT", "BNB", 0.001, 60000, 0.6},
+		{"unpriceable asset unconverted", "BTCUSDT", "XYZ", 0.7, 60000, 0.7},
+	}
+	for _, tt := range tests {
+		if got := fees.ToQuote(tt.symbol, tt.asset, tt.fee, tt.price); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: ToQuote() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Token prices are cached for the converter's lifetime
+	before := pricer.lookups
+	fees.ToQuote("ETHUSDT", "BNB", 0.002, 3000)
+	if pricer.lookups != before {
+		t.Errorf("BNB priced again, want the cached price")
+	}
+}
+
+func TestOrderStatusCommission(t *testing.T) {
+	pricer := &fakePricer{prices: map[string]float64{"BNBUSDT": 600}}
+
+	fee, asset, quote := OrderStatusCommission(pricer, "BTCUSDT", map[string]interface{}{"commission": 0.001, "commissionAsset": "bnb"}, 60000)
+	if fee != 0.001 || asset != "BNB" || math.Abs(quote-0.6) > 1e-9 {
+		t.Errorf("BNB fee = %v %s (%v quote), want 0.001 BNB (0.6 quote)", fee, asset, quote)
+	}
+
+	// Exchanges that don't report the asset charge the quote currency
+	fee, asset, quote = OrderStatusCommission(pricer, "BTCUSDT", map[string]interface{}{"commission": 0.5}, 60000)
+	if fee != 0.5 || asset != "USDT" || quote != 0.5 {
+		t.Errorf("fee without asset = %v %s (%v quote), want 0.5 USDT", fee, asset, quote)
+	}
+}
+
+func TestBybitTradeFeeAsset(t *testing.T) {
+	bt := &BybitTrader{}
+	trades, _ := bt.parseTradesResult([]map[string]interface{}{
+		{"symbol": "BTCUSDT", "execFee": "0.1"},
+		{"symbol": "BTCPERP", "execFee": "0.1"},
+		{"symbol": "ETHUSDT", "execFee": "0.1", "feeCurrency": "ETH"},
+	})
+	want := []string{"USDT", "USDC", "ETH"}
+	for i, w := range want {
+		if trades[i].FeeAsset != w {
+			t.Errorf("%s fee asset = %q, want %q", trades[i].Symbol, trades[i].FeeAsset, w)
+		}
+	}
+}
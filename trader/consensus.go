@@ -0,0 +1,284 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/mcp"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ConsensusModelSpec one entry of a trader's consensus_models setting
+type ConsensusModelSpec struct {
+	ID     string
+	Weight float64
+}
+
+// ParseConsensusModels parses "model_a:2,model_b" (weight defaults to 1)
+func ParseConsensusModels(spec string) ([]ConsensusModelSpec, error) {
+	var result []ConsensusModelSpec
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, weightStr, hasWeight := strings.Cut(part, ":")
+		id = strings.TrimSpace(id)
+		weight := 1.0
+		if hasWeight {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("invalid weight for consensus model %s: %q", id, weightStr)
+			}
+			weight = w
+		}
+		if id == "" {
+			return nil, fmt.Errorf("empty consensus model ID in %q", spec)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate consensus model %s", id)
+		}
+		seen[id] = true
+		result = append(result, ConsensusModelSpec{ID: id, Weight: weight})
+	}
+	return result, nil
+}
+
+// ConsensusModelConfig a resolved consensus model (credentials from the AI model config)
+type ConsensusModelConfig struct {
+	ID              string
+	Provider        string
+	APIKey          string
+	CustomAPIURL    string
+	CustomModelName string
+	Weight          float64
+}
+
+// consensusVoter an extra model voting on each cycle's decisions
+type consensusVoter struct {
+	id     string
+	weight float64
+	client mcp.AIClient
+}
+
+// newConsensusVoters creates the AI clients of the consensus models
+func newConsensusVoters(models []ConsensusModelConfig) []consensusVoter {
+	voters := make([]consensusVoter, 0, len(models))
+	for _, m := range models {
+		var client mcp.AIClient
+		switch m.Provider {
+		case "deepseek":
+			client = mcp.NewDeepSeekClient()
+		case "qwen":
+			client = mcp.NewQwenClient()
+		case "openai":
+			client = mcp.NewOpenAIClient()
+		case "claude":
+			client = mcp.NewClaudeClient()
+		case "gemini":
+			client = mcp.NewGeminiClient()
+		case "grok":
+			client = mcp.NewGrokClient()
+		case "kimi":
+			client = mcp.NewKimiClient()
+		default:
+			client = mcp.New()
+		}
+		client.SetAPIKey(m.APIKey, m.CustomAPIURL, m.CustomModelName)
+
+		weight := m.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		voters = append(voters, consensusVoter{id: m.ID, weight: weight, client: client})
+	}
+	return voters
+}
+
+// consensusBallot one model's decisions in a consensus cycle
+type consensusBallot struct {
+	model     string
+	weight    float64
+	decisions []kernel.Decision
+	err       error
+}
+
+// requestDecision asks the trader's model for a decision; with consensus models configured, all
+// models are queried in parallel on the same context and only decisions reaching the consensus
+// threshold are kept. Returns the votes per symbol (nil without consensus).
+func (at *AutoTrader) requestDecision(ctx *kernel.Context) (*kernel.FullDecision, map[string][]store.ConsensusVote, error) {
+	if len(at.consensusVoters) == 0 {
+		decision, err := kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, at.config.PromptVariant)
+		return decision, nil, err
+	}
+
+	// Fetch market data once, the parallel calls only read the context afterwards
+	if err := kernel.PrepareContext(ctx, at.strategyEngine); err != nil {
+		return nil, nil, err
+	}
+
+	var primary *kernel.FullDecision
+	var primaryErr error
+	ballots := make([]consensusBallot, len(at.consensusVoters)+1)
+	ballots[0] = consensusBallot{model: at.aiModel, weight: 1}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		primary, primaryErr = kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, at.config.PromptVariant)
+		ballots[0].err = primaryErr
+		if primary != nil {
+			ballots[0].decisions = primary.Decisions
+		}
+	}()
+	for i, voter := range at.consensusVoters {
+		ballots[i+1] = consensusBallot{model: voter.id, weight: voter.weight}
+		wg.Add(1)
+		go func(b *consensusBallot, client mcp.AIClient) {
+			defer wg.Done()
+			fd, err := kernel.GetFullDecisionWithStrategy(ctx, client, at.strategyEngine, at.config.PromptVariant)
+			b.err = err
+			if err == nil && fd != nil {
+				b.decisions = fd.Decisions
+			}
+		}(&ballots[i+1], voter.client)
+	}
+	wg.Wait()
+
+	if primaryErr != nil {
+		return primary, nil, primaryErr
+	}
+	for _, b := range ballots[1:] {
+		if b.err != nil {
+			at.log().Warnf("⚠️ [%s] Consensus model %s failed, counted as abstaining: %v", at.name, b.model, b.err)
+		}
+	}
+
+	decisions, votes := aggregateConsensus(ballots, at.config.ConsensusThreshold)
+	at.log().Infof("🗳 [%s] Consensus of %d models: %d of %d primary decisions agreed",
+		at.name, len(ballots), countExecutable(decisions), countExecutable(primary.Decisions))
+	primary.Decisions = decisions
+	return primary, votes, nil
+}
+
+// consensusProposal an action+symbol proposed by one or more models
+type consensusProposal struct {
+	decision kernel.Decision // Parameters of the first proposing model (ballot order, primary first)
+	weight   float64
+}
+
+// aggregateConsensus keeps the decisions whose action+symbol is backed by enough vote weight.
+// threshold is the share of the total weight required (0 = more than half); models that failed
+// count as abstaining. Proposals that don't reach it become a "hold" for the symbol.
+func aggregateConsensus(ballots []consensusBallot, threshold float64) ([]kernel.Decision, map[string][]store.ConsensusVote) {
+	totalWeight := 0.0
+	for _, b := range ballots {
+		totalWeight += b.weight
+	}
+	agreed := func(weight float64) bool {
+		if threshold <= 0 {
+			return weight*2 > totalWeight
+		}
+		return weight >= threshold*totalWeight-1e-9
+	}
+
+	proposals := make(map[string]*consensusProposal) // symbol|action -> proposal
+	var keys []string
+	symbolActions := make(map[string]map[int]string) // symbol -> ballot -> first action
+	var symbols []string
+	primaryIdle := make(map[string]kernel.Decision) // primary's own hold/wait decisions
+
+	for i, b := range ballots {
+		seen := make(map[string]bool)
+		for _, d := range b.decisions {
+			if symbolActions[d.Symbol] == nil {
+				symbolActions[d.Symbol] = make(map[int]string)
+				symbols = append(symbols, d.Symbol)
+			}
+			if _, voted := symbolActions[d.Symbol][i]; !voted {
+				symbolActions[d.Symbol][i] = d.Action
+			}
+			if d.Action == "hold" || d.Action == "wait" {
+				if i == 0 {
+					primaryIdle[d.Symbol] = d
+				}
+				continue
+			}
+			key := d.Symbol + "|" + d.Action
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if p, ok := proposals[key]; ok {
+				p.weight += b.weight
+			} else {
+				proposals[key] = &consensusProposal{decision: d, weight: b.weight}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var decisions []kernel.Decision
+	rejected := make(map[string][]string) // symbol -> proposals that didn't reach the threshold
+	decided := make(map[string]bool)
+	for _, key := range keys {
+		p := proposals[key]
+		if agreed(p.weight) {
+			decisions = append(decisions, p.decision)
+			decided[p.decision.Symbol] = true
+			continue
+		}
+		rejected[p.decision.Symbol] = append(rejected[p.decision.Symbol],
+			fmt.Sprintf("%s %.0f%%", p.decision.Action, p.weight/totalWeight*100))
+	}
+
+	votes := make(map[string][]store.ConsensusVote, len(symbols))
+	for _, symbol := range symbols {
+		for i, b := range ballots {
+			action, ok := symbolActions[symbol][i]
+			switch {
+			case b.err != nil:
+				action = "error"
+			case !ok:
+				action = "wait"
+			}
+			votes[symbol] = append(votes[symbol], store.ConsensusVote{Model: b.model, Action: action, Weight: b.weight})
+		}
+		if decided[symbol] {
+			continue
+		}
+		if len(rejected[symbol]) > 0 {
+			decisions = append(decisions, kernel.Decision{
+				Symbol:    symbol,
+				Action:    "hold",
+				Reasoning: fmt.Sprintf("No consensus (%s, need %s)", strings.Join(rejected[symbol], ", "), consensusThresholdLabel(threshold)),
+			})
+		} else if d, ok := primaryIdle[symbol]; ok {
+			decisions = append(decisions, d)
+		}
+	}
+	return decisions, votes
+}
+
+// consensusThresholdLabel formats the consensus threshold for logs
+func consensusThresholdLabel(threshold float64) string {
+	if threshold <= 0 {
+		return ">50%"
+	}
+	return fmt.Sprintf("%.0f%%", threshold*100)
+}
+
+// countExecutable counts decisions that aren't hold/wait
+func countExecutable(decisions []kernel.Decision) int {
+	n := 0
+	for _, d := range decisions {
+		if d.Action != "hold" && d.Action != "wait" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package trader
+
+import (
+	"errors"
+	"nofx/kernel"
+	"strings"
+	"testing"
+)
+
+func TestParseConsensusModels(t *testing.T) {
+	specs, err := ParseConsensusModels(" a:2, b ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(specs) != 2 || specs[0].ID != "a" || specs[0].Weight != 2 || specs[1].ID != "b" || specs[1].Weight != 1 {
+		t.Errorf("unexpected specs: %+v", specs)
+	}
+	for _, bad := range []string{"a:0", "a:x", "a,a", ":2"} {
+		if _, err := ParseConsensusModels(bad); err == nil {
+			t.Errorf("ParseConsensusModels(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAggregateConsensus(t *testing.T) {
+	openBTC := kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5}
+	closeETH := kernel.Decision{Symbol: "ETHUSDT", Action: "close_short"}
+
+	t.Run("majority", func(t *testing.T) {
+		ballots := []consensusBallot{
+			{model: "primary", weight: 1, decisions: []kernel.Decision{openBTC, closeETH}},
+			{model: "m1", weight: 1, decisions: []kernel.Decision{{Symbol: "BTCUSDT", Action: "open_long", Leverage: 3}}},
+			{model: "m2", weight: 1, decisions: []kernel.Decision{{Symbol: "BTCUSDT", Action: "open_short"}}},
+		}
+		decisions, votes := aggregateConsensus(ballots, 0)
+		if len(decisions) != 2 {
+			t.Fatalf("expected 2 decisions, got %+v", decisions)
+		}
+		if decisions[0].Action != "open_long" || decisions[0].Leverage != 5 {
+			t.Errorf("agreed decision should keep the primary's parameters, got %+v", decisions[0])
+		}
+		if decisions[1].Symbol != "ETHUSDT" || decisions[1].Action != "hold" || !strings.Contains(decisions[1].Reasoning, "close_short 33%") {
+			t.Errorf("disagreement should become hold, got %+v", decisions[1])
+		}
+		if v := votes["BTCUSDT"]; len(v) != 3 || v[2].Action != "open_short" {
+			t.Errorf("unexpected BTC votes: %+v", v)
+		}
+		if v := votes["ETHUSDT"]; v[1].Action != "wait" {
+			t.Errorf("model without a decision should vote wait, got %+v", v)
+		}
+	})
+
+	t.Run("weights and threshold", func(t *testing.T) {
+		ballots := []consensusBallot{
+			{model: "primary", weight: 1, decisions: []kernel.Decision{openBTC}},
+			{model: "m1", weight: 2, decisions: []kernel.Decision{openBTC}},
+			{model: "m2", weight: 1, err: errors.New("timeout")},
+		}
+		if decisions, _ := aggregateConsensus(ballots, 0.75); decisions[0].Action != "open_long" {
+			t.Errorf("3 of 4 weight should reach a 75%% threshold, got %+v", decisions)
+		}
+		decisions, votes := aggregateConsensus(ballots, 0.8)
+		if decisions[0].Action != "hold" {
+			t.Errorf("3 of 4 weight should not reach an 80%% threshold, got %+v", decisions)
+		}
+		if votes["BTCUSDT"][2].Action != "error" {
+			t.Errorf("failed model should be recorded as error, got %+v", votes["BTCUSDT"][2])
+		}
+	})
+}
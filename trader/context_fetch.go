@@ -0,0 +1,109 @@
+package trader
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"nofx/logger"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// contextFetchTimeout bounds each market-wide data fetch in buildTradingContext,
+// so one slow endpoint can't stall the whole cycle
+const contextFetchTimeout = 20 * time.Second
+
+// fetchWithTimeout runs fetch and returns its result, or ctx.Err() if the timeout hits first.
+// The fetch keeps running in the background until its own HTTP timeout; its late result is dropped.
+func fetchWithTimeout[T any](ctx context.Context, timeout time.Duration, fetch func() T) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan T, 1)
+	go func() {
+		done <- fetch()
+	}()
+
+	select {
+	case result := <-done:
+		return result, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// parallelFetcher runs independent context fetches concurrently and records which ones timed out or failed
+type parallelFetcher struct {
+	group    *errgroup.Group
+	ctx      context.Context
+	timeout  time.Duration
+	log      *logger.Entry
+	mu       sync.Mutex
+	timedOut []string
+	failed   []string
+}
+
+func newParallelFetcher(ctx context.Context, timeout time.Duration, log *logger.Entry) *parallelFetcher {
+	group, groupCtx := errgroup.WithContext(ctx)
+	return &parallelFetcher{group: group, ctx: groupCtx, timeout: timeout, log: log}
+}
+
+// goFetch schedules fetch; apply is called with the result only if it arrived in time.
+// Fetch errors never cancel the other fetches: the cycle proceeds with whatever data arrived.
+func goFetch[T any](f *parallelFetcher, name string, fetch func() T, apply func(T)) {
+	f.group.Go(func() error {
+		start := time.Now()
+		result, err := fetchWithTimeout(f.ctx, f.timeout, fetch)
+		if err != nil {
+			f.mu.Lock()
+			f.timedOut = append(f.timedOut, name)
+			f.mu.Unlock()
+			f.log.Warnf("⏱️ %s fetch timed out after %v, continuing without it", name, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		apply(result)
+		return nil
+	})
+}
+
+// goFetchErr is goFetch for fetches that report errors; a failed fetch is recorded under its name
+// and apply is not called
+func goFetchErr[T any](f *parallelFetcher, name string, fetch func() (T, error), apply func(T)) {
+	type result struct {
+		value T
+		err   error
+	}
+	goFetch(f, name, func() result {
+		value, err := fetch()
+		return result{value, err}
+	}, func(r result) {
+		if r.err != nil {
+			f.mu.Lock()
+			f.failed = append(f.failed, name)
+			f.mu.Unlock()
+			f.log.Warnf("⚠️ %s unavailable, continuing without it: %v", name, r.err)
+			return
+		}
+		apply(r.value)
+	})
+}
+
+// Wait waits for all fetches and returns the names of those that timed out
+func (f *parallelFetcher) Wait() []string {
+	_ = f.group.Wait()
+	return f.timedOut
+}
+
+// Unavailable returns the names of fetches that timed out or failed (call after Wait)
+func (f *parallelFetcher) Unavailable() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.timedOut)+len(f.failed))
+	names = append(names, f.timedOut...)
+	names = append(names, f.failed...)
+	sort.Strings(names)
+	return names
+}
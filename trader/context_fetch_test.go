@@ -0,0 +1,71 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nofx/logger"
+)
+
+func TestFetchWithTimeout(t *testing.T) {
+	got, err := fetchWithTimeout(context.Background(), time.Second, func() int { return 42 })
+	if err != nil || got != 42 {
+		t.Fatalf("fetchWithTimeout() = %d, %v; want 42, nil", got, err)
+	}
+
+	_, err = fetchWithTimeout(context.Background(), 10*time.Millisecond, func() int {
+		time.Sleep(200 * time.Millisecond)
+		return 1
+	})
+	if err == nil {
+		t.Fatal("expected timeout error for slow fetch")
+	}
+}
+
+func TestParallelFetcherContinuesAfterTimeout(t *testing.T) {
+	fetcher := newParallelFetcher(context.Background(), 50*time.Millisecond, logger.TraderEntry("", nil))
+
+	var fast string
+	var slow string
+	goFetch(fetcher, "fast", func() string { return "ok" }, func(v string) { fast = v })
+	goFetch(fetcher, "slow", func() string {
+		time.Sleep(500 * time.Millisecond)
+		return "late"
+	}, func(v string) { slow = v })
+
+	start := time.Now()
+	timedOut := fetcher.Wait()
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("Wait took %v, slow fetch should not block the cycle", elapsed)
+	}
+	if fast != "ok" {
+		t.Errorf("fast result = %q, want ok", fast)
+	}
+	if slow != "" {
+		t.Errorf("slow result should be dropped, got %q", slow)
+	}
+	if len(timedOut) != 1 || timedOut[0] != "slow" {
+		t.Errorf("timedOut = %v, want [slow]", timedOut)
+	}
+}
+
+func TestParallelFetcherRecordsFailedSources(t *testing.T) {
+	fetcher := newParallelFetcher(context.Background(), time.Second, logger.TraderEntry("", nil))
+
+	var ok, failed string
+	goFetchErr(fetcher, "ok", func() (string, error) { return "data", nil }, func(v string) { ok = v })
+	goFetchErr(fetcher, "down", func() (string, error) { return "partial", errors.New("503") }, func(v string) { failed = v })
+	fetcher.Wait()
+
+	if ok != "data" {
+		t.Errorf("ok result = %q, want data", ok)
+	}
+	if failed != "" {
+		t.Errorf("failed fetch should not be applied, got %q", failed)
+	}
+	if got := fetcher.Unavailable(); len(got) != 1 || got[0] != "down" {
+		t.Errorf("Unavailable() = %v, want [down]", got)
+	}
+}
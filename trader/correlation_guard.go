@@ -0,0 +1,54 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+)
+
+// correlationGroupContains reports whether symbol belongs to the group (symbols compared after normalization)
+func correlationGroupContains(group store.CorrelationGroup, symbol string) bool {
+	normalized := market.Normalize(symbol)
+	for _, s := range group.Symbols {
+		if market.Normalize(s) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// countGroupPositions counts open positions in the group on the given side ("long" / "short")
+func countGroupPositions(group store.CorrelationGroup, side string, positions []map[string]interface{}) int {
+	count := 0
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		posSide, _ := pos["side"].(string)
+		if posSide == side && correlationGroupContains(group, symbol) {
+			count++
+		}
+	}
+	return count
+}
+
+// checkCorrelationGroups returns an error if opening symbol on side would exceed any group's same-direction limit
+func checkCorrelationGroups(groups []store.CorrelationGroup, symbol, side string, positions []map[string]interface{}) error {
+	for _, group := range groups {
+		if group.MaxSameDirection <= 0 || !correlationGroupContains(group, symbol) {
+			continue
+		}
+		count := countGroupPositions(group, side, positions)
+		if count >= group.MaxSameDirection {
+			return fmt.Errorf("❌ [RISK CONTROL] Correlated group '%s' already has %d/%d %s positions, %s %s blocked",
+				group.Name, count, group.MaxSameDirection, side, side, symbol)
+		}
+	}
+	return nil
+}
+
+// enforceCorrelationGroupLimits rejects same-direction opens in a correlated group at its limit (CODE ENFORCED)
+func (at *AutoTrader) enforceCorrelationGroupLimits(symbol, side string, positions []map[string]interface{}) error {
+	if at.config.StrategyConfig == nil {
+		return nil
+	}
+	return checkCorrelationGroups(at.config.StrategyConfig.RiskControl.CorrelationGroups, symbol, side, positions)
+}
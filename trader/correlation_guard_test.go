@@ -0,0 +1,41 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/store"
+)
+
+func TestCheckCorrelationGroups(t *testing.T) {
+	groups := []store.CorrelationGroup{
+		{Name: "majors", Symbols: []string{"BTC", "ETHUSDT", "SOLUSDT"}, MaxSameDirection: 2},
+		{Name: "unlimited", Symbols: []string{"DOGEUSDT"}, MaxSameDirection: 0},
+	}
+	positions := []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long"},
+		{"symbol": "ETHUSDT", "side": "long"},
+		{"symbol": "SOLUSDT", "side": "short"},
+		{"symbol": "DOGEUSDT", "side": "long"},
+	}
+
+	tests := []struct {
+		name    string
+		symbol  string
+		side    string
+		wantErr bool
+	}{
+		{name: "group long limit reached", symbol: "SOLUSDT", side: "long", wantErr: true},
+		{name: "opposite direction allowed", symbol: "BTCUSDT", side: "short"},
+		{name: "symbol outside groups", symbol: "XRPUSDT", side: "long"},
+		{name: "group without limit", symbol: "DOGEUSDT", side: "long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCorrelationGroups(groups, tt.symbol, tt.side, positions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCorrelationGroups(%s, %s) error = %v, wantErr %v", tt.symbol, tt.side, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"math"
+	"strconv"
+)
+
+// MaxDisplayDecimals upper bound of a trader's quote display precision
+const MaxDisplayDecimals = 8
+
+// autoDisplayDecimals picks a precision from the account size so dust balances and micro-PnL stay visible
+func autoDisplayDecimals(equity float64) int {
+	switch abs := math.Abs(equity); {
+	case abs == 0 || abs >= 100:
+		return 2
+	case abs >= 1:
+		return 4
+	default:
+		return 6
+	}
+}
+
+// displayDecimals returns the configured quote precision, or one chosen from equity when unset (0)
+func (at *AutoTrader) displayDecimals(equity float64) int {
+	if d := at.config.DisplayDecimals; d > 0 {
+		return min(d, MaxDisplayDecimals)
+	}
+	return autoDisplayDecimals(equity)
+}
+
+// roundQuote rounds a quote-asset amount to decimals places
+func roundQuote(v float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(v*p) / p
+}
+
+// formatQuote formats a quote-asset amount with decimals places (for logs)
+func formatQuote(v float64, decimals int) string {
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
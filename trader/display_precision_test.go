@@ -0,0 +1,40 @@
+package trader
+
+import "testing"
+
+func TestDisplayDecimals(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured int
+		equity     float64
+		want       int
+	}{
+		{"auto large account", 0, 5000, 2},
+		{"auto small account", 0, 12.5, 4},
+		{"auto dust account", 0, 0.35, 6},
+		{"auto unknown equity", 0, 0, 2},
+		{"configured", 5, 5000, 5},
+		{"configured above max", 12, 5000, MaxDisplayDecimals},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := &AutoTrader{config: AutoTraderConfig{DisplayDecimals: tt.configured}}
+			if got := at.displayDecimals(tt.equity); got != tt.want {
+				t.Errorf("displayDecimals(%v) = %d, want %d", tt.equity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundQuote(t *testing.T) {
+	if got := roundQuote(0.0004321, 2); got != 0 {
+		t.Errorf("roundQuote(0.0004321, 2) = %v, want 0", got)
+	}
+	if got := roundQuote(0.0004321, 6); got != 0.000432 {
+		t.Errorf("roundQuote(0.0004321, 6) = %v, want 0.000432", got)
+	}
+	if got := formatQuote(-1.5, 4); got != "-1.5000" {
+		t.Errorf("formatQuote(-1.5, 4) = %q, want -1.5000", got)
+	}
+}
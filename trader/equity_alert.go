@@ -0,0 +1,70 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/store"
+)
+
+// defaultEquityAlertHysteresisPct a fired equity alert re-arms once equity moves back past the
+// threshold by this much (%), so equity hovering around the threshold doesn't repeat the alert
+const defaultEquityAlertHysteresisPct = 1.0
+
+// evaluateEquityAlert checks an alert rule against an equity reading.
+// fire: the threshold was crossed while armed; rearm: a fired alert recovered past the hysteresis band.
+func evaluateEquityAlert(alert *store.EquityAlert, equity float64) (fire, rearm bool) {
+	if equity <= 0 || alert.Threshold <= 0 {
+		return false, false
+	}
+	hysteresis := alert.HysteresisPct
+	if hysteresis <= 0 {
+		hysteresis = defaultEquityAlertHysteresisPct
+	}
+	band := alert.Threshold * hysteresis / 100
+
+	switch alert.Direction {
+	case store.EquityAlertBelow:
+		if !alert.Triggered {
+			return equity < alert.Threshold, false
+		}
+		return false, equity >= alert.Threshold+band
+	case store.EquityAlertAbove:
+		if !alert.Triggered {
+			return equity > alert.Threshold, false
+		}
+		return false, equity <= alert.Threshold-band
+	}
+	return false, false
+}
+
+// checkEquityAlerts evaluates the trader's equity alert rules and notifies on crossings
+func (at *AutoTrader) checkEquityAlerts(equity float64) {
+	if at.store == nil {
+		return
+	}
+	alerts, err := at.store.EquityAlert().ListEnabled(at.id)
+	if err != nil {
+		at.log().Infof("⚠️ Failed to load equity alerts: %v", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		fire, rearm := evaluateEquityAlert(alert, equity)
+		if !fire && !rearm {
+			continue
+		}
+		if err := at.store.EquityAlert().SetTriggered(alert.ID, fire); err != nil {
+			at.log().Infof("⚠️ Failed to update equity alert %d: %v", alert.ID, err)
+			continue
+		}
+		if rearm {
+			at.log().Infof("🔔 [%s] Equity alert #%d re-armed (equity %.2f)", at.name, alert.ID, equity)
+			continue
+		}
+
+		message := fmt.Sprintf("equity %.2f is %s the alert threshold %.2f", equity, alert.Direction, alert.Threshold)
+		if alert.Note != "" {
+			message += ": " + alert.Note
+		}
+		at.notify(NotificationEquityAlert, message)
+	}
+}
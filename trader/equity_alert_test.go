@@ -0,0 +1,39 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+)
+
+func TestEvaluateEquityAlert(t *testing.T) {
+	below := &store.EquityAlert{Direction: store.EquityAlertBelow, Threshold: 1000}
+	above := &store.EquityAlert{Direction: store.EquityAlertAbove, Threshold: 1000, HysteresisPct: 5}
+
+	tests := []struct {
+		name      string
+		alert     *store.EquityAlert
+		triggered bool
+		equity    float64
+		fire      bool
+		rearm     bool
+	}{
+		{"below armed, above threshold", below, false, 1005, false, false},
+		{"below armed, crosses", below, false, 999, true, false},
+		{"below fired, still inside band", below, true, 1005, false, false},
+		{"below fired, recovers past band", below, true, 1010, false, true},
+		{"above armed, crosses", above, false, 1001, true, false},
+		{"above fired, dips inside band", above, true, 960, false, false},
+		{"above fired, falls past band", above, true, 950, false, true},
+		{"no equity reading", below, false, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := *tt.alert
+			alert.Triggered = tt.triggered
+			fire, rearm := evaluateEquityAlert(&alert, tt.equity)
+			if fire != tt.fire || rearm != tt.rearm {
+				t.Errorf("evaluateEquityAlert(equity=%.0f) = fire %v, rearm %v; want %v, %v", tt.equity, fire, rearm, tt.fire, tt.rearm)
+			}
+		})
+	}
+}
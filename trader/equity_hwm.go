@@ -0,0 +1,94 @@
+package trader
+
+import (
+	"fmt"
+)
+
+// hwmNotifyStepPct a new high is only announced once it beats the last announced high by this much (%)
+const hwmNotifyStepPct = 1.0
+
+// equityTracker tracks the account equity high-water mark and drawdown from it
+type equityTracker struct {
+	hwm             float64 // Highest equity seen (0 = not seeded yet)
+	lastNotifiedHWM float64 // High-water mark of the last "new high" notification
+	drawdownPct     float64 // Current drawdown from the high-water mark (%)
+	drawdownAlerted bool    // Alert fired for the current drawdown, re-armed after recovery
+}
+
+// equityUpdate outcome of feeding one equity reading to the tracker
+type equityUpdate struct {
+	newHWM        bool // High-water mark moved up (needs persisting)
+	announceHigh  bool // New high worth notifying
+	drawdownAlert bool // Drawdown crossed the alert threshold
+}
+
+// update feeds an equity reading; alertPct <= 0 disables drawdown alerts.
+// The drawdown alert re-arms once drawdown recovers below half the threshold (hysteresis).
+func (t *equityTracker) update(equity, alertPct float64) equityUpdate {
+	var u equityUpdate
+	if equity <= 0 {
+		return u
+	}
+
+	if equity > t.hwm {
+		seeded := t.hwm > 0
+		t.hwm = equity
+		u.newHWM = true
+		if !seeded {
+			t.lastNotifiedHWM = equity
+		} else if equity >= t.lastNotifiedHWM*(1+hwmNotifyStepPct/100) {
+			t.lastNotifiedHWM = equity
+			u.announceHigh = true
+		}
+	}
+
+	t.drawdownPct = (t.hwm - equity) / t.hwm * 100
+	if alertPct > 0 {
+		if !t.drawdownAlerted && t.drawdownPct >= alertPct {
+			t.drawdownAlerted = true
+			u.drawdownAlert = true
+		} else if t.drawdownAlerted && t.drawdownPct < alertPct/2 {
+			t.drawdownAlerted = false
+		}
+	}
+	return u
+}
+
+// drawdownAlertPct returns the strategy's drawdown-from-HWM alert threshold (0 = disabled)
+func (at *AutoTrader) drawdownAlertPct() float64 {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.DrawdownAlertPct
+}
+
+// updateEquityHighWaterMark updates the high-water mark from an equity reading,
+// persists new highs and notifies on new highs / drawdown breaches
+func (at *AutoTrader) updateEquityHighWaterMark(equity float64) {
+	alertPct := at.drawdownAlertPct()
+
+	at.equityMu.Lock()
+	u := at.equity.update(equity, alertPct)
+	hwm, drawdown := at.equity.hwm, at.equity.drawdownPct
+	at.equityMu.Unlock()
+
+	if u.newHWM && at.store != nil {
+		if err := at.store.Trader().UpdateEquityHighWaterMark(at.id, hwm); err != nil {
+			at.log().Infof("⚠️ Failed to save equity high-water mark: %v", err)
+		}
+	}
+	if u.announceHigh {
+		at.notify(NotificationEquityHigh, fmt.Sprintf("new equity high %.2f", hwm))
+	}
+	if u.drawdownAlert {
+		at.notify(NotificationDrawdown, fmt.Sprintf("equity %.2f is %.2f%% below the high-water mark %.2f (alert at %.2f%%)",
+			equity, drawdown, hwm, alertPct))
+	}
+}
+
+// equityHighWaterMark returns the high-water mark, current drawdown (%) and whether the drawdown alert is active
+func (at *AutoTrader) equityHighWaterMark() (hwm, drawdownPct float64, alerted bool) {
+	at.equityMu.RLock()
+	defer at.equityMu.RUnlock()
+	return at.equity.hwm, at.equity.drawdownPct, at.equity.drawdownAlerted
+}
@@ -0,0 +1,64 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEquityTrackerHighWaterMark(t *testing.T) {
+	var tr equityTracker
+
+	u := tr.update(1000, 10)
+	if !u.newHWM || u.announceHigh {
+		t.Fatalf("first reading should seed the HWM without announcing, got %+v", u)
+	}
+
+	// Below the notify step: new HWM persisted but not announced
+	if u = tr.update(1005, 10); !u.newHWM || u.announceHigh {
+		t.Fatalf("small new high: got %+v", u)
+	}
+	if u = tr.update(1020, 10); !u.announceHigh {
+		t.Fatalf("new high beyond notify step should be announced, got %+v", u)
+	}
+	if tr.hwm != 1020 {
+		t.Fatalf("hwm = %v, want 1020", tr.hwm)
+	}
+
+	if u = tr.update(-1, 10); u != (equityUpdate{}) || tr.hwm != 1020 {
+		t.Fatalf("non-positive equity should be ignored, got %+v hwm=%v", u, tr.hwm)
+	}
+}
+
+func TestEquityTrackerDrawdownAlert(t *testing.T) {
+	tr := equityTracker{hwm: 1000, lastNotifiedHWM: 1000}
+
+	if u := tr.update(950, 10); u.drawdownAlert {
+		t.Fatal("5% drawdown should not alert at 10% threshold")
+	}
+	if math.Abs(tr.drawdownPct-5) > 1e-9 {
+		t.Fatalf("drawdownPct = %v, want 5", tr.drawdownPct)
+	}
+	if u := tr.update(890, 10); !u.drawdownAlert {
+		t.Fatal("11% drawdown should alert")
+	}
+	if u := tr.update(880, 10); u.drawdownAlert {
+		t.Fatal("alert should fire once per breach")
+	}
+	// Recovery above half the threshold keeps the alert latched
+	tr.update(940, 10)
+	if !tr.drawdownAlerted {
+		t.Fatal("alert should stay latched at 6% drawdown")
+	}
+	// Recovery below half the threshold re-arms it
+	tr.update(960, 10)
+	if tr.drawdownAlerted {
+		t.Fatal("alert should re-arm after recovering below half the threshold")
+	}
+	if u := tr.update(880, 10); !u.drawdownAlert {
+		t.Fatal("re-armed alert should fire again")
+	}
+
+	if u := (&equityTracker{hwm: 1000}).update(500, 0); u.drawdownAlert {
+		t.Fatal("zero threshold disables alerts")
+	}
+}
@@ -0,0 +1,143 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+)
+
+// ErrUnsupportedExchange returned by NewTraderFromExchangeConfig for unknown exchange types
+var ErrUnsupportedExchange = errors.New("unsupported exchange type")
+
+// SupportedExchangeTypes exchange types NewTraderFromExchangeConfig can construct
+var SupportedExchangeTypes = []string{
+	"binance", "bybit", "okx", "bitget", "hyperliquid", "aster", "lighter", "gateio", "coinbase", "backpack",
+}
+
+// IsSupportedExchange reports whether exchangeType can be constructed
+func IsSupportedExchange(exchangeType string) bool {
+	for _, t := range SupportedExchangeTypes {
+		if t == exchangeType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTraderFromExchangeConfig creates the exchange client of an exchange account config.
+// This is the single place exchange types are mapped to their implementations: running
+// traders, balance queries, credential tests and manual position operations all use it.
+// Additional API keys of the account are handed to clients that can rotate them (MultiKeyTrader),
+// IOC slippage settings to DEX clients that price market orders as aggressive limits (SlippageConfigurable).
+func NewTraderFromExchangeConfig(cfg *store.Exchange, userID string) (Trader, error) {
+	t, err := newExchangeTrader(cfg, userID)
+	if err != nil {
+		return nil, err
+	}
+	if keys := cfg.AdditionalAPIKeys(); len(keys) > 0 {
+		if mk, ok := t.(MultiKeyTrader); ok {
+			mk.AddAPIKeys(keys)
+			logger.Infof("🔑 %s: rotating requests across %d API keys", cfg.ExchangeType, len(keys)+1)
+		} else {
+			logger.Warnf("⚠️ %s does not support API key rotation, %d additional keys ignored", cfg.ExchangeType, len(keys))
+		}
+	}
+	if slippage := cfg.SlippageSettings(); !slippage.IsZero() {
+		if sc, ok := t.(SlippageConfigurable); ok {
+			sc.SetSlippage(slippage)
+		} else {
+			logger.Warnf("⚠️ %s does not use IOC slippage settings, ignored", cfg.ExchangeType)
+		}
+	}
+	return t, nil
+}
+
+func newExchangeTrader(cfg *store.Exchange, userID string) (Trader, error) {
+	switch cfg.ExchangeType {
+	case "binance":
+		return NewFuturesTrader(string(cfg.APIKey), string(cfg.SecretKey), userID, cfg.Testnet), nil
+	case "bybit":
+		return NewBybitTrader(string(cfg.APIKey), string(cfg.SecretKey), cfg.Testnet), nil
+	case "okx":
+		return NewOKXTrader(string(cfg.APIKey), string(cfg.SecretKey), string(cfg.Passphrase), cfg.Testnet), nil
+	case "bitget":
+		return NewBitgetTrader(string(cfg.APIKey), string(cfg.SecretKey), string(cfg.Passphrase), cfg.Testnet), nil
+	case "hyperliquid":
+		// The API key field holds the agent wallet private key
+		t, err := NewHyperliquidTrader(string(cfg.APIKey), cfg.HyperliquidWalletAddr, cfg.Testnet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Hyperliquid trader: %w", err)
+		}
+		return t, nil
+	case "aster":
+		t, err := NewAsterTrader(cfg.AsterUser, cfg.AsterSigner, string(cfg.AsterPrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Aster trader: %w", err)
+		}
+		return t, nil
+	case "lighter":
+		if cfg.LighterWalletAddr == "" || string(cfg.LighterAPIKeyPrivateKey) == "" {
+			return nil, fmt.Errorf("Lighter requires wallet address and API Key private key")
+		}
+		// Lighter only supports mainnet (testnet disabled)
+		t, err := NewLighterTraderV2(cfg.LighterWalletAddr, string(cfg.LighterAPIKeyPrivateKey), cfg.LighterAPIKeyIndex, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize LIGHTER trader: %w", err)
+		}
+		return t, nil
+	case "gateio":
+		return NewGateTrader(string(cfg.APIKey), string(cfg.SecretKey)), nil
+	case "coinbase":
+		return NewCoinbaseTrader(string(cfg.APIKey), string(cfg.SecretKey), string(cfg.Passphrase)), nil
+	case "backpack":
+		return NewBackpackTrader(string(cfg.APIKey), string(cfg.SecretKey)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExchange, cfg.ExchangeType)
+	}
+}
+
+// exchangeConfig maps the per-exchange credential fields of the config back to an exchange account config
+func (config *AutoTraderConfig) exchangeConfig() *store.Exchange {
+	cfg := &store.Exchange{ID: config.ExchangeID, ExchangeType: config.Exchange, ExtraAPIKeys: store.EncodeAPIKeys(config.ExtraAPIKeys),
+		Slippage: store.EncodeSlippage(config.Slippage)}
+	switch config.Exchange {
+	case "binance":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.BinanceAPIKey), crypto.EncryptedString(config.BinanceSecretKey)
+		cfg.Testnet = config.BinanceTestnet
+	case "bybit":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.BybitAPIKey), crypto.EncryptedString(config.BybitSecretKey)
+		cfg.Testnet = config.BybitTestnet
+	case "okx":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.OKXAPIKey), crypto.EncryptedString(config.OKXSecretKey)
+		cfg.Passphrase = crypto.EncryptedString(config.OKXPassphrase)
+		cfg.Testnet = config.OKXTestnet
+	case "bitget":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.BitgetAPIKey), crypto.EncryptedString(config.BitgetSecretKey)
+		cfg.Passphrase = crypto.EncryptedString(config.BitgetPassphrase)
+		cfg.Testnet = config.BitgetTestnet
+	case "hyperliquid":
+		cfg.APIKey = crypto.EncryptedString(config.HyperliquidPrivateKey)
+		cfg.HyperliquidWalletAddr = config.HyperliquidWalletAddr
+		cfg.Testnet = config.HyperliquidTestnet
+	case "aster":
+		cfg.AsterUser, cfg.AsterSigner = config.AsterUser, config.AsterSigner
+		cfg.AsterPrivateKey = crypto.EncryptedString(config.AsterPrivateKey)
+	case "lighter":
+		cfg.LighterWalletAddr = config.LighterWalletAddr
+		cfg.LighterPrivateKey = crypto.EncryptedString(config.LighterPrivateKey)
+		cfg.LighterAPIKeyPrivateKey = crypto.EncryptedString(config.LighterAPIKeyPrivateKey)
+		cfg.LighterAPIKeyIndex = config.LighterAPIKeyIndex
+		cfg.Testnet = config.LighterTestnet
+	case "gateio":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.GateAPIKey), crypto.EncryptedString(config.GateSecretKey)
+	case "coinbase":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.CoinbaseAPIKey), crypto.EncryptedString(config.CoinbaseSecretKey)
+		cfg.Passphrase = crypto.EncryptedString(config.CoinbasePassphrase)
+	case "backpack":
+		cfg.APIKey, cfg.SecretKey = crypto.EncryptedString(config.BackpackAPIKey), crypto.EncryptedString(config.BackpackSecretKey)
+	}
+	return cfg
+}
@@ -0,0 +1,83 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+
+	"nofx/store"
+)
+
+const testPrivateKeyHex = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestNewTraderFromExchangeConfig(t *testing.T) {
+	configs := map[string]*store.Exchange{
+		"binance":  {ExchangeType: "binance", APIKey: "key", SecretKey: "secret"},
+		"bybit":    {ExchangeType: "bybit", APIKey: "key", SecretKey: "secret"},
+		"okx":      {ExchangeType: "okx", APIKey: "key", SecretKey: "secret", Passphrase: "pass"},
+		"bitget":   {ExchangeType: "bitget", APIKey: "key", SecretKey: "secret", Passphrase: "pass"},
+		"aster":    {ExchangeType: "aster", AsterUser: "0x1", AsterSigner: "0x2", AsterPrivateKey: testPrivateKeyHex},
+		"gateio":   {ExchangeType: "gateio", APIKey: "key", SecretKey: "secret"},
+		"coinbase": {ExchangeType: "coinbase", APIKey: "key", SecretKey: "c2VjcmV0", Passphrase: "pass"},
+		"backpack": {ExchangeType: "backpack", APIKey: "key", SecretKey: "secret"},
+		// Constructors that need the exchange to start: only check the config reaches them
+		"hyperliquid": {ExchangeType: "hyperliquid", APIKey: "not-a-key", HyperliquidWalletAddr: "0x1"},
+		"lighter":     {ExchangeType: "lighter", LighterWalletAddr: "0x1"},
+	}
+	needsNetwork := map[string]bool{"hyperliquid": true, "lighter": true}
+
+	for _, exchangeType := range SupportedExchangeTypes {
+		t.Run(exchangeType, func(t *testing.T) {
+			cfg, ok := configs[exchangeType]
+			if !ok {
+				t.Fatalf("no test config for supported exchange %s", exchangeType)
+			}
+			tr, err := NewTraderFromExchangeConfig(cfg, "u1")
+			if errors.Is(err, ErrUnsupportedExchange) {
+				t.Fatalf("supported exchange %s not handled by the factory", exchangeType)
+			}
+			if needsNetwork[exchangeType] {
+				if err == nil {
+					t.Errorf("expected invalid %s credentials to be rejected", exchangeType)
+				}
+				return
+			}
+			if err != nil || tr == nil {
+				t.Fatalf("NewTraderFromExchangeConfig(%s) = %v, %v", exchangeType, tr, err)
+			}
+		})
+	}
+}
+
+func TestNewTraderFromExchangeConfigUnsupported(t *testing.T) {
+	_, err := NewTraderFromExchangeConfig(&store.Exchange{ExchangeType: "mtgox"}, "u1")
+	if !errors.Is(err, ErrUnsupportedExchange) {
+		t.Errorf("got %v, want ErrUnsupportedExchange", err)
+	}
+	if IsSupportedExchange("mtgox") || !IsSupportedExchange("gateio") {
+		t.Error("IsSupportedExchange disagrees with SupportedExchangeTypes")
+	}
+}
+
+func TestAutoTraderConfigExchangeConfig(t *testing.T) {
+	cfg := (&AutoTraderConfig{
+		Exchange: "bybit", ExchangeID: "ex1",
+		BybitAPIKey: "key", BybitSecretKey: "secret", BybitTestnet: true,
+	}).exchangeConfig()
+	if cfg.ID != "ex1" || cfg.ExchangeType != "bybit" || cfg.APIKey != "key" || cfg.SecretKey != "secret" || !cfg.Testnet {
+		t.Errorf("bybit exchange config = %+v", cfg)
+	}
+
+	cfg = (&AutoTraderConfig{
+		Exchange: "okx", OKXAPIKey: "key", OKXSecretKey: "secret", OKXPassphrase: "pass", OKXTestnet: true,
+	}).exchangeConfig()
+	if cfg.Passphrase != "pass" || !cfg.Testnet {
+		t.Errorf("okx exchange config = %+v", cfg)
+	}
+
+	cfg = (&AutoTraderConfig{
+		Exchange: "hyperliquid", HyperliquidPrivateKey: "pk", HyperliquidWalletAddr: "0xabc",
+	}).exchangeConfig()
+	if cfg.APIKey != "pk" || cfg.HyperliquidWalletAddr != "0xabc" {
+		t.Errorf("hyperliquid exchange config = %+v", cfg)
+	}
+}
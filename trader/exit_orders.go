@@ -0,0 +1,137 @@
+package trader
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Exit order types (risk_control.exit_order_type)
+const (
+	ExitOrderMarket = "market" // Market order when the trigger price is hit
+	ExitOrderLimit  = "limit"  // Limit order at an offset beyond the trigger price
+)
+
+// defaultExitLimitOffsetPct limit offset from the trigger when exit_limit_offset_pct is not set
+const defaultExitLimitOffsetPct = 0.2
+
+// LimitExitOrderSetter is implemented by exchanges that support stop-limit / take-profit-limit orders.
+// Exchanges without it fall back to market triggers (SetStopLoss / SetTakeProfit).
+type LimitExitOrderSetter interface {
+	// SetStopLossLimit places a stop-limit order: when stopPrice triggers, a reduce-only limit order at limitPrice is placed
+	SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error
+	// SetTakeProfitLimit places a take-profit-limit order: when takeProfitPrice triggers, a reduce-only limit order at limitPrice is placed
+	SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error
+}
+
+// exitLimitPrice returns the limit price of an exit order triggered at triggerPrice.
+// The limit sits offsetPct beyond the trigger in the direction of the close (lower for a long's sell,
+// higher for a short's buy) so the order still fills when price moves through the trigger.
+func exitLimitPrice(positionSide string, triggerPrice, offsetPct float64) float64 {
+	if positionSide == "LONG" {
+		return triggerPrice * (1 - offsetPct/100)
+	}
+	return triggerPrice * (1 + offsetPct/100)
+}
+
+// formatLimitPrice rounds a limit price to the tick size and formats it with the tick's decimals.
+// Buys round up and sells round down, so rounding never makes the limit less marketable.
+func formatLimitPrice(price, tickSize float64, roundUp bool) string {
+	if tickSize <= 0 {
+		return strconv.FormatFloat(price, 'f', -1, 64)
+	}
+	steps := price / tickSize
+	if roundUp {
+		steps = math.Ceil(steps - 1e-9)
+	} else {
+		steps = math.Floor(steps + 1e-9)
+	}
+	decimals := 0
+	if tick := strconv.FormatFloat(tickSize, 'f', -1, 64); strings.Contains(tick, ".") {
+		decimals = len(tick) - strings.Index(tick, ".") - 1
+	}
+	return strconv.FormatFloat(steps*tickSize, 'f', decimals, 64)
+}
+
+// exitOrderSettings returns the configured exit order type and limit offset
+func (at *AutoTrader) exitOrderSettings() (orderType string, offsetPct float64) {
+	if at.config.StrategyConfig == nil {
+		return ExitOrderMarket, 0
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if !strings.EqualFold(rc.ExitOrderType, ExitOrderLimit) {
+		return ExitOrderMarket, 0
+	}
+	offsetPct = rc.ExitLimitOffsetPct
+	if offsetPct <= 0 {
+		offsetPct = defaultExitLimitOffsetPct
+	}
+	return ExitOrderLimit, offsetPct
+}
+
+// placeStopLoss places a stop loss with the configured exit order type and returns the type actually used.
+// Limit exits fall back to a market trigger when the exchange lacks stop-limit support or rejects the order.
+func (at *AutoTrader) placeStopLoss(symbol, positionSide string, quantity, stopPrice float64) (string, error) {
+	at.enforceOpenOrderLimit(symbol, positionSide, "stop_loss")
+	if orderType, offsetPct := at.exitOrderSettings(); orderType == ExitOrderLimit {
+		if setter, ok := capableTrader[LimitExitOrderSetter](at.trader); ok {
+			limitPrice := exitLimitPrice(positionSide, stopPrice, offsetPct)
+			err := setter.SetStopLossLimit(symbol, positionSide, quantity, stopPrice, limitPrice)
+			if err == nil {
+				return ExitOrderLimit, nil
+			}
+			at.log().Infof("  ⚠ Stop-limit failed for %s %s, falling back to market trigger: %v", symbol, positionSide, err)
+		} else {
+			at.log().Infof("  ⚠ %s does not support stop-limit orders, using market trigger", at.exchange)
+		}
+	}
+	if err := at.trader.SetStopLoss(symbol, positionSide, quantity, stopPrice); err != nil {
+		return "", err
+	}
+	return ExitOrderMarket, nil
+}
+
+// placeTakeProfit places a take profit with the configured exit order type and returns the type actually used.
+// Limit exits fall back to a market trigger when the exchange lacks take-profit-limit support or rejects the order.
+func (at *AutoTrader) placeTakeProfit(symbol, positionSide string, quantity, takeProfitPrice float64) (string, error) {
+	return at.placeTakeProfitWith(symbol, positionSide, quantity, takeProfitPrice, at.trader.SetTakeProfit)
+}
+
+// placeTakeProfitWith places a take profit like placeTakeProfit, using setMarket for the market trigger
+// (a take-profit ladder passes a partial setter so a level never closes the whole position)
+func (at *AutoTrader) placeTakeProfitWith(symbol, positionSide string, quantity, takeProfitPrice float64,
+	setMarket func(symbol, positionSide string, quantity, takeProfitPrice float64) error) (string, error) {
+	at.enforceOpenOrderLimit(symbol, positionSide, "take_profit")
+	if orderType, offsetPct := at.exitOrderSettings(); orderType == ExitOrderLimit {
+		if setter, ok := capableTrader[LimitExitOrderSetter](at.trader); ok {
+			limitPrice := exitLimitPrice(positionSide, takeProfitPrice, offsetPct)
+			err := setter.SetTakeProfitLimit(symbol, positionSide, quantity, takeProfitPrice, limitPrice)
+			if err == nil {
+				return ExitOrderLimit, nil
+			}
+			at.log().Infof("  ⚠ Take-profit-limit failed for %s %s, falling back to market trigger: %v", symbol, positionSide, err)
+		} else {
+			at.log().Infof("  ⚠ %s does not support take-profit-limit orders, using market trigger", at.exchange)
+		}
+	}
+	if err := setMarket(symbol, positionSide, quantity, takeProfitPrice); err != nil {
+		return "", err
+	}
+	return ExitOrderMarket, nil
+}
+
+// mergeExitOrderTypes combines the order types used for a position's stop loss and take profits
+// into the value recorded on the decision ("mixed" when a fallback made them differ)
+func mergeExitOrderTypes(types ...string) string {
+	merged := ""
+	for _, t := range types {
+		switch {
+		case t == "":
+		case merged == "":
+			merged = t
+		case merged != t:
+			return "mixed"
+		}
+	}
+	return merged
+}
@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExitLimitPrice(t *testing.T) {
+	// Long exits sell below the trigger, short exits buy above it
+	if got := exitLimitPrice("LONG", 100, 0.5); math.Abs(got-99.5) > 1e-9 {
+		t.Errorf("long limit = %v, want 99.5", got)
+	}
+	if got := exitLimitPrice("SHORT", 100, 0.5); math.Abs(got-100.5) > 1e-9 {
+		t.Errorf("short limit = %v, want 100.5", got)
+	}
+}
+
+func TestFormatLimitPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    float64
+		tickSize float64
+		roundUp  bool
+		want     string
+	}{
+		{"sell rounds down", 99.456, 0.01, false, "99.45"},
+		{"buy rounds up", 99.451, 0.01, true, "99.46"},
+		{"already on tick", 99.45, 0.01, true, "99.45"},
+		{"integer tick", 64321.7, 1, false, "64321"},
+		{"half tick", 1.2345, 0.5, true, "1.5"},
+		{"small tick", 0.0123456, 0.00001, false, "0.01234"},
+		{"no tick size", 1.23456, 0, false, "1.23456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatLimitPrice(tt.price, tt.tickSize, tt.roundUp); got != tt.want {
+				t.Errorf("formatLimitPrice(%v, %v, %v) = %s, want %s", tt.price, tt.tickSize, tt.roundUp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeExitOrderTypes(t *testing.T) {
+	tests := []struct {
+		types []string
+		want  string
+	}{
+		{[]string{ExitOrderLimit, ExitOrderLimit}, ExitOrderLimit},
+		{[]string{ExitOrderMarket, ""}, ExitOrderMarket},
+		{[]string{"", ""}, ""},
+		{[]string{ExitOrderLimit, ExitOrderMarket}, "mixed"},
+	}
+
+	for _, tt := range tests {
+		if got := mergeExitOrderTypes(tt.types...); got != tt.want {
+			t.Errorf("mergeExitOrderTypes(%v) = %q, want %q", tt.types, got, tt.want)
+		}
+	}
+}
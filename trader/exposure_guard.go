@@ -0,0 +1,52 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+)
+
+// directionalNotional sums the notional value (quantity × mark price) of long and short positions
+func directionalNotional(positions []map[string]interface{}) (long, short float64) {
+	for _, pos := range positions {
+		side, _ := pos["side"].(string)
+		quantity, _ := pos["positionAmt"].(float64)
+		markPrice, _ := pos["markPrice"].(float64)
+		notional := math.Abs(quantity) * markPrice
+		switch side {
+		case "long":
+			long += notional
+		case "short":
+			short += notional
+		}
+	}
+	return long, short
+}
+
+// checkNetDirectionalExposure returns an error if opening sizeUSD on side ("long" / "short") would push
+// the net exposure in that direction beyond limitPct of equity. Opens that reduce it are always allowed.
+func checkNetDirectionalExposure(positions []map[string]interface{}, symbol, side string, sizeUSD, equity, limitPct float64) error {
+	if limitPct <= 0 || equity <= 0 {
+		return nil
+	}
+
+	long, short := directionalNotional(positions)
+	net := short - long
+	if side == "long" {
+		net = long - short
+	}
+	netPct := (net + sizeUSD) / equity * 100
+	if netPct > limitPct {
+		return fmt.Errorf("❌ [RISK CONTROL] Net %s exposure would reach %.1f%% of equity (long %.2f / short %.2f + %.2f), limit %.1f%%, %s %s blocked",
+			side, netPct, long, short, sizeUSD, limitPct, side, symbol)
+	}
+	return nil
+}
+
+// enforceNetDirectionalExposure rejects opens that would make the account too directional (CODE ENFORCED)
+func (at *AutoTrader) enforceNetDirectionalExposure(symbol, side string, positions []map[string]interface{}, sizeUSD, equity float64) error {
+	if at.config.StrategyConfig == nil {
+		return nil
+	}
+	limit := at.config.StrategyConfig.RiskControl.MaxNetDirectionalExposurePct
+	return checkNetDirectionalExposure(positions, symbol, side, sizeUSD, equity, limit)
+}
@@ -0,0 +1,35 @@
+package trader
+
+import "testing"
+
+func TestCheckNetDirectionalExposure(t *testing.T) {
+	// 1000 equity: 1200 long (BTC + ETH), 300 short (SOL) -> net long 90%
+	positions := []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.01, "markPrice": 80000.0},
+		{"symbol": "ETHUSDT", "side": "long", "positionAmt": 0.1, "markPrice": 4000.0},
+		{"symbol": "SOLUSDT", "side": "short", "positionAmt": -2.0, "markPrice": 150.0},
+	}
+
+	tests := []struct {
+		name     string
+		side     string
+		sizeUSD  float64
+		limitPct float64
+		wantErr  bool
+	}{
+		{name: "long within limit", side: "long", sizeUSD: 100, limitPct: 100},
+		{name: "long beyond limit", side: "long", sizeUSD: 200, limitPct: 100, wantErr: true},
+		{name: "short flips net long within limit", side: "short", sizeUSD: 1500, limitPct: 100},
+		{name: "short beyond limit on the other side", side: "short", sizeUSD: 2000, limitPct: 100, wantErr: true},
+		{name: "disabled", side: "long", sizeUSD: 10000, limitPct: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNetDirectionalExposure(positions, "XRPUSDT", tt.side, tt.sizeUSD, 1000, tt.limitPct)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkNetDirectionalExposure(%s, %.0f) error = %v, wantErr %v", tt.side, tt.sizeUSD, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// StopAndFlatten stops the trader, then market-closes all open positions and cancels pending orders,
+// so nothing stays live without the AI managing it. Closes are recorded as orders/fills like AI closes.
+// Plain Stop() never touches positions: it also runs on server shutdown, where positions must survive.
+func (at *AutoTrader) StopAndFlatten() error {
+	at.Stop()
+	return at.FlattenPositions()
+}
+
+// FlattenPositions market-closes every open position and cancels all pending orders of the trader
+func (at *AutoTrader) FlattenPositions() error {
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	at.log().Infof("🧹 [%s] Flattening %d open positions", at.name, len(positions))
+	var failed []string
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		amt, _ := pos["positionAmt"].(float64)
+		quantity := math.Abs(amt)
+		if symbol == "" || quantity == 0 {
+			continue
+		}
+		entryPrice, _ := pos["entryPrice"].(float64)
+		markPrice, _ := pos["markPrice"].(float64)
+
+		// Cancel SL/TP and other pending orders first so nothing reopens or fires after the close
+		if err := at.trader.CancelAllOrders(symbol); err != nil {
+			at.log().Infof("  ⚠️ Failed to cancel pending orders for %s: %v", symbol, err)
+		}
+
+		if err := at.closeFullPosition(symbol, side, quantity, markPrice, entryPrice); err != nil {
+			at.log().Infof("  ❌ Failed to close %s %s: %v", symbol, side, err)
+			failed = append(failed, symbol+" "+side)
+			continue
+		}
+
+		at.ClearPeakPnLCache(symbol, strings.ToLower(side))
+		at.log().Infof("  ✓ Closed %s %s (qty %.6f)", symbol, side, quantity)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to close: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
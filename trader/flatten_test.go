@@ -0,0 +1,82 @@
+package trader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// flattenTestTrader records closes and cancels; unused Trader methods panic via the nil embedded interface
+type flattenTestTrader struct {
+	Trader
+	positions []map[string]interface{}
+	failClose string
+	closed    []string
+	cancelled []string
+}
+
+func (f *flattenTestTrader) GetPositions() ([]map[string]interface{}, error) {
+	return f.positions, nil
+}
+
+func (f *flattenTestTrader) CancelAllOrders(symbol string) error {
+	f.cancelled = append(f.cancelled, symbol)
+	return nil
+}
+
+func (f *flattenTestTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return f.close(symbol, "long")
+}
+
+func (f *flattenTestTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return f.close(symbol, "short")
+}
+
+func (f *flattenTestTrader) close(symbol, side string) (map[string]interface{}, error) {
+	if symbol == f.failClose {
+		return nil, fmt.Errorf("rejected")
+	}
+	f.closed = append(f.closed, symbol+" "+side)
+	return map[string]interface{}{"orderId": int64(1)}, nil
+}
+
+func TestFlattenPositions(t *testing.T) {
+	ft := &flattenTestTrader{
+		positions: []map[string]interface{}{
+			{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5},
+			{"symbol": "ETHUSDT", "side": "short", "positionAmt": -2.0},
+			{"symbol": "SOLUSDT", "side": "long", "positionAmt": 0.0}, // Already closed
+		},
+	}
+	at := &AutoTrader{name: "test", trader: ft}
+
+	if err := at.FlattenPositions(); err != nil {
+		t.Fatalf("FlattenPositions() error = %v", err)
+	}
+	if got := strings.Join(ft.closed, ","); got != "BTCUSDT long,ETHUSDT short" {
+		t.Errorf("closed = %q", got)
+	}
+	if got := strings.Join(ft.cancelled, ","); got != "BTCUSDT,ETHUSDT" {
+		t.Errorf("cancelled = %q", got)
+	}
+}
+
+func TestFlattenPositionsReportsFailures(t *testing.T) {
+	ft := &flattenTestTrader{
+		positions: []map[string]interface{}{
+			{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5},
+			{"symbol": "ETHUSDT", "side": "short", "positionAmt": -2.0},
+		},
+		failClose: "BTCUSDT",
+	}
+	at := &AutoTrader{name: "test", trader: ft}
+
+	err := at.FlattenPositions()
+	if err == nil || !strings.Contains(err.Error(), "BTCUSDT long") {
+		t.Fatalf("FlattenPositions() error = %v, want BTCUSDT failure", err)
+	}
+	// A failed close must not stop the remaining positions from being closed
+	if len(ft.closed) != 1 || ft.closed[0] != "ETHUSDT short" {
+		t.Errorf("closed = %v", ft.closed)
+	}
+}
@@ -0,0 +1,136 @@
+package trader
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+)
+
+// FundingPayment a perpetual funding fee received (positive amount) or paid (negative amount)
+type FundingPayment struct {
+	ID     string // Exchange record ID
+	Symbol string
+	Asset  string
+	Amount float64
+	Time   time.Time
+}
+
+// FundingHistoryProvider is implemented by exchanges that expose the account's funding fee history
+type FundingHistoryProvider interface {
+	// GetFundingPayments returns the funding payments of the given symbols settled since the given time
+	GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error)
+}
+
+// fundingReconcileFloor returns the time funding payments are imported from: the latest recorded
+// payment, or the trader's creation when none is recorded yet
+func fundingReconcileFloor(st *store.Store, traderID string) (time.Time, error) {
+	var floor time.Time
+	if t, err := st.Trader().GetByID(traderID); err == nil && t != nil {
+		floor = t.CreatedAt
+	}
+	latest, err := st.FundingPayment().LatestTime(traderID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest funding payment: %w", err)
+	}
+	if latestTime := time.UnixMilli(latest); latest > 0 && latestTime.After(floor) {
+		floor = latestTime
+	}
+	return floor, nil
+}
+
+// reconcileFunding records the funding payments of symbols held since the floor that are not
+// stored yet, returns the number of payments recorded and their net amount
+func reconcileFunding(provider FundingHistoryProvider, st *store.Store, traderID string) (int, float64, error) {
+	floor, err := fundingReconcileFloor(st, traderID)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Funding only settles on held positions: symbols still open, or closed after the floor
+	symbols, err := st.Position().GetSymbolsHeldSince(traderID, floor.UnixMilli())
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(symbols) == 0 {
+		return 0, 0, nil
+	}
+
+	payments, err := provider.GetFundingPayments(symbols, floor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get funding history: %w", err)
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Time.Before(payments[j].Time) })
+
+	recorded, net := 0, 0.0
+	for _, p := range payments {
+		// Payments at the floor itself may already be recorded, the external ID check deduplicates them
+		if p.ID == "" || p.Amount == 0 || p.Time.Before(floor) {
+			continue
+		}
+		exists, err := st.FundingPayment().ExistsExternal(traderID, p.ID)
+		if err != nil {
+			return recorded, net, fmt.Errorf("failed to check funding payment %s: %w", p.ID, err)
+		}
+		if exists {
+			continue
+		}
+		if err := st.FundingPayment().Create(&store.FundingPayment{
+			TraderID:   traderID,
+			Symbol:     p.Symbol,
+			Asset:      p.Asset,
+			Amount:     p.Amount,
+			ExternalID: p.ID,
+			CreatedAt:  p.Time.UnixMilli(),
+		}); err != nil {
+			return recorded, net, err
+		}
+		recorded++
+		net += p.Amount
+	}
+	return recorded, net, nil
+}
+
+// startFundingReconcileLoop periodically imports the exchange's funding payments so PnL includes them
+func startFundingReconcileLoop(name string, provider FundingHistoryProvider, traderID string, st *store.Store,
+	interval time.Duration, stopCh <-chan struct{}) {
+	log := logger.TraderEntry(traderID, nil)
+	reconcile := func() {
+		n, net, err := reconcileFunding(provider, st, traderID)
+		if err != nil {
+			log.Warnf("⚠️ [%s] Funding reconcile failed: %v", name, err)
+			return
+		}
+		if n > 0 {
+			log.Infof("💰 [%s] Recorded %d funding payment(s), net %+.4f", name, n, net)
+		}
+	}
+
+	go func() {
+		reconcile()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reconcile()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// netFunding returns the trader's recorded net funding payments (0 without a store)
+func netFunding(st *store.Store, traderID string) float64 {
+	if st == nil {
+		return 0
+	}
+	total, err := st.FundingPayment().NetFunding(traderID)
+	if err != nil {
+		return 0
+	}
+	return total
+}
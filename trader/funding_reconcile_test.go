@@ -0,0 +1,105 @@
+package trader
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+type fakeFundingProvider struct {
+	payments []FundingPayment
+	symbols  []string
+	since    time.Time
+}
+
+func (p *fakeFundingProvider) GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error) {
+	p.symbols, p.since = symbols, since
+	return p.payments, nil
+}
+
+func TestReconcileFunding(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "funding.db"))
+	if err != nil {
+		t.Fatalf("Failed to init test store: %v", err)
+	}
+	defer st.Close()
+
+	provider := &fakeFundingProvider{}
+	if n, _, err := reconcileFunding(provider, st, "trader-1"); err != nil || n != 0 || provider.symbols != nil {
+		t.Fatalf("reconcileFunding() without positions = %d, %v (queried %v), want no query", n, err, provider.symbols)
+	}
+
+	if err := st.Position().Create(&store.TraderPosition{
+		TraderID: "trader-1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100,
+		EntryTime: time.Now().Add(-2 * time.Hour).UnixMilli(), Status: "OPEN",
+	}); err != nil {
+		t.Fatalf("Create position error = %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	provider.payments = []FundingPayment{
+		{ID: "f1", Symbol: "BTCUSDT", Asset: "USDT", Amount: -1.5, Time: base},
+		{ID: "f2", Symbol: "BTCUSDT", Asset: "USDT", Amount: 0.25, Time: base.Add(8 * time.Minute)},
+		{ID: "f3", Symbol: "BTCUSDT", Asset: "USDT", Amount: 0, Time: base.Add(16 * time.Minute)},
+	}
+	n, net, err := reconcileFunding(provider, st, "trader-1")
+	if err != nil {
+		t.Fatalf("reconcileFunding() error = %v", err)
+	}
+	if n != 2 || math.Abs(net+1.25) > 1e-9 {
+		t.Fatalf("recorded %d payments net %v, want 2 net -1.25", n, net)
+	}
+	if len(provider.symbols) != 1 || provider.symbols[0] != "BTCUSDT" {
+		t.Errorf("queried symbols %v, want the held BTCUSDT", provider.symbols)
+	}
+	if got := netFunding(st, "trader-1"); math.Abs(got+1.25) > 1e-9 {
+		t.Errorf("net funding = %v, want -1.25", got)
+	}
+
+	// Second run resumes from the latest payment and doesn't record duplicates
+	if n, _, err := reconcileFunding(provider, st, "trader-1"); err != nil || n != 0 {
+		t.Fatalf("second reconcileFunding() = %d, %v, want 0 new", n, err)
+	}
+	if !provider.since.Equal(base.Add(8 * time.Minute)) {
+		t.Errorf("second run since = %v, want latest payment time %v", provider.since, base.Add(8*time.Minute))
+	}
+
+	stats, err := st.Position().GetFullStats("trader-1")
+	if err != nil || math.Abs(stats.TotalFunding+1.25) > 1e-9 || stats.NetPnL != stats.TotalPnL+stats.TotalFunding {
+		t.Errorf("GetFullStats() = %+v, %v, want funding -1.25 included in net PnL", stats, err)
+	}
+}
+
+func TestParseBybitFundingLog(t *testing.T) {
+	body := []byte(`{"retCode":0,"retMsg":"OK","result":{"nextPageCursor":"abc","list":[
+		{"id":"1","symbol":"BTCUSDT","currency":"USDT","funding":"0.5","transactionTime":"1700000000000"},
+		{"id":"2","symbol":"ETHUSDT","currency":"USDT","funding":"-0.2","transactionTime":"1700028800000"},
+		{"id":"3","symbol":"BTCPERP","currency":"USDC","funding":"0","transactionTime":"1700028800000"}
+	]}}`)
+
+	payments, cursor, err := parseBybitFundingLog(body)
+	if err != nil {
+		t.Fatalf("parseBybitFundingLog() error = %v", err)
+	}
+	if cursor != "abc" || len(payments) != 2 {
+		t.Fatalf("got %d payments, cursor %q, want 2 and abc", len(payments), cursor)
+	}
+	// Bybit funding is a fee: paid 0.5, received 0.2
+	if payments[0].Amount != -0.5 || payments[1].Amount != 0.2 || payments[1].Symbol != "ETHUSDT" {
+		t.Errorf("payments = %+v", payments)
+	}
+}
+
+func TestParseOKXFundingBills(t *testing.T) {
+	data := []byte(`[{"billId":"b1","ccy":"USDT","balChg":"-0.031","ts":"1700000000000"}]`)
+	payments, err := parseOKXFundingBills(data, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("parseOKXFundingBills() error = %v", err)
+	}
+	if len(payments) != 1 || payments[0].Amount != -0.031 || payments[0].Symbol != "BTCUSDT" || payments[0].ID != "b1" {
+		t.Errorf("payments = %+v", payments)
+	}
+}
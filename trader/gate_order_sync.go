@@ -3,11 +3,11 @@ package trader
 import (
 	"fmt"
 	"math"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/antihax/optional"
@@ -49,7 +49,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Gate.io", len(trades))
+	t.log().Infof("📥 Received %d trades from Gate.io", len(trades))
 
 	// Sort by time
 	sort.Slice(trades, func(i, j int) bool {
@@ -59,6 +59,8 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
+	feeAsset := strings.ToUpper(t.settle) // Futures fees are charged in the settle currency
 	syncedCount := 0
 
 	for _, trade := range trades {
@@ -182,6 +184,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 		// But SDK struct?
 		// checking... Gate SDK `FuturesTrade`: `Fee` string. Yes.
 		fee, _ := strconv.ParseFloat(trade.Fee, 64)
+		feeUSDT := fees.ToQuote(normalizedSymbol, feeAsset, fee, price)
 		
 		// Exec time
 		execTime := time.Unix(int64(trade.CreateTime), 0).UTC()
@@ -192,6 +195,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 			ExchangeID:      exchangeID,
 			ExchangeType:    exchangeType,
 			ExchangeOrderID: trade.OrderId, // Use OrderID to link (OrderId is string in MyFuturesTrade?)
+			TraceID:         orderTraceID(exchangeID, trade.OrderId),
 			Symbol:          normalizedSymbol,
 			Side:            side,
 			PositionSide:    "BOTH",
@@ -202,14 +206,14 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 			Status:          "FILLED",
 			FilledQuantity:  quantity,
 			AvgFillPrice:    price,
-			Commission:      fee,
+			Commission:      feeUSDT,
 			FilledAt:        execTime.UnixMilli(),
 			CreatedAt:       execTime.UnixMilli(),
 			UpdatedAt:       execTime.UnixMilli(),
 		}
 		
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Warnf("Failed to sync order %s: %v", tradeID, err)
+			t.log().Warnf("Failed to sync order %s: %v", tradeID, err)
 			continue
 		}
 		
@@ -220,6 +224,7 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 			ExchangeType:    exchangeType,
 			OrderID:         orderRecord.ID, // DB ID
 			ExchangeOrderID: trade.OrderId,
+			TraceID:         orderTraceID(exchangeID, trade.OrderId),
 			ExchangeTradeID: tradeID,
 			Symbol:          normalizedSymbol,
 			Side:            side,
@@ -227,11 +232,12 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 			Quantity:        quantity,
 			QuoteQuantity:   price * quantity,
 			Commission:      fee,
-			CommissionAsset: "USDT", // Assumption
+			CommissionAsset: commissionAsset(feeAsset),
+			CommissionUSDT:  feeUSDT,
 			CreatedAt:       execTime.UnixMilli(),
 		}
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Warnf("Failed to sync fill %s: %v", tradeID, err)
+			t.log().Warnf("Failed to sync fill %s: %v", tradeID, err)
 		}
 		
 		// Update Position Builder
@@ -247,30 +253,22 @@ func (t *GateTrader) SyncOrdersFromGate(traderID string, exchangeID string, exch
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			normalizedSymbol, posSide, action,
-			quantity, price, fee, 0, // pnl
+			quantity, price, feeUSDT, 0, // pnl
 			execTime.UnixMilli(), tradeID,
 		); err != nil {
-			logger.Warnf("Failed to process position for trade %s: %v", tradeID, err)
+			t.log().Warnf("Failed to process position for trade %s: %v", tradeID, err)
 		}
 		
 		syncedCount++
 	}
 	
 	if syncedCount > 0 {
-		logger.Infof("✅ Gate.io order sync completed: %d new trades synced", syncedCount)
+		t.log().Infof("✅ Gate.io order sync completed: %d new trades synced", syncedCount)
 	}
 	return nil
 }
 
-// StartOrderSync starts background sync
-func (t *GateTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromGate(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Warnf("Gate.io order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Gate.io order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Gate.io
+func (t *GateTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromGate(traderID, exchangeID, exchangeType, st)
 }
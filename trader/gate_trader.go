@@ -16,6 +16,8 @@ import (
 
 // GateTrader Gate.io Futures Trader
 type GateTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	client    *gateapi.APIClient
 	apiKey    string
 	secretKey string
@@ -114,6 +116,20 @@ func (t *GateTrader) getContractInfo(symbol string) (*gateapi.Contract, error) {
 	return &contract, nil
 }
 
+// GetSymbolInfo gets per-symbol trading limits (max leverage and base maintenance rate from contract info)
+func (t *GateTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	contract, err := t.getContractInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+	maxLeverage, _ := strconv.ParseFloat(contract.LeverageMax, 64)
+	info := &SymbolInfo{Symbol: symbol, MaxLeverage: int(maxLeverage)}
+	if rate, err := strconv.ParseFloat(contract.MaintenanceRate, 64); err == nil && rate > 0 {
+		info.MaintenanceTiers = []MaintenanceMarginTier{{MaintMarginRatio: rate}}
+	}
+	return info, nil
+}
+
 // formatQuantity formats quantity according to lot size
 func (t *GateTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	contract, err := t.getContractInfo(symbol)
@@ -234,7 +250,7 @@ func (t *GateTrader) GetPositions() ([]map[string]interface{}, error) {
 		// Need contract multiplier to convert sizeIdx to quantity (BTC amount)
 		contract, err := t.getContractInfo(symbol)
 		if err != nil {
-			logger.Errorf("Failed to get contract info for %s: %v", symbol, err)
+			t.log().Errorf("Failed to get contract info for %s: %v", symbol, err)
 			continue
 		}
 		multiplier, _ := strconv.ParseFloat(contract.QuantoMultiplier, 64)
@@ -316,7 +332,7 @@ func (t *GateTrader) placeOrder(symbol string, quantity float64, leverage int, s
 
 	// Set Leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Warnf("Failed to set leverage for %s: %v", symbol, err)
+		t.log().Warnf("Failed to set leverage for %s: %v", symbol, err)
 	}
 
 	// Format quantity (number of contracts)
@@ -591,6 +607,21 @@ func (t *GateTrader) CancelStopOrders(symbol string) error {
 	return err
 }
 
+// CancelOrder cancels a single pending order by order ID, falling back to price-triggered
+// (stop-loss/take-profit) orders
+func (t *GateTrader) CancelOrder(symbol string, orderID string) error {
+	ctx := t.getAuthContext()
+
+	_, _, err := t.client.FuturesApi.CancelFuturesOrder(ctx, t.settle, orderID, nil)
+	if err == nil {
+		return nil
+	}
+	if _, _, triggerErr := t.client.FuturesApi.CancelPriceTriggeredOrder(ctx, t.settle, orderID); triggerErr != nil {
+		return fmt.Errorf("failed to cancel order %s: %v (triggered: %v)", orderID, err, triggerErr)
+	}
+	return nil
+}
+
 // CancelAllOrders cancels all regular pending orders
 func (t *GateTrader) CancelAllOrders(symbol string) error {
 	gateSymbol := t.convertSymbol(symbol)
@@ -0,0 +1,26 @@
+package trader
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// TradingPausedConfigKey system_config key persisting the global pause across restarts
+const TradingPausedConfigKey = "trading_paused"
+
+// ErrTradingPaused returned for decisions executed while trading is globally paused
+var ErrTradingPaused = errors.New("trading is globally paused")
+
+// tradingPaused global emergency brake: while set, no trader opens or closes positions from AI cycles
+// or external decisions; monitoring (drawdown checks, order sync, equity snapshots) keeps running
+var tradingPaused atomic.Bool
+
+// SetTradingPaused pauses or resumes trading of all traders
+func SetTradingPaused(paused bool) {
+	tradingPaused.Store(paused)
+}
+
+// IsTradingPaused reports whether trading is globally paused
+func IsTradingPaused() bool {
+	return tradingPaused.Load()
+}
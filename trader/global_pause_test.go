@@ -0,0 +1,20 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+
+	"nofx/kernel"
+)
+
+func TestExecuteDecisionWhilePaused(t *testing.T) {
+	SetTradingPaused(true)
+	defer SetTradingPaused(false)
+
+	// No exchange client: reaching the execution path would panic
+	at := &AutoTrader{name: "paused"}
+	err := at.ExecuteDecision(&kernel.Decision{Symbol: "BTCUSDT", Action: "open_long"})
+	if !errors.Is(err, ErrTradingPaused) {
+		t.Errorf("ExecuteDecision() error = %v, want ErrTradingPaused", err)
+	}
+}
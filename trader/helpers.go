@@ -74,3 +74,17 @@ func SafeInt(data map[string]interface{}, key string) (int, error) {
 		return 0, fmt.Errorf("value for key '%s' is not an integer (type: %T)", key, v)
 	}
 }
+
+// orderIDString returns the "orderId" of an order result as string (exchanges use int64, float64 or string)
+func orderIDString(orderResult map[string]interface{}) string {
+	switch v := orderResult["orderId"].(type) {
+	case int64:
+		return fmt.Sprintf("%d", v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -2,7 +2,6 @@ package trader
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -22,7 +21,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Hyperliquid trades from: %s", startTime.Format(time.RFC3339))
+	t.log().Infof("🔄 Syncing Hyperliquid trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records
 	trades, err := t.GetTrades(startTime, 1000)
@@ -30,7 +29,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Hyperliquid", len(trades))
+	t.log().Infof("📥 Received %d trades from Hyperliquid", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -41,6 +40,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 	syncedCount := 0
 
 	for _, trade := range trades {
@@ -51,7 +51,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 			}
 
 			// Normalize symbol
-			symbol := market.Normalize(trade.Symbol)
+			symbol := market.NormalizeWithQuote(trade.Symbol, t.quoteAsset)
 
 			// Use order action from trade (parsed from Hyperliquid Dir field)
 			// Dir field values: "Open Long", "Open Short", "Close Long", "Close Short"
@@ -61,6 +61,9 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 				positionSide = "SHORT"
 			}
 
+			// Fees charged in the base coin or a discount token are valued in the quote currency
+			feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.Price)
+
 			// Create order record - use Unix milliseconds UTC
 			tradeTimeMs := trade.Time.UTC().UnixMilli()
 			orderRecord := &store.TraderOrder{
@@ -68,6 +71,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 				ExchangeID:      exchangeID,   // UUID
 				ExchangeType:    exchangeType, // Exchange type
 				ExchangeOrderID: trade.TradeID,
+				TraceID:         orderTraceID(exchangeID, trade.OrderID),
 				Symbol:          symbol,
 				Side:            trade.Side,
 				PositionSide:    "BOTH", // Hyperliquid uses one-way position mode
@@ -78,7 +82,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 				Status:          "FILLED",
 				FilledQuantity:  trade.Quantity,
 				AvgFillPrice:    trade.Price,
-				Commission:      trade.Fee,
+				Commission:      feeUSDT,
 				FilledAt:        tradeTimeMs,
 				CreatedAt:       tradeTimeMs,
 				UpdatedAt:       tradeTimeMs,
@@ -86,7 +90,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 
 			// Insert order record
 			if err := orderStore.CreateOrder(orderRecord); err != nil {
-				logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+				t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 				continue
 			}
 
@@ -97,6 +101,7 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 				ExchangeType:    exchangeType, // Exchange type
 				OrderID:         orderRecord.ID,
 				ExchangeOrderID: trade.TradeID,
+				TraceID:         orderTraceID(exchangeID, trade.OrderID),
 				ExchangeTradeID: trade.TradeID,
 				Symbol:          symbol,
 				Side:            trade.Side,
@@ -104,46 +109,39 @@ func (t *HyperliquidTrader) SyncOrdersFromHyperliquid(traderID string, exchangeI
 				Quantity:        trade.Quantity,
 				QuoteQuantity:   trade.Price * trade.Quantity,
 				Commission:      trade.Fee,
-				CommissionAsset: "USDT",
+				CommissionAsset: commissionAsset(trade.FeeAsset),
+				CommissionUSDT:  feeUSDT,
 				RealizedPnL:     trade.RealizedPnL,
 				IsMaker:         false, // Hyperliquid GetTrades doesn't provide maker/taker info
 				CreatedAt:       tradeTimeMs,
 			}
 
 			if err := orderStore.CreateFill(fillRecord); err != nil {
-				logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+				t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 			}
 
 			// Create/update position record using PositionBuilder
 			if err := posBuilder.ProcessTrade(
 				traderID, exchangeID, exchangeType,
 				symbol, positionSide, orderAction,
-				trade.Quantity, trade.Price, trade.Fee, trade.RealizedPnL,
+				trade.Quantity, trade.Price, feeUSDT, trade.RealizedPnL,
 				tradeTimeMs, trade.TradeID,
 			); err != nil {
-				logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+				t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 			} else {
-				logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
+				t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
 			}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, trade.Side, trade.Quantity, trade.Price, trade.RealizedPnL, trade.Fee, orderAction)
 	}
 
-	logger.Infof("✅ Order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
-// StartOrderSync starts background order sync task
-func (t *HyperliquidTrader) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromHyperliquid(traderID, exchangeID, exchangeType, st); err != nil {
-				logger.Infof("⚠️  Hyperliquid order sync failed: %v", err)
-			}
-		}
-	}()
-	logger.Infof("🔄 Hyperliquid order sync started (interval: %v)", interval)
+// SyncOrders implements OrderSyncer for Hyperliquid
+func (t *HyperliquidTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	return t.SyncOrdersFromHyperliquid(traderID, exchangeID, exchangeType, st)
 }
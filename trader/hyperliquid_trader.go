@@ -9,6 +9,8 @@ import (
 	"io"
 	"net/http"
 	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
 	"strconv"
 	"strings"
 	"sync"
@@ -20,6 +22,8 @@ import (
 
 // HyperliquidTrader Hyperliquid trader
 type HyperliquidTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	exchange      *hyperliquid.Exchange
 	ctx           context.Context
 	walletAddr    string
@@ -31,6 +35,8 @@ type HyperliquidTrader struct {
 	xyzMetaMutex sync.RWMutex
 	privateKey   *ecdsa.PrivateKey // For xyz dex signing
 	isTestnet    bool
+	slippage     slippageConfig // IOC limit offset of market orders
+	quoteAsset   string         // Quote of reported position symbols (USDT unless set)
 }
 
 // xyzDexMeta represents metadata for xyz dex assets
@@ -66,7 +72,7 @@ var xyzDexAssets = map[string]bool{
 func isXyzDexAsset(symbol string) bool {
 	// Remove common suffixes to get base symbol
 	base := strings.ToUpper(symbol) // Convert to uppercase for case-insensitive matching
-	for _, suffix := range []string{"USDT", "USD", "-USDC", "-USD"} {
+	for _, suffix := range []string{"-USDC", "-USD", "USDT", "USDC", "USD"} {
 		if strings.HasSuffix(base, suffix) {
 			base = strings.TrimSuffix(base, suffix)
 			break
@@ -187,18 +193,18 @@ func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool)
 
 // GetBalance gets account balance
 func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
-	logger.Infof("🔄 Calling Hyperliquid API to get account balance...")
+	t.log().Infof("🔄 Calling Hyperliquid API to get account balance...")
 
 	// ✅ Step 1: Query Spot account balance
 	spotState, err := t.exchange.Info().SpotUserState(t.ctx, t.walletAddr)
 	var spotUSDCBalance float64 = 0.0
 	if err != nil {
-		logger.Infof("⚠️ Failed to query Spot balance (may have no spot assets): %v", err)
+		t.log().Infof("⚠️ Failed to query Spot balance (may have no spot assets): %v", err)
 	} else if spotState != nil && len(spotState.Balances) > 0 {
 		for _, balance := range spotState.Balances {
 			if balance.Coin == "USDC" {
 				spotUSDCBalance, _ = strconv.ParseFloat(balance.Total, 64)
-				logger.Infof("✓ Found Spot balance: %.2f USDC", spotUSDCBalance)
+				t.log().Infof("✓ Found Spot balance: %.2f USDC", spotUSDCBalance)
 				break
 			}
 		}
@@ -207,7 +213,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	// ✅ Step 2: Query Perpetuals contract account status
 	accountState, err := t.exchange.Info().UserState(t.ctx, t.walletAddr)
 	if err != nil {
-		logger.Infof("❌ Hyperliquid Perpetuals API call failed: %v", err)
+		t.log().Infof("❌ Hyperliquid Perpetuals API call failed: %v", err)
 		return nil, fmt.Errorf("failed to get account information: %w", err)
 	}
 
@@ -235,8 +241,8 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 
 	// 🔍 Debug: Print complete summary structure returned by API
 	summaryJSON, _ := json.MarshalIndent(summary, "  ", "  ")
-	logger.Infof("🔍 [DEBUG] Hyperliquid API %s complete data:", summaryType)
-	logger.Infof("%s", string(summaryJSON))
+	t.log().Infof("🔍 [DEBUG] Hyperliquid API %s complete data:", summaryType)
+	t.log().Infof("%s", string(summaryJSON))
 
 	// ⚠️ Critical fix: Accumulate actual unrealized PnL from all positions
 	totalUnrealizedPnl := 0.0
@@ -260,7 +266,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 		withdrawable, err := strconv.ParseFloat(accountState.Withdrawable, 64)
 		if err == nil && withdrawable > 0 {
 			availableBalance = withdrawable
-			logger.Infof("✓ Using Withdrawable as available balance: %.2f", availableBalance)
+			t.log().Infof("✓ Using Withdrawable as available balance: %.2f", availableBalance)
 		}
 	}
 
@@ -268,7 +274,7 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	if availableBalance == 0 && accountState.Withdrawable == "" {
 		availableBalance = accountValue - totalMarginUsed
 		if availableBalance < 0 {
-			logger.Infof("⚠️ Calculated available balance is negative (%.2f), reset to 0", availableBalance)
+			t.log().Infof("⚠️ Calculated available balance is negative (%.2f), reset to 0", availableBalance)
 			availableBalance = 0
 		}
 	}
@@ -279,17 +285,17 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	xyzAccountValue, xyzUnrealizedPnl, xyzPositions, err = t.getXYZDexBalance()
 	if err != nil {
 		// xyz dex query failed - log warning but don't fail the entire balance query
-		logger.Infof("⚠️ Failed to query xyz dex balance: %v", err)
+		t.log().Infof("⚠️ Failed to query xyz dex balance: %v", err)
 	}
 	// Always log xyz dex state for debugging
-	logger.Infof("🔍 xyz dex state: accountValue=%.4f, unrealizedPnl=%.4f, positions=%d",
+	t.log().Infof("🔍 xyz dex state: accountValue=%.4f, unrealizedPnl=%.4f, positions=%d",
 		xyzAccountValue, xyzUnrealizedPnl, len(xyzPositions))
 	for _, pos := range xyzPositions {
 		entryPx := "nil"
 		if pos.Position.EntryPx != nil {
 			entryPx = *pos.Position.EntryPx
 		}
-		logger.Infof("   └─ %s: size=%s, entryPx=%s, posValue=%s, pnl=%s",
+		t.log().Infof("   └─ %s: size=%s, entryPx=%s, posValue=%s, pnl=%s",
 			pos.Position.Coin, pos.Position.Szi, entryPx, pos.Position.PositionValue, pos.Position.UnrealizedPnl)
 	}
 	xyzWalletBalance := xyzAccountValue - xyzUnrealizedPnl
@@ -312,20 +318,20 @@ func (t *HyperliquidTrader) GetBalance() (map[string]interface{}, error) {
 	result["xyzDexUnrealizedPnl"] = xyzUnrealizedPnl        // xyz dex unrealized PnL
 	result["perpAccountValue"] = accountValue               // Perp account value for debugging
 
-	logger.Infof("✓ Hyperliquid complete account:")
-	logger.Infof("  • Spot balance: %.2f USDC", spotUSDCBalance)
-	logger.Infof("  • Perpetuals equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
+	t.log().Infof("✓ Hyperliquid complete account:")
+	t.log().Infof("  • Spot balance: %.2f USDC", spotUSDCBalance)
+	t.log().Infof("  • Perpetuals equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
 		accountValue,
 		walletBalanceWithoutUnrealized,
 		totalUnrealizedPnl)
-	logger.Infof("  • Perpetuals available balance: %.2f USDC", availableBalance)
-	logger.Infof("  • Margin used: %.2f USDC", totalMarginUsed)
-	logger.Infof("  • xyz dex equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
+	t.log().Infof("  • Perpetuals available balance: %.2f USDC", availableBalance)
+	t.log().Infof("  • Margin used: %.2f USDC", totalMarginUsed)
+	t.log().Infof("  • xyz dex equity: %.2f USDC (wallet %.2f + unrealized %.2f)",
 		xyzAccountValue,
 		xyzWalletBalance,
 		xyzUnrealizedPnl)
-	logger.Infof("  • Total assets (Perp+Spot+xyz): %.2f USDC", totalWalletBalance)
-	logger.Infof("  ⭐ Total: %.2f USDC | Perp: %.2f | Spot: %.2f | xyz: %.2f",
+	t.log().Infof("  • Total assets (Perp+Spot+xyz): %.2f USDC", totalWalletBalance)
+	t.log().Infof("  ⭐ Total: %.2f USDC | Perp: %.2f | Spot: %.2f | xyz: %.2f",
 		totalWalletBalance, availableBalance, spotUSDCBalance, xyzAccountValue)
 
 	return result, nil
@@ -472,17 +478,54 @@ func (t *HyperliquidTrader) fetchXyzMeta() error {
 	t.xyzMeta = &meta
 	t.xyzMetaMutex.Unlock()
 
-	logger.Infof("✅ xyz dex meta fetched, contains %d assets", len(meta.Universe))
+	t.log().Infof("✅ xyz dex meta fetched, contains %d assets", len(meta.Universe))
 	return nil
 }
 
+// GetSymbolInfo gets per-symbol trading limits (max leverage from meta)
+func (t *HyperliquidTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	coin := convertSymbolToHyperliquid(symbol)
+
+	if strings.HasPrefix(coin, "xyz:") {
+		t.xyzMetaMutex.RLock()
+		cached := t.xyzMeta != nil
+		t.xyzMetaMutex.RUnlock()
+		if !cached {
+			if err := t.fetchXyzMeta(); err != nil {
+				return nil, fmt.Errorf("failed to fetch xyz meta: %w", err)
+			}
+		}
+
+		t.xyzMetaMutex.RLock()
+		defer t.xyzMetaMutex.RUnlock()
+		for _, asset := range t.xyzMeta.Universe {
+			if asset.Name == coin {
+				return &SymbolInfo{Symbol: symbol, MaxLeverage: asset.MaxLeverage}, nil
+			}
+		}
+		return nil, fmt.Errorf("xyz asset %s not found in meta", coin)
+	}
+
+	t.metaMutex.RLock()
+	defer t.metaMutex.RUnlock()
+	if t.meta == nil {
+		return nil, fmt.Errorf("meta information is empty")
+	}
+	for _, asset := range t.meta.Universe {
+		if asset.Name == coin {
+			return &SymbolInfo{Symbol: symbol, MaxLeverage: asset.MaxLeverage}, nil
+		}
+	}
+	return nil, fmt.Errorf("asset %s not found in meta", coin)
+}
+
 // getXyzSzDecimals gets quantity precision for xyz dex asset
 func (t *HyperliquidTrader) getXyzSzDecimals(coin string) int {
 	t.xyzMetaMutex.RLock()
 	defer t.xyzMetaMutex.RUnlock()
 
 	if t.xyzMeta == nil {
-		logger.Infof("⚠️  xyz meta information is empty, using default precision 2")
+		t.log().Infof("⚠️  xyz meta information is empty, using default precision 2")
 		return 2 // Default precision for stocks/forex
 	}
 
@@ -499,10 +542,16 @@ func (t *HyperliquidTrader) getXyzSzDecimals(coin string) int {
 		}
 	}
 
-	logger.Infof("⚠️  Precision information not found for %s, using default precision 2", lookupName)
+	t.log().Infof("⚠️  Precision information not found for %s, using default precision 2", lookupName)
 	return 2 // Default precision for stocks/forex
 }
 
+// SetQuoteAsset reports positions in the trader's quote asset, so "BTC" is "BTCUSDC" for a
+// USDC-quoted trader (implements QuoteAssetConfigurable). Hyperliquid has a single USDC account.
+func (t *HyperliquidTrader) SetQuoteAsset(quote string) {
+	t.quoteAsset = quote
+}
+
 // GetPositions gets all positions (including xyz dex positions)
 func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 	// Get account status
@@ -526,8 +575,8 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 
 		posMap := make(map[string]interface{})
 
-		// Normalize symbol format (Hyperliquid uses "BTC", we convert to "BTCUSDT")
-		symbol := position.Coin + "USDT"
+		// Normalize symbol format (Hyperliquid uses "BTC", we convert to "BTCUSDT" or "BTCUSDC")
+		symbol := market.NormalizeWithQuote(position.Coin, t.quoteAsset)
 		posMap["symbol"] = symbol
 
 		// Position amount and direction
@@ -570,7 +619,7 @@ func (t *HyperliquidTrader) GetPositions() ([]map[string]interface{}, error) {
 	_, _, xyzPositions, err := t.getXYZDexBalance()
 	if err != nil {
 		// xyz dex query failed - log warning but don't fail
-		logger.Infof("⚠️  Failed to get xyz dex positions: %v", err)
+		t.log().Infof("⚠️  Failed to get xyz dex positions: %v", err)
 	} else {
 		for _, pos := range xyzPositions {
 			posAmt, _ := strconv.ParseFloat(pos.Position.Szi, 64)
@@ -642,7 +691,7 @@ func (t *HyperliquidTrader) SetMarginMode(symbol string, isCrossMargin bool) err
 	if !isCrossMargin {
 		marginModeStr = "isolated margin"
 	}
-	logger.Infof("  ✓ %s will use %s mode", symbol, marginModeStr)
+	t.log().Infof("  ✓ %s will use %s mode", symbol, marginModeStr)
 	return nil
 }
 
@@ -658,7 +707,23 @@ func (t *HyperliquidTrader) SetLeverage(symbol string, leverage int) error {
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
 
-	logger.Infof("  ✓ %s leverage switched to %dx", symbol, leverage)
+	t.log().Infof("  ✓ %s leverage switched to %dx", symbol, leverage)
+	return nil
+}
+
+// AdjustPositionMargin adds (amount > 0) or removes (amount < 0) isolated margin of an open position
+// (implements PositionMarginAdjuster; cross margin positions share the account balance and can't be adjusted)
+func (t *HyperliquidTrader) AdjustPositionMargin(symbol, positionSide string, amount float64) error {
+	if t.isCrossMargin {
+		return fmt.Errorf("position margin can only be adjusted in isolated margin mode")
+	}
+	coin := convertSymbolToHyperliquid(symbol)
+	if strings.HasPrefix(coin, "xyz:") {
+		return fmt.Errorf("position margin adjustment is not supported for xyz dex assets")
+	}
+	if _, err := t.exchange.UpdateIsolatedMargin(t.ctx, amount, coin); err != nil {
+		return fmt.Errorf("failed to adjust position margin: %w", err)
+	}
 	return nil
 }
 
@@ -669,7 +734,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 		return nil // Meta is normal, no refresh needed
 	}
 
-	logger.Infof("⚠️  Asset ID for %s is 0, attempting to refresh Meta information...", coin)
+	t.log().Infof("⚠️  Asset ID for %s is 0, attempting to refresh Meta information...", coin)
 
 	// Refresh Meta information
 	meta, err := t.exchange.Info().Meta(t.ctx)
@@ -682,7 +747,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 	t.meta = meta
 	t.metaMutex.Unlock()
 
-	logger.Infof("✅ Meta information refreshed, contains %d assets", len(meta.Universe))
+	t.log().Infof("✅ Meta information refreshed, contains %d assets", len(meta.Universe))
 
 	// Verify Asset ID after refresh
 	assetID = t.exchange.Info().NameToAsset(coin)
@@ -693,7 +758,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 			"  3. API connection issue", coin)
 	}
 
-	logger.Infof("✅ Asset ID check passed after refresh: %s -> %d", coin, assetID)
+	t.log().Infof("✅ Asset ID check passed after refresh: %s -> %d", coin, assetID)
 	return nil
 }
 
@@ -701,7 +766,7 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel old pending orders: %v", err)
 	}
 
 	// Hyperliquid symbol format
@@ -716,7 +781,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 			return nil, err
 		}
 	} else {
-		logger.Infof("  ℹ xyz dex asset %s - using default leverage", coin)
+		t.log().Infof("  ℹ xyz dex asset %s - using default leverage", coin)
 	}
 
 	// Get current price (for market order)
@@ -726,8 +791,9 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	}
 
 	// ⚠️ Critical: Price needs to be processed to 5 significant figures
-	aggressivePrice := t.roundPriceToSigfigs(price * 1.01)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*1.01, aggressivePrice)
+	rawPrice := t.iocPrice(symbol, price, true)
+	aggressivePrice := t.roundPriceToSigfigs(rawPrice)
+	t.log().Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", rawPrice, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -738,7 +804,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	} else {
 		// Standard crypto order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log().Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -759,7 +825,7 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 		}
 	}
 
-	logger.Infof("✓ Long position opened successfully: %s quantity: %.4f", symbol, quantity)
+	t.log().Infof("✓ Long position opened successfully: %s quantity: %.4f", symbol, quantity)
 
 	result := make(map[string]interface{})
 	result["orderId"] = 0
@@ -769,11 +835,21 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	return result, nil
 }
 
+// SetSlippage sets the IOC limit offset used by market orders (implements SlippageConfigurable)
+func (t *HyperliquidTrader) SetSlippage(settings store.SlippageSettings) {
+	t.slippage.set(settings)
+}
+
+// iocPrice returns the aggressive IOC limit price of a market order on symbol
+func (t *HyperliquidTrader) iocPrice(symbol string, price float64, isBuy bool) float64 {
+	return aggressivePrice(price, isBuy, t.slippage.bpsFor(symbol, hyperliquidDefaultSlippageBps))
+}
+
 // OpenShort opens a short position (supports both crypto and xyz dex)
 func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel old pending orders: %v", err)
 	}
 
 	// Hyperliquid symbol format
@@ -788,7 +864,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 			return nil, err
 		}
 	} else {
-		logger.Infof("  ℹ xyz dex asset %s - using default leverage", coin)
+		t.log().Infof("  ℹ xyz dex asset %s - using default leverage", coin)
 	}
 
 	// Get current price
@@ -798,8 +874,9 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 	}
 
 	// ⚠️ Critical: Price needs to be processed to 5 significant figures
-	aggressivePrice := t.roundPriceToSigfigs(price * 0.99)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*0.99, aggressivePrice)
+	rawPrice := t.iocPrice(symbol, price, false)
+	aggressivePrice := t.roundPriceToSigfigs(rawPrice)
+	t.log().Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", rawPrice, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -810,7 +887,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 	} else {
 		// Standard crypto order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log().Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -831,7 +908,7 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 		}
 	}
 
-	logger.Infof("✓ Short position opened successfully: %s quantity: %.4f", symbol, quantity)
+	t.log().Infof("✓ Short position opened successfully: %s quantity: %.4f", symbol, quantity)
 
 	result := make(map[string]interface{})
 	result["orderId"] = 0
@@ -880,8 +957,9 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 	}
 
 	// ⚠️ Critical: Price needs to be processed to 5 significant figures
-	aggressivePrice := t.roundPriceToSigfigs(price * 0.99)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*0.99, aggressivePrice)
+	rawPrice := t.iocPrice(symbol, price, false)
+	aggressivePrice := t.roundPriceToSigfigs(rawPrice)
+	t.log().Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", rawPrice, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -892,7 +970,7 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 	} else {
 		// Standard crypto close order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log().Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -913,11 +991,11 @@ func (t *HyperliquidTrader) CloseLong(symbol string, quantity float64) (map[stri
 		}
 	}
 
-	logger.Infof("✓ Long position closed successfully: %s quantity: %.4f", symbol, quantity)
+	t.log().Infof("✓ Long position closed successfully: %s quantity: %.4f", symbol, quantity)
 
 	// Cancel all pending orders for this coin after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -967,8 +1045,9 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 	}
 
 	// ⚠️ Critical: Price needs to be processed to 5 significant figures
-	aggressivePrice := t.roundPriceToSigfigs(price * 1.01)
-	logger.Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", price*1.01, aggressivePrice)
+	rawPrice := t.iocPrice(symbol, price, true)
+	aggressivePrice := t.roundPriceToSigfigs(rawPrice)
+	t.log().Infof("  💰 Price precision handling: %.8f -> %.8f (5 significant figures)", rawPrice, aggressivePrice)
 
 	// Handle xyz dex assets differently
 	if isXyz {
@@ -979,7 +1058,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 	} else {
 		// Standard crypto close order
 		roundedQuantity := t.roundToSzDecimals(coin, quantity)
-		logger.Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
+		t.log().Infof("  📏 Quantity precision handling: %.8f -> %.8f (szDecimals=%d)", quantity, roundedQuantity, t.getSzDecimals(coin))
 
 		order := hyperliquid.CreateOrderRequest{
 			Coin:  coin,
@@ -1000,11 +1079,11 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 		}
 	}
 
-	logger.Infof("✓ Short position closed successfully: %s quantity: %.4f", symbol, quantity)
+	t.log().Infof("✓ Short position closed successfully: %s quantity: %.4f", symbol, quantity)
 
 	// Cancel all pending orders for this coin after closing position
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+		t.log().Infof("  ⚠ Failed to cancel pending orders: %v", err)
 	}
 
 	result := make(map[string]interface{})
@@ -1019,7 +1098,7 @@ func (t *HyperliquidTrader) CloseShort(symbol string, quantity float64) (map[str
 func (t *HyperliquidTrader) CancelStopLossOrders(symbol string) error {
 	// Hyperliquid SDK's OpenOrder structure does not expose trigger field
 	// Cannot distinguish stop loss and take profit orders, so cancel all pending orders for this coin
-	logger.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
+	t.log().Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
 	return t.CancelStopOrders(symbol)
 }
 
@@ -1027,10 +1106,28 @@ func (t *HyperliquidTrader) CancelStopLossOrders(symbol string) error {
 func (t *HyperliquidTrader) CancelTakeProfitOrders(symbol string) error {
 	// Hyperliquid SDK's OpenOrder structure does not expose trigger field
 	// Cannot distinguish stop loss and take profit orders, so cancel all pending orders for this coin
-	logger.Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
+	t.log().Infof("  ⚠️ Hyperliquid cannot distinguish stop loss/take profit orders, will cancel all pending orders")
 	return t.CancelStopOrders(symbol)
 }
 
+// CancelOrder cancels a single pending order by oid
+func (t *HyperliquidTrader) CancelOrder(symbol string, orderID string) error {
+	oid, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID %q: %w", orderID, err)
+	}
+
+	coin := convertSymbolToHyperliquid(symbol)
+	if strings.HasPrefix(coin, "xyz:") {
+		return t.cancelXyzOrder(oid)
+	}
+
+	if _, err := t.exchange.Cancel(t.ctx, coin, oid); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+	return nil
+}
+
 // CancelAllOrders cancels all pending orders for this coin
 func (t *HyperliquidTrader) CancelAllOrders(symbol string) error {
 	coin := convertSymbolToHyperliquid(symbol)
@@ -1054,12 +1151,12 @@ func (t *HyperliquidTrader) CancelAllOrders(symbol string) error {
 		if order.Coin == coin {
 			_, err := t.exchange.Cancel(t.ctx, coin, order.Oid)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
+				t.log().Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
 			}
 		}
 	}
 
-	logger.Infof("  ✓ Cancelled all pending orders for %s", symbol)
+	t.log().Infof("  ✓ Cancelled all pending orders for %s", symbol)
 	return nil
 }
 
@@ -1089,7 +1186,7 @@ func (t *HyperliquidTrader) CancelStopOrders(symbol string) error {
 		if order.Coin == coin {
 			_, err := t.exchange.Cancel(t.ctx, coin, order.Oid)
 			if err != nil {
-				logger.Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
+				t.log().Infof("  ⚠ Failed to cancel order (oid=%d): %v", order.Oid, err)
 				continue
 			}
 			canceledCount++
@@ -1097,9 +1194,9 @@ func (t *HyperliquidTrader) CancelStopOrders(symbol string) error {
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ No pending orders to cancel for %s", symbol)
+		t.log().Infof("  ℹ No pending orders to cancel for %s", symbol)
 	} else {
-		logger.Infof("  ✓ Cancelled %d pending orders for %s (including TP/SL orders)", canceledCount, symbol)
+		t.log().Infof("  ✓ Cancelled %d pending orders for %s (including TP/SL orders)", canceledCount, symbol)
 	}
 
 	return nil
@@ -1157,7 +1254,7 @@ func (t *HyperliquidTrader) cancelXyzOrders(coin string) error {
 	for _, order := range openOrders {
 		if order.Coin == coin {
 			if err := t.cancelXyzOrder(order.Oid); err != nil {
-				logger.Infof("  ⚠ Failed to cancel xyz dex order (oid=%d): %v", order.Oid, err)
+				t.log().Infof("  ⚠ Failed to cancel xyz dex order (oid=%d): %v", order.Oid, err)
 				continue
 			}
 			canceledCount++
@@ -1165,9 +1262,9 @@ func (t *HyperliquidTrader) cancelXyzOrders(coin string) error {
 	}
 
 	if canceledCount == 0 {
-		logger.Infof("  ℹ No pending xyz dex orders to cancel for %s", coin)
+		t.log().Infof("  ℹ No pending xyz dex orders to cancel for %s", coin)
 	} else {
-		logger.Infof("  ✓ Cancelled %d xyz dex orders for %s", canceledCount, coin)
+		t.log().Infof("  ✓ Cancelled %d xyz dex orders for %s", canceledCount, coin)
 	}
 
 	return nil
@@ -1384,7 +1481,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 	// Round price to 5 significant figures
 	roundedPrice := t.roundPriceToSigfigs(price)
 
-	logger.Infof("📝 Placing xyz dex order (direct): %s %s size=%.4f price=%.4f metaIndex=%d assetIndex=%d (formula: 100000 + 1*10000 + %d) reduceOnly=%v",
+	t.log().Infof("📝 Placing xyz dex order (direct): %s %s size=%.4f price=%.4f metaIndex=%d assetIndex=%d (formula: 100000 + 1*10000 + %d) reduceOnly=%v",
 		map[bool]string{true: "BUY", false: "SELL"}[isBuy],
 		coin, roundedSize, roundedPrice, metaIndex, assetIndex, metaIndex, reduceOnly)
 
@@ -1439,7 +1536,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	logger.Infof("📤 Sending xyz dex order to %s/exchange", apiURL)
+	t.log().Infof("📤 Sending xyz dex order to %s/exchange", apiURL)
 
 	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, apiURL+"/exchange", bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -1482,7 +1579,7 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 
 	if err := json.Unmarshal(body, &result); err != nil {
 		// Try to parse as error response
-		logger.Infof("⚠️  Failed to parse response as success, raw body: %s", string(body))
+		t.log().Infof("⚠️  Failed to parse response as success, raw body: %s", string(body))
 		return fmt.Errorf("xyz dex order failed, status=%d, body=%s", resp.StatusCode, string(body))
 	}
 
@@ -1498,14 +1595,14 @@ func (t *HyperliquidTrader) placeXyzOrder(coin string, isBuy bool, size float64,
 			return fmt.Errorf("xyz dex order error (coin=%s, assetIndex=%d, size=%.4f, price=%.4f): %s", coin, assetIndex, roundedSize, roundedPrice, *status.Error)
 		}
 		if status.Filled != nil {
-			logger.Infof("✅ xyz dex order filled: totalSz=%s avgPx=%s oid=%d",
+			t.log().Infof("✅ xyz dex order filled: totalSz=%s avgPx=%s oid=%d",
 				status.Filled.TotalSz, status.Filled.AvgPx, status.Filled.Oid)
 		} else if status.Resting != nil {
-			logger.Infof("✅ xyz dex order resting: oid=%d", status.Resting.Oid)
+			t.log().Infof("✅ xyz dex order resting: oid=%d", status.Resting.Oid)
 		}
 	}
 
-	logger.Infof("✅ xyz dex order placed successfully: %s (response: %s)", coin, string(body))
+	t.log().Infof("✅ xyz dex order placed successfully: %s (response: %s)", coin, string(body))
 	return nil
 }
 
@@ -1568,7 +1665,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 	// Round price to 5 significant figures
 	roundedPrice := t.roundPriceToSigfigs(triggerPrice)
 
-	logger.Infof("📝 Placing xyz dex %s order: %s %s size=%.4f triggerPrice=%.4f assetIndex=%d",
+	t.log().Infof("📝 Placing xyz dex %s order: %s %s size=%.4f triggerPrice=%.4f assetIndex=%d",
 		tpsl,
 		map[bool]string{true: "BUY", false: "SELL"}[isBuy],
 		coin, roundedSize, roundedPrice, assetIndex)
@@ -1626,7 +1723,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	logger.Infof("📤 Sending xyz dex %s order to %s/exchange", tpsl, apiURL)
+	t.log().Infof("📤 Sending xyz dex %s order to %s/exchange", tpsl, apiURL)
 
 	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, apiURL+"/exchange", bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -1663,7 +1760,7 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		logger.Infof("⚠️  Failed to parse response, raw body: %s", string(body))
+		t.log().Infof("⚠️  Failed to parse response, raw body: %s", string(body))
 		return fmt.Errorf("xyz dex %s order failed, status=%d, body=%s", tpsl, resp.StatusCode, string(body))
 	}
 
@@ -1679,11 +1776,11 @@ func (t *HyperliquidTrader) placeXyzTriggerOrder(coin string, isBuy bool, size f
 			return fmt.Errorf("xyz dex %s order error: %s", tpsl, *status.Error)
 		}
 		if status.Resting != nil {
-			logger.Infof("✅ xyz dex %s order placed: oid=%d", tpsl, status.Resting.Oid)
+			t.log().Infof("✅ xyz dex %s order placed: oid=%d", tpsl, status.Resting.Oid)
 		}
 	}
 
-	logger.Infof("✅ xyz dex %s order placed successfully: %s", tpsl, coin)
+	t.log().Infof("✅ xyz dex %s order placed successfully: %s", tpsl, coin)
 	return nil
 }
 
@@ -1731,7 +1828,7 @@ func (t *HyperliquidTrader) SetStopLoss(symbol string, positionSide string, quan
 		}
 	}
 
-	logger.Infof("  Stop loss price set: %.4f", roundedStopPrice)
+	t.log().Infof("  Stop loss price set: %.4f", roundedStopPrice)
 	return nil
 }
 
@@ -1779,10 +1876,16 @@ func (t *HyperliquidTrader) SetTakeProfit(symbol string, positionSide string, qu
 		}
 	}
 
-	logger.Infof("  Take profit price set: %.4f", roundedTakeProfitPrice)
+	t.log().Infof("  Take profit price set: %.4f", roundedTakeProfitPrice)
 	return nil
 }
 
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a reduce-only trigger order for the rounded size
+func (t *HyperliquidTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
 // FormatQuantity formats quantity to correct precision
 func (t *HyperliquidTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	coin := convertSymbolToHyperliquid(symbol)
@@ -1800,7 +1903,7 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 	defer t.metaMutex.RUnlock()
 
 	if t.meta == nil {
-		logger.Infof("⚠️  meta information is empty, using default precision 4")
+		t.log().Infof("⚠️  meta information is empty, using default precision 4")
 		return 4 // Default precision
 	}
 
@@ -1811,7 +1914,7 @@ func (t *HyperliquidTrader) getSzDecimals(coin string) int {
 		}
 	}
 
-	logger.Infof("⚠️  Precision information not found for %s, using default precision 4", coin)
+	t.log().Infof("⚠️  Precision information not found for %s, using default precision 4", coin)
 	return 4 // Default precision
 }
 
@@ -1874,7 +1977,7 @@ func convertSymbolToHyperliquid(symbol string) string {
 	base := strings.ToUpper(symbol)
 
 	// Remove common suffixes to get base symbol
-	for _, suffix := range []string{"USDT", "USD", "-USDC", "-USD"} {
+	for _, suffix := range []string{"-USDC", "-USD", "USDT", "USDC", "USD"} {
 		if strings.HasSuffix(base, suffix) {
 			base = strings.TrimSuffix(base, suffix)
 			break
@@ -2056,6 +2159,7 @@ func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]TradeRe
 		// Hyperliquid uses one-way mode, so PositionSide is "BOTH"
 		trade := TradeRecord{
 			TradeID:      strconv.FormatInt(fill.Tid, 10),
+			OrderID:      strconv.FormatInt(fill.Oid, 10),
 			Symbol:       fill.Coin,
 			Side:         side,
 			PositionSide: "BOTH", // Hyperliquid doesn't have hedge mode
@@ -2064,6 +2168,7 @@ func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]TradeRe
 			Quantity:     qty,
 			RealizedPnL:  pnl,
 			Fee:          fee,
+			FeeAsset:     fill.FeeToken,
 			Time:         time.UnixMilli(fill.Time).UTC(),
 		}
 		trades = append(trades, trade)
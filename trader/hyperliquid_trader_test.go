@@ -8,6 +8,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"nofx/logger"
+	"nofx/market"
+
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/sonirico/go-hyperliquid"
 	"github.com/stretchr/testify/assert"
@@ -403,6 +406,16 @@ func TestConvertSymbolToHyperliquid(t *testing.T) {
 			symbol:   "BTC",
 			expected: "BTC",
 		},
+		{
+			name:     "BTCUSDC conversion",
+			symbol:   "BTCUSDC",
+			expected: "BTC",
+		},
+		{
+			name:     "Dash-quoted conversion",
+			symbol:   "BTC-USD",
+			expected: "BTC",
+		},
 	}
 
 	for _, tt := range tests {
@@ -413,6 +426,23 @@ func TestConvertSymbolToHyperliquid(t *testing.T) {
 	}
 }
 
+// TestHyperliquidTrader_USDCQuote Test a USDC-quoted trader: its symbols map to Hyperliquid coins
+// and positions come back in its quote
+func TestHyperliquidTrader_USDCQuote(t *testing.T) {
+	suite := NewHyperliquidTestSuite(t)
+	defer suite.Cleanup()
+	trader := suite.Trader.(*HyperliquidTrader)
+	applyQuoteAsset(trader, market.QuoteUSDC, "test", logger.TraderEntry("", nil))
+
+	assert.Equal(t, "BTC", convertSymbolToHyperliquid(market.WithQuote("BTC", market.QuoteUSDC)))
+
+	positions, err := trader.GetPositions()
+	assert.NoError(t, err)
+	if assert.Len(t, positions, 1) {
+		assert.Equal(t, "BTCUSDC", positions[0]["symbol"])
+	}
+}
+
 // TestAbsFloat Test absolute value function
 func TestAbsFloat(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,154 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Iceberg exit defaults (risk_control.iceberg_*)
+const (
+	defaultIcebergSliceFraction = 0.25
+	defaultIcebergSliceDelay    = time.Second
+	maxIcebergSlices            = 20
+)
+
+// icebergExitSettings returns the notional above which full closes are sliced (0 = disabled),
+// the fraction closed per slice and the delay between slices
+func (at *AutoTrader) icebergExitSettings() (thresholdUSD, fraction float64, delay time.Duration) {
+	if at.config.StrategyConfig == nil {
+		return 0, 0, 0
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	fraction = rc.IcebergSliceFraction
+	if fraction <= 0 || fraction > 1 {
+		fraction = defaultIcebergSliceFraction
+	}
+	delay = defaultIcebergSliceDelay
+	if rc.IcebergSliceDelayMs > 0 {
+		delay = time.Duration(rc.IcebergSliceDelayMs) * time.Millisecond
+	}
+	return rc.IcebergExitThresholdUSD, fraction, delay
+}
+
+// icebergSlices splits quantity into slices of quantity*fraction (at most maxIcebergSlices of equal
+// size); the last slice takes the remainder
+func icebergSlices(quantity, fraction float64) []float64 {
+	if quantity <= 0 {
+		return nil
+	}
+	if fraction <= 0 || fraction >= 1 {
+		return []float64{quantity}
+	}
+	n := int(math.Ceil(1/fraction - 1e-9))
+	slice := quantity * fraction
+	if n > maxIcebergSlices {
+		n = maxIcebergSlices
+		slice = quantity / float64(n)
+	}
+
+	slices := make([]float64, 0, n)
+	remaining := quantity
+	for i := 0; i < n-1; i++ {
+		slices = append(slices, slice)
+		remaining -= slice
+	}
+	return append(slices, remaining)
+}
+
+// closeFullPosition market-closes a whole position and records the close orders/fills. Positions whose
+// notional exceeds risk_control.iceberg_exit_threshold_usd are closed in sequential reduce-only slices,
+// each slice confirmed filled before the next one is placed; the last slice closes whatever is left.
+func (at *AutoTrader) closeFullPosition(symbol, side string, quantity, markPrice, entryPrice float64) error {
+	side = strings.ToLower(side)
+	if side != "long" && side != "short" {
+		return fmt.Errorf("unknown position direction: %s", side)
+	}
+	action := "close_" + side
+
+	threshold, fraction, delay := at.icebergExitSettings()
+	if threshold <= 0 || quantity <= 0 || markPrice <= 0 || quantity*markPrice <= threshold {
+		order, err := at.closeSide(symbol, side, 0) // 0 = close all
+		if err != nil {
+			return err
+		}
+		at.recordAndConfirmOrder(order, symbol, action, quantity, markPrice, 0, entryPrice)
+		return nil
+	}
+
+	slices := icebergSlices(quantity, fraction)
+	at.log().Infof("🧊 [%s] Iceberg exit %s %s: notional %.2f USDT > %.2f, closing in %d slices",
+		at.name, symbol, side, quantity*markPrice, threshold, len(slices))
+
+	closed := 0.0
+	for i, sliceQty := range slices {
+		last := i == len(slices)-1
+		closeQty := 0.0 // 0 = close all, the last slice also sweeps up rounding leftovers
+		if !last {
+			formatted, err := at.formatSliceQuantity(symbol, sliceQty)
+			if err != nil {
+				return err
+			}
+			if formatted > 0 {
+				closeQty = formatted
+			} else {
+				last = true // slice below the quantity precision, close the rest at once
+			}
+		}
+		recordQty := closeQty
+		if closeQty == 0 {
+			recordQty = quantity - closed
+		}
+
+		order, err := at.closeSide(symbol, side, closeQty)
+		if err != nil {
+			return fmt.Errorf("iceberg slice %d/%d failed: %w", i+1, len(slices), err)
+		}
+		// Wait for the slice to fill before placing the next one
+		if status := at.sliceFillStatus(symbol, order); isFinalOrderStatus(status) && status != "FILLED" {
+			return fmt.Errorf("iceberg slice %d/%d not filled: %s", i+1, len(slices), status)
+		}
+		at.recordAndConfirmOrder(order, symbol, action, recordQty, markPrice, 0, entryPrice)
+		closed += recordQty
+		at.log().Infof("  🧊 Slice %d/%d closed: %s %s qty %.6f", i+1, len(slices), symbol, side, recordQty)
+
+		if last {
+			break
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
+
+// closeSide closes quantity of a long/short position (0 = close all)
+func (at *AutoTrader) closeSide(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	if side == "long" {
+		return at.trader.CloseLong(symbol, quantity)
+	}
+	return at.trader.CloseShort(symbol, quantity)
+}
+
+// formatSliceQuantity rounds a slice quantity to the symbol's quantity precision
+func (at *AutoTrader) formatSliceQuantity(symbol string, quantity float64) (float64, error) {
+	formatted, err := at.trader.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to format slice quantity: %w", err)
+	}
+	return strconv.ParseFloat(formatted, 64)
+}
+
+// sliceFillStatus waits for a slice order to reach a final status and returns it ("" if unknown)
+func (at *AutoTrader) sliceFillStatus(symbol string, order map[string]interface{}) string {
+	orderID := orderIDString(order)
+	if orderID == "" {
+		return ""
+	}
+	status := at.confirmOrderFill(symbol, orderID)
+	if status == nil {
+		return ""
+	}
+	s, _ := status["status"].(string)
+	return s
+}
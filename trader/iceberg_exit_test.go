@@ -0,0 +1,47 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIcebergSlices(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		fraction float64
+		want     int
+		first    float64
+	}{
+		{name: "quarters", quantity: 10, fraction: 0.25, want: 4, first: 2.5},
+		{name: "uneven fraction", quantity: 10, fraction: 0.3, want: 4, first: 3},
+		{name: "capped slice count", quantity: 100, fraction: 0.01, want: maxIcebergSlices, first: 5},
+		{name: "whole position", quantity: 10, fraction: 1, want: 1, first: 10},
+		{name: "invalid fraction", quantity: 10, fraction: 0, want: 1, first: 10},
+		{name: "no quantity", quantity: 0, fraction: 0.25, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slices := icebergSlices(tt.quantity, tt.fraction)
+			if len(slices) != tt.want {
+				t.Fatalf("got %d slices %v, want %d", len(slices), slices, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			if math.Abs(slices[0]-tt.first) > 1e-9 {
+				t.Errorf("first slice = %v, want %v", slices[0], tt.first)
+			}
+			total := 0.0
+			for _, s := range slices {
+				if s <= 0 {
+					t.Errorf("non-positive slice in %v", slices)
+				}
+				total += s
+			}
+			if math.Abs(total-tt.quantity) > 1e-9 {
+				t.Errorf("slices sum to %v, want %v", total, tt.quantity)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ type ClosedPnLRecord struct {
 // Used for reconstructing position history with unified algorithm
 type TradeRecord struct {
 	TradeID      string    // Unique trade ID from exchange
+	OrderID      string    // Exchange order ID the trade filled ("" if unknown)
 	Symbol       string    // Trading pair (e.g., "BTCUSDT")
 	Side         string    // "BUY" or "SELL"
 	PositionSide string    // "LONG", "SHORT", or "BOTH" (for one-way mode)
@@ -31,6 +32,7 @@ type TradeRecord struct {
 	Quantity     float64   // Executed quantity
 	RealizedPnL  float64   // Realized PnL (non-zero for closing trades)
 	Fee          float64   // Trading fee/commission
+	FeeAsset     string    // Asset the fee was charged in ("" = quote currency)
 	Time         time.Time // Trade execution time
 }
 
@@ -76,6 +78,9 @@ type Trader interface {
 	// CancelTakeProfitOrders Cancel only take-profit orders (BUG fix: don't delete stop-loss when adjusting take-profit)
 	CancelTakeProfitOrders(symbol string) error
 
+	// CancelOrder Cancel a single pending order by exchange order ID
+	CancelOrder(symbol string, orderID string) error
+
 	// CancelAllOrders Cancel all pending orders for this symbol
 	CancelAllOrders(symbol string) error
 
@@ -0,0 +1,174 @@
+package trader
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"nofx/store"
+)
+
+// defaultKeyCooldown how long a rate-limited key is skipped when the exchange doesn't say
+const defaultKeyCooldown = 5 * time.Second
+
+// MultiKeyTrader exchange clients that can rotate requests across additional API keys of the same account
+type MultiKeyTrader interface {
+	AddAPIKeys(keys []store.APIKeyCredential)
+}
+
+// PooledAPIKey a key of an APIKeyPool
+type PooledAPIKey struct {
+	store.APIKeyCredential
+	coolUntil time.Time
+}
+
+// APIKeyPool rotates requests round-robin across the API keys of one exchange account, so per-key
+// rate limits add up. Keys that hit a rate limit cool down and are skipped until they recover.
+// A nil pool is valid and means "primary key only".
+type APIKeyPool struct {
+	mu   sync.Mutex
+	keys []*PooledAPIKey
+	next int
+}
+
+// NewAPIKeyPool creates a pool of the primary key followed by the additional keys (keys without
+// key or secret are dropped). Returns nil when only one usable key remains.
+func NewAPIKeyPool(primary store.APIKeyCredential, additional []store.APIKeyCredential) *APIKeyPool {
+	pool := &APIKeyPool{}
+	for _, k := range append([]store.APIKeyCredential{primary}, additional...) {
+		if k.APIKey == "" || k.SecretKey == "" {
+			continue
+		}
+		pool.keys = append(pool.keys, &PooledAPIKey{APIKeyCredential: k})
+	}
+	if len(pool.keys) < 2 {
+		return nil
+	}
+	return pool
+}
+
+// Size returns the number of keys in the pool (0 for a nil pool)
+func (p *APIKeyPool) Size() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.keys)
+}
+
+// Acquire returns the key to sign the next request with: the next key in round-robin order that
+// isn't cooling down, or the one that recovers first if all are. Returns nil for a nil pool.
+func (p *APIKeyPool) Acquire() *PooledAPIKey {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var soonest *PooledAPIKey
+	for i := 0; i < len(p.keys); i++ {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if !now.Before(k.coolUntil) {
+			p.next = (p.next + i + 1) % len(p.keys)
+			return k
+		}
+		if soonest == nil || k.coolUntil.Before(soonest.coolUntil) {
+			soonest = k
+		}
+	}
+	return soonest
+}
+
+// Next acquires the key for the next request and returns it with its credentials; a nil pool
+// returns (nil, primary). Keys without their own passphrase use the primary key's passphrase.
+func (p *APIKeyPool) Next(primary store.APIKeyCredential) (*PooledAPIKey, store.APIKeyCredential) {
+	key := p.Acquire()
+	if key == nil {
+		return nil, primary
+	}
+	cred := key.APIKeyCredential
+	if cred.Passphrase == "" {
+		cred.Passphrase = primary.Passphrase
+	}
+	return key, cred
+}
+
+// CoolDown marks a key as rate limited for d (defaultKeyCooldown if d <= 0)
+func (p *APIKeyPool) CoolDown(key *PooledAPIKey, d time.Duration) {
+	if p == nil || key == nil {
+		return
+	}
+	if d <= 0 {
+		d = defaultKeyCooldown
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until := time.Now().Add(d); until.After(key.coolUntil) {
+		key.coolUntil = until
+	}
+}
+
+// keyUsageThreshold share of a key's request budget after which it is rested until the window resets
+const keyUsageThreshold = 0.9
+
+// binanceUsageLimits per-minute budgets of the Binance usage headers
+var binanceUsageLimits = map[string]float64{
+	"X-MBX-USED-WEIGHT-1M": 2400,
+	"X-MBX-ORDER-COUNT-1M": 1200,
+}
+
+// ObserveResponse cools the key down when the response says it is rate limited (HTTP 429/418,
+// honoring Retry-After) or its usage headers show it is close to its limit
+func (p *APIKeyPool) ObserveResponse(key *PooledAPIKey, resp *http.Response) {
+	if p == nil || key == nil || resp == nil {
+		return
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		p.CoolDown(key, retryAfter(resp.Header))
+		return
+	}
+	if d := usageCooldown(resp.Header, time.Now()); d > 0 {
+		p.CoolDown(key, d)
+	}
+}
+
+// retryAfter parses the Retry-After header (seconds), 0 if absent
+func retryAfter(h http.Header) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// usageCooldown returns how long to rest a key whose usage headers show it has used up
+// keyUsageThreshold of its budget, 0 if it has room left:
+//   - Binance: X-MBX-USED-WEIGHT-1M / X-MBX-ORDER-COUNT-1M, rested until the next minute
+//   - Bybit: X-Bapi-Limit-Status (remaining) of X-Bapi-Limit, rested until X-Bapi-Limit-Reset-Timestamp
+func usageCooldown(h http.Header, now time.Time) time.Duration {
+	var d time.Duration
+	for name, limit := range binanceUsageLimits {
+		used, err := strconv.ParseFloat(h.Get(name), 64)
+		if err != nil || used < limit*keyUsageThreshold {
+			continue
+		}
+		if untilMinute := now.Truncate(time.Minute).Add(time.Minute).Sub(now); untilMinute > d {
+			d = untilMinute
+		}
+	}
+
+	remaining, err1 := strconv.ParseFloat(h.Get("X-Bapi-Limit-Status"), 64)
+	limit, err2 := strconv.ParseFloat(h.Get("X-Bapi-Limit"), 64)
+	if err1 == nil && err2 == nil && limit > 0 && remaining <= limit*(1-keyUsageThreshold) {
+		wait := defaultKeyCooldown
+		if resetMs, err := strconv.ParseInt(h.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64); err == nil {
+			if until := time.UnixMilli(resetMs).Sub(now); until > 0 {
+				wait = until
+			}
+		}
+		if wait > d {
+			d = wait
+		}
+	}
+	return d
+}
@@ -0,0 +1,191 @@
+package trader
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+func TestAPIKeyPoolRotation(t *testing.T) {
+	primary := store.APIKeyCredential{APIKey: "k1", SecretKey: "s1", Passphrase: "p1"}
+	pool := NewAPIKeyPool(primary, []store.APIKeyCredential{
+		{APIKey: "k2", SecretKey: "s2"},
+		{APIKey: "k3", SecretKey: ""}, // incomplete, dropped
+		{APIKey: "k4", SecretKey: "s4", Passphrase: "p4"},
+	})
+	if pool.Size() != 3 {
+		t.Fatalf("pool size = %d, want 3", pool.Size())
+	}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		_, cred := pool.Next(primary)
+		got = append(got, cred.APIKey+"/"+cred.Passphrase)
+	}
+	want := []string{"k1/p1", "k2/p1", "k4/p4", "k1/p1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rotation = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAPIKeyPoolCoolDown(t *testing.T) {
+	primary := store.APIKeyCredential{APIKey: "k1", SecretKey: "s1"}
+	pool := NewAPIKeyPool(primary, []store.APIKeyCredential{{APIKey: "k2", SecretKey: "s2"}})
+
+	k1 := pool.Acquire()
+	pool.ObserveResponse(k1, &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"30"}}})
+	for i := 0; i < 3; i++ {
+		if k := pool.Acquire(); k.APIKey != "k2" {
+			t.Fatalf("acquired %s while k1 cools down", k.APIKey)
+		}
+	}
+
+	// All keys cooling down: the one recovering first is used
+	k2 := pool.Acquire()
+	pool.CoolDown(k2, time.Minute)
+	if k := pool.Acquire(); k.APIKey != "k1" {
+		t.Errorf("acquired %s, want k1 (recovers first)", k.APIKey)
+	}
+}
+
+func TestAPIKeyPoolSingleKey(t *testing.T) {
+	primary := store.APIKeyCredential{APIKey: "k1", SecretKey: "s1"}
+	pool := NewAPIKeyPool(primary, nil)
+	if pool != nil {
+		t.Fatal("single key should not create a pool")
+	}
+	key, cred := pool.Next(primary)
+	if key != nil || cred != primary {
+		t.Errorf("nil pool returned %v, %v", key, cred)
+	}
+	pool.CoolDown(key, time.Second) // no-op on nil pool
+}
+
+func TestUsageCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 45, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "no usage headers", header: http.Header{}, want: 0},
+		{name: "binance weight with room", header: http.Header{"X-Mbx-Used-Weight-1m": {"1200"}}, want: 0},
+		{name: "binance weight near limit", header: http.Header{"X-Mbx-Used-Weight-1m": {"2200"}}, want: 15 * time.Second},
+		{name: "binance orders near limit", header: http.Header{"X-Mbx-Order-Count-1m": {"1150"}}, want: 15 * time.Second},
+		{name: "bybit with room", header: http.Header{"X-Bapi-Limit": {"10"}, "X-Bapi-Limit-Status": {"5"}}, want: 0},
+		{
+			name: "bybit near limit",
+			header: http.Header{
+				"X-Bapi-Limit":                 {"10"},
+				"X-Bapi-Limit-Status":          {"1"},
+				"X-Bapi-Limit-Reset-Timestamp": {"1767268847000"}, // now + 2s
+			},
+			want: 2 * time.Second,
+		},
+		{name: "bybit near limit without reset", header: http.Header{"X-Bapi-Limit": {"10"}, "X-Bapi-Limit-Status": {"0"}}, want: defaultKeyCooldown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := usageCooldown(tt.header, now); got != tt.want {
+				t.Errorf("usageCooldown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// keyedSignatureServer accepts requests signed with any of secrets (keyed by API key) and records
+// the API key of each request; sign computes the expected signature of a request
+func keyedSignatureServer(t *testing.T, secrets map[string]string, keyHeader string, sign func(r *http.Request, body []byte, secret string) (got, want string), resp string) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var used []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		key := r.Header.Get(keyHeader)
+		secret, ok := secrets[key]
+		if !ok {
+			t.Errorf("request with unknown API key %q", key)
+		} else if got, want := sign(r, body, secret); got != want {
+			t.Errorf("%s %s: signature %q, want %q for key %s", r.Method, r.URL.Path, got, want, key)
+		}
+		mu.Lock()
+		used = append(used, key)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &used
+}
+
+func hmacHex(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestFuturesTraderAddAPIKeys(t *testing.T) {
+	secrets := map[string]string{"k1": "s1", "k2": "s2"}
+	srv, used := keyedSignatureServer(t, secrets, "X-MBX-APIKEY", func(r *http.Request, body []byte, secret string) (string, string) {
+		payload, _ := binanceSignedPayload(r.URL.RawQuery)
+		return r.URL.Query().Get("signature"), hmacHex(secret, payload+string(body))
+	}, `[]`)
+
+	client := futures.NewClient("k1", "s1")
+	client.BaseURL = srv.URL
+	ft := &FuturesTrader{client: client}
+	ft.AddAPIKeys([]store.APIKeyCredential{{APIKey: "k2", SecretKey: "s2"}})
+	if client.HTTPClient == http.DefaultClient {
+		t.Fatal("http.DefaultClient not replaced by a client of its own")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.NewListOpenOrdersService().Symbol("BTCUSDT").Do(context.Background()); err != nil {
+			t.Fatalf("signed GET: %v", err)
+		}
+	}
+	// Form-encoded body: signed together with the query string
+	client.NewCreateOrderService().Symbol("BTCUSDT").Side(futures.SideTypeBuy).
+		Type(futures.OrderTypeMarket).Quantity("0.01").Do(context.Background())
+
+	if got := strings.Join(*used, ","); got != "k1,k2,k1" {
+		t.Errorf("keys used = %s, want k1,k2,k1", got)
+	}
+}
+
+func TestBybitTraderAddAPIKeys(t *testing.T) {
+	secrets := map[string]string{"k1": "s1", "k2": "s2"}
+	srv, used := keyedSignatureServer(t, secrets, "X-BAPI-API-KEY", func(r *http.Request, body []byte, secret string) (string, string) {
+		payload := r.Header.Get("X-BAPI-TIMESTAMP") + r.Header.Get("X-BAPI-API-KEY") + r.Header.Get("X-BAPI-RECV-WINDOW") + r.URL.RawQuery
+		return r.Header.Get("X-BAPI-SIGN"), hmacHex(secret, payload)
+	}, `{"retCode":0,"result":{"list":[]}}`)
+
+	bt := NewBybitTrader("k1", "s1", false)
+	bt.baseURL = srv.URL
+	bt.clock = NewClockSync("Bybit", nil)
+	bt.signer.clock = bt.clock
+	bt.AddAPIKeys([]store.APIKeyCredential{{APIKey: "k2", SecretKey: "s2"}})
+
+	// Hand-signed requests set the primary key; the transport re-signs them with the pooled key
+	for i := 0; i < 3; i++ {
+		if _, err := bt.getTradesViaHTTP(time.Now().Add(-time.Hour), 10); err != nil {
+			t.Fatalf("getTradesViaHTTP() error = %v", err)
+		}
+	}
+	if got := strings.Join(*used, ","); got != "k1,k2,k1" {
+		t.Errorf("keys used = %s, want k1,k2,k1", got)
+	}
+}
@@ -0,0 +1,74 @@
+package trader
+
+import (
+	"nofx/kernel"
+)
+
+// SymbolInfo exchange trading limits for a symbol
+type SymbolInfo struct {
+	Symbol           string
+	MaxLeverage      int                     // Max leverage allowed by the exchange (0 = unknown)
+	MaintenanceTiers []MaintenanceMarginTier // Maintenance margin brackets by notional (nil = unknown)
+}
+
+// SymbolInfoProvider is implemented by exchanges that expose per-symbol trading limits
+type SymbolInfoProvider interface {
+	GetSymbolInfo(symbol string) (*SymbolInfo, error)
+}
+
+// clampLeverage limits requested leverage to the exchange maximum (maxLeverage <= 0 = unknown, no limit)
+func clampLeverage(requested, maxLeverage int) int {
+	if maxLeverage > 0 && requested > maxLeverage {
+		return maxLeverage
+	}
+	return requested
+}
+
+// maxLeverageCeiling returns the configured hard leverage ceiling (0 = no ceiling)
+func (at *AutoTrader) maxLeverageCeiling() int {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.MaxLeverage
+}
+
+// enforceMaxLeverage clamps the decision's leverage to the configured ceiling, independent of the
+// BTC/ETH and altcoin leverage the AI is guided to use (CODE ENFORCED)
+func (at *AutoTrader) enforceMaxLeverage(decision *kernel.Decision) {
+	ceiling := at.maxLeverageCeiling()
+	leverage := clampLeverage(decision.Leverage, ceiling)
+	if leverage == decision.Leverage {
+		return
+	}
+
+	at.log().Infof("  ⚠️ [LEVERAGE] %s requested %dx exceeds max leverage %dx, clamping",
+		decision.Symbol, decision.Leverage, ceiling)
+	decision.Leverage = leverage
+}
+
+// enforceExchangeMaxLeverage clamps the decision's leverage to the exchange's per-symbol maximum
+// before opening, so SetLeverage isn't rejected (or silently clamped) on low-cap symbols (CODE ENFORCED)
+func (at *AutoTrader) enforceExchangeMaxLeverage(decision *kernel.Decision) {
+	provider, ok := at.trader.(SymbolInfoProvider)
+	if !ok {
+		return
+	}
+
+	info, err := provider.GetSymbolInfo(decision.Symbol)
+	if err != nil {
+		at.log().Infof("  ⚠️ [LEVERAGE] Failed to get %s symbol info, skipping exchange limit check: %v", decision.Symbol, err)
+		return
+	}
+	if info == nil {
+		return
+	}
+
+	leverage := clampLeverage(decision.Leverage, info.MaxLeverage)
+	if leverage == decision.Leverage {
+		return
+	}
+
+	at.log().Infof("  ⚠️ [LEVERAGE] %s requested %dx exceeds %s max %dx, clamping to %dx",
+		decision.Symbol, decision.Leverage, at.exchange, info.MaxLeverage, leverage)
+	decision.Leverage = leverage
+}
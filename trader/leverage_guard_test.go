@@ -0,0 +1,55 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+func TestClampLeverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		max       int
+		want      int
+	}{
+		{name: "within limit", requested: 10, max: 20, want: 10},
+		{name: "at limit", requested: 20, max: 20, want: 20},
+		{name: "above limit", requested: 50, max: 10, want: 10},
+		{name: "unknown limit", requested: 50, max: 0, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampLeverage(tt.requested, tt.max); got != tt.want {
+				t.Errorf("clampLeverage(%d, %d) = %d, want %d", tt.requested, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnforceMaxLeverage(t *testing.T) {
+	at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{
+		RiskControl: store.RiskControlConfig{MaxLeverage: 20},
+	}}}
+
+	decision := &kernel.Decision{Symbol: "DOGEUSDT", Leverage: 125}
+	at.enforceMaxLeverage(decision)
+	if decision.Leverage != 20 {
+		t.Errorf("leverage = %d, want 20", decision.Leverage)
+	}
+
+	decision = &kernel.Decision{Symbol: "BTCUSDT", Leverage: 5}
+	at.enforceMaxLeverage(decision)
+	if decision.Leverage != 5 {
+		t.Errorf("leverage = %d, want 5 (below ceiling)", decision.Leverage)
+	}
+
+	at.config.StrategyConfig.RiskControl.MaxLeverage = 0
+	decision = &kernel.Decision{Symbol: "DOGEUSDT", Leverage: 125}
+	at.enforceMaxLeverage(decision)
+	if decision.Leverage != 125 {
+		t.Errorf("leverage = %d, want 125 (no ceiling)", decision.Leverage)
+	}
+}
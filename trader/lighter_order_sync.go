@@ -2,7 +2,6 @@ package trader
 
 import (
 	"fmt"
-	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
 	"sort"
@@ -22,7 +21,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 	// Get recent trades (last 24 hours)
 	startTime := time.Now().Add(-24 * time.Hour)
 
-	logger.Infof("🔄 Syncing Lighter trades from: %s", startTime.Format(time.RFC3339))
+	t.log().Infof("🔄 Syncing Lighter trades from: %s", startTime.Format(time.RFC3339))
 
 	// Use GetTrades method to fetch trade records (same as other exchanges)
 	trades, err := t.GetTrades(startTime, 100)
@@ -30,7 +29,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 		return fmt.Errorf("failed to get trades: %w", err)
 	}
 
-	logger.Infof("📥 Received %d trades from Lighter", len(trades))
+	t.log().Infof("📥 Received %d trades from Lighter", len(trades))
 
 	// Sort trades by time ASC (oldest first) for proper position building
 	sort.Slice(trades, func(i, j int) bool {
@@ -41,6 +40,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 	orderStore := st.Order()
 	positionStore := st.Position()
 	posBuilder := store.NewPositionBuilder(positionStore)
+	fees := newCommissionConverter(t)
 
 	syncedCount := 0
 	for _, trade := range trades {
@@ -70,6 +70,9 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 			}
 		}
 
+		// Fees charged in another asset than the quote currency are valued in it
+		feeUSDT := fees.ToQuote(symbol, trade.FeeAsset, trade.Fee, trade.Price)
+
 		// Create order record - use Unix milliseconds UTC
 		tradeTimeMs := trade.Time.UTC().UnixMilli()
 		orderRecord := &store.TraderOrder{
@@ -77,6 +80,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 			ExchangeID:      exchangeID,   // UUID
 			ExchangeType:    exchangeType, // Exchange type
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			Symbol:          symbol,
 			Side:            strings.ToUpper(side),
 			PositionSide:    positionSide,
@@ -87,7 +91,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 			Status:          "FILLED",
 			FilledQuantity:  trade.Quantity,
 			AvgFillPrice:    trade.Price,
-			Commission:      trade.Fee,
+			Commission:      feeUSDT,
 			FilledAt:        tradeTimeMs,
 			CreatedAt:       tradeTimeMs,
 			UpdatedAt:       tradeTimeMs,
@@ -95,7 +99,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 
 		// Insert order record
 		if err := orderStore.CreateOrder(orderRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync trade %s: %v", trade.TradeID, err)
 			continue
 		}
 
@@ -106,6 +110,7 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 			ExchangeType:    exchangeType, // Exchange type
 			OrderID:         orderRecord.ID,
 			ExchangeOrderID: trade.TradeID,
+			TraceID:         orderTraceID(exchangeID, trade.OrderID),
 			ExchangeTradeID: trade.TradeID,
 			Symbol:          symbol,
 			Side:            strings.ToUpper(side),
@@ -113,49 +118,46 @@ func (t *LighterTraderV2) SyncOrdersFromLighter(traderID string, exchangeID stri
 			Quantity:        trade.Quantity,
 			QuoteQuantity:   trade.Price * trade.Quantity,
 			Commission:      trade.Fee,
-			CommissionAsset: "USDT",
+			CommissionAsset: commissionAsset(trade.FeeAsset),
+			CommissionUSDT:  feeUSDT,
 			RealizedPnL:     trade.RealizedPnL,
 			IsMaker:         false,
 			CreatedAt:       tradeTimeMs,
 		}
 
 		if err := orderStore.CreateFill(fillRecord); err != nil {
-			logger.Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync fill for trade %s: %v", trade.TradeID, err)
 		}
 
 		// Create/update position record using PositionBuilder
 		if err := posBuilder.ProcessTrade(
 			traderID, exchangeID, exchangeType,
 			symbol, positionSide, orderAction,
-			trade.Quantity, trade.Price, trade.Fee, trade.RealizedPnL,
+			trade.Quantity, trade.Price, feeUSDT, trade.RealizedPnL,
 			tradeTimeMs, trade.TradeID,
 		); err != nil {
-			logger.Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
+			t.log().Infof("  ⚠️ Failed to sync position for trade %s: %v", trade.TradeID, err)
 		} else {
-			logger.Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
+			t.log().Infof("  📍 Position updated for trade: %s (action: %s, qty: %.6f)", trade.TradeID, orderAction, trade.Quantity)
 		}
 
 		syncedCount++
-		logger.Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
+		t.log().Infof("  ✅ Synced trade: %s %s %s qty=%.6f price=%.6f pnl=%.2f fee=%.6f action=%s",
 			trade.TradeID, symbol, side, trade.Quantity, trade.Price, trade.RealizedPnL, trade.Fee, orderAction)
 	}
 
-	logger.Infof("✅ Order sync completed: %d new trades synced", syncedCount)
+	t.log().Infof("✅ Order sync completed: %d new trades synced", syncedCount)
 	return nil
 }
 
-// StartOrderSync starts background order sync task
-func (t *LighterTraderV2) StartOrderSync(traderID string, exchangeID string, exchangeType string, st *store.Store, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			if err := t.SyncOrdersFromLighter(traderID, exchangeID, exchangeType, st); err != nil {
-				// Only log non-404 errors to reduce log spam
-				if !strings.Contains(err.Error(), "status 404") {
-					logger.Infof("⚠️  Order sync failed: %v", err)
-				}
-			}
+// SyncOrders implements OrderSyncer for Lighter
+// 404 responses (no trades yet) are not treated as sync failures
+func (t *LighterTraderV2) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	if err := t.SyncOrdersFromLighter(traderID, exchangeID, exchangeType, st); err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			return nil
 		}
-	}()
-	logger.Infof("🔄 Lighter order+position sync started (interval: %v)", interval)
+		return err
+	}
+	return nil
 }
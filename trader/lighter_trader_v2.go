@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"nofx/logger"
+	"nofx/store"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +19,9 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+// lighterFeeAsset Lighter charges trading fees in its USDC collateral
+const lighterFeeAsset = "USDC"
+
 // AccountInfo LIGHTER account information
 type AccountInfo struct {
 	AccountIndex     int64   `json:"account_index"`
@@ -58,6 +62,8 @@ type AccountResponse struct {
 
 // LighterTraderV2 New implementation using official lighter-go SDK
 type LighterTraderV2 struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	ctx        context.Context
 	walletAddr string // Ethereum wallet address
 
@@ -87,6 +93,9 @@ type LighterTraderV2 struct {
 	// Market index cache
 	marketIndexMap map[string]uint16 // symbol -> market_id
 	marketMutex    sync.RWMutex
+
+	// Market order price protection
+	slippage slippageConfig
 }
 
 // NewLighterTraderV2 Create new LIGHTER trader (using official SDK)
@@ -186,7 +195,7 @@ func (t *LighterTraderV2) initializeAccount() error {
 	t.accountIndex = accountInfo.AccountIndex
 	t.accountMutex.Unlock()
 
-	logger.Infof("✓ Account index: %d", t.accountIndex)
+	t.log().Infof("✓ Account index: %d", t.accountIndex)
 	return nil
 }
 
@@ -212,7 +221,7 @@ func (t *LighterTraderV2) getAccountByL1Address() (*AccountInfo, error) {
 	}
 
 	// Log raw response for debugging
-	logger.Infof("LIGHTER account API response: %s", string(body))
+	t.log().Infof("LIGHTER account API response: %s", string(body))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get account (status %d): %s", resp.StatusCode, string(body))
@@ -239,9 +248,9 @@ func (t *LighterTraderV2) getAccountByL1Address() (*AccountInfo, error) {
 	}
 
 	// Log all found accounts
-	logger.Infof("Found %d accounts (main: %d, sub: %d)", len(allAccounts), len(accountResp.Accounts), len(accountResp.SubAccounts))
+	t.log().Infof("Found %d accounts (main: %d, sub: %d)", len(allAccounts), len(accountResp.Accounts), len(accountResp.SubAccounts))
 	for i, acc := range allAccounts {
-		logger.Infof("  Account[%d]: index=%d, collateral=%s", i, acc.AccountIndex, acc.Collateral)
+		t.log().Infof("  Account[%d]: index=%d, collateral=%s", i, acc.AccountIndex, acc.Collateral)
 	}
 
 	account := &allAccounts[0]
@@ -275,7 +284,7 @@ func (t *LighterTraderV2) checkClient() error {
 		return fmt.Errorf("API Key mismatch: local=%s, server=%s", localPubKey, publicKey)
 	}
 
-	logger.Infof("✓ API Key verification passed")
+	t.log().Infof("✓ API Key verification passed")
 	return nil
 }
 
@@ -310,7 +319,7 @@ func (t *LighterTraderV2) refreshAuthToken() error {
 	t.tokenExpiry = deadline
 	t.accountMutex.Unlock()
 
-	logger.Infof("✓ Auth token generated (valid until: %s)", t.tokenExpiry.Format(time.RFC3339))
+	t.log().Infof("✓ Auth token generated (valid until: %s)", t.tokenExpiry.Format(time.RFC3339))
 	return nil
 }
 
@@ -321,7 +330,7 @@ func (t *LighterTraderV2) ensureAuthToken() error {
 	t.accountMutex.RUnlock()
 
 	if expired {
-		logger.Info("🔄 Auth token about to expire, refreshing...")
+		t.log().Info("🔄 Auth token about to expire, refreshing...")
 		return t.refreshAuthToken()
 	}
 
@@ -333,9 +342,14 @@ func (t *LighterTraderV2) GetExchangeType() string {
 	return "lighter"
 }
 
+// SetSlippage sets the market order price protection offset (implements SlippageConfigurable)
+func (t *LighterTraderV2) SetSlippage(settings store.SlippageSettings) {
+	t.slippage.set(settings)
+}
+
 // Cleanup Clean up resources
 func (t *LighterTraderV2) Cleanup() error {
-	logger.Info("⏹  LIGHTER trader cleanup completed")
+	t.log().Info("⏹  LIGHTER trader cleanup completed")
 	return nil
 }
 
@@ -413,7 +427,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 	endpoint := fmt.Sprintf("%s/api/v1/trades?account_index=%d&sort_by=timestamp&sort_dir=desc&limit=%d&auth=%s",
 		t.baseURL, t.accountIndex, limit, encodedAuth)
 
-	logger.Infof("🔍 Calling Lighter GetTrades API: %s", endpoint[:min(len(endpoint), 150)]+"...")
+	t.log().Infof("🔍 Calling Lighter GetTrades API: %s", endpoint[:min(len(endpoint), 150)]+"...")
 
 	req, err := http.NewRequest("GET", endpoint, nil)
 	if err != nil {
@@ -432,7 +446,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		logger.Infof("⚠️  Lighter trades API returned %d: %s", resp.StatusCode, string(body))
+		t.log().Infof("⚠️  Lighter trades API returned %d: %s", resp.StatusCode, string(body))
 		return []TradeRecord{}, nil
 	}
 
@@ -441,21 +455,21 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 	if len(logBody) > 500 {
 		logBody = logBody[:500] + "..."
 	}
-	logger.Infof("📋 Lighter trades API raw response: %s", logBody)
+	t.log().Infof("📋 Lighter trades API raw response: %s", logBody)
 
 	var response LighterTradeResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		logger.Infof("⚠️  Failed to parse trades response as object: %v", err)
+		t.log().Infof("⚠️  Failed to parse trades response as object: %v", err)
 		var trades []LighterTrade
 		if err := json.Unmarshal(body, &trades); err != nil {
-			logger.Infof("⚠️  Failed to parse trades response as array: %v", err)
+			t.log().Infof("⚠️  Failed to parse trades response as array: %v", err)
 			return []TradeRecord{}, nil
 		}
 		response.Trades = trades
 	}
 
 	if response.Code != 200 && response.Code != 0 {
-		logger.Infof("⚠️  Trades API returned non-success code: %d", response.Code)
+		t.log().Infof("⚠️  Trades API returned non-success code: %d", response.Code)
 		return []TradeRecord{}, nil
 	}
 
@@ -463,7 +477,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 	marketMap := make(map[int]string)
 	markets, err := t.fetchMarketList()
 	if err != nil {
-		logger.Infof("⚠️  Failed to fetch market list: %v, using fallback", err)
+		t.log().Infof("⚠️  Failed to fetch market list: %v, using fallback", err)
 		// Fallback market IDs (common ones)
 		marketMap[0] = "BTC"
 		marketMap[1] = "ETH"
@@ -572,6 +586,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 				Quantity:     closeQty,
 				RealizedPnL:  0,
 				Fee:          fee * (closeQty / qty),
+				FeeAsset:     lighterFeeAsset,
 				Time:         tradeTime.Add(-time.Millisecond),
 			}
 			result = append(result, closeTrade)
@@ -586,11 +601,12 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 				Quantity:     openQty,
 				RealizedPnL:  0,
 				Fee:          fee * (openQty / qty),
+				FeeAsset:     lighterFeeAsset,
 				Time:         tradeTime,
 			}
 			result = append(result, openTrade)
 
-			logger.Infof("  🔄 Flip: %s %.4f → %s %.4f", closeSide, closeQty, openSide, openQty)
+			t.log().Infof("  🔄 Flip: %s %.4f → %s %.4f", closeSide, closeQty, openSide, openQty)
 			continue
 		}
 
@@ -628,6 +644,7 @@ func (t *LighterTraderV2) GetTrades(startTime time.Time, limit int) ([]TradeReco
 			Quantity:     qty,
 			RealizedPnL:  0, // Not available in API
 			Fee:          fee,
+			FeeAsset:     lighterFeeAsset,
 			Time:         time.UnixMilli(lt.Timestamp).UTC(),
 		}
 		result = append(result, trade)
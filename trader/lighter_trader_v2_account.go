@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"nofx/logger"
+	"nofx/kernel"
 	"strconv"
 	"strings"
 )
@@ -122,7 +122,7 @@ func (t *LighterTraderV2) GetAccountBalance() (*AccountBalance, error) {
 		MaintenanceMargin: maintenanceMargin,
 	}
 
-	logger.Infof("✓ Lighter balance: equity=%.2f, available=%.2f, crossValue=%.2f",
+	t.log().Infof("✓ Lighter balance: equity=%.2f, available=%.2f, crossValue=%.2f",
 		totalEquity, availableBalance, crossAssetValue)
 
 	return balance, nil
@@ -227,11 +227,11 @@ func (t *LighterTraderV2) GetPositionsRaw(symbol string) ([]Position, error) {
 		}
 		positions = append(positions, pos)
 
-		logger.Infof("✓ Lighter position: %s %s size=%.4f entry=%.2f mark=%.2f lev=%.1fx pnl=%.4f",
+		t.log().Infof("✓ Lighter position: %s %s size=%.4f entry=%.2f mark=%.2f lev=%.1fx pnl=%.4f",
 			lPos.Symbol, side, size, entryPrice, markPrice, leverage, pnl)
 	}
 
-	logger.Infof("✓ Lighter positions: found %d positions", len(positions))
+	t.log().Infof("✓ Lighter positions: found %d positions", len(positions))
 	return positions, nil
 }
 
@@ -313,7 +313,7 @@ func (t *LighterTraderV2) GetMarketPrice(symbol string) (float64, error) {
 				return 0, fmt.Errorf("invalid price for %s: %.2f", normalizedSymbol, price)
 			}
 
-			logger.Infof("✓ Lighter %s price: %.2f", normalizedSymbol, price)
+			t.log().Infof("✓ Lighter %s price: %.2f", normalizedSymbol, price)
 			return price, nil
 		}
 	}
@@ -328,12 +328,12 @@ func (t *LighterTraderV2) FormatQuantity(symbol string, quantity float64) (strin
 	return fmt.Sprintf("%.4f", quantity), nil
 }
 
-// GetOrderBook Get order book with best bid/ask prices
-func (t *LighterTraderV2) GetOrderBook(symbol string) (bestBid, bestAsk float64, err error) {
+// GetOrderBook Get the top depth levels of the order book
+func (t *LighterTraderV2) GetOrderBook(symbol string, depth int) (*kernel.OrderBook, error) {
 	// Get market_id first
 	marketID, err := t.getMarketIndex(symbol)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get market ID: %w", err)
+		return nil, fmt.Errorf("failed to get market ID: %w", err)
 	}
 
 	// Get order book from Lighter API
@@ -341,22 +341,22 @@ func (t *LighterTraderV2) GetOrderBook(symbol string) (bestBid, bestAsk float64,
 
 	req, err := http.NewRequest("GET", endpoint, nil)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("failed to get order book (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to get order book (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
@@ -369,35 +369,37 @@ func (t *LighterTraderV2) GetOrderBook(symbol string) (bestBid, bestAsk float64,
 	}
 
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return 0, 0, fmt.Errorf("failed to parse order book: %w", err)
+		return nil, fmt.Errorf("failed to parse order book: %w", err)
 	}
 
 	if apiResp.Code != 200 {
-		return 0, 0, fmt.Errorf("API error code: %d", apiResp.Code)
+		return nil, fmt.Errorf("API error code: %d", apiResp.Code)
 	}
 
-	// Get best bid (highest buy price)
-	if len(apiResp.Data.Bids) > 0 && len(apiResp.Data.Bids[0]) >= 1 {
-		if price, ok := apiResp.Data.Bids[0][0].(float64); ok {
-			bestBid = price
-		} else if priceStr, ok := apiResp.Data.Bids[0][0].(string); ok {
-			bestBid, _ = strconv.ParseFloat(priceStr, 64)
-		}
-	}
+	return &kernel.OrderBook{
+		Symbol: symbol,
+		Bids:   parseOrderBookLevels(apiResp.Data.Bids, depth),
+		Asks:   parseOrderBookLevels(apiResp.Data.Asks, depth),
+	}, nil
+}
 
-	// Get best ask (lowest sell price)
-	if len(apiResp.Data.Asks) > 0 && len(apiResp.Data.Asks[0]) >= 1 {
-		if price, ok := apiResp.Data.Asks[0][0].(float64); ok {
-			bestAsk = price
-		} else if priceStr, ok := apiResp.Data.Asks[0][0].(string); ok {
-			bestAsk, _ = strconv.ParseFloat(priceStr, 64)
-		}
+// GetBestBidAsk Get best bid/ask prices from the order book
+func (t *LighterTraderV2) GetBestBidAsk(symbol string) (bestBid, bestAsk float64, err error) {
+	book, err := t.GetOrderBook(symbol, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(book.Bids) > 0 {
+		bestBid = book.Bids[0].Price
+	}
+	if len(book.Asks) > 0 {
+		bestAsk = book.Asks[0].Price
 	}
 
 	if bestBid <= 0 || bestAsk <= 0 {
 		return 0, 0, fmt.Errorf("invalid order book prices: bid=%.2f, ask=%.2f", bestBid, bestAsk)
 	}
 
-	logger.Infof("✓ Lighter order book: %s bid=%.2f, ask=%.2f", symbol, bestBid, bestAsk)
+	t.log().Infof("✓ Lighter order book: %s bid=%.2f, ask=%.2f", symbol, bestBid, bestAsk)
 	return bestBid, bestAsk, nil
 }
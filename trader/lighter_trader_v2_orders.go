@@ -7,7 +7,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"nofx/logger"
 	"strconv"
 
 	"github.com/elliottech/lighter-go/types"
@@ -20,7 +19,7 @@ func (t *LighterTraderV2) SetStopLoss(symbol string, positionSide string, quanti
 		return fmt.Errorf("TxClient not initialized")
 	}
 
-	logger.Infof("🛑 LIGHTER Setting stop-loss: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, stopPrice)
+	t.log().Infof("🛑 LIGHTER Setting stop-loss: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, stopPrice)
 
 	// Determine order direction (long position uses sell order, short position uses buy order)
 	isAsk := (positionSide == "LONG" || positionSide == "long")
@@ -31,7 +30,7 @@ func (t *LighterTraderV2) SetStopLoss(symbol string, positionSide string, quanti
 		return fmt.Errorf("failed to set stop-loss: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER stop-loss set: trigger=%.2f", stopPrice)
+	t.log().Infof("✓ LIGHTER stop-loss set: trigger=%.2f", stopPrice)
 	return nil
 }
 
@@ -42,7 +41,7 @@ func (t *LighterTraderV2) SetTakeProfit(symbol string, positionSide string, quan
 		return fmt.Errorf("TxClient not initialized")
 	}
 
-	logger.Infof("🎯 LIGHTER Setting take-profit: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, takeProfitPrice)
+	t.log().Infof("🎯 LIGHTER Setting take-profit: %s %s qty=%.4f, trigger=%.2f", symbol, positionSide, quantity, takeProfitPrice)
 
 	// Determine order direction (long position uses sell order, short position uses buy order)
 	isAsk := (positionSide == "LONG" || positionSide == "long")
@@ -53,7 +52,7 @@ func (t *LighterTraderV2) SetTakeProfit(symbol string, positionSide string, quan
 		return fmt.Errorf("failed to set take-profit: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER take-profit set: trigger=%.2f", takeProfitPrice)
+	t.log().Infof("✓ LIGHTER take-profit set: trigger=%.2f", takeProfitPrice)
 	return nil
 }
 
@@ -74,7 +73,7 @@ func (t *LighterTraderV2) CancelAllOrders(symbol string) error {
 	}
 
 	if len(orders) == 0 {
-		logger.Infof("✓ LIGHTER - No orders to cancel (no active orders)")
+		t.log().Infof("✓ LIGHTER - No orders to cancel (no active orders)")
 		return nil
 	}
 
@@ -82,13 +81,13 @@ func (t *LighterTraderV2) CancelAllOrders(symbol string) error {
 	canceledCount := 0
 	for _, order := range orders {
 		if err := t.CancelOrder(symbol, order.OrderID); err != nil {
-			logger.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
+			t.log().Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
 		} else {
 			canceledCount++
 		}
 	}
 
-	logger.Infof("✓ LIGHTER - Canceled %d orders", canceledCount)
+	t.log().Infof("✓ LIGHTER - Canceled %d orders", canceledCount)
 	return nil
 }
 
@@ -156,14 +155,14 @@ func (t *LighterTraderV2) GetOrderStatus(symbol string, orderID string) (map[str
 // CancelStopLossOrders Cancel only stop-loss orders (implements Trader interface)
 func (t *LighterTraderV2) CancelStopLossOrders(symbol string) error {
 	// LIGHTER cannot distinguish between stop-loss and take-profit orders yet, will cancel all stop orders
-	logger.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
+	t.log().Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
 	return t.CancelStopOrders(symbol)
 }
 
 // CancelTakeProfitOrders Cancel only take-profit orders (implements Trader interface)
 func (t *LighterTraderV2) CancelTakeProfitOrders(symbol string) error {
 	// LIGHTER cannot distinguish between stop-loss and take-profit orders yet, will cancel all stop orders
-	logger.Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
+	t.log().Infof("⚠️  LIGHTER cannot distinguish stop-loss/take-profit orders, will cancel all stop orders")
 	return t.CancelStopOrders(symbol)
 }
 
@@ -188,13 +187,13 @@ func (t *LighterTraderV2) CancelStopOrders(symbol string) error {
 		// TODO: Check order type, only cancel stop orders
 		// For now, cancel all orders
 		if err := t.CancelOrder(symbol, order.OrderID); err != nil {
-			logger.Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
+			t.log().Infof("⚠️  Failed to cancel order (ID: %s): %v", order.OrderID, err)
 		} else {
 			canceledCount++
 		}
 	}
 
-	logger.Infof("✓ LIGHTER - Canceled %d stop orders", canceledCount)
+	t.log().Infof("✓ LIGHTER - Canceled %d stop orders", canceledCount)
 	return nil
 }
 
@@ -250,7 +249,7 @@ func (t *LighterTraderV2) GetActiveOrders(symbol string) ([]OrderResponse, error
 		return nil, fmt.Errorf("failed to get active orders (code %d): %s", apiResp.Code, apiResp.Message)
 	}
 
-	logger.Infof("✓ LIGHTER - Retrieved %d active orders", len(apiResp.Data))
+	t.log().Infof("✓ LIGHTER - Retrieved %d active orders", len(apiResp.Data))
 	return apiResp.Data, nil
 }
 
@@ -300,7 +299,7 @@ func (t *LighterTraderV2) CancelOrder(symbol, orderID string) error {
 		return fmt.Errorf("failed to submit cancel order: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER order canceled - ID: %s", orderID)
+	t.log().Infof("✓ LIGHTER order canceled - ID: %s", orderID)
 	return nil
 }
 
@@ -363,6 +362,6 @@ func (t *LighterTraderV2) submitCancelOrder(signedTx []byte) (map[string]interfa
 		"status":  "cancelled",
 	}
 
-	logger.Infof("✓ Cancel order submitted to LIGHTER - tx_hash: %v", sendResp.Data["tx_hash"])
+	t.log().Infof("✓ Cancel order submitted to LIGHTER - tx_hash: %v", sendResp.Data["tx_hash"])
 	return result, nil
 }
@@ -7,7 +7,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"nofx/logger"
 	"strconv"
 	"strings"
 	"time"
@@ -21,16 +20,16 @@ func (t *LighterTraderV2) OpenLong(symbol string, quantity float64, leverage int
 		return nil, fmt.Errorf("TxClient not initialized, please set API Key first")
 	}
 
-	logger.Infof("📈 LIGHTER opening long: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
+	t.log().Infof("📈 LIGHTER opening long: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
 
 	// 1. First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel old pending orders: %v", err)
+		t.log().Infof("⚠️  Failed to cancel old pending orders: %v", err)
 	}
 
 	// 2. Set leverage (if needed)
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️  Failed to set leverage: %v", err)
+		t.log().Infof("⚠️  Failed to set leverage: %v", err)
 	}
 
 	// 3. Get market price
@@ -45,7 +44,7 @@ func (t *LighterTraderV2) OpenLong(symbol string, quantity float64, leverage int
 		return nil, fmt.Errorf("failed to open long: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER opened long successfully: %s @ %.2f", symbol, marketPrice)
+	t.log().Infof("✓ LIGHTER opened long successfully: %s @ %.2f", symbol, marketPrice)
 
 	return map[string]interface{}{
 		"orderId": orderResult["orderId"],
@@ -62,16 +61,16 @@ func (t *LighterTraderV2) OpenShort(symbol string, quantity float64, leverage in
 		return nil, fmt.Errorf("TxClient not initialized, please set API Key first")
 	}
 
-	logger.Infof("📉 LIGHTER opening short: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
+	t.log().Infof("📉 LIGHTER opening short: %s, qty=%.4f, leverage=%dx", symbol, quantity, leverage)
 
 	// 1. First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel old pending orders: %v", err)
+		t.log().Infof("⚠️  Failed to cancel old pending orders: %v", err)
 	}
 
 	// 2. Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️  Failed to set leverage: %v", err)
+		t.log().Infof("⚠️  Failed to set leverage: %v", err)
 	}
 
 	// 3. Get market price
@@ -86,7 +85,7 @@ func (t *LighterTraderV2) OpenShort(symbol string, quantity float64, leverage in
 		return nil, fmt.Errorf("failed to open short: %w", err)
 	}
 
-	logger.Infof("✓ LIGHTER opened short successfully: %s @ %.2f", symbol, marketPrice)
+	t.log().Infof("✓ LIGHTER opened short successfully: %s @ %.2f", symbol, marketPrice)
 
 	return map[string]interface{}{
 		"orderId": orderResult["orderId"],
@@ -118,11 +117,11 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 		quantity = pos.Size
 	}
 
-	logger.Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
+	t.log().Infof("🔻 LIGHTER closing long: %s, qty=%.4f", symbol, quantity)
 
 	// Cancel pending orders before closing
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel orders: %v", err)
+		t.log().Infof("⚠️  Failed to cancel orders: %v", err)
 	}
 
 	// Create market sell order to close (reduceOnly=true)
@@ -132,7 +131,7 @@ func (t *LighterTraderV2) CloseLong(symbol string, quantity float64) (map[string
 	}
 
 	txHash, _ := orderResult["orderId"].(string)
-	logger.Infof("✓ LIGHTER closed long successfully: %s (tx: %s)", symbol, txHash)
+	t.log().Infof("✓ LIGHTER closed long successfully: %s (tx: %s)", symbol, txHash)
 
 	return map[string]interface{}{
 		"orderId": txHash,
@@ -162,11 +161,11 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 		quantity = pos.Size
 	}
 
-	logger.Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
+	t.log().Infof("🔺 LIGHTER closing short: %s, qty=%.4f", symbol, quantity)
 
 	// Cancel pending orders before closing
 	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️  Failed to cancel orders: %v", err)
+		t.log().Infof("⚠️  Failed to cancel orders: %v", err)
 	}
 
 	// Create market buy order to close (reduceOnly=true)
@@ -176,7 +175,7 @@ func (t *LighterTraderV2) CloseShort(symbol string, quantity float64) (map[strin
 	}
 
 	txHash, _ := orderResult["orderId"].(string)
-	logger.Infof("✓ LIGHTER closed short successfully: %s (tx: %s)", symbol, txHash)
+	t.log().Infof("✓ LIGHTER closed short successfully: %s (tx: %s)", symbol, txHash)
 
 	return map[string]interface{}{
 		"orderId": txHash,
@@ -209,14 +208,14 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 
 	// Convert quantity to LIGHTER base_amount format using dynamic precision from API
 	baseAmount := int64(quantity * float64(pow10(marketInfo.SizeDecimals)))
-	logger.Infof("🔸 Using size precision: %d decimals, quantity=%.4f → baseAmount=%d",
+	t.log().Infof("🔸 Using size precision: %d decimals, quantity=%.4f → baseAmount=%d",
 		marketInfo.SizeDecimals, quantity, baseAmount)
 
 	// Set price based on order type
 	priceValue := uint32(0)
 	if orderType == "limit" {
 		priceValue = uint32(price * float64(pow10(marketInfo.PriceDecimals)))
-		logger.Infof("🔸 LIMIT order - Price: %.2f (precision: %d decimals)", price, marketInfo.PriceDecimals)
+		t.log().Infof("🔸 LIMIT order - Price: %.2f (precision: %d decimals)", price, marketInfo.PriceDecimals)
 	} else {
 		// Market order - Price field is used as PRICE PROTECTION (slippage limit)
 		// NOT as the execution price! Set it wider to allow order to fill.
@@ -225,20 +224,15 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 			return nil, fmt.Errorf("failed to get market price: %w", err)
 		}
 
-		// For BUY: set price protection ABOVE market (allow buying up to 105% of market price)
-		// For SELL: set price protection BELOW market (allow selling down to 95% of market price)
-		var protectedPrice float64
+		// For BUY: set price protection ABOVE market, for SELL: BELOW market (default 5%)
+		slippageBps := t.slippage.bpsFor(symbol, lighterDefaultSlippageBps)
+		protectedPrice := aggressivePrice(marketPrice, !isAsk, slippageBps)
+		side := "BUY"
 		if isAsk {
-			// Selling: accept down to 95% of market price
-			protectedPrice = marketPrice * 0.95
-			logger.Infof("🔸 MARKET SELL order - Price protection: %.2f (95%% of market %.2f, precision: %d decimals)",
-				protectedPrice, marketPrice, marketInfo.PriceDecimals)
-		} else {
-			// Buying: accept up to 105% of market price
-			protectedPrice = marketPrice * 1.05
-			logger.Infof("🔸 MARKET BUY order - Price protection: %.2f (105%% of market %.2f, precision: %d decimals)",
-				protectedPrice, marketPrice, marketInfo.PriceDecimals)
+			side = "SELL"
 		}
+		t.log().Infof("🔸 MARKET %s order - Price protection: %.2f (%.0f bps from market %.2f, precision: %d decimals)",
+			side, protectedPrice, slippageBps, marketPrice, marketInfo.PriceDecimals)
 		priceValue = uint32(protectedPrice * float64(pow10(marketInfo.PriceDecimals)))
 	}
 
@@ -288,7 +282,7 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 	}
 
 	// Debug: Log the tx_info content
-	logger.Infof("DEBUG tx_type: %d, tx_info: %s", tx.GetTxType(), txInfo)
+	t.log().Infof("DEBUG tx_type: %d, tx_info: %s", tx.GetTxType(), txInfo)
 
 	// Submit order to LIGHTER API
 	orderResp, err := t.submitOrder(int(tx.GetTxType()), txInfo)
@@ -300,7 +294,7 @@ func (t *LighterTraderV2) CreateOrder(symbol string, isAsk bool, quantity float6
 	if isAsk {
 		side = "sell"
 	}
-	logger.Infof("✓ LIGHTER order created: %s %s qty=%.4f", symbol, side, quantity)
+	t.log().Infof("✓ LIGHTER order created: %s %s qty=%.4f", symbol, side, quantity)
 
 	return orderResp, nil
 }
@@ -386,7 +380,7 @@ func (t *LighterTraderV2) submitOrder(txType int, txInfo string) (map[string]int
 	}
 
 	// Log full response for debugging
-	logger.Infof("DEBUG API response: %s", string(respBody))
+	t.log().Infof("DEBUG API response: %s", string(respBody))
 
 	// Check response code
 	if sendResp.Code != 200 {
@@ -409,7 +403,7 @@ func (t *LighterTraderV2) submitOrder(txType int, txInfo string) (map[string]int
 		"orderId": txHash, // Use tx_hash as orderId
 	}
 
-	logger.Infof("✓ Order submitted to LIGHTER - tx_hash: %s", txHash)
+	t.log().Infof("✓ Order submitted to LIGHTER - tx_hash: %s", txHash)
 
 	return result, nil
 }
@@ -452,7 +446,7 @@ func (t *LighterTraderV2) getMarketIndex(symbol string) (uint16, error) {
 	marketInfo, err := t.getMarketInfo(symbol)
 	if err != nil {
 		// Fallback to hardcoded mapping
-		logger.Infof("⚠️  Failed to get market info from API, using hardcoded mapping: %v", err)
+		t.log().Infof("⚠️  Failed to get market info from API, using hardcoded mapping: %v", err)
 		normalizedSymbol := normalizeSymbol(symbol)
 		return t.getFallbackMarketIndex(normalizedSymbol)
 	}
@@ -522,7 +516,7 @@ func (t *LighterTraderV2) fetchMarketList() ([]MarketInfo, error) {
 		}
 	}
 
-	logger.Infof("✓ Retrieved %d active markets from Lighter", len(markets))
+	t.log().Infof("✓ Retrieved %d active markets from Lighter", len(markets))
 	return markets, nil
 }
 
@@ -542,7 +536,7 @@ func (t *LighterTraderV2) getFallbackMarketIndex(symbol string) (uint16, error)
 	}
 
 	if index, ok := fallbackMap[symbol]; ok {
-		logger.Infof("✓ Using hardcoded market index: %s -> %d", symbol, index)
+		t.log().Infof("✓ Using hardcoded market index: %s -> %d", symbol, index)
 		return index, nil
 	}
 
@@ -556,7 +550,7 @@ func (t *LighterTraderV2) SetLeverage(symbol string, leverage int) error {
 	}
 
 	// TODO: Sign and submit SetLeverage transaction using SDK
-	logger.Infof("⚙️  Setting leverage: %s = %dx", symbol, leverage)
+	t.log().Infof("⚙️  Setting leverage: %s = %dx", symbol, leverage)
 
 	return nil // Return success for now
 }
@@ -572,7 +566,7 @@ func (t *LighterTraderV2) SetMarginMode(symbol string, isCrossMargin bool) error
 		modeStr = "cross"
 	}
 
-	logger.Infof("⚙️  Setting margin mode: %s = %s", symbol, modeStr)
+	t.log().Infof("⚙️  Setting margin mode: %s = %s", symbol, modeStr)
 
 	// TODO: Sign and submit SetMarginMode transaction using SDK
 	return nil
@@ -653,7 +647,7 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 		return nil, fmt.Errorf("failed to get tx info: %w", err)
 	}
 
-	logger.Infof("DEBUG stop order - type: %d, trigger: %.2f, price: %.2f, isAsk: %v", orderTypeValue, triggerPrice, float64(priceValue)/100, isAsk)
+	t.log().Infof("DEBUG stop order - type: %d, trigger: %.2f, price: %.2f, isAsk: %v", orderTypeValue, triggerPrice, float64(priceValue)/100, isAsk)
 
 	// Submit order
 	orderResp, err := t.submitOrder(int(tx.GetTxType()), txInfo)
@@ -665,7 +659,7 @@ func (t *LighterTraderV2) CreateStopOrder(symbol string, isAsk bool, quantity fl
 	if isAsk {
 		side = "sell"
 	}
-	logger.Infof("✓ LIGHTER %s order created: %s %s qty=%.4f trigger=%.2f", orderType, symbol, side, quantity, triggerPrice)
+	t.log().Infof("✓ LIGHTER %s order created: %s %s qty=%.4f trigger=%.2f", orderType, symbol, side, quantity, triggerPrice)
 
 	return orderResp, nil
 }
@@ -0,0 +1,77 @@
+package trader
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultMaintMarginRatio maintenance margin ratio assumed when the exchange doesn't expose one
+// (lowest tier of major perps on most CEXs)
+const defaultMaintMarginRatio = 0.005
+
+// MaintenanceMarginTier one maintenance margin bracket: a position with notional up to NotionalCap
+// needs notional * MaintMarginRatio - MaintAmount of maintenance margin
+type MaintenanceMarginTier struct {
+	NotionalCap      float64 `json:"notional_cap"` // 0 = no cap (last tier)
+	MaintMarginRatio float64 `json:"maint_margin_ratio"`
+	MaintAmount      float64 `json:"maint_amount"` // Maintenance amount deduction (Binance "cum")
+}
+
+// MaintenanceTierFor returns the maintenance margin tier of a position notional.
+// Without exchange tiers, Hyperliquid-style half of the max-leverage initial margin is used
+// when the max leverage is known, otherwise defaultMaintMarginRatio.
+func MaintenanceTierFor(info *SymbolInfo, notional float64) MaintenanceMarginTier {
+	if info != nil && len(info.MaintenanceTiers) > 0 {
+		tiers := append([]MaintenanceMarginTier(nil), info.MaintenanceTiers...)
+		sort.Slice(tiers, func(i, j int) bool {
+			// Uncapped tier last
+			if tiers[i].NotionalCap == 0 || tiers[j].NotionalCap == 0 {
+				return tiers[j].NotionalCap == 0 && tiers[i].NotionalCap != 0
+			}
+			return tiers[i].NotionalCap < tiers[j].NotionalCap
+		})
+		for _, tier := range tiers {
+			if tier.NotionalCap == 0 || notional <= tier.NotionalCap {
+				return tier
+			}
+		}
+		return tiers[len(tiers)-1]
+	}
+	if info != nil && info.MaxLeverage > 0 {
+		return MaintenanceMarginTier{MaintMarginRatio: 1 / (2 * float64(info.MaxLeverage))}
+	}
+	return MaintenanceMarginTier{MaintMarginRatio: defaultMaintMarginRatio}
+}
+
+// EstimateLiquidationPrice estimates the liquidation price of a new isolated-margin position
+// opened at entryPrice, using the exchange maintenance margin formula
+//
+//	LONG:  (entry * (1 - 1/leverage) - maintAmount/qty) / (1 - mmr)
+//	SHORT: (entry * (1 + 1/leverage) + maintAmount/qty) / (1 + mmr)
+//
+// Fees and funding are ignored; cross-margin positions liquidate further away, since the whole
+// account balance backs them. Returns 0 for invalid input or when the position can't be liquidated.
+func EstimateLiquidationPrice(side string, entryPrice, quantity float64, leverage int, tier MaintenanceMarginTier) float64 {
+	if entryPrice <= 0 || quantity <= 0 || leverage <= 0 {
+		return 0
+	}
+	mmr := tier.MaintMarginRatio
+	lev := float64(leverage)
+
+	var price float64
+	switch strings.ToUpper(side) {
+	case "LONG":
+		if mmr >= 1 {
+			return 0
+		}
+		price = (entryPrice*(1-1/lev) - tier.MaintAmount/quantity) / (1 - mmr)
+	case "SHORT":
+		price = (entryPrice*(1+1/lev) + tier.MaintAmount/quantity) / (1 + mmr)
+	default:
+		return 0
+	}
+	if price < 0 {
+		return 0
+	}
+	return price
+}
@@ -0,0 +1,55 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateLiquidationPrice(t *testing.T) {
+	tier := MaintenanceMarginTier{MaintMarginRatio: 0.005}
+	tests := []struct {
+		name     string
+		side     string
+		leverage int
+		tier     MaintenanceMarginTier
+		want     float64
+	}{
+		{name: "long 10x", side: "LONG", leverage: 10, tier: tier, want: 100 * 0.9 / 0.995},
+		{name: "short 10x", side: "SHORT", leverage: 10, tier: tier, want: 100 * 1.1 / 1.005},
+		{name: "long 1x", side: "long", leverage: 1, tier: tier, want: 0},
+		// Binance BTC tier 2 style deduction: cum 50 on a 1 BTC position
+		{name: "long with maint amount", side: "LONG", leverage: 20, tier: MaintenanceMarginTier{MaintMarginRatio: 0.005, MaintAmount: 50}, want: (100*0.95 - 50) / 0.995},
+		{name: "invalid side", side: "BOTH", leverage: 10, tier: tier, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateLiquidationPrice(tt.side, 100, 1, tt.leverage, tt.tier)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EstimateLiquidationPrice() = %.6f, want %.6f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceTierFor(t *testing.T) {
+	info := &SymbolInfo{MaxLeverage: 125, MaintenanceTiers: []MaintenanceMarginTier{
+		{NotionalCap: 0, MaintMarginRatio: 0.5},
+		{NotionalCap: 250000, MaintMarginRatio: 0.005, MaintAmount: 50},
+		{NotionalCap: 50000, MaintMarginRatio: 0.004},
+	}}
+	if got := MaintenanceTierFor(info, 1000); got.MaintMarginRatio != 0.004 {
+		t.Errorf("small notional tier = %+v, want 0.4%%", got)
+	}
+	if got := MaintenanceTierFor(info, 100000); got.MaintAmount != 50 {
+		t.Errorf("mid notional tier = %+v, want cum 50", got)
+	}
+	if got := MaintenanceTierFor(info, 1e9); got.MaintMarginRatio != 0.5 {
+		t.Errorf("huge notional tier = %+v, want uncapped tier", got)
+	}
+	if got := MaintenanceTierFor(&SymbolInfo{MaxLeverage: 50}, 1000); got.MaintMarginRatio != 0.01 {
+		t.Errorf("max leverage fallback = %v, want 0.01", got.MaintMarginRatio)
+	}
+	if got := MaintenanceTierFor(nil, 1000); got.MaintMarginRatio != defaultMaintMarginRatio {
+		t.Errorf("default = %v", got.MaintMarginRatio)
+	}
+}
@@ -0,0 +1,123 @@
+package trader
+
+import (
+	"fmt"
+	"time"
+
+	"nofx/store"
+)
+
+const (
+	// defaultLossCooldown cool-off after a losing streak when risk_control.loss_cooldown_minutes is not set
+	defaultLossCooldown = 60 * time.Minute
+	// lossStreakLookback closed positions inspected when counting the losing streak
+	lossStreakLookback = 50
+)
+
+// lossStreakState losing streak tracking for the consecutive-loss cool-off
+type lossStreakState struct {
+	count         int       // Consecutive losing closes since the last win (or the last cool-off)
+	cooldownUntil time.Time // End of the current cool-off (zero = none)
+	countedSince  int64     // Exit time (ms) of the loss that triggered the last cool-off, earlier losses no longer count
+}
+
+// maxConsecutiveLosses returns risk_control.max_consecutive_losses (0 = disabled)
+func (at *AutoTrader) maxConsecutiveLosses() int {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.MaxConsecutiveLosses
+}
+
+// lossCooldown returns risk_control.loss_cooldown_minutes (default 60)
+func (at *AutoTrader) lossCooldown() time.Duration {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.LossCooldownMinutes > 0 {
+		return time.Duration(at.config.StrategyConfig.RiskControl.LossCooldownMinutes) * time.Minute
+	}
+	return defaultLossCooldown
+}
+
+// consecutiveLosses counts the losing closes at the head of positions (newest first) that exited
+// after sinceMs, stopping at the first win. Breakeven closes neither count nor break the streak.
+// Returns the streak length and the exit time (ms) of the most recent loss.
+func consecutiveLosses(positions []*store.TraderPosition, sinceMs int64) (int, int64) {
+	count, lastLossMs := 0, int64(0)
+	for _, pos := range positions {
+		if pos.ExitTime <= sinceMs || pos.RealizedPnL > 0 {
+			break
+		}
+		if pos.RealizedPnL < 0 {
+			if count == 0 {
+				lastLossMs = pos.ExitTime
+			}
+			count++
+		}
+	}
+	return count, lastLossMs
+}
+
+// checkLossStreak recounts the losing streak from the trader's closed positions and, once it reaches
+// risk_control.max_consecutive_losses, pauses new trading (stopUntil) for the cool-off counted from
+// the last loss. The losses that triggered a cool-off don't count toward the next one; a win resets
+// the streak.
+func (at *AutoTrader) checkLossStreak(now time.Time) {
+	limit := at.maxConsecutiveLosses()
+	if limit <= 0 || at.store == nil {
+		return
+	}
+
+	positions, err := at.store.Position().GetClosedPositions(at.id, lossStreakLookback)
+	if err != nil {
+		at.log().Warnf("⚠️ [%s] Failed to check losing streak: %v", at.name, err)
+		return
+	}
+
+	at.lossStreakMu.Lock()
+	streak, lastLossMs := consecutiveLosses(positions, at.lossStreak.countedSince)
+	at.lossStreak.count = streak
+	if streak < limit {
+		at.lossStreakMu.Unlock()
+		return
+	}
+
+	cooldown := at.lossCooldown()
+	until := time.UnixMilli(lastLossMs).Add(cooldown)
+	at.lossStreak.countedSince = lastLossMs
+	if !until.After(now) {
+		at.lossStreakMu.Unlock()
+		return // The cool-off already elapsed (e.g. the losses happened while the trader was stopped)
+	}
+	at.lossStreak.cooldownUntil = until
+	at.lossStreakMu.Unlock()
+
+	reason := fmt.Sprintf("%d consecutive losing trades, cooling off for %v", streak, cooldown)
+	at.extendRiskPause(until, reason)
+	at.notify(NotificationLossCooldown, fmt.Sprintf("%s (until %s)", reason, until.UTC().Format(time.RFC3339)))
+}
+
+// riskPause returns the end of the risk-control trading pause and why it was started
+func (at *AutoTrader) riskPause() (time.Time, string) {
+	at.stopMu.RLock()
+	defer at.stopMu.RUnlock()
+	return at.stopUntil, at.stopReason
+}
+
+// extendRiskPause pauses new trading until the given time, unless a longer pause is already active
+func (at *AutoTrader) extendRiskPause(until time.Time, reason string) {
+	at.stopMu.Lock()
+	defer at.stopMu.Unlock()
+	if until.After(at.stopUntil) {
+		at.stopUntil = until
+		at.stopReason = reason
+	}
+}
+
+// lossStreakStatus returns the current losing streak and the end of the active cool-off (zero if none)
+func (at *AutoTrader) lossStreakStatus(now time.Time) (int, time.Time) {
+	at.lossStreakMu.Lock()
+	defer at.lossStreakMu.Unlock()
+	if !at.lossStreak.cooldownUntil.After(now) {
+		return at.lossStreak.count, time.Time{}
+	}
+	return at.lossStreak.count, at.lossStreak.cooldownUntil
+}
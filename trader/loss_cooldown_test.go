@@ -0,0 +1,80 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+func TestConsecutiveLosses(t *testing.T) {
+	// Newest first
+	positions := []*store.TraderPosition{
+		{ExitTime: 500, RealizedPnL: -1},
+		{ExitTime: 400, RealizedPnL: 0}, // breakeven doesn't break the streak
+		{ExitTime: 300, RealizedPnL: -2},
+		{ExitTime: 200, RealizedPnL: 5},
+		{ExitTime: 100, RealizedPnL: -3},
+	}
+	if n, last := consecutiveLosses(positions, 0); n != 2 || last != 500 {
+		t.Errorf("consecutiveLosses() = %d, %d, want 2 losses, last at 500", n, last)
+	}
+	if n, _ := consecutiveLosses(positions, 300); n != 1 {
+		t.Errorf("losses after 300 = %d, want 1", n)
+	}
+	if n, _ := consecutiveLosses(positions[3:], 0); n != 0 {
+		t.Errorf("streak after a win = %d, want 0", n)
+	}
+}
+
+func TestCheckLossStreak(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "streak.db"))
+	if err != nil {
+		t.Fatalf("Failed to init test store: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now()
+	closeAt := func(ago time.Duration, pnl float64) {
+		exit := now.Add(-ago).UnixMilli()
+		pos := &store.TraderPosition{
+			TraderID: "trader-1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: exit - 60000,
+		}
+		if err := st.Position().Create(pos); err != nil {
+			t.Fatalf("Create position error = %v", err)
+		}
+		if err := st.Position().ClosePositionFully(pos.ID, 100+pnl, "", exit, pnl, 0, "manual"); err != nil {
+			t.Fatalf("Close position error = %v", err)
+		}
+	}
+	closeAt(50*time.Minute, 3)
+	closeAt(40*time.Minute, -1)
+	closeAt(30*time.Minute, -2)
+
+	at := &AutoTrader{id: "trader-1", store: st, config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{}}}
+	at.config.StrategyConfig.RiskControl.MaxConsecutiveLosses = 3
+	at.config.StrategyConfig.RiskControl.LossCooldownMinutes = 60
+
+	at.checkLossStreak(now)
+	if streak, until := at.lossStreakStatus(now); streak != 2 || !until.IsZero() || !at.stopUntil.IsZero() {
+		t.Fatalf("after 2 losses: streak %d, cool-off %v, stopUntil %v, want streak 2 without cool-off", streak, until, at.stopUntil)
+	}
+
+	closeAt(10*time.Minute, -0.5)
+	at.checkLossStreak(now)
+	want := now.Add(-10 * time.Minute).Add(time.Hour).Truncate(time.Millisecond)
+	if !at.stopUntil.Equal(want) || at.stopReason == "" {
+		t.Fatalf("stopUntil = %v (%q), want %v with a reason", at.stopUntil, at.stopReason, want)
+	}
+	if _, until := at.lossStreakStatus(now); !until.Equal(want) {
+		t.Errorf("cool-off until %v, want %v", until, want)
+	}
+
+	// The losses that triggered the cool-off don't trigger it again
+	at.stopUntil = time.Time{}
+	at.checkLossStreak(now)
+	if streak, _ := at.lossStreakStatus(now); streak != 0 || !at.stopUntil.IsZero() {
+		t.Errorf("after cool-off: streak %d, stopUntil %v, want a fresh streak", streak, at.stopUntil)
+	}
+}
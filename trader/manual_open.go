@@ -0,0 +1,141 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+)
+
+// ErrRiskControlPaused returned for manual and external entries while the trader's risk control pauses trading
+// (losing-streak cool-off, unhealthy order sync)
+var ErrRiskControlPaused = errors.New("trading paused by risk control")
+
+// ManualOpenRequest discretionary entry placed through a running trader's account
+type ManualOpenRequest struct {
+	Symbol     string
+	Side       string // "long" or "short"
+	SizeUSD    float64
+	Leverage   int
+	StopLoss   float64 // 0 = none
+	TakeProfit float64 // 0 = none
+}
+
+// Decision validates the request and converts it to the decision the executors run
+func (r ManualOpenRequest) Decision() (*kernel.Decision, error) {
+	var action string
+	switch strings.ToLower(r.Side) {
+	case "long":
+		action = "open_long"
+	case "short":
+		action = "open_short"
+	default:
+		return nil, fmt.Errorf("side must be long or short")
+	}
+
+	d := &kernel.Decision{
+		Symbol:          r.Symbol,
+		Action:          action,
+		Leverage:        r.Leverage,
+		PositionSizeUSD: r.SizeUSD,
+		StopLoss:        r.StopLoss,
+		TakeProfit:      r.TakeProfit,
+		Reasoning:       "Manual open",
+	}
+	if err := ValidateSignal(&Signal{Decision: d}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// riskControlPause returns why the cycle's risk control currently blocks new trades ("" = not paused)
+func (at *AutoTrader) riskControlPause(now time.Time) string {
+	at.checkLossStreak(now)
+	if stopUntil, stopReason := at.riskPause(); now.Before(stopUntil) {
+		reason := ""
+		if stopReason != "" {
+			reason = " (" + stopReason + ")"
+		}
+		return fmt.Sprintf("risk control paused%s, remaining %.0f minutes", reason, stopUntil.Sub(now).Minutes())
+	}
+	if at.orderSyncHealth != nil && at.config.OrderSync.PauseOnUnhealthy && !at.orderSyncHealth.IsHealthy() {
+		return fmt.Sprintf("order sync unhealthy (%d consecutive failures)", at.orderSyncHealth.ConsecutiveFailures())
+	}
+	return ""
+}
+
+// OpenManualPosition executes a user's discretionary entry like one of the AI's: the cycle's pauses
+// and the executors' code-enforced limits apply, and the action is saved as its own decision record
+// under its own correlation ID. It waits for a running cycle to finish, so the two never trade at once.
+func (at *AutoTrader) OpenManualPosition(req ManualOpenRequest) (action *store.DecisionAction, err error) {
+	if req.Symbol != "" {
+		req.Symbol = at.normalizeSymbol(req.Symbol)
+	}
+	d, err := req.Decision()
+	if err != nil {
+		return nil, err
+	}
+
+	at.isRunningMutex.RLock()
+	running := at.isRunning
+	at.isRunningMutex.RUnlock()
+	if !running {
+		return nil, ErrSignalTraderNotRunning
+	}
+
+	at.execMu.Lock()
+	defer at.execMu.Unlock()
+	if IsTradingPaused() {
+		return nil, ErrTradingPaused
+	}
+	if reason := at.riskControlPause(time.Now()); reason != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRiskControlPaused, reason)
+	}
+
+	at.withExecTrace(func(ctx context.Context) {
+		action, err = at.executeManualOpen(ctx, d)
+	})
+	return action, err
+}
+
+// executeManualOpen executes a manual open's decision and saves its decision record
+func (at *AutoTrader) executeManualOpen(ctx context.Context, d *kernel.Decision) (*store.DecisionAction, error) {
+	at.log().Infof("🖐 [%s] Manual open: %s %s %.2f USDT %dx", at.name, d.Symbol, d.Action, d.PositionSizeUSD, d.Leverage)
+
+	action := &store.DecisionAction{
+		Symbol:     d.Symbol,
+		Action:     d.Action,
+		Leverage:   d.Leverage,
+		StopLoss:   d.StopLoss,
+		TakeProfit: d.TakeProfit,
+		Reasoning:  d.Reasoning,
+		Timestamp:  time.Now().UTC(),
+	}
+	record := &store.DecisionRecord{
+		ExecutionLog: []string{},
+		Success:      true,
+		TraceID:      logger.TraceID(ctx),
+	}
+
+	execErr := at.executeExternalDecision(d, action)
+	if execErr != nil {
+		action.Error = execErr.Error()
+		record.Success = false
+		record.ErrorMessage = fmt.Sprintf("Manual open failed: %v", execErr)
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s (manual) failed: %v", d.Symbol, d.Action, execErr))
+	} else {
+		action.Success = true
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s (manual) succeeded", d.Symbol, d.Action))
+	}
+	record.Decisions = []store.DecisionAction{*action}
+
+	if err := at.saveDecision(record); err != nil {
+		at.log().Infof("⚠ Failed to save manual open record: %v", err)
+	}
+	return action, execErr
+}
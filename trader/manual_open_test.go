@@ -0,0 +1,68 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nofx/kernel"
+)
+
+func TestManualOpenRequestDecision(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        ManualOpenRequest
+		wantAction string
+		wantErr    bool
+	}{
+		{"long", ManualOpenRequest{Symbol: "btc", Side: "long", SizeUSD: 100, Leverage: 5}, "open_long", false},
+		{"short upper case", ManualOpenRequest{Symbol: "ETHUSDT", Side: "SHORT", SizeUSD: 50, Leverage: 3, StopLoss: 4000, TakeProfit: 3000}, "open_short", false},
+		{"bad side", ManualOpenRequest{Symbol: "BTCUSDT", Side: "up", SizeUSD: 100, Leverage: 5}, "", true},
+		{"no size", ManualOpenRequest{Symbol: "BTCUSDT", Side: "long", Leverage: 5}, "", true},
+		{"no leverage", ManualOpenRequest{Symbol: "BTCUSDT", Side: "long", SizeUSD: 100}, "", true},
+		{"long stop above target", ManualOpenRequest{Symbol: "BTCUSDT", Side: "long", SizeUSD: 100, Leverage: 5, StopLoss: 70000, TakeProfit: 60000}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.req.Decision()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if d.Action != tt.wantAction {
+				t.Errorf("Action = %s, want %s", d.Action, tt.wantAction)
+			}
+			if d.Symbol == "" || d.Symbol[len(d.Symbol)-4:] != "USDT" {
+				t.Errorf("Symbol = %s, want normalized USDT pair", d.Symbol)
+			}
+		})
+	}
+}
+
+func TestOpenManualPositionBlocked(t *testing.T) {
+	req := ManualOpenRequest{Symbol: "BTCUSDT", Side: "long", SizeUSD: 100, Leverage: 5}
+
+	// No exchange client: reaching the execution path would panic
+	stopped := &AutoTrader{name: "stopped"}
+	if _, err := stopped.OpenManualPosition(req); !errors.Is(err, ErrSignalTraderNotRunning) {
+		t.Errorf("stopped trader error = %v, want ErrSignalTraderNotRunning", err)
+	}
+
+	coolingOff := &AutoTrader{name: "cooling off", isRunning: true, stopUntil: time.Now().Add(time.Hour), stopReason: "3 consecutive losses"}
+	if _, err := coolingOff.OpenManualPosition(req); !errors.Is(err, ErrRiskControlPaused) {
+		t.Errorf("cooling-off trader error = %v, want ErrRiskControlPaused", err)
+	}
+	// Webhook signals and debate decisions go through ExecuteDecision
+	if err := coolingOff.ExecuteDecision(&kernel.Decision{Symbol: "BTCUSDT", Action: "open_long"}); !errors.Is(err, ErrRiskControlPaused) {
+		t.Errorf("cooling-off trader external decision error = %v, want ErrRiskControlPaused", err)
+	}
+
+	SetTradingPaused(true)
+	defer SetTradingPaused(false)
+	running := &AutoTrader{name: "paused", isRunning: true}
+	if _, err := running.OpenManualPosition(req); !errors.Is(err, ErrTradingPaused) {
+		t.Errorf("paused trader error = %v, want ErrTradingPaused", err)
+	}
+}
@@ -0,0 +1,102 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+)
+
+// calculateMarginUsedPct estimates margin usage (% of equity) from exchange positions
+// Same estimate as the trading context: Σ(quantity × markPrice / leverage) / equity
+func calculateMarginUsedPct(positions []map[string]interface{}, equity float64) float64 {
+	if equity <= 0 {
+		return 0
+	}
+
+	totalMarginUsed := 0.0
+	for _, pos := range positions {
+		quantity, _ := pos["positionAmt"].(float64)
+		markPrice, _ := pos["markPrice"].(float64)
+		leverage := 10.0 // Default, same as buildTradingContext
+		if lev, ok := pos["leverage"].(float64); ok && lev > 0 {
+			leverage = lev
+		}
+		totalMarginUsed += math.Abs(quantity) * markPrice / leverage
+	}
+	return totalMarginUsed / equity * 100
+}
+
+// maxTotalMarginUsedPct returns the portfolio margin usage limit in percent (0 = disabled)
+// Falls back to MaxMarginUsage (ratio) when MaxTotalMarginUsedPct is not set
+func (at *AutoTrader) maxTotalMarginUsedPct() float64 {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if rc.MaxTotalMarginUsedPct > 0 {
+		return rc.MaxTotalMarginUsedPct
+	}
+	if rc.MaxMarginUsage > 0 {
+		return rc.MaxMarginUsage * 100
+	}
+	return 0
+}
+
+// enforceMaxTotalMarginUsage blocks new opens while portfolio margin usage exceeds the limit (CODE ENFORCED)
+func (at *AutoTrader) enforceMaxTotalMarginUsage(positions []map[string]interface{}, equity float64) error {
+	limit := at.maxTotalMarginUsedPct()
+	if limit <= 0 {
+		return nil
+	}
+
+	usedPct := calculateMarginUsedPct(positions, equity)
+	if usedPct >= limit {
+		return fmt.Errorf("❌ [RISK CONTROL] Margin usage %.1f%% exceeds limit %.1f%%, new positions blocked", usedPct, limit)
+	}
+	return nil
+}
+
+// legacyAffordableFraction share of the max affordable size used when no free-margin buffer is configured
+const legacyAffordableFraction = 0.98
+
+// capToAffordableSize caps a position size so its margin and fees fit into the available balance
+// while keeping minFreeMarginPct (%) of it free. Required funds per USD of position:
+// 1/leverage margin + 1% of margin + 0.1% fees = 1.01/leverage + 0.001.
+// Without a buffer (minFreeMarginPct <= 0) oversized positions are cut to 98% of the max affordable.
+func capToAffordableSize(positionSizeUSD, availableBalance float64, leverage int, minFreeMarginPct float64) (float64, bool) {
+	if leverage < 1 {
+		leverage = 1
+	}
+	marginFactor := 1.01/float64(leverage) + 0.001
+	if minFreeMarginPct <= 0 {
+		maxAffordable := availableBalance / marginFactor
+		if positionSizeUSD > maxAffordable {
+			return maxAffordable * legacyAffordableFraction, true
+		}
+		return positionSizeUSD, false
+	}
+
+	usable := availableBalance * (1 - math.Min(minFreeMarginPct, 100)/100)
+	maxAffordable := math.Max(usable, 0) / marginFactor
+	if positionSizeUSD > maxAffordable {
+		return maxAffordable, true
+	}
+	return positionSizeUSD, false
+}
+
+// enforceAffordableSize applies capToAffordableSize with the strategy's MinFreeMarginPct (CODE ENFORCED)
+func (at *AutoTrader) enforceAffordableSize(positionSizeUSD, availableBalance float64, leverage int) float64 {
+	var minFreePct float64
+	if at.config.StrategyConfig != nil {
+		minFreePct = at.config.StrategyConfig.RiskControl.MinFreeMarginPct
+	}
+	adjusted, capped := capToAffordableSize(positionSizeUSD, availableBalance, leverage, minFreePct)
+	if capped {
+		if minFreePct > 0 {
+			at.log().Infof("  ⚠️ Position size %.2f exceeds what %.2f available allows with %.0f%% kept free, auto-reducing to %.2f",
+				positionSizeUSD, availableBalance, minFreePct, adjusted)
+		} else {
+			at.log().Infof("  ⚠️ Position size %.2f exceeds max affordable, auto-reducing to %.2f", positionSizeUSD, adjusted)
+		}
+	}
+	return adjusted
+}
@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+)
+
+func TestCalculateMarginUsedPct(t *testing.T) {
+	positions := []map[string]interface{}{
+		{"symbol": "BTCUSDT", "positionAmt": 0.1, "markPrice": 50000.0, "leverage": 10.0}, // 500 margin
+		{"symbol": "ETHUSDT", "positionAmt": -2.0, "markPrice": 2500.0, "leverage": 5.0},  // 1000 margin
+		{"symbol": "SOLUSDT", "positionAmt": 10.0, "markPrice": 100.0},                    // default 10x → 100 margin
+	}
+
+	got := calculateMarginUsedPct(positions, 10000)
+	if math.Abs(got-16) > 1e-9 {
+		t.Errorf("calculateMarginUsedPct() = %.4f, want 16", got)
+	}
+	if calculateMarginUsedPct(positions, 0) != 0 {
+		t.Error("zero equity should return 0")
+	}
+}
+
+func TestEnforceMaxTotalMarginUsage(t *testing.T) {
+	positions := []map[string]interface{}{
+		{"positionAmt": 1.0, "markPrice": 1000.0, "leverage": 1.0}, // 1000 margin
+	}
+
+	tests := []struct {
+		name    string
+		rc      store.RiskControlConfig
+		equity  float64
+		wantErr bool
+	}{
+		{name: "disabled", rc: store.RiskControlConfig{}, equity: 1000},
+		{name: "below limit", rc: store.RiskControlConfig{MaxTotalMarginUsedPct: 80}, equity: 2000},
+		{name: "above limit", rc: store.RiskControlConfig{MaxTotalMarginUsedPct: 80}, equity: 1100, wantErr: true},
+		{name: "fallback to max margin usage", rc: store.RiskControlConfig{MaxMarginUsage: 0.4}, equity: 2000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := &AutoTrader{config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{RiskControl: tt.rc}}}
+			err := at.enforceMaxTotalMarginUsage(positions, tt.equity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("enforceMaxTotalMarginUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCapToAffordableSize(t *testing.T) {
+	// 10x: required funds per USD = 0.101 + 0.001 = 0.102 → 1000 available affords ~9803.92
+	maxAffordable := 1000 / 0.102
+
+	tests := []struct {
+		name       string
+		size       float64
+		minFreePct float64
+		want       float64
+		wantCapped bool
+	}{
+		{name: "fits, no buffer", size: 5000, want: 5000},
+		{name: "legacy 98% cap", size: 20000, want: maxAffordable * 0.98, wantCapped: true},
+		{name: "fits with buffer", size: 5000, minFreePct: 20, want: 5000},
+		{name: "buffer caps below legacy limit", size: 9000, minFreePct: 20, want: 800 / 0.102, wantCapped: true},
+		{name: "full buffer", size: 100, minFreePct: 100, want: 0, wantCapped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, capped := capToAffordableSize(tt.size, 1000, 10, tt.minFreePct)
+			if math.Abs(got-tt.want) > 1e-6 || capped != tt.wantCapped {
+				t.Errorf("capToAffordableSize() = %.4f, %v; want %.4f, %v", got, capped, tt.want, tt.wantCapped)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// closeReasonMaxHoldTime close reason of positions closed for exceeding the max hold time
+const closeReasonMaxHoldTime = "max_hold_time"
+
+// maxHoldMinutes returns risk_control.max_hold_minutes (0 = disabled)
+func (at *AutoTrader) maxHoldMinutes() int {
+	if at.config.StrategyConfig == nil {
+		return 0
+	}
+	return at.config.StrategyConfig.RiskControl.MaxHoldMinutes
+}
+
+// positionEntryTime returns when a position was opened (Unix ms): the recorded position's entry
+// time, else the exchange's creation time. Returns 0 when unknown.
+func (at *AutoTrader) positionEntryTime(symbol, side string, pos map[string]interface{}) (entryTime int64, positionID int64) {
+	if at.store != nil {
+		if dbPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err == nil && dbPos != nil {
+			positionID = dbPos.ID
+			if dbPos.EntryTime > 0 {
+				return dbPos.EntryTime, positionID
+			}
+		}
+	}
+	if createdTime, ok := pos["createdTime"].(int64); ok && createdTime > 0 {
+		return createdTime, positionID
+	}
+	return 0, positionID
+}
+
+// heldTooLong reports whether a position opened at entryTime exceeds the max hold time at now
+func heldTooLong(entryTime int64, maxHoldMinutes int, now time.Time) bool {
+	if entryTime <= 0 || maxHoldMinutes <= 0 {
+		return false
+	}
+	return now.Sub(time.UnixMilli(entryTime)) > time.Duration(maxHoldMinutes)*time.Minute
+}
+
+// enforceMaxHoldTime closes the position if it has been held longer than risk_control.max_hold_minutes.
+// Positions whose entry time is unknown are left alone. Called by the drawdown monitor; returns true
+// if the position was closed.
+func (at *AutoTrader) enforceMaxHoldTime(symbol, side string, pos map[string]interface{}) bool {
+	maxHold := at.maxHoldMinutes()
+	if maxHold <= 0 {
+		return false
+	}
+
+	entryTime, positionID := at.positionEntryTime(symbol, side, pos)
+	if entryTime == 0 {
+		at.log().Debugf("⏱ [%s] Entry time of %s %s unknown, max hold time not enforced", at.name, symbol, side)
+		return false
+	}
+	now := time.Now()
+	if !heldTooLong(entryTime, maxHold, now) {
+		return false
+	}
+
+	held := now.Sub(time.UnixMilli(entryTime)).Round(time.Minute)
+	at.log().Infof("⏱ [%s] %s %s held %v, exceeds max hold time %dm, closing", at.name, symbol, side, held, maxHold)
+	entryPrice, _ := pos["entryPrice"].(float64)
+	markPrice, _ := pos["markPrice"].(float64)
+	amt, _ := pos["positionAmt"].(float64)
+	if err := at.emergencyClosePosition(symbol, side, math.Abs(amt), markPrice, entryPrice); err != nil {
+		at.log().Infof("❌ [%s] Max hold time close failed (%s %s): %v", at.name, symbol, side, err)
+		return false
+	}
+
+	if positionID > 0 {
+		if err := at.store.Position().SetCloseReason(positionID, closeReasonMaxHoldTime); err != nil {
+			at.log().Infof("⚠️ [%s] Failed to record max hold time close reason: %v", at.name, err)
+		}
+	}
+	at.ClearPeakPnLCache(symbol, side)
+	at.notify(NotificationMaxHoldClose, fmt.Sprintf("Closed %s %s after %v (max hold time %dm)", symbol, side, held, maxHold))
+	return true
+}
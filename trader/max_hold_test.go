@@ -0,0 +1,39 @@
+package trader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeldTooLong(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		entryTime int64
+		maxHold   int
+		want      bool
+	}{
+		{name: "within limit", entryTime: now.Add(-59 * time.Minute).UnixMilli(), maxHold: 60, want: false},
+		{name: "beyond limit", entryTime: now.Add(-61 * time.Minute).UnixMilli(), maxHold: 60, want: true},
+		{name: "unknown entry time", entryTime: 0, maxHold: 60, want: false},
+		{name: "disabled", entryTime: now.Add(-48 * time.Hour).UnixMilli(), maxHold: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heldTooLong(tt.entryTime, tt.maxHold, now); got != tt.want {
+				t.Errorf("heldTooLong() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositionEntryTimeFromExchange(t *testing.T) {
+	at := &AutoTrader{}
+	created := time.Now().Add(-2 * time.Hour).UnixMilli()
+	if got, _ := at.positionEntryTime("BTCUSDT", "long", map[string]interface{}{"createdTime": created}); got != created {
+		t.Errorf("entry time = %d, want exchange createdTime %d", got, created)
+	}
+	if got, _ := at.positionEntryTime("BTCUSDT", "long", map[string]interface{}{}); got != 0 {
+		t.Errorf("entry time = %d, want 0 when unknown", got)
+	}
+}
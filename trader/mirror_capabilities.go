@@ -0,0 +1,227 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errMirrorUnsupported returned for a venue that lacks the optional capability an action needs
+var errMirrorUnsupported = errors.New("not supported by this exchange")
+
+// capableTrader returns t as the optional capability C. A mirrored trader has it only when every
+// venue does, since the action is replicated on all of them; otherwise the caller falls back to
+// what it does for an exchange without C (plain orders, a single take profit, ...).
+func capableTrader[C any](t Trader) (C, bool) {
+	if m, ok := t.(*MirrorTrader); ok {
+		for _, v := range m.venues {
+			if _, ok := v.Trader.(C); !ok {
+				var none C
+				return none, false
+			}
+		}
+	}
+	c, ok := t.(C)
+	return c, ok
+}
+
+// mirrorRecordID makes a mirror's exchange record ID (transfer, funding payment) unique among the
+// trader's records, which are deduplicated by ID across venues. Primary IDs are kept as they are.
+func mirrorRecordID(v MirrorVenue, id string) string {
+	if id == "" {
+		return ""
+	}
+	return v.ExchangeID + ":" + id
+}
+
+// OpenLongWithBracket opens a long with attached SL/TP on every venue, split by equity share
+func (m *MirrorTrader) OpenLongWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return m.open("open long with bracket", symbol, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		opener, ok := t.(BracketOrderOpener)
+		if !ok {
+			return nil, errMirrorUnsupported
+		}
+		return opener.OpenLongWithBracket(symbol, q, leverage, stopLoss, takeProfit)
+	})
+}
+
+// OpenShortWithBracket opens a short with attached SL/TP on every venue, split by equity share
+func (m *MirrorTrader) OpenShortWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return m.open("open short with bracket", symbol, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		opener, ok := t.(BracketOrderOpener)
+		if !ok {
+			return nil, errMirrorUnsupported
+		}
+		return opener.OpenShortWithBracket(symbol, q, leverage, stopLoss, takeProfit)
+	})
+}
+
+// SetStopLossLimit places the stop-limit order on every venue holding the position
+func (m *MirrorTrader) SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error {
+	_, err := m.reduce("set stop loss limit", symbol, positionSide, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		setter, ok := t.(LimitExitOrderSetter)
+		if !ok {
+			return nil, errMirrorUnsupported
+		}
+		return nil, setter.SetStopLossLimit(symbol, positionSide, q, stopPrice, limitPrice)
+	})
+	return err
+}
+
+// SetTakeProfitLimit places the take-profit-limit order on every venue holding the position
+func (m *MirrorTrader) SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error {
+	_, err := m.reduce("set take profit limit", symbol, positionSide, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		setter, ok := t.(LimitExitOrderSetter)
+		if !ok {
+			return nil, errMirrorUnsupported
+		}
+		return nil, setter.SetTakeProfitLimit(symbol, positionSide, q, takeProfitPrice, limitPrice)
+	})
+	return err
+}
+
+// SetPartialTakeProfit places a take-profit level on every venue holding the position, each closing
+// its share of quantity
+func (m *MirrorTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	_, err := m.reduce("set partial take profit", symbol, positionSide, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		setter, ok := t.(PartialTakeProfitSetter)
+		if !ok {
+			return nil, errMirrorUnsupported
+		}
+		return nil, setter.SetPartialTakeProfit(symbol, positionSide, q, takeProfitPrice)
+	})
+	return err
+}
+
+// GetSymbolInfo returns the symbol info of the first venue reporting it, with the lowest max
+// leverage of all venues so a clamped leverage is accepted everywhere (nil if no venue reports it)
+func (m *MirrorTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	var info *SymbolInfo
+	for i, v := range m.venues {
+		provider, ok := v.Trader.(SymbolInfoProvider)
+		if !ok {
+			continue
+		}
+		venueInfo, err := provider.GetSymbolInfo(symbol)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			m.log().Warnf("⚠️ Mirror %s: failed to get %s symbol info: %v", v.name(), symbol, err)
+			continue
+		}
+		if venueInfo == nil {
+			continue
+		}
+		if info == nil {
+			copied := *venueInfo
+			info = &copied
+			continue
+		}
+		if venueInfo.MaxLeverage > 0 && (info.MaxLeverage <= 0 || venueInfo.MaxLeverage < info.MaxLeverage) {
+			info.MaxLeverage = venueInfo.MaxLeverage
+		}
+	}
+	return info, nil
+}
+
+// GetTransfers returns the deposits/withdrawals of every venue exposing them, since each moves the
+// combined balance. One venue's error fails the import so no venue's transfers are skipped; it's
+// retried on the next run.
+func (m *MirrorTrader) GetTransfers(since time.Time) ([]Transfer, error) {
+	var all []Transfer
+	for i, v := range m.venues {
+		provider, ok := v.Trader.(TransferHistoryProvider)
+		if !ok {
+			continue
+		}
+		transfers, err := provider.GetTransfers(since)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.name(), err)
+		}
+		for _, tr := range transfers {
+			if i > 0 {
+				tr.ID = mirrorRecordID(v, tr.ID)
+			}
+			all = append(all, tr)
+		}
+	}
+	return all, nil
+}
+
+// GetFundingPayments returns the funding payments of every venue exposing them (see GetTransfers)
+func (m *MirrorTrader) GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error) {
+	var all []FundingPayment
+	for i, v := range m.venues {
+		provider, ok := v.Trader.(FundingHistoryProvider)
+		if !ok {
+			continue
+		}
+		payments, err := provider.GetFundingPayments(symbols, since)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.name(), err)
+		}
+		for _, p := range payments {
+			if i > 0 {
+				p.ID = mirrorRecordID(v, p.ID)
+			}
+			all = append(all, p)
+		}
+	}
+	return all, nil
+}
+
+// GetMinNotional returns the smallest order value that still gives every venue its own minimum once
+// the order is split by equity share (a venue with a quarter of the equity needs 4x its minimum).
+// Returns 0 (unknown) when the balances can't be fetched.
+func (m *MirrorTrader) GetMinNotional(symbol string) float64 {
+	balances, err := m.balances()
+	if err != nil {
+		return 0
+	}
+	equities := make([]float64, len(balances))
+	total := 0.0
+	for i, balance := range balances {
+		equities[i] = balanceEquity(balance)
+		if equities[i] > 0 {
+			total += equities[i]
+		}
+	}
+
+	minNotional := 0.0
+	for i, v := range m.venues {
+		if equities[i] <= 0 {
+			continue // Gets no share of the order
+		}
+		venueMin := defaultChildMinNotional
+		if reporter, ok := v.Trader.(minNotionalReporter); ok {
+			if min := reporter.GetMinNotional(symbol); min > 0 {
+				venueMin = min
+			}
+		}
+		if need := venueMin * total / equities[i]; need > minNotional {
+			minNotional = need
+		}
+	}
+	return minNotional
+}
+
+// SetQuoteAsset hands the quote asset to every venue settling per quote
+func (m *MirrorTrader) SetQuoteAsset(quote string) {
+	for _, v := range m.venues {
+		if qc, ok := v.Trader.(QuoteAssetConfigurable); ok {
+			qc.SetQuoteAsset(quote)
+		}
+	}
+}
+
+// SetTraceContext tags the mirror's and every venue's log lines with the trader's current cycle
+func (m *MirrorTrader) SetTraceContext(traderID string, ctx context.Context) {
+	m.traceLogger.SetTraceContext(traderID, ctx)
+	for _, v := range m.venues {
+		if setter, ok := v.Trader.(TraceContextSetter); ok {
+			setter.SetTraceContext(traderID, ctx)
+		}
+	}
+}
@@ -0,0 +1,560 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+)
+
+// MirrorVenue one exchange account a mirrored trader executes on
+type MirrorVenue struct {
+	ExchangeID   string // Exchange account UUID
+	ExchangeType string // binance/bybit/okx/etc
+	Trader       Trader
+}
+
+// name identifies the venue in logs (exchange type and short account ID)
+func (v MirrorVenue) name() string {
+	id := v.ExchangeID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return v.ExchangeType + "/" + id
+}
+
+// MirrorTrader runs one trader config on several exchange accounts at once. The first venue is the
+// primary: its order IDs, prices, order status and closed PnL are what the trader sees. Every order
+// action is replicated on the other venues concurrently, and a venue's failure is logged without
+// affecting the primary or the other mirrors.
+//
+// The AI sees the combined account: balances are summed and positions merged per symbol and side.
+// Opens are split across venues by their share of the combined equity; closes and stop/take-profit
+// orders by each venue's share of the position. A mirror that can't be reached stands in with its
+// last good snapshot for up to mirrorSnapshotMaxAge, then drops out of the account and of sizing.
+//
+// Optional exchange capabilities are mirrored when every venue has them (bracket entries, limit
+// exits, partial take profits, see capableTrader) or combined across venues (symbol limits,
+// transfer and funding history); fills are confirmed on the primary's order stream. Post-only
+// entries are never mirrored: a resting entry is tracked on one exchange only.
+type MirrorTrader struct {
+	traceLogger
+
+	venues []MirrorVenue
+	now    func() time.Time
+
+	mu            sync.Mutex
+	lastBalances  []map[string]interface{}   // Last good balance per venue, used while a mirror is unreachable
+	balancesAt    []time.Time                // When each venue's last good balance was fetched
+	lastPositions [][]map[string]interface{} // Last good positions per venue
+	positionsAt   []time.Time                // When each venue's last good positions were fetched
+}
+
+// mirrorSnapshotMaxAge how long an unreachable mirror's last good balance and positions stand in for
+// it; after that it's left out of the combined account, the AI's positions and order sizing
+const mirrorSnapshotMaxAge = 5 * time.Minute
+
+// NewMirrorTrader creates a trader replicating the primary's orders on the mirrors
+func NewMirrorTrader(primary MirrorVenue, mirrors ...MirrorVenue) *MirrorTrader {
+	venues := append([]MirrorVenue{primary}, mirrors...)
+	return &MirrorTrader{
+		venues:        venues,
+		now:           time.Now,
+		lastBalances:  make([]map[string]interface{}, len(venues)),
+		balancesAt:    make([]time.Time, len(venues)),
+		lastPositions: make([][]map[string]interface{}, len(venues)),
+		positionsAt:   make([]time.Time, len(venues)),
+	}
+}
+
+// Venues returns the exchange accounts the trader executes on, primary first
+func (m *MirrorTrader) Venues() []MirrorVenue {
+	return append([]MirrorVenue(nil), m.venues...)
+}
+
+// newMirroredTrader wraps the primary exchange client so every order is also placed on the config's
+// mirror exchange accounts. A mirror whose client can't be created is left out.
+func newMirroredTrader(config *AutoTraderConfig, primary Trader, userID string) Trader {
+	log := logger.TraderEntry(config.ID, nil)
+	var mirrors []MirrorVenue
+	for _, exchange := range config.MirrorExchanges {
+		venue := MirrorVenue{ExchangeID: exchange.ID, ExchangeType: exchange.ExchangeType}
+		t, err := NewTraderFromExchangeConfig(exchange, userID)
+		if err != nil {
+			log.Warnf("⚠️ [%s] Mirror %s unavailable, not replicating orders there: %v", config.Name, venue.name(), err)
+			continue
+		}
+		applyQuoteAsset(t, config.QuoteAsset, config.Name, log)
+		venue.Trader = t
+		mirrors = append(mirrors, venue)
+		log.Infof("🪞 [%s] Mirroring orders on %s", config.Name, venue.name())
+	}
+	if len(mirrors) == 0 {
+		return primary
+	}
+	if config.StrategyConfig != nil && config.StrategyConfig.RiskControl.PostOnlyEntries {
+		log.Warnf("⚠️ [%s] Post-only entries aren't mirrored (a resting entry is tracked on one exchange), entries use market orders", config.Name)
+	}
+	return NewMirrorTrader(MirrorVenue{ExchangeID: config.ExchangeID, ExchangeType: config.Exchange, Trader: primary}, mirrors...)
+}
+
+// mirrorVenueStatus lists the mirror venues for the trader status
+func mirrorVenueStatus(venues []MirrorVenue) []map[string]string {
+	result := make([]map[string]string, 0, len(venues))
+	for _, v := range venues {
+		result = append(result, map[string]string{"exchange_id": v.ExchangeID, "exchange": v.ExchangeType})
+	}
+	return result
+}
+
+// ParseMirrorExchangeIDs parses a comma-separated list of exchange account IDs
+func ParseMirrorExchangeIDs(spec string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		id := strings.TrimSpace(part)
+		if id == "" {
+			continue
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate mirror exchange %s", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// errMirrorSkip returned by a fan-out action for a venue that has nothing to do (e.g. no position)
+var errMirrorSkip = errors.New("nothing to do on this venue")
+
+// fanOut runs fn on every venue concurrently and returns the primary's result. A mirror's error
+// (or panic) is only logged.
+func (m *MirrorTrader) fanOut(action string, fn func(i int, v MirrorVenue) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	results := make([]map[string]interface{}, len(m.venues))
+	errs := make([]error, len(m.venues))
+	var wg sync.WaitGroup
+	for i, v := range m.venues {
+		wg.Add(1)
+		go func(i int, v MirrorVenue) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("panic: %v", r)
+				}
+			}()
+			results[i], errs[i] = fn(i, v)
+		}(i, v)
+	}
+	wg.Wait()
+
+	for i := 1; i < len(m.venues); i++ {
+		if errs[i] != nil && !errors.Is(errs[i], errMirrorSkip) {
+			m.log().Warnf("⚠️ Mirror %s: %s failed: %v", m.venues[i].name(), action, errs[i])
+		}
+	}
+	return results[0], errs[0]
+}
+
+// fanOutErr is fanOut for actions without a result
+func (m *MirrorTrader) fanOutErr(action string, fn func(t Trader) error) error {
+	_, err := m.fanOut(action, func(_ int, v MirrorVenue) (map[string]interface{}, error) {
+		return nil, fn(v.Trader)
+	})
+	return err
+}
+
+// balanceEquity returns the equity of an exchange balance (totalEquity, else wallet + unrealized)
+func balanceEquity(balance map[string]interface{}) float64 {
+	if eq, ok := balance["totalEquity"].(float64); ok && eq > 0 {
+		return eq
+	}
+	wallet, _ := balance["totalWalletBalance"].(float64)
+	unrealized, _ := balance["totalUnrealizedProfit"].(float64)
+	return wallet + unrealized
+}
+
+// balances fetches every venue's balance concurrently. A mirror that can't be reached contributes
+// its last good balance while it's at most mirrorSnapshotMaxAge old, nil after that (or if it never
+// answered); the primary's error is returned.
+func (m *MirrorTrader) balances() ([]map[string]interface{}, error) {
+	balances := make([]map[string]interface{}, len(m.venues))
+	errs := make([]error, len(m.venues))
+	var wg sync.WaitGroup
+	for i, v := range m.venues {
+		wg.Add(1)
+		go func(i int, t Trader) {
+			defer wg.Done()
+			balances[i], errs[i] = t.GetBalance()
+		}(i, v.Trader)
+	}
+	wg.Wait()
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.venues {
+		if errs[i] == nil {
+			m.lastBalances[i], m.balancesAt[i] = balances[i], now
+			continue
+		}
+		if age := now.Sub(m.balancesAt[i]); m.lastBalances[i] != nil && age <= mirrorSnapshotMaxAge {
+			m.log().Warnf("⚠️ Mirror %s: failed to get balance, using last known (%v old): %v", m.venues[i].name(), age.Round(time.Second), errs[i])
+			balances[i] = m.lastBalances[i]
+			continue
+		}
+		m.log().Warnf("⚠️ Mirror %s: failed to get balance, leaving it out (no balance in the last %v): %v", m.venues[i].name(), mirrorSnapshotMaxAge, errs[i])
+		balances[i] = nil
+	}
+	return balances, nil
+}
+
+// GetBalance returns the combined balance of all venues: numeric fields are summed, other fields
+// come from the primary
+func (m *MirrorTrader) GetBalance() (map[string]interface{}, error) {
+	balances, err := m.balances()
+	if err != nil {
+		return nil, err
+	}
+
+	combined := make(map[string]interface{}, len(balances[0])+1)
+	for k, v := range balances[0] {
+		combined[k] = v
+	}
+	combined["totalEquity"] = 0.0
+	for i, balance := range balances {
+		for k, v := range balance {
+			f, ok := v.(float64)
+			if !ok || k == "totalEquity" {
+				continue
+			}
+			if i == 0 {
+				combined[k] = f
+			} else if sum, ok := combined[k].(float64); ok {
+				combined[k] = sum + f
+			} else {
+				combined[k] = f
+			}
+		}
+		// Venues report equity differently, so it's summed from each venue's own equity
+		combined["totalEquity"] = combined["totalEquity"].(float64) + balanceEquity(balance)
+	}
+	return combined, nil
+}
+
+// positions fetches every venue's positions concurrently, falling back to a mirror's last known
+// positions when it can't be reached; the primary's error is returned. stale reports the mirrors
+// whose positions are older than mirrorSnapshotMaxAge (or unknown).
+func (m *MirrorTrader) positions() (positions [][]map[string]interface{}, stale []bool, err error) {
+	positions = make([][]map[string]interface{}, len(m.venues))
+	errs := make([]error, len(m.venues))
+	var wg sync.WaitGroup
+	for i, v := range m.venues {
+		wg.Add(1)
+		go func(i int, t Trader) {
+			defer wg.Done()
+			positions[i], errs[i] = t.GetPositions()
+		}(i, v.Trader)
+	}
+	wg.Wait()
+	if errs[0] != nil {
+		return nil, nil, errs[0]
+	}
+
+	now := m.now()
+	stale = make([]bool, len(m.venues))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.venues {
+		if errs[i] == nil {
+			m.lastPositions[i], m.positionsAt[i] = positions[i], now
+			continue
+		}
+		age := now.Sub(m.positionsAt[i])
+		stale[i] = m.positionsAt[i].IsZero() || age > mirrorSnapshotMaxAge
+		if stale[i] {
+			m.log().Warnf("⚠️ Mirror %s: failed to get positions, none known from the last %v: %v", m.venues[i].name(), mirrorSnapshotMaxAge, errs[i])
+		} else {
+			m.log().Warnf("⚠️ Mirror %s: failed to get positions, using last known (%v old): %v", m.venues[i].name(), age.Round(time.Second), errs[i])
+		}
+		positions[i] = m.lastPositions[i]
+	}
+	return positions, stale, nil
+}
+
+// positionAmount returns the absolute size of a position map
+func positionAmount(pos map[string]interface{}) float64 {
+	amt, _ := pos["positionAmt"].(float64)
+	return math.Abs(amt)
+}
+
+// GetPositions returns the positions of all venues merged per symbol and side: sizes and unrealized
+// PnL are summed, the entry price is size-weighted, other fields come from the first venue holding it.
+// Mirrors with stale positions are left out.
+func (m *MirrorTrader) GetPositions() ([]map[string]interface{}, error) {
+	venuePositions, stale, err := m.positions()
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []map[string]interface{}
+	index := make(map[string]int)
+	for i, positions := range venuePositions {
+		if stale[i] {
+			continue
+		}
+		for _, pos := range positions {
+			key := fmt.Sprintf("%v_%v", pos["symbol"], pos["side"])
+			i, ok := index[key]
+			if !ok {
+				copied := make(map[string]interface{}, len(pos))
+				for k, v := range pos {
+					copied[k] = v
+				}
+				index[key] = len(merged)
+				merged = append(merged, copied)
+				continue
+			}
+
+			existing := merged[i]
+			existingAmt, newAmt := positionAmount(existing), positionAmount(pos)
+			if total := existingAmt + newAmt; total > 0 {
+				existingEntry, _ := existing["entryPrice"].(float64)
+				newEntry, _ := pos["entryPrice"].(float64)
+				existing["entryPrice"] = (existingEntry*existingAmt + newEntry*newAmt) / total
+			}
+			amt, _ := existing["positionAmt"].(float64)
+			add, _ := pos["positionAmt"].(float64)
+			existing["positionAmt"] = amt + add
+			pnl, _ := existing["unRealizedProfit"].(float64)
+			addPnL, _ := pos["unRealizedProfit"].(float64)
+			existing["unRealizedProfit"] = pnl + addPnL
+		}
+	}
+	return merged, nil
+}
+
+// openQuantities splits an entry across venues by their share of the combined equity. Mirrors
+// without a known positive equity get 0 (skipped).
+func (m *MirrorTrader) openQuantities(quantity float64) ([]float64, error) {
+	balances, err := m.balances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to size mirrored order: %w", err)
+	}
+	equities := make([]float64, len(balances))
+	total := 0.0
+	for i, balance := range balances {
+		if eq := balanceEquity(balance); eq > 0 {
+			equities[i] = eq
+			total += eq
+		}
+	}
+	if equities[0] <= 0 {
+		return nil, fmt.Errorf("failed to size mirrored order: primary equity unavailable")
+	}
+
+	quantities := make([]float64, len(balances))
+	for i, eq := range equities {
+		quantities[i] = quantity * eq / total
+	}
+	return quantities, nil
+}
+
+// positionQuantities splits a close or exit order across venues by their share of the symbol's
+// position (quantity 0 stays 0, meaning the whole position). Mirrors without the position get -1
+// (skipped); the primary keeps the requested quantity when it has none, so the exchange answers
+// as it would without mirroring. A mirror's last known positions count however old they are: a
+// rejected close is better than leaving its position without exits.
+func (m *MirrorTrader) positionQuantities(symbol, side string, quantity float64) ([]float64, error) {
+	venuePositions, _, err := m.positions()
+	if err != nil {
+		return nil, err
+	}
+	side = strings.ToLower(side)
+	amounts := make([]float64, len(venuePositions))
+	total := 0.0
+	for i, positions := range venuePositions {
+		for _, pos := range positions {
+			if pos["symbol"] == symbol && pos["side"] == side {
+				amounts[i] += positionAmount(pos)
+			}
+		}
+		total += amounts[i]
+	}
+
+	quantities := make([]float64, len(venuePositions))
+	for i, amt := range amounts {
+		switch {
+		case amt <= 0 && i == 0:
+			quantities[i] = quantity
+		case amt <= 0:
+			quantities[i] = -1
+		case quantity <= 0:
+			quantities[i] = 0
+		default:
+			quantities[i] = quantity * amt / total
+		}
+	}
+	return quantities, nil
+}
+
+// open replicates an entry on every venue with its share of the quantity
+func (m *MirrorTrader) open(action, symbol string, quantity float64,
+	open func(t Trader, quantity float64) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	quantities, err := m.openQuantities(quantity)
+	if err != nil {
+		return nil, err
+	}
+	return m.fanOut(fmt.Sprintf("%s %s", action, symbol), func(i int, v MirrorVenue) (map[string]interface{}, error) {
+		if quantities[i] <= 0 {
+			if i > 0 {
+				m.log().Warnf("⚠️ Mirror %s: equity unknown, %s %s not replicated", v.name(), action, symbol)
+			}
+			return nil, errMirrorSkip
+		}
+		return open(v.Trader, quantities[i])
+	})
+}
+
+// OpenLong opens a long on every venue
+func (m *MirrorTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return m.open("open long", symbol, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.OpenLong(symbol, q, leverage)
+	})
+}
+
+// OpenShort opens a short on every venue
+func (m *MirrorTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return m.open("open short", symbol, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.OpenShort(symbol, q, leverage)
+	})
+}
+
+// reduce replicates a position-sized action on every venue holding the position
+func (m *MirrorTrader) reduce(action, symbol, side string, quantity float64,
+	fn func(t Trader, quantity float64) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	quantities, err := m.positionQuantities(symbol, side, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return m.fanOut(fmt.Sprintf("%s %s", action, symbol), func(i int, v MirrorVenue) (map[string]interface{}, error) {
+		if quantities[i] < 0 {
+			return nil, errMirrorSkip
+		}
+		return fn(v.Trader, quantities[i])
+	})
+}
+
+// CloseLong closes the long on every venue holding it
+func (m *MirrorTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return m.reduce("close long", symbol, "long", quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.CloseLong(symbol, q)
+	})
+}
+
+// CloseShort closes the short on every venue holding it
+func (m *MirrorTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return m.reduce("close short", symbol, "short", quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.CloseShort(symbol, q)
+	})
+}
+
+// SetStopLoss places the stop-loss on every venue holding the position
+func (m *MirrorTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	_, err := m.reduce("set stop loss", symbol, positionSide, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return nil, t.SetStopLoss(symbol, positionSide, q, stopPrice)
+	})
+	return err
+}
+
+// SetTakeProfit places the take-profit on every venue holding the position
+func (m *MirrorTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	_, err := m.reduce("set take profit", symbol, positionSide, quantity, func(t Trader, q float64) (map[string]interface{}, error) {
+		return nil, t.SetTakeProfit(symbol, positionSide, q, takeProfitPrice)
+	})
+	return err
+}
+
+// SetLeverage sets the leverage on every venue
+func (m *MirrorTrader) SetLeverage(symbol string, leverage int) error {
+	return m.fanOutErr("set leverage "+symbol, func(t Trader) error { return t.SetLeverage(symbol, leverage) })
+}
+
+// SetMarginMode sets the margin mode on every venue
+func (m *MirrorTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return m.fanOutErr("set margin mode "+symbol, func(t Trader) error { return t.SetMarginMode(symbol, isCrossMargin) })
+}
+
+// CancelStopLossOrders cancels the stop-loss orders on every venue
+func (m *MirrorTrader) CancelStopLossOrders(symbol string) error {
+	return m.fanOutErr("cancel stop loss "+symbol, func(t Trader) error { return t.CancelStopLossOrders(symbol) })
+}
+
+// CancelTakeProfitOrders cancels the take-profit orders on every venue
+func (m *MirrorTrader) CancelTakeProfitOrders(symbol string) error {
+	return m.fanOutErr("cancel take profit "+symbol, func(t Trader) error { return t.CancelTakeProfitOrders(symbol) })
+}
+
+// CancelAllOrders cancels all pending orders of symbol on every venue
+func (m *MirrorTrader) CancelAllOrders(symbol string) error {
+	return m.fanOutErr("cancel all orders "+symbol, func(t Trader) error { return t.CancelAllOrders(symbol) })
+}
+
+// CancelStopOrders cancels the stop-loss/take-profit orders on every venue
+func (m *MirrorTrader) CancelStopOrders(symbol string) error {
+	return m.fanOutErr("cancel stop orders "+symbol, func(t Trader) error { return t.CancelStopOrders(symbol) })
+}
+
+// CancelOrder cancels a primary order (order IDs are venue specific)
+func (m *MirrorTrader) CancelOrder(symbol string, orderID string) error {
+	return m.venues[0].Trader.CancelOrder(symbol, orderID)
+}
+
+// GetMarketPrice returns the primary's market price
+func (m *MirrorTrader) GetMarketPrice(symbol string) (float64, error) {
+	return m.venues[0].Trader.GetMarketPrice(symbol)
+}
+
+// FormatQuantity formats with the primary's precision (each venue formats its own share when ordering)
+func (m *MirrorTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return m.venues[0].Trader.FormatQuantity(symbol, quantity)
+}
+
+// GetOrderStatus returns the status of a primary order
+func (m *MirrorTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return m.venues[0].Trader.GetOrderStatus(symbol, orderID)
+}
+
+// GetClosedPnL returns the primary's closed positions (mirrors' are recorded by their order sync)
+func (m *MirrorTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return m.venues[0].Trader.GetClosedPnL(startTime, limit)
+}
+
+// GetOpenOrders returns the primary's open orders
+func (m *MirrorTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	return m.venues[0].Trader.GetOpenOrders(symbol)
+}
+
+// SyncOrders implements OrderSyncer: each venue's trades are recorded under its own exchange
+// account, so orders, fills and positions stay attributed per exchange
+func (m *MirrorTrader) SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error {
+	var errs []error
+	for _, v := range m.venues {
+		syncer, ok := v.Trader.(OrderSyncer)
+		if !ok {
+			continue
+		}
+		if err := syncer.SyncOrders(traderID, v.ExchangeID, v.ExchangeType, st); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
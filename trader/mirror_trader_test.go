@@ -0,0 +1,309 @@
+package trader
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMirrorVenue records the orders a venue receives
+type fakeMirrorVenue struct {
+	Trader
+	mu         sync.Mutex
+	balance    map[string]interface{}
+	balanceErr error
+	positions  []map[string]interface{}
+	posErr     error
+	openErr    error
+	opened     []float64
+	closed     []float64
+}
+
+func (f *fakeMirrorVenue) GetBalance() (map[string]interface{}, error) {
+	return f.balance, f.balanceErr
+}
+
+func (f *fakeMirrorVenue) GetPositions() ([]map[string]interface{}, error) {
+	return f.positions, f.posErr
+}
+
+func (f *fakeMirrorVenue) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.openErr != nil {
+		return nil, f.openErr
+	}
+	f.opened = append(f.opened, quantity)
+	return map[string]interface{}{"orderId": int64(len(f.opened))}, nil
+}
+
+func (f *fakeMirrorVenue) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = append(f.closed, quantity)
+	return map[string]interface{}{"orderId": int64(1)}, nil
+}
+
+func newTestMirrorTrader(primary *fakeMirrorVenue, mirrors ...*fakeMirrorVenue) *MirrorTrader {
+	venues := make([]MirrorVenue, len(mirrors))
+	for i, m := range mirrors {
+		venues[i] = MirrorVenue{ExchangeID: "mirror", ExchangeType: "bybit", Trader: m}
+	}
+	return NewMirrorTrader(MirrorVenue{ExchangeID: "primary", ExchangeType: "binance", Trader: primary}, venues...)
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMirrorTraderGetBalance(t *testing.T) {
+	primary := &fakeMirrorVenue{balance: map[string]interface{}{
+		"totalWalletBalance": 1000.0, "totalUnrealizedProfit": 50.0, "availableBalance": 800.0, "asset": "USDT",
+	}}
+	mirror := &fakeMirrorVenue{balance: map[string]interface{}{
+		"totalEquity": 520.0, "totalWalletBalance": 500.0, "availableBalance": 400.0,
+	}}
+	m := newTestMirrorTrader(primary, mirror)
+
+	balance, err := m.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if !approxEqual(balance["totalEquity"].(float64), 1570) {
+		t.Errorf("totalEquity = %v, want 1570", balance["totalEquity"])
+	}
+	if !approxEqual(balance["availableBalance"].(float64), 1200) {
+		t.Errorf("availableBalance = %v, want 1200", balance["availableBalance"])
+	}
+	if balance["asset"] != "USDT" {
+		t.Errorf("asset = %v, want the primary's", balance["asset"])
+	}
+
+	// An unreachable mirror keeps contributing its last known balance
+	mirror.balanceErr = errors.New("timeout")
+	balance, err = m.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance() with failing mirror error = %v", err)
+	}
+	if !approxEqual(balance["totalEquity"].(float64), 1570) {
+		t.Errorf("totalEquity with failing mirror = %v, want 1570", balance["totalEquity"])
+	}
+
+	primary.balanceErr = errors.New("primary down")
+	if _, err := m.GetBalance(); err == nil {
+		t.Error("expected the primary's balance error")
+	}
+}
+
+func TestMirrorTraderOpenSplitsByEquity(t *testing.T) {
+	primary := &fakeMirrorVenue{balance: map[string]interface{}{"totalEquity": 3000.0}}
+	mirror := &fakeMirrorVenue{balance: map[string]interface{}{"totalEquity": 1000.0}}
+	failing := &fakeMirrorVenue{balance: map[string]interface{}{"totalEquity": 1000.0}, openErr: errors.New("rejected")}
+	m := newTestMirrorTrader(primary, mirror, failing)
+
+	order, err := m.OpenLong("BTCUSDT", 5, 10)
+	if err != nil {
+		t.Fatalf("OpenLong() error = %v, a mirror failure must not fail the primary", err)
+	}
+	if order["orderId"] != int64(1) {
+		t.Errorf("order = %v, want the primary's order", order)
+	}
+	if len(primary.opened) != 1 || !approxEqual(primary.opened[0], 3) {
+		t.Errorf("primary opened %v, want [3]", primary.opened)
+	}
+	if len(mirror.opened) != 1 || !approxEqual(mirror.opened[0], 1) {
+		t.Errorf("mirror opened %v, want [1]", mirror.opened)
+	}
+}
+
+func TestMirrorTraderCloseScalesByPosition(t *testing.T) {
+	primary := &fakeMirrorVenue{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 3.0},
+	}}
+	mirror := &fakeMirrorVenue{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0},
+	}}
+	flat := &fakeMirrorVenue{}
+	m := newTestMirrorTrader(primary, mirror, flat)
+
+	if _, err := m.CloseLong("BTCUSDT", 2); err != nil {
+		t.Fatalf("CloseLong() error = %v", err)
+	}
+	if len(primary.closed) != 1 || !approxEqual(primary.closed[0], 1.5) {
+		t.Errorf("primary closed %v, want [1.5]", primary.closed)
+	}
+	if len(mirror.closed) != 1 || !approxEqual(mirror.closed[0], 0.5) {
+		t.Errorf("mirror closed %v, want [0.5]", mirror.closed)
+	}
+	if len(flat.closed) != 0 {
+		t.Errorf("venue without the position closed %v, want nothing", flat.closed)
+	}
+
+	// quantity 0 closes the whole position everywhere
+	if _, err := m.CloseLong("BTCUSDT", 0); err != nil {
+		t.Fatalf("CloseLong(0) error = %v", err)
+	}
+	if primary.closed[1] != 0 || mirror.closed[1] != 0 {
+		t.Errorf("close all sent %v / %v, want 0", primary.closed[1], mirror.closed[1])
+	}
+}
+
+func TestMirrorTraderGetPositionsMerges(t *testing.T) {
+	primary := &fakeMirrorVenue{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": -3.0, "entryPrice": 100.0, "unRealizedProfit": 6.0, "leverage": 10.0},
+	}}
+	mirror := &fakeMirrorVenue{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": -1.0, "entryPrice": 104.0, "unRealizedProfit": -2.0, "leverage": 5.0},
+		{"symbol": "ETHUSDT", "side": "long", "positionAmt": 2.0, "entryPrice": 50.0},
+	}}
+	m := newTestMirrorTrader(primary, mirror)
+
+	positions, err := m.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions() error = %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("got %d positions, want 2", len(positions))
+	}
+	btc := positions[0]
+	if !approxEqual(btc["positionAmt"].(float64), -4) || !approxEqual(btc["entryPrice"].(float64), 101) ||
+		!approxEqual(btc["unRealizedProfit"].(float64), 4) || btc["leverage"] != 10.0 {
+		t.Errorf("merged BTC position = %v", btc)
+	}
+	if primary.positions[0]["positionAmt"] != -3.0 {
+		t.Error("merging must not modify the venue's positions")
+	}
+}
+
+func TestMirrorTraderDropsStaleSnapshots(t *testing.T) {
+	primary := &fakeMirrorVenue{
+		balance:   map[string]interface{}{"totalEquity": 3000.0},
+		positions: []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 3.0}},
+	}
+	mirror := &fakeMirrorVenue{
+		balance:   map[string]interface{}{"totalEquity": 1000.0},
+		positions: []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0}},
+	}
+	m := newTestMirrorTrader(primary, mirror)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	if _, err := m.GetPositions(); err != nil {
+		t.Fatalf("GetPositions() error = %v", err)
+	}
+	if _, err := m.GetBalance(); err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	mirror.balanceErr, mirror.posErr = errors.New("timeout"), errors.New("timeout")
+	now = now.Add(mirrorSnapshotMaxAge + time.Second)
+
+	balance, err := m.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if !approxEqual(balance["totalEquity"].(float64), 3000) {
+		t.Errorf("totalEquity with a stale mirror = %v, want only the primary's 3000", balance["totalEquity"])
+	}
+	positions, err := m.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions() error = %v", err)
+	}
+	if len(positions) != 1 || !approxEqual(positions[0]["positionAmt"].(float64), 3) {
+		t.Errorf("positions with a stale mirror = %v, want only the primary's", positions)
+	}
+
+	if _, err := m.OpenLong("BTCUSDT", 2, 10); err != nil {
+		t.Fatalf("OpenLong() error = %v", err)
+	}
+	if len(primary.opened) != 1 || !approxEqual(primary.opened[0], 2) || len(mirror.opened) != 0 {
+		t.Errorf("opened primary %v / mirror %v, want the whole entry on the primary", primary.opened, mirror.opened)
+	}
+
+	// Exits still reach a mirror known to hold the position
+	if _, err := m.CloseLong("BTCUSDT", 0); err != nil {
+		t.Fatalf("CloseLong() error = %v", err)
+	}
+	if len(mirror.closed) != 1 {
+		t.Errorf("mirror closed %v, want its last known position closed", mirror.closed)
+	}
+}
+
+// fakePartialMirrorVenue a venue with partial take profits
+type fakePartialMirrorVenue struct {
+	*fakeMirrorVenue
+	partial []float64
+}
+
+func (f *fakePartialMirrorVenue) SetPartialTakeProfit(symbol, positionSide string, quantity, price float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.partial = append(f.partial, quantity)
+	return nil
+}
+
+func TestCapableTrader(t *testing.T) {
+	long := func(amt float64) []map[string]interface{} {
+		return []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": amt}}
+	}
+	primary := &fakePartialMirrorVenue{fakeMirrorVenue: &fakeMirrorVenue{positions: long(3)}}
+	mirror := &fakePartialMirrorVenue{fakeMirrorVenue: &fakeMirrorVenue{positions: long(1)}}
+	plain := &fakeMirrorVenue{positions: long(1)}
+
+	mixed := NewMirrorTrader(MirrorVenue{ExchangeID: "p", Trader: primary}, MirrorVenue{ExchangeID: "m", Trader: plain})
+	if _, ok := capableTrader[PartialTakeProfitSetter](mixed); ok {
+		t.Error("mirror with a venue lacking partial take profits reported the capability")
+	}
+
+	m := NewMirrorTrader(MirrorVenue{ExchangeID: "p", Trader: primary}, MirrorVenue{ExchangeID: "m", Trader: mirror})
+	setter, ok := capableTrader[PartialTakeProfitSetter](m)
+	if !ok {
+		t.Fatal("mirror whose venues all have partial take profits lacks the capability")
+	}
+	if err := setter.SetPartialTakeProfit("BTCUSDT", "LONG", 2, 110); err != nil {
+		t.Fatalf("SetPartialTakeProfit() error = %v", err)
+	}
+	if len(primary.partial) != 1 || !approxEqual(primary.partial[0], 1.5) || len(mirror.partial) != 1 || !approxEqual(mirror.partial[0], 0.5) {
+		t.Errorf("partial TPs primary %v / mirror %v, want [1.5] / [0.5]", primary.partial, mirror.partial)
+	}
+}
+
+// fakeTransferVenue a venue exposing its transfer history
+type fakeTransferVenue struct {
+	*fakeMirrorVenue
+	transfers []Transfer
+}
+
+func (f *fakeTransferVenue) GetTransfers(since time.Time) ([]Transfer, error) {
+	return f.transfers, nil
+}
+
+func TestMirrorTraderGetTransfers(t *testing.T) {
+	primary := &fakeTransferVenue{fakeMirrorVenue: &fakeMirrorVenue{}, transfers: []Transfer{{ID: "1", Amount: 100}}}
+	mirror := &fakeTransferVenue{fakeMirrorVenue: &fakeMirrorVenue{}, transfers: []Transfer{{ID: "1", Amount: 50}}}
+	m := NewMirrorTrader(MirrorVenue{ExchangeID: "p", Trader: primary}, MirrorVenue{ExchangeID: "m", Trader: mirror},
+		MirrorVenue{ExchangeID: "x", Trader: &fakeMirrorVenue{}})
+
+	transfers, err := m.GetTransfers(time.Time{})
+	if err != nil {
+		t.Fatalf("GetTransfers() error = %v", err)
+	}
+	if len(transfers) != 2 || transfers[0].ID != "1" || transfers[1].ID != "m:1" {
+		t.Errorf("transfers = %+v, want the primary's ID kept and the mirror's prefixed", transfers)
+	}
+}
+
+func TestParseMirrorExchangeIDs(t *testing.T) {
+	ids, err := ParseMirrorExchangeIDs(" a, b ,,c")
+	if err != nil {
+		t.Fatalf("ParseMirrorExchangeIDs() error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "b", "c"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	if _, err := ParseMirrorExchangeIDs("a,a"); err == nil {
+		t.Error("expected duplicate error")
+	}
+}
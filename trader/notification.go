@@ -0,0 +1,59 @@
+package trader
+
+import (
+	"sync"
+	"time"
+)
+
+// Notification kinds
+const (
+	NotificationEquityHigh   = "equity_high"    // Equity reached a new high-water mark
+	NotificationDrawdown     = "drawdown"       // Drawdown from the high-water mark breached the alert threshold
+	NotificationEquityAlert  = "equity_alert"   // Equity crossed a user-defined alert threshold
+	NotificationMaxHoldClose = "max_hold_close" // A position was closed for exceeding the max hold time
+	NotificationLossCooldown = "loss_cooldown"  // Trading paused after too many consecutive losing trades
+)
+
+// Notification an alert raised by a trader
+type Notification struct {
+	TraderID   string    `json:"trader_id"`
+	TraderName string    `json:"trader_name"`
+	Kind       string    `json:"kind"`
+	Message    string    `json:"message"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// NotificationHandler receives trader notifications (called on its own goroutine)
+type NotificationHandler func(Notification)
+
+var (
+	notificationHandlers   []NotificationHandler
+	notificationHandlersMu sync.RWMutex
+)
+
+// RegisterNotificationHandler adds a handler for notifications from all traders (e.g. a chat integration)
+func RegisterNotificationHandler(handler NotificationHandler) {
+	notificationHandlersMu.Lock()
+	defer notificationHandlersMu.Unlock()
+	notificationHandlers = append(notificationHandlers, handler)
+}
+
+// notify logs a notification and hands it to the registered handlers
+func (at *AutoTrader) notify(kind, message string) {
+	at.log().Warnf("🔔 [%s] %s: %s", at.name, kind, message)
+
+	n := Notification{
+		TraderID:   at.id,
+		TraderName: at.name,
+		Kind:       kind,
+		Message:    message,
+		Timestamp:  time.Now().UTC(),
+	}
+
+	notificationHandlersMu.RLock()
+	handlers := append([]NotificationHandler(nil), notificationHandlers...)
+	notificationHandlersMu.RUnlock()
+	for _, handler := range handlers {
+		go handler(n)
+	}
+}
@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// okxBillTypeFundingFee bill type of funding fee settlements
+const okxBillTypeFundingFee = "8"
+
+// GetFundingPayments returns the funding fee bills of the given symbols (last 7 days, balChg is signed)
+func (t *OKXTrader) GetFundingPayments(symbols []string, since time.Time) ([]FundingPayment, error) {
+	var payments []FundingPayment
+	for _, symbol := range symbols {
+		path := fmt.Sprintf("/api/v5/account/bills?instType=SWAP&type=%s&instId=%s&begin=%d&limit=100",
+			okxBillTypeFundingFee, t.convertSymbol(symbol), since.UnixMilli())
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding bills of %s: %w", symbol, err)
+		}
+		bills, err := parseOKXFundingBills(data, symbol)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, bills...)
+	}
+	return payments, nil
+}
+
+// parseOKXFundingBills parses the data of /api/v5/account/bills into funding payments of symbol
+func parseOKXFundingBills(data []byte, symbol string) ([]FundingPayment, error) {
+	var bills []struct {
+		BillID string `json:"billId"`
+		Ccy    string `json:"ccy"`
+		BalChg string `json:"balChg"`
+		Ts     string `json:"ts"`
+	}
+	if err := json.Unmarshal(data, &bills); err != nil {
+		return nil, fmt.Errorf("failed to parse funding bills: %w", err)
+	}
+
+	payments := make([]FundingPayment, 0, len(bills))
+	for _, bill := range bills {
+		amount, _ := strconv.ParseFloat(bill.BalChg, 64)
+		ms, _ := strconv.ParseInt(bill.Ts, 10, 64)
+		payments = append(payments, FundingPayment{
+			ID:     bill.BillID,
+			Symbol: symbol,
+			Asset:  bill.Ccy,
+			Amount: amount,
+			Time:   time.UnixMilli(ms).UTC(),
+		})
+	}
+	return payments, nil
+}
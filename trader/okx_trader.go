@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"nofx/kernel"
 	"nofx/logger"
+	"nofx/store"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,6 +28,7 @@ const (
 	okxOrderPath         = "/api/v5/trade/order"
 	okxLeveragePath      = "/api/v5/account/set-leverage"
 	okxTickerPath        = "/api/v5/market/ticker"
+	okxBooksPath         = "/api/v5/market/books"
 	okxInstrumentsPath   = "/api/v5/public/instruments"
 	okxCancelOrderPath   = "/api/v5/trade/cancel-order"
 	okxPendingOrdersPath = "/api/v5/trade/orders-pending"
@@ -36,12 +39,23 @@ const (
 	okxAccountConfigPath = "/api/v5/account/config"
 )
 
+// okxCodeRateLimited OKX error code "Too Many Requests"
+const okxCodeRateLimited = "50011"
+
 // OKXTrader OKX futures trader
 type OKXTrader struct {
+	traceLogger // Tags log lines with the owning trader and its cycle's correlation ID
+
 	apiKey     string
 	secretKey  string
 	passphrase string
 
+	// Demo trading: requests carry the simulated-trading header
+	testnet bool
+
+	// Additional API keys of the account requests rotate across (nil = primary key only)
+	keys *APIKeyPool
+
 	// Margin mode setting
 	isCrossMargin bool
 
@@ -51,6 +65,9 @@ type OKXTrader struct {
 	// HTTP client (proxy disabled)
 	httpClient *http.Client
 
+	// Exchange clock offset for request timestamps
+	clock *ClockSync
+
 	// Balance cache
 	cachedBalance     map[string]interface{}
 	balanceCacheTime  time.Time
@@ -80,6 +97,7 @@ type OKXInstrument struct {
 	MaxMktSz float64 // Maximum market order size
 	TickSz   float64 // Minimum price increment
 	CtType   string  // Contract type
+	MaxLever float64 // Max leverage
 }
 
 // OKXResponse OKX API response
@@ -103,8 +121,8 @@ func genOkxClOrdID() string {
 	return orderID
 }
 
-// NewOKXTrader creates OKX trader
-func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
+// NewOKXTrader creates OKX trader (testnet = OKX demo trading, same host with simulated-trading header)
+func NewOKXTrader(apiKey, secretKey, passphrase string, testnet bool) *OKXTrader {
 	// Use default transport which respects system proxy settings
 	// OKX requires proxy in China due to DNS pollution
 	httpClient := &http.Client{
@@ -116,10 +134,12 @@ func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
 		apiKey:           apiKey,
 		secretKey:        secretKey,
 		passphrase:       passphrase,
+		testnet:          testnet,
 		httpClient:       httpClient,
 		cacheDuration:    15 * time.Second,
 		instrumentsCache: make(map[string]*OKXInstrument),
 	}
+	trader.clock = NewClockSync("OKX", httpServerTimeFetcher(httpClient, okxBaseURL+"/api/v5/public/time", parseOKXServerTime))
 
 	// Get current position mode first
 	if err := trader.detectPositionMode(); err != nil {
@@ -155,7 +175,7 @@ func (t *OKXTrader) detectPositionMode() error {
 
 	if len(configs) > 0 {
 		t.positionMode = configs[0].PosMode
-		logger.Infof("✓ Detected OKX position mode: %s", t.positionMode)
+		t.log().Infof("✓ Detected OKX position mode: %s", t.positionMode)
 	}
 
 	return nil
@@ -171,20 +191,25 @@ func (t *OKXTrader) setPositionMode() error {
 	if err != nil {
 		// Ignore error if already in dual position mode
 		if strings.Contains(err.Error(), "already") || strings.Contains(err.Error(), "Position mode is not modified") {
-			logger.Infof("  ✓ OKX account is already in dual position mode")
+			t.log().Infof("  ✓ OKX account is already in dual position mode")
 			return nil
 		}
 		return err
 	}
 
-	logger.Infof("  ✓ OKX account switched to dual position mode")
+	t.log().Infof("  ✓ OKX account switched to dual position mode")
 	return nil
 }
 
+// AddAPIKeys rotates requests across the primary and the additional API keys (MultiKeyTrader)
+func (t *OKXTrader) AddAPIKeys(keys []store.APIKeyCredential) {
+	t.keys = NewAPIKeyPool(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase}, keys)
+}
+
 // sign generates OKX API signature
-func (t *OKXTrader) sign(timestamp, method, requestPath, body string) string {
+func (t *OKXTrader) sign(secretKey, timestamp, method, requestPath, body string) string {
 	preHash := timestamp + method + requestPath + body
-	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h := hmac.New(sha256.New, []byte(secretKey))
 	h.Write([]byte(preHash))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
@@ -201,27 +226,33 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 		}
 	}
 
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-	signature := t.sign(timestamp, method, path, string(bodyBytes))
+	key, cred := t.keys.Next(store.APIKeyCredential{APIKey: t.apiKey, SecretKey: t.secretKey, Passphrase: t.passphrase})
+	timestamp := t.clock.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	signature := t.sign(cred.SecretKey, timestamp, method, path, string(bodyBytes))
 
 	req, err := http.NewRequest(method, okxBaseURL+path, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("OK-ACCESS-KEY", t.apiKey)
+	req.Header.Set("OK-ACCESS-KEY", cred.APIKey)
 	req.Header.Set("OK-ACCESS-SIGN", signature)
 	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
-	req.Header.Set("OK-ACCESS-PASSPHRASE", t.passphrase)
+	req.Header.Set("OK-ACCESS-PASSPHRASE", cred.Passphrase)
 	req.Header.Set("Content-Type", "application/json")
-	// Set request header
-	req.Header.Set("x-simulated-trading", "0")
+	// Demo trading uses the live host and keys created in demo mode
+	if t.testnet {
+		req.Header.Set("x-simulated-trading", "1")
+	} else {
+		req.Header.Set("x-simulated-trading", "0")
+	}
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	t.keys.ObserveResponse(key, resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -236,12 +267,31 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 	// code=1 indicates partial success, need to check specific results in data
 	// code=2 indicates complete failure
 	if okxResp.Code != "0" && okxResp.Code != "1" {
+		if okxResp.Code == okxCodeRateLimited {
+			t.keys.CoolDown(key, 0)
+		}
 		return nil, fmt.Errorf("OKX API error: code=%s, msg=%s", okxResp.Code, okxResp.Msg)
 	}
 
 	return okxResp.Data, nil
 }
 
+// parseOKXServerTime parses /api/v5/public/time: {"data":[{"ts":"1597026383085"}]}
+func parseOKXServerTime(body []byte) (int64, error) {
+	var resp struct {
+		Data []struct {
+			Ts json.RawMessage `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, err
+	}
+	if len(resp.Data) == 0 {
+		return 0, fmt.Errorf("empty OKX server time response")
+	}
+	return parseMillisField(resp.Data[0].Ts)
+}
+
 // convertSymbol converts generic symbol to OKX format
 // e.g. BTCUSDT -> BTC-USDT-SWAP
 func (t *OKXTrader) convertSymbol(symbol string) string {
@@ -266,12 +316,12 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 	t.balanceCacheMutex.RLock()
 	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
 		t.balanceCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached OKX account balance")
+		t.log().Infof("✓ Using cached OKX account balance")
 		return t.cachedBalance, nil
 	}
 	t.balanceCacheMutex.RUnlock()
 
-	logger.Infof("🔄 Calling OKX API to get account balance...")
+	t.log().Infof("🔄 Calling OKX API to get account balance...")
 	data, err := t.doRequest("GET", okxAccountPath, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
@@ -319,7 +369,7 @@ func (t *OKXTrader) GetBalance() (map[string]interface{}, error) {
 		"totalUnrealizedProfit": usdtUPL,
 	}
 
-	logger.Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
+	t.log().Infof("✓ OKX balance: Total equity=%.2f, Available=%.2f, Unrealized PnL=%.2f", totalEq, usdtAvail, usdtUPL)
 
 	// Update cache
 	t.balanceCacheMutex.Lock()
@@ -336,12 +386,12 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 	t.positionsCacheMutex.RLock()
 	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
 		t.positionsCacheMutex.RUnlock()
-		logger.Infof("✓ Using cached OKX positions")
+		t.log().Infof("✓ Using cached OKX positions")
 		return t.cachedPositions, nil
 	}
 	t.positionsCacheMutex.RUnlock()
 
-	logger.Infof("🔄 Calling OKX API to get positions...")
+	t.log().Infof("🔄 Calling OKX API to get positions...")
 	data, err := t.doRequest("GET", okxPositionPath+"?instType=SWAP", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
@@ -366,10 +416,10 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to parse position data: %w", err)
 	}
 
-	logger.Infof("🔍 OKX raw positions response: %d positions", len(positions))
+	t.log().Infof("🔍 OKX raw positions response: %d positions", len(positions))
 	var result []map[string]interface{}
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX raw position: instId=%s, posSide=%s, pos=%s, mgnMode=%s", pos.InstId, pos.PosSide, pos.Pos, pos.MgnMode)
+		t.log().Infof("🔍 OKX raw position: instId=%s, posSide=%s, pos=%s, mgnMode=%s", pos.InstId, pos.PosSide, pos.Pos, pos.MgnMode)
 		contractCount, _ := strconv.ParseFloat(pos.Pos, 64)
 		if contractCount == 0 {
 			continue
@@ -383,7 +433,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 
 		// Convert symbol format
 		symbol := t.convertSymbolBack(pos.InstId)
-		logger.Infof("🔍 OKX symbol conversion: %s → %s", pos.InstId, symbol)
+		t.log().Infof("🔍 OKX symbol conversion: %s → %s", pos.InstId, symbol)
 
 		// Determine direction and ensure contractCount is positive
 		side := "long"
@@ -401,7 +451,7 @@ func (t *OKXTrader) GetPositions() ([]map[string]interface{}, error) {
 		posAmt := contractCount
 		if err == nil && inst.CtVal > 0 {
 			posAmt = contractCount * inst.CtVal
-			logger.Debugf("  📊 OKX position %s: contracts=%.4f, ctVal=%.6f, posAmt=%.6f", symbol, contractCount, inst.CtVal, posAmt)
+			t.log().Debugf("  📊 OKX position %s: contracts=%.4f, ctVal=%.6f, posAmt=%.6f", symbol, contractCount, inst.CtVal, posAmt)
 		}
 
 		// Parse timestamps
@@ -475,6 +525,7 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 		MaxMktSz string `json:"maxMktSz"` // Maximum market order size
 		TickSz   string `json:"tickSz"`
 		CtType   string `json:"ctType"`
+		Lever    string `json:"lever"` // Max leverage
 	}
 
 	if err := json.Unmarshal(data, &instruments); err != nil {
@@ -492,6 +543,7 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 	minSz, _ := strconv.ParseFloat(inst.MinSz, 64)
 	maxMktSz, _ := strconv.ParseFloat(inst.MaxMktSz, 64)
 	tickSz, _ := strconv.ParseFloat(inst.TickSz, 64)
+	maxLever, _ := strconv.ParseFloat(inst.Lever, 64)
 
 	instrument := &OKXInstrument{
 		InstID:   inst.InstId,
@@ -502,6 +554,7 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 		MaxMktSz: maxMktSz,
 		TickSz:   tickSz,
 		CtType:   inst.CtType,
+		MaxLever: maxLever,
 	}
 
 	// Update cache
@@ -513,6 +566,15 @@ func (t *OKXTrader) getInstrument(symbol string) (*OKXInstrument, error) {
 	return instrument, nil
 }
 
+// GetSymbolInfo gets per-symbol trading limits (max leverage from instrument info)
+func (t *OKXTrader) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
+	inst, err := t.getInstrument(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &SymbolInfo{Symbol: symbol, MaxLeverage: int(inst.MaxLever)}, nil
+}
+
 // SetMarginMode sets margin mode
 func (t *OKXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	instId := t.convertSymbol(symbol)
@@ -531,18 +593,18 @@ func (t *OKXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	if err != nil {
 		// Ignore error if already in target mode
 		if strings.Contains(err.Error(), "already") {
-			logger.Infof("  ✓ %s margin mode is already %s", symbol, mgnMode)
+			t.log().Infof("  ✓ %s margin mode is already %s", symbol, mgnMode)
 			return nil
 		}
 		// Cannot change when there are positions
 		if strings.Contains(err.Error(), "position") {
-			logger.Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
+			t.log().Infof("  ⚠️ %s has positions, cannot change margin mode", symbol)
 			return nil
 		}
 		return err
 	}
 
-	logger.Infof("  ✓ %s margin mode set to %s", symbol, mgnMode)
+	t.log().Infof("  ✓ %s margin mode set to %s", symbol, mgnMode)
 	return nil
 }
 
@@ -565,99 +627,74 @@ func (t *OKXTrader) SetLeverage(symbol string, leverage int) error {
 			if strings.Contains(err.Error(), "same") {
 				continue
 			}
-			logger.Infof("  ⚠️ Failed to set %s %s leverage: %v", symbol, posSide, err)
+			t.log().Infof("  ⚠️ Failed to set %s %s leverage: %v", symbol, posSide, err)
 		}
 	}
 
-	logger.Infof("  ✓ %s leverage set to %dx", symbol, leverage)
+	t.log().Infof("  ✓ %s leverage set to %dx", symbol, leverage)
 	return nil
 }
 
-// OpenLong opens long position
-func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	// Cancel old orders
-	t.CancelAllOrders(symbol)
-
-	// Set leverage
-	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
-	}
-
-	instId := t.convertSymbol(symbol)
-
-	// Get instrument info and calculate contract size
-	inst, err := t.getInstrument(symbol)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get instrument info: %w", err)
+// AdjustPositionMargin adds (amount > 0) or removes (amount < 0) isolated margin of an open position
+// (implements PositionMarginAdjuster)
+func (t *OKXTrader) AdjustPositionMargin(symbol, positionSide string, amount float64) error {
+	action := "add"
+	if amount < 0 {
+		action = "reduce"
+		amount = -amount
 	}
-
-	// OKX uses contract count, need to convert quantity (in base asset) to contract count
-	// sz = quantity / ctVal (number of contracts = asset amount / asset per contract)
-	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
-
-	logger.Infof("  📊 OKX OpenLong: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
-
-	// Check max market order size limit
-	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
-		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
-		sz = inst.MaxMktSz
-		szStr = t.formatSize(sz, inst)
-	}
-
 	body := map[string]interface{}{
-		"instId":  instId,
-		"tdMode":  "cross",
-		"side":    "buy",
-		"posSide": "long",
-		"ordType": "market",
-		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
-		"tag":     okxTag,
+		"instId":  t.convertSymbol(symbol),
+		"posSide": strings.ToLower(positionSide),
+		"type":    action,
+		"amt":     strconv.FormatFloat(amount, 'f', -1, 64),
 	}
-
-	data, err := t.doRequest("POST", okxOrderPath, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open long position: %w", err)
+	if _, err := t.doRequest("POST", "/api/v5/account/position/margin-balance", body); err != nil {
+		return fmt.Errorf("failed to adjust position margin: %w", err)
 	}
+	return nil
+}
 
-	var orders []struct {
-		OrdId   string `json:"ordId"`
-		ClOrdId string `json:"clOrdId"`
-		SCode   string `json:"sCode"`
-		SMsg    string `json:"sMsg"`
-	}
+// OpenLong opens long position
+func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "long", quantity, leverage, 0, 0, 0)
+}
 
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, fmt.Errorf("failed to parse order response: %w", err)
-	}
+// OpenShort opens short position
+func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "short", quantity, leverage, 0, 0, 0)
+}
 
-	if len(orders) == 0 || orders[0].SCode != "0" {
-		msg := "unknown error"
-		if len(orders) > 0 {
-			msg = orders[0].SMsg
-		}
-		return nil, fmt.Errorf("failed to open long position: %s", msg)
-	}
+// OpenLongWithBracket opens long position with stop loss / take profit attached to the entry order
+func (t *OKXTrader) OpenLongWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "long", quantity, leverage, stopLoss, takeProfit, 0)
+}
 
-	logger.Infof("✓ OKX opened long position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+// OpenShortWithBracket opens short position with stop loss / take profit attached to the entry order
+func (t *OKXTrader) OpenShortWithBracket(symbol string, quantity float64, leverage int, stopLoss, takeProfit float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "short", quantity, leverage, stopLoss, takeProfit, 0)
+}
 
-	return map[string]interface{}{
-		"orderId": orders[0].OrdId,
-		"symbol":  symbol,
-		"status":  "FILLED",
-	}, nil
+// OpenLongPostOnly opens long position with a post-only limit buy at price
+func (t *OKXTrader) OpenLongPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "long", quantity, leverage, 0, 0, price)
 }
 
-// OpenShort opens short position
-func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+// OpenShortPostOnly opens short position with a post-only limit sell at price
+func (t *OKXTrader) OpenShortPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "short", quantity, leverage, 0, 0, price)
+}
+
+// openPosition places a market entry order, or a post-only limit at postOnlyPrice when it is non-zero
+// (OKX cancels it instead of letting it cross the book); non-zero stopLoss / takeProfit are attached
+// to it (attachAlgoOrds) and become active as soon as the entry fills
+func (t *OKXTrader) openPosition(symbol, posSide string, quantity float64, leverage int, stopLoss, takeProfit, postOnlyPrice float64) (map[string]interface{}, error) {
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
 	// Set leverage
 	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("  ⚠️ Failed to set leverage: %v", err)
+		t.log().Infof("  ⚠️ Failed to set leverage: %v", err)
 	}
 
 	instId := t.convertSymbol(symbol)
@@ -673,29 +710,54 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 	sz := quantity / inst.CtVal
 	szStr := t.formatSize(sz, inst)
 
-	logger.Infof("  📊 OKX OpenShort: quantity=%.6f, ctVal=%.6f, contracts=%.2f", quantity, inst.CtVal, sz)
+	t.log().Infof("  📊 OKX open %s: quantity=%.6f, ctVal=%.6f, contracts=%.2f", posSide, quantity, inst.CtVal, sz)
 
 	// Check max market order size limit
-	if inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
-		logger.Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
+	if postOnlyPrice <= 0 && inst.MaxMktSz > 0 && sz > inst.MaxMktSz {
+		t.log().Infof("  ⚠️ OKX market order size %.2f exceeds max %.2f, reducing to max", sz, inst.MaxMktSz)
 		sz = inst.MaxMktSz
 		szStr = t.formatSize(sz, inst)
 	}
 
+	side := "buy"
+	if posSide == "short" {
+		side = "sell"
+	}
+
 	body := map[string]interface{}{
 		"instId":  instId,
 		"tdMode":  "cross",
-		"side":    "sell",
-		"posSide": "short",
+		"side":    side,
+		"posSide": posSide,
 		"ordType": "market",
 		"sz":      szStr,
 		"clOrdId": genOkxClOrdID(),
 		"tag":     okxTag,
 	}
+	status := "FILLED"
+	if postOnlyPrice > 0 {
+		body["ordType"] = "post_only"
+		body["px"] = formatLimitPrice(postOnlyPrice, inst.TickSz, side == "sell")
+		status = "NEW"
+	}
+
+	// Attached TP/SL: a single entry with both prices becomes one OCO algo order after the fill
+	if stopLoss > 0 || takeProfit > 0 {
+		attached := map[string]interface{}{}
+		if stopLoss > 0 {
+			attached["slTriggerPx"] = fmt.Sprintf("%.8f", stopLoss)
+			attached["slOrdPx"] = "-1" // Market price
+		}
+		if takeProfit > 0 {
+			attached["tpTriggerPx"] = fmt.Sprintf("%.8f", takeProfit)
+			attached["tpOrdPx"] = "-1" // Market price
+		}
+		body["attachAlgoOrds"] = []map[string]interface{}{attached}
+	}
 
 	data, err := t.doRequest("POST", okxOrderPath, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open short position: %w", err)
+		return nil, fmt.Errorf("failed to open %s position: %w", posSide, err)
 	}
 
 	var orders []struct {
@@ -714,16 +776,19 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 		if len(orders) > 0 {
 			msg = orders[0].SMsg
 		}
-		return nil, fmt.Errorf("failed to open short position: %s", msg)
+		return nil, fmt.Errorf("failed to open %s position: %s", posSide, msg)
 	}
 
-	logger.Infof("✓ OKX opened short position successfully: %s size: %s", symbol, szStr)
-	logger.Infof("  Order ID: %s", orders[0].OrdId)
+	t.log().Infof("✓ OKX opened %s position successfully: %s size: %s", posSide, symbol, szStr)
+	t.log().Infof("  Order ID: %s", orders[0].OrdId)
+	if stopLoss > 0 || takeProfit > 0 {
+		t.log().Infof("  Attached stop loss: %.4f, take profit: %.4f", stopLoss, takeProfit)
+	}
 
 	return map[string]interface{}{
 		"orderId": orders[0].OrdId,
 		"symbol":  symbol,
-		"status":  "FILLED",
+		"status":  status,
 	}, nil
 }
 
@@ -748,9 +813,9 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 	var actualQty float64
 	var posFound bool
 	var posMgnMode string = "cross" // Default to cross margin
-	logger.Infof("🔍 OKX CloseLong: searching for symbol=%s in %d positions", symbol, len(positions))
+	t.log().Infof("🔍 OKX CloseLong: searching for symbol=%s in %d positions", symbol, len(positions))
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v", pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
+		t.log().Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v", pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
 		if pos["symbol"] == symbol {
 			side := pos["side"].(string)
 			// In net_mode, "long" means positive position
@@ -761,14 +826,14 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 				if mgnMode, ok := pos["mgnMode"].(string); ok && mgnMode != "" {
 					posMgnMode = mgnMode
 				}
-				logger.Infof("🔍 OKX CloseLong: found matching position! qty=%.6f, mgnMode=%s", actualQty, posMgnMode)
+				t.log().Infof("🔍 OKX CloseLong: found matching position! qty=%.6f, mgnMode=%s", actualQty, posMgnMode)
 				break
 			}
 		}
 	}
 
 	if !posFound || actualQty == 0 {
-		logger.Infof("🔍 OKX CloseLong: NO position found for %s LONG", symbol)
+		t.log().Infof("🔍 OKX CloseLong: NO position found for %s LONG", symbol)
 		return map[string]interface{}{
 			"status":  "NO_POSITION",
 			"message": fmt.Sprintf("No long position found for %s on OKX", symbol),
@@ -785,7 +850,7 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 	contracts := quantity / inst.CtVal
 	szStr := t.formatSize(contracts, inst)
 
-	logger.Infof("🔻 OKX close long: symbol=%s, instId=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
+	t.log().Infof("🔻 OKX close long: symbol=%s, instId=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
 		symbol, instId, quantity, inst.CtVal, contracts, szStr, t.positionMode, posMgnMode)
 
 	body := map[string]interface{}{
@@ -826,7 +891,7 @@ func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]inter
 		return nil, fmt.Errorf("failed to close long position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX closed long position successfully: %s", symbol)
+	t.log().Infof("✓ OKX closed long position successfully: %s", symbol)
 
 	// Cancel pending orders after closing position
 	t.CancelAllOrders(symbol)
@@ -859,9 +924,9 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 	var actualQty float64
 	var posFound bool
 	var posMgnMode string = "cross" // Default to cross margin
-	logger.Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
+	t.log().Infof("🔍 OKX CloseShort searching positions: symbol=%s, current position count=%d", symbol, len(positions))
 	for _, pos := range positions {
-		logger.Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v",
+		t.log().Infof("🔍 OKX position: symbol=%v, side=%v, positionAmt=%v, mgnMode=%v",
 			pos["symbol"], pos["side"], pos["positionAmt"], pos["mgnMode"])
 		if pos["symbol"] == symbol && pos["side"] == "short" {
 			actualQty = pos["positionAmt"].(float64)
@@ -869,7 +934,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 			if mgnMode, ok := pos["mgnMode"].(string); ok && mgnMode != "" {
 				posMgnMode = mgnMode
 			}
-			logger.Infof("🔍 OKX found short position: quantity=%f (base asset), mgnMode=%s", actualQty, posMgnMode)
+			t.log().Infof("🔍 OKX found short position: quantity=%f (base asset), mgnMode=%s", actualQty, posMgnMode)
 			break
 		}
 	}
@@ -896,7 +961,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 	contracts := quantity / inst.CtVal
 	szStr := t.formatSize(contracts, inst)
 
-	logger.Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
+	t.log().Infof("🔻 OKX close short: symbol=%s, quantity=%.6f, ctVal=%.6f, contracts=%.2f, szStr=%s, posMode=%s, mgnMode=%s",
 		symbol, quantity, inst.CtVal, contracts, szStr, t.positionMode, posMgnMode)
 
 	body := map[string]interface{}{
@@ -914,7 +979,7 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		body["posSide"] = "short"
 	}
 
-	logger.Infof("🔻 OKX close short request body: %+v", body)
+	t.log().Infof("🔻 OKX close short request body: %+v", body)
 
 	data, err := t.doRequest("POST", okxOrderPath, body)
 	if err != nil {
@@ -936,11 +1001,11 @@ func (t *OKXTrader) CloseShort(symbol string, quantity float64) (map[string]inte
 		if len(orders) > 0 {
 			msg = fmt.Sprintf("sCode=%s, sMsg=%s", orders[0].SCode, orders[0].SMsg)
 		}
-		logger.Infof("❌ OKX failed to close short position: %s, response: %s", msg, string(data))
+		t.log().Infof("❌ OKX failed to close short position: %s, response: %s", msg, string(data))
 		return nil, fmt.Errorf("failed to close short position: %s", msg)
 	}
 
-	logger.Infof("✓ OKX closed short position successfully: %s, ordId=%s", symbol, orders[0].OrdId)
+	t.log().Infof("✓ OKX closed short position successfully: %s, ordId=%s", symbol, orders[0].OrdId)
 
 	// Cancel pending orders after closing position
 	t.CancelAllOrders(symbol)
@@ -982,51 +1047,117 @@ func (t *OKXTrader) GetMarketPrice(symbol string) (float64, error) {
 	return price, nil
 }
 
-// SetStopLoss sets stop loss order
-func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
-	instId := t.convertSymbol(symbol)
-
-	// Get instrument info
-	inst, err := t.getInstrument(symbol)
+// GetBestBidAsk gets the best bid and ask from the market ticker
+func (t *OKXTrader) GetBestBidAsk(symbol string) (float64, float64, error) {
+	path := fmt.Sprintf("%s?instId=%s", okxTickerPath, t.convertSymbol(symbol))
+	data, err := t.doRequest("GET", path, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get instrument info: %w", err)
+		return 0, 0, fmt.Errorf("failed to get ticker: %w", err)
 	}
 
-	// Calculate contract size: quantity (in base asset) / ctVal (asset per contract)
-	sz := quantity / inst.CtVal
-	szStr := t.formatSize(sz, inst)
+	var tickers []struct {
+		BidPx string `json:"bidPx"`
+		AskPx string `json:"askPx"`
+	}
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return 0, 0, err
+	}
+	if len(tickers) == 0 {
+		return 0, 0, fmt.Errorf("no ticker data received")
+	}
 
-	// Determine direction
-	side := "sell"
-	posSide := "long"
-	if strings.ToUpper(positionSide) == "SHORT" {
-		side = "buy"
-		posSide = "short"
+	bid, _ := strconv.ParseFloat(tickers[0].BidPx, 64)
+	ask, _ := strconv.ParseFloat(tickers[0].AskPx, 64)
+	return bid, ask, nil
+}
+
+// GetOrderBook gets the top depth levels of the order book
+func (t *OKXTrader) GetOrderBook(symbol string, depth int) (*kernel.OrderBook, error) {
+	path := fmt.Sprintf("%s?instId=%s&sz=%d", okxBooksPath, t.convertSymbol(symbol), min(depth, 400))
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
 	}
 
-	body := map[string]interface{}{
-		"instId":      instId,
-		"tdMode":      "cross",
-		"side":        side,
-		"posSide":     posSide,
-		"ordType":     "conditional",
-		"sz":          szStr,
-		"slTriggerPx": fmt.Sprintf("%.8f", stopPrice),
-		"slOrdPx":     "-1", // Market price
-		"tag":         okxTag,
+	var books []struct {
+		Bids [][]interface{} `json:"bids"` // [price, size, deprecated, order count]
+		Asks [][]interface{} `json:"asks"`
+	}
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, err
+	}
+	if len(books) == 0 {
+		return nil, fmt.Errorf("no order book data received")
 	}
 
-	_, err = t.doRequest("POST", okxAlgoOrderPath, body)
-	if err != nil {
+	// Sizes are in contracts, convert to coins so depth notional is comparable across exchanges
+	ctVal := 1.0
+	if inst, err := t.getInstrument(symbol); err == nil && inst.CtVal > 0 {
+		ctVal = inst.CtVal
+	}
+	book := &kernel.OrderBook{
+		Symbol: symbol,
+		Bids:   parseOrderBookLevels(books[0].Bids, depth),
+		Asks:   parseOrderBookLevels(books[0].Asks, depth),
+	}
+	for i := range book.Bids {
+		book.Bids[i].Quantity *= ctVal
+	}
+	for i := range book.Asks {
+		book.Asks[i].Quantity *= ctVal
+	}
+	return book, nil
+}
+
+// SetStopLoss sets stop loss order
+func (t *OKXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeExitAlgoOrder(symbol, positionSide, "sl", quantity, stopPrice, 0); err != nil {
 		return fmt.Errorf("failed to set stop loss: %w", err)
 	}
 
-	logger.Infof("  Stop loss price set: %.4f", stopPrice)
+	t.log().Infof("  Stop loss price set: %.4f", stopPrice)
 	return nil
 }
 
 // SetTakeProfit sets take profit order
 func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeExitAlgoOrder(symbol, positionSide, "tp", quantity, takeProfitPrice, 0); err != nil {
+		return fmt.Errorf("failed to set take profit: %w", err)
+	}
+
+	t.log().Infof("  Take profit price set: %.4f", takeProfitPrice)
+	return nil
+}
+
+// SetPartialTakeProfit places a take profit for quantity only (implements PartialTakeProfitSetter):
+// SetTakeProfit already sends a conditional algo order for sz contracts on the position side
+func (t *OKXTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// SetStopLossLimit sets a stop-limit order: a limit order at limitPrice is placed when stopPrice triggers
+func (t *OKXTrader) SetStopLossLimit(symbol string, positionSide string, quantity, stopPrice, limitPrice float64) error {
+	if err := t.placeExitAlgoOrder(symbol, positionSide, "sl", quantity, stopPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set stop-limit: %w", err)
+	}
+
+	t.log().Infof("  Stop-limit set: trigger %.4f, limit %.4f", stopPrice, limitPrice)
+	return nil
+}
+
+// SetTakeProfitLimit sets a take-profit-limit order: a limit order at limitPrice is placed when takeProfitPrice triggers
+func (t *OKXTrader) SetTakeProfitLimit(symbol string, positionSide string, quantity, takeProfitPrice, limitPrice float64) error {
+	if err := t.placeExitAlgoOrder(symbol, positionSide, "tp", quantity, takeProfitPrice, limitPrice); err != nil {
+		return fmt.Errorf("failed to set take-profit-limit: %w", err)
+	}
+
+	t.log().Infof("  Take-profit-limit set: trigger %.4f, limit %.4f", takeProfitPrice, limitPrice)
+	return nil
+}
+
+// placeExitAlgoOrder places a conditional algo order closing the position
+// kind: "sl" or "tp"; limitPrice 0 = market order on trigger
+func (t *OKXTrader) placeExitAlgoOrder(symbol, positionSide, kind string, quantity, triggerPrice, limitPrice float64) error {
 	instId := t.convertSymbol(symbol)
 
 	// Get instrument info
@@ -1047,25 +1178,25 @@ func (t *OKXTrader) SetTakeProfit(symbol string, positionSide string, quantity,
 		posSide = "short"
 	}
 
-	body := map[string]interface{}{
-		"instId":      instId,
-		"tdMode":      "cross",
-		"side":        side,
-		"posSide":     posSide,
-		"ordType":     "conditional",
-		"sz":          szStr,
-		"tpTriggerPx": fmt.Sprintf("%.8f", takeProfitPrice),
-		"tpOrdPx":     "-1", // Market price
-		"tag":         okxTag,
+	ordPx := "-1" // Market price
+	if limitPrice > 0 {
+		ordPx = formatLimitPrice(limitPrice, inst.TickSz, side == "buy")
 	}
 
-	_, err = t.doRequest("POST", okxAlgoOrderPath, body)
-	if err != nil {
-		return fmt.Errorf("failed to set take profit: %w", err)
+	body := map[string]interface{}{
+		"instId":           instId,
+		"tdMode":           "cross",
+		"side":             side,
+		"posSide":          posSide,
+		"ordType":          "conditional",
+		"sz":               szStr,
+		kind + "TriggerPx": fmt.Sprintf("%.8f", triggerPrice),
+		kind + "OrdPx":     ordPx,
+		"tag":              okxTag,
 	}
 
-	logger.Infof("  Take profit price set: %.4f", takeProfitPrice)
-	return nil
+	_, err = t.doRequest("POST", okxAlgoOrderPath, body)
+	return err
 }
 
 // CancelStopLossOrders cancels stop loss orders
@@ -1082,20 +1213,26 @@ func (t *OKXTrader) CancelTakeProfitOrders(symbol string) error {
 func (t *OKXTrader) cancelAlgoOrders(symbol string, orderType string) error {
 	instId := t.convertSymbol(symbol)
 
-	// Get pending algo orders
-	path := fmt.Sprintf("%s?instType=SWAP&instId=%s&ordType=conditional", okxAlgoPendingPath, instId)
-	data, err := t.doRequest("GET", path, nil)
-	if err != nil {
-		return err
-	}
-
+	// Get pending algo orders (oco = TP/SL attached to an entry order)
 	var orders []struct {
 		AlgoId string `json:"algoId"`
 		InstId string `json:"instId"`
 	}
+	for _, ordType := range []string{"conditional", "oco"} {
+		path := fmt.Sprintf("%s?instType=SWAP&instId=%s&ordType=%s", okxAlgoPendingPath, instId, ordType)
+		data, err := t.doRequest("GET", path, nil)
+		if err != nil {
+			return err
+		}
 
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return err
+		var pending []struct {
+			AlgoId string `json:"algoId"`
+			InstId string `json:"instId"`
+		}
+		if err := json.Unmarshal(data, &pending); err != nil {
+			return err
+		}
+		orders = append(orders, pending...)
 	}
 
 	canceledCount := 0
@@ -1109,19 +1246,66 @@ func (t *OKXTrader) cancelAlgoOrders(symbol string, orderType string) error {
 
 		_, err := t.doRequest("POST", okxCancelAlgoPath, body)
 		if err != nil {
-			logger.Infof("  ⚠️ Failed to cancel algo order: %v", err)
+			t.log().Infof("  ⚠️ Failed to cancel algo order: %v", err)
 			continue
 		}
 		canceledCount++
 	}
 
 	if canceledCount > 0 {
-		logger.Infof("  ✓ Canceled %d algo orders for %s", canceledCount, symbol)
+		t.log().Infof("  ✓ Canceled %d algo orders for %s", canceledCount, symbol)
 	}
 
 	return nil
 }
 
+// CancelOrder cancels a single pending order by order ID, falling back to the algo
+// order endpoint for stop-loss/take-profit orders
+func (t *OKXTrader) CancelOrder(symbol string, orderID string) error {
+	instId := t.convertSymbol(symbol)
+
+	var results []struct {
+		SCode string `json:"sCode"`
+		SMsg  string `json:"sMsg"`
+	}
+	cancelled := func(data []byte) error {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return err
+		}
+		if len(results) == 0 || results[0].SCode != "0" {
+			msg := "unknown error"
+			if len(results) > 0 {
+				msg = fmt.Sprintf("sCode=%s, sMsg=%s", results[0].SCode, results[0].SMsg)
+			}
+			return fmt.Errorf("%s", msg)
+		}
+		return nil
+	}
+
+	data, err := t.doRequest("POST", okxCancelOrderPath, map[string]interface{}{
+		"instId": instId,
+		"ordId":  orderID,
+	})
+	if err == nil {
+		if err = cancelled(data); err == nil {
+			return nil
+		}
+	}
+
+	algoData, algoErr := t.doRequest("POST", okxCancelAlgoPath, []map[string]interface{}{
+		{
+			"algoId": orderID,
+			"instId": instId,
+		},
+	})
+	if algoErr == nil {
+		if algoErr = cancelled(algoData); algoErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to cancel order %s: %v (algo: %v)", orderID, err, algoErr)
+}
+
 // CancelAllOrders cancels all pending orders
 func (t *OKXTrader) CancelAllOrders(symbol string) error {
 	instId := t.convertSymbol(symbol)
@@ -1155,7 +1339,7 @@ func (t *OKXTrader) CancelAllOrders(symbol string) error {
 	t.cancelAlgoOrders(symbol, "")
 
 	if len(orders) > 0 {
-		logger.Infof("  ✓ Canceled all pending orders for %s", symbol)
+		t.log().Infof("  ✓ Canceled all pending orders for %s", symbol)
 	}
 
 	return nil
@@ -1243,7 +1427,7 @@ func (t *OKXTrader) GetOrderStatus(symbol string, orderID string) (map[string]in
 	inst, err := t.getInstrument(symbol)
 	if err == nil && inst.CtVal > 0 {
 		executedQty = fillSz * inst.CtVal
-		logger.Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
+		t.log().Debugf("  📊 OKX order %s: fillSz(contracts)=%.4f, ctVal=%.6f, executedQty=%.6f", orderID, fillSz, inst.CtVal, executedQty)
 	}
 
 	// Status mapping
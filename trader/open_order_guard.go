@@ -0,0 +1,102 @@
+package trader
+
+import (
+	"strconv"
+	"strings"
+)
+
+// defaultMaxOpenOrdersPerSymbol open order limit per symbol when risk_control.max_open_orders_per_symbol is not set
+const defaultMaxOpenOrdersPerSymbol = 10
+
+// maxOpenOrdersPerSymbol returns risk_control.max_open_orders_per_symbol (default 10)
+func (at *AutoTrader) maxOpenOrdersPerSymbol() int {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.MaxOpenOrdersPerSymbol > 0 {
+		return at.config.StrategyConfig.RiskControl.MaxOpenOrdersPerSymbol
+	}
+	return defaultMaxOpenOrdersPerSymbol
+}
+
+// enforceOpenOrderLimit runs before a new stop order of kind ("stop_loss"/"take_profit") is placed for
+// the symbol's positionSide. Repeated SL/TP resets can leave orphan orders behind (exchanges that can't
+// cancel by kind, failed cancels), so when the new order would exceed the per-symbol limit, stale
+// protective orders are cancelled first: orders of a side without an open position, exact duplicates,
+// and, for a stop loss, the stops it replaces. Entry orders are never touched.
+func (at *AutoTrader) enforceOpenOrderLimit(symbol, positionSide, kind string) {
+	limit := at.maxOpenOrdersPerSymbol()
+	orders, err := at.trader.GetOpenOrders(symbol)
+	if err != nil || len(orders) < limit {
+		return
+	}
+
+	heldSides := make(map[string]bool)
+	if positions, err := at.trader.GetPositions(); err == nil {
+		for _, pos := range positions {
+			if pos["symbol"] == symbol && positionFloat(pos, "positionAmt") != 0 {
+				side, _ := pos["side"].(string)
+				heldSides[strings.ToUpper(side)] = true
+			}
+		}
+	} else {
+		return // Without positions orphans can't be told apart from live protection
+	}
+
+	stale := staleProtectiveOrders(orders, heldSides, positionSide, kind)
+	excess := len(orders) - limit + 1
+	at.log().Warnf("⚠️ [%s] %s has %d open orders (limit %d), cancelling up to %d stale protective order(s)",
+		at.name, symbol, len(orders), limit, excess)
+
+	cancelled := 0
+	for _, o := range stale {
+		if cancelled >= excess {
+			break
+		}
+		if err := at.trader.CancelOrder(symbol, o.OrderID); err != nil {
+			at.log().Infof("  ⚠ Failed to cancel stale order %s (%s): %v", o.OrderID, symbol, err)
+			continue
+		}
+		cancelled++
+	}
+	if cancelled < excess {
+		at.log().Warnf("⚠️ [%s] %s still has %d open orders after cleanup (limit %d)", at.name, symbol, len(orders)-cancelled, limit)
+	}
+}
+
+// staleProtectiveOrders returns the protective orders safe to cancel, most clearly stale first:
+// orders of sides without an open position, then duplicates (same side, kind and trigger price),
+// then, when a stop loss is about to be placed, the side's existing stops it supersedes
+func staleProtectiveOrders(orders []OpenOrder, heldSides map[string]bool, positionSide, kind string) []OpenOrder {
+	var orphans, duplicates, superseded []OpenOrder
+	seen := make(map[string]bool)
+	for _, o := range orders {
+		side, orderKind := protectiveOrderSide(o)
+		switch {
+		case orderKind == "":
+			continue // Entry or other non-protective order
+		case !heldSides[side]:
+			orphans = append(orphans, o)
+			continue
+		}
+
+		key := side + "|" + orderKind + "|" + strconv.FormatFloat(o.StopPrice, 'f', -1, 64)
+		if seen[key] {
+			duplicates = append(duplicates, o)
+			continue
+		}
+		seen[key] = true
+		if kind == "stop_loss" && orderKind == "stop_loss" && side == positionSide {
+			superseded = append(superseded, o)
+		}
+	}
+	return append(append(orphans, duplicates...), superseded...)
+}
+
+// protectiveOrderSide returns the position side ("LONG"/"SHORT") and kind of a protective order
+// ("" kind for non-protective orders)
+func protectiveOrderSide(o OpenOrder) (string, string) {
+	for _, side := range []string{"LONG", "SHORT"} {
+		if kind := protectiveOrderKind(o, side); kind != "" {
+			return side, kind
+		}
+	}
+	return "", ""
+}
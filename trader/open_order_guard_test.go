@@ -0,0 +1,63 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/store"
+)
+
+// fakeOrderBookTrader holds a long BTCUSDT position and a fixed set of open orders
+type fakeOrderBookTrader struct {
+	Trader
+	orders    []OpenOrder
+	cancelled []string
+}
+
+func (f *fakeOrderBookTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	return f.orders, nil
+}
+
+func (f *fakeOrderBookTrader) GetPositions() ([]map[string]interface{}, error) {
+	return []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0}}, nil
+}
+
+func (f *fakeOrderBookTrader) CancelOrder(symbol, orderID string) error {
+	f.cancelled = append(f.cancelled, orderID)
+	return nil
+}
+
+func TestEnforceOpenOrderLimit(t *testing.T) {
+	orders := []OpenOrder{
+		{OrderID: "entry", Side: "BUY", PositionSide: "LONG", Type: "LIMIT", Price: 90},
+		{OrderID: "sl1", Side: "SELL", PositionSide: "LONG", Type: "STOP_MARKET", StopPrice: 95},
+		{OrderID: "tp1", Side: "SELL", PositionSide: "LONG", Type: "TAKE_PROFIT_MARKET", StopPrice: 110},
+		{OrderID: "tp1-dup", Side: "SELL", PositionSide: "LONG", Type: "TAKE_PROFIT_MARKET", StopPrice: 110},
+		{OrderID: "orphan", Side: "BUY", PositionSide: "SHORT", Type: "STOP_MARKET", StopPrice: 105},
+	}
+	newTrader := func(max int) (*AutoTrader, *fakeOrderBookTrader) {
+		ft := &fakeOrderBookTrader{orders: orders}
+		cfg := &store.StrategyConfig{}
+		cfg.RiskControl.MaxOpenOrdersPerSymbol = max
+		return &AutoTrader{trader: ft, config: AutoTraderConfig{StrategyConfig: cfg}}, ft
+	}
+
+	at, ft := newTrader(10)
+	at.enforceOpenOrderLimit("BTCUSDT", "LONG", "take_profit")
+	if len(ft.cancelled) != 0 {
+		t.Errorf("within the limit cancelled %v, want none", ft.cancelled)
+	}
+
+	// 5 orders, limit 4: two must go so the new take profit fits, orphan first, then the duplicate
+	at, ft = newTrader(4)
+	at.enforceOpenOrderLimit("BTCUSDT", "LONG", "take_profit")
+	if len(ft.cancelled) != 2 || ft.cancelled[0] != "orphan" || ft.cancelled[1] != "tp1-dup" {
+		t.Errorf("cancelled %v, want [orphan tp1-dup]", ft.cancelled)
+	}
+
+	// A new stop loss supersedes the existing one; the entry order is never cancelled
+	at, ft = newTrader(2)
+	at.enforceOpenOrderLimit("BTCUSDT", "LONG", "stop_loss")
+	if len(ft.cancelled) != 3 || ft.cancelled[2] != "sl1" {
+		t.Errorf("cancelled %v, want [orphan tp1-dup sl1]", ft.cancelled)
+	}
+}
@@ -0,0 +1,127 @@
+package trader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AllOpenOrdersLister is implemented by exchanges that list the open orders of all symbols in one call
+type AllOpenOrdersLister interface {
+	GetAllOpenOrders() ([]OpenOrder, error)
+}
+
+// openOrdersFanOut max concurrent per-symbol GetOpenOrders calls
+const openOrdersFanOut = 4
+
+// AllOpenOrders open orders of a trader across symbols
+type AllOpenOrders struct {
+	Orders  []OpenOrder       `json:"orders"`
+	Symbols []string          `json:"symbols"`          // Symbols queried (empty when listed in one exchange call)
+	Errors  map[string]string `json:"errors,omitempty"` // Symbols whose query failed
+}
+
+// GetAllOpenOrders returns the pending SL/TP/limit orders of all symbols. Exchanges that can list
+// every open order at once are asked directly; otherwise the trader's open positions and locally
+// pending orders are queried symbol by symbol.
+func (at *AutoTrader) GetAllOpenOrders() (*AllOpenOrders, error) {
+	if lister, ok := at.trader.(AllOpenOrdersLister); ok {
+		orders, err := lister.GetAllOpenOrders()
+		if err == nil {
+			return &AllOpenOrders{Orders: normalizeOpenOrders(orders), Symbols: []string{}}, nil
+		}
+		at.log().Warnf("⚠️ [%s] Listing all open orders failed, querying per symbol: %v", at.name, err)
+	}
+
+	symbols, err := at.openOrderSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AllOpenOrders{Orders: []OpenOrder{}, Symbols: symbols}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, openOrdersFanOut)
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			orders, err := at.trader.GetOpenOrders(symbol)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if result.Errors == nil {
+					result.Errors = make(map[string]string)
+				}
+				result.Errors[symbol] = err.Error()
+				return
+			}
+			result.Orders = append(result.Orders, orders...)
+		}(symbol)
+	}
+	wg.Wait()
+
+	if len(symbols) > 0 && len(result.Errors) == len(symbols) {
+		return nil, fmt.Errorf("failed to get open orders for all %d symbols", len(symbols))
+	}
+	result.Orders = normalizeOpenOrders(result.Orders)
+	return result, nil
+}
+
+// openOrderSymbols symbols that may have open orders: open positions plus locally pending orders
+func (at *AutoTrader) openOrderSymbols() ([]string, error) {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		symbol = at.normalizeSymbol(symbol)
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	for _, pos := range positions {
+		if symbol, ok := pos["symbol"].(string); ok {
+			add(symbol)
+		}
+	}
+
+	if at.store != nil {
+		if pending, err := at.store.Order().GetTraderOrdersFiltered(at.id, "", "NEW", 200); err == nil {
+			for _, order := range pending {
+				add(order.Symbol)
+			}
+		}
+	}
+
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// normalizeOpenOrders upper-cases the enum fields and sorts orders by symbol, then type
+func normalizeOpenOrders(orders []OpenOrder) []OpenOrder {
+	for i := range orders {
+		orders[i].Side = strings.ToUpper(orders[i].Side)
+		orders[i].PositionSide = strings.ToUpper(orders[i].PositionSide)
+		orders[i].Type = strings.ToUpper(orders[i].Type)
+		orders[i].Status = strings.ToUpper(orders[i].Status)
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Symbol != orders[j].Symbol {
+			return orders[i].Symbol < orders[j].Symbol
+		}
+		return orders[i].Type < orders[j].Type
+	})
+	if orders == nil {
+		orders = []OpenOrder{}
+	}
+	return orders
+}
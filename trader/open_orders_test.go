@@ -0,0 +1,25 @@
+package trader
+
+import "testing"
+
+func TestNormalizeOpenOrders(t *testing.T) {
+	orders := normalizeOpenOrders([]OpenOrder{
+		{OrderID: "3", Symbol: "SOLUSDT", Side: "sell", PositionSide: "long", Type: "take_profit_market", Status: "new"},
+		{OrderID: "1", Symbol: "BTCUSDT", Side: "buy", PositionSide: "short", Type: "STOP_MARKET", Status: "NEW"},
+		{OrderID: "2", Symbol: "BTCUSDT", Side: "BUY", PositionSide: "SHORT", Type: "LIMIT", Status: "NEW"},
+	})
+
+	wantIDs := []string{"2", "1", "3"}
+	for i, id := range wantIDs {
+		if orders[i].OrderID != id {
+			t.Fatalf("order %d = %s, want %s", i, orders[i].OrderID, id)
+		}
+	}
+	if o := orders[2]; o.Side != "SELL" || o.PositionSide != "LONG" || o.Type != "TAKE_PROFIT_MARKET" || o.Status != "NEW" {
+		t.Errorf("fields not normalized: %+v", o)
+	}
+
+	if empty := normalizeOpenOrders(nil); empty == nil || len(empty) != 0 {
+		t.Errorf("nil input should give an empty slice, got %#v", empty)
+	}
+}
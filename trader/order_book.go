@@ -0,0 +1,134 @@
+package trader
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"nofx/kernel"
+)
+
+// OrderBookProvider is implemented by exchanges that can return the top levels of a symbol's order book
+type OrderBookProvider interface {
+	GetOrderBook(symbol string, depth int) (*kernel.OrderBook, error)
+}
+
+// orderBookCandidateLimit how many of the top-ranked candidates get an order book snapshot
+const orderBookCandidateLimit = 5
+
+// orderBookCache order books fetched during one decision cycle, so every symbol's book is requested
+// at most once per cycle no matter how often the context is built
+type orderBookCache struct {
+	mu    sync.Mutex
+	cycle int
+	books map[string]*kernel.OrderBook
+}
+
+// get returns the cached book of symbol for cycle, fetching it on a miss. Starting a new cycle
+// drops the previous cycle's books. Errors are not cached.
+func (c *orderBookCache) get(cycle int, symbol string, fetch func() (*kernel.OrderBook, error)) (*kernel.OrderBook, error) {
+	c.mu.Lock()
+	if c.books == nil || c.cycle != cycle {
+		c.cycle = cycle
+		c.books = make(map[string]*kernel.OrderBook)
+	}
+	if book, ok := c.books[symbol]; ok {
+		c.mu.Unlock()
+		return book, nil
+	}
+	c.mu.Unlock()
+
+	book, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.cycle == cycle {
+		c.books[symbol] = book
+	}
+	c.mu.Unlock()
+	return book, nil
+}
+
+// orderBookDepth returns indicators.order_book_depth (default 10)
+func (at *AutoTrader) orderBookDepth() int {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.Indicators.OrderBookDepth > 0 {
+		return at.config.StrategyConfig.Indicators.OrderBookDepth
+	}
+	return kernel.DefaultOrderBookDepth
+}
+
+// orderBookSymbols returns the symbols that get an order book: every position and the top-ranked candidates
+func orderBookSymbols(positions []kernel.PositionInfo, candidates []kernel.CandidateCoin) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	for _, pos := range positions {
+		add(pos.Symbol)
+	}
+	for i, coin := range candidates {
+		if i >= orderBookCandidateLimit {
+			break
+		}
+		add(coin.Symbol)
+	}
+	return symbols
+}
+
+// fetchOrderBooks returns the order books of symbols for the current cycle. Symbols whose book
+// can't be fetched are left out; an error is returned only when every fetch failed.
+func (at *AutoTrader) fetchOrderBooks(provider OrderBookProvider, symbols []string) (map[string]*kernel.OrderBook, error) {
+	depth := at.orderBookDepth()
+	books := make(map[string]*kernel.OrderBook, len(symbols))
+	var lastErr error
+	for _, symbol := range symbols {
+		book, err := at.orderBooks.get(at.callCount, symbol, func() (*kernel.OrderBook, error) {
+			return provider.GetOrderBook(symbol, depth)
+		})
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", symbol, err)
+			continue
+		}
+		books[symbol] = book
+	}
+	if len(books) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return books, nil
+}
+
+// parseOrderBookLevels parses up to depth [price, quantity, ...] rows (numbers or numeric strings)
+func parseOrderBookLevels(rows [][]interface{}, depth int) []kernel.OrderBookLevel {
+	levels := make([]kernel.OrderBookLevel, 0, min(len(rows), depth))
+	for _, row := range rows {
+		if len(levels) >= depth {
+			break
+		}
+		if len(row) < 2 {
+			continue
+		}
+		price, quantity := orderBookNumber(row[0]), orderBookNumber(row[1])
+		if price <= 0 || quantity <= 0 {
+			continue
+		}
+		levels = append(levels, kernel.OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return levels
+}
+
+// orderBookNumber converts a JSON number or numeric string to float64 (0 if neither)
+func orderBookNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"nofx/kernel"
+)
+
+func TestOrderBookSymbols(t *testing.T) {
+	positions := []kernel.PositionInfo{{Symbol: "ETHUSDT"}}
+	candidates := []kernel.CandidateCoin{
+		{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "SOLUSDT"},
+		{Symbol: "BNBUSDT"}, {Symbol: "XRPUSDT"}, {Symbol: "DOGEUSDT"},
+	}
+	got := orderBookSymbols(positions, candidates)
+	want := []string{"ETHUSDT", "BTCUSDT", "SOLUSDT", "BNBUSDT", "XRPUSDT"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orderBookSymbols() = %v, want %v", got, want)
+	}
+}
+
+func TestOrderBookCache(t *testing.T) {
+	var c orderBookCache
+	calls := 0
+	fetch := func() (*kernel.OrderBook, error) {
+		calls++
+		return &kernel.OrderBook{Symbol: "BTCUSDT"}, nil
+	}
+
+	c.get(1, "BTCUSDT", fetch)
+	c.get(1, "BTCUSDT", fetch)
+	if calls != 1 {
+		t.Errorf("fetched %d times within one cycle, want 1", calls)
+	}
+	c.get(2, "BTCUSDT", fetch)
+	if calls != 2 {
+		t.Errorf("fetched %d times after a new cycle, want 2", calls)
+	}
+
+	failing := func() (*kernel.OrderBook, error) { return nil, errors.New("down") }
+	if _, err := c.get(2, "ETHUSDT", failing); err == nil {
+		t.Fatal("expected fetch error")
+	}
+	c.get(2, "ETHUSDT", fetch)
+	if calls != 3 {
+		t.Error("failed fetch should not be cached")
+	}
+}
+
+func TestParseOrderBookLevels(t *testing.T) {
+	rows := [][]interface{}{
+		{"100.5", "2"},
+		{100.4, 3.0},
+		{"bad", "1"},
+		{"100.3"},
+		{"100.2", "4"},
+	}
+	got := parseOrderBookLevels(rows, 2)
+	want := []kernel.OrderBookLevel{{Price: 100.5, Quantity: 2}, {Price: 100.4, Quantity: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOrderBookLevels() = %v, want %v", got, want)
+	}
+}
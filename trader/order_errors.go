@@ -0,0 +1,188 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nofx/kernel"
+)
+
+// Typed order rejections, exchange-specific errors are mapped to these by classifyOrderError
+var (
+	ErrInsufficientMargin = errors.New("insufficient margin")
+	ErrMinNotional        = errors.New("order below exchange minimum size")
+	ErrReduceOnlyReject   = errors.New("reduce-only order rejected")
+)
+
+// OrderError an exchange order rejection classified as one of the typed errors.
+// errors.Is matches both the kind and the original exchange error.
+type OrderError struct {
+	Kind     error
+	Exchange string
+	Err      error
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("%v (%s: %v)", e.Kind, e.Exchange, e.Err)
+}
+
+func (e *OrderError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// orderErrorRule maps exchange error codes / message fragments (lowercase) to a typed error
+type orderErrorRule struct {
+	kind      error
+	fragments []string
+}
+
+// commonOrderErrorRules messages used by most exchanges (checked after the exchange's own rules)
+var commonOrderErrorRules = []orderErrorRule{
+	{ErrInsufficientMargin, []string{"insufficient margin", "insufficient balance", "insufficient available", "margin is insufficient", "not enough margin", "balance not enough", "balance is not enough"}},
+	{ErrMinNotional, []string{"min notional", "minimum notional", "minimum order value", "minimum value of", "below the minimum", "too small"}},
+	{ErrReduceOnlyReject, []string{"reduceonly", "reduce-only", "reduce only"}},
+}
+
+// exchangeOrderErrorRules per-exchange error codes and messages
+var exchangeOrderErrorRules = map[string][]orderErrorRule{
+	"binance": {
+		{ErrInsufficientMargin, []string{"code=-2019", "code=-2018"}},
+		{ErrMinNotional, []string{"code=-4164", "notional must be no smaller than"}},
+		{ErrReduceOnlyReject, []string{"code=-2022", "code=-4118"}},
+	},
+	"aster": {
+		{ErrInsufficientMargin, []string{"code=-2019", "code=-2018"}},
+		{ErrMinNotional, []string{"code=-4164", "notional must be no smaller than"}},
+		{ErrReduceOnlyReject, []string{"code=-2022"}},
+	},
+	"bybit": {
+		{ErrInsufficientMargin, []string{"110004", "110007", "110012", "110045", "ab not enough for new order"}},
+		{ErrMinNotional, []string{"110094", "does not meet minimum order value"}},
+		{ErrReduceOnlyReject, []string{"110017", "reduce-only rule not satisfied", "current position is zero"}},
+	},
+	"okx": {
+		{ErrInsufficientMargin, []string{"scode=51008", "scode=51004", "scode=51131"}},
+		{ErrMinNotional, []string{"scode=51020", "min available amount"}},
+		{ErrReduceOnlyReject, []string{"scode=51169", "scode=51170", "scode=51121"}},
+	},
+	"bitget": {
+		{ErrInsufficientMargin, []string{"code=40762", "code=43012", "exceeds the balance"}},
+		{ErrMinNotional, []string{"code=45110", "code=45111", "less than the minimum"}},
+		{ErrReduceOnlyReject, []string{"code=22002", "no position to close"}},
+	},
+	"gate": {
+		{ErrInsufficientMargin, []string{"insufficient_available", "margin_not_enough", "balance_not_enough"}},
+		{ErrMinNotional, []string{"order_size_too_small", "size_too_small"}},
+		{ErrReduceOnlyReject, []string{"reduce_only_fail", "reduce_exceeded"}},
+	},
+	"hyperliquid": {
+		{ErrInsufficientMargin, []string{"insufficient margin to place order"}},
+		{ErrMinNotional, []string{"order must have minimum value"}},
+		{ErrReduceOnlyReject, []string{"reduce only order would increase position"}},
+	},
+	"backpack": {
+		{ErrInsufficientMargin, []string{"insufficient_margin", "insufficient_funds"}},
+		{ErrMinNotional, []string{"quantity is below the minimum"}},
+		{ErrReduceOnlyReject, []string{"reduce only order not reduced"}},
+	},
+	"lighter": {
+		{ErrInsufficientMargin, []string{"not enough collateral"}},
+		{ErrMinNotional, []string{"invalid order base amount"}},
+	},
+}
+
+// classifyOrderError maps an exchange order error to a typed OrderError (returned as is if no rule matches)
+func classifyOrderError(exchange string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *OrderError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	msg := strings.ToLower(err.Error())
+	rules := append(append([]orderErrorRule{}, exchangeOrderErrorRules[exchangeErrorFamily(exchange)]...), commonOrderErrorRules...)
+	for _, rule := range rules {
+		for _, fragment := range rule.fragments {
+			if strings.Contains(msg, fragment) {
+				return &OrderError{Kind: rule.kind, Exchange: exchange, Err: err}
+			}
+		}
+	}
+	return err
+}
+
+// exchangeErrorFamily maps exchange variants to the rule set they share
+func exchangeErrorFamily(exchange string) string {
+	exchange = strings.ToLower(exchange)
+	switch {
+	case strings.HasPrefix(exchange, "gate"):
+		return "gate"
+	case strings.HasPrefix(exchange, "hyperliquid"):
+		return "hyperliquid"
+	case strings.HasPrefix(exchange, "lighter"):
+		return "lighter"
+	}
+	return exchange
+}
+
+// marginRetryShrink size of the retried open relative to the rejected one (when the balance can't be re-read)
+const marginRetryShrink = 0.8
+
+// openPositionWithRetry opens a position and reacts to typed rejections: an insufficient-margin
+// rejection is retried once with a smaller size (re-sized from the fresh available balance).
+// Returns the quantity actually opened. Large market opens are placed as a sliced entry instead.
+func (at *AutoTrader) openPositionWithRetry(decision *kernel.Decision, positionSide string, quantity, price float64) (order map[string]interface{}, opened float64, attachedSL, attachedTP bool, err error) {
+	if n, interval := at.entrySlices(decision, quantity, price); n > 1 {
+		order, opened, err = at.openSlicedEntry(decision, positionSide, quantity, price, n, interval)
+		if err != nil {
+			return nil, 0, false, false, err
+		}
+		return order, opened, order["stopLossType"] != "", order["takeProfitType"] != "", nil
+	}
+
+	order, attachedSL, attachedTP, err = at.openPosition(decision, positionSide, quantity)
+	err = classifyOrderError(at.exchange, err)
+	if !errors.Is(err, ErrInsufficientMargin) || price <= 0 {
+		return order, quantity, attachedSL, attachedTP, err
+	}
+
+	retryQty := quantity * marginRetryShrink
+	if balance, balErr := at.trader.GetBalance(); balErr == nil && decision.Leverage > 0 {
+		if avail, ok := balance["availableBalance"].(float64); ok && avail > 0 {
+			// Same margin estimate as the pre-open auto-size, with a wider buffer
+			affordable := avail / (1.01/float64(decision.Leverage) + 0.001) * 0.9 / price
+			if affordable < retryQty {
+				retryQty = affordable
+			}
+		}
+	}
+	if minErr := at.enforceMinPositionSize(retryQty * price); minErr != nil {
+		at.log().Infof("  ⚠️ %v, not retrying: %v", err, minErr)
+		return nil, quantity, false, false, err
+	}
+
+	at.log().Infof("  ⚠️ %s rejected open for insufficient margin, retrying once with %.4f (was %.4f)", at.exchange, retryQty, quantity)
+	decision.PositionSizeUSD = retryQty * price
+	order, attachedSL, attachedTP, err = at.openPosition(decision, positionSide, retryQty)
+	return order, retryQty, attachedSL, attachedTP, classifyOrderError(at.exchange, err)
+}
+
+// closeRejectedAsFlat reports whether a rejected close failed only because the position is already gone
+func (at *AutoTrader) closeRejectedAsFlat(symbol, side string, err error) bool {
+	if !errors.Is(err, ErrReduceOnlyReject) {
+		return false
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return false
+	}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyOrderError(t *testing.T) {
+	tests := []struct {
+		exchange string
+		err      error
+		want     error
+	}{
+		{"binance", errors.New("<APIError> code=-2019, msg=Margin is insufficient."), ErrInsufficientMargin},
+		{"binance", errors.New("<APIError> code=-4164, msg=Order's notional must be no smaller than 5.0"), ErrMinNotional},
+		{"binance", errors.New("<APIError> code=-2022, msg=ReduceOnly Order is rejected."), ErrReduceOnlyReject},
+		{"okx", errors.New("OKX order failed: sCode=51008, sMsg=Order failed. Insufficient USDT margin in account"), ErrInsufficientMargin},
+		{"bybit", errors.New("Bybit open long failed: order placement failed: ab not enough for new order"), ErrInsufficientMargin},
+		{"bitget", errors.New("Bitget API error: code=45110, msg=less than the minimum amount 5 USDT"), ErrMinNotional},
+		{"gateio", errors.New("Gate.io place order failed: INSUFFICIENT_AVAILABLE"), ErrInsufficientMargin},
+		{"hyperliquid", errors.New("failed to open long position: Order must have minimum value of $10"), ErrMinNotional},
+		{"aster", errors.New("reduce only order rejected"), ErrReduceOnlyReject},
+	}
+	for _, tt := range tests {
+		got := classifyOrderError(tt.exchange, fmt.Errorf("wrapped: %w", tt.err))
+		if !errors.Is(got, tt.want) {
+			t.Errorf("%s %q: got %v, want %v", tt.exchange, tt.err, got, tt.want)
+		}
+		if !errors.Is(got, tt.err) {
+			t.Errorf("%s %q: classified error should still match the original", tt.exchange, tt.err)
+		}
+	}
+
+	other := errors.New("connection reset by peer")
+	if got := classifyOrderError("binance", other); got != other {
+		t.Errorf("unmatched error should be returned as is, got %v", got)
+	}
+	if classifyOrderError("binance", nil) != nil {
+		t.Error("nil error should stay nil")
+	}
+}
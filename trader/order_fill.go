@@ -0,0 +1,297 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// orderUpdateRetention how long final order states pushed by a stream are kept for late waiters
+const orderUpdateRetention = 5 * time.Minute
+
+// errOrderStreamDown returned by WaitOrderFill while the order stream is not connected
+var errOrderStreamDown = errors.New("order stream not connected")
+
+// OrderFillWaiter is implemented by exchanges with a private order update stream (user data stream),
+// allowing fills to be confirmed as soon as the exchange pushes them instead of polling GetOrderStatus
+type OrderFillWaiter interface {
+	// StartOrderStream connects the stream and keeps it alive (reconnecting on errors) until stopCh is closed
+	StartOrderStream(stopCh <-chan struct{}) error
+	// WaitOrderFill blocks until the order reaches a final state or timeout expires.
+	// The result has the same shape as GetOrderStatus (status, avgPrice, executedQty, commission).
+	WaitOrderFill(symbol, orderID string, timeout time.Duration) (map[string]interface{}, error)
+}
+
+// orderFillWaiter returns t's order stream. A mirrored trader reports the primary's order IDs, so
+// its fills are confirmed on the primary's stream.
+func orderFillWaiter(t Trader) (OrderFillWaiter, bool) {
+	if m, ok := t.(*MirrorTrader); ok {
+		t = m.venues[0].Trader
+	}
+	waiter, ok := t.(OrderFillWaiter)
+	return waiter, ok
+}
+
+// OrderFillConfig controls how order fills are confirmed after submission
+type OrderFillConfig struct {
+	PollAttempts  int           // GetOrderStatus polls before giving up (default 5)
+	PollInterval  time.Duration // Delay before the first poll and between polls (default 500ms)
+	UseUserStream bool          // Confirm fills from the exchange's private order stream where supported
+}
+
+// DefaultOrderFillConfig returns the default order fill configuration
+func DefaultOrderFillConfig() OrderFillConfig {
+	return OrderFillConfig{
+		PollAttempts:  5,
+		PollInterval:  500 * time.Millisecond,
+		UseUserStream: true,
+	}
+}
+
+// withDefaults fills zero values with defaults
+func (c OrderFillConfig) withDefaults() OrderFillConfig {
+	def := DefaultOrderFillConfig()
+	if c.PollAttempts <= 0 {
+		c.PollAttempts = def.PollAttempts
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = def.PollInterval
+	}
+	return c
+}
+
+// Window returns how long fill confirmation waits in total (initial delay + all polls)
+func (c OrderFillConfig) Window() time.Duration {
+	c = c.withDefaults()
+	return c.PollInterval * time.Duration(c.PollAttempts+1)
+}
+
+// ForExchange applies the override for exchange from a spec like "gate=10x300ms,okx=8x1s"
+// (attempts x interval). Invalid entries are logged and ignored.
+func (c OrderFillConfig) ForExchange(exchange, spec string) OrderFillConfig {
+	overrides, err := ParseOrderFillOverrides(spec)
+	if err != nil {
+		logger.Warnf("⚠️ Invalid order fill overrides %q: %v", spec, err)
+	}
+	if o, ok := overrides[strings.ToLower(exchange)]; ok {
+		c.PollAttempts = o.PollAttempts
+		c.PollInterval = o.PollInterval
+	}
+	return c
+}
+
+// ParseOrderFillOverrides parses per-exchange poll settings "exchange=attemptsxinterval,...".
+// Valid entries are returned even if others fail to parse.
+func ParseOrderFillOverrides(spec string) (map[string]OrderFillConfig, error) {
+	overrides := make(map[string]OrderFillConfig)
+	var errs []error
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		exchange, value, ok := strings.Cut(entry, "=")
+		attemptsStr, intervalStr, okValue := strings.Cut(strings.TrimSpace(value), "x")
+		if !ok || !okValue || strings.TrimSpace(exchange) == "" {
+			errs = append(errs, fmt.Errorf("%q: expected exchange=attemptsxinterval", entry))
+			continue
+		}
+		attempts, err := strconv.Atoi(strings.TrimSpace(attemptsStr))
+		if err != nil || attempts <= 0 {
+			errs = append(errs, fmt.Errorf("%q: invalid attempts", entry))
+			continue
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(intervalStr))
+		if err != nil || interval <= 0 {
+			errs = append(errs, fmt.Errorf("%q: invalid interval", entry))
+			continue
+		}
+		overrides[strings.ToLower(strings.TrimSpace(exchange))] = OrderFillConfig{PollAttempts: attempts, PollInterval: interval}
+	}
+	return overrides, errors.Join(errs...)
+}
+
+// isFinalOrderStatus reports whether an order in this (unified) status won't change anymore
+func isFinalOrderStatus(status string) bool {
+	switch status {
+	case "FILLED", "CANCELED", "EXPIRED", "REJECTED":
+		return true
+	}
+	return false
+}
+
+// orderUpdate final order state received from a stream
+type orderUpdate struct {
+	status     map[string]interface{}
+	receivedAt time.Time
+}
+
+// orderUpdateHub hands final order states pushed by a user data stream to waiting callers.
+// States are kept for a while because the fill often arrives before the caller starts waiting.
+type orderUpdateHub struct {
+	mu        sync.Mutex
+	final     map[string]orderUpdate
+	waiters   map[string][]chan map[string]interface{}
+	connected atomic.Bool
+	started   atomic.Bool
+}
+
+func newOrderUpdateHub() *orderUpdateHub {
+	return &orderUpdateHub{
+		final:   make(map[string]orderUpdate),
+		waiters: make(map[string][]chan map[string]interface{}),
+	}
+}
+
+// publish records an order update, only final states are kept and delivered
+func (h *orderUpdateHub) publish(orderID string, status map[string]interface{}) {
+	statusStr, _ := status["status"].(string)
+	if orderID == "" || !isFinalOrderStatus(statusStr) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for id, u := range h.final {
+		if now.Sub(u.receivedAt) > orderUpdateRetention {
+			delete(h.final, id)
+		}
+	}
+	h.final[orderID] = orderUpdate{status: status, receivedAt: now}
+
+	for _, ch := range h.waiters[orderID] {
+		ch <- status
+	}
+	delete(h.waiters, orderID)
+}
+
+// wait blocks until a final state for orderID is published or timeout expires
+func (h *orderUpdateHub) wait(orderID string, timeout time.Duration) (map[string]interface{}, error) {
+	if h == nil || !h.connected.Load() {
+		return nil, errOrderStreamDown
+	}
+
+	h.mu.Lock()
+	if u, ok := h.final[orderID]; ok {
+		h.mu.Unlock()
+		return u.status, nil
+	}
+	ch := make(chan map[string]interface{}, 1)
+	h.waiters[orderID] = append(h.waiters[orderID], ch)
+	h.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case status := <-ch:
+		return status, nil
+	case <-timer.C:
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Published between the timeout and taking the lock
+	select {
+	case status := <-ch:
+		return status, nil
+	default:
+	}
+	waiters := h.waiters[orderID]
+	for i, w := range waiters {
+		if w == ch {
+			h.waiters[orderID] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(h.waiters[orderID]) == 0 {
+		delete(h.waiters, orderID)
+	}
+	return nil, fmt.Errorf("no update for order %s within %v", orderID, timeout)
+}
+
+// runStreamLoop keeps a stream connected until stopCh is closed, reconnecting with backoff.
+// connect blocks while the connection is alive and calls onConnected once it is established.
+// log is the client's logger, so the stream's lines go to the trader using it.
+func (h *orderUpdateHub) runStreamLoop(name string, log func() *logger.Entry, stopCh <-chan struct{}, connect func(stopCh <-chan struct{}, onConnected func()) error) {
+	go func() {
+		defer h.started.Store(false)
+		backoff := time.Second
+		for {
+			err := connect(stopCh, func() {
+				h.connected.Store(true)
+				backoff = time.Second
+				log().Infof("📡 [%s] Order stream connected", name)
+			})
+			h.connected.Store(false)
+
+			select {
+			case <-stopCh:
+				log().Infof("⏹ [%s] Order stream stopped", name)
+				return
+			default:
+			}
+			log().Warnf("⚠️ [%s] Order stream disconnected, reconnecting in %v: %v", name, backoff, err)
+
+			select {
+			case <-time.After(backoff):
+			case <-stopCh:
+				return
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+// confirmOrderFill waits for an order to reach a final state.
+// Uses the exchange order stream when available and falls back to polling GetOrderStatus.
+// Returns the last status seen (nil if none), which may not be final if the window expired.
+func (at *AutoTrader) confirmOrderFill(symbol, orderID string) map[string]interface{} {
+	cfg := at.config.OrderFill.withDefaults()
+
+	if waiter, ok := orderFillWaiter(at.trader); ok && cfg.UseUserStream {
+		start := time.Now()
+		status, err := waiter.WaitOrderFill(symbol, orderID, cfg.Window())
+		if err == nil {
+			at.log().Infof("  📡 Order %s update received from stream in %v", orderID, time.Since(start).Round(time.Millisecond))
+			return status
+		}
+		if !errors.Is(err, errOrderStreamDown) {
+			at.log().Infof("  ⚠️ Order stream: %v, falling back to polling", err)
+		}
+	}
+
+	var last map[string]interface{}
+	for i := 0; i < cfg.PollAttempts; i++ {
+		time.Sleep(cfg.PollInterval)
+		status, err := at.trader.GetOrderStatus(symbol, orderID)
+		if err != nil {
+			continue
+		}
+		last = status
+		if statusStr, _ := status["status"].(string); isFinalOrderStatus(statusStr) {
+			return status
+		}
+	}
+	at.log().Infof("  ⚠️ Order %s not final after %d polls (%v)", orderID, cfg.PollAttempts, cfg.PollInterval)
+	return last
+}
+
+// requestOrderSync asks the order sync loop to run now (no-op without order sync)
+func (at *AutoTrader) requestOrderSync() {
+	if at.orderSyncTrigger == nil {
+		return
+	}
+	select {
+	case at.orderSyncTrigger <- struct{}{}:
+	default: // A sync is already pending
+	}
+}
@@ -0,0 +1,156 @@
+package trader
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// fakeStreamTrader reports fills through an order stream
+type fakeStreamTrader struct {
+	Trader
+	waited []string // Order IDs passed to WaitOrderFill
+}
+
+func (f *fakeStreamTrader) StartOrderStream(stopCh <-chan struct{}) error { return nil }
+
+func (f *fakeStreamTrader) WaitOrderFill(symbol, orderID string, timeout time.Duration) (map[string]interface{}, error) {
+	f.waited = append(f.waited, orderID)
+	return map[string]interface{}{"status": "FILLED", "executedQty": 1.0, "avgPrice": 100.0}, nil
+}
+
+func TestRecordAndConfirmOrder_StreamBeforeOrderSync(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "fill.db"))
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	defer st.Close()
+
+	ft := &fakeStreamTrader{}
+	at := &AutoTrader{
+		exchange:         "binance",
+		trader:           ft,
+		store:            st,
+		orderSyncTrigger: make(chan struct{}, 1),
+		config:           AutoTraderConfig{OrderFill: OrderFillConfig{UseUserStream: true}},
+	}
+	at.recordAndConfirmOrder(map[string]interface{}{"orderId": int64(42)}, "BTCUSDT", "open_long", 1, 100, 5, 0)
+
+	if len(ft.waited) != 1 || ft.waited[0] != "42" {
+		t.Errorf("stream waited for %v, want order 42", ft.waited)
+	}
+	select {
+	case <-at.orderSyncTrigger:
+	default:
+		t.Error("order sync not requested after the stream confirmed the fill")
+	}
+}
+
+func TestParseOrderFillOverrides(t *testing.T) {
+	overrides, err := ParseOrderFillOverrides("gate=10x300ms, OKX=8x1s, bad=x, lighter=3")
+	if err == nil {
+		t.Error("expected error for invalid entries")
+	}
+	if got := overrides["gate"]; got.PollAttempts != 10 || got.PollInterval != 300*time.Millisecond {
+		t.Errorf("gate override = %+v", got)
+	}
+	if got := overrides["okx"]; got.PollAttempts != 8 || got.PollInterval != time.Second {
+		t.Errorf("okx override = %+v", got)
+	}
+	if len(overrides) != 2 {
+		t.Errorf("expected 2 valid overrides, got %v", overrides)
+	}
+}
+
+func TestOrderFillConfig_ForExchange(t *testing.T) {
+	base := OrderFillConfig{PollAttempts: 5, PollInterval: 500 * time.Millisecond, UseUserStream: true}
+
+	cfg := base.ForExchange("gate", "gate=10x300ms")
+	if cfg.PollAttempts != 10 || cfg.PollInterval != 300*time.Millisecond || !cfg.UseUserStream {
+		t.Errorf("override not applied: %+v", cfg)
+	}
+	if cfg := base.ForExchange("binance", "gate=10x300ms"); cfg != base {
+		t.Errorf("other exchange should keep base config, got %+v", cfg)
+	}
+	if w := (OrderFillConfig{}).Window(); w != 3*time.Second {
+		t.Errorf("default window = %v, want 3s", w)
+	}
+}
+
+func TestOrderUpdateHub_PublishBeforeWait(t *testing.T) {
+	hub := newOrderUpdateHub()
+	hub.connected.Store(true)
+
+	hub.publish("42", map[string]interface{}{"status": "NEW"})
+	hub.publish("42", map[string]interface{}{"status": "FILLED", "avgPrice": 100.5})
+
+	status, err := hub.wait("42", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected cached final state, got %v", err)
+	}
+	if status["avgPrice"] != 100.5 {
+		t.Errorf("unexpected status %v", status)
+	}
+}
+
+func TestOrderUpdateHub_WaitThenPublish(t *testing.T) {
+	hub := newOrderUpdateHub()
+	hub.connected.Store(true)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		hub.publish("7", map[string]interface{}{"status": "CANCELED"})
+	}()
+	status, err := hub.wait("7", time.Second)
+	if err != nil || status["status"] != "CANCELED" {
+		t.Fatalf("wait() = %v, %v", status, err)
+	}
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if len(hub.waiters) != 0 {
+		t.Errorf("waiters not cleaned up: %v", hub.waiters)
+	}
+}
+
+func TestOrderUpdateHub_TimeoutAndDisconnected(t *testing.T) {
+	hub := newOrderUpdateHub()
+	if _, err := hub.wait("1", time.Millisecond); !errors.Is(err, errOrderStreamDown) {
+		t.Errorf("expected stream down error, got %v", err)
+	}
+
+	hub.connected.Store(true)
+	if _, err := hub.wait("1", time.Millisecond); err == nil {
+		t.Error("expected timeout error")
+	}
+	if len(hub.waiters) != 0 {
+		t.Errorf("waiters not cleaned up after timeout: %v", hub.waiters)
+	}
+
+	var nilHub *orderUpdateHub
+	if _, err := nilHub.wait("1", time.Millisecond); !errors.Is(err, errOrderStreamDown) {
+		t.Errorf("nil hub should report stream down, got %v", err)
+	}
+}
+
+func TestBybitUnifiedOrderStatus(t *testing.T) {
+	cases := []struct {
+		status string
+		qty    float64
+		want   string
+	}{
+		{"Filled", 1, "FILLED"},
+		{"PartiallyFilledCanceled", 0.5, "FILLED"},
+		{"PartiallyFilledCanceled", 0, "CANCELED"},
+		{"Deactivated", 0, "CANCELED"},
+		{"Rejected", 0, "REJECTED"},
+		{"New", 0, "NEW"},
+	}
+	for _, c := range cases {
+		if got := bybitUnifiedOrderStatus(c.status, c.qty); got != c.want {
+			t.Errorf("bybitUnifiedOrderStatus(%q, %v) = %q, want %q", c.status, c.qty, got, c.want)
+		}
+	}
+}
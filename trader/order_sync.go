@@ -0,0 +1,194 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/store"
+	"sync"
+	"time"
+)
+
+// OrderSyncer is implemented by exchanges that sync orders/fills from the exchange into the local store
+type OrderSyncer interface {
+	// SyncOrders syncs recent trades from exchange and updates local orders/positions
+	SyncOrders(traderID string, exchangeID string, exchangeType string, st *store.Store) error
+}
+
+// OrderSyncConfig order sync retry and health configuration
+type OrderSyncConfig struct {
+	Interval         time.Duration // Sync interval (default 30s)
+	MaxRetries       int           // Retries within one cycle before the cycle counts as failed (default 2)
+	RetryBackoff     time.Duration // Initial backoff between retries, doubled after each retry (default 2s)
+	UnhealthyAfter   int           // Consecutive failed cycles before sync is marked unhealthy (default 5)
+	PauseOnUnhealthy bool          // Pause trading cycles while sync is unhealthy
+}
+
+// DefaultOrderSyncConfig returns the default order sync configuration
+func DefaultOrderSyncConfig() OrderSyncConfig {
+	return OrderSyncConfig{
+		Interval:       30 * time.Second,
+		MaxRetries:     2,
+		RetryBackoff:   2 * time.Second,
+		UnhealthyAfter: 5,
+	}
+}
+
+// withDefaults fills zero values with defaults
+func (c OrderSyncConfig) withDefaults() OrderSyncConfig {
+	def := DefaultOrderSyncConfig()
+	if c.Interval <= 0 {
+		c.Interval = def.Interval
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = def.RetryBackoff
+	}
+	if c.UnhealthyAfter <= 0 {
+		c.UnhealthyAfter = def.UnhealthyAfter
+	}
+	return c
+}
+
+// OrderSyncHealth tracks order sync failures for one trader
+type OrderSyncHealth struct {
+	mu                  sync.RWMutex
+	unhealthyAfter      int
+	consecutiveFailures int
+	totalFailures       int
+	lastError           string
+	lastErrorTime       time.Time
+	lastSuccessTime     time.Time
+}
+
+// NewOrderSyncHealth creates order sync health tracker
+func NewOrderSyncHealth(unhealthyAfter int) *OrderSyncHealth {
+	if unhealthyAfter <= 0 {
+		unhealthyAfter = DefaultOrderSyncConfig().UnhealthyAfter
+	}
+	return &OrderSyncHealth{unhealthyAfter: unhealthyAfter}
+}
+
+// RecordSuccess records a successful sync cycle
+func (h *OrderSyncHealth) RecordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures = 0
+	h.lastSuccessTime = time.Now().UTC()
+}
+
+// RecordFailure records a failed sync cycle, returns true if sync just became unhealthy
+func (h *OrderSyncHealth) RecordFailure(err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures++
+	h.totalFailures++
+	if err != nil {
+		h.lastError = err.Error()
+	}
+	h.lastErrorTime = time.Now().UTC()
+	return h.consecutiveFailures == h.unhealthyAfter
+}
+
+// IsHealthy returns whether consecutive failures are below the unhealthy threshold
+func (h *OrderSyncHealth) IsHealthy() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.consecutiveFailures < h.unhealthyAfter
+}
+
+// ConsecutiveFailures returns the number of consecutive failed sync cycles
+func (h *OrderSyncHealth) ConsecutiveFailures() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.consecutiveFailures
+}
+
+// Status returns sync health for status API
+func (h *OrderSyncHealth) Status() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	status := map[string]interface{}{
+		"healthy":              h.consecutiveFailures < h.unhealthyAfter,
+		"consecutive_failures": h.consecutiveFailures,
+		"total_failures":       h.totalFailures,
+		"unhealthy_after":      h.unhealthyAfter,
+		"last_error":           h.lastError,
+	}
+	if !h.lastErrorTime.IsZero() {
+		status["last_error_time"] = h.lastErrorTime.Format(time.RFC3339)
+	}
+	if !h.lastSuccessTime.IsZero() {
+		status["last_success_time"] = h.lastSuccessTime.Format(time.RFC3339)
+	}
+	return status
+}
+
+// runOrderSyncCycle runs one sync cycle with bounded retry and exponential backoff
+// Returns the last error if all attempts failed
+func runOrderSyncCycle(cfg OrderSyncConfig, stopCh <-chan struct{}, syncFn func() error) error {
+	var err error
+	backoff := cfg.RetryBackoff
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-stopCh:
+				return err
+			}
+			backoff *= 2
+		}
+		if err = syncFn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// startOrderSyncLoop runs syncer periodically until stopCh is closed, and immediately on each trigger
+// (e.g. a fill pushed by the order stream). Failed cycles are retried with backoff; after UnhealthyAfter
+// consecutive failed cycles the sync is marked unhealthy in health (surfaced via trader status)
+func startOrderSyncLoop(name string, syncer OrderSyncer, traderID, exchangeID, exchangeType string, st *store.Store,
+	cfg OrderSyncConfig, health *OrderSyncHealth, trigger <-chan struct{}, stopCh <-chan struct{}) {
+	log := logger.TraderEntry(traderID, nil)
+	cfg = cfg.withDefaults()
+	syncFn := func() error {
+		return syncer.SyncOrders(traderID, exchangeID, exchangeType, st)
+	}
+
+	runCycle := func() {
+		err := runOrderSyncCycle(cfg, stopCh, syncFn)
+		if err == nil {
+			health.RecordSuccess()
+			return
+		}
+		if health.RecordFailure(err) {
+			log.Warnf("🚨 [%s] Order sync marked unhealthy after %d consecutive failed cycles: %v",
+				name, cfg.UnhealthyAfter, err)
+		} else {
+			log.Infof("⚠️  %s order sync failed after %d attempts: %v", name, cfg.MaxRetries+1, err)
+		}
+	}
+
+	go func() {
+		// Run first sync immediately
+		runCycle()
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				runCycle()
+			case <-trigger:
+				runCycle()
+				ticker.Reset(cfg.Interval)
+			case <-stopCh:
+				log.Infof("⏹ %s order sync stopped", name)
+				return
+			}
+		}
+	}()
+	log.Infof("🔄 %s order sync started (interval: %v, retries: %d)", name, cfg.Interval, cfg.MaxRetries)
+}
@@ -0,0 +1,97 @@
+package trader
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunOrderSyncCycle_RetriesUntilSuccess(t *testing.T) {
+	cfg := OrderSyncConfig{MaxRetries: 3, RetryBackoff: time.Millisecond}
+	calls := 0
+	err := runOrderSyncCycle(cfg, make(chan struct{}), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRunOrderSyncCycle_BoundedRetries(t *testing.T) {
+	cfg := OrderSyncConfig{MaxRetries: 2, RetryBackoff: time.Millisecond}
+	calls := 0
+	err := runOrderSyncCycle(cfg, make(chan struct{}), func() error {
+		calls++
+		return errors.New("exchange down")
+	})
+	if err == nil || err.Error() != "exchange down" {
+		t.Fatalf("expected last error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts (1 + 2 retries), got %d", calls)
+	}
+}
+
+func TestRunOrderSyncCycle_StopsDuringBackoff(t *testing.T) {
+	cfg := OrderSyncConfig{MaxRetries: 5, RetryBackoff: time.Hour}
+	stopCh := make(chan struct{})
+	close(stopCh)
+	calls := 0
+	err := runOrderSyncCycle(cfg, stopCh, func() error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("expected error when stopped during backoff")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt before stop, got %d", calls)
+	}
+}
+
+func TestOrderSyncHealth(t *testing.T) {
+	h := NewOrderSyncHealth(3)
+	if !h.IsHealthy() {
+		t.Fatal("new health tracker should be healthy")
+	}
+
+	syncErr := errors.New("timeout")
+	if h.RecordFailure(syncErr) || h.RecordFailure(syncErr) {
+		t.Fatal("should not become unhealthy before threshold")
+	}
+	if !h.IsHealthy() {
+		t.Error("expected healthy with 2/3 failures")
+	}
+	if !h.RecordFailure(syncErr) {
+		t.Error("expected transition to unhealthy on 3rd failure")
+	}
+	if h.IsHealthy() {
+		t.Error("expected unhealthy after 3 consecutive failures")
+	}
+	if h.RecordFailure(syncErr) {
+		t.Error("transition should only be reported once")
+	}
+
+	status := h.Status()
+	if status["healthy"] != false || status["last_error"] != "timeout" || status["consecutive_failures"] != 4 {
+		t.Errorf("unexpected status: %v", status)
+	}
+	if _, ok := status["last_error_time"]; !ok {
+		t.Error("expected last_error_time in status")
+	}
+
+	h.RecordSuccess()
+	if !h.IsHealthy() || h.ConsecutiveFailures() != 0 {
+		t.Error("success should reset consecutive failures")
+	}
+	if h.Status()["total_failures"] != 4 {
+		t.Error("total failures should be kept after success")
+	}
+}
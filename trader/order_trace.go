@@ -0,0 +1,174 @@
+package trader
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nofx/logger"
+)
+
+// orderTraceTTL how long a placed order's correlation ID is kept for order sync to pick up
+const orderTraceTTL = 24 * time.Hour
+
+type orderTraceEntry struct {
+	traceID  string
+	placedAt time.Time
+}
+
+// orderTraceRegistry correlation IDs of recently placed orders (exchange account + order ID -> trace ID;
+// order IDs are only unique per exchange, and several accounts may run on the same exchange).
+// Most exchanges record orders and fills later through order sync, which only sees exchange
+// trade history; the registry lets those records be tagged with the cycle that placed them.
+type orderTraceRegistry struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]orderTraceEntry
+}
+
+var orderTraces = newOrderTraceRegistry(orderTraceTTL)
+
+func newOrderTraceRegistry(ttl time.Duration) *orderTraceRegistry {
+	return &orderTraceRegistry{ttl: ttl, entries: make(map[string]orderTraceEntry)}
+}
+
+// remember records the correlation ID of an order, dropping expired entries
+func (r *orderTraceRegistry) remember(orderID, traceID string, now time.Time) {
+	if orderID == "" || traceID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, e := range r.entries {
+		if now.Sub(e.placedAt) > r.ttl {
+			delete(r.entries, id)
+		}
+	}
+	r.entries[orderID] = orderTraceEntry{traceID: traceID, placedAt: now}
+}
+
+// lookup returns the correlation ID of an order ("" if unknown or expired). Entries stay until
+// they expire, since an order can be synced as several fills.
+func (r *orderTraceRegistry) lookup(orderID string, now time.Time) string {
+	if orderID == "" {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[orderID]
+	if !ok || now.Sub(e.placedAt) > r.ttl {
+		return ""
+	}
+	return e.traceID
+}
+
+// orderTraceKey registry key of an order of an exchange account ("" without an order ID)
+func orderTraceKey(exchangeID, orderID string) string {
+	if orderID == "" {
+		return ""
+	}
+	return exchangeID + "/" + orderID
+}
+
+// rememberOrderTrace records the correlation ID of the cycle that placed an order on an exchange account
+func rememberOrderTrace(exchangeID, orderID, traceID string) {
+	orderTraces.remember(orderTraceKey(exchangeID, orderID), traceID, time.Now())
+}
+
+// orderTraceID returns the correlation ID of the cycle that placed an order on an exchange account ("" if unknown)
+func orderTraceID(exchangeID, orderID string) string {
+	return orderTraces.lookup(orderTraceKey(exchangeID, orderID), time.Now())
+}
+
+// startTrace starts a new correlation ID for a trading cycle (or an externally triggered decision)
+// and returns the context carrying it
+func (at *AutoTrader) startTrace() context.Context {
+	ctx := logger.WithTraceID(context.Background(), logger.NewTraceID())
+	at.traceMu.Lock()
+	at.traceCtx = ctx
+	at.traceMu.Unlock()
+	at.setClientTrace(ctx)
+	return ctx
+}
+
+// withExecTrace runs fn under its own correlation ID, leaving the cycle's trace as it is: while fn
+// runs, the trader's and the exchange client's log lines and placed orders are tagged with it.
+// The caller holds execMu, so no cycle runs meanwhile.
+func (at *AutoTrader) withExecTrace(fn func(ctx context.Context)) {
+	ctx := logger.WithTraceID(context.Background(), logger.NewTraceID())
+	at.traceMu.Lock()
+	at.execTraceCtx = ctx
+	at.traceMu.Unlock()
+	at.setClientTrace(ctx)
+	defer func() {
+		at.traceMu.Lock()
+		at.execTraceCtx = nil
+		at.traceMu.Unlock()
+		at.setClientTrace(at.traceContext())
+	}()
+	fn(ctx)
+}
+
+// setClientTrace tags the exchange client's log lines with the trader and ctx's correlation ID
+func (at *AutoTrader) setClientTrace(ctx context.Context) {
+	if setter, ok := at.trader.(TraceContextSetter); ok {
+		setter.SetTraceContext(at.id, ctx)
+	}
+}
+
+// traceContext returns the context of the running out-of-cycle execution, else of the current cycle
+// (background before the first cycle)
+func (at *AutoTrader) traceContext() context.Context {
+	at.traceMu.RLock()
+	defer at.traceMu.RUnlock()
+	if at.execTraceCtx != nil {
+		return at.execTraceCtx
+	}
+	if at.traceCtx == nil {
+		return context.Background()
+	}
+	return at.traceCtx
+}
+
+// traceID returns the current cycle's correlation ID
+func (at *AutoTrader) traceID() string {
+	return logger.TraceID(at.traceContext())
+}
+
+// log returns the trader's logger (own file and level when configured), tagging lines with the
+// current cycle's correlation ID
+func (at *AutoTrader) log() *logger.Entry {
+	return at.traderLog.Entry(at.traceContext())
+}
+
+// TraceContextSetter optional interface of exchange clients whose log lines follow the trader using
+// them: its log file and level, and the correlation ID of its current cycle
+type TraceContextSetter interface {
+	SetTraceContext(traderID string, ctx context.Context)
+}
+
+// traceLogger embedded by exchange clients to implement TraceContextSetter. Each trader owns its
+// client, so the last context set is the one of the cycle driving the client.
+type traceLogger struct {
+	trace atomic.Pointer[clientTrace]
+}
+
+type clientTrace struct {
+	log *logger.TraderLogger
+	ctx context.Context
+}
+
+// SetTraceContext tags the client's following log lines with the trader and ctx's correlation ID
+func (l *traceLogger) SetTraceContext(traderID string, ctx context.Context) {
+	l.trace.Store(&clientTrace{log: logger.ForTrader(traderID), ctx: ctx})
+}
+
+// log returns the logger of the trader using the client, tagged with its current correlation ID
+// (the global logger until a trader sets its context)
+func (l *traceLogger) log() *logger.Entry {
+	if tr := l.trace.Load(); tr != nil {
+		return tr.log.Entry(tr.ctx)
+	}
+	return logger.TraderEntry("", context.Background())
+}
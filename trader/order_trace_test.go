@@ -0,0 +1,102 @@
+package trader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nofx/logger"
+)
+
+func TestOrderTraceRegistry(t *testing.T) {
+	r := newOrderTraceRegistry(time.Hour)
+	now := time.Now()
+
+	r.remember("1001", "trace-a", now)
+	r.remember("", "trace-b", now)
+	r.remember("1002", "", now)
+
+	if got := r.lookup("1001", now.Add(time.Minute)); got != "trace-a" {
+		t.Errorf("lookup(1001) = %q, want trace-a", got)
+	}
+	if got := r.lookup("1001", now.Add(2*time.Minute)); got != "trace-a" {
+		t.Error("an order synced as several fills should keep its trace")
+	}
+	if got := r.lookup("1002", now); got != "" {
+		t.Errorf("order without trace = %q, want empty", got)
+	}
+	if got := r.lookup("1001", now.Add(2*time.Hour)); got != "" {
+		t.Errorf("expired trace = %q, want empty", got)
+	}
+
+	r.remember("1003", "trace-c", now.Add(2*time.Hour))
+	if _, ok := r.entries["1001"]; ok {
+		t.Error("expired entries should be dropped")
+	}
+}
+
+func TestStartTrace(t *testing.T) {
+	at := &AutoTrader{}
+	if at.traceID() != "" {
+		t.Error("no trace expected before the first cycle")
+	}
+
+	ctx := at.startTrace()
+	id := logger.TraceID(ctx)
+	if id == "" || at.traceID() != id {
+		t.Errorf("traceID() = %q, want the started trace %q", at.traceID(), id)
+	}
+	if at.startTrace(); at.traceID() == id {
+		t.Error("each cycle should get a new trace ID")
+	}
+}
+
+func TestStartTraceTagsClientLogs(t *testing.T) {
+	client := &BybitTrader{}
+	if _, ok := client.log().Data[logger.TraceField]; ok {
+		t.Error("client tagged with a trace before any cycle")
+	}
+
+	at := &AutoTrader{id: "trader-1", trader: client}
+	ctx := at.startTrace()
+	if got := client.log().Data[logger.TraceField]; got != logger.TraceID(ctx) {
+		t.Errorf("client log trace = %v, want the cycle's %q", got, logger.TraceID(ctx))
+	}
+}
+
+func TestWithExecTraceKeepsCycleTrace(t *testing.T) {
+	client := &BybitTrader{}
+	at := &AutoTrader{id: "trader-1", trader: client}
+	cycle := logger.TraceID(at.startTrace())
+
+	var exec string
+	at.withExecTrace(func(ctx context.Context) {
+		exec = logger.TraceID(ctx)
+		if at.traceID() != exec || client.log().Data[logger.TraceField] != exec {
+			t.Errorf("inside: trader trace %q, client trace %v, want the execution's %q",
+				at.traceID(), client.log().Data[logger.TraceField], exec)
+		}
+	})
+	if exec == "" || exec == cycle {
+		t.Errorf("execution trace = %q, want its own ID (cycle %q)", exec, cycle)
+	}
+	if at.traceID() != cycle || client.log().Data[logger.TraceField] != cycle {
+		t.Errorf("after: trader trace %q, client trace %v, want the cycle's %q back",
+			at.traceID(), client.log().Data[logger.TraceField], cycle)
+	}
+}
+
+func TestOrderTraceIDPerExchangeAccount(t *testing.T) {
+	rememberOrderTrace("acct-a", "5001", "trace-a")
+	rememberOrderTrace("acct-b", "5001", "trace-b")
+
+	if got := orderTraceID("acct-a", "5001"); got != "trace-a" {
+		t.Errorf("acct-a order 5001 trace = %q, want trace-a", got)
+	}
+	if got := orderTraceID("acct-b", "5001"); got != "trace-b" {
+		t.Errorf("acct-b order 5001 trace = %q, want trace-b (same order ID on another account)", got)
+	}
+	if got := orderTraceID("acct-c", "5001"); got != "" {
+		t.Errorf("unknown account trace = %q, want empty", got)
+	}
+}
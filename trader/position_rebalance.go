@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+)
+
+// PositionMarginAdjuster is implemented by exchanges that can add or remove isolated margin of an open position
+type PositionMarginAdjuster interface {
+	// AdjustPositionMargin adds (amount > 0) or removes (amount < 0) margin of the symbol's
+	// positionSide ("LONG"/"SHORT") position, in quote currency
+	AdjustPositionMargin(symbol, positionSide string, amount float64) error
+}
+
+// RebalanceRequest changes the leverage and/or isolated margin of an open position without closing it
+type RebalanceRequest struct {
+	Symbol      string
+	Side        string  // "LONG" or "SHORT"
+	Leverage    int     // New leverage (0 = unchanged)
+	MarginDelta float64 // Isolated margin to add (> 0) or remove (< 0), 0 = unchanged
+}
+
+// RebalanceResult position state before and after a rebalance
+type RebalanceResult struct {
+	Symbol                   string  `json:"symbol"`
+	Side                     string  `json:"side"`
+	Quantity                 float64 `json:"quantity"`
+	EntryPrice               float64 `json:"entry_price"`
+	MarkPrice                float64 `json:"mark_price"`
+	PreviousLeverage         int     `json:"previous_leverage"`
+	Leverage                 int     `json:"leverage"`
+	MarginDelta              float64 `json:"margin_delta"`
+	PreviousLiquidationPrice float64 `json:"previous_liquidation_price"`
+	LiquidationPrice         float64 `json:"liquidation_price"`
+	// LiquidationEstimated the exchange didn't report a liquidation price, it was estimated locally
+	LiquidationEstimated bool `json:"liquidation_estimated,omitempty"`
+}
+
+// Validate checks the request is well-formed and changes something
+func (r RebalanceRequest) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if r.Side != "LONG" && r.Side != "SHORT" {
+		return fmt.Errorf("side must be LONG or SHORT")
+	}
+	if r.Leverage < 0 {
+		return fmt.Errorf("leverage must be positive")
+	}
+	if r.Leverage == 0 && r.MarginDelta == 0 {
+		return fmt.Errorf("at least one of leverage or margin_delta is required")
+	}
+	return nil
+}
+
+// RebalancePosition changes the leverage and/or isolated margin of an open position, then reads
+// the position back to report the resulting leverage and liquidation price. Margin changes need
+// an exchange implementing PositionMarginAdjuster; leverage changes use SetLeverage, which the
+// exchange may reject while a position is open.
+func RebalancePosition(t Trader, req RebalanceRequest) (*RebalanceResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	adjuster, canAdjustMargin := t.(PositionMarginAdjuster)
+	if req.MarginDelta != 0 && !canAdjustMargin {
+		return nil, fmt.Errorf("exchange does not support position margin adjustment")
+	}
+
+	var info *SymbolInfo
+	if provider, ok := t.(SymbolInfoProvider); ok {
+		info, _ = provider.GetSymbolInfo(req.Symbol)
+	}
+	if info != nil && info.MaxLeverage > 0 && req.Leverage > info.MaxLeverage {
+		return nil, fmt.Errorf("leverage %dx exceeds the exchange maximum %dx for %s", req.Leverage, info.MaxLeverage, req.Symbol)
+	}
+
+	before, err := findOpenPosition(t, req.Symbol, req.Side)
+	if err != nil {
+		return nil, err
+	}
+	result := &RebalanceResult{
+		Symbol:                   req.Symbol,
+		Side:                     req.Side,
+		PreviousLeverage:         positionLeverage(before),
+		PreviousLiquidationPrice: positionFloat(before, "liquidationPrice"),
+		MarginDelta:              req.MarginDelta,
+	}
+
+	if req.Leverage > 0 && req.Leverage != result.PreviousLeverage {
+		if err := t.SetLeverage(req.Symbol, req.Leverage); err != nil {
+			return nil, fmt.Errorf("failed to change leverage: %w", err)
+		}
+		logger.Infof("⚖️ %s %s leverage changed %dx -> %dx", req.Symbol, req.Side, result.PreviousLeverage, req.Leverage)
+	}
+	if req.MarginDelta != 0 {
+		if err := adjuster.AdjustPositionMargin(req.Symbol, req.Side, req.MarginDelta); err != nil {
+			return nil, fmt.Errorf("failed to adjust position margin: %w", err)
+		}
+		logger.Infof("⚖️ %s %s isolated margin adjusted by %+.4f", req.Symbol, req.Side, req.MarginDelta)
+	}
+
+	after, err := findOpenPosition(t, req.Symbol, req.Side)
+	if err != nil {
+		return nil, fmt.Errorf("position changed but could not be read back: %w", err)
+	}
+	result.Quantity = positionFloat(after, "positionAmt")
+	if result.Quantity < 0 {
+		result.Quantity = -result.Quantity
+	}
+	result.EntryPrice = positionFloat(after, "entryPrice")
+	result.MarkPrice = positionFloat(after, "markPrice")
+	result.Leverage = positionLeverage(after)
+	if result.Leverage == 0 {
+		result.Leverage = req.Leverage
+	}
+	result.LiquidationPrice = positionFloat(after, "liquidationPrice")
+
+	// Leverage-only changes can be estimated from the isolated formula; after a margin change
+	// the effective leverage is unknown, so the exchange value is the only reliable one
+	if result.LiquidationPrice <= 0 && req.MarginDelta == 0 && result.Leverage > 0 {
+		tier := MaintenanceTierFor(info, result.Quantity*result.EntryPrice)
+		result.LiquidationPrice = EstimateLiquidationPrice(req.Side, result.EntryPrice, result.Quantity, result.Leverage, tier)
+		result.LiquidationEstimated = result.LiquidationPrice > 0
+	}
+	return result, nil
+}
+
+// findOpenPosition returns the exchange position of symbol on side ("LONG"/"SHORT")
+func findOpenPosition(t Trader, symbol, side string) (map[string]interface{}, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == strings.ToLower(side) && positionFloat(pos, "positionAmt") != 0 {
+			return pos, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s position found for %s", side, symbol)
+}
+
+// positionFloat reads a numeric field of an exchange position map (0 if missing)
+func positionFloat(pos map[string]interface{}, key string) float64 {
+	v, _ := pos[key].(float64)
+	return v
+}
+
+// positionLeverage reads the leverage of an exchange position map (0 if unknown)
+func positionLeverage(pos map[string]interface{}) int {
+	switch lev := pos["leverage"].(type) {
+	case float64:
+		return int(lev)
+	case int:
+		return lev
+	}
+	return 0
+}
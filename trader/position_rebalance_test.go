@@ -0,0 +1,105 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+// fakeRebalanceTrader keeps a single position; embedded Trader methods are not used by RebalancePosition
+type fakeRebalanceTrader struct {
+	Trader
+	position    map[string]interface{}
+	marginDelta float64
+}
+
+func (f *fakeRebalanceTrader) GetPositions() ([]map[string]interface{}, error) {
+	pos := make(map[string]interface{}, len(f.position))
+	for k, v := range f.position {
+		pos[k] = v
+	}
+	return []map[string]interface{}{pos}, nil
+}
+
+func (f *fakeRebalanceTrader) SetLeverage(symbol string, leverage int) error {
+	f.position["leverage"] = float64(leverage)
+	f.position["liquidationPrice"] = 0.0 // Exchange reports no liquidation price
+	return nil
+}
+
+type fakeMarginTrader struct {
+	fakeRebalanceTrader
+}
+
+func (f *fakeMarginTrader) AdjustPositionMargin(symbol, positionSide string, amount float64) error {
+	f.marginDelta += amount
+	f.position["liquidationPrice"] = 85.0
+	return nil
+}
+
+func newRebalancePosition() map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":           "BTCUSDT",
+		"side":             "long",
+		"positionAmt":      1.0,
+		"entryPrice":       100.0,
+		"markPrice":        101.0,
+		"leverage":         10.0,
+		"liquidationPrice": 91.0,
+	}
+}
+
+func TestRebalancePositionLeverage(t *testing.T) {
+	ft := &fakeRebalanceTrader{position: newRebalancePosition()}
+
+	result, err := RebalancePosition(ft, RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG", Leverage: 5})
+	if err != nil {
+		t.Fatalf("RebalancePosition() error = %v", err)
+	}
+	if result.PreviousLeverage != 10 || result.Leverage != 5 {
+		t.Errorf("leverage %dx -> %dx, want 10x -> 5x", result.PreviousLeverage, result.Leverage)
+	}
+	// Missing exchange value: estimated with the default maintenance ratio, (100 * 0.8) / 0.995
+	if !result.LiquidationEstimated || math.Abs(result.LiquidationPrice-80/0.995) > 1e-6 {
+		t.Errorf("liquidation = %v (estimated %v), want estimated %v", result.LiquidationPrice, result.LiquidationEstimated, 80/0.995)
+	}
+}
+
+func TestRebalancePositionMargin(t *testing.T) {
+	if _, err := RebalancePosition(&fakeRebalanceTrader{position: newRebalancePosition()},
+		RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG", MarginDelta: 50}); err == nil {
+		t.Error("margin change on an exchange without PositionMarginAdjuster should fail")
+	}
+
+	ft := &fakeMarginTrader{fakeRebalanceTrader{position: newRebalancePosition()}}
+	result, err := RebalancePosition(ft, RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG", MarginDelta: 50})
+	if err != nil {
+		t.Fatalf("RebalancePosition() error = %v", err)
+	}
+	if ft.marginDelta != 50 || result.LiquidationPrice != 85 || result.LiquidationEstimated {
+		t.Errorf("margin delta %v, liquidation %v (estimated %v), want 50, 85 from exchange",
+			ft.marginDelta, result.LiquidationPrice, result.LiquidationEstimated)
+	}
+
+	if _, err := RebalancePosition(ft, RebalanceRequest{Symbol: "BTCUSDT", Side: "SHORT", MarginDelta: 10}); err == nil {
+		t.Error("rebalancing a side without a position should fail")
+	}
+}
+
+func TestRebalanceRequestValidate(t *testing.T) {
+	tests := []struct {
+		req     RebalanceRequest
+		wantErr bool
+	}{
+		{RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG", Leverage: 3}, false},
+		{RebalanceRequest{Symbol: "BTCUSDT", Side: "SHORT", MarginDelta: -20}, false},
+		{RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG"}, true},
+		{RebalanceRequest{Symbol: "BTCUSDT", Side: "long", Leverage: 3}, true},
+		{RebalanceRequest{Side: "LONG", Leverage: 3}, true},
+		{RebalanceRequest{Symbol: "BTCUSDT", Side: "LONG", Leverage: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.req, err, tt.wantErr)
+		}
+	}
+}
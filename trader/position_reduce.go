@@ -0,0 +1,190 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/store"
+	"strconv"
+	"strings"
+)
+
+// reduceQuantity returns the quantity to close for a partial reduce of percent (0-100, exclusive)
+func reduceQuantity(positionQty, percent float64) float64 {
+	if positionQty <= 0 || percent <= 0 || percent >= 100 {
+		return 0
+	}
+	return positionQty * percent / 100
+}
+
+// protectiveOrderKind classifies an open order as "stop_loss" / "take_profit" protecting the given
+// position side ("LONG"/"SHORT"), or "" if it's not a trigger order of that position
+func protectiveOrderKind(order OpenOrder, positionSide string) string {
+	if order.StopPrice <= 0 {
+		return ""
+	}
+
+	switch strings.ToUpper(order.PositionSide) {
+	case "LONG", "SHORT":
+		if !strings.EqualFold(order.PositionSide, positionSide) {
+			return ""
+		}
+	default:
+		// One-way mode: protective orders sit on the opposite side of the position
+		closeSide := "SELL"
+		if positionSide == "SHORT" {
+			closeSide = "BUY"
+		}
+		if !strings.EqualFold(order.Side, closeSide) {
+			return ""
+		}
+	}
+
+	orderType := strings.ToUpper(strings.ReplaceAll(order.Type, "_", ""))
+	switch {
+	case strings.Contains(orderType, "TAKEPROFIT"):
+		return "take_profit"
+	case strings.Contains(orderType, "STOP"):
+		return "stop_loss"
+	}
+	return ""
+}
+
+// openPositionSize returns quantity and entry price of an open position,
+// preferring the local position record and falling back to the exchange
+func (at *AutoTrader) openPositionSize(symbol, side string) (float64, float64) {
+	if at.store != nil {
+		if openPos, err := at.store.Position().GetOpenPositionBySymbol(at.id, at.normalizeSymbol(symbol), strings.ToUpper(side)); err == nil && openPos != nil && openPos.Quantity > 0 {
+			return openPos.Quantity, openPos.EntryPrice
+		}
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return 0, 0
+	}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			qty, _ := pos["positionAmt"].(float64)
+			if qty < 0 {
+				qty = -qty
+			}
+			entryPrice, _ := pos["entryPrice"].(float64)
+			return qty, entryPrice
+		}
+	}
+	return 0, 0
+}
+
+// executeReduceWithRecord closes close_percentage of an open position (side "long"/"short").
+// The rest of the position stays open with its entry price; stop-loss/take-profit orders
+// cancelled by the exchange close are re-placed for the remaining quantity.
+func (at *AutoTrader) executeReduceWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction, side string) error {
+	at.log().Infof("  ✂️ Reduce %s: %s by %.1f%%", side, decision.Symbol, decision.ClosePercentage)
+
+	if decision.ClosePercentage <= 0 || decision.ClosePercentage >= 100 {
+		return fmt.Errorf("close_percentage must be between 0 and 100 (exclusive), got %.2f", decision.ClosePercentage)
+	}
+
+	marketData, err := market.Get(decision.Symbol)
+	if err != nil {
+		return err
+	}
+	actionRecord.Price = marketData.CurrentPrice
+
+	positionQty, entryPrice := at.openPositionSize(decision.Symbol, side)
+	if positionQty <= 0 {
+		return fmt.Errorf("no open %s position for %s", side, decision.Symbol)
+	}
+	quantity := reduceQuantity(positionQty, decision.ClosePercentage)
+	if formatted, err := at.trader.FormatQuantity(decision.Symbol, quantity); err == nil {
+		if rounded, err := strconv.ParseFloat(formatted, 64); err == nil {
+			quantity = rounded
+		}
+	}
+	if quantity <= 0 {
+		return fmt.Errorf("reduce quantity for %s rounds to zero (position %.8f, %.1f%%)", decision.Symbol, positionQty, decision.ClosePercentage)
+	}
+	if quantity >= positionQty {
+		return fmt.Errorf("reduce quantity %.8f would close the whole %s position, use close_%s instead", quantity, decision.Symbol, side)
+	}
+	actionRecord.Quantity = quantity
+
+	// Exchanges cancel all symbol orders when closing, remember the protective ones first
+	positionSide := strings.ToUpper(side)
+	protective, err := at.trader.GetOpenOrders(decision.Symbol)
+	if err != nil {
+		at.log().Infof("  ⚠ Failed to query open orders before reduce: %v", err)
+	}
+
+	var order map[string]interface{}
+	if side == "long" {
+		order, err = at.trader.CloseLong(decision.Symbol, quantity)
+	} else {
+		order, err = at.trader.CloseShort(decision.Symbol, quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	if orderID, ok := order["orderId"].(int64); ok {
+		actionRecord.OrderID = orderID
+	}
+
+	// Closing trade goes through the position builder, which books a partial close
+	// (realized PnL for the closed part, remaining quantity and entry price unchanged)
+	at.recordAndConfirmOrder(order, decision.Symbol, "close_"+side, quantity, marketData.CurrentPrice, 0, entryPrice)
+
+	at.restoreProtectiveOrders(decision.Symbol, positionSide, protective, positionQty, positionQty-quantity)
+
+	at.log().Infof("  ✓ Position reduced: %s %s %.8f → %.8f", decision.Symbol, side, positionQty, positionQty-quantity)
+	return nil
+}
+
+// restoreProtectiveOrders re-places stop-loss/take-profit orders of a position after a partial close,
+// scaled to the remaining quantity. Skipped if the exchange kept the original orders.
+func (at *AutoTrader) restoreProtectiveOrders(symbol, positionSide string, before []OpenOrder, positionQty, remainingQty float64) {
+	var stops, takeProfits []OpenOrder
+	for _, o := range before {
+		switch protectiveOrderKind(o, positionSide) {
+		case "stop_loss":
+			stops = append(stops, o)
+		case "take_profit":
+			takeProfits = append(takeProfits, o)
+		}
+	}
+	if len(stops) == 0 && len(takeProfits) == 0 {
+		return
+	}
+
+	if after, err := at.trader.GetOpenOrders(symbol); err == nil {
+		for _, o := range after {
+			if protectiveOrderKind(o, positionSide) != "" {
+				return
+			}
+		}
+	}
+
+	// Orders without quantity (close-position triggers) cover the whole remaining position
+	scaled := func(o OpenOrder) float64 {
+		if o.Quantity <= 0 || positionQty <= 0 {
+			return remainingQty
+		}
+		return o.Quantity * remainingQty / positionQty
+	}
+
+	for _, o := range stops {
+		if _, err := at.placeStopLoss(symbol, positionSide, scaled(o), o.StopPrice); err != nil {
+			at.log().Infof("  ⚠ Failed to restore stop loss @ %.4f: %v", o.StopPrice, err)
+			continue
+		}
+		at.log().Infof("  🛡️ Stop loss restored for remaining position @ %.4f", o.StopPrice)
+	}
+	for _, o := range takeProfits {
+		if _, err := at.placeTakeProfit(symbol, positionSide, scaled(o), o.StopPrice); err != nil {
+			at.log().Infof("  ⚠ Failed to restore take profit @ %.4f: %v", o.StopPrice, err)
+			continue
+		}
+		at.log().Infof("  🎯 Take profit restored for remaining position @ %.4f", o.StopPrice)
+	}
+}
@@ -0,0 +1,53 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReduceQuantity(t *testing.T) {
+	tests := []struct {
+		name        string
+		positionQty float64
+		percent     float64
+		want        float64
+	}{
+		{name: "half", positionQty: 2, percent: 50, want: 1},
+		{name: "quarter", positionQty: 0.8, percent: 25, want: 0.2},
+		{name: "full close rejected", positionQty: 1, percent: 100, want: 0},
+		{name: "zero percent", positionQty: 1, percent: 0, want: 0},
+		{name: "no position", positionQty: 0, percent: 50, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reduceQuantity(tt.positionQty, tt.percent); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("reduceQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectiveOrderKind(t *testing.T) {
+	tests := []struct {
+		name         string
+		order        OpenOrder
+		positionSide string
+		want         string
+	}{
+		{name: "hedge mode stop", order: OpenOrder{Side: "SELL", PositionSide: "LONG", Type: "STOP_MARKET", StopPrice: 90}, positionSide: "LONG", want: "stop_loss"},
+		{name: "hedge mode take profit", order: OpenOrder{Side: "BUY", PositionSide: "SHORT", Type: "TAKE_PROFIT_MARKET", StopPrice: 80}, positionSide: "SHORT", want: "take_profit"},
+		{name: "other side", order: OpenOrder{Side: "BUY", PositionSide: "SHORT", Type: "STOP_MARKET", StopPrice: 110}, positionSide: "LONG", want: ""},
+		{name: "one-way take profit", order: OpenOrder{Side: "Sell", Type: "TakeProfit", StopPrice: 120}, positionSide: "LONG", want: "take_profit"},
+		{name: "one-way stop wrong side", order: OpenOrder{Side: "Sell", Type: "StopLoss", StopPrice: 120}, positionSide: "SHORT", want: ""},
+		{name: "plain limit", order: OpenOrder{Side: "SELL", PositionSide: "LONG", Type: "LIMIT", Price: 100}, positionSide: "LONG", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protectiveOrderKind(tt.order, tt.positionSide); got != tt.want {
+				t.Errorf("protectiveOrderKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
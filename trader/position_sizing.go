@@ -0,0 +1,202 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/store"
+)
+
+// Position sizing models (see store.RiskControlConfig.SizingModel)
+//
+//   fixed_usd (default):  size = AI's position_size_usd
+//   fixed_fraction:       size = equity × f                               (f = SizingEquityFraction, default 0.1)
+//   volatility_target:    size = equity × target / (ATR / price)          (target = SizingVolatilityTarget, default 0.01)
+//                         i.e. a 1×ATR move changes equity by `target`
+//   kelly:                k = W − (1 − W) / R                             (W = win rate, R = avg win / avg loss)
+//                         size = equity × min(k × scale, maxFraction)     (scale default 0.5, maxFraction default 0.25)
+//                         k ≤ 0 (no edge) → size 0 (trade rejected by min position size check)
+//
+// The model size replaces the AI's size; position value ratio, margin and min size checks still apply afterwards.
+// If the model lacks inputs (no ATR, < kellyMinTrades closed trades) the AI's size is kept.
+//
+// Volatility scaling (RiskControlConfig.VolatilityScaling) then multiplies the size by
+// reference ATR% / symbol ATR%, clamped to [min, max] multiplier, so every position carries
+// roughly the same volatility risk. It's skipped for volatility_target, which already sizes by ATR.
+
+const (
+	defaultSizingEquityFraction   = 0.1
+	defaultSizingVolatilityTarget = 0.01
+	defaultSizingKellyScale       = 0.5
+	defaultSizingKellyMaxFraction = 0.25
+	kellyMinTrades                = 20 // Minimum closed trades before Kelly sizing is trusted
+
+	defaultVolatilityReferenceATRPct = 2.0
+	defaultVolatilityTimeframe       = "1h"
+	defaultVolatilityMinMultiplier   = 0.25
+	defaultVolatilityMaxMultiplier   = 2.0
+	volatilityATRPeriod              = 14
+)
+
+// calculateModelPositionSize calculates position size (USD) using the configured sizing model
+// Returns ok=false when the AI's requested size should be kept
+func calculateModelPositionSize(rc store.RiskControlConfig, equity, price, atr float64, stats *store.TraderStats) (size float64, detail string, ok bool) {
+	if equity <= 0 {
+		return 0, "", false
+	}
+
+	switch rc.SizingModel {
+	case store.SizingModelFixedFraction:
+		fraction := rc.SizingEquityFraction
+		if fraction <= 0 {
+			fraction = defaultSizingEquityFraction
+		}
+		return equity * fraction, fmt.Sprintf("equity %.2f × %.3f", equity, fraction), true
+
+	case store.SizingModelVolatilityTarget:
+		if atr <= 0 || price <= 0 {
+			return 0, "", false
+		}
+		target := rc.SizingVolatilityTarget
+		if target <= 0 {
+			target = defaultSizingVolatilityTarget
+		}
+		atrPct := atr / price
+		return equity * target / atrPct,
+			fmt.Sprintf("equity %.2f × target %.3f / ATR %.2f%%", equity, target, atrPct*100), true
+
+	case store.SizingModelKelly:
+		if stats == nil || stats.TotalTrades < kellyMinTrades || stats.AvgLoss <= 0 || stats.AvgWin <= 0 {
+			return 0, "", false
+		}
+		winRate := stats.WinRate / 100
+		payoff := stats.AvgWin / stats.AvgLoss
+		kelly := winRate - (1-winRate)/payoff
+		if kelly <= 0 {
+			return 0, fmt.Sprintf("no edge (win rate %.1f%%, payoff %.2f, kelly %.3f)", stats.WinRate, payoff, kelly), true
+		}
+
+		scale := rc.SizingKellyScale
+		if scale <= 0 {
+			scale = defaultSizingKellyScale
+		}
+		maxFraction := rc.SizingKellyMaxFraction
+		if maxFraction <= 0 {
+			maxFraction = defaultSizingKellyMaxFraction
+		}
+		fraction := kelly * scale
+		if fraction > maxFraction {
+			fraction = maxFraction
+		}
+		return equity * fraction,
+			fmt.Sprintf("kelly %.3f × %.2f (cap %.2f) = %.3f of equity %.2f", kelly, scale, maxFraction, fraction, equity), true
+	}
+
+	// fixed_usd or unknown model: keep AI size
+	return 0, "", false
+}
+
+// applySizingModel overrides the AI's position size according to the strategy's sizing model (CODE ENFORCED)
+func (at *AutoTrader) applySizingModel(decision *kernel.Decision, equity float64, marketData *market.Data) {
+	if at.config.StrategyConfig == nil {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if rc.SizingModel == "" || rc.SizingModel == store.SizingModelFixedUSD {
+		return
+	}
+
+	var atr float64
+	if marketData != nil {
+		if marketData.LongerTermContext != nil && marketData.LongerTermContext.ATR14 > 0 {
+			atr = marketData.LongerTermContext.ATR14
+		} else if marketData.IntradaySeries != nil {
+			atr = marketData.IntradaySeries.ATR14
+		}
+	}
+
+	var stats *store.TraderStats
+	if rc.SizingModel == store.SizingModelKelly && at.store != nil {
+		var err error
+		stats, err = at.store.Position().GetFullStats(at.id)
+		if err != nil {
+			at.log().Infof("  ⚠️ [SIZING] Failed to get trade stats for Kelly sizing: %v", err)
+		}
+	}
+
+	price := 0.0
+	if marketData != nil {
+		price = marketData.CurrentPrice
+	}
+	size, detail, ok := calculateModelPositionSize(rc, equity, price, atr, stats)
+	if !ok {
+		at.log().Infof("  📏 [SIZING] %s: insufficient data, keeping AI size %.2f USDT", rc.SizingModel, decision.PositionSizeUSD)
+		return
+	}
+
+	at.log().Infof("  📏 [SIZING] %s: %.2f → %.2f USDT (%s)", rc.SizingModel, decision.PositionSizeUSD, size, detail)
+	decision.PositionSizeUSD = size
+}
+
+// volatilityScaleMultiplier returns reference ATR% / symbol ATR% clamped to the configured bounds
+func volatilityScaleMultiplier(rc store.RiskControlConfig, atr, price float64) (multiplier, atrPct float64, ok bool) {
+	if atr <= 0 || price <= 0 {
+		return 0, 0, false
+	}
+	reference := rc.VolatilityReferenceATRPct
+	if reference <= 0 {
+		reference = defaultVolatilityReferenceATRPct
+	}
+	minMultiplier := rc.VolatilityMinMultiplier
+	if minMultiplier <= 0 {
+		minMultiplier = defaultVolatilityMinMultiplier
+	}
+	maxMultiplier := rc.VolatilityMaxMultiplier
+	if maxMultiplier <= 0 {
+		maxMultiplier = defaultVolatilityMaxMultiplier
+	}
+	if maxMultiplier < minMultiplier {
+		maxMultiplier = minMultiplier
+	}
+
+	atrPct = atr / price * 100
+	multiplier = math.Min(math.Max(reference/atrPct, minMultiplier), maxMultiplier)
+	return multiplier, atrPct, true
+}
+
+// applyVolatilityScaling scales the position size inversely to the symbol's volatility (CODE ENFORCED)
+// The ATR and multiplier used are recorded on the action record
+func (at *AutoTrader) applyVolatilityScaling(decision *kernel.Decision, actionRecord *store.DecisionAction) {
+	if at.config.StrategyConfig == nil || !at.config.StrategyConfig.RiskControl.VolatilityScaling {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	if rc.SizingModel == store.SizingModelVolatilityTarget {
+		return
+	}
+
+	timeframe := rc.VolatilityTimeframe
+	if timeframe == "" {
+		timeframe = defaultVolatilityTimeframe
+	}
+	atr, price, err := market.GetATR(decision.Symbol, timeframe, volatilityATRPeriod)
+	if err != nil {
+		at.log().Infof("  ⚠️ [VOL SCALING] %s: failed to get %s ATR, keeping size %.2f USDT: %v", decision.Symbol, timeframe, decision.PositionSizeUSD, err)
+		return
+	}
+
+	multiplier, atrPct, ok := volatilityScaleMultiplier(rc, atr, price)
+	if !ok {
+		return
+	}
+	scaled := decision.PositionSizeUSD * multiplier
+	at.log().Infof("  📏 [VOL SCALING] %s: %s ATR %.4f (%.2f%%) → ×%.2f, %.2f → %.2f USDT",
+		decision.Symbol, timeframe, atr, atrPct, multiplier, decision.PositionSizeUSD, scaled)
+
+	decision.PositionSizeUSD = scaled
+	if actionRecord != nil {
+		actionRecord.ATR = atr
+		actionRecord.SizeMultiplier = multiplier
+	}
+}
@@ -0,0 +1,77 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+)
+
+func TestCalculateModelPositionSize(t *testing.T) {
+	goodStats := &store.TraderStats{TotalTrades: 40, WinRate: 50, AvgWin: 200, AvgLoss: 100}
+	badStats := &store.TraderStats{TotalTrades: 40, WinRate: 30, AvgWin: 100, AvgLoss: 100}
+
+	tests := []struct {
+		name   string
+		rc     store.RiskControlConfig
+		atr    float64
+		stats  *store.TraderStats
+		want   float64
+		wantOK bool
+	}{
+		{name: "fixed_usd keeps AI size", rc: store.RiskControlConfig{SizingModel: store.SizingModelFixedUSD}},
+		{name: "empty model keeps AI size", rc: store.RiskControlConfig{}},
+		{name: "fixed fraction default", rc: store.RiskControlConfig{SizingModel: store.SizingModelFixedFraction}, want: 1000, wantOK: true},
+		{name: "fixed fraction custom", rc: store.RiskControlConfig{SizingModel: store.SizingModelFixedFraction, SizingEquityFraction: 0.25}, want: 2500, wantOK: true},
+		// ATR 2% of price, target 1% → size = 10000 × 0.01 / 0.02 = 5000
+		{name: "volatility target", rc: store.RiskControlConfig{SizingModel: store.SizingModelVolatilityTarget}, atr: 2, want: 5000, wantOK: true},
+		{name: "volatility target without ATR", rc: store.RiskControlConfig{SizingModel: store.SizingModelVolatilityTarget}},
+		// kelly = 0.5 - 0.5/2 = 0.25, half Kelly = 0.125 → 1250
+		{name: "kelly half", rc: store.RiskControlConfig{SizingModel: store.SizingModelKelly}, stats: goodStats, want: 1250, wantOK: true},
+		{name: "kelly capped", rc: store.RiskControlConfig{SizingModel: store.SizingModelKelly, SizingKellyScale: 1, SizingKellyMaxFraction: 0.1}, stats: goodStats, want: 1000, wantOK: true},
+		{name: "kelly no edge", rc: store.RiskControlConfig{SizingModel: store.SizingModelKelly}, stats: badStats, want: 0, wantOK: true},
+		{name: "kelly insufficient history", rc: store.RiskControlConfig{SizingModel: store.SizingModelKelly}, stats: &store.TraderStats{TotalTrades: 5, WinRate: 60, AvgWin: 1, AvgLoss: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, ok := calculateModelPositionSize(tt.rc, 10000, 100, tt.atr, tt.stats)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("size = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVolatilityScaleMultiplier(t *testing.T) {
+	tests := []struct {
+		name   string
+		rc     store.RiskControlConfig
+		atr    float64
+		want   float64
+		wantOK bool
+	}{
+		// reference 2% / ATR 1% → ×2
+		{name: "calm coin sized up", atr: 1, want: 2, wantOK: true},
+		{name: "reference volatility unchanged", atr: 2, want: 1, wantOK: true},
+		{name: "volatile coin sized down", atr: 4, want: 0.5, wantOK: true},
+		{name: "clamped to min", atr: 20, want: 0.25, wantOK: true},
+		{name: "clamped to custom max", rc: store.RiskControlConfig{VolatilityMaxMultiplier: 1.5}, atr: 0.5, want: 1.5, wantOK: true},
+		{name: "custom reference", rc: store.RiskControlConfig{VolatilityReferenceATRPct: 3}, atr: 6, want: 0.5, wantOK: true},
+		{name: "no ATR", atr: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, ok := volatilityScaleMultiplier(tt.rc, tt.atr, 100)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("multiplier = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,30 +14,31 @@ import (
 // 2. Get current real positions from exchange
 // 3. Create a "snapshot" record for each real position
 func CreatePositionSnapshot(traderID, exchangeID, exchangeType string, trader Trader, st *store.Store) error {
-	logger.Infof("📸 Creating position snapshot for trader %s (%s)...", traderID, exchangeType)
+	log := logger.TraderEntry(traderID, nil)
+	log.Infof("📸 Creating position snapshot for trader %s (%s)...", traderID, exchangeType)
 
 	positionStore := st.Position()
 
 	// Step 1: Delete all OPEN positions
-	logger.Infof("🗑️  Deleting all OPEN positions from database...")
+	log.Infof("🗑️  Deleting all OPEN positions from database...")
 	if err := positionStore.DeleteAllOpenPositions(traderID); err != nil {
 		return fmt.Errorf("failed to delete open positions: %w", err)
 	}
-	logger.Infof("✅ Deleted all OPEN positions")
+	log.Infof("✅ Deleted all OPEN positions")
 
 	// Step 2: Get current positions from exchange
-	logger.Infof("📡 Fetching current positions from exchange...")
+	log.Infof("📡 Fetching current positions from exchange...")
 	positions, err := trader.GetPositions()
 	if err != nil {
 		return fmt.Errorf("failed to get positions from exchange: %w", err)
 	}
 
 	if len(positions) == 0 {
-		logger.Infof("✅ No open positions on exchange, snapshot complete")
+		log.Infof("✅ No open positions on exchange, snapshot complete")
 		return nil
 	}
 
-	logger.Infof("📥 Found %d positions on exchange", len(positions))
+	log.Infof("📥 Found %d positions on exchange", len(positions))
 
 	// Step 3: Create snapshot record for each position
 	nowMs := time.Now().UnixMilli()
@@ -46,7 +47,7 @@ func CreatePositionSnapshot(traderID, exchangeID, exchangeType string, trader Tr
 	for _, posMap := range positions {
 		// Parse position data
 		rawSymbol, _ := posMap["symbol"].(string)
-		symbol := market.Normalize(rawSymbol)
+		symbol := market.NormalizeForExchange(rawSymbol, exchangeType, "")
 		sideStr, _ := posMap["side"].(string)
 		positionAmt, _ := posMap["positionAmt"].(float64)
 		entryPrice, _ := posMap["entryPrice"].(float64)
@@ -89,15 +90,15 @@ func CreatePositionSnapshot(traderID, exchangeID, exchangeType string, trader Tr
 		}
 
 		if err := positionStore.CreateOpenPosition(snapshotPosition); err != nil {
-			logger.Infof("  ⚠️ Failed to create snapshot position for %s %s: %v", symbol, side, err)
+			log.Infof("  ⚠️ Failed to create snapshot position for %s %s: %v", symbol, side, err)
 			continue
 		}
 
-		logger.Infof("  ✅ Created snapshot: %s %s %.6f @ %.2f (leverage: %dx)",
+		log.Infof("  ✅ Created snapshot: %s %s %.6f @ %.2f (leverage: %dx)",
 			symbol, side, positionAmt, entryPrice, int(leverage))
 		createdCount++
 	}
 
-	logger.Infof("✅ Position snapshot complete: %d positions created", createdCount)
+	log.Infof("✅ Position snapshot complete: %d positions created", createdCount)
 	return nil
 }
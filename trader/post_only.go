@@ -0,0 +1,239 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nofx/kernel"
+	"nofx/logger"
+)
+
+// PostOnlyOpener is implemented by exchanges that can open with a post-only (maker) limit order.
+// The exchange rejects or expires the order instead of letting it take liquidity when the price
+// would cross the book, so the entry never pays taker fees.
+type PostOnlyOpener interface {
+	OpenLongPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error)
+	OpenShortPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error)
+}
+
+// BestBidAskProvider is implemented by exchanges that can quote the top of the order book
+type BestBidAskProvider interface {
+	GetBestBidAsk(symbol string) (bid, ask float64, err error)
+}
+
+const (
+	// defaultPostOnlyTimeout how long a resting post-only entry may wait for a fill (risk_control.post_only_timeout_sec)
+	defaultPostOnlyTimeout = 60 * time.Second
+	// makerEntryPollInterval how often a resting post-only entry's status is checked
+	makerEntryPollInterval = 5 * time.Second
+)
+
+// makerEntry a post-only entry resting on the book; exits are placed once it fills
+type makerEntry struct {
+	decision     kernel.Decision
+	positionSide string // "LONG" or "SHORT"
+	orderID      string
+	quantity     float64
+	price        float64
+	deadline     time.Time
+	traceCtx     context.Context // Cycle that placed the entry, its correlation ID tags the watcher's log lines
+}
+
+// log returns the trader's logger tagged with the correlation ID of the cycle that placed the entry
+func (e *makerEntry) log(traderID string) *logger.Entry {
+	return logger.TraderEntry(traderID, e.traceCtx)
+}
+
+// postOnlyEntriesEnabled reports whether risk_control.post_only_entries allows post-only entries
+func (at *AutoTrader) postOnlyEntriesEnabled() bool {
+	return at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.PostOnlyEntries
+}
+
+// postOnlyTimeout returns risk_control.post_only_timeout_sec (default 60s)
+func (at *AutoTrader) postOnlyTimeout() time.Duration {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.PostOnlyTimeoutSec > 0 {
+		return time.Duration(at.config.StrategyConfig.RiskControl.PostOnlyTimeoutSec) * time.Second
+	}
+	return defaultPostOnlyTimeout
+}
+
+// makerEntryPrice returns the passive limit price of a post-only entry: the best bid for a long,
+// the best ask for a short. Without a book quote the last price is used and the exchange rejects
+// the order if it would cross.
+func (at *AutoTrader) makerEntryPrice(symbol, positionSide string) (float64, error) {
+	if provider, ok := at.trader.(BestBidAskProvider); ok {
+		bid, ask, err := provider.GetBestBidAsk(symbol)
+		if err == nil && bid > 0 && ask > 0 {
+			if positionSide == "SHORT" {
+				return ask, nil
+			}
+			return bid, nil
+		}
+		at.log().Infof("  ⚠ Failed to get best bid/ask for %s, pricing post-only entry at last price: %v", symbol, err)
+	}
+	return at.trader.GetMarketPrice(symbol)
+}
+
+// openPostOnly places a post-only limit entry at the maker side of the book
+func (at *AutoTrader) openPostOnly(opener PostOnlyOpener, decision *kernel.Decision, positionSide string, quantity float64) (map[string]interface{}, error) {
+	price, err := at.makerEntryPrice(decision.Symbol, positionSide)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price post-only entry: %w", err)
+	}
+	open := opener.OpenLongPostOnly
+	if positionSide == "SHORT" {
+		open = opener.OpenShortPostOnly
+	}
+	order, err := open(decision.Symbol, quantity, decision.Leverage, price)
+	if err != nil {
+		return nil, err
+	}
+	order["postOnly"] = true
+	order["price"] = price
+	at.log().Infof("  🧾 Post-only %s entry resting at %.6f (order %v)", positionSide, price, order["orderId"])
+	return order, nil
+}
+
+// isPostOnlyOrder reports whether order is a resting post-only entry placed by openPostOnly
+func isPostOnlyOrder(order map[string]interface{}) bool {
+	postOnly, _ := order["postOnly"].(bool)
+	return postOnly
+}
+
+// hasMakerEntry reports whether symbol has a post-only entry waiting for a fill
+func (at *AutoTrader) hasMakerEntry(symbol string) bool {
+	at.makerEntriesMu.Lock()
+	defer at.makerEntriesMu.Unlock()
+	for _, entry := range at.makerEntries {
+		if entry.decision.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// trackMakerEntry watches a resting post-only entry until it fills or its window expires.
+// Fills are recorded by order sync; the watcher places the stop loss / take profit for the
+// filled quantity and cancels whatever is still unfilled after risk_control.post_only_timeout_sec.
+func (at *AutoTrader) trackMakerEntry(decision *kernel.Decision, positionSide string, quantity float64, order map[string]interface{}) {
+	entry := &makerEntry{
+		decision:     *decision,
+		positionSide: positionSide,
+		orderID:      orderIDString(order),
+		quantity:     quantity,
+		deadline:     time.Now().Add(at.postOnlyTimeout()),
+		traceCtx:     at.traceContext(),
+	}
+	entry.price, _ = order["price"].(float64)
+	if entry.orderID == "" || entry.orderID == "0" {
+		at.log().Infof("  ⚠ Post-only entry for %s has no order ID, can't track it", decision.Symbol)
+		return
+	}
+
+	at.makerEntriesMu.Lock()
+	if at.makerEntries == nil {
+		at.makerEntries = make(map[string]*makerEntry)
+	}
+	at.makerEntries[entry.orderID] = entry
+	at.makerEntriesMu.Unlock()
+
+	at.monitorWg.Add(1)
+	go func() {
+		defer at.monitorWg.Done()
+		defer func() {
+			at.makerEntriesMu.Lock()
+			delete(at.makerEntries, entry.orderID)
+			at.makerEntriesMu.Unlock()
+		}()
+
+		ticker := time.NewTicker(makerEntryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if at.checkMakerEntry(entry, time.Now()) {
+					return
+				}
+			case <-at.stopMonitorCh:
+				// Nothing watches the order once the trader stops, so a later fill would be unprotected
+				entry.log(at.id).Infof("⏹ [%s] Trader stopping, cancelling post-only entry %s (%s)", at.name, entry.orderID, entry.decision.Symbol)
+				at.cancelMakerEntry(entry)
+				return
+			}
+		}
+	}()
+}
+
+// checkMakerEntry polls one resting entry; it returns true once the entry is settled (filled,
+// rejected or cancelled at the deadline)
+func (at *AutoTrader) checkMakerEntry(entry *makerEntry, now time.Time) bool {
+	symbol := entry.decision.Symbol
+	status, err := at.trader.GetOrderStatus(symbol, entry.orderID)
+	if err != nil {
+		entry.log(at.id).Infof("  ⚠ Failed to check post-only entry %s (%s): %v", entry.orderID, symbol, err)
+		if now.Before(entry.deadline) {
+			return false
+		}
+		status = map[string]interface{}{}
+	}
+	state, _ := status["status"].(string)
+
+	switch {
+	case state == "FILLED":
+		at.onMakerEntryFilled(entry, orderExecutedQty(status, entry.quantity))
+		return true
+	case isFinalOrderStatus(state):
+		// Post-only orders that would have crossed are rejected/expired by the exchange
+		if filled := orderExecutedQty(status, 0); filled > 0 {
+			at.onMakerEntryFilled(entry, filled)
+		} else {
+			entry.log(at.id).Infof("  ℹ Post-only %s entry %s for %s ended %s without a fill", entry.positionSide, entry.orderID, symbol, state)
+		}
+		return true
+	case now.Before(entry.deadline):
+		return false
+	}
+
+	if !at.cancelMakerEntry(entry) {
+		return false // Retry on the next tick, the order may still fill
+	}
+	entry.log(at.id).Infof("  ⏱ Post-only %s entry %s for %s unfilled after %v, cancelled", entry.positionSide, entry.orderID, symbol, at.postOnlyTimeout())
+	return true
+}
+
+// cancelMakerEntry cancels a resting entry and places the exits of whatever filled before the
+// cancel, since a partial fill is a real position. Returns false if the cancel failed.
+func (at *AutoTrader) cancelMakerEntry(entry *makerEntry) bool {
+	symbol := entry.decision.Symbol
+	if err := at.trader.CancelOrder(symbol, entry.orderID); err != nil {
+		entry.log(at.id).Infof("  ⚠ Failed to cancel unfilled post-only entry %s (%s): %v", entry.orderID, symbol, err)
+		return false
+	}
+	if final, err := at.trader.GetOrderStatus(symbol, entry.orderID); err == nil {
+		if filled := orderExecutedQty(final, 0); filled > 0 {
+			at.onMakerEntryFilled(entry, filled)
+		}
+	}
+	return true
+}
+
+// onMakerEntryFilled places the exits of a filled post-only entry and syncs the fill
+func (at *AutoTrader) onMakerEntryFilled(entry *makerEntry, quantity float64) {
+	entry.log(at.id).Infof("  ✅ Post-only %s entry %s for %s filled: %.6f @ %.6f", entry.positionSide, entry.orderID, entry.decision.Symbol, quantity, entry.price)
+
+	if _, err := at.placeStopLoss(entry.decision.Symbol, entry.positionSide, quantity, entry.decision.StopLoss); err != nil {
+		entry.log(at.id).Infof("  ⚠ Failed to set stop loss: %v", err)
+	}
+	at.placeTakeProfits(&entry.decision, entry.positionSide, quantity)
+
+	at.requestOrderSync()
+}
+
+// orderExecutedQty reads the executed quantity of a GetOrderStatus result (fallback if missing)
+func orderExecutedQty(status map[string]interface{}, fallback float64) float64 {
+	if qty, ok := status["executedQty"].(float64); ok && qty > 0 {
+		return qty
+	}
+	return fallback
+}
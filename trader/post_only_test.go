@@ -0,0 +1,178 @@
+package trader
+
+import (
+	"testing"
+	"time"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+// fakeMakerTrader reports a fixed order status and records exits/cancels
+type fakeMakerTrader struct {
+	Trader
+	status    map[string]interface{}
+	cancelled []string
+	stopQty   float64
+	takeQty   float64
+	opened    []string // "market" / "post_only" entries
+}
+
+func (f *fakeMakerTrader) GetOrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	return f.status, nil
+}
+
+func (f *fakeMakerTrader) CancelOrder(symbol, orderID string) error {
+	f.cancelled = append(f.cancelled, orderID)
+	return nil
+}
+
+func (f *fakeMakerTrader) SetStopLoss(symbol, positionSide string, quantity, stopPrice float64) error {
+	f.stopQty = quantity
+	return nil
+}
+
+func (f *fakeMakerTrader) SetTakeProfit(symbol, positionSide string, quantity, takeProfitPrice float64) error {
+	f.takeQty = quantity
+	return nil
+}
+
+func (f *fakeMakerTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	return nil, nil
+}
+
+func (f *fakeMakerTrader) GetMarketPrice(symbol string) (float64, error) {
+	return 100, nil
+}
+
+func (f *fakeMakerTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	f.opened = append(f.opened, "market")
+	return map[string]interface{}{"orderId": "1"}, nil
+}
+
+func (f *fakeMakerTrader) OpenLongPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	f.opened = append(f.opened, "post_only")
+	return map[string]interface{}{"orderId": "42"}, nil
+}
+
+func (f *fakeMakerTrader) OpenShortPostOnly(symbol string, quantity float64, leverage int, price float64) (map[string]interface{}, error) {
+	f.opened = append(f.opened, "post_only")
+	return map[string]interface{}{"orderId": "42"}, nil
+}
+
+type fakeBookTrader struct {
+	fakeMakerTrader
+}
+
+func (f *fakeBookTrader) GetBestBidAsk(symbol string) (float64, float64, error) {
+	return 99.5, 100.5, nil
+}
+
+func newMakerEntry(deadline time.Time) *makerEntry {
+	return &makerEntry{
+		decision:     kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95, TakeProfit: 110},
+		positionSide: "LONG",
+		orderID:      "42",
+		quantity:     2,
+		deadline:     deadline,
+	}
+}
+
+func TestCheckMakerEntry(t *testing.T) {
+	now := time.Now()
+
+	t.Run("filled places exits", func(t *testing.T) {
+		ft := &fakeMakerTrader{status: map[string]interface{}{"status": "FILLED", "executedQty": 2.0}}
+		at := &AutoTrader{trader: ft}
+		if !at.checkMakerEntry(newMakerEntry(now.Add(time.Minute)), now) {
+			t.Fatal("filled entry should be settled")
+		}
+		if ft.stopQty != 2 || ft.takeQty != 2 {
+			t.Errorf("exits placed for sl=%v tp=%v, want 2", ft.stopQty, ft.takeQty)
+		}
+	})
+
+	t.Run("resting within window", func(t *testing.T) {
+		ft := &fakeMakerTrader{status: map[string]interface{}{"status": "NEW"}}
+		at := &AutoTrader{trader: ft}
+		if at.checkMakerEntry(newMakerEntry(now.Add(time.Minute)), now) || len(ft.cancelled) != 0 {
+			t.Error("resting entry within its window should be left alone")
+		}
+	})
+
+	t.Run("partial fill cancelled at deadline", func(t *testing.T) {
+		ft := &fakeMakerTrader{status: map[string]interface{}{"status": "PARTIALLY_FILLED", "executedQty": 0.5}}
+		at := &AutoTrader{trader: ft}
+		if !at.checkMakerEntry(newMakerEntry(now.Add(-time.Second)), now) {
+			t.Fatal("expired entry should be settled")
+		}
+		if len(ft.cancelled) != 1 || ft.cancelled[0] != "42" {
+			t.Errorf("cancelled = %v, want [42]", ft.cancelled)
+		}
+		if ft.stopQty != 0.5 {
+			t.Errorf("stop loss quantity = %v, want the partially filled 0.5", ft.stopQty)
+		}
+	})
+
+	t.Run("rejected for crossing", func(t *testing.T) {
+		ft := &fakeMakerTrader{status: map[string]interface{}{"status": "EXPIRED"}}
+		at := &AutoTrader{trader: ft}
+		if !at.checkMakerEntry(newMakerEntry(now.Add(time.Minute)), now) || ft.stopQty != 0 {
+			t.Error("expired post-only entry should settle without exits")
+		}
+	})
+}
+
+func TestMakerEntryPrice(t *testing.T) {
+	book := &AutoTrader{trader: &fakeBookTrader{}}
+	if got, _ := book.makerEntryPrice("BTCUSDT", "LONG"); got != 99.5 {
+		t.Errorf("long maker price = %v, want best bid 99.5", got)
+	}
+	if got, _ := book.makerEntryPrice("BTCUSDT", "SHORT"); got != 100.5 {
+		t.Errorf("short maker price = %v, want best ask 100.5", got)
+	}
+
+	noBook := &AutoTrader{trader: &fakeMakerTrader{}}
+	if got, _ := noBook.makerEntryPrice("BTCUSDT", "LONG"); got != 100 {
+		t.Errorf("maker price without book = %v, want last price 100", got)
+	}
+}
+
+func TestOpenPositionPostOnlyRequiresRiskControl(t *testing.T) {
+	decision := &kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5, PostOnly: true}
+	for _, enabled := range []bool{false, true} {
+		ft := &fakeMakerTrader{}
+		at := &AutoTrader{trader: ft, config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{}}}
+		at.config.StrategyConfig.RiskControl.PostOnlyEntries = enabled
+		if _, _, _, err := at.openPosition(decision, "LONG", 1); err != nil {
+			t.Fatalf("openPosition() error = %v", err)
+		}
+		want := "market"
+		if enabled {
+			want = "post_only"
+		}
+		if len(ft.opened) != 1 || ft.opened[0] != want {
+			t.Errorf("post_only_entries=%v: opened %v, want %s", enabled, ft.opened, want)
+		}
+	}
+}
+
+func TestTrackMakerEntryCancelledOnStop(t *testing.T) {
+	ft := &fakeMakerTrader{status: map[string]interface{}{"status": "CANCELED", "executedQty": 0.5}}
+	at := &AutoTrader{trader: ft, stopMonitorCh: make(chan struct{})}
+	decision := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95}
+	at.trackMakerEntry(decision, "LONG", 2, map[string]interface{}{"orderId": "42", "price": 100.0})
+
+	close(at.stopMonitorCh)
+	at.monitorWg.Wait()
+
+	if len(ft.cancelled) != 1 || ft.cancelled[0] != "42" {
+		t.Errorf("cancelled = %v, want the resting entry 42", ft.cancelled)
+	}
+	if ft.stopQty != 0.5 {
+		t.Errorf("stop loss quantity = %v, want the partially filled 0.5", ft.stopQty)
+	}
+	if at.hasMakerEntry("BTCUSDT") {
+		t.Error("entry still tracked after stop")
+	}
+}
@@ -0,0 +1,24 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/market"
+)
+
+// QuoteAssetConfigurable is implemented by exchange clients that margin USDT- and USDC-quoted
+// contracts separately, so balances and positions are read in the trader's quote asset
+type QuoteAssetConfigurable interface {
+	SetQuoteAsset(quote string)
+}
+
+// applyQuoteAsset hands a USDC (non-USDT) quote asset to an exchange client that settles per quote.
+// Clients that don't implement QuoteAssetConfigurable report a single (USD-valued) account.
+func applyQuoteAsset(t Trader, quote, traderName string, log *logger.Entry) {
+	if quote == "" || quote == market.QuoteUSDT {
+		return
+	}
+	if qc, ok := t.(QuoteAssetConfigurable); ok {
+		qc.SetQuoteAsset(quote)
+		log.Infof("💵 [%s] Trading %s-quoted contracts", traderName, quote)
+	}
+}
@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/kernel"
+	"nofx/market"
+	"sync"
+	"time"
+)
+
+const (
+	signalQueueSize            = 16
+	defaultSignalRatePerMinute = 6
+	signalRateWindow           = time.Minute
+)
+
+// Signal sources
+const (
+	SignalSourceWebhook = "webhook" // HMAC-signed webhook (e.g. TradingView relay)
+	SignalSourceAPI     = "api"     // Authenticated user request
+)
+
+// Signal submission errors
+var (
+	ErrSignalTraderNotRunning = errors.New("trader is not running")
+	ErrSignalRateLimited      = errors.New("signal rate limit exceeded")
+	ErrSignalQueueFull        = errors.New("signal queue is full")
+)
+
+// Signal out-of-band instruction from an external system (e.g. a TradingView alert),
+// handled by the trader's main loop between scan intervals
+type Signal struct {
+	Decision   *kernel.Decision // Decision to execute directly (nil = only run a cycle)
+	RunCycle   bool             // Run a full AI cycle now instead of waiting for the next tick
+	Source     string           // Where the signal came from (webhook / api)
+	ReceivedAt time.Time
+}
+
+// signalRateLimiter sliding-window limiter for incoming signals
+type signalRateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	times  []time.Time
+}
+
+func newSignalRateLimiter(limit int, window time.Duration) *signalRateLimiter {
+	if limit <= 0 {
+		limit = defaultSignalRatePerMinute
+	}
+	return &signalRateLimiter{limit: limit, window: window}
+}
+
+// Allow records a signal at now and reports whether it's within the limit
+func (l *signalRateLimiter) Allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	kept := l.times[:0]
+	for _, t := range l.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	l.times = kept
+
+	if len(l.times) >= l.limit {
+		return false
+	}
+	l.times = append(l.times, now)
+	return true
+}
+
+// ValidateSignal checks an incoming signal and normalizes its decision symbol
+func ValidateSignal(sig *Signal) error {
+	if sig.Decision == nil {
+		if !sig.RunCycle {
+			return fmt.Errorf("signal must contain a decision or run_cycle")
+		}
+		return nil
+	}
+
+	d := sig.Decision
+	if d.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	d.Symbol = market.Normalize(d.Symbol)
+
+	switch d.Action {
+	case "open_long", "open_short":
+		if d.Leverage <= 0 {
+			return fmt.Errorf("leverage must be greater than 0")
+		}
+		if d.PositionSizeUSD <= 0 {
+			return fmt.Errorf("position_size_usd must be greater than 0")
+		}
+		if d.StopLoss < 0 || d.TakeProfit < 0 {
+			return fmt.Errorf("stop_loss and take_profit cannot be negative")
+		}
+		if d.StopLoss > 0 && d.TakeProfit > 0 {
+			if d.Action == "open_long" && d.StopLoss >= d.TakeProfit {
+				return fmt.Errorf("for long positions, stop_loss must be below take_profit")
+			}
+			if d.Action == "open_short" && d.StopLoss <= d.TakeProfit {
+				return fmt.Errorf("for short positions, stop_loss must be above take_profit")
+			}
+		}
+	case "close_long", "close_short":
+	case "reduce_long", "reduce_short":
+		if d.ClosePercentage <= 0 || d.ClosePercentage >= 100 {
+			return fmt.Errorf("close_percentage must be between 0 and 100 (exclusive)")
+		}
+	default:
+		return fmt.Errorf("unsupported action for signals: %s", d.Action)
+	}
+
+	if d.Reasoning == "" {
+		d.Reasoning = "External signal"
+	}
+	return nil
+}
+
+// SubmitSignal validates, rate-limits and enqueues a signal for the main loop (non-blocking)
+func (at *AutoTrader) SubmitSignal(sig Signal) error {
+	at.isRunningMutex.RLock()
+	running := at.isRunning
+	at.isRunningMutex.RUnlock()
+	if !running {
+		return ErrSignalTraderNotRunning
+	}
+	if sig.Decision != nil && sig.Decision.Symbol != "" {
+		sig.Decision.Symbol = at.normalizeSymbol(sig.Decision.Symbol)
+	}
+	if err := ValidateSignal(&sig); err != nil {
+		return err
+	}
+	if !at.signalLimiter.Allow(time.Now()) {
+		return fmt.Errorf("%w (max %d per minute)", ErrSignalRateLimited, at.signalLimiter.limit)
+	}
+	if sig.ReceivedAt.IsZero() {
+		sig.ReceivedAt = time.Now()
+	}
+
+	select {
+	case at.signalCh <- sig:
+		at.log().Infof("📨 [%s] Signal queued from %s (run_cycle=%v, decision=%v)",
+			at.name, sig.Source, sig.RunCycle, sig.Decision != nil)
+		return nil
+	default:
+		return ErrSignalQueueFull
+	}
+}
+
+// handleSignal processes a queued signal on the main loop goroutine
+func (at *AutoTrader) handleSignal(sig Signal) {
+	if sig.Decision != nil {
+		at.log().Infof("📨 [%s] Executing %s signal: %s %s", at.name, sig.Source, sig.Decision.Action, sig.Decision.Symbol)
+		if err := at.ExecuteDecision(sig.Decision); err != nil {
+			at.log().Warnf("⚠️ [%s] Signal execution failed: %v", at.name, err)
+		}
+	}
+	if sig.RunCycle {
+		at.log().Infof("📨 [%s] Running cycle on %s signal", at.name, sig.Source)
+		if err := at.runCycle(); err != nil {
+			at.log().Infof("❌ Execution failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package trader
+
+import (
+	"testing"
+	"time"
+
+	"nofx/kernel"
+)
+
+func TestSignalRateLimiter(t *testing.T) {
+	limiter := newSignalRateLimiter(2, time.Minute)
+	now := time.Now()
+
+	if !limiter.Allow(now) || !limiter.Allow(now.Add(time.Second)) {
+		t.Fatal("first two signals should be allowed")
+	}
+	if limiter.Allow(now.Add(2 * time.Second)) {
+		t.Error("third signal within the window should be rejected")
+	}
+	if !limiter.Allow(now.Add(61 * time.Second)) {
+		t.Error("signal after the window should be allowed")
+	}
+}
+
+func TestValidateSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		sig     Signal
+		wantErr bool
+	}{
+		{name: "run cycle only", sig: Signal{RunCycle: true}},
+		{name: "empty signal", sig: Signal{}, wantErr: true},
+		{name: "valid open", sig: Signal{Decision: &kernel.Decision{Symbol: "BTC", Action: "open_long", Leverage: 5, PositionSizeUSD: 100, StopLoss: 90, TakeProfit: 110}}},
+		{name: "open without size", sig: Signal{Decision: &kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5}}, wantErr: true},
+		{name: "short with inverted levels", sig: Signal{Decision: &kernel.Decision{Symbol: "BTCUSDT", Action: "open_short", Leverage: 5, PositionSizeUSD: 100, StopLoss: 90, TakeProfit: 110}}, wantErr: true},
+		{name: "close", sig: Signal{Decision: &kernel.Decision{Symbol: "ETHUSDT", Action: "close_short"}}},
+		{name: "reduce", sig: Signal{Decision: &kernel.Decision{Symbol: "ETHUSDT", Action: "reduce_long", ClosePercentage: 50}}},
+		{name: "reduce without percentage", sig: Signal{Decision: &kernel.Decision{Symbol: "ETHUSDT", Action: "reduce_short"}}, wantErr: true},
+		{name: "hold not allowed", sig: Signal{Decision: &kernel.Decision{Symbol: "ETHUSDT", Action: "hold"}}, wantErr: true},
+		{name: "missing symbol", sig: Signal{Decision: &kernel.Decision{Action: "close_long"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSignal(&tt.sig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSignal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package trader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nofx/kernel"
+)
+
+// Sliced entry defaults (risk_control.sliced_entry_*)
+const (
+	defaultSlicedEntryCount    = 4
+	defaultSlicedEntryInterval = 2 * time.Second
+	maxSlicedEntryCount        = 20
+	// maxSlicedEntryInterval longest delay between child orders: the cycle (and manual opens) wait
+	// for the whole entry, and the part already opened moves with the market meanwhile
+	maxSlicedEntryInterval = 10 * time.Second
+	// defaultChildMinNotional smallest child order value (USDT) when the exchange doesn't report its minimum
+	defaultChildMinNotional = 10.0
+)
+
+// minNotionalReporter optional: exchanges reporting their minimum order value per symbol
+type minNotionalReporter interface {
+	GetMinNotional(symbol string) float64
+}
+
+// slicedEntrySettings returns the notional above which market opens are sliced (0 = disabled),
+// the number of child orders and the delay between them
+func (at *AutoTrader) slicedEntrySettings() (thresholdUSD float64, count int, interval time.Duration) {
+	if at.config.StrategyConfig == nil {
+		return 0, 0, 0
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	count = rc.SlicedEntryCount
+	if count <= 0 {
+		count = defaultSlicedEntryCount
+	}
+	interval = defaultSlicedEntryInterval
+	if rc.SlicedEntryIntervalMs > 0 {
+		interval = time.Duration(rc.SlicedEntryIntervalMs) * time.Millisecond
+	}
+	if interval > maxSlicedEntryInterval {
+		interval = maxSlicedEntryInterval
+	}
+	return rc.SlicedEntryThresholdUSD, count, interval
+}
+
+// entryChildCount returns how many child orders an entry of notional is split into: the configured
+// count (at most maxSlicedEntryCount), reduced until every child reaches minNotional
+func entryChildCount(notional, minNotional float64, count int) int {
+	if count > maxSlicedEntryCount {
+		count = maxSlicedEntryCount
+	}
+	if minNotional > 0 {
+		if fit := int(notional / minNotional); fit < count {
+			count = fit
+		}
+	}
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// childMinNotional returns the exchange's minimum order value for symbol
+func (at *AutoTrader) childMinNotional(symbol string) float64 {
+	if reporter, ok := at.trader.(minNotionalReporter); ok {
+		if min := reporter.GetMinNotional(symbol); min > 0 {
+			return min
+		}
+	}
+	return defaultChildMinNotional
+}
+
+// entrySlices returns the number of child orders a market open of quantity at price is split into
+// (1 = single order) and the delay between them. Post-only entries are never sliced.
+func (at *AutoTrader) entrySlices(decision *kernel.Decision, quantity, price float64) (int, time.Duration) {
+	threshold, count, interval := at.slicedEntrySettings()
+	notional := quantity * price
+	if threshold <= 0 || decision.PostOnly || quantity <= 0 || price <= 0 || notional <= threshold {
+		return 1, 0
+	}
+	return entryChildCount(notional, at.childMinNotional(decision.Symbol), count), interval
+}
+
+// isSlicedOrder reports whether an open result aggregates the child orders of a sliced entry
+// (each child is recorded as it fills)
+func isSlicedOrder(order map[string]interface{}) bool {
+	sliced, _ := order["sliced"].(bool)
+	return sliced
+}
+
+// attachedExitType returns the order type of an exit placed along with the entry: the one a sliced
+// entry recorded under key, else a market trigger attached to the entry order
+func attachedExitType(order map[string]interface{}, key string) string {
+	if orderType, _ := order[key].(string); orderType != "" {
+		return orderType
+	}
+	return ExitOrderMarket
+}
+
+// positionExits returns the stop-loss and take-profit orders of one position side (hedge mode keeps
+// the other side's)
+func (at *AutoTrader) positionExits(symbol, positionSide string) ([]OpenOrder, error) {
+	orders, err := at.trader.GetOpenOrders(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+	var exits []OpenOrder
+	for _, o := range orders {
+		if protectiveOrderKind(o, positionSide) != "" {
+			exits = append(exits, o)
+		}
+	}
+	return exits, nil
+}
+
+// protectSlicedEntry places the stop loss and take profits of the quantity a sliced entry opened so
+// far, replacing those of the previous children (in place with order types prevSL/prevTP, "" for
+// none). The new exits are placed before the previous ones are cancelled, so the opened part is never
+// unprotected; a previous exit whose replacement fails is kept, covering the quantity opened before
+// (the next child retries). Returns the exit order types in place, "" for an exit that isn't (left to
+// the executor once the entry completes).
+func (at *AutoTrader) protectSlicedEntry(decision *kernel.Decision, positionSide string, opened float64, prevSL, prevTP string) (slType, tpType string) {
+	var previous []OpenOrder
+	if prevSL != "" || prevTP != "" {
+		exits, err := at.positionExits(decision.Symbol, positionSide)
+		if err != nil {
+			at.log().Warnf("⚠️ [%s] Sliced entry %s: failed to resize exits to %.6f, keeping the previous ones: %v",
+				at.name, decision.Symbol, opened, err)
+			return prevSL, prevTP
+		}
+		previous = exits
+	}
+
+	slType, err := at.placeStopLoss(decision.Symbol, positionSide, opened, decision.StopLoss)
+	if err != nil {
+		at.log().Infof("  ⚠ Failed to set stop loss: %v", err)
+	}
+	tpType = at.placeTakeProfits(decision, positionSide, opened)
+
+	for _, o := range previous {
+		switch protectiveOrderKind(o, positionSide) {
+		case "stop_loss":
+			if slType == "" {
+				continue
+			}
+		case "take_profit":
+			if tpType == "" {
+				continue
+			}
+		}
+		if err := at.trader.CancelOrder(decision.Symbol, o.OrderID); err != nil {
+			at.log().Warnf("⚠️ [%s] Sliced entry %s: failed to cancel replaced exit %s: %v", at.name, decision.Symbol, o.OrderID, err)
+		}
+	}
+	if slType == "" && prevSL != "" {
+		at.log().Warnf("⚠️ [%s] Sliced entry %s: keeping the previous stop loss, it covers less than the opened %.6f", at.name, decision.Symbol, opened)
+		slType = prevSL
+	}
+	if tpType == "" && prevTP != "" {
+		at.log().Warnf("⚠️ [%s] Sliced entry %s: keeping the previous take profit, it covers less than the opened %.6f", at.name, decision.Symbol, opened)
+		tpType = prevTP
+	}
+	return slType, tpType
+}
+
+// openSlicedEntry opens quantity as n sequential child market orders spaced interval apart, each
+// confirmed and recorded before the next one is placed; the last child takes whatever is left after
+// rounding. The position is protected from the first fill on: each child places the stop loss and
+// take profits for the quantity opened so far, replacing the previous ones. A failed child (or the
+// trader stopping) ends the slicing: the children already filled are kept as the position (only a
+// failure of the first child is an error). Returns an aggregated order, carrying the exit order types
+// in place ("stopLossType"/"takeProfitType"), and the quantity opened.
+func (at *AutoTrader) openSlicedEntry(decision *kernel.Decision, positionSide string, quantity, price float64,
+	n int, interval time.Duration) (map[string]interface{}, float64, error) {
+	open := at.trader.OpenLong
+	if positionSide == "SHORT" {
+		open = at.trader.OpenShort
+	}
+	action := "open_" + strings.ToLower(positionSide)
+
+	slices := icebergSlices(quantity, 1/float64(n))
+	at.log().Infof("🧩 [%s] Sliced entry %s %s: notional %.2f USDT, opening in %d child orders every %v",
+		at.name, decision.Symbol, positionSide, quantity*price, len(slices), interval)
+
+	opened := 0.0
+	children := 0
+	var lastOrder map[string]interface{}
+	var slType, tpType string
+	for i, childQty := range slices {
+		last := i == len(slices)-1
+		if last {
+			childQty = quantity - opened
+		}
+		formatted, err := at.formatSliceQuantity(decision.Symbol, childQty)
+		if err == nil && formatted <= 0 {
+			if !last {
+				continue // below the quantity precision, carried over to the next child
+			}
+			err = fmt.Errorf("remaining quantity %.8f below the quantity precision", childQty)
+		}
+
+		var order map[string]interface{}
+		if err == nil {
+			order, err = open(decision.Symbol, formatted, decision.Leverage)
+			err = classifyOrderError(at.exchange, err)
+		}
+		if err == nil {
+			if status := at.sliceFillStatus(decision.Symbol, order); isFinalOrderStatus(status) && status != "FILLED" {
+				err = fmt.Errorf("not filled: %s", status)
+			}
+		}
+		if err != nil {
+			if children == 0 {
+				return nil, 0, fmt.Errorf("sliced entry child 1/%d failed: %w", len(slices), err)
+			}
+			at.log().Warnf("⚠️ [%s] Sliced entry %s child %d/%d failed, keeping %.6f opened by %d children: %v",
+				at.name, decision.Symbol, i+1, len(slices), opened, children, err)
+			break
+		}
+
+		at.recordAndConfirmOrder(order, decision.Symbol, action, formatted, price, decision.Leverage, 0)
+		opened += formatted
+		children++
+		lastOrder = order
+		at.log().Infof("  🧩 Child %d/%d opened: %s %s qty %.6f", i+1, len(slices), decision.Symbol, positionSide, formatted)
+		slType, tpType = at.protectSlicedEntry(decision, positionSide, opened, slType, tpType)
+
+		if last {
+			break
+		}
+		select {
+		case <-time.After(interval):
+		case <-at.stopMonitorCh:
+			at.log().Infof("⏹ [%s] Trader stopping, ending sliced entry %s with %.6f opened by %d children",
+				at.name, decision.Symbol, opened, children)
+			return slicedOrder(decision.Symbol, lastOrder, children, slType, tpType), opened, nil
+		}
+	}
+
+	return slicedOrder(decision.Symbol, lastOrder, children, slType, tpType), opened, nil
+}
+
+// slicedOrder aggregates the child orders of a sliced entry
+func slicedOrder(symbol string, lastOrder map[string]interface{}, children int, slType, tpType string) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":        lastOrder["orderId"],
+		"symbol":         symbol,
+		"sliced":         true,
+		"childOrders":    children,
+		"stopLossType":   slType,
+		"takeProfitType": tpType,
+	}
+}
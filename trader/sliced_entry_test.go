@@ -0,0 +1,202 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"nofx/kernel"
+)
+
+func TestEntryChildCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		notional    float64
+		minNotional float64
+		count       int
+		want        int
+	}{
+		{"configured count", 1000, 10, 4, 4},
+		{"capped", 100000, 10, 50, maxSlicedEntryCount},
+		{"reduced to min notional", 35, 10, 4, 3},
+		{"below min notional", 8, 10, 4, 1},
+		{"no minimum", 100, 0, 5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entryChildCount(tt.notional, tt.minNotional, tt.count); got != tt.want {
+				t.Errorf("entryChildCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSlicingTrader fills every open immediately and fails the open number failAt (1-based, 0 = never);
+// its stop-loss/take-profit orders rest until cancelled, and those after the first maxExits placed
+// (0 = no limit) fail
+type fakeSlicingTrader struct {
+	Trader
+	opened   []float64
+	failAt   int
+	exits    []OpenOrder
+	nextID   int
+	maxExits int
+	fewest   int // Fewest resting exits left by a cancel (-1 = none cancelled)
+}
+
+func (f *fakeSlicingTrader) addExit(orderType string, quantity, price float64) error {
+	if f.maxExits > 0 && f.nextID >= f.maxExits {
+		return errors.New("exchange unavailable")
+	}
+	f.nextID++
+	f.exits = append(f.exits, OpenOrder{OrderID: fmt.Sprint(f.nextID), PositionSide: "LONG", Type: orderType, StopPrice: price, Quantity: quantity})
+	return nil
+}
+
+func (f *fakeSlicingTrader) SetStopLoss(symbol, positionSide string, quantity, price float64) error {
+	return f.addExit("STOP_MARKET", quantity, price)
+}
+
+func (f *fakeSlicingTrader) SetTakeProfit(symbol, positionSide string, quantity, price float64) error {
+	return f.addExit("TAKE_PROFIT_MARKET", quantity, price)
+}
+
+func (f *fakeSlicingTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	return append([]OpenOrder(nil), f.exits...), nil
+}
+
+func (f *fakeSlicingTrader) CancelOrder(symbol, orderID string) error {
+	for i, o := range f.exits {
+		if o.OrderID == orderID {
+			f.exits = append(f.exits[:i], f.exits[i+1:]...)
+			if f.fewest < 0 || len(f.exits) < f.fewest {
+				f.fewest = len(f.exits)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("order %s not found", orderID)
+}
+
+func (f *fakeSlicingTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if len(f.opened)+1 == f.failAt {
+		return nil, errors.New("exchange unavailable")
+	}
+	f.opened = append(f.opened, quantity)
+	return map[string]interface{}{"orderId": int64(len(f.opened))}, nil
+}
+
+func (f *fakeSlicingTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return fmt.Sprintf("%.3f", math.Floor(quantity*1000)/1000), nil
+}
+
+func (f *fakeSlicingTrader) GetOrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "FILLED"}, nil
+}
+
+func TestOpenSlicedEntry(t *testing.T) {
+	decision := &kernel.Decision{Symbol: "SOLUSDT", Leverage: 3, StopLoss: 140, TakeProfit: 170}
+	newTrader := func(ft *fakeSlicingTrader) *AutoTrader {
+		return &AutoTrader{trader: ft, config: AutoTraderConfig{OrderFill: OrderFillConfig{PollAttempts: 1, PollInterval: time.Millisecond}}}
+	}
+
+	t.Run("children sum to quantity", func(t *testing.T) {
+		ft := &fakeSlicingTrader{}
+		order, opened, err := newTrader(ft).openSlicedEntry(decision, "LONG", 10, 150, 3, time.Millisecond)
+		if err != nil {
+			t.Fatalf("openSlicedEntry() error = %v", err)
+		}
+		if len(ft.opened) != 3 {
+			t.Fatalf("placed %d children %v, want 3", len(ft.opened), ft.opened)
+		}
+		// Children are rounded down to the quantity step, never exceeding the requested quantity
+		if opened > 10+1e-9 || opened < 10-0.002 {
+			t.Errorf("opened = %v, want 10 within one quantity step", opened)
+		}
+		if !isSlicedOrder(order) || order["childOrders"] != 3 || order["orderId"] != int64(3) {
+			t.Errorf("aggregated order = %v", order)
+		}
+	})
+
+	t.Run("failed child keeps filled part", func(t *testing.T) {
+		ft := &fakeSlicingTrader{failAt: 3}
+		_, opened, err := newTrader(ft).openSlicedEntry(decision, "LONG", 8, 150, 4, time.Millisecond)
+		if err != nil {
+			t.Fatalf("openSlicedEntry() error = %v", err)
+		}
+		if math.Abs(opened-4) > 1e-9 {
+			t.Errorf("opened = %v, want 4 (two children)", opened)
+		}
+	})
+
+	t.Run("exits follow the opened quantity", func(t *testing.T) {
+		ft := &fakeSlicingTrader{fewest: -1}
+		order, opened, err := newTrader(ft).openSlicedEntry(decision, "LONG", 9, 150, 3, time.Millisecond)
+		if err != nil {
+			t.Fatalf("openSlicedEntry() error = %v", err)
+		}
+		if len(ft.exits) != 2 {
+			t.Fatalf("resting exits = %+v, want one stop loss and one take profit", ft.exits)
+		}
+		for _, o := range ft.exits {
+			if math.Abs(o.Quantity-opened) > 1e-9 {
+				t.Errorf("%s sized %v, want the opened %v", o.Type, o.Quantity, opened)
+			}
+		}
+		if ft.nextID != 6 {
+			t.Errorf("placed %d exits, want a stop loss and take profit per child (6)", ft.nextID)
+		}
+		// The previous exits are cancelled once their replacements rest
+		if ft.fewest < 2 {
+			t.Errorf("%d exits left resting while resizing, want the position covered throughout", ft.fewest)
+		}
+		if order["stopLossType"] != ExitOrderMarket || order["takeProfitType"] != ExitOrderMarket {
+			t.Errorf("exit types = %v / %v, want market", order["stopLossType"], order["takeProfitType"])
+		}
+	})
+
+	t.Run("failed resize keeps the previous exits", func(t *testing.T) {
+		ft := &fakeSlicingTrader{maxExits: 2}
+		order, _, err := newTrader(ft).openSlicedEntry(decision, "LONG", 9, 150, 3, time.Millisecond)
+		if err != nil {
+			t.Fatalf("openSlicedEntry() error = %v", err)
+		}
+		if len(ft.exits) != 2 {
+			t.Fatalf("resting exits = %+v, want the first child's stop loss and take profit", ft.exits)
+		}
+		for _, o := range ft.exits {
+			if math.Abs(o.Quantity-3) > 1e-9 {
+				t.Errorf("%s sized %v, want the first child's 3", o.Type, o.Quantity)
+			}
+		}
+		if order["stopLossType"] != ExitOrderMarket || order["takeProfitType"] != ExitOrderMarket {
+			t.Errorf("exit types = %v / %v, want the kept market exits", order["stopLossType"], order["takeProfitType"])
+		}
+	})
+
+	t.Run("stopping the trader ends the slicing", func(t *testing.T) {
+		ft := &fakeSlicingTrader{}
+		at := newTrader(ft)
+		at.stopMonitorCh = make(chan struct{})
+		close(at.stopMonitorCh)
+		_, opened, err := at.openSlicedEntry(decision, "LONG", 8, 150, 4, time.Hour)
+		if err != nil {
+			t.Fatalf("openSlicedEntry() error = %v", err)
+		}
+		if len(ft.opened) != 1 || math.Abs(opened-2) > 1e-9 {
+			t.Errorf("opened %v (%v), want only the first child", ft.opened, opened)
+		}
+		if len(ft.exits) != 2 {
+			t.Errorf("resting exits = %+v, want the first child protected", ft.exits)
+		}
+	})
+
+	t.Run("first child failure is an error", func(t *testing.T) {
+		ft := &fakeSlicingTrader{failAt: 1}
+		if _, _, err := newTrader(ft).openSlicedEntry(decision, "LONG", 8, 150, 4, time.Millisecond); err == nil {
+			t.Error("expected error when no child opens")
+		}
+	})
+}
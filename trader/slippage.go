@@ -0,0 +1,79 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+	"sync"
+)
+
+// DEX market orders are sent as aggressive IOC limits: a buy is priced slippage bps above the mark
+// price, a sell below it. A wider offset makes fills more certain on thin books but accepts a worse
+// price; the offset is configured per exchange account with optional per-symbol overrides.
+
+const (
+	// hyperliquidDefaultSlippageBps default IOC offset on Hyperliquid (1%)
+	hyperliquidDefaultSlippageBps = 100
+	// lighterDefaultSlippageBps default market order price protection on Lighter (5%)
+	lighterDefaultSlippageBps = 500
+	// MaxSlippageBps largest configurable IOC offset (20%)
+	MaxSlippageBps = 2000
+)
+
+// SlippageConfigurable is implemented by exchange clients whose market orders are aggressive IOC limits
+type SlippageConfigurable interface {
+	SetSlippage(settings store.SlippageSettings)
+}
+
+// ValidateSlippage checks slippage settings: every offset must be in (0, MaxSlippageBps]
+func ValidateSlippage(settings store.SlippageSettings) error {
+	if settings.Bps < 0 || settings.Bps > MaxSlippageBps {
+		return fmt.Errorf("slippage bps must be between 0 and %d", MaxSlippageBps)
+	}
+	for symbol, bps := range settings.SymbolBps {
+		if bps <= 0 || bps > MaxSlippageBps {
+			return fmt.Errorf("slippage bps of %s must be between 0 and %d (exclusive of 0)", symbol, MaxSlippageBps)
+		}
+	}
+	return nil
+}
+
+// slippageConfig per-symbol IOC offsets of an exchange client, safe for concurrent use
+type slippageConfig struct {
+	mu        sync.RWMutex
+	bps       float64
+	symbolBps map[string]float64
+}
+
+// set replaces the settings; symbol keys are normalized so "btc" and "BTCUSDT" match
+func (c *slippageConfig) set(settings store.SlippageSettings) {
+	symbolBps := make(map[string]float64, len(settings.SymbolBps))
+	for symbol, bps := range settings.SymbolBps {
+		symbolBps[market.Normalize(symbol)] = bps
+	}
+	c.mu.Lock()
+	c.bps = settings.Bps
+	c.symbolBps = symbolBps
+	c.mu.Unlock()
+}
+
+// bpsFor returns the offset of symbol: its override, else the account default, else defaultBps
+func (c *slippageConfig) bpsFor(symbol string, defaultBps float64) float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if bps, ok := c.symbolBps[market.Normalize(symbol)]; ok && bps > 0 {
+		return bps
+	}
+	if c.bps > 0 {
+		return c.bps
+	}
+	return defaultBps
+}
+
+// aggressivePrice offsets price by bps towards the taker side (up for buys, down for sells)
+func aggressivePrice(price float64, isBuy bool, bps float64) float64 {
+	if isBuy {
+		return price * (1 + bps/10000)
+	}
+	return price * (1 - bps/10000)
+}
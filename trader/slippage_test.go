@@ -0,0 +1,62 @@
+package trader
+
+import (
+	"math"
+	"testing"
+
+	"nofx/store"
+)
+
+func TestSlippageConfigBpsFor(t *testing.T) {
+	var cfg slippageConfig
+	if got := cfg.bpsFor("BTCUSDT", hyperliquidDefaultSlippageBps); got != hyperliquidDefaultSlippageBps {
+		t.Errorf("unset config = %v bps, want exchange default %d", got, hyperliquidDefaultSlippageBps)
+	}
+
+	cfg.set(store.SlippageSettings{Bps: 30, SymbolBps: map[string]float64{"pepe": 250}})
+	if got := cfg.bpsFor("BTCUSDT", hyperliquidDefaultSlippageBps); got != 30 {
+		t.Errorf("account default = %v bps, want 30", got)
+	}
+	if got := cfg.bpsFor("PEPEUSDT", hyperliquidDefaultSlippageBps); got != 250 {
+		t.Errorf("per-symbol override = %v bps, want 250", got)
+	}
+}
+
+func TestAggressivePrice(t *testing.T) {
+	if got := aggressivePrice(100, true, 100); math.Abs(got-101) > 1e-9 {
+		t.Errorf("buy at 100 bps = %v, want 101", got)
+	}
+	if got := aggressivePrice(100, false, 25); math.Abs(got-99.75) > 1e-9 {
+		t.Errorf("sell at 25 bps = %v, want 99.75", got)
+	}
+}
+
+func TestHyperliquidIOCPriceDefaultsToOnePercent(t *testing.T) {
+	ht := &HyperliquidTrader{}
+	if got := ht.iocPrice("ETHUSDT", 2000, true); math.Abs(got-2020) > 1e-9 {
+		t.Errorf("default buy IOC price = %v, want 2020", got)
+	}
+	ht.SetSlippage(store.SlippageSettings{SymbolBps: map[string]float64{"ETH": 10}})
+	if got := ht.iocPrice("ETHUSDT", 2000, false); math.Abs(got-1998) > 1e-9 {
+		t.Errorf("overridden sell IOC price = %v, want 1998", got)
+	}
+}
+
+func TestValidateSlippage(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings store.SlippageSettings
+		wantErr  bool
+	}{
+		{"empty", store.SlippageSettings{}, false},
+		{"valid", store.SlippageSettings{Bps: 50, SymbolBps: map[string]float64{"BTC": 5}}, false},
+		{"negative", store.SlippageSettings{Bps: -1}, true},
+		{"too wide", store.SlippageSettings{Bps: MaxSlippageBps + 1}, true},
+		{"zero override", store.SlippageSettings{SymbolBps: map[string]float64{"BTC": 0}}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSlippage(tt.settings); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateSlippage() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"strings"
+)
+
+// PartialTakeProfitSetter is implemented by exchanges that can place a reduce-only take profit for part
+// of a position. SetTakeProfit may close the whole position when triggered (e.g. Binance closePosition),
+// so take-profit ladders are only placed through this interface.
+type PartialTakeProfitSetter interface {
+	// SetPartialTakeProfit places a reduce-only market take profit closing exactly quantity when triggered
+	SetPartialTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error
+}
+
+// ladderFallbackPrice returns the single take-profit price used when a ladder can't be placed:
+// the decision's take_profit, else the levels' percent-weighted average price
+func ladderFallbackPrice(decision *kernel.Decision) float64 {
+	if decision.TakeProfit > 0 {
+		return decision.TakeProfit
+	}
+	var weighted, total float64
+	for _, level := range decision.TakeProfitLevels {
+		weighted += level.Price * level.Percent
+		total += level.Percent
+	}
+	if total <= 0 {
+		return 0
+	}
+	return weighted / total
+}
+
+// placeTakeProfits places take-profit orders for a newly opened position
+// With take_profit_levels, places one reduce-only TP per level sized by percent (last level gets the remainder);
+// otherwise places a single TP at decision.TakeProfit. Exchanges without partial take profits
+// (PartialTakeProfitSetter) get a single TP instead of the ladder. Returns the exit order type used ("" if none was placed).
+func (at *AutoTrader) placeTakeProfits(decision *kernel.Decision, positionSide string, quantity float64) string {
+	partial, canLadder := capableTrader[PartialTakeProfitSetter](at.trader)
+	if len(decision.TakeProfitLevels) == 0 || !canLadder {
+		price := decision.TakeProfit
+		if len(decision.TakeProfitLevels) > 0 {
+			price = ladderFallbackPrice(decision)
+			at.log().Warnf("  ⚠ %s does not support partial take profits, placing a single take profit at %.4f instead of %d levels",
+				at.exchange, price, len(decision.TakeProfitLevels))
+		}
+		orderType, err := at.placeTakeProfit(decision.Symbol, positionSide, quantity, price)
+		if err != nil {
+			at.log().Infof("  ⚠ Failed to set take profit: %v", err)
+		}
+		return orderType
+	}
+
+	key := decision.Symbol + "_" + strings.ToLower(positionSide)
+
+	// Replace any ladder left over from a previous position in this symbol
+	at.tpLaddersMutex.Lock()
+	_, stale := at.tpLadders[key]
+	at.tpLaddersMutex.Unlock()
+	if stale {
+		if err := at.trader.CancelTakeProfitOrders(decision.Symbol); err != nil {
+			at.log().Infof("  ⚠ Failed to cancel previous take profit ladder: %v", err)
+		}
+	}
+
+	remaining := quantity
+	placed := make([]kernel.TakeProfitLevel, 0, len(decision.TakeProfitLevels))
+	orderTypes := make([]string, 0, len(decision.TakeProfitLevels))
+	for i, level := range decision.TakeProfitLevels {
+		levelQty := quantity * level.Percent / 100
+		if i == len(decision.TakeProfitLevels)-1 || levelQty > remaining {
+			levelQty = remaining
+		}
+		if levelQty <= 0 {
+			continue
+		}
+
+		orderType, err := at.placeTakeProfitWith(decision.Symbol, positionSide, levelQty, level.Price, partial.SetPartialTakeProfit)
+		if err != nil {
+			at.log().Infof("  ⚠ Failed to set take profit level %d (%.4f @ %.4f): %v", i+1, levelQty, level.Price, err)
+			continue
+		}
+		remaining -= levelQty
+		placed = append(placed, level)
+		orderTypes = append(orderTypes, orderType)
+		at.log().Infof("  🎯 TP level %d/%d: %.4f @ %.4f (%.0f%%)",
+			i+1, len(decision.TakeProfitLevels), levelQty, level.Price, level.Percent)
+	}
+
+	at.tpLaddersMutex.Lock()
+	if len(placed) > 0 {
+		at.tpLadders[key] = placed
+	} else {
+		delete(at.tpLadders, key)
+	}
+	at.tpLaddersMutex.Unlock()
+	return mergeExitOrderTypes(orderTypes...)
+}
+
+// reconcileTakeProfitLadders cancels remaining ladder TP orders of positions that no longer exist
+// (e.g. stopped out before all levels filled), called every cycle
+func (at *AutoTrader) reconcileTakeProfitLadders(positions []kernel.PositionInfo) {
+	at.tpLaddersMutex.Lock()
+	if len(at.tpLadders) == 0 {
+		at.tpLaddersMutex.Unlock()
+		return
+	}
+
+	open := make(map[string]bool, len(positions))
+	for _, pos := range positions {
+		open[pos.Symbol+"_"+strings.ToLower(pos.Side)] = true
+	}
+
+	var closed []string
+	for key := range at.tpLadders {
+		if !open[key] {
+			closed = append(closed, key)
+			delete(at.tpLadders, key)
+		}
+	}
+	at.tpLaddersMutex.Unlock()
+
+	for _, key := range closed {
+		symbol := key[:strings.LastIndex(key, "_")]
+		if err := at.trader.CancelTakeProfitOrders(symbol); err != nil {
+			at.log().Infof("⚠️ [%s] Failed to cancel leftover take profit ladder for %s: %v", at.name, key, err)
+			continue
+		}
+		at.log().Infof("🧹 [%s] Position %s closed, cancelled leftover take profit ladder orders", at.name, key)
+	}
+}
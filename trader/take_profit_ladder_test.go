@@ -0,0 +1,84 @@
+package trader
+
+import (
+	"math"
+	"testing"
+
+	"nofx/kernel"
+)
+
+// fakeTakeProfitTrader records take-profit orders of an exchange without partial take profits
+type fakeTakeProfitTrader struct {
+	Trader
+	full []float64 // Quantities sent to SetTakeProfit
+	tpPx []float64 // Trigger prices sent to SetTakeProfit
+}
+
+func (f *fakeTakeProfitTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) { return nil, nil }
+
+func (f *fakeTakeProfitTrader) SetTakeProfit(symbol, positionSide string, quantity, price float64) error {
+	f.full = append(f.full, quantity)
+	f.tpPx = append(f.tpPx, price)
+	return nil
+}
+
+// fakePartialTakeProfitTrader records take-profit orders of an exchange with partial take profits
+type fakePartialTakeProfitTrader struct {
+	fakeTakeProfitTrader
+	partial []float64 // Quantities sent to SetPartialTakeProfit
+}
+
+func (f *fakePartialTakeProfitTrader) SetPartialTakeProfit(symbol, positionSide string, quantity, price float64) error {
+	f.partial = append(f.partial, quantity)
+	return nil
+}
+
+func TestPlaceTakeProfitLadder(t *testing.T) {
+	decision := &kernel.Decision{
+		Symbol: "BTCUSDT",
+		TakeProfitLevels: []kernel.TakeProfitLevel{
+			{Price: 110, Percent: 30},
+			{Price: 120, Percent: 30},
+			{Price: 130, Percent: 40},
+		},
+	}
+
+	t.Run("each level sends its own quantity", func(t *testing.T) {
+		ft := &fakePartialTakeProfitTrader{}
+		at := &AutoTrader{trader: ft, tpLadders: make(map[string][]kernel.TakeProfitLevel)}
+		if got := at.placeTakeProfits(decision, "LONG", 2); got != ExitOrderMarket {
+			t.Errorf("order type = %q, want %q", got, ExitOrderMarket)
+		}
+		want := []float64{0.6, 0.6, 0.8}
+		if len(ft.partial) != len(want) {
+			t.Fatalf("partial TPs = %v, want %v", ft.partial, want)
+		}
+		for i := range want {
+			if math.Abs(ft.partial[i]-want[i]) > 1e-9 {
+				t.Errorf("level %d quantity = %v, want %v", i+1, ft.partial[i], want[i])
+			}
+		}
+		if len(ft.full) != 0 {
+			t.Errorf("full-position TP used for a ladder level: %v", ft.full)
+		}
+		if len(at.tpLadders["BTCUSDT_long"]) != 3 {
+			t.Errorf("ladder not tracked: %v", at.tpLadders)
+		}
+	})
+
+	t.Run("no partial take profits places a single TP", func(t *testing.T) {
+		ft := &fakeTakeProfitTrader{}
+		at := &AutoTrader{trader: ft, tpLadders: make(map[string][]kernel.TakeProfitLevel)}
+		at.placeTakeProfits(decision, "LONG", 2)
+		if len(ft.full) != 1 || ft.full[0] != 2 {
+			t.Fatalf("take profits = %v, want one for the whole quantity", ft.full)
+		}
+		// Percent-weighted average of the levels: 0.3*110 + 0.3*120 + 0.4*130
+		if math.Abs(ft.tpPx[0]-121) > 1e-9 {
+			t.Errorf("fallback price = %v, want 121", ft.tpPx[0])
+		}
+		if len(at.tpLadders) != 0 {
+			t.Errorf("ladder tracked although not placed: %v", at.tpLadders)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+var tradingWindowDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// weekRange a window occurrence in minutes since Sunday 00:00 UTC, [start, start+length)
+type weekRange struct {
+	start, length int
+}
+
+// parseWindowClock parses "HH:MM" into minutes since midnight
+func parseWindowClock(s string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// tradingWindowRanges expands a window into its weekly occurrences.
+// End <= Start wraps past midnight (End == Start is a full day); the occurrence belongs to the day it starts on.
+func tradingWindowRanges(w store.TradingWindow) ([]weekRange, error) {
+	start, err := parseWindowClock(w.Start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	end, err := parseWindowClock(w.End)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	length := end - start
+	if length <= 0 {
+		length += minutesPerDay
+	}
+
+	days := make([]time.Weekday, 0, 7)
+	if len(w.Days) == 0 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			days = append(days, d)
+		}
+	}
+	for _, name := range w.Days {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if len(key) > 3 {
+			key = key[:3] // "monday" -> "mon"
+		}
+		day, ok := tradingWindowDays[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q, expected mon..sun", name)
+		}
+		days = append(days, day)
+	}
+
+	ranges := make([]weekRange, 0, len(days))
+	for _, day := range days {
+		ranges = append(ranges, weekRange{start: int(day)*minutesPerDay + start, length: length})
+	}
+	return ranges, nil
+}
+
+// ValidateTradingWindows checks that every trading window parses
+func ValidateTradingWindows(windows []store.TradingWindow) error {
+	for i, w := range windows {
+		if _, err := tradingWindowRanges(w); err != nil {
+			return fmt.Errorf("trading window #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// minuteOfWeek minutes since Sunday 00:00 UTC
+func minuteOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*minutesPerDay + t.Hour()*60 + t.Minute()
+}
+
+// inTradingWindow reports whether now falls in any window (no windows = always open, invalid windows are ignored)
+func inTradingWindow(windows []store.TradingWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	m := minuteOfWeek(now)
+	for _, w := range windows {
+		ranges, err := tradingWindowRanges(w)
+		if err != nil {
+			continue
+		}
+		for _, r := range ranges {
+			// m+minutesPerWeek catches Saturday windows wrapping into Sunday
+			if (m >= r.start && m < r.start+r.length) || (m+minutesPerWeek >= r.start && m+minutesPerWeek < r.start+r.length) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextTradingWindowOpen returns when the next window opens after now (ok=false if there is none)
+func nextTradingWindowOpen(windows []store.TradingWindow, now time.Time) (time.Time, bool) {
+	m := minuteOfWeek(now)
+	best := -1
+	for _, w := range windows {
+		ranges, err := tradingWindowRanges(w)
+		if err != nil {
+			continue
+		}
+		for _, r := range ranges {
+			delta := (r.start - m + minutesPerWeek) % minutesPerWeek
+			if delta == 0 {
+				delta = minutesPerWeek
+			}
+			if best < 0 || delta < best {
+				best = delta
+			}
+		}
+	}
+	if best < 0 {
+		return time.Time{}, false
+	}
+	return now.UTC().Truncate(time.Minute).Add(time.Duration(best) * time.Minute), true
+}
+
+// tradingWindows returns the strategy's trading windows (nil = no restriction)
+func (at *AutoTrader) tradingWindows() []store.TradingWindow {
+	if at.config.StrategyConfig == nil {
+		return nil
+	}
+	return at.config.StrategyConfig.RiskControl.TradingWindows
+}
+
+// enforceTradingWindow rejects new positions outside the configured trading windows (CODE ENFORCED)
+// Closes and SL/TP management are not affected
+func (at *AutoTrader) enforceTradingWindow(now time.Time) error {
+	windows := at.tradingWindows()
+	if inTradingWindow(windows, now) {
+		return nil
+	}
+	if next, ok := nextTradingWindowOpen(windows, now); ok {
+		return fmt.Errorf("❌ [RISK CONTROL] Outside trading window, no new positions until %s", next.Format(time.RFC3339))
+	}
+	return fmt.Errorf("❌ [RISK CONTROL] Outside trading window, no new positions")
+}
@@ -0,0 +1,72 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestInTradingWindow(t *testing.T) {
+	weekdays := []store.TradingWindow{{Start: "00:00", End: "00:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}}
+	overnight := []store.TradingWindow{{Start: "22:00", End: "02:00", Days: []string{"sat"}}}
+	daytime := []store.TradingWindow{{Start: "08:00", End: "20:00"}}
+
+	tests := []struct {
+		name    string
+		windows []store.TradingWindow
+		now     string
+		want    bool
+	}{
+		{"no windows always open", nil, "2026-10-17T03:00:00Z", true},
+		{"weekday full day", weekdays, "2026-10-16T23:59:00Z", true}, // Friday
+		{"weekend closed", weekdays, "2026-10-17T12:00:00Z", false},  // Saturday
+		{"daily window open", daytime, "2026-10-18T08:00:00Z", true},
+		{"daily window end exclusive", daytime, "2026-10-18T20:00:00Z", false},
+		{"overnight before midnight", overnight, "2026-10-17T23:00:00Z", true},
+		{"overnight wraps into sunday", overnight, "2026-10-18T01:30:00Z", true},
+		{"overnight closed after end", overnight, "2026-10-18T02:00:00Z", false},
+		{"invalid window ignored", []store.TradingWindow{{Start: "25:00", End: "01:00"}}, "2026-10-18T00:30:00Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, _ := time.Parse(time.RFC3339, tt.now)
+			if got := inTradingWindow(tt.windows, now); got != tt.want {
+				t.Errorf("inTradingWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTradingWindowOpen(t *testing.T) {
+	windows := []store.TradingWindow{{Start: "08:00", End: "20:00", Days: []string{"Monday", "wed"}}}
+
+	now, _ := time.Parse(time.RFC3339, "2026-10-17T12:30:45Z") // Saturday
+	next, ok := nextTradingWindowOpen(windows, now)
+	if !ok || next.Format(time.RFC3339) != "2026-10-19T08:00:00Z" {
+		t.Errorf("next = %v (ok=%v), want Monday 08:00", next, ok)
+	}
+
+	now, _ = time.Parse(time.RFC3339, "2026-10-19T21:00:00Z") // Monday after close
+	if next, _ = nextTradingWindowOpen(windows, now); next.Format(time.RFC3339) != "2026-10-21T08:00:00Z" {
+		t.Errorf("next = %v, want Wednesday 08:00", next)
+	}
+
+	if _, ok := nextTradingWindowOpen(nil, now); ok {
+		t.Error("expected no next window without windows")
+	}
+}
+
+func TestValidateTradingWindows(t *testing.T) {
+	if err := ValidateTradingWindows([]store.TradingWindow{{Start: "22:00", End: "24:00", Days: []string{"fri"}}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, w := range []store.TradingWindow{
+		{Start: "8", End: "20:00"},
+		{Start: "08:00", End: "20:60"},
+		{Start: "08:00", End: "20:00", Days: []string{"funday"}},
+	} {
+		if err := ValidateTradingWindows([]store.TradingWindow{w}); err == nil {
+			t.Errorf("expected error for %+v", w)
+		}
+	}
+}
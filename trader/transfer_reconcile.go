@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// Transfer a deposit into (positive amount) or withdrawal from (negative amount) the trading account
+type Transfer struct {
+	ID     string // Exchange transfer ID
+	Asset  string
+	Amount float64
+	Time   time.Time
+}
+
+// TransferHistoryProvider is implemented by exchanges that expose the trading account's deposit/withdrawal history
+type TransferHistoryProvider interface {
+	// GetTransfers returns stablecoin transfers in/out of the trading account since the given time
+	GetTransfers(since time.Time) ([]Transfer, error)
+}
+
+// transferReconcileFloor returns the time transfers are imported from. Every recorded adjustment (a
+// balance sync estimate, manual amount or reset) already accounts for funding before it, so only
+// transfers after the latest one, and after the trader was created, move the PnL baseline.
+func transferReconcileFloor(st *store.Store, traderID string) (time.Time, error) {
+	var floor time.Time
+	if t, err := st.Trader().GetByID(traderID); err == nil && t != nil {
+		floor = t.CreatedAt
+	}
+	latest, err := st.BalanceAdjustment().LatestTime(traderID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest balance adjustment: %w", err)
+	}
+	if latestTime := time.UnixMilli(latest); latest > 0 && latestTime.After(floor) {
+		floor = latestTime
+	}
+	return floor, nil
+}
+
+// reconcileTransfers records the exchange transfers not yet stored as balance adjustments,
+// returns the number of transfers recorded
+func reconcileTransfers(provider TransferHistoryProvider, st *store.Store, traderID string) (int, error) {
+	floor, err := transferReconcileFloor(st, traderID)
+	if err != nil {
+		return 0, err
+	}
+
+	transfers, err := provider.GetTransfers(floor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transfer history: %w", err)
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].Time.Before(transfers[j].Time) })
+
+	recorded := 0
+	for _, tr := range transfers {
+		// Transfers at the floor itself may already be recorded, the external ID check deduplicates them
+		if tr.ID == "" || tr.Amount == 0 || tr.Time.Before(floor) || !market.IsSupportedQuoteAsset(tr.Asset) {
+			continue
+		}
+		exists, err := st.BalanceAdjustment().ExistsExternal(traderID, tr.ID)
+		if err != nil {
+			return recorded, fmt.Errorf("failed to check transfer %s: %w", tr.ID, err)
+		}
+		if exists {
+			continue
+		}
+		if err := st.BalanceAdjustment().Create(&store.BalanceAdjustment{
+			TraderID:   traderID,
+			Amount:     tr.Amount,
+			Source:     store.BalanceAdjustmentTransfer,
+			Note:       tr.Asset,
+			ExternalID: tr.ID,
+			CreatedAt:  tr.Time.UnixMilli(),
+		}); err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// startTransferReconcileLoop periodically imports the exchange's deposits/withdrawals so PnL excludes them
+func startTransferReconcileLoop(name string, provider TransferHistoryProvider, traderID string, st *store.Store,
+	interval time.Duration, stopCh <-chan struct{}) {
+	log := logger.TraderEntry(traderID, nil)
+	reconcile := func() {
+		n, err := reconcileTransfers(provider, st, traderID)
+		if err != nil {
+			log.Warnf("⚠️ [%s] Transfer reconcile failed: %v", name, err)
+			return
+		}
+		if n > 0 {
+			log.Infof("💸 [%s] Recorded %d deposit/withdrawal(s) from exchange transfer history", name, n)
+		}
+	}
+
+	go func() {
+		reconcile()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reconcile()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+type fakeTransferProvider struct {
+	transfers []Transfer
+	since     time.Time
+}
+
+func (p *fakeTransferProvider) GetTransfers(since time.Time) ([]Transfer, error) {
+	p.since = since
+	return p.transfers, nil
+}
+
+func TestReconcileTransfers(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "transfers.db"))
+	if err != nil {
+		t.Fatalf("Failed to init test store: %v", err)
+	}
+	defer st.Close()
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	provider := &fakeTransferProvider{transfers: []Transfer{
+		{ID: "t1", Asset: "USDT", Amount: 500, Time: base},
+		{ID: "t2", Asset: "USDC", Amount: -200, Time: base.Add(10 * time.Minute)},
+		{ID: "t3", Asset: "BNB", Amount: 3, Time: base.Add(20 * time.Minute)}, // not a quote asset
+	}}
+
+	n, err := reconcileTransfers(provider, st, "trader-1")
+	if err != nil {
+		t.Fatalf("reconcileTransfers() error = %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("recorded %d transfers, want 2", n)
+	}
+	if net, _ := st.BalanceAdjustment().NetContributions("trader-1"); net != 300 {
+		t.Errorf("net contributions = %v, want 300", net)
+	}
+
+	// Second run resumes from the latest transfer and doesn't record duplicates
+	n, err = reconcileTransfers(provider, st, "trader-1")
+	if err != nil || n != 0 {
+		t.Fatalf("second reconcileTransfers() = %d, %v, want 0 new", n, err)
+	}
+	if !provider.since.Equal(base.Add(10 * time.Minute)) {
+		t.Errorf("second run since = %v, want latest transfer time %v", provider.since, base.Add(10*time.Minute))
+	}
+
+	// Transfers before a later adjustment (e.g. a balance reset) are already accounted for
+	if err := st.BalanceAdjustment().Create(&store.BalanceAdjustment{
+		TraderID: "trader-1", Amount: -300, Source: store.BalanceAdjustmentReset,
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	provider.transfers = append(provider.transfers, Transfer{ID: "t4", Asset: "USDT", Amount: 50, Time: base.Add(30 * time.Minute)})
+	if n, _ := reconcileTransfers(provider, st, "trader-1"); n != 0 {
+		t.Errorf("recorded %d transfers older than the reset, want 0", n)
+	}
+}
+
+func TestParseBybitTransactionLog(t *testing.T) {
+	body := []byte(`{"retCode":0,"retMsg":"OK","result":{"list":[
+		{"id":"592324_XRPUSDT_161440249321","currency":"USDT","cashFlow":"1000","transactionTime":"1700000000000"},
+		{"id":"592325_XRPUSDT_161440249322","currency":"USDT","cashFlow":"-250.5","transactionTime":"1700000060000"}
+	]}}`)
+
+	transfers, err := parseBybitTransactionLog(body)
+	if err != nil {
+		t.Fatalf("parseBybitTransactionLog() error = %v", err)
+	}
+	if len(transfers) != 2 {
+		t.Fatalf("got %d transfers, want 2", len(transfers))
+	}
+	if transfers[0].Amount != 1000 || transfers[1].Amount != -250.5 {
+		t.Errorf("amounts = %v, %v, want 1000, -250.5", transfers[0].Amount, transfers[1].Amount)
+	}
+	if transfers[1].Time.UnixMilli() != 1700000060000 {
+		t.Errorf("time = %v, want 1700000060000 ms", transfers[1].Time.UnixMilli())
+	}
+
+	if _, err := parseBybitTransactionLog([]byte(`{"retCode":10001,"retMsg":"params error"}`)); err == nil {
+		t.Error("expected error for non-zero retCode")
+	}
+}
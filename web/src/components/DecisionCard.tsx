@@ -14,6 +14,8 @@ const ACTION_CONFIG: Record<string, { color: string; bg: string; icon: string; l
   open_short: { color: '#F6465D', bg: 'rgba(246, 70, 93, 0.15)', icon: '📉', label: 'SHORT' },
   close_long: { color: '#F0B90B', bg: 'rgba(240, 185, 11, 0.15)', icon: '💰', label: 'CLOSE' },
   close_short: { color: '#F0B90B', bg: 'rgba(240, 185, 11, 0.15)', icon: '💰', label: 'CLOSE' },
+  reduce_long: { color: '#F0B90B', bg: 'rgba(240, 185, 11, 0.15)', icon: '✂️', label: 'REDUCE' },
+  reduce_short: { color: '#F0B90B', bg: 'rgba(240, 185, 11, 0.15)', icon: '✂️', label: 'REDUCE' },
   hold: { color: '#848E9C', bg: 'rgba(132, 142, 156, 0.15)', icon: '⏸️', label: 'HOLD' },
   wait: { color: '#848E9C', bg: 'rgba(132, 142, 156, 0.15)', icon: '⏳', label: 'WAIT' },
 }
@@ -26,6 +26,8 @@ const SUPPORTED_EXCHANGE_TEMPLATES = [
   { exchange_type: 'aster', name: 'Aster DEX', type: 'dex' as const },
   { exchange_type: 'lighter', name: 'Lighter', type: 'dex' as const },
   { exchange_type: 'gateio', name: 'Gate.io Futures', type: 'cex' as const },
+  { exchange_type: 'coinbase', name: 'Coinbase International', type: 'cex' as const },
+  { exchange_type: 'backpack', name: 'Backpack', type: 'cex' as const },
 ]
 
 interface ExchangeConfigModalProps {
@@ -131,6 +133,8 @@ export function ExchangeConfigModal({
     aster: { url: 'https://www.asterdex.com/en/referral/fdfc0e', hasReferral: true },
     lighter: { url: 'https://app.lighter.xyz/?referral=68151432', hasReferral: true },
     gateio: { url: 'https://www.gate.io/signup', hasReferral: false },
+    coinbase: { url: 'https://international.coinbase.com', hasReferral: false },
+    backpack: { url: 'https://backpack.exchange', hasReferral: false },
   }
 
   // 如果是编辑现有交易所，初始化表单数据
@@ -287,7 +291,7 @@ export function ExchangeConfigModal({
       } else if (currentExchangeType === 'okx') {
         if (!apiKey.trim() || !secretKey.trim() || !passphrase.trim()) return
         await onSave(exchangeId, exchangeType, trimmedAccountName, apiKey.trim(), secretKey.trim(), passphrase.trim(), testnet)
-      } else if (currentExchangeType === 'bitget') {
+      } else if (currentExchangeType === 'bitget' || currentExchangeType === 'coinbase') {
         if (!apiKey.trim() || !secretKey.trim() || !passphrase.trim()) return
         await onSave(exchangeId, exchangeType, trimmedAccountName, apiKey.trim(), secretKey.trim(), passphrase.trim(), testnet)
       } else if (currentExchangeType === 'hyperliquid') {
@@ -545,11 +549,13 @@ export function ExchangeConfigModal({
 
             {selectedTemplate && (
               <>
-                {/* Binance/Bybit/OKX/Bitget 的输入字段 */}
+                {/* Binance/Bybit/OKX/Bitget/Coinbase/Backpack 的输入字段 */}
                 {(currentExchangeType === 'binance' ||
                   currentExchangeType === 'bybit' ||
                   currentExchangeType === 'okx' ||
-                  currentExchangeType === 'bitget') && (
+                  currentExchangeType === 'bitget' ||
+                  currentExchangeType === 'coinbase' ||
+                  currentExchangeType === 'backpack') && (
                     <>
                       {/* 币安用户配置提示 (D1 方案) */}
                       {currentExchangeType === 'binance' && (
@@ -694,7 +700,9 @@ export function ExchangeConfigModal({
                         />
                       </div>
 
-                      {(currentExchangeType === 'okx' || currentExchangeType === 'bitget') && (
+                      {(currentExchangeType === 'okx' ||
+                        currentExchangeType === 'bitget' ||
+                        currentExchangeType === 'coinbase') && (
                         <div>
                           <label
                             className="block text-sm font-semibold mb-2"
@@ -1262,6 +1270,10 @@ export function ExchangeConfigModal({
                   (!apiKey.trim() ||
                     !secretKey.trim() ||
                     !passphrase.trim())) ||
+                (currentExchangeType === 'coinbase' &&
+                  (!apiKey.trim() ||
+                    !secretKey.trim() ||
+                    !passphrase.trim())) ||
                 (currentExchangeType === 'hyperliquid' &&
                   (!apiKey.trim() || !hyperliquidWalletAddr.trim())) || // 验证私钥和钱包地址
                 (currentExchangeType === 'aster' &&
@@ -1272,6 +1284,8 @@ export function ExchangeConfigModal({
                   (!lighterWalletAddr.trim() || !lighterApiKeyPrivateKey.trim())) ||
                 (currentExchangeType === 'bybit' &&
                   (!apiKey.trim() || !secretKey.trim())) ||
+                (currentExchangeType === 'backpack' &&
+                  (!apiKey.trim() || !secretKey.trim())) ||
                 (selectedTemplate?.type === 'cex' &&
                   currentExchangeType !== 'hyperliquid' &&
                   currentExchangeType !== 'aster' &&
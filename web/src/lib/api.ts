@@ -3,9 +3,13 @@ import type {
   AccountInfo,
   Position,
   DecisionRecord,
+  DecisionDiffResponse,
+  RealizedPnLHistory,
   Statistics,
   TraderInfo,
   TraderConfigData,
+  TraderEffectiveConfig,
+  LiquidationPreview,
   AIModel,
   Exchange,
   CreateTraderRequest,
@@ -19,6 +23,10 @@ import type {
   BacktestEquityPoint,
   BacktestTradeEvent,
   BacktestMetrics,
+  StrategyBacktestSummary,
+  ExchangeTestResult,
+  ReadOnlyToken,
+  Portfolio,
   BacktestRunMetadata,
   BacktestKlinesResponse,
   Strategy,
@@ -30,6 +38,9 @@ import type {
   DebateVote,
   DebatePersonalityInfo,
   PositionHistoryResponse,
+  SymbolSearchResult,
+  RebalanceResult,
+  DecisionAction,
 } from '../types'
 import { CryptoService } from './crypto'
 import { httpClient } from './httpClient'
@@ -106,11 +117,34 @@ export const api = {
     if (!result.success) throw new Error('启动交易员失败')
   },
 
-  async stopTrader(traderId: string): Promise<void> {
-    const result = await httpClient.post(`${API_BASE}/traders/${traderId}/stop`)
+  // flatten 可覆盖交易员的 flatten_on_stop 设置（停止时平仓并撤单）
+  async stopTrader(traderId: string, flatten?: boolean): Promise<void> {
+    const result = await httpClient.post(
+      `${API_BASE}/traders/${traderId}/stop`,
+      flatten === undefined ? undefined : { flatten }
+    )
     if (!result.success) throw new Error('停止交易员失败')
   },
 
+  async disableTrader(traderId: string): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/traders/${traderId}/disable`)
+    if (!result.success) throw new Error('停用交易员失败')
+  },
+
+  async enableTrader(traderId: string): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/traders/${traderId}/enable`)
+    if (!result.success) throw new Error('启用交易员失败')
+  },
+
+  // 清空已停止交易员的决策、订单、成交、持仓和净值记录（保留配置）
+  async resetTraderHistory(traderId: string): Promise<Record<string, number>> {
+    const result = await httpClient.post<{ deleted: Record<string, number> }>(
+      `${API_BASE}/traders/${traderId}/reset-history?confirm=true`
+    )
+    if (!result.success) throw new Error('清空交易员历史失败')
+    return result.data!.deleted
+  },
+
   async toggleCompetition(traderId: string, showInCompetition: boolean): Promise<void> {
     const result = await httpClient.put(
       `${API_BASE}/traders/${traderId}/competition`,
@@ -128,6 +162,49 @@ export const api = {
     return result.data!
   },
 
+  async openPosition(
+    traderId: string,
+    params: {
+      symbol: string
+      side: 'long' | 'short'
+      size_usd: number
+      leverage: number
+      stop_loss?: number
+      take_profit?: number
+    }
+  ): Promise<{ message: string; action: DecisionAction }> {
+    const result = await httpClient.post<{ message: string; action: DecisionAction }>(
+      `${API_BASE}/traders/${traderId}/open`,
+      params
+    )
+    if (!result.success) throw new Error('开仓失败')
+    return result.data!
+  },
+
+  async rebalancePosition(
+    traderId: string,
+    params: { symbol: string; side: string; leverage?: number; margin_delta?: number }
+  ): Promise<RebalanceResult> {
+    const result = await httpClient.post<RebalanceResult>(
+      `${API_BASE}/traders/${traderId}/rebalance`,
+      params
+    )
+    if (!result.success) throw new Error('调整仓位杠杆/保证金失败')
+    return result.data!
+  },
+
+  async cancelTraderOrder(
+    traderId: string,
+    exchangeOrderId: string,
+    symbol: string
+  ): Promise<{ message: string; recorded: boolean }> {
+    const result = await httpClient.delete<{ message: string; recorded: boolean }>(
+      `${API_BASE}/traders/${traderId}/orders/${encodeURIComponent(exchangeOrderId)}?symbol=${encodeURIComponent(symbol)}`
+    )
+    if (!result.success) throw new Error('撤单失败')
+    return result.data!
+  },
+
   async updateTraderPrompt(
     traderId: string,
     customPrompt: string
@@ -147,6 +224,14 @@ export const api = {
     return result.data!
   },
 
+  async getTraderEffectiveConfig(traderId: string): Promise<TraderEffectiveConfig> {
+    const result = await httpClient.get<TraderEffectiveConfig>(
+      `${API_BASE}/traders/${traderId}/effective-config`
+    )
+    if (!result.success) throw new Error('获取交易员运行配置失败')
+    return result.data!
+  },
+
   async updateTrader(
     traderId: string,
     request: CreateTraderRequest
@@ -292,6 +377,45 @@ export const api = {
     if (!result.success) throw new Error('删除交易所账户失败')
   },
 
+  // 测试交易所API连通性（查询余额，失败时返回脱敏后的错误原因）
+  async testExchange(exchangeId: string): Promise<ExchangeTestResult> {
+    const result = await httpClient.post<ExchangeTestResult>(
+      `${API_BASE}/exchanges/${exchangeId}/test`
+    )
+    if (!result.success) throw new Error('测试交易所连接失败')
+    return result.data!
+  },
+
+  // 保存前测试交易所API凭证（不保存任何数据，启用传输加密时加密发送）
+  async testExchangeCredentials(request: CreateExchangeRequest): Promise<ExchangeTestResult> {
+    const config = await CryptoService.fetchCryptoConfig()
+
+    let payload: unknown = request
+    if (config.transport_encryption) {
+      const publicKey = await CryptoService.fetchPublicKey()
+      await CryptoService.initialize(publicKey)
+      payload = await CryptoService.encryptSensitiveData(
+        JSON.stringify(request),
+        localStorage.getItem('user_id') || '',
+        sessionStorage.getItem('session_id') || ''
+      )
+    }
+
+    const result = await httpClient.post<ExchangeTestResult>(`${API_BASE}/exchanges/test`, payload)
+    if (!result.success) throw new Error('测试交易所连接失败')
+    return result.data!
+  },
+
+  // 生成只读令牌（不能创建/启停交易员或下单）
+  async createReadOnlyToken(ttlHours?: number): Promise<ReadOnlyToken> {
+    const result = await httpClient.post<ReadOnlyToken>(
+      `${API_BASE}/tokens/read-only`,
+      ttlHours ? { ttl_hours: ttlHours } : undefined
+    )
+    if (!result.success) throw new Error('生成只读令牌失败')
+    return result.data!
+  },
+
   // 使用加密传输更新交易所配置（自动检测是否启用加密）
   async updateExchangeConfigsEncrypted(
     request: UpdateExchangeConfigRequest
@@ -372,6 +496,27 @@ export const api = {
     return result.data!
   },
 
+  // 按币种筛选决策日志（最新在前，分页）
+  async getDecisionsBySymbol(
+    traderId: string,
+    symbol: string,
+    limit: number = 50,
+    offset: number = 0
+  ): Promise<{ total: number; items: DecisionRecord[] }> {
+    const params = new URLSearchParams({
+      trader_id: traderId,
+      symbol,
+      limit: String(limit),
+      offset: String(offset),
+    })
+    const result = await httpClient.get<{
+      total: number
+      items: DecisionRecord[]
+    }>(`${API_BASE}/decisions?${params}`)
+    if (!result.success) throw new Error('获取决策日志失败')
+    return result.data!
+  },
+
   // 获取最新决策（支持trader_id和limit参数）
   async getLatestDecisions(
     traderId?: string,
@@ -390,6 +535,22 @@ export const api = {
     return result.data!
   },
 
+  // 获取决策审计轨迹：每个周期相对上一周期的开仓/平仓/持有变化及理由
+  async getDecisionDiff(
+    traderId: string,
+    limit: number = 20
+  ): Promise<DecisionDiffResponse> {
+    const params = new URLSearchParams({
+      trader_id: traderId,
+      limit: limit.toString(),
+    })
+    const result = await httpClient.get<DecisionDiffResponse>(
+      `${API_BASE}/decisions/diff?${params}`
+    )
+    if (!result.success) throw new Error('获取决策变化失败')
+    return result.data!
+  },
+
   // 获取统计信息（支持trader_id）
   async getStatistics(traderId?: string): Promise<Statistics> {
     const url = traderId
@@ -400,6 +561,16 @@ export const api = {
     return result.data!
   },
 
+  // 获取所有交易员的汇总组合（权益、盈亏、保证金、合并权益曲线）
+  async getPortfolio(hours?: number): Promise<Portfolio> {
+    const url = hours
+      ? `${API_BASE}/portfolio?hours=${hours}`
+      : `${API_BASE}/portfolio`
+    const result = await httpClient.get<Portfolio>(url)
+    if (!result.success) throw new Error('获取组合汇总失败')
+    return result.data!
+  },
+
   // 获取收益率历史数据（支持trader_id）
   async getEquityHistory(traderId?: string): Promise<any[]> {
     const url = traderId
@@ -410,6 +581,20 @@ export const api = {
     return result.data!
   },
 
+  async getRealizedPnLHistory(
+    traderId: string,
+    interval: 'day' | 'hour' = 'day',
+    days?: number
+  ): Promise<RealizedPnLHistory> {
+    const params = new URLSearchParams({ trader_id: traderId, interval })
+    if (days) params.set('days', String(days))
+    const result = await httpClient.get<RealizedPnLHistory>(
+      `${API_BASE}/realized-pnl-history?${params}`
+    )
+    if (!result.success) throw new Error('获取已实现盈亏历史失败')
+    return result.data!
+  },
+
   // 批量获取多个交易员的历史数据（无需认证）
   // hours: 可选参数，获取最近N小时的数据（0表示全部数据）
   // 常用值: 24=1天, 72=3天, 168=7天, 720=30天, 0=全部
@@ -644,6 +829,63 @@ export const api = {
     return res.blob()
   },
 
+  // 下载交易员绩效报告（HTML），period 如 '30d'
+  async downloadTraderReport(traderId: string, period = '30d'): Promise<Blob> {
+    const res = await fetch(
+      `${API_BASE}/traders/${traderId}/report?period=${encodeURIComponent(period)}`,
+      { headers: getAuthHeaders() }
+    )
+    if (!res.ok) {
+      const data = await res.json().catch(() => null)
+      throw new Error(data?.error || '生成报告失败，请稍后再试')
+    }
+    return res.blob()
+  },
+
+  // 交易员日志文件的最近若干行（需开启 TRADER_LOG_ENABLED）
+  async getTraderLogs(
+    traderId: string,
+    tail = 500
+  ): Promise<{ trader_id: string; lines: string[]; count: number }> {
+    const result = await httpClient.get<{ trader_id: string; lines: string[]; count: number }>(
+      `${API_BASE}/traders/${traderId}/logs?tail=${tail}`
+    )
+    if (!result.success) throw new Error('获取交易员日志失败')
+    return result.data!
+  },
+
+  // 跨数据源搜索交易标的（CoinAnk 加密货币、Alpaca 美股、TwelveData 外汇/贵金属、Hyperliquid）
+  async getLiquidationPreview(params: {
+    exchangeId: string
+    symbol: string
+    side: 'LONG' | 'SHORT'
+    sizeUsd: number
+    leverage: number
+  }): Promise<LiquidationPreview> {
+    const query = new URLSearchParams({
+      exchange_id: params.exchangeId,
+      symbol: params.symbol,
+      side: params.side,
+      size_usd: String(params.sizeUsd),
+      leverage: String(params.leverage),
+    })
+    const result = await httpClient.get<LiquidationPreview>(
+      `${API_BASE}/liquidation-preview?${query.toString()}`
+    )
+    if (!result.success) throw new Error('获取强平价格预估失败')
+    return result.data!
+  },
+
+  async searchSymbols(query: string, category?: string, limit = 20): Promise<SymbolSearchResult[]> {
+    const params = new URLSearchParams({ q: query, limit: String(limit) })
+    if (category) params.set('category', category)
+    const result = await httpClient.get<{ symbols: SymbolSearchResult[] }>(
+      `${API_BASE}/symbols/search?${params.toString()}`
+    )
+    if (!result.success) throw new Error('搜索交易标的失败')
+    return result.data?.symbols ?? []
+  },
+
   // Strategy APIs
   async getStrategies(): Promise<Strategy[]> {
     const result = await httpClient.get<{ strategies: Strategy[] }>(`${API_BASE}/strategies`)
@@ -710,6 +952,14 @@ export const api = {
     return result.data!
   },
 
+  async getStrategyBacktestSummary(strategyId: string): Promise<StrategyBacktestSummary | null> {
+    const result = await httpClient.get<StrategyBacktestSummary>(
+      `${API_BASE}/strategies/${strategyId}/backtest-summary`
+    )
+    if (!result.success) return null
+    return result.data ?? null
+  },
+
   // Debate Arena APIs
   async getDebates(): Promise<DebateSession[]> {
     const result = await httpClient.get<DebateSession[]>(`${API_BASE}/debates`)
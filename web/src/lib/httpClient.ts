@@ -19,6 +19,8 @@ export interface ApiResponse<T = any> {
   success: boolean
   data?: T
   message?: string
+  /** Stable error code from the API (e.g. TRADER_NOT_FOUND), use it for branching/i18n */
+  code?: string
 }
 
 /**
@@ -196,6 +198,7 @@ export class HttpClient {
         return {
           success: false,
           message: errorData?.error || errorData?.message || 'Operation failed',
+          code: errorData?.code,
         }
       }
 
@@ -138,6 +138,10 @@ export function TraderDashboardPage({
     const [positionsPageSize, setPositionsPageSize] = useState<number>(20)
     const [positionsCurrentPage, setPositionsCurrentPage] = useState<number>(1)
 
+    // Decimal places of equity/PnL amounts, set per trader (small accounts need more than 2)
+    const quoteDecimals = account?.display_decimals ?? 2
+    const zeroQuote = (0).toFixed(quoteDecimals)
+
     // Calculate paginated positions
     const totalPositions = positions?.length || 0
     const totalPositionPages = Math.ceil(totalPositions / positionsPageSize)
@@ -492,8 +496,8 @@ export function TraderDashboardPage({
                         <span>SYSTEM_STATUS::ONLINE</span>
                         <div className="flex gap-4">
                             <span>LAST_UPDATE::{lastUpdate}</span>
-                            <span>EQ::{account?.total_equity?.toFixed(2)}</span>
-                            <span>PNL::{account?.total_pnl?.toFixed(2)}</span>
+                            <span>EQ::{account?.total_equity?.toFixed(quoteDecimals)}</span>
+                            <span>PNL::{account?.total_pnl?.toFixed(quoteDecimals)}</span>
                         </div>
                     </div>
                 )}
@@ -502,7 +506,7 @@ export function TraderDashboardPage({
                 <div className="grid grid-cols-1 md:grid-cols-4 gap-4 mb-8">
                     <StatCard
                         title={t('totalEquity', language)}
-                        value={`${account?.total_equity?.toFixed(2) || '0.00'}`}
+                        value={`${account?.total_equity?.toFixed(quoteDecimals) || zeroQuote}`}
                         unit="USDT"
                         change={account?.total_pnl_pct || 0}
                         positive={(account?.total_pnl ?? 0) > 0}
@@ -510,14 +514,14 @@ export function TraderDashboardPage({
                     />
                     <StatCard
                         title={t('availableBalance', language)}
-                        value={`${account?.available_balance?.toFixed(2) || '0.00'}`}
+                        value={`${account?.available_balance?.toFixed(quoteDecimals) || zeroQuote}`}
                         unit="USDT"
                         subtitle={`${account?.available_balance && account?.total_equity ? ((account.available_balance / account.total_equity) * 100).toFixed(1) : '0.0'}% ${t('free', language)}`}
                         icon="💳"
                     />
                     <StatCard
                         title={t('totalPnL', language)}
-                        value={`${account?.total_pnl !== undefined && account.total_pnl >= 0 ? '+' : ''}${account?.total_pnl?.toFixed(2) || '0.00'}`}
+                        value={`${account?.total_pnl !== undefined && account.total_pnl >= 0 ? '+' : ''}${account?.total_pnl?.toFixed(quoteDecimals) || zeroQuote}`}
                         unit="USDT"
                         change={account?.total_pnl_pct || 0}
                         positive={(account?.total_pnl ?? 0) >= 0}
@@ -645,7 +649,7 @@ export function TraderDashboardPage({
                                                                 style={{ textShadow: pos.unrealized_pnl >= 0 ? '0 0 10px rgba(14,203,129,0.3)' : '0 0 10px rgba(246,70,93,0.3)' }}
                                                             >
                                                                 {pos.unrealized_pnl >= 0 ? '+' : ''}
-                                                                {pos.unrealized_pnl.toFixed(2)}
+                                                                {pos.unrealized_pnl.toFixed(quoteDecimals)}
                                                             </span>
                                                         </td>
                                                         <td className="px-1 py-3 font-mono whitespace-nowrap text-right text-nofx-text-muted">{pos.liquidation_price.toFixed(4)}</td>
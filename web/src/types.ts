@@ -11,6 +11,12 @@ export interface SystemStatus {
   stop_until: string
   last_reset_time: string
   ai_provider: string
+  display_decimals?: number // 金额显示的小数位数
+  stop_reason?: string // 暂停交易的原因（如连续亏损冷静期）
+  loss_streak?: number // 当前连续亏损笔数（启用连续亏损冷静时）
+  max_consecutive_losses?: number
+  loss_cooldown_until?: string // 连续亏损冷静期结束时间
+  mirror_exchanges?: { exchange_id: string; exchange: string }[] // 同步复制下单的其他交易所
 }
 
 export interface AccountInfo {
@@ -25,6 +31,9 @@ export interface AccountInfo {
   position_count: number
   margin_used: number
   margin_used_pct: number
+  net_contributions?: number // 初始余额之后的净入金（入金 - 出金）
+  funding_total?: number // 累计资金费（收取 - 支付），已计入总盈亏
+  display_decimals?: number // 本响应中金额的小数位数
 }
 
 export interface Position {
@@ -48,14 +57,93 @@ export interface DecisionAction {
   price: number
   stop_loss?: number      // Stop loss price
   take_profit?: number    // Take profit price
+  exit_order_type?: 'market' | 'limit' | 'mixed' // Order type of the placed SL/TP
+  post_only?: boolean     // 以挂单（post-only maker）方式开仓
   confidence?: number     // AI confidence (0-100)
+  atr?: number            // ATR used for volatility scaling
+  size_multiplier?: number // Volatility scaling multiplier applied to the size
   reasoning?: string      // Brief reasoning
+  votes?: ConsensusVote[] // Multi-model consensus votes on this symbol
   order_id: number
   timestamp: string
   success: boolean
   error?: string
 }
 
+// One model's vote in a multi-model consensus cycle
+export interface ConsensusVote {
+  model: string
+  action: string // 'wait' if the model proposed nothing, 'error' if its call failed
+  weight: number
+}
+
+// Equity alert rule: notify when equity crosses the threshold
+export interface EquityAlert {
+  id: number
+  trader_id: string
+  direction: 'below' | 'above'
+  threshold: number
+  hysteresis_pct: number // Re-arm distance past the threshold (%, 0 = default 1%)
+  note: string
+  enabled: boolean
+  triggered: boolean
+  last_triggered_at?: string
+  created_at: string
+  updated_at: string
+}
+
+// One symbol's action in a cycle compared with the previous cycle
+export interface DecisionChange {
+  symbol: string
+  action: string
+  reasoning?: string
+  confidence?: number
+  quantity?: number
+  price?: number
+  error?: string
+  prev_action?: string    // Empty if the symbol wasn't addressed in the previous cycle
+  prev_reasoning?: string
+  prev_confidence?: number
+  reasoning_changed: boolean
+}
+
+// What the AI changed in one cycle versus the previous one
+export interface DecisionCycleDiff {
+  record_id: number
+  cycle_number: number
+  timestamp: string
+  success: boolean
+  error_message?: string
+  prev_record_id?: number
+  prev_cycle_number?: number
+  opened: DecisionChange[]
+  closed: DecisionChange[]
+  reduced: DecisionChange[]
+  held: DecisionChange[]
+  failed: DecisionChange[]
+  dropped: DecisionChange[]  // Opened or held last cycle, not addressed in this one
+}
+
+export interface DecisionDiffResponse {
+  trader_id: string
+  cycles: DecisionCycleDiff[]
+}
+
+// 已实现盈亏曲线（按 UTC 日/小时汇总已平仓位）
+export interface RealizedPnLPoint {
+  time: number // 时间桶起点（毫秒）
+  realized_pnl: number
+  fee: number
+  trades: number
+  cumulative_pnl: number
+}
+
+export interface RealizedPnLHistory {
+  trader_id: string
+  interval: 'day' | 'hour'
+  points: RealizedPnLPoint[]
+}
+
 export interface AccountSnapshot {
   total_balance: number
   available_balance: number
@@ -78,6 +166,11 @@ export interface DecisionRecord {
   execution_log: string[]
   success: boolean
   error_message?: string
+  notes?: string
+  tags?: string[]
+  archived?: boolean // prompts/responses compressed by retention, shown when the single record is opened
+  unavailable_sources?: string[] // enabled data sources (e.g. OI ranking) that failed this cycle
+  trace_id?: string // correlation ID of the cycle, tags its log lines, orders and fills
 }
 
 export interface Statistics {
@@ -86,6 +179,7 @@ export interface Statistics {
   failed_cycles: number
   total_open_positions: number
   total_close_positions: number
+  total_funding: number // 累计资金费（收取 - 支付）
 }
 
 // AI Trading相关类型
@@ -95,6 +189,15 @@ export interface TraderInfo {
   ai_model: string
   exchange_id?: string
   is_running?: boolean
+  disabled?: boolean // 已停用（kill-switch），重启后也不会自动启动
+  flatten_on_stop?: boolean // 停止时平掉所有仓位并撤销挂单
+  prompt_language?: string // AI 推理语言（为空则使用策略配置）
+  consensus_models?: string // 共识投票的额外模型 ID，"id:权重,id"
+  consensus_threshold?: number // 执行所需的投票权重占比（0 = 过半数）
+  ai_request_timeout_sec?: number // 等待 AI 决策的最长秒数（0 = 默认 120 秒）
+  display_decimals?: number // 权益/盈亏金额的小数位数（0 = 按账户规模自动选择）
+  mirror_exchange_ids?: string // 同步复制下单的其他交易所账户 ID，逗号分隔
+  log_level?: '' | 'debug' | 'info' | 'warn' | 'error' // 该交易员的日志级别（空 = 全局级别）
   show_in_competition?: boolean
   strategy_id?: string
   strategy_name?: string
@@ -136,10 +239,31 @@ export interface Exchange {
   lighterPrivateKey?: string
   lighterApiKeyPrivateKey?: string
   lighterApiKeyIndex?: number
+  extraApiKeyCount?: number      // Additional API keys rotated with the primary key (Binance, Bybit, OKX, Bitget, Coinbase, Backpack)
+  slippage?: SlippageSettings    // Hyperliquid/Lighter IOC slippage, omitted = exchange default
+}
+
+// DEX 市价单 IOC 限价偏移（基点）
+export interface SlippageSettings {
+  bps?: number                          // 所有币种（0 = 交易所默认：Hyperliquid 100，Lighter 500）
+  symbol_bps?: Record<string, number>   // 按币种覆盖
+}
+
+export interface ExtraAPIKey {
+  api_key: string
+  secret_key: string
+  passphrase?: string            // Falls back to the primary key's passphrase
+}
+
+export interface SymbolSearchResult {
+  symbol: string
+  name: string
+  category: 'crypto' | 'stock' | 'forex' | 'commodity' | 'index'
+  source: 'coinank' | 'alpaca' | 'twelvedata' | 'hyperliquid'
 }
 
 export interface CreateExchangeRequest {
-  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "gateio"
+  exchange_type: string          // "binance", "bybit", "okx", "hyperliquid", "aster", "lighter", "gateio", "coinbase", "backpack"
   account_name: string           // User-defined account name
   enabled: boolean
   api_key?: string
@@ -165,6 +289,15 @@ export interface CreateTraderRequest {
   scan_interval_minutes?: number
   is_cross_margin?: boolean
   show_in_competition?: boolean // 是否在竞技场显示
+  quote_asset?: 'USDT' | 'USDC' // 计价币种（默认 USDT）
+  flatten_on_stop?: boolean // 停止时平掉所有仓位并撤销挂单
+  prompt_language?: string // AI 推理语言（为空则使用策略配置）
+  consensus_models?: string // 共识投票的额外模型 ID，"id:权重,id"
+  consensus_threshold?: number // 执行所需的投票权重占比（0 = 过半数）
+  ai_request_timeout_sec?: number // 等待 AI 决策的最长秒数（0 = 默认 120 秒）
+  display_decimals?: number // 权益/盈亏金额的小数位数（0 = 按账户规模自动选择）
+  mirror_exchange_ids?: string // 同步复制下单的其他交易所账户 ID，逗号分隔
+  log_level?: '' | 'debug' | 'info' | 'warn' | 'error' // 该交易员的日志级别（空 = 全局级别）
   // 以下字段为向后兼容保留，新版使用策略配置
   btc_eth_leverage?: number
   altcoin_leverage?: number
@@ -206,6 +339,10 @@ export interface UpdateExchangeConfigRequest {
       lighter_private_key?: string
       lighter_api_key_private_key?: string
       lighter_api_key_index?: number
+      // 同一账户的额外 API Key（轮换使用；不传 = 不变，空数组 = 全部移除）
+      extra_api_keys?: ExtraAPIKey[]
+      // Hyperliquid/Lighter 市价单滑点（不传 = 不变，空对象 = 交易所默认）
+      slippage?: SlippageSettings
     }
   }
 }
@@ -242,6 +379,16 @@ export interface TraderConfigData {
   scan_interval_minutes: number
   initial_balance: number
   is_running: boolean
+  disabled?: boolean // 已停用（kill-switch）
+  flatten_on_stop?: boolean // 停止时平掉所有仓位并撤销挂单
+  prompt_language?: string // AI 推理语言（为空则使用策略配置）
+  consensus_models?: string // 共识投票的额外模型 ID，"id:权重,id"
+  consensus_threshold?: number // 执行所需的投票权重占比（0 = 过半数）
+  ai_request_timeout_sec?: number // 等待 AI 决策的最长秒数（0 = 默认 120 秒）
+  display_decimals?: number // 权益/盈亏金额的小数位数（0 = 按账户规模自动选择）
+  mirror_exchange_ids?: string // 同步复制下单的其他交易所账户 ID，逗号分隔
+  log_level?: '' | 'debug' | 'info' | 'warn' | 'error' // 该交易员的日志级别（空 = 全局级别）
+  quote_asset?: 'USDT' | 'USDC' // 计价币种
   // 以下为旧版字段（向后兼容）
   btc_eth_leverage?: number
   altcoin_leverage?: number
@@ -339,6 +486,63 @@ export interface BacktestTradeEvent {
   note?: string;
 }
 
+export interface BacktestWalkForwardWindow {
+  index: number;
+  train_start_ts: number;
+  train_end_ts: number;
+  test_start_ts: number;
+  test_end_ts: number;
+  run_id?: string;
+  in_sample_run_id?: string;
+  out_of_sample?: BacktestMetrics;
+  in_sample?: BacktestMetrics;
+  error?: string;
+}
+
+export interface BacktestWalkForwardReport {
+  windows: BacktestWalkForwardWindow[];
+  completed_windows: number;
+  positive_windows: number;
+  avg_return_pct: number;
+  efficiency?: number;
+}
+
+export type BacktestSweepParamName =
+  | 'leverage'
+  | 'btc_eth_leverage'
+  | 'altcoin_leverage'
+  | 'min_confidence'
+  | 'max_positions'
+  | 'min_risk_reward_ratio'
+  | 'max_margin_usage';
+
+export interface BacktestSweepParam {
+  name: BacktestSweepParamName;
+  values: number[];
+}
+
+export interface BacktestSweepResult {
+  index: number;
+  params: Partial<Record<BacktestSweepParamName, number>>;
+  run_id: string;
+  rank?: number; // 1 = highest total return
+  total_return_pct: number;
+  max_drawdown_pct: number;
+  sharpe_ratio: number;
+  profit_factor: number;
+  win_rate: number;
+  trades: number;
+  liquidated: boolean;
+  error?: string;
+}
+
+export interface BacktestSweepReport {
+  params: BacktestSweepParam[];
+  results: BacktestSweepResult[];
+  completed: number;
+  best_run_id?: string;
+}
+
 export interface BacktestMetrics {
   total_return_pct: number;
   max_drawdown_pct: number;
@@ -351,6 +555,8 @@ export interface BacktestMetrics {
   best_symbol: string;
   worst_symbol: string;
   liquidated: boolean;
+  walk_forward?: BacktestWalkForwardReport;
+  sweep?: BacktestSweepReport; // Sweep runs: the other fields are the best combination's metrics
   symbol_stats?: Record<
     string,
     {
@@ -364,6 +570,116 @@ export interface BacktestMetrics {
   >;
 }
 
+export interface ExchangeTestResult {
+  success: boolean;
+  exchange_id: string;
+  exchange_type: string;
+  testnet?: boolean;
+  latency_ms?: number;
+  total_equity?: number;
+  available_balance?: number;
+  error_code?: string; // invalid_api_key / ip_not_whitelisted / invalid_signature / ...
+  error?: string;
+}
+
+export interface PortfolioTrader {
+  trader_id: string;
+  trader_name: string;
+  is_running: boolean;
+  initial_balance: number;
+  total_equity: number;
+  total_pnl: number;
+  total_pnl_pct: number;
+  unrealized_pnl: number;
+  realized_pnl: number;
+  margin_used: number;
+  open_positions: number;
+  equity_share_pct: number;
+  last_update: string;
+}
+
+export interface PortfolioPoint {
+  timestamp: string;
+  total_equity: number;
+  unrealized_pnl: number;
+  traders: number;
+}
+
+export interface Portfolio {
+  total_equity: number;
+  total_initial_balance: number;
+  total_pnl: number;
+  total_pnl_pct: number;
+  unrealized_pnl: number;
+  realized_pnl: number;
+  margin_used: number;
+  margin_used_pct: number;
+  open_positions: number;
+  traders: PortfolioTrader[];
+  equity_curve: PortfolioPoint[];
+  curve_bucket: string;
+  generated_at: string;
+}
+
+export interface PortfolioPosition {
+  trader_id: string;
+  trader_name: string;
+  symbol: string;
+  side: 'long' | 'short';
+  quantity: number;
+  entry_price: number;
+  mark_price: number; // entry price when source is 'db'
+  leverage: number;
+  unrealized_pnl: number;
+  notional: number;
+  source: 'live' | 'db';
+}
+
+export interface SymbolExposure {
+  symbol: string;
+  long_notional: number;
+  short_notional: number;
+  net_notional: number;
+  net_side: 'long' | 'short' | 'flat';
+  gross_pct: number;
+  positions: number;
+  trader_ids: string[];
+}
+
+export interface PortfolioPositions {
+  positions: PortfolioPosition[];
+  exposure: SymbolExposure[];
+  total_long: number;
+  total_short: number;
+  net_exposure: number;
+  gross_exposure: number;
+  errors?: Record<string, string>;
+}
+
+export interface ReadOnlyToken {
+  token: string;
+  scope: 'read_only';
+  expires_at: string;
+}
+
+export interface StrategyBacktestSummary {
+  strategy_id: string;
+  run_id: string;
+  state: string;
+  params_hash: string;
+  config_hash: string;
+  current_config_hash: string;
+  stale: boolean; // Strategy config changed since this backtest ran
+  symbols: string[];
+  start_ts: number;
+  end_ts: number;
+  initial_balance: number;
+  equity_last: number;
+  max_drawdown_pct: number;
+  metrics: BacktestMetrics | null;
+  completed_at: string;
+}
+
 export interface BacktestStartConfig {
   run_id?: string;
   ai_model_id?: string;
@@ -387,7 +703,17 @@ export interface BacktestStartConfig {
   checkpoint_interval_bars?: number;
   checkpoint_interval_seconds?: number;
   replay_decision_dir?: string;
-  shared_ai_cache_path?: string;
+  ai_cache_path?: string;
+  mode?: '' | 'walkforward' | 'sweep';
+  walk_forward?: {
+    windows?: number;
+    train_pct?: number;
+    in_sample?: boolean;
+  };
+  sweep?: {
+    params: BacktestSweepParam[];
+    concurrency?: number; // Runs in parallel (default 2)
+  };
   ai?: {
     provider?: string;
     model?: string;
@@ -399,6 +725,12 @@ export interface BacktestStartConfig {
     btc_eth_leverage?: number;
     altcoin_leverage?: number;
   };
+  risk?: {
+    max_positions?: number;
+    min_confidence?: number;
+    min_risk_reward_ratio?: number;
+    max_margin_usage?: number;
+  };
 }
 
 // Kline data for backtest chart
@@ -461,30 +793,94 @@ export interface PromptSectionsConfig {
   decision_process?: string;
 }
 
+export interface LiquidationPreview {
+  exchange_id: string
+  exchange_type: string
+  symbol: string
+  side: 'LONG' | 'SHORT'
+  size_usd: number
+  leverage: number
+  mark_price: number
+  quantity: number
+  initial_margin: number
+  maint_margin_ratio: number
+  maint_amount: number
+  tiers_from_exchange: boolean
+  liquidation_price: number // 0 = cannot be liquidated (e.g. 1x long)
+  distance_pct?: number
+  max_leverage?: number
+  warning?: string
+}
+
+export interface TraderEffectiveConfig {
+  trader_id: string
+  strategy_id: string
+  strategy_name?: string
+  is_running: boolean
+  effective_config: StrategyConfig
+  matches_stored: boolean
+  differences?: string[]
+  stored_error?: string
+}
+
 export interface StrategyConfig {
   // Language setting: "zh" for Chinese, "en" for English
   // Determines the language used for data formatting and prompt generation
   language?: 'zh' | 'en';
+  // Language the AI writes its reasoning in (e.g. 'en', 'zh', 'ja'); empty keeps the model default
+  prompt_language?: string;
   coin_source: CoinSourceConfig;
   indicators: IndicatorConfig;
   custom_prompt?: string;
   risk_control: RiskControlConfig;
   prompt_sections?: PromptSectionsConfig;
+  response_repair?: ResponseRepairConfig;
+  context_budget?: ContextBudgetConfig;
+}
+
+export interface ResponseRepairConfig {
+  // Skip the one-time "return only valid JSON" re-prompt when the AI response can't be parsed
+  disable_reprompt?: boolean;
+}
+
+// Prompt size cap; over budget, recent trades, rankings, stats, then candidates are trimmed
+export interface ContextBudgetConfig {
+  max_tokens?: number; // 0 = no limit
+  max_chars?: number;  // 0 = no limit
+}
+
+// 可插拔币种来源类型
+export type CoinSourceType = 'symbols' | 'volume_top' | 'gainers' | 'losers' | 'coinank_rank';
+
+export interface CoinSourceSpec {
+  type: CoinSourceType;
+  symbols?: string[];   // symbols: 显式币种列表
+  limit?: number;       // 每个来源最多取多少个币种（默认 10）
+  window?: '5m' | '15m' | '30m' | '1h' | '4h' | '6h' | '12h' | '24h'; // gainers/losers 涨跌幅窗口，非 24h 需要 coinank_api_key
+  ranking?: 'oi' | 'price' | 'volume' | 'liquidation' | 'long_short'; // coinank_rank: CoinAnk 排行榜
+  sort_by?: string;     // coinank_rank: CoinAnk sortBy 字段，如 openInterestCh4
+  sort_type?: 'asc' | 'desc';
 }
 
 export interface CoinSourceConfig {
-  source_type: 'static' | 'ai500' | 'oi_top' | 'mixed';
+  source_type: 'static' | 'ai500' | 'oi_top' | 'mixed' | 'custom';
   static_coins?: string[];
   excluded_coins?: string[];   // 排除的币种列表
   use_ai500: boolean;
   ai500_limit?: number;
   use_oi_top: boolean;
   oi_top_limit?: number;
+  max_candidates?: number;  // Max candidates passed to the AI after merging sources (0 = no limit)
+  candidate_ranking?: 'sources' | 'volume' | 'oi_change' | 'volatility'; // Ranking used when truncating
+  sources?: CoinSourceSpec[];  // 可插拔来源：custom 时为唯一来源，mixed 时与其他来源合并
+  coinank_api_key?: string;    // CoinAnk API key（coinank_rank 与非 24h 涨跌幅窗口需要）
   // Note: API URLs are now built automatically using nofxos_api_key from IndicatorConfig
 }
 
 export interface IndicatorConfig {
   klines: KlineConfig;
+  // Kline interval used for coin analysis (e.g. "5m", "15m", "1h"), defaults to klines.primary_timeframe
+  analysis_interval?: string;
   // Raw OHLCV kline data - required for AI analysis
   enable_raw_klines: boolean;
   // Technical indicators (optional)
@@ -525,6 +921,10 @@ export interface IndicatorConfig {
   enable_price_ranking?: boolean;
   price_ranking_duration?: string;  // "1h", "4h", "24h" or "1h,4h,24h"
   price_ranking_limit?: number;
+
+  // 订单簿深度（持仓及头部候选币的买卖盘不平衡）
+  enable_order_book?: boolean;
+  order_book_depth?: number;  // 每侧档位数，默认 10
 }
 
 export interface KlineConfig {
@@ -554,6 +954,7 @@ export interface RiskControlConfig {
   // Trading Leverage - exchange leverage for opening positions (AI guided)
   btc_eth_max_leverage: number;    // BTC/ETH max exchange leverage
   altcoin_max_leverage: number;    // Altcoin max exchange leverage
+  max_leverage?: number;           // Hard leverage ceiling for all symbols (CODE ENFORCED, 0 = no ceiling)
 
   // Position Value Ratio - single position notional value / account equity (CODE ENFORCED)
   // Max position value = equity × this ratio
@@ -562,9 +963,57 @@ export interface RiskControlConfig {
 
   // Risk Parameters
   max_margin_usage: number;        // Max margin utilization, e.g. 0.9 = 90% (CODE ENFORCED)
+  max_total_margin_used_pct?: number; // Block new opens above this margin usage %, e.g. 80 (CODE ENFORCED, 0 = use max_margin_usage)
+  min_free_margin_pct?: number;    // Keep this % of available balance free when sizing opens (CODE ENFORCED, 0 = 98% of max affordable)
+  max_net_directional_exposure_pct?: number; // Block opens pushing net long/short notional beyond this % of equity (CODE ENFORCED, 0 = disabled)
   min_position_size: number;       // Min position size in USDT (CODE ENFORCED)
   min_risk_reward_ratio: number;   // Min take_profit / stop_loss ratio (AI guided)
   min_confidence: number;          // Min AI confidence to open position (AI guided)
+  atr_stop_loss_multiplier?: number;   // SL = entry ∓ k × ATR when AI sets stop_loss "auto" (0 = disabled)
+  atr_take_profit_multiplier?: number; // TP = entry ± k × ATR when AI sets take_profit "auto" (0 = disabled)
+  atr_timeframe?: string;              // ATR timeframe (default: primary timeframe)
+  sizing_model?: 'fixed_usd' | 'fixed_fraction' | 'volatility_target' | 'kelly'; // Position sizing model (CODE ENFORCED)
+  sizing_equity_fraction?: number;     // fixed_fraction: size = equity × fraction
+  sizing_volatility_target?: number;   // volatility_target: equity fraction per 1×ATR move
+  sizing_kelly_scale?: number;         // kelly: fraction of full Kelly
+  sizing_kelly_max_fraction?: number;  // kelly: max equity fraction per position
+  volatility_scaling?: boolean;        // Scale size × reference ATR% / symbol ATR% (CODE ENFORCED)
+  volatility_reference_atr_pct?: number; // ATR% at which size is unchanged (default 2)
+  volatility_timeframe?: string;       // Kline timeframe of the scaling ATR (default 1h)
+  volatility_min_multiplier?: number;  // Lower bound of the size multiplier (default 0.25)
+  volatility_max_multiplier?: number;  // Upper bound of the size multiplier (default 2)
+  correlation_groups?: CorrelationGroup[]; // Cap same-direction positions per correlated group (CODE ENFORCED)
+  trading_windows?: TradingWindow[];   // UTC windows in which new positions may be opened (CODE ENFORCED, empty = always)
+  auto_flip?: boolean;                 // Opening against an opposite position closes it first (CODE ENFORCED)
+  drawdown_alert_pct?: number;         // Alert when equity falls this % below its high-water mark (0 = disabled)
+  breakeven_trigger_pct?: number;      // Move stop loss to entry + fees once P&L % reaches this (CODE ENFORCED, 0 = disabled)
+  max_hold_minutes?: number;           // Close positions held longer than this many minutes (CODE ENFORCED, 0 = disabled)
+  exit_order_type?: 'market' | 'limit'; // SL/TP order type; limit falls back to market where unsupported
+  exit_limit_offset_pct?: number;      // Limit price offset beyond the trigger in % (default 0.2)
+  attach_sltp_to_entry?: boolean;      // Attach SL/TP to the entry order where the exchange supports it (Bybit, OKX)
+  iceberg_exit_threshold_usd?: number; // Close positions above this notional in slices (CODE ENFORCED, 0 = disabled)
+  iceberg_slice_fraction?: number;     // Fraction of the position closed per slice (default 0.25)
+  iceberg_slice_delay_ms?: number;     // Delay between slices in ms (default 1000)
+  sliced_entry_threshold_usd?: number; // 名义价值超过此值的市价开仓拆分为多笔子单（TWAP，0 = 关闭）
+  sliced_entry_count?: number;         // 子单数量（默认 4，最多 20）
+  sliced_entry_interval_ms?: number;   // 子单间隔（毫秒，默认 2000，最大 10000）
+  post_only_entries?: boolean;         // 允许 AI 以 post-only 挂单开仓（赚取 maker 费率）
+  post_only_timeout_sec?: number;      // 挂单开仓未成交的撤单时间（秒，默认 60）
+  max_open_orders_per_symbol?: number; // 单币种最大挂单数，超出时先清理过期止损/止盈单（默认 10）
+  max_consecutive_losses?: number;     // 连续亏损笔数达到此值后暂停交易冷静（0 = 关闭）
+  loss_cooldown_minutes?: number;      // 连续亏损后的冷静时间（分钟，默认 60）
+}
+
+export interface CorrelationGroup {
+  name: string;               // e.g. "majors"
+  symbols: string[];          // e.g. ["BTCUSDT", "ETHUSDT"]
+  max_same_direction: number; // Max concurrent same-direction positions in the group (0 = no limit)
+}
+
+export interface TradingWindow {
+  start: string;              // "HH:MM" UTC, inclusive
+  end: string;                // "HH:MM" UTC, exclusive; end <= start wraps past midnight
+  days?: string[];            // Weekdays the window starts on ("mon".."sun"), empty = every day
 }
 
 // Debate Arena Types
@@ -703,12 +1152,28 @@ export interface HistoricalPosition {
   realized_pnl: number;
   fee: number;
   leverage: number;
+  liquidation_price?: number; // 最近一次记录的强平价（0 = 未知）
   status: string;
   close_reason: string;
   created_at: string;
   updated_at: string;
 }
 
+// POST /traders/:id/rebalance 的结果
+export interface RebalanceResult {
+  symbol: string;
+  side: string;
+  quantity: number;
+  entry_price: number;
+  mark_price: number;
+  previous_leverage: number;
+  leverage: number;
+  margin_delta: number;
+  previous_liquidation_price: number;
+  liquidation_price: number;
+  liquidation_estimated?: boolean; // 交易所未返回强平价，本地估算
+}
+
 // Matches Go TraderStats struct exactly
 export interface TraderStats {
   total_trades: number;
@@ -719,6 +1184,8 @@ export interface TraderStats {
   sharpe_ratio: number;
   total_pnl: number;
   total_fee: number;
+  total_funding: number; // 净资金费，不含在 total_pnl 中
+  net_pnl: number; // 含资金费的已实现盈亏
   avg_win: number;
   avg_loss: number;
   max_drawdown_pct: number;